@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg/namefilter"
 	"github.com/joho/godotenv"
 )
 
@@ -31,10 +33,132 @@ type Config struct {
 	Push            PushConfig
 	AppLinks        AppLinksConfig
 	EncryptionKey   string // AES-256 key (64 hex chars = 32 bytes) for LiveKit credential encryption
-	HetznerAPIToken string // Hetzner Cloud API token (read-only) — optional
+	// EncryptionKeyPrev is the retired key during a rotation. Set the old
+	// ENCRYPTION_KEY here when introducing a new one: stored credentials stay
+	// decryptable and get rewritten under the new key at startup. Unset it once
+	// the rotation boot has run.
+	EncryptionKeyPrev string
+	HetznerAPIToken   string // Hetzner Cloud API token (read-only) — optional
 	// PasswordBreachCheck queries Have I Been Pwned when a password is set. Needs no key or
 	// account; turn it off only for a self-host with no route out. Failures allow the password.
 	PasswordBreachCheck bool
+	PasswordPolicy      PasswordPolicyConfig
+	NameFilter          NameFilterConfig
+	Reactions           ReactionsConfig
+	MessageFormat       MessageFormatConfig
+	// ServerCreatePerHour caps how many servers one user may create per hour.
+	// The owned-server count limit only covers mqvi-hosted; this also slows
+	// self-hosted spam. Platform admins are exempt.
+	ServerCreatePerHour int
+	// MessageRate caps message sends per user (channel and DM messages share
+	// one budget); ReactionRate caps reaction toggles. Members holding
+	// ManageMessages on the server are exempt from both.
+	MessageRate  MsgRateLimitConfig
+	ReactionRate MsgRateLimitConfig
+	// VoiceChannelReconcileInterval is how often in-memory voice states are checked
+	// against channel existence (MQVI_VOICE_CHANNEL_RECONCILE). Defense-in-depth for
+	// voice-state drift: clears states left pointing at a channel that was deleted
+	// without its participants being cleared first.
+	VoiceChannelReconcileInterval time.Duration
+	// StatsCacheTTL is how long the public /api/stats payload is served from memory
+	// (MQVI_STATS_CACHE_TTL). The endpoint is unauthenticated, so the TTL is what
+	// stands between landing-page bots and repeated COUNT(*) scans.
+	StatsCacheTTL time.Duration
+	// MaxVoiceParticipants is an instance-wide cap on participants per voice
+	// channel (MQVI_MAX_VOICE_PARTICIPANTS, 0 = no cap). A channel's own
+	// UserLimit still applies; this bounds LiveKit room size even for channels
+	// whose limit is 0 (unlimited) — a safeguard for shared/hosted instances.
+	MaxVoiceParticipants int
+	// MaxPinsPerChannel caps pinned messages per channel
+	// (MQVI_MAX_PINS_PER_CHANNEL, default 50). Pins beyond the cap are
+	// rejected; lowering it never unpins what is already there.
+	MaxPinsPerChannel int
+	// DefaultMemberPermissions is the permission bitmask granted to the
+	// auto-created "everyone" role on new servers (MQVI_DEFAULT_MEMBER_PERMS,
+	// decimal bitmask). Lets an instance ship more locked-down defaults — e.g.
+	// read-only members until verified. Unset falls back to
+	// models.PermDefaultMember; 0 is a valid value meaning no permissions.
+	DefaultMemberPermissions models.Permission
+	VoiceBitrate             VoiceBitrateConfig
+	AuditRetention           AuditRetentionConfig
+}
+
+// AuditRetentionConfig bounds the moderation audit log, which otherwise grows
+// for the life of the instance. The two bounds compose: an entry is pruned
+// when it fails either one. Setting a bound to 0 disables it.
+type AuditRetentionConfig struct {
+	// Days prunes entries older than this many days
+	// (MQVI_AUDIT_RETENTION_DAYS, default 90; 0 = keep forever).
+	Days int
+	// MaxEntriesPerServer keeps only the newest N entries per server
+	// (MQVI_AUDIT_MAX_ENTRIES_PER_SERVER, default 0 = uncapped).
+	MaxEntriesPerServer int
+}
+
+// VoiceBitrateConfig bounds what a voice channel's bitrate may be set to, split
+// by how the server is hosted. Mqvi-hosted servers share platform LiveKit
+// instances, so their ceiling protects neighbours; a self-hosted ceiling only
+// protects the owner's own instance.
+type VoiceBitrateConfig struct {
+	// Min is the floor for any voice channel (MQVI_VOICE_BITRATE_MIN).
+	Min int
+	// HostedMax caps mqvi-hosted channels (MQVI_VOICE_BITRATE_MAX_HOSTED).
+	HostedMax int
+	// SelfHostedMax caps self-hosted channels
+	// (MQVI_VOICE_BITRATE_MAX_SELF_HOSTED, unset = uncapped).
+	SelfHostedMax int
+}
+
+// ReactionsConfig caps reaction abuse on a single message. Both limits apply to
+// server-channel messages and DMs alike.
+type ReactionsConfig struct {
+	// MaxPerUserPerMessage caps how many distinct emoji one user can add to one
+	// message (MQVI_MAX_REACTIONS_PER_USER_PER_MESSAGE). Removing is always allowed.
+	MaxPerUserPerMessage int
+	// MaxDistinctPerMessage caps how many distinct emoji a message can accumulate
+	// across all users (MQVI_MAX_REACTION_EMOJIS_PER_MESSAGE). Reacting with an
+	// emoji that is already on the message never counts against it.
+	MaxDistinctPerMessage int
+}
+
+// MessageFormatConfig caps message shape beyond the character limit, for channel
+// and DM messages alike. The character cap alone still admits a wall of newlines
+// that strains rendering.
+type MessageFormatConfig struct {
+	// MaxLines caps lines per message (MQVI_MAX_MESSAGE_LINES). Exceeding it
+	// rejects the message.
+	MaxLines int
+	// MaxBlankLines caps consecutive blank lines (MQVI_MAX_MESSAGE_BLANK_LINES).
+	// Longer runs are collapsed, not rejected.
+	MaxBlankLines int
+}
+
+// PasswordPolicyConfig tunes the password rules in pkg/password. The defaults are the
+// policy; these knobs exist for instances whose compliance regime says otherwise.
+type PasswordPolicyConfig struct {
+	// MinLength is the rune-count floor (MQVI_PASSWORD_MIN_LENGTH, default 12,
+	// refuses anything below 8).
+	MinLength int
+	// RequireUppercase, RequireDigit, RequireSymbol turn on composition rules
+	// (MQVI_PASSWORD_REQUIRE_UPPERCASE / _DIGIT / _SYMBOL, all default false).
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// BcryptCost is the work factor for new hashes (MQVI_PASSWORD_BCRYPT_COST,
+	// default 12). Each step doubles hashing time — for cracking and for login alike.
+	BcryptCost int
+}
+
+// NameFilterConfig — optional. Terms that may not appear in usernames, display names, or
+// nicknames, instance-wide. Both sources are merged; if both are empty the check is off.
+type NameFilterConfig struct {
+	// BlockedWords holds the merged terms from MQVI_BLOCKED_NAME_WORDS (comma-separated,
+	// fine for a handful) and the file below.
+	BlockedWords []string
+	// BlockedWordsFile (MQVI_BLOCKED_NAME_WORDS_FILE) points at a one-term-per-line list
+	// for anything longer than a handful. Missing file = startup error, not a silent
+	// no-filter boot.
+	BlockedWordsFile string
 }
 
 // PushConfig — optional. If CredentialsFile is missing or invalid, push is disabled
@@ -126,6 +250,12 @@ type ServerConfig struct {
 	// endpoint hits the database on every request and reports pool and goroutine internals, and
 	// behind a reverse proxy a RemoteAddr check cannot tell the internet from localhost.
 	ReadinessAddr string
+	// ShutdownDrain caps how long a SIGTERM waits for in-flight HTTP requests
+	// (large uploads included) before the server force-closes what is left.
+	ShutdownDrain time.Duration
+	// PresenceIdleTimeout is how long a WS connection may go without a
+	// heartbeat before its user is auto-idled (0 disables the sweep).
+	PresenceIdleTimeout time.Duration
 }
 
 type DatabaseConfig struct {
@@ -148,6 +278,16 @@ type UploadConfig struct {
 	Dir               string
 	MaxSize           int64 // bytes (default: 100MB — Cloudflare body limit)
 	DefaultQuotaBytes int64 // per-user storage quota (default: 10GB)
+	// Image dimension caps applied to image uploads (attachments, avatars,
+	// icons) before storing — see pkg/files.ImageLimits. 0 disables a cap.
+	MaxImageWidth  int // px (default: 16384)
+	MaxImageHeight int // px (default: 16384)
+	MaxImagePixels int // width × height (default: 50 megapixels)
+	// DirMode is the permission mode for directories created under Dir
+	// (MQVI_UPLOAD_DIR_MODE, octal, default 0755). Self-hosts that share the
+	// data directory with a backup user can widen it; hardened deployments can
+	// drop group/other bits.
+	DirMode os.FileMode
 	// PublicURL is the absolute base URL prepended to file URLs when they need
 	// to be served cross-origin or via CDN, e.g. "https://files.mqvi.net".
 	// Empty means file URLs are returned as relative paths (current behaviour).
@@ -179,6 +319,14 @@ type FileRateLimitConfig struct {
 	IPPerMin   int
 }
 
+// MsgRateLimitConfig shapes one message-style limiter: Burst sends per
+// Window, then locked out for Cooldown.
+type MsgRateLimitConfig struct {
+	Burst    int
+	Window   time.Duration
+	Cooldown time.Duration
+}
+
 // Load reads configuration from environment variables.
 // Falls back to .env file in development.
 func Load() (*Config, error) {
@@ -215,6 +363,21 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid MQVI_DEFAULT_QUOTA_BYTES: %w", err)
 	}
 
+	// Image dimension caps (decompression-bomb guard). Defaults: 16384 px per
+	// side (common GPU texture limit) and 50 megapixels total. 0 disables a cap.
+	maxImageWidth, err := strconv.Atoi(getEnv("MQVI_UPLOAD_MAX_IMAGE_WIDTH", "16384"))
+	if err != nil || maxImageWidth < 0 {
+		return nil, fmt.Errorf("invalid MQVI_UPLOAD_MAX_IMAGE_WIDTH: %v", getEnv("MQVI_UPLOAD_MAX_IMAGE_WIDTH", ""))
+	}
+	maxImageHeight, err := strconv.Atoi(getEnv("MQVI_UPLOAD_MAX_IMAGE_HEIGHT", "16384"))
+	if err != nil || maxImageHeight < 0 {
+		return nil, fmt.Errorf("invalid MQVI_UPLOAD_MAX_IMAGE_HEIGHT: %v", getEnv("MQVI_UPLOAD_MAX_IMAGE_HEIGHT", ""))
+	}
+	maxImagePixels, err := strconv.Atoi(getEnv("MQVI_UPLOAD_MAX_IMAGE_PIXELS", "50000000"))
+	if err != nil || maxImagePixels < 0 {
+		return nil, fmt.Errorf("invalid MQVI_UPLOAD_MAX_IMAGE_PIXELS: %v", getEnv("MQVI_UPLOAD_MAX_IMAGE_PIXELS", ""))
+	}
+
 	fileRateUser, err := strconv.Atoi(getEnv("MQVI_FILE_RATE_USER_PER_MIN", "600"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid MQVI_FILE_RATE_USER_PER_MIN: %w", err)
@@ -234,6 +397,35 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid MQVI_PASSWORD_BREACH_CHECK: %w", err)
 	}
+
+	passwordMinLength, err := getEnvInt("MQVI_PASSWORD_MIN_LENGTH", 12)
+	if err != nil {
+		return nil, err
+	}
+	if passwordMinLength < 8 {
+		return nil, fmt.Errorf("MQVI_PASSWORD_MIN_LENGTH must be at least 8 (got %d)", passwordMinLength)
+	}
+	passwordRequireUpper, err := strconv.ParseBool(getEnv("MQVI_PASSWORD_REQUIRE_UPPERCASE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQVI_PASSWORD_REQUIRE_UPPERCASE: %w", err)
+	}
+	passwordRequireDigit, err := strconv.ParseBool(getEnv("MQVI_PASSWORD_REQUIRE_DIGIT", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQVI_PASSWORD_REQUIRE_DIGIT: %w", err)
+	}
+	passwordRequireSymbol, err := strconv.ParseBool(getEnv("MQVI_PASSWORD_REQUIRE_SYMBOL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQVI_PASSWORD_REQUIRE_SYMBOL: %w", err)
+	}
+	// 10–16, not bcrypt's full 4–31: below 10 is crackable on a laptop, above 16 a login
+	// takes seconds and the hash becomes its own denial of service.
+	bcryptCost, err := getEnvInt("MQVI_PASSWORD_BCRYPT_COST", 12)
+	if err != nil {
+		return nil, err
+	}
+	if bcryptCost < 10 || bcryptCost > 16 {
+		return nil, fmt.Errorf("MQVI_PASSWORD_BCRYPT_COST must be between 10 and 16 (got %d)", bcryptCost)
+	}
 	avTimeout, err := strconv.Atoi(getEnv("MQVI_ANTIVIRUS_TIMEOUT_SECONDS", "10"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid MQVI_ANTIVIRUS_TIMEOUT_SECONDS: %w", err)
@@ -342,11 +534,166 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	dirModeRaw := getEnv("MQVI_UPLOAD_DIR_MODE", "0755")
+	dirModeBits, err := strconv.ParseUint(dirModeRaw, 8, 32)
+	if err != nil || dirModeBits > 0o777 {
+		return nil, fmt.Errorf("MQVI_UPLOAD_DIR_MODE must be octal permission bits (e.g. 0755), got %q", dirModeRaw)
+	}
+	if dirModeBits&0o700 != 0o700 {
+		return nil, fmt.Errorf("MQVI_UPLOAD_DIR_MODE must keep owner rwx (the server traverses and writes these directories), got %q", dirModeRaw)
+	}
+
+	maxReactionsPerUser, err := getEnvInt("MQVI_MAX_REACTIONS_PER_USER_PER_MESSAGE", 5)
+	if err != nil {
+		return nil, err
+	}
+	maxReactionEmojis, err := getEnvInt("MQVI_MAX_REACTION_EMOJIS_PER_MESSAGE", 20)
+	if err != nil {
+		return nil, err
+	}
+	if maxReactionsPerUser < 1 || maxReactionEmojis < 1 {
+		return nil, fmt.Errorf("reaction limits must be at least 1 (got per-user %d, per-message %d)", maxReactionsPerUser, maxReactionEmojis)
+	}
+
+	serverCreatePerHour, err := getEnvInt("MQVI_SERVER_CREATE_PER_HOUR", 5)
+	if err != nil {
+		return nil, err
+	}
+	if serverCreatePerHour < 1 {
+		return nil, fmt.Errorf("MQVI_SERVER_CREATE_PER_HOUR must be at least 1 (got %d)", serverCreatePerHour)
+	}
+
+	messageRate, err := loadMsgRateLimit("MQVI_MSG_RATE", 5, 5*time.Second, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	reactionRate, err := loadMsgRateLimit("MQVI_REACTION_RATE", 10, 5*time.Second, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	voiceChannelReconcile, err := getEnvDuration("MQVI_VOICE_CHANNEL_RECONCILE", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if voiceChannelReconcile < time.Minute {
+		return nil, fmt.Errorf("MQVI_VOICE_CHANNEL_RECONCILE must be at least 1m (got %s)", voiceChannelReconcile)
+	}
+
+	maxMessageLines, err := getEnvInt("MQVI_MAX_MESSAGE_LINES", 100)
+	if err != nil {
+		return nil, err
+	}
+	maxMessageBlankLines, err := getEnvInt("MQVI_MAX_MESSAGE_BLANK_LINES", 3)
+	if err != nil {
+		return nil, err
+	}
+	if maxMessageLines < 1 || maxMessageBlankLines < 1 {
+		return nil, fmt.Errorf("message format limits must be at least 1 (got lines %d, blank lines %d)", maxMessageLines, maxMessageBlankLines)
+	}
+
+	statsCacheTTL, err := getEnvDuration("MQVI_STATS_CACHE_TTL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if statsCacheTTL < time.Second {
+		return nil, fmt.Errorf("MQVI_STATS_CACHE_TTL must be at least 1s (got %s)", statsCacheTTL)
+	}
+
+	// Default comfortably above the old fixed 5s: the HTTP read/write timeouts
+	// allow 5-minute uploads, and cutting one mid-transfer on every deploy is
+	// exactly what a drain period is for.
+	shutdownDrain, err := getEnvDuration("MQVI_SHUTDOWN_DRAIN", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if shutdownDrain < time.Second {
+		return nil, fmt.Errorf("MQVI_SHUTDOWN_DRAIN must be at least 1s (got %s)", shutdownDrain)
+	}
+
+	presenceIdleTimeout, err := getEnvDuration("MQVI_PRESENCE_IDLE_TIMEOUT", 10*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if presenceIdleTimeout != 0 && presenceIdleTimeout < time.Minute {
+		return nil, fmt.Errorf("MQVI_PRESENCE_IDLE_TIMEOUT must be 0 (disabled) or at least 1m (got %s)", presenceIdleTimeout)
+	}
+
+	maxVoiceParticipants, err := getEnvInt("MQVI_MAX_VOICE_PARTICIPANTS", 0)
+	if err != nil {
+		return nil, err
+	}
+	if maxVoiceParticipants < 0 {
+		return nil, fmt.Errorf("MQVI_MAX_VOICE_PARTICIPANTS must be >= 0 (0 = no cap, got %d)", maxVoiceParticipants)
+	}
+
+	maxPinsPerChannel, err := getEnvInt("MQVI_MAX_PINS_PER_CHANNEL", 50)
+	if err != nil {
+		return nil, err
+	}
+	if maxPinsPerChannel < 1 {
+		return nil, fmt.Errorf("MQVI_MAX_PINS_PER_CHANNEL must be >= 1 (got %d)", maxPinsPerChannel)
+	}
+
+	auditRetentionDays, err := strconv.Atoi(getEnv("MQVI_AUDIT_RETENTION_DAYS", "90"))
+	if err != nil || auditRetentionDays < 0 {
+		return nil, fmt.Errorf("MQVI_AUDIT_RETENTION_DAYS must be >= 0 (0 = keep forever), got %q", getEnv("MQVI_AUDIT_RETENTION_DAYS", ""))
+	}
+	auditMaxEntries, err := strconv.Atoi(getEnv("MQVI_AUDIT_MAX_ENTRIES_PER_SERVER", "0"))
+	if err != nil || auditMaxEntries < 0 {
+		return nil, fmt.Errorf("MQVI_AUDIT_MAX_ENTRIES_PER_SERVER must be >= 0 (0 = uncapped), got %q", getEnv("MQVI_AUDIT_MAX_ENTRIES_PER_SERVER", ""))
+	}
+
+	voiceBitrateMin, err := getEnvInt("MQVI_VOICE_BITRATE_MIN", 8000)
+	if err != nil {
+		return nil, err
+	}
+	voiceBitrateHostedMax, err := getEnvInt("MQVI_VOICE_BITRATE_MAX_HOSTED", 128000)
+	if err != nil {
+		return nil, err
+	}
+	voiceBitrateSelfMax, err := getEnvInt("MQVI_VOICE_BITRATE_MAX_SELF_HOSTED", 0)
+	if err != nil {
+		return nil, err
+	}
+	if voiceBitrateHostedMax < voiceBitrateMin || (voiceBitrateSelfMax > 0 && voiceBitrateSelfMax < voiceBitrateMin) {
+		return nil, fmt.Errorf("voice bitrate ceilings must not be below MQVI_VOICE_BITRATE_MIN (%d)", voiceBitrateMin)
+	}
+
+	// Validated against the known permission bits here, not at server creation:
+	// a typo'd bitmask should stop the boot, not hand every new server a
+	// nonsense default role.
+	defaultMemberPerms := models.PermDefaultMember
+	if raw := getEnv("MQVI_DEFAULT_MEMBER_PERMS", ""); raw != "" {
+		bits, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("MQVI_DEFAULT_MEMBER_PERMS must be a decimal permission bitmask: %w", err)
+		}
+		if bits < 0 || models.Permission(bits)&^models.PermAll != 0 {
+			return nil, fmt.Errorf("MQVI_DEFAULT_MEMBER_PERMS contains unknown permission bits: %d", bits)
+		}
+		defaultMemberPerms = models.Permission(bits)
+	}
+
+	// Merge the file into the CSV terms here so a bad path stops the boot — discovering
+	// at startup beats discovering from the first offensive username.
+	blockedNameWords := splitCSV(getEnv("MQVI_BLOCKED_NAME_WORDS", ""))
+	blockedNameFile := getEnv("MQVI_BLOCKED_NAME_WORDS_FILE", "")
+	if blockedNameFile != "" {
+		fileTerms, fileErr := namefilter.LoadFile(blockedNameFile)
+		if fileErr != nil {
+			return nil, fmt.Errorf("MQVI_BLOCKED_NAME_WORDS_FILE: %w", fileErr)
+		}
+		blockedNameWords = append(blockedNameWords, fileTerms...)
+	}
+
 	cfg := &Config{
 		Server: ServerConfig{
-			Host:          getEnv("SERVER_HOST", "0.0.0.0"),
-			Port:          port,
-			ReadinessAddr: getEnv("MQVI_READINESS_ADDR", "127.0.0.1:9091"),
+			Host:                getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:                port,
+			ReadinessAddr:       getEnv("MQVI_READINESS_ADDR", "127.0.0.1:9091"),
+			ShutdownDrain:       shutdownDrain,
+			PresenceIdleTimeout: presenceIdleTimeout,
 		},
 		Database: DatabaseConfig{
 			Path: getEnv("DATABASE_PATH", "./data/mqvi.db"),
@@ -365,6 +712,10 @@ func Load() (*Config, error) {
 			Dir:                 getEnv("UPLOAD_DIR", "./data/uploads"),
 			MaxSize:             maxSize,
 			DefaultQuotaBytes:   defaultQuota,
+			MaxImageWidth:       maxImageWidth,
+			MaxImageHeight:      maxImageHeight,
+			MaxImagePixels:      maxImagePixels,
+			DirMode:             os.FileMode(dirModeBits),
 			PublicURL:           getEnv("MQVI_PUBLIC_FILE_URL", ""),
 			SignedURLSecret:     getEnv("MQVI_SIGNED_URL_SECRET", ""),
 			SignedURLSecretPrev: getEnv("MQVI_SIGNED_URL_SECRET_PREV", ""),
@@ -423,8 +774,45 @@ func Load() (*Config, error) {
 		},
 		CallGraceWindow:     callGraceWindow,
 		EncryptionKey:       encKey,
+		EncryptionKeyPrev:   getEnv("ENCRYPTION_KEY_PREV", ""),
 		HetznerAPIToken:     getEnv("HETZNER_API_TOKEN", ""),
 		PasswordBreachCheck: breachCheck,
+		PasswordPolicy: PasswordPolicyConfig{
+			MinLength:        passwordMinLength,
+			RequireUppercase: passwordRequireUpper,
+			RequireDigit:     passwordRequireDigit,
+			RequireSymbol:    passwordRequireSymbol,
+			BcryptCost:       bcryptCost,
+		},
+		NameFilter: NameFilterConfig{
+			BlockedWords:     blockedNameWords,
+			BlockedWordsFile: blockedNameFile,
+		},
+		Reactions: ReactionsConfig{
+			MaxPerUserPerMessage:  maxReactionsPerUser,
+			MaxDistinctPerMessage: maxReactionEmojis,
+		},
+		MessageFormat: MessageFormatConfig{
+			MaxLines:      maxMessageLines,
+			MaxBlankLines: maxMessageBlankLines,
+		},
+		ServerCreatePerHour:           serverCreatePerHour,
+		MessageRate:                   messageRate,
+		ReactionRate:                  reactionRate,
+		VoiceChannelReconcileInterval: voiceChannelReconcile,
+		StatsCacheTTL:                 statsCacheTTL,
+		MaxVoiceParticipants:          maxVoiceParticipants,
+		MaxPinsPerChannel:             maxPinsPerChannel,
+		DefaultMemberPermissions:      defaultMemberPerms,
+		VoiceBitrate: VoiceBitrateConfig{
+			Min:           voiceBitrateMin,
+			HostedMax:     voiceBitrateHostedMax,
+			SelfHostedMax: voiceBitrateSelfMax,
+		},
+		AuditRetention: AuditRetentionConfig{
+			Days:                auditRetentionDays,
+			MaxEntriesPerServer: auditMaxEntries,
+		},
 	}
 
 	return cfg, nil
@@ -502,6 +890,27 @@ func splitCSV(s string) []string {
 	return out
 }
 
+// loadMsgRateLimit reads one limiter's knobs from <prefix>_BURST, _WINDOW
+// and _COOLDOWN. getEnvInt already rejects non-positive bursts.
+func loadMsgRateLimit(prefix string, burst int, window, cooldown time.Duration) (MsgRateLimitConfig, error) {
+	b, err := getEnvInt(prefix+"_BURST", burst)
+	if err != nil {
+		return MsgRateLimitConfig{}, err
+	}
+	w, err := getEnvDuration(prefix+"_WINDOW", window)
+	if err != nil {
+		return MsgRateLimitConfig{}, err
+	}
+	c, err := getEnvDuration(prefix+"_COOLDOWN", cooldown)
+	if err != nil {
+		return MsgRateLimitConfig{}, err
+	}
+	if w < time.Second || c < time.Second {
+		return MsgRateLimitConfig{}, fmt.Errorf("%s_WINDOW and %s_COOLDOWN must be at least 1s (got %s, %s)", prefix, prefix, w, c)
+	}
+	return MsgRateLimitConfig{Burst: b, Window: w, Cooldown: c}, nil
+}
+
 // getEnvInt and getEnvDuration REFUSE a value they cannot parse rather than quietly falling back
 // to the default.
 //
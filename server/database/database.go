@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"io/fs"
@@ -156,9 +157,27 @@ func (db *DB) runMigrations(migrationsFS fs.FS) error {
 // PRAGMA statements are executed outside the transaction (SQLite requires this),
 // all other statements run inside the tx. On success, the migration filename
 // is recorded in schema_migrations within the same tx.
+//
+// The whole file runs on ONE pinned connection. PRAGMAs are per-connection
+// state, so on a pool a PRAGMA could otherwise land on a different connection
+// than the transaction that needs it — e.g. foreign_keys=OFF for a table
+// rebuild. foreign_keys is force-restored before the connection goes back to
+// the pool, so a migration can never leak that state into normal operation.
 func (db *DB) execMigrationInTx(filename, content string) (err error) {
 	statements := splitStatements(content)
 
+	ctx := context.Background()
+	conn, err := db.Conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to pin connection for %s: %w", filename, err)
+	}
+	defer func() {
+		if _, fkErr := conn.ExecContext(ctx, "PRAGMA foreign_keys=ON"); fkErr != nil && err == nil {
+			err = fmt.Errorf("failed to restore foreign_keys after %s: %w", filename, fkErr)
+		}
+		conn.Close()
+	}()
+
 	// Phase 1: execute PRAGMA statements outside the transaction.
 	// SQLite PRAGMAs like journal_mode and foreign_keys cannot run inside a transaction.
 	var nonPragma []string
@@ -168,7 +187,7 @@ func (db *DB) execMigrationInTx(filename, content string) (err error) {
 			continue
 		}
 		if isPragma(stmt) {
-			if _, execErr := db.Conn.Exec(stmt); execErr != nil {
+			if _, execErr := conn.ExecContext(ctx, stmt); execErr != nil {
 				return fmt.Errorf("failed to execute PRAGMA in %s: %w", filename, execErr)
 			}
 		} else {
@@ -177,7 +196,7 @@ func (db *DB) execMigrationInTx(filename, content string) (err error) {
 	}
 
 	// Phase 2: execute remaining statements in a transaction.
-	tx, err := db.Conn.Begin()
+	tx, err := conn.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin migration tx for %s: %w", filename, err)
 	}
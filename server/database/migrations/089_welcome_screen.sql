@@ -0,0 +1,14 @@
+-- Per-server welcome screen and optional rules-acceptance gate.
+--
+-- The welcome config (description, suggested channels, rules text) lives on servers.
+-- rules_required turns on the gate: a member who has not accepted is held read-only
+-- by the permission resolver until they POST /accept-rules.
+--
+-- rules_accepted defaults to 1 so enabling the gate later does not lock out everyone
+-- already inside — only joins made while the gate is on start at 0 (AddMember decides).
+ALTER TABLE servers ADD COLUMN welcome_description TEXT;
+ALTER TABLE servers ADD COLUMN welcome_channel_ids TEXT;
+ALTER TABLE servers ADD COLUMN rules_text TEXT;
+ALTER TABLE servers ADD COLUMN rules_required INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE server_members ADD COLUMN rules_accepted INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,18 @@
+-- 090: Category mute — per-user category muting.
+--
+-- Sits between channel_mutes and server_mutes in the notification cascade:
+-- channel override → category setting → server setting. Muting a category
+-- suppresses notifications/badges for every channel in it without a row per
+-- channel — channels added to the category later are covered automatically.
+-- Unread dots are NOT affected; mutes only silence notifications.
+--
+-- muted_until: NULL = forever; expired mutes are cleaned lazily on read,
+-- same as channel_mutes/server_mutes.
+CREATE TABLE IF NOT EXISTS category_mutes (
+    user_id     TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id TEXT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    server_id   TEXT NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+    muted_until DATETIME,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, category_id)
+);
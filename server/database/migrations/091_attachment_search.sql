@@ -0,0 +1,30 @@
+-- 091: Index attachment filenames for message search.
+--
+-- Search only matched message content, so "that PDF someone shared" was
+-- unfindable unless the sender typed the filename into the message. A second
+-- contentless FTS table over attachments.filename (same trigram tokenizer as
+-- messages_fts, migration 057) lets the search repo union filename hits with
+-- content hits. Attachments are insert/delete-only — no update trigger needed.
+
+CREATE VIRTUAL TABLE IF NOT EXISTS attachments_fts USING fts5(
+    filename,
+    content='attachments',
+    content_rowid='rowid',
+    tokenize='trigram'
+);
+
+-- Backfill existing attachments
+INSERT OR IGNORE INTO attachments_fts(rowid, filename)
+    SELECT rowid, filename FROM attachments;
+
+CREATE TRIGGER IF NOT EXISTS attachments_ai AFTER INSERT ON attachments
+BEGIN
+    INSERT INTO attachments_fts(rowid, filename) VALUES (NEW.rowid, NEW.filename);
+END;
+
+-- External-content prune uses the documented 'delete' command form, not a plain
+-- DELETE — see migration 081 for why the latter corrupts the index.
+CREATE TRIGGER IF NOT EXISTS attachments_ad AFTER DELETE ON attachments
+BEGIN
+    INSERT INTO attachments_fts(attachments_fts, rowid, filename) VALUES ('delete', OLD.rowid, OLD.filename);
+END;
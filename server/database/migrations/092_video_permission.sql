@@ -0,0 +1,6 @@
+-- 092_video_permission.sql
+-- Adds the PermVideo (bit 524288) permission to all existing roles.
+-- Camera publishing was previously bundled into PermSpeak's CanPublish grant;
+-- splitting it out lets admins allow talking but disallow cameras per channel.
+-- Every existing role gets the bit so current behavior is preserved.
+UPDATE roles SET permissions = permissions | 524288;
@@ -0,0 +1,7 @@
+-- 093_pin_announcements.sql
+-- Pin announcements: pinning a message can post a system message in the channel
+-- ("X pinned a message") so pins are discoverable in the timeline.
+--   messages.type          — 'default' for user messages, 'pin' for pin announcements
+--   channels.pin_announcements — per-channel toggle, on by default
+ALTER TABLE messages ADD COLUMN type TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE channels ADD COLUMN pin_announcements INTEGER NOT NULL DEFAULT 1;
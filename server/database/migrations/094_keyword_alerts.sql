@@ -0,0 +1,13 @@
+-- 094: Per-user keyword alerts (Slack-style highlight words).
+--
+-- A keyword row means "notify me when this word appears in any channel I can
+-- read", without requiring an @mention. Keywords are stored lowercased — the
+-- message-create path matches against a lowercased word set, so case never
+-- matters on either side. Matching respects channel visibility and mutes; it
+-- happens in the service, this table only stores the terms.
+CREATE TABLE IF NOT EXISTS user_keyword_alerts (
+    user_id    TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    keyword    TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, keyword)
+);
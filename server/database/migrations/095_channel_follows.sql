@@ -0,0 +1,21 @@
+-- 095: Channel follows — announcement cross-posting between channels.
+--
+-- A text channel (the target) can follow another text channel (the source),
+-- usually across servers: new user messages in the source are cross-posted
+-- into the target as system messages of type 'crosspost'. Creating a follow
+-- requires manage-channels on both channels, so both servers' admins consent
+-- through whoever holds the permission on each end.
+--
+-- ON DELETE CASCADE on both channel FKs is the unfollow-on-delete cleanup:
+-- deleting either channel removes the follow row with it.
+CREATE TABLE IF NOT EXISTS channel_follows (
+    id                TEXT PRIMARY KEY,
+    source_channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    target_channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    created_by        TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source_channel_id, target_channel_id)
+);
+
+-- Fan-out runs per message in a followed channel; this is its lookup.
+CREATE INDEX IF NOT EXISTS idx_channel_follows_source ON channel_follows(source_channel_id);
@@ -0,0 +1,21 @@
+-- 096: Message edit history.
+--
+-- Every edit used to overwrite `content` in place, losing the original text
+-- forever. These tables keep the pre-edit revisions so moderators (and the
+-- author) can see what a message said before. Only plaintext revisions are
+-- stored — recording old ciphertext would be useless to render and would
+-- pin key-rotated blobs forever. The service caps rows per message (25);
+-- rowid gives the revision order since edited_at is second-granular.
+CREATE TABLE IF NOT EXISTS message_edits (
+    message_id  TEXT NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    old_content TEXT NOT NULL,
+    edited_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(message_id);
+
+CREATE TABLE IF NOT EXISTS dm_message_edits (
+    message_id  TEXT NOT NULL REFERENCES dm_messages(id) ON DELETE CASCADE,
+    old_content TEXT NOT NULL,
+    edited_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dm_message_edits_message ON dm_message_edits(message_id);
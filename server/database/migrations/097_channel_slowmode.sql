@@ -0,0 +1,6 @@
+-- 097: Per-channel slowmode.
+--
+-- slowmode_seconds is the minimum gap between two messages from the same user
+-- in the channel (0 = off). Enforcement lives in the message service with an
+-- in-memory last-post tracker; this column only stores the setting.
+ALTER TABLE channels ADD COLUMN slowmode_seconds INTEGER NOT NULL DEFAULT 0;
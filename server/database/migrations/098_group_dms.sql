@@ -0,0 +1,81 @@
+-- 098: Group DMs (3+ participants).
+--
+-- dm_channels was hard-limited to a user1/user2 pair. Membership moves to a
+-- dm_channel_members join table; the pair columns stay for 1:1 channels (the
+-- request/accept flow and the "one channel per pair" rule still hang off them)
+-- and are NULL for group channels.
+--
+-- The pair columns' NOT NULL + table-level UNIQUE can't be relaxed in place,
+-- so dm_channels is rebuilt. foreign_keys must be OFF for the rebuild: with
+-- them on, dropping the old table cascade-deletes every dm_message. The
+-- migration runner pins this file to one connection and restores
+-- foreign_keys=ON afterwards, so no PRAGMA here to turn it back on.
+-- Child tables (dm_messages, dm_settings, dm_read_states) reference
+-- "dm_channels" by name and resolve against the rebuilt table.
+PRAGMA foreign_keys=OFF;
+
+-- The last_message_at triggers (026) UPDATE dm_channels from dm_messages.
+-- ALTER TABLE re-parses every trigger body, and between the DROP and the
+-- RENAME these two dangle — drop them for the rebuild, recreate after.
+DROP TRIGGER IF EXISTS dm_channels_update_last_message_ai;
+DROP TRIGGER IF EXISTS dm_channels_update_last_message_ad;
+
+CREATE TABLE IF NOT EXISTS dm_channels_new (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(8)))),
+    user1_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    user2_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    is_group INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_message_at DATETIME,
+    e2ee_enabled BOOLEAN NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'accepted',
+    initiated_by TEXT
+);
+
+INSERT INTO dm_channels_new (id, user1_id, user2_id, is_group, created_at, last_message_at, e2ee_enabled, status, initiated_by)
+SELECT id, user1_id, user2_id, 0, created_at, last_message_at, e2ee_enabled, status, initiated_by
+FROM dm_channels;
+
+DROP TABLE dm_channels;
+ALTER TABLE dm_channels_new RENAME TO dm_channels;
+
+-- Recreate the 026 triggers verbatim.
+CREATE TRIGGER IF NOT EXISTS dm_channels_update_last_message_ai
+AFTER INSERT ON dm_messages
+BEGIN
+    UPDATE dm_channels
+    SET last_message_at = NEW.created_at
+    WHERE id = NEW.dm_channel_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS dm_channels_update_last_message_ad
+AFTER DELETE ON dm_messages
+BEGIN
+    UPDATE dm_channels
+    SET last_message_at = (
+        SELECT MAX(created_at)
+        FROM dm_messages
+        WHERE dm_channel_id = OLD.dm_channel_id
+    )
+    WHERE id = OLD.dm_channel_id;
+END;
+
+-- The old table-level UNIQUE(user1_id, user2_id), scoped to 1:1 rows. Group
+-- rows have NULL pair columns and stay out of it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dm_channels_pair ON dm_channels(user1_id, user2_id) WHERE is_group = 0;
+
+CREATE TABLE IF NOT EXISTS dm_channel_members (
+    dm_channel_id TEXT NOT NULL REFERENCES dm_channels(id) ON DELETE CASCADE,
+    user_id       TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (dm_channel_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_dm_channel_members_user ON dm_channel_members(user_id);
+
+-- Backfill: every existing 1:1 pair becomes two member rows, so membership
+-- reads can use the join table uniformly.
+INSERT OR IGNORE INTO dm_channel_members (dm_channel_id, user_id)
+SELECT id, user1_id FROM dm_channels WHERE user1_id IS NOT NULL;
+
+INSERT OR IGNORE INTO dm_channel_members (dm_channel_id, user_id)
+SELECT id, user2_id FROM dm_channels WHERE user2_id IS NOT NULL;
@@ -0,0 +1,22 @@
+-- 099: Audit log — who did what on a server, for moderators.
+--
+-- One append-only row per moderation action (kick, ban, unban, role change,
+-- channel create/delete, role CRUD, server settings update). The API exposes
+-- no update or delete; rows only leave the table when their server does.
+--
+-- actor_id/target_id deliberately have no FK to users: the log must outlive
+-- the accounts it mentions. Usernames are joined at read time and simply come
+-- back NULL for deleted accounts. metadata is a JSON blob whose shape depends
+-- on action_type (ban reason, assigned role ids, channel name, ...).
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          TEXT PRIMARY KEY,
+    server_id   TEXT NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+    actor_id    TEXT NOT NULL,
+    action_type TEXT NOT NULL,
+    target_id   TEXT,
+    metadata    TEXT,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The only read path filters by server and pages newest-first.
+CREATE INDEX IF NOT EXISTS idx_audit_logs_server ON audit_logs(server_id, created_at DESC);
@@ -0,0 +1,19 @@
+-- 100: Scheduled messages — queue a channel message for future delivery.
+--
+-- Rows are pending work, not history: the scheduler polls for due rows,
+-- posts them through the normal message-create path, and deletes the row
+-- whether the send succeeded or not (a send refused by a permission
+-- re-check must not retry forever). Deleting the channel or the author
+-- cascades the queue away with them.
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+    id         TEXT PRIMARY KEY,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    user_id    TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content    TEXT NOT NULL,
+    send_at    DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The poller scans by due time; the list endpoint by channel+author.
+CREATE INDEX IF NOT EXISTS idx_scheduled_messages_send_at ON scheduled_messages(send_at);
+CREATE INDEX IF NOT EXISTS idx_scheduled_messages_channel_user ON scheduled_messages(channel_id, user_id);
@@ -0,0 +1,10 @@
+-- 101_pin_positions.sql
+-- Manual ordering for pinned messages.
+--
+-- pin_position orders a channel's pin panel. Every pin starts at 0 and ties
+-- are broken by created_at DESC, so channels that were never reordered keep
+-- the old newest-first order unchanged — no backfill needed. A reorder
+-- rewrites the submitted pins to 0..n-1; a pin created after that defaults to
+-- 0 again and surfaces at the top until the next reorder.
+
+ALTER TABLE pinned_messages ADD COLUMN pin_position INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Member timeout (temporary mute). NULL = not timed out; a timestamp in the
+-- past is treated as expired everywhere it is read, so the background sweep
+-- only exists to clear the column and tell clients — enforcement never races it.
+ALTER TABLE server_members ADD COLUMN timeout_until DATETIME;
@@ -0,0 +1,5 @@
+-- Temporary invites. A member who joined through a temporary invite and never
+-- earned a non-default role is removed when they fully disconnect — the flag on
+-- the membership records how they got in, the role check happens at disconnect.
+ALTER TABLE invites ADD COLUMN temporary INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE server_members ADD COLUMN is_temporary INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Vanity invite codes: a human-readable per-server join alias (mqvi.app/gaming)
+-- that resolves before regular invite codes. Unlimited uses, no expiry. The
+-- partial unique index keeps slugs globally unique while leaving servers
+-- without one (NULL) unconstrained.
+ALTER TABLE servers ADD COLUMN vanity_code TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_servers_vanity_code ON servers(vanity_code) WHERE vanity_code IS NOT NULL;
@@ -0,0 +1,17 @@
+-- Per-user notification settings, one row per (user, scope). Scope is a server
+-- or a single channel; a channel row overrides its server's row. level:
+--   all      — every message may notify
+--   mentions — only mentions may notify
+--   nothing  — not even mentions
+-- suppress_everyone only means anything on server rows. Absence of a row is the
+-- default (all). The older server_mutes/channel_mutes tables stay as-is — they
+-- are the blunt on/off layer; this table is the granular one.
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id    TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scope_type TEXT NOT NULL CHECK(scope_type IN ('server', 'channel')),
+    scope_id   TEXT NOT NULL,
+    level      TEXT NOT NULL DEFAULT 'all' CHECK(level IN ('all', 'mentions', 'nothing')),
+    suppress_everyone INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, scope_type, scope_id)
+);
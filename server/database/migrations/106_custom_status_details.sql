@@ -0,0 +1,7 @@
+-- Custom status details. users.custom_status (the text) predates this migration;
+-- the emoji and the optional expiry arrive with the dedicated endpoint. NULL
+-- expiry = the status stays until the user changes it; a timestamp in the past
+-- is presented as cleared everywhere it is read, so the background sweep only
+-- exists to reset the columns and tell clients.
+ALTER TABLE users ADD COLUMN custom_status_emoji TEXT;
+ALTER TABLE users ADD COLUMN custom_status_expires_at DATETIME;
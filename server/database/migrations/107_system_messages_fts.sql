@@ -0,0 +1,41 @@
+-- Keep system messages out of server-side search.
+--
+-- System messages ("X pinned a message", join/creation notices) carry canned
+-- content that clients render from the type anyway; indexing them just pollutes
+-- search results with one identical hit per event. The insert trigger gains a
+-- type = 'default' guard, and — per the rule from 081/087 — the delete halves
+-- must carry the same predicate so FTS5 is never asked to remove a row it
+-- never stored.
+
+-- De-index system messages that made it into the index before this guard
+-- existed (pin announcements from 093 onward).
+INSERT INTO messages_fts(messages_fts, rowid, content)
+    SELECT 'delete', rowid, content FROM messages
+    WHERE type != 'default' AND content IS NOT NULL AND encryption_version = 0;
+
+DROP TRIGGER IF EXISTS messages_ai;
+CREATE TRIGGER messages_ai AFTER INSERT ON messages
+WHEN NEW.content IS NOT NULL AND NEW.encryption_version = 0 AND NEW.type = 'default'
+BEGIN
+    INSERT INTO messages_fts(rowid, content) VALUES (NEW.rowid, NEW.content);
+END;
+
+DROP TRIGGER IF EXISTS messages_au;
+CREATE TRIGGER messages_au AFTER UPDATE OF content ON messages
+WHEN (OLD.encryption_version = 0 OR NEW.encryption_version = 0) AND NEW.type = 'default'
+BEGIN
+    INSERT INTO messages_fts(messages_fts, rowid, content)
+    SELECT 'delete', OLD.rowid, OLD.content
+    WHERE OLD.content IS NOT NULL AND OLD.encryption_version = 0;
+
+    INSERT INTO messages_fts(rowid, content)
+    SELECT NEW.rowid, NEW.content
+    WHERE NEW.content IS NOT NULL AND NEW.encryption_version = 0;
+END;
+
+DROP TRIGGER IF EXISTS messages_ad;
+CREATE TRIGGER messages_ad AFTER DELETE ON messages
+WHEN OLD.content IS NOT NULL AND OLD.encryption_version = 0 AND OLD.type = 'default'
+BEGIN
+    INSERT INTO messages_fts(messages_fts, rowid, content) VALUES ('delete', OLD.rowid, OLD.content);
+END;
@@ -0,0 +1,26 @@
+-- 108: Webhooks — tokened posting endpoints for integrations (CI, RSS, alerts).
+--
+-- A webhook belongs to one channel; anyone holding its token can post there
+-- through the public execute endpoint, no user account involved. The rendered
+-- identity (name/avatar, per-message overridable) is denormalized onto the
+-- message row at send time so history keeps rendering after the webhook is
+-- renamed or deleted.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id         TEXT PRIMARY KEY,
+    server_id  TEXT NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+    channel_id TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    avatar_url TEXT,
+    token      TEXT NOT NULL UNIQUE,
+    created_by TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_channel ON webhooks(channel_id);
+
+-- Webhook messages keep user_id = the webhook's creator (the column is NOT
+-- NULL and quotas/joins run through it); clients render the webhook identity
+-- whenever webhook_id is set.
+ALTER TABLE messages ADD COLUMN webhook_id TEXT REFERENCES webhooks(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN webhook_name TEXT;
+ALTER TABLE messages ADD COLUMN webhook_avatar_url TEXT;
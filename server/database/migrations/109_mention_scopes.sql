@@ -0,0 +1,8 @@
+-- 109: Mass mentions — @everyone / @here.
+--
+-- mention_scope marks a message that mass-pinged ('everyone' or 'here');
+-- direct <@userId> mentions keep living in message_mentions. @here is
+-- expanded to the members online at send time, stored as mention rows with
+-- kind 'here' so notifications can tell them from a direct ping.
+ALTER TABLE messages ADD COLUMN mention_scope TEXT;
+ALTER TABLE message_mentions ADD COLUMN kind TEXT NOT NULL DEFAULT 'direct';
@@ -0,0 +1,19 @@
+-- 110: Voice channel recordings (LiveKit room composite egress).
+--
+-- Only completed recordings land here; an in-progress recording is tracked in
+-- memory by RecordingService and lost on restart (the egress itself keeps
+-- running and can be stopped from the LiveKit side). file_url points at the
+-- egress service's output storage, not this server's upload tree.
+CREATE TABLE IF NOT EXISTS voice_recordings (
+    id               TEXT PRIMARY KEY,
+    server_id        TEXT NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+    channel_id       TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    egress_id        TEXT NOT NULL,
+    file_url         TEXT,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    started_by       TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at       DATETIME NOT NULL,
+    ended_at         DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_voice_recordings_channel ON voice_recordings(channel_id);
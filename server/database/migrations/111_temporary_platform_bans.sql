@@ -0,0 +1,8 @@
+-- 111_temporary_platform_bans.sql
+-- Temporary platform bans: NULL = permanent (existing behavior), otherwise the
+-- ban stops blocking access once the timestamp passes. Expiry is evaluated at
+-- auth time — no sweeper clears the flag, so an expired ban is simply inert
+-- until an admin re-bans or unbans.
+
+ALTER TABLE users ADD COLUMN platform_banned_until DATETIME;
+ALTER TABLE platform_bans ADD COLUMN banned_until DATETIME;
@@ -0,0 +1,6 @@
+-- 112_livekit_instance_draining.sql
+-- Drain mode for platform-managed LiveKit instances: a draining instance keeps
+-- serving its attached servers but is skipped when new servers are assigned,
+-- so it can be emptied via migration and then decommissioned.
+
+ALTER TABLE livekit_instances ADD COLUMN is_draining INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- Per-server upload limits. upload_max_size in bytes, NULL = platform default
+-- (mqvi-hosted servers are additionally capped by the platform ceiling at
+-- resolution time). The type columns hold comma-separated rules — MIME types
+-- ("application/pdf"), MIME prefixes ("image/*"), or extensions (".exe").
+-- NULL allowed = everything not blocked; blocked always wins.
+ALTER TABLE servers ADD COLUMN upload_max_size INTEGER;
+ALTER TABLE servers ADD COLUMN upload_allowed_types TEXT;
+ALTER TABLE servers ADD COLUMN upload_blocked_types TEXT;
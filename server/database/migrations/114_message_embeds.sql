@@ -0,0 +1,20 @@
+-- 114_message_embeds.sql
+-- Link embeds attached to messages: the Open Graph metadata unfurled from the
+-- first URL in a message, fetched asynchronously after create. One embed per
+-- message. link_previews stays the URL-keyed fetch cache; this table pins the
+-- snapshot the message shipped with.
+CREATE TABLE IF NOT EXISTS message_embeds (
+    message_id  TEXT PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+    url         TEXT NOT NULL,
+    title       TEXT,
+    description TEXT,
+    image_url   TEXT,
+    site_name   TEXT,
+    favicon_url TEXT,
+    created_at  TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- Unfurling is gated on the new PermEmbedLinks (bit 67108864). Link previews
+-- were previously available to everyone via the client-side endpoint, so every
+-- existing role gets the bit to preserve current behavior.
+UPDATE roles SET permissions = permissions | 67108864;
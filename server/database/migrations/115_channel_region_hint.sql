@@ -0,0 +1,6 @@
+-- 115_channel_region_hint.sql
+-- Optional region hint for voice channels ("eu-central", "us-east", ...).
+-- NULL = no preference. Advisory today: voice is served by the server's
+-- LiveKit instance, so the hint rides along in the channel payload and the
+-- voice token response for clients and future per-region instance routing.
+ALTER TABLE channels ADD COLUMN region_hint TEXT;
@@ -0,0 +1,24 @@
+-- 116_export_jobs.sql
+-- Owner-triggered server history exports. Each job streams the server's
+-- messages, attachment metadata and member list into a zip archive under
+-- uploads/exports/<server_id>/<job_id>.zip.
+--
+-- file_path is a disk path, not a served URL: the archive is streamed by the
+-- owner-only download endpoint, never from /api/files, and the exports/
+-- subtree is not a registered upload kind so the orphan walk skips it. The
+-- cleanup sweep's export-expiry phase owns deletion after the 24h TTL.
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id            TEXT PRIMARY KEY,
+    server_id     TEXT NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+    user_id       TEXT NOT NULL,
+    format        TEXT NOT NULL CHECK(format IN ('json', 'csv')),
+    status        TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'running', 'completed', 'failed')),
+    file_path     TEXT,
+    file_size     INTEGER NOT NULL DEFAULT 0,
+    message_count INTEGER NOT NULL DEFAULT 0,
+    error         TEXT,
+    created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at  DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_server ON export_jobs(server_id);
@@ -0,0 +1,5 @@
+-- Per-server nicknames. Membership-scoped like timeout_until: the same user
+-- can go by a different name in every server. NULL means no nickname — clients
+-- fall back to the global display_name, then the username. Mentions keep
+-- resolving by the real username.
+ALTER TABLE server_members ADD COLUMN nickname TEXT;
@@ -0,0 +1,12 @@
+-- Ephemeral messages. A text channel can set delete_after_seconds (0 = off);
+-- every message posted while it is set gets an absolute expires_at, computed at
+-- create time so the sweep never has to consult the channel. A per-message
+-- override in the create request can shorten or set a TTL on its own.
+-- Pinned messages are exempt at sweep time, not here — unpinning re-exposes
+-- the stored deadline.
+ALTER TABLE channels ADD COLUMN delete_after_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN expires_at DATETIME;
+
+-- Partial index: the sweep scans only rows that can expire, and the common
+-- case (no TTL anywhere) costs nothing.
+CREATE INDEX idx_messages_expires_at ON messages(expires_at) WHERE expires_at IS NOT NULL;
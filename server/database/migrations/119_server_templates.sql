@@ -0,0 +1,15 @@
+-- Reusable server structures: categories, channels, roles and channel
+-- permission overrides snapshotted into one JSON document (no messages,
+-- no members). The document references roles and categories by array index
+-- instead of ID, so a template keeps working after the source server — and
+-- everything in it — is gone. Hence no FK to servers, only to the creator.
+CREATE TABLE IF NOT EXISTS server_templates (
+    id         TEXT PRIMARY KEY,
+    creator_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    is_public  INTEGER NOT NULL DEFAULT 0,
+    structure  TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_server_templates_creator ON server_templates(creator_id);
@@ -0,0 +1,4 @@
+-- Optional per-server rule: members may not react to their own messages.
+-- Off by default; self-reactions elsewhere (and in DMs, which have no server)
+-- stay allowed. Enforced only when a toggle would add — removals always work.
+ALTER TABLE servers ADD COLUMN disallow_self_reactions INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,27 @@
+-- 121_channel_permission_member_overrides.sql
+-- Generalize channel_permissions from role-only overrides to role OR member
+-- targets. The (channel_id, role_id) key becomes (channel_id, target_type,
+-- target_id) with target_type in ('role','member'); existing rows carry over
+-- as role targets. target_id is polymorphic (roles.id or users.id), so the
+-- old role FK cannot survive — stale rows for deleted roles/users simply never
+-- match anyone at resolution time.
+-- Note: migration runner wraps this in a transaction automatically.
+
+CREATE TABLE IF NOT EXISTS channel_permissions_new (
+    channel_id  TEXT NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    target_type TEXT NOT NULL CHECK(target_type IN ('role', 'member')),
+    target_id   TEXT NOT NULL,
+    allow       INTEGER NOT NULL DEFAULT 0,
+    deny        INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (channel_id, target_type, target_id)
+);
+
+INSERT INTO channel_permissions_new (channel_id, target_type, target_id, allow, deny)
+    SELECT channel_id, 'role', role_id, allow, deny FROM channel_permissions;
+
+DROP TABLE channel_permissions;
+ALTER TABLE channel_permissions_new RENAME TO channel_permissions;
+
+-- Member-override lookups during resolution and visibility filtering are keyed
+-- by target_id (the user), not the channel the PK leads with.
+CREATE INDEX IF NOT EXISTS idx_channel_permissions_target ON channel_permissions(target_type, target_id);
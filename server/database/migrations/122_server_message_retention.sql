@@ -0,0 +1,7 @@
+-- Per-server message retention. message_retention_days (0 = keep forever)
+-- sets a rolling cutoff: the daily retention sweep deletes messages whose
+-- created_at is older than the window, channel by channel, in bounded chunks.
+-- Pinned messages are exempt at sweep time, like the ephemeral-message sweep.
+-- No expires_at stamping here — retention is a moving window over created_at,
+-- so changing the setting takes effect without rewriting existing rows.
+ALTER TABLE servers ADD COLUMN message_retention_days INTEGER NOT NULL DEFAULT 0;
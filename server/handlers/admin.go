@@ -28,6 +28,7 @@ type AdminHandler struct {
 	appLogService         services.AppLogService
 	badgeService          services.SettingsBadgeService
 	screenShareStats      ScreenShareStatsProvider
+	instanceLimits        *models.InstanceLimits
 }
 
 func NewAdminHandler(
@@ -39,6 +40,7 @@ func NewAdminHandler(
 	appLogService services.AppLogService,
 	badgeService services.SettingsBadgeService,
 	screenShareStats ScreenShareStatsProvider,
+	instanceLimits *models.InstanceLimits,
 ) *AdminHandler {
 	return &AdminHandler{
 		livekitAdminService:   livekitAdminService,
@@ -49,11 +51,18 @@ func NewAdminHandler(
 		appLogService:         appLogService,
 		badgeService:          badgeService,
 		screenShareStats:      screenShareStats,
+		instanceLimits:        instanceLimits,
 	}
 }
 
 // GetBadges -- GET /api/admin/badges
 // Returns whether the current admin has unseen feedback or reports.
+// GetInstanceLimits handles GET /api/admin/limits — the effective limits in
+// force, resolved at boot. Limits, not secrets: nothing here needs redaction.
+func (h *AdminHandler) GetInstanceLimits(w http.ResponseWriter, r *http.Request) {
+	pkg.JSON(w, http.StatusOK, h.instanceLimits)
+}
+
 func (h *AdminHandler) GetBadges(w http.ResponseWriter, r *http.Request) {
 	admin, ok := r.Context().Value(UserContextKey).(*models.User)
 	if !ok {
@@ -219,8 +228,8 @@ func (h *AdminHandler) DeleteLiveKitInstance(w http.ResponseWriter, r *http.Requ
 }
 
 // ListServers -- GET /api/admin/servers
-// Query: ?limit=50&offset=0&search=foo&status=active&status=soft_deleted&type=managed&sort=name&dir=asc
-// status/type are repeatable (empty = all).
+// Query: ?limit=50&offset=0&search=foo&status=active&status=soft_deleted&type=managed&instance_id=li1&sort=name&dir=asc
+// status/type are repeatable (empty = all); instance_id narrows to one LiveKit instance.
 func (h *AdminHandler) ListServers(w http.ResponseWriter, r *http.Request) {
 	page, err := h.livekitAdminService.ListServersPaged(r.Context(),
 		parseAdminListParams(r, []string{"active", "soft_deleted"}))
@@ -349,15 +358,16 @@ func parseAdminListParams(r *http.Request, allowedStatuses []string) models.Admi
 		dir = "desc"
 	}
 	return models.AdminListPageParams{
-		Limit:     limit,
-		Offset:    offset,
-		Search:    q.Get("search"),
-		Statuses:  filterAllowed(parseMultiQuery(r, "status"), allowedStatuses),
-		Presences: filterAllowed(parseMultiQuery(r, "presence"), []string{"online", "idle", "dnd", "offline"}),
-		Admin:     filterAllowed(parseMultiQuery(r, "admin"), []string{"admin", "non_admin"}),
-		Types:     filterAllowed(parseMultiQuery(r, "type"), []string{"managed", "self"}),
-		Sort:      q.Get("sort"),
-		Dir:       dir,
+		Limit:      limit,
+		Offset:     offset,
+		Search:     q.Get("search"),
+		Statuses:   filterAllowed(parseMultiQuery(r, "status"), allowedStatuses),
+		Presences:  filterAllowed(parseMultiQuery(r, "presence"), []string{"online", "idle", "dnd", "offline"}),
+		Admin:      filterAllowed(parseMultiQuery(r, "admin"), []string{"admin", "non_admin"}),
+		Types:      filterAllowed(parseMultiQuery(r, "type"), []string{"managed", "self"}),
+		InstanceID: q.Get("instance_id"),
+		Sort:       q.Get("sort"),
+		Dir:        dir,
 	}
 }
 
@@ -447,7 +457,7 @@ func (h *AdminHandler) PlatformBanUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.adminUserService.PlatformBanUser(r.Context(), admin.ID, targetID, req.Reason, req.DeleteMessages); err != nil {
+	if err := h.adminUserService.PlatformBanUser(r.Context(), admin.ID, targetID, req.Reason, req.BannedUntil, req.DeleteMessages); err != nil {
 		pkg.Error(w, err)
 		return
 	}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// AuditLogHandler serves the server moderation audit trail.
+type AuditLogHandler struct {
+	auditService services.AuditLogService
+}
+
+func NewAuditLogHandler(auditService services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{auditService: auditService}
+}
+
+// List handles GET /api/servers/{serverId}/audit-logs?action_type=X&before=ID&limit=50
+// (requires VIEW_AUDIT_LOG). Cursor-based pagination, newest first, limit max 100.
+func (h *AuditLogHandler) List(w http.ResponseWriter, r *http.Request) {
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	actionType := r.URL.Query().Get("action_type")
+	beforeID := r.URL.Query().Get("before")
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	page, err := h.auditService.List(r.Context(), serverID, actionType, beforeID, limit)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, page)
+}
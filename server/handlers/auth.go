@@ -10,6 +10,7 @@ import (
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/pkg/authcookie"
+	"github.com/akinalp/mqvi/pkg/password"
 	"github.com/akinalp/mqvi/pkg/ratelimit"
 	"github.com/akinalp/mqvi/services"
 )
@@ -45,17 +46,28 @@ func NewAuthHandler(
 	}
 }
 
+// ipRateLimited runs an IP-keyed limiter, stamps the X-RateLimit-* headers on
+// the response either way, and writes the 429 when over budget. `what` names
+// the spent budget in the error message ("login attempts", ...).
+func ipRateLimited(w http.ResponseWriter, limiter *ratelimit.LoginRateLimiter, ip, what string) bool {
+	if limiter == nil {
+		return false
+	}
+	res := limiter.AllowWithResult(ip)
+	res.SetHeaders(w)
+	if res.Allowed {
+		return false
+	}
+	pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
+		fmt.Sprintf("too many %s, please try again in %s", what, ratelimit.FormatRetryMessage(res.RetryAfterSeconds())))
+	return true
+}
+
 // Register handles POST /api/auth/register
 // First registered user automatically becomes Owner.
 // IP-based rate limiting prevents registration spam.
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	ip := ratelimit.ExtractIP(r)
-	if h.registerLimiter != nil && !h.registerLimiter.Allow(ip) {
-		retryAfter := h.registerLimiter.RetryAfterSeconds(ip)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many registration attempts, please try again in %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if ipRateLimited(w, h.registerLimiter, ratelimit.ExtractIP(r), "registration attempts") {
 		return
 	}
 
@@ -81,12 +93,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // IP-based rate limiting protects against brute-force. Successful login resets the counter.
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ip := ratelimit.ExtractIP(r)
-	if h.loginLimiter != nil && !h.loginLimiter.Allow(ip) {
-		retryAfter := h.loginLimiter.RetryAfterSeconds(ip)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many login attempts, please try again in %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if ipRateLimited(w, h.loginLimiter, ip, "login attempts") {
 		return
 	}
 
@@ -270,13 +277,7 @@ func (h *AuthHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
 // Returns same success response whether email exists or not (enumeration protection).
 // IP-based rate limiting + per-email 90s cooldown.
 func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
-	ip := ratelimit.ExtractIP(r)
-	if h.forgotPwdLimiter != nil && !h.forgotPwdLimiter.Allow(ip) {
-		retryAfter := h.forgotPwdLimiter.RetryAfterSeconds(ip)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many requests, please try again in %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if ipRateLimited(w, h.forgotPwdLimiter, ratelimit.ExtractIP(r), "requests") {
 		return
 	}
 
@@ -315,13 +316,7 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 // Validates token, updates password, deletes token.
 // IP-based rate limiting prevents brute-force token guessing.
 func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
-	ip := ratelimit.ExtractIP(r)
-	if h.resetPwdLimiter != nil && !h.resetPwdLimiter.Allow(ip) {
-		retryAfter := h.resetPwdLimiter.RetryAfterSeconds(ip)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many attempts, please try again in %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if ipRateLimited(w, h.resetPwdLimiter, ratelimit.ExtractIP(r), "attempts") {
 		return
 	}
 
@@ -347,6 +342,20 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// PasswordPolicy handles GET /api/auth/password-policy. Public — the register and reset
+// forms need the rules before there is a session. The bcrypt cost stays server-side; what
+// the hash costs to crack is nobody's business but the operator's.
+func (h *AuthHandler) PasswordPolicy(w http.ResponseWriter, r *http.Request) {
+	p := h.authService.PasswordPolicy()
+	pkg.JSON(w, http.StatusOK, map[string]any{
+		"min_length":        p.MinLength,
+		"max_bytes":         password.MaxBytes,
+		"require_uppercase": p.RequireUppercase,
+		"require_digit":     p.RequireDigit,
+		"require_symbol":    p.RequireSymbol,
+	})
+}
+
 // SoftDeleteSelf — DELETE /api/users/me
 // Body: { "password": "..." }
 // Soft-deletes the current user (recoverable via login). Disconnects sessions/WS.
@@ -384,12 +393,7 @@ func (h *AuthHandler) SoftDeleteSelf(w http.ResponseWriter, r *http.Request) {
 // limiter cannot pivot to /auth/restore for the same brute-force budget.
 func (h *AuthHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
 	ip := ratelimit.ExtractIP(r)
-	if h.loginLimiter != nil && !h.loginLimiter.Allow(ip) {
-		retryAfter := h.loginLimiter.RetryAfterSeconds(ip)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many attempts, please try again in %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if ipRateLimited(w, h.loginLimiter, ip, "attempts") {
 		return
 	}
 
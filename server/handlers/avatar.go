@@ -34,6 +34,7 @@ type AvatarHandler struct {
 	locator       *files.Locator
 	pipeline      services.UploadPipeline
 	urlSigner     services.FileURLSigner
+	imageLimits   files.ImageLimits
 }
 
 func NewAvatarHandler(
@@ -43,6 +44,7 @@ func NewAvatarHandler(
 	locator *files.Locator,
 	pipeline services.UploadPipeline,
 	urlSigner services.FileURLSigner,
+	imageLimits files.ImageLimits,
 ) *AvatarHandler {
 	return &AvatarHandler{
 		userRepo:      userRepo,
@@ -51,6 +53,7 @@ func NewAvatarHandler(
 		locator:       locator,
 		pipeline:      pipeline,
 		urlSigner:     urlSigner,
+		imageLimits:   imageLimits,
 	}
 }
 
@@ -227,6 +230,15 @@ func (h *AvatarHandler) processUpload(w http.ResponseWriter, r *http.Request, ki
 		return "", fmt.Errorf("%w: only image files are allowed (jpeg, png, gif, webp)", pkg.ErrBadRequest)
 	}
 
+	// Avatars and icons are decoded by every member's client — same
+	// decompression-bomb exposure as message attachments.
+	if err := h.imageLimits.Check(file); err != nil {
+		if errors.Is(err, files.ErrImageTooLarge) {
+			return "", fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+		}
+		return "", err
+	}
+
 	stored, err := h.pipeline.Store(r.Context(), kind, scopeID, file, header, avatarMaxSize)
 	if err != nil {
 		if errors.Is(err, files.ErrInvalidSegment) {
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// CategoryMuteHandler handles category mute/unmute endpoints.
+type CategoryMuteHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewCategoryMuteHandler(notificationService services.NotificationService) *CategoryMuteHandler {
+	return &CategoryMuteHandler{notificationService: notificationService}
+}
+
+// Mute mutes a category (all its channels, for notification purposes) for the current user.
+// POST /api/servers/{serverId}/categories/{id}/mute
+// Body: {"duration": "1h" | "8h" | "7d" | "forever"}
+func (h *CategoryMuteHandler) Mute(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	categoryID := r.PathValue("id")
+	if categoryID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "category id required")
+		return
+	}
+
+	var req models.MuteCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.notificationService.MuteCategory(r.Context(), user.ID, categoryID, serverID, &req); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "category muted"})
+}
+
+// Unmute removes a category mute for the current user.
+// DELETE /api/servers/{serverId}/categories/{id}/mute
+func (h *CategoryMuteHandler) Unmute(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	categoryID := r.PathValue("id")
+	if categoryID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "category id required")
+		return
+	}
+
+	if err := h.notificationService.UnmuteCategory(r.Context(), user.ID, categoryID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "category unmuted"})
+}
+
+// ListMuted returns muted category IDs for the current user.
+// GET /api/categories/mutes
+func (h *CategoryMuteHandler) ListMuted(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	ids, err := h.notificationService.GetMutedCategoryIDs(r.Context(), user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	if ids == nil {
+		ids = []string{}
+	}
+
+	pkg.JSON(w, http.StatusOK, ids)
+}
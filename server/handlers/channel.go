@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
@@ -19,6 +20,8 @@ func NewChannelHandler(channelService services.ChannelService) *ChannelHandler {
 
 // List handles GET /api/servers/{serverId}/channels
 // Returns channels grouped by category, filtered by ViewChannel permission.
+// ?include=unread,pins adds per-channel unread and pin counts to the same tree;
+// without it the response is byte-for-byte the old shape.
 func (h *ChannelHandler) List(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value(UserContextKey).(*models.User)
 	if !ok {
@@ -32,7 +35,24 @@ func (h *ChannelHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	grouped, err := h.channelService.GetAllGrouped(r.Context(), serverID, user.ID)
+	var includeUnread, includePins bool
+	if raw := r.URL.Query().Get("include"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			switch strings.TrimSpace(part) {
+			case "unread":
+				includeUnread = true
+			case "pins":
+				includePins = true
+			case "":
+			default:
+				// Reject typos rather than silently serving a sidebar with no badges.
+				pkg.ErrorWithMessage(w, http.StatusBadRequest, "unknown include value: "+part)
+				return
+			}
+		}
+	}
+
+	grouped, err := h.channelService.GetAllGroupedWithMeta(r.Context(), serverID, user.ID, includeUnread, includePins)
 	if err != nil {
 		pkg.Error(w, err)
 		return
@@ -49,13 +69,39 @@ func (h *ChannelHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	var req models.CreateChannelRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	channel, err := h.channelService.Create(r.Context(), serverID, &req)
+	channel, err := h.channelService.Create(r.Context(), serverID, user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, channel)
+}
+
+// Clone handles POST /api/servers/{serverId}/channels/{id}/clone (requires MANAGE_CHANNELS).
+func (h *ChannelHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	id := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	channel, err := h.channelService.Clone(r.Context(), serverID, user.ID, id)
 	if err != nil {
 		pkg.Error(w, err)
 		return
@@ -89,7 +135,13 @@ func (h *ChannelHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	serverID := r.PathValue("serverId")
 	id := r.PathValue("id")
 
-	if err := h.channelService.Delete(r.Context(), serverID, id); err != nil {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.channelService.Delete(r.Context(), serverID, user.ID, id); err != nil {
 		pkg.Error(w, err)
 		return
 	}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+type ChannelFollowHandler struct {
+	followService services.ChannelFollowService
+}
+
+func NewChannelFollowHandler(followService services.ChannelFollowService) *ChannelFollowHandler {
+	return &ChannelFollowHandler{followService: followService}
+}
+
+// List handles GET /api/servers/{serverId}/channels/{channelId}/follows
+func (h *ChannelFollowHandler) List(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("channelId")
+
+	follows, err := h.followService.ListFollows(r.Context(), serverID, channelID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, follows)
+}
+
+// Follow handles POST /api/servers/{serverId}/channels/{channelId}/follows
+// Requires ManageChannels permission; the target end is checked in the service.
+func (h *ChannelFollowHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("channelId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	var req models.FollowChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	follow, err := h.followService.Follow(r.Context(), serverID, channelID, user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, follow)
+}
+
+// Unfollow handles DELETE /api/servers/{serverId}/channels/{channelId}/follows/{targetChannelId}
+func (h *ChannelFollowHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("channelId")
+	targetChannelID := r.PathValue("targetChannelId")
+
+	if err := h.followService.Unfollow(r.Context(), serverID, channelID, targetChannelID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "channel unfollowed"})
+}
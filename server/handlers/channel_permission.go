@@ -33,14 +33,16 @@ func (h *ChannelPermissionHandler) ListOverrides(w http.ResponseWriter, r *http.
 	pkg.JSON(w, http.StatusOK, overrides)
 }
 
-// SetOverride handles PUT /api/channels/{channelId}/permissions/{roleId}
-// Upserts a permission override. allow and deny must not overlap.
+// SetOverride handles PUT /api/channels/{channelId}/permissions/{targetType}/{targetId}
+// Upserts a permission override for a role or a specific member.
+// allow and deny must not overlap.
 // Only channel-level permissions (ChannelOverridablePerms) can be overridden.
 // allow=0, deny=0 deletes the override (reverts to inherit).
 func (h *ChannelPermissionHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
 	serverID := r.PathValue("serverId")
 	channelID := r.PathValue("channelId")
-	roleID := r.PathValue("roleId")
+	targetType := r.PathValue("targetType")
+	targetID := r.PathValue("targetId")
 
 	var req models.SetOverrideRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -48,7 +50,7 @@ func (h *ChannelPermissionHandler) SetOverride(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := h.service.SetOverride(r.Context(), serverID, channelID, roleID, &req); err != nil {
+	if err := h.service.SetOverride(r.Context(), serverID, channelID, targetType, targetID, &req); err != nil {
 		pkg.Error(w, err)
 		return
 	}
@@ -56,14 +58,15 @@ func (h *ChannelPermissionHandler) SetOverride(w http.ResponseWriter, r *http.Re
 	pkg.JSON(w, http.StatusOK, map[string]string{"message": "override updated"})
 }
 
-// DeleteOverride handles DELETE /api/channels/{channelId}/permissions/{roleId}
-// Removes the override; the role falls back to its global permissions.
+// DeleteOverride handles DELETE /api/channels/{channelId}/permissions/{targetType}/{targetId}
+// Removes the override; the target falls back to its inherited permissions.
 func (h *ChannelPermissionHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
 	serverID := r.PathValue("serverId")
 	channelID := r.PathValue("channelId")
-	roleID := r.PathValue("roleId")
+	targetType := r.PathValue("targetType")
+	targetID := r.PathValue("targetId")
 
-	if err := h.service.DeleteOverride(r.Context(), serverID, channelID, roleID); err != nil {
+	if err := h.service.DeleteOverride(r.Context(), serverID, channelID, targetType, targetID); err != nil {
 		pkg.Error(w, err)
 		return
 	}
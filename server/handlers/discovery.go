@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -52,14 +51,19 @@ func NewDiscoveryHandler(
 	}
 }
 
-// rateLimited writes a 429 (with Retry-After) and returns true when the user is over budget.
+// rateLimited writes a 429 (with the rate-limit headers) and returns true when
+// the user is over budget.
 func (h *DiscoveryHandler) rateLimited(w http.ResponseWriter, userID string) bool {
-	if h.limiter != nil && !h.limiter.Allow(userID) {
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", h.limiter.CooldownSeconds(userID)))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests, "too many requests, slow down")
-		return true
+	if h.limiter == nil {
+		return false
 	}
-	return false
+	res := h.limiter.AllowWithResult(userID)
+	res.SetHeaders(w)
+	if res.Allowed {
+		return false
+	}
+	pkg.ErrorWithMessage(w, http.StatusTooManyRequests, "too many requests, slow down")
+	return true
 }
 
 // ListPublicServers -- GET /api/discovery/servers?q=&category=&featured=&page=&limit=
@@ -24,6 +24,7 @@ type DMHandler struct {
 	messageLimiter  *ratelimit.MessageRateLimiter
 	readLimiter     *ratelimit.MessageRateLimiter
 	urlSigner       services.FileURLSigner
+	idempotency     services.IdempotencyService
 }
 
 func NewDMHandler(
@@ -34,6 +35,7 @@ func NewDMHandler(
 	messageLimiter *ratelimit.MessageRateLimiter,
 	readLimiter *ratelimit.MessageRateLimiter,
 	urlSigner services.FileURLSigner,
+	idempotency services.IdempotencyService,
 ) *DMHandler {
 	return &DMHandler{
 		dmService:       dmService,
@@ -43,22 +45,33 @@ func NewDMHandler(
 		messageLimiter:  messageLimiter,
 		readLimiter:     readLimiter,
 		urlSigner:       urlSigner,
+		idempotency:     idempotency,
 	}
 }
 
-// rateLimited replies 429 with a Retry-After and reports whether it did. Shared by the
-// mark-read endpoints, which are cheap per call and ruinous in a loop.
-func rateLimited(w http.ResponseWriter, limiter *ratelimit.MessageRateLimiter, userID string) bool {
-	if limiter == nil || limiter.Allow(userID) {
+// userRateLimited runs a per-user limiter, stamps the X-RateLimit-* headers on
+// the response either way, and writes the 429 when over budget. `what` names
+// the spent budget in the error message ("messages", "replies", ...).
+func userRateLimited(w http.ResponseWriter, limiter *ratelimit.MessageRateLimiter, userID, what string) bool {
+	if limiter == nil {
+		return false
+	}
+	res := limiter.AllowWithResult(userID)
+	res.SetHeaders(w)
+	if res.Allowed {
 		return false
 	}
-	retryAfter := limiter.CooldownSeconds(userID)
-	w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
 	pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-		fmt.Sprintf("too many requests, please wait %s", ratelimit.FormatRetryMessage(retryAfter)))
+		fmt.Sprintf("too many %s, please wait %s", what, ratelimit.FormatRetryMessage(res.RetryAfterSeconds())))
 	return true
 }
 
+// rateLimited replies 429 with a Retry-After and reports whether it did. Shared by the
+// mark-read endpoints, which are cheap per call and ruinous in a loop.
+func rateLimited(w http.ResponseWriter, limiter *ratelimit.MessageRateLimiter, userID string) bool {
+	return userRateLimited(w, limiter, userID, "requests")
+}
+
 type createDMChannelRequest struct {
 	UserID string `json:"user_id"`
 }
@@ -109,6 +122,82 @@ func (h *DMHandler) CreateOrGetChannel(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, channel)
 }
 
+// CreateGroupChannel handles POST /api/dms/groups
+func (h *DMHandler) CreateGroupChannel(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	var req models.CreateGroupDMRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	channel, err := h.dmService.CreateGroupChannel(r.Context(), user.ID, req.MemberIDs)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, channel)
+}
+
+type addGroupMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// AddGroupMember handles POST /api/dms/channels/{channelId}/members
+func (h *DMHandler) AddGroupMember(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("channelId")
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	var req addGroupMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.UserID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	if err := h.dmService.AddGroupMember(r.Context(), user.ID, channelID, req.UserID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveGroupMember handles DELETE /api/dms/channels/{channelId}/members/{userId}
+func (h *DMHandler) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("channelId")
+	memberID := r.PathValue("userId")
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	if err := h.dmService.RemoveGroupMember(r.Context(), user.ID, channelID, memberID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // AcceptRequest handles POST /api/dms/{channelId}/accept
 func (h *DMHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
 	channelID := r.PathValue("channelId")
@@ -169,6 +258,33 @@ func (h *DMHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, page)
 }
 
+// GetMessagesAround handles GET /api/dms/{channelId}/messages/around/{messageId}
+// — the context window for "jump to message". limit counts per direction.
+func (h *DMHandler) GetMessagesAround(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("channelId")
+	messageID := r.PathValue("messageId")
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	limit := 25
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 50 {
+			limit = parsed
+		}
+	}
+
+	page, err := h.dmService.GetMessagesAround(r.Context(), user.ID, channelID, messageID, limit)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, page)
+}
+
 // SendMessage handles POST /api/dms/{channelId}/messages
 // Accepts JSON or multipart/form-data. Files uploaded after message creation, then WS broadcast.
 func (h *DMHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
@@ -179,12 +295,16 @@ func (h *DMHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.messageLimiter != nil && !h.messageLimiter.Allow(user.ID) {
-		retryAfter := h.messageLimiter.CooldownSeconds(user.ID)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many messages, please wait %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	// Same retry shield as channel sends: a repeated Idempotency-Key replays
+	// the original response instead of double-posting, before the rate limit.
+	idemKey := r.Header.Get("Idempotency-Key")
+	if cached, ok := h.idempotency.Replay(user.ID, idemKey); ok {
+		w.Header().Set("Idempotency-Replay", "true")
+		pkg.JSON(w, http.StatusCreated, cached)
+		return
+	}
+
+	if userRateLimited(w, h.messageLimiter, user.ID, "messages") {
 		return
 	}
 
@@ -307,6 +427,8 @@ func (h *DMHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 	// Broadcast after uploads so clients see attachments
 	h.dmService.BroadcastCreate(msg)
 
+	h.idempotency.Remember(user.ID, idemKey, msg)
+
 	pkg.JSON(w, http.StatusCreated, msg)
 }
 
@@ -381,6 +503,28 @@ func (h *DMHandler) ToggleReaction(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// ListReactionUsers handles GET /api/dms/messages/{id}/reactions
+// Emoji as the `emoji` query parameter, paging as in ReactionHandler.ListUsers.
+func (h *DMHandler) ListReactionUsers(w http.ResponseWriter, r *http.Request) {
+	messageID := r.PathValue("id")
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	emoji := r.URL.Query().Get("emoji")
+	limit, offset := reactionPageParams(r)
+
+	page, err := h.dmService.ListReactors(r.Context(), user.ID, messageID, emoji, limit, offset)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, page)
+}
+
 // ─── Pin Endpoints ───
 
 // PinMessage handles POST /api/dms/messages/{id}/pin
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// ExportHandler manages server history export jobs.
+// Ownership checks live in the service layer — every route re-verifies there.
+type ExportHandler struct {
+	service services.ExportService
+}
+
+func NewExportHandler(service services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// Start kicks off an async export job.
+// POST /api/servers/{serverId}/export?format=json|csv
+func (h *ExportHandler) Start(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	job, err := h.service.StartExport(r.Context(), serverID, user.ID, r.URL.Query().Get("format"))
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusAccepted, job)
+}
+
+// Status returns the job for polling.
+// GET /api/servers/{serverId}/export/{jobId}
+func (h *ExportHandler) Status(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	jobID := r.PathValue("jobId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	job, err := h.service.GetJob(r.Context(), serverID, jobID, user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, job)
+}
+
+// Download streams the completed archive.
+// GET /api/servers/{serverId}/export/{jobId}/download
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	jobID := r.PathValue("jobId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	f, job, err := h.service.OpenArchive(r.Context(), serverID, jobID, user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s-%s.zip"`, serverID, job.Format))
+	if job.FileSize > 0 {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", job.FileSize))
+	}
+	// Errors here are client disconnects — headers are already out.
+	_, _ = io.Copy(w, f)
+}
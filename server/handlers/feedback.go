@@ -68,12 +68,7 @@ func (h *FeedbackHandler) CreateTicket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.limiter != nil && !h.limiter.Allow(user.ID) {
-		retryAfter := h.limiter.CooldownSeconds(user.ID)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many feedback submissions, please wait %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if userRateLimited(w, h.limiter, user.ID, "feedback submissions") {
 		return
 	}
 
@@ -208,12 +203,7 @@ func (h *FeedbackHandler) AddReply(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.limiter != nil && !h.limiter.Allow(user.ID) {
-		retryAfter := h.limiter.CooldownSeconds(user.ID)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many replies, please wait %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if userRateLimited(w, h.limiter, user.ID, "replies") {
 		return
 	}
 
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/akinalp/mqvi/models"
@@ -58,11 +57,7 @@ func (h *ICEServerHandler) GetICEServers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if h.limiter != nil && !h.limiter.Allow(user.ID) {
-		retryAfter := h.limiter.CooldownSeconds(user.ID)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many requests, please wait %s", ratelimit.FormatRetryMessage(retryAfter)))
+	if userRateLimited(w, h.limiter, user.ID, "requests") {
 		return
 	}
 
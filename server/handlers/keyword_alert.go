@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// KeywordAlertHandler handles the current user's highlight-word endpoints.
+type KeywordAlertHandler struct {
+	keywordAlertService services.KeywordAlertService
+}
+
+func NewKeywordAlertHandler(keywordAlertService services.KeywordAlertService) *KeywordAlertHandler {
+	return &KeywordAlertHandler{keywordAlertService: keywordAlertService}
+}
+
+// List returns the current user's keywords.
+// GET /api/users/me/keyword-alerts
+func (h *KeywordAlertHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	keywords, err := h.keywordAlertService.List(r.Context(), user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string][]string{"keywords": keywords})
+}
+
+// Add registers a keyword for the current user.
+// POST /api/users/me/keyword-alerts
+func (h *KeywordAlertHandler) Add(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	var req models.AddKeywordAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.keywordAlertService.Add(r.Context(), user.ID, &req); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "keyword added"})
+}
+
+// Remove deletes a keyword for the current user.
+// DELETE /api/users/me/keyword-alerts/{keyword}
+func (h *KeywordAlertHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	if err := h.keywordAlertService.Remove(r.Context(), user.ID, r.PathValue("keyword")); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "keyword removed"})
+}
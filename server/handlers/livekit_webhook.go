@@ -31,16 +31,16 @@ type WebhookKeyLoader interface {
 }
 
 type LiveKitWebhookHandler struct {
-	keyLoader     WebhookKeyLoader
-	encryptionKey []byte // AES-256-GCM key for credential decryption
-	appLogger     services.AppLogService
+	keyLoader WebhookKeyLoader
+	keychain  *crypto.Keychain // AES-256-GCM keys for credential decryption
+	appLogger services.AppLogService
 }
 
-func NewLiveKitWebhookHandler(keyLoader WebhookKeyLoader, encryptionKey []byte, appLogger services.AppLogService) *LiveKitWebhookHandler {
+func NewLiveKitWebhookHandler(keyLoader WebhookKeyLoader, keychain *crypto.Keychain, appLogger services.AppLogService) *LiveKitWebhookHandler {
 	return &LiveKitWebhookHandler{
-		keyLoader:     keyLoader,
-		encryptionKey: encryptionKey,
-		appLogger:     appLogger,
+		keyLoader: keyLoader,
+		keychain:  keychain,
+		appLogger: appLogger,
 	}
 }
 
@@ -79,12 +79,12 @@ func (h *LiveKitWebhookHandler) buildKeyProvider(ctx context.Context) (auth.KeyP
 
 	keys := make(map[string]string, len(instances))
 	for _, inst := range instances {
-		apiKey, err := crypto.Decrypt(inst.APIKey, h.encryptionKey)
+		apiKey, err := h.keychain.Decrypt(inst.APIKey)
 		if err != nil {
 			log.Printf("[livekit-webhook] failed to decrypt key for instance %s: %v", inst.ID, err)
 			continue
 		}
-		apiSecret, err := crypto.Decrypt(inst.APISecret, h.encryptionKey)
+		apiSecret, err := h.keychain.Decrypt(inst.APISecret)
 		if err != nil {
 			log.Printf("[livekit-webhook] failed to decrypt secret for instance %s: %v", inst.ID, err)
 			continue
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
@@ -18,6 +19,8 @@ func NewMemberHandler(memberService services.MemberService) *MemberHandler {
 }
 
 // List handles GET /api/servers/{serverId}/members
+// Query params: limit (default 100, max 200), after (cursor: last user ID of the
+// previous page), q (username/display-name filter), role_id (role holders only).
 func (h *MemberHandler) List(w http.ResponseWriter, r *http.Request) {
 	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
 	if !ok || serverID == "" {
@@ -25,13 +28,25 @@ func (h *MemberHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	members, err := h.memberService.GetAll(r.Context(), serverID)
+	params := models.MemberListParams{
+		Limit:  100,
+		After:  r.URL.Query().Get("after"),
+		Q:      r.URL.Query().Get("q"),
+		RoleID: r.URL.Query().Get("role_id"),
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 200 {
+			params.Limit = parsed
+		}
+	}
+
+	page, err := h.memberService.List(r.Context(), serverID, params)
 	if err != nil {
 		pkg.Error(w, err)
 		return
 	}
 
-	pkg.JSON(w, http.StatusOK, members)
+	pkg.JSON(w, http.StatusOK, page)
 }
 
 // Get handles GET /api/servers/{serverId}/members/{id}
@@ -143,6 +158,99 @@ func (h *MemberHandler) Ban(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, map[string]string{"message": "member banned"})
 }
 
+// Timeout handles PATCH /api/servers/{serverId}/members/{id}/timeout
+func (h *MemberHandler) Timeout(w http.ResponseWriter, r *http.Request) {
+	actor, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	targetID := r.PathValue("id")
+
+	var req models.TimeoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.memberService.Timeout(r.Context(), serverID, actor.ID, targetID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, member)
+}
+
+// UpdateOwnNickname handles PATCH /api/servers/{serverId}/members/me/nickname.
+// Body nickname null removes the nickname.
+func (h *MemberHandler) UpdateOwnNickname(w http.ResponseWriter, r *http.Request) {
+	actor, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	var req models.UpdateNicknameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.memberService.UpdateNickname(r.Context(), serverID, actor.ID, actor.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, member)
+}
+
+// UpdateNickname handles PATCH /api/servers/{serverId}/members/{id}/nickname
+// (requires MANAGE_NICKNAMES).
+func (h *MemberHandler) UpdateNickname(w http.ResponseWriter, r *http.Request) {
+	actor, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	targetID := r.PathValue("id")
+
+	var req models.UpdateNicknameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	member, err := h.memberService.UpdateNickname(r.Context(), serverID, actor.ID, targetID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, member)
+}
+
 // GetBans handles GET /api/servers/{serverId}/bans (requires BAN_MEMBERS).
 func (h *MemberHandler) GetBans(w http.ResponseWriter, r *http.Request) {
 	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
@@ -168,9 +276,15 @@ func (h *MemberHandler) Unban(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actor, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	userID := r.PathValue("id")
 
-	if err := h.memberService.Unban(r.Context(), serverID, userID); err != nil {
+	if err := h.memberService.Unban(r.Context(), serverID, actor.ID, userID); err != nil {
 		pkg.Error(w, err)
 		return
 	}
@@ -201,3 +315,27 @@ func (h *MemberHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 
 	pkg.JSON(w, http.StatusOK, member)
 }
+
+// UpdateCustomStatus handles PUT /api/users/me/custom-status
+// Global endpoint (not server-scoped). Empty text and emoji clear the status.
+func (h *MemberHandler) UpdateCustomStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	var req models.UpdateCustomStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := h.memberService.UpdateCustomStatus(r.Context(), user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, updated)
+}
@@ -20,26 +20,32 @@ type MessageHandler struct {
 	messageService services.MessageService
 	uploadService  services.UploadService
 	storageService services.StorageService
+	uploadPolicy   services.UploadPolicyResolver
 	maxUploadSize  int64
 	messageLimiter *ratelimit.MessageRateLimiter
 	urlSigner      services.FileURLSigner
+	idempotency    services.IdempotencyService
 }
 
 func NewMessageHandler(
 	messageService services.MessageService,
 	uploadService services.UploadService,
 	storageService services.StorageService,
+	uploadPolicy services.UploadPolicyResolver,
 	maxUploadSize int64,
 	messageLimiter *ratelimit.MessageRateLimiter,
 	urlSigner services.FileURLSigner,
+	idempotency services.IdempotencyService,
 ) *MessageHandler {
 	return &MessageHandler{
 		messageService: messageService,
 		uploadService:  uploadService,
 		storageService: storageService,
+		uploadPolicy:   uploadPolicy,
 		maxUploadSize:  maxUploadSize,
 		messageLimiter: messageLimiter,
 		urlSigner:      urlSigner,
+		idempotency:    idempotency,
 	}
 }
 
@@ -72,6 +78,53 @@ func (h *MessageHandler) List(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, page)
 }
 
+// Around handles GET /api/servers/{serverId}/channels/{id}/messages/around/{messageId}
+// — the context window for "jump to message". limit counts per direction.
+func (h *MessageHandler) Around(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("id")
+	messageID := r.PathValue("messageId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	limit := 25
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 50 {
+			limit = parsed
+		}
+	}
+
+	page, err := h.messageService.GetMessagesAround(r.Context(), channelID, user.ID, messageID, limit)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, page)
+}
+
+// Stats handles GET /api/servers/{serverId}/channels/{id}/stats
+func (h *MessageHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	stats, err := h.messageService.GetChannelStats(r.Context(), channelID, user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, stats)
+}
+
 // Create handles POST /api/channels/{id}/messages
 // Accepts JSON or multipart/form-data (for file attachments).
 func (h *MessageHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -83,12 +136,19 @@ func (h *MessageHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.messageLimiter != nil && !h.messageLimiter.Allow(user.ID) {
-		retryAfter := h.messageLimiter.CooldownSeconds(user.ID)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many messages, please wait %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	// A retry of a send whose response was lost must not create a duplicate.
+	// Checked before the rate limit — the original request already paid for it.
+	idemKey := r.Header.Get("Idempotency-Key")
+	if cached, ok := h.idempotency.Replay(user.ID, idemKey); ok {
+		w.Header().Set("Idempotency-Replay", "true")
+		pkg.JSON(w, http.StatusCreated, cached)
+		return
+	}
+
+	// Moderators are exempt: ManageMessages already trusts them with the
+	// channel's content, and cleanup tooling shouldn't trip the spam guard.
+	perms, _ := r.Context().Value(PermissionsContextKey).(models.Permission)
+	if !perms.Has(models.PermManageMessages) && userRateLimited(w, h.messageLimiter, user.ID, "messages") {
 		return
 	}
 
@@ -96,9 +156,20 @@ func (h *MessageHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	var req models.CreateMessageRequest
 
+	// The server's upload policy caps the body before anything is parsed, so a
+	// raised self-hosted limit actually takes effect and a lowered one rejects
+	// early instead of after the whole file was read.
+	uploadPolicy := models.UploadPolicy{MaxSize: h.maxUploadSize}
 	if isMultipart(contentType) {
-		limitMultipartBody(w, r, h.maxUploadSize, maxMessageUploadFiles)
-		if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
+		policy, err := h.uploadPolicy.UploadPolicy(r.Context(), r.PathValue("serverId"))
+		if err != nil {
+			pkg.Error(w, err)
+			return
+		}
+		uploadPolicy = policy
+
+		limitMultipartBody(w, r, uploadPolicy.MaxSize, maxMessageUploadFiles)
+		if err := r.ParseMultipartForm(uploadPolicy.MaxSize); err != nil {
 			pkg.ErrorWithMessage(w, http.StatusBadRequest, "failed to parse multipart form")
 			return
 		}
@@ -106,6 +177,15 @@ func (h *MessageHandler) Create(w http.ResponseWriter, r *http.Request) {
 			pkg.ErrorWithMessage(w, http.StatusBadRequest, "too many files")
 			return
 		}
+		// Reject oversized files on their multipart size hint, before quota
+		// reservation and message creation.
+		for _, fh := range r.MultipartForm.File["files"] {
+			if fh.Size > uploadPolicy.MaxSize {
+				pkg.ErrorWithMessage(w, http.StatusBadRequest,
+					fmt.Sprintf("file %q is too large (this server allows at most %dMB)", fh.Filename, uploadPolicy.MaxSize/(1024*1024)))
+				return
+			}
+		}
 
 		req.Content = r.FormValue("content")
 		if replyTo := r.FormValue("reply_to_id"); replyTo != "" {
@@ -182,7 +262,7 @@ func (h *MessageHandler) Create(w http.ResponseWriter, r *http.Request) {
 			}
 
 			thumb := thumbnailFor(r.MultipartForm, i)
-			attachment, err := h.uploadService.Upload(r.Context(), message.ID, file, fileHeader, isEncrypted, thumb)
+			attachment, err := h.uploadService.Upload(r.Context(), message.ID, file, fileHeader, isEncrypted, thumb, uploadPolicy)
 			file.Close()
 			if thumb != nil {
 				thumb.File.Close()
@@ -218,6 +298,10 @@ func (h *MessageHandler) Create(w http.ResponseWriter, r *http.Request) {
 	// Broadcast after uploads so all clients see attachments
 	h.messageService.BroadcastCreate(message)
 
+	// Remembered only after uploads succeeded — a replay must return the
+	// message exactly as the original response carried it.
+	h.idempotency.Remember(user.ID, idemKey, message)
+
 	pkg.JSON(w, http.StatusCreated, message)
 }
 
@@ -267,6 +351,54 @@ func (h *MessageHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, map[string]string{"message": "message deleted"})
 }
 
+// BulkDelete — POST /api/servers/{serverId}/channels/{id}/messages/bulk-delete
+// Body: {"message_ids": [...]} or {"user_id": "...", "within_minutes": 30}
+// Moderation only — the route gates on PermManageMessages.
+func (h *MessageHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("id")
+
+	var req models.BulkDeleteMessagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	deletedIDs, err := h.messageService.BulkDelete(r.Context(), serverID, channelID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]any{
+		"deleted_ids": deletedIDs,
+		"count":       len(deletedIDs),
+	})
+}
+
+// GetEditHistory returns a message's prior revisions (newest first) so the
+// frontend can render a diff. GET /api/servers/{serverId}/messages/{id}/history
+func (h *MessageHandler) GetEditHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	perms, _ := r.Context().Value(PermissionsContextKey).(models.Permission)
+	serverID := r.PathValue("serverId")
+
+	history, err := h.messageService.GetEditHistory(r.Context(), serverID, id, user.ID, perms)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, history)
+}
+
 // PermissionsContextKey carries the user's effective permissions in request context.
 const PermissionsContextKey contextKey = "permissions"
 
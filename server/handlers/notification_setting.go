@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// NotificationSettingHandler handles the granular per-scope notification settings.
+type NotificationSettingHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationSettingHandler(notificationService services.NotificationService) *NotificationSettingHandler {
+	return &NotificationSettingHandler{notificationService: notificationService}
+}
+
+// Update sets (or resets) the current user's notification level for one scope.
+// PUT /api/users/me/notifications/{scopeType}/{scopeId}
+// Body: {"level": "all" | "mentions" | "nothing", "suppress_everyone": bool}
+func (h *NotificationSettingHandler) Update(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	scopeType := r.PathValue("scopeType")
+	scopeID := r.PathValue("scopeId")
+	if scopeType == "" || scopeID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "scope type and scope id required")
+		return
+	}
+
+	var req models.UpdateNotificationSettingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	setting, err := h.notificationService.UpdateSetting(r.Context(), user.ID, scopeType, scopeID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, setting)
+}
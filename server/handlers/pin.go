@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/akinalp/mqvi/models"
@@ -57,6 +58,26 @@ func (h *PinHandler) Pin(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusCreated, pin)
 }
 
+// Reorder handles PATCH /api/servers/{serverId}/channels/{id}/pins/reorder (requires MANAGE_MESSAGES).
+func (h *PinHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("id")
+
+	var req models.ReorderPinsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	pins, err := h.pinService.Reorder(r.Context(), serverID, channelID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, pins)
+}
+
 // Unpin handles DELETE /api/channels/{channelId}/messages/{messageId}/pin
 // Requires ManageMessages permission.
 func (h *PinHandler) Unpin(w http.ResponseWriter, r *http.Request) {
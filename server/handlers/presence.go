@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// PresenceHandler handles the batch presence query endpoint.
+type PresenceHandler struct {
+	audience services.PresenceAudienceService
+}
+
+func NewPresenceHandler(audience services.PresenceAudienceService) *PresenceHandler {
+	return &PresenceHandler{audience: audience}
+}
+
+// Query -- POST /api/users/presence
+// Returns the current status for a specific set of users — the catch-up for
+// lists that loaded after the ready event. IDs outside the requester's
+// presence audience (no shared server, not friends) are absent from the
+// response; invisible users report as offline.
+func (h *PresenceHandler) Query(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	var req models.BatchPresenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		pkg.Error(w, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err))
+		return
+	}
+
+	statuses, err := h.audience.Statuses(r.Context(), user.ID, req.UserIDs)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]any{"statuses": statuses})
+}
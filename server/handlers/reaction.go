@@ -3,18 +3,24 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/pkg/ratelimit"
 	"github.com/akinalp/mqvi/services"
 )
 
 type ReactionHandler struct {
 	reactionService services.ReactionService
+	reactionLimiter *ratelimit.MessageRateLimiter
 }
 
-func NewReactionHandler(reactionService services.ReactionService) *ReactionHandler {
-	return &ReactionHandler{reactionService: reactionService}
+func NewReactionHandler(reactionService services.ReactionService, reactionLimiter *ratelimit.MessageRateLimiter) *ReactionHandler {
+	return &ReactionHandler{
+		reactionService: reactionService,
+		reactionLimiter: reactionLimiter,
+	}
 }
 
 type toggleRequest struct {
@@ -38,6 +44,13 @@ func (h *ReactionHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Moderators are exempt — ManageMessages already trusts them with the
+	// channel's content wholesale.
+	perms, _ := r.Context().Value(PermissionsContextKey).(models.Permission)
+	if !perms.Has(models.PermManageMessages) && userRateLimited(w, h.reactionLimiter, user.ID, "reactions") {
+		return
+	}
+
 	if err := h.reactionService.ToggleReaction(r.Context(), messageID, user.ID, body.Emoji); err != nil {
 		pkg.Error(w, err)
 		return
@@ -45,3 +58,44 @@ func (h *ReactionHandler) Toggle(w http.ResponseWriter, r *http.Request) {
 
 	pkg.JSON(w, http.StatusOK, map[string]string{"message": "reaction toggled"})
 }
+
+// ListUsers handles GET /api/servers/{serverId}/messages/{messageId}/reactions
+// Emoji comes as the `emoji` query parameter — unicode emoji make unreliable
+// path segments. Pagination via limit (default 50, max 100) and offset.
+func (h *ReactionHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	messageID := r.PathValue("messageId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	emoji := r.URL.Query().Get("emoji")
+	limit, offset := reactionPageParams(r)
+
+	page, err := h.reactionService.ListReactors(r.Context(), messageID, user.ID, emoji, limit, offset)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, page)
+}
+
+// reactionPageParams parses limit/offset for reactor lists; shared with the DM
+// handler so both endpoints page identically.
+func reactionPageParams(r *http.Request) (limit, offset int) {
+	limit = 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+	return limit, offset
+}
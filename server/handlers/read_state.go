@@ -118,7 +118,8 @@ func (h *ReadStateHandler) MarkMentionSeen(w http.ResponseWriter, r *http.Reques
 	pkg.JSON(w, http.StatusOK, map[string]string{"message": "mention seen"})
 }
 
-// GetUnreads returns unread message counts for all channels in the server.
+// GetUnreads returns unread and mention counts for all channels in the server,
+// plus the server-wide mention total for the sidebar badge.
 // GET /api/servers/{serverId}/channels/unread
 func (h *ReadStateHandler) GetUnreads(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value(UserContextKey).(*models.User)
@@ -141,3 +142,22 @@ func (h *ReadStateHandler) GetUnreads(w http.ResponseWriter, r *http.Request) {
 
 	pkg.JSON(w, http.StatusOK, unreads)
 }
+
+// GetAllUnreads returns the unread/mention snapshot across every server the user is a
+// member of — what a client pulls after being backgrounded instead of replaying events.
+// GET /api/unreads
+func (h *ReadStateHandler) GetAllUnreads(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	unreads, err := h.readStateService.GetAllUnreadCounts(r.Context(), user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, unreads)
+}
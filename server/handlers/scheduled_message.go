@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// ScheduledMessageHandler handles queueing messages for future delivery.
+type ScheduledMessageHandler struct {
+	schedulerService services.SchedulerService
+}
+
+func NewScheduledMessageHandler(schedulerService services.SchedulerService) *ScheduledMessageHandler {
+	return &ScheduledMessageHandler{schedulerService: schedulerService}
+}
+
+// Create handles POST /api/servers/{serverId}/channels/{id}/messages/schedule
+// (requires SEND_MESSAGES).
+func (h *ScheduledMessageHandler) Create(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.ScheduleMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	msg, err := h.schedulerService.Schedule(r.Context(), serverID, channelID, user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, msg)
+}
+
+// List handles GET /api/servers/{serverId}/channels/{id}/messages/scheduled —
+// the caller's own pending queue for this channel.
+func (h *ScheduledMessageHandler) List(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	messages, err := h.schedulerService.ListPending(r.Context(), serverID, channelID, user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, messages)
+}
+
+// Delete handles DELETE /api/servers/{serverId}/channels/{id}/messages/scheduled/{messageId}.
+func (h *ScheduledMessageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("id")
+	messageID := r.PathValue("messageId")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.schedulerService.Cancel(r.Context(), serverID, channelID, user.ID, messageID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
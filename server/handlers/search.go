@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/services"
 )
@@ -17,7 +18,7 @@ func NewSearchHandler(searchService services.SearchService) *SearchHandler {
 }
 
 // Search handles GET /api/servers/{serverId}/search?q=query&channel_id=optional&limit=25&offset=0
-// FTS5 full-text search scoped to the server's channels.
+// FTS5 full-text search scoped to the channels the requesting user can see.
 func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
 	if !ok || serverID == "" {
@@ -25,6 +26,12 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
 	query := r.URL.Query().Get("q")
 	if query == "" {
 		pkg.ErrorWithMessage(w, http.StatusBadRequest, "query parameter 'q' is required")
@@ -50,7 +57,7 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	result, err := h.searchService.Search(r.Context(), serverID, query, channelID, limit, offset)
+	result, err := h.searchService.Search(r.Context(), serverID, user.ID, query, channelID, limit, offset)
 	if err != nil {
 		pkg.Error(w, err)
 		return
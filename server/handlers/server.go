@@ -6,16 +6,19 @@ import (
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/pkg/ratelimit"
 	"github.com/akinalp/mqvi/services"
 )
 
 // ServerHandler handles server CRUD, join/leave, and reorder endpoints.
 type ServerHandler struct {
 	serverService services.ServerService
+	createLimiter *ratelimit.MessageRateLimiter
+	appLog        services.AppLogService
 }
 
-func NewServerHandler(serverService services.ServerService) *ServerHandler {
-	return &ServerHandler{serverService: serverService}
+func NewServerHandler(serverService services.ServerService, createLimiter *ratelimit.MessageRateLimiter, appLog services.AppLogService) *ServerHandler {
+	return &ServerHandler{serverService: serverService, createLimiter: createLimiter, appLog: appLog}
 }
 
 // ListMyServers returns all servers the user is a member of.
@@ -46,6 +49,12 @@ func (h *ServerHandler) CreateServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The owned-server count limit only covers mqvi-hosted servers; the rate limit
+	// also slows self-hosted spam. Platform admins are exempt.
+	if !user.IsPlatformAdmin && userRateLimited(w, h.createLimiter, user.ID, "servers created") {
+		return
+	}
+
 	var req models.CreateServerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
@@ -58,9 +67,140 @@ func (h *ServerHandler) CreateServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Audit trail for abuse investigation: who created what, and how it is hosted.
+	if h.appLog != nil {
+		h.appLog.Log(models.LogLevelInfo, models.LogCategoryServer, &user.ID, &server.ID,
+			"server created", map[string]string{"name": server.Name, "host_type": req.HostType})
+	}
+
 	pkg.JSON(w, http.StatusCreated, server)
 }
 
+// CreateFromDM upgrades a DM conversation into a new server.
+// POST /api/dms/channels/{channelId}/create-server
+// Body: { "name": "..." }
+func (h *ServerHandler) CreateFromDM(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	// Same budget as regular creation — an upgrade IS a server creation.
+	if !user.IsPlatformAdmin && userRateLimited(w, h.createLimiter, user.ID, "servers created") {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	server, err := h.serverService.CreateFromDM(r.Context(), user.ID, r.PathValue("channelId"), req.Name)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	if h.appLog != nil {
+		h.appLog.Log(models.LogLevelInfo, models.LogCategoryServer, &user.ID, &server.ID,
+			"server created from dm", map[string]string{"name": server.Name})
+	}
+
+	pkg.JSON(w, http.StatusCreated, server)
+}
+
+// SaveTemplate snapshots this server's structure into a reusable template.
+// POST /api/servers/{serverId}/template (requires ADMIN)
+// Body: { "name": "...", "is_public": false }
+func (h *ServerHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	var req models.SaveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	template, err := h.serverService.SaveTemplate(r.Context(), serverID, user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, template)
+}
+
+// CreateFromTemplate instantiates a template as a new mqvi-hosted server.
+// POST /api/servers/from-template
+// Body: { "template_id": "...", "name": "..." }
+func (h *ServerHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	// Same budget as regular creation — instantiating IS a server creation.
+	if !user.IsPlatformAdmin && userRateLimited(w, h.createLimiter, user.ID, "servers created") {
+		return
+	}
+
+	var req models.CreateFromTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	server, err := h.serverService.CreateFromTemplate(r.Context(), user.ID, req.TemplateID, req.Name)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	if h.appLog != nil {
+		h.appLog.Log(models.LogLevelInfo, models.LogCategoryServer, &user.ID, &server.ID,
+			"server created from template", map[string]string{"name": server.Name, "template_id": req.TemplateID})
+	}
+
+	pkg.JSON(w, http.StatusCreated, server)
+}
+
+// ListTemplates returns the templates the caller may instantiate: every
+// public one plus their own private ones.
+// GET /api/templates
+func (h *ServerHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	templates, err := h.serverService.ListTemplates(r.Context(), user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, templates)
+}
+
 // JoinServer joins a server via invite code.
 // POST /api/servers/join
 // Body: { "invite_code": "abc123" }
@@ -88,7 +228,17 @@ func (h *ServerHandler) JoinServer(w http.ResponseWriter, r *http.Request) {
 		pkg.JSON(w, http.StatusOK, map[string]any{"pending": true})
 		return
 	}
-	pkg.JSON(w, http.StatusOK, map[string]any{"pending": false, "server": result.Server})
+
+	// ?hydrate=true also returns the member's visible channels and roles, so the
+	// client can render the server without two follow-up fetches.
+	if r.URL.Query().Get("hydrate") == "true" {
+		if err := h.serverService.HydrateJoinResult(r.Context(), result, user.ID); err != nil {
+			pkg.Error(w, err)
+			return
+		}
+	}
+
+	pkg.JSON(w, http.StatusOK, result)
 }
 
 // ListJoinRequests -- GET /api/servers/{serverId}/requests (PermApproveMembers)
@@ -186,13 +336,19 @@ func (h *ServerHandler) UpdateServer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
 	var req models.UpdateServerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	server, err := h.serverService.UpdateServer(r.Context(), serverID, &req)
+	server, err := h.serverService.UpdateServer(r.Context(), serverID, user.ID, &req)
 	if err != nil {
 		pkg.Error(w, err)
 		return
@@ -201,6 +357,35 @@ func (h *ServerHandler) UpdateServer(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, server)
 }
 
+// SetVanity sets or clears the server's vanity invite code. Requires MANAGE_INVITES.
+// POST /api/servers/{serverId}/vanity
+func (h *ServerHandler) SetVanity(w http.ResponseWriter, r *http.Request) {
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	var req models.SetVanityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	code, err := h.serverService.SetVanityCode(r.Context(), serverID, req.Code)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]*string{"vanity_code": code})
+}
+
 // DeleteServer soft-deletes a server. Owner only. Restorable for 30 days.
 // DELETE /api/servers/{serverId}
 func (h *ServerHandler) DeleteServer(w http.ResponseWriter, r *http.Request) {
@@ -311,6 +496,102 @@ func (h *ServerHandler) LeaveServer(w http.ResponseWriter, r *http.Request) {
 	pkg.JSON(w, http.StatusOK, map[string]string{"message": "left server"})
 }
 
+// TransferOwnership hands the server to another member. Owner-only with password
+// re-entry, both enforced in the service.
+// POST /api/servers/{serverId}/transfer
+func (h *ServerHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	var req models.TransferOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	server, err := h.serverService.TransferOwnership(r.Context(), serverID, user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, server)
+}
+
+// GetWelcomeScreen returns the server's welcome screen / rules config.
+// GET /api/servers/{serverId}/welcome
+func (h *ServerHandler) GetWelcomeScreen(w http.ResponseWriter, r *http.Request) {
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	cfg, err := h.serverService.GetWelcomeScreen(r.Context(), serverID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, cfg)
+}
+
+// UpdateWelcomeScreen replaces the welcome screen / rules config. Requires admin permission.
+// PUT /api/servers/{serverId}/welcome
+func (h *ServerHandler) UpdateWelcomeScreen(w http.ResponseWriter, r *http.Request) {
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	var cfg models.WelcomeConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	updated, err := h.serverService.UpdateWelcomeScreen(r.Context(), serverID, &cfg)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, updated)
+}
+
+// AcceptRules records the member's rules acceptance and lifts the read-only gate.
+// POST /api/servers/{serverId}/accept-rules
+func (h *ServerHandler) AcceptRules(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	serverID, ok := r.Context().Value(ServerIDContextKey).(string)
+	if !ok || serverID == "" {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "server context required")
+		return
+	}
+
+	if err := h.serverService.AcceptRules(r.Context(), serverID, user.ID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "rules accepted"})
+}
+
 // ReorderServers reorders the user's personal server list.
 // PATCH /api/servers/reorder
 // Body: { "items": [{ "id": "serverId", "position": 0 }, ...] }
@@ -2,32 +2,89 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/pkg/cache"
 	"github.com/akinalp/mqvi/repository"
 )
 
 type StatsResponse struct {
-	TotalUsers int `json:"total_users"`
+	TotalUsers      int `json:"total_users"`
+	TotalServers    int `json:"total_servers"`
+	TotalMessages   int `json:"total_messages"`
+	PeakOnlineUsers int `json:"peak_online_users"`
+}
+
+// statsCacheKey — the cache holds a single entry; the key only exists because
+// TTLCache is keyed.
+const statsCacheKey = "public"
+
+// OnlinePeakProvider reports the high-water mark of concurrently connected users.
+// Satisfied by ws.Hub.
+type OnlinePeakProvider interface {
+	PeakOnlineUsers() int
 }
 
 // StatsHandler serves public stats (no auth required). Used by the landing page.
+// Responses are cached for a short TTL — the endpoint is unauthenticated, so
+// without the cache every bot hitting it costs three COUNT(*) scans.
 type StatsHandler struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	serverRepo  repository.ServerRepository
+	messageRepo repository.MessageRepository
+	peakOnline  OnlinePeakProvider
+	statsCache  *cache.TTLCache[string, StatsResponse]
 }
 
-func NewStatsHandler(userRepo repository.UserRepository) *StatsHandler {
-	return &StatsHandler{userRepo: userRepo}
+func NewStatsHandler(
+	userRepo repository.UserRepository,
+	serverRepo repository.ServerRepository,
+	messageRepo repository.MessageRepository,
+	peakOnline OnlinePeakProvider,
+	cacheTTL time.Duration,
+) *StatsHandler {
+	return &StatsHandler{
+		userRepo:    userRepo,
+		serverRepo:  serverRepo,
+		messageRepo: messageRepo,
+		peakOnline:  peakOnline,
+		statsCache:  cache.New[string, StatsResponse](cacheTTL, 5*time.Minute),
+	}
 }
 
-// GetPublicStats returns total registered user count.
+// GetPublicStats returns totals for users, servers, and messages plus the peak
+// concurrent user count since boot.
 // GET /api/stats
 func (h *StatsHandler) GetPublicStats(w http.ResponseWriter, r *http.Request) {
-	count, err := h.userRepo.Count(r.Context())
+	if cached, ok := h.statsCache.Get(statsCacheKey); ok {
+		pkg.JSON(w, http.StatusOK, cached)
+		return
+	}
+
+	users, err := h.userRepo.Count(r.Context())
 	if err != nil {
 		pkg.ErrorWithMessage(w, http.StatusInternalServerError, "failed to get stats")
 		return
 	}
+	servers, err := h.serverRepo.Count(r.Context())
+	if err != nil {
+		pkg.ErrorWithMessage(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+	messages, err := h.messageRepo.Count(r.Context())
+	if err != nil {
+		pkg.ErrorWithMessage(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+
+	resp := StatsResponse{
+		TotalUsers:      users,
+		TotalServers:    servers,
+		TotalMessages:   messages,
+		PeakOnlineUsers: h.peakOnline.PeakOnlineUsers(),
+	}
+	h.statsCache.Set(statsCacheKey, resp)
 
-	pkg.JSON(w, http.StatusOK, StatsResponse{TotalUsers: count})
+	pkg.JSON(w, http.StatusOK, resp)
 }
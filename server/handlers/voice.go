@@ -17,6 +17,7 @@ type voiceHandlerService interface {
 	GenerateToken(ctx context.Context, userID, username, displayName, channelID string) (*models.VoiceTokenResponse, error)
 	GenerateScreenShareToken(ctx context.Context, userID, username, displayName, channelID string) (*models.VoiceTokenResponse, error)
 	GetAllVoiceStates() []models.VoiceState
+	InstanceMaxParticipants() int
 }
 
 type VoiceHandler struct {
@@ -114,5 +115,8 @@ func (h *VoiceHandler) VoiceStates(w http.ResponseWriter, r *http.Request) {
 		st.AvatarURL = h.urlSigner.SignURL(st.AvatarURL)
 		out = append(out, st)
 	}
-	pkg.JSON(w, http.StatusOK, out)
+	pkg.JSON(w, http.StatusOK, models.VoiceStatesResponse{
+		States:          out,
+		MaxParticipants: h.voiceService.InstanceMaxParticipants(),
+	})
 }
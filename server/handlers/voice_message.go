@@ -1,12 +1,12 @@
-// Package handlers — VoiceMessageHandler: ephemeral voice channel chat.
-// Endpoints are membership-gated by VoiceMessageService (must be in the
-// target voice channel). Attachments use the existing UploadPipeline with
-// KindVoiceMsg so the channel-scoped directory can be wiped on cleanup.
+// Package handlers — VoiceMessageHandler: voice channel text chat.
+// Writes are membership-gated by VoiceMessageService (must be in the target
+// voice channel); reads also admit users with PermViewChannel on it.
+// Attachments use the existing UploadPipeline with KindVoiceMsg so the
+// channel-scoped directory can be wiped when the channel is deleted.
 package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 
 	"github.com/akinalp/mqvi/models"
@@ -69,12 +69,7 @@ func (h *VoiceMessageHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if h.messageLimiter != nil && !h.messageLimiter.Allow(user.ID) {
-		retryAfter := h.messageLimiter.CooldownSeconds(user.ID)
-		w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
-		pkg.ErrorWithMessage(w, http.StatusTooManyRequests,
-			fmt.Sprintf("too many messages, please wait %s",
-				ratelimit.FormatRetryMessage(retryAfter)))
+	if userRateLimited(w, h.messageLimiter, user.ID, "messages") {
 		return
 	}
 
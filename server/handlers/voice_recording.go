@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/services"
+)
+
+// RecordingHandler starts and stops voice channel recordings. Both routes are
+// gated by PermRecordVoice in the router; everyone else only ever sees the
+// OpVoiceRecordingState broadcasts.
+type RecordingHandler struct {
+	recordingService services.RecordingService
+}
+
+func NewRecordingHandler(recordingService services.RecordingService) *RecordingHandler {
+	return &RecordingHandler{recordingService: recordingService}
+}
+
+// Start handles POST /api/servers/{serverId}/channels/{id}/recording/start
+func (h *RecordingHandler) Start(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	if err := h.recordingService.Start(r.Context(), channelID, user.ID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "recording started"})
+}
+
+// Stop handles POST /api/servers/{serverId}/channels/{id}/recording/stop
+func (h *RecordingHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	channelID := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "user not found in context")
+		return
+	}
+
+	recording, err := h.recordingService.Stop(r.Context(), channelID, user.ID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, recording)
+}
@@ -15,6 +15,7 @@ import (
 // data; the token methods are unused in this test.
 type stubVoiceService struct {
 	all []models.VoiceState
+	cap int
 }
 
 func (s *stubVoiceService) GenerateToken(_ context.Context, _, _, _, _ string) (*models.VoiceTokenResponse, error) {
@@ -24,6 +25,7 @@ func (s *stubVoiceService) GenerateScreenShareToken(_ context.Context, _, _, _,
 	return nil, nil
 }
 func (s *stubVoiceService) GetAllVoiceStates() []models.VoiceState { return s.all }
+func (s *stubVoiceService) InstanceMaxParticipants() int           { return s.cap }
 
 // passthroughSigner is a FileURLSigner that returns its input unchanged so
 // tests assert on the path, not on signature artifacts.
@@ -39,7 +41,7 @@ func TestVoiceStates_FiltersByServerID(t *testing.T) {
 		{UserID: "u3", ServerID: "server-a", ChannelID: "c3"},
 		{UserID: "u4", ServerID: "server-c", ChannelID: "c4"},
 	}
-	h := &VoiceHandler{voiceService: &stubVoiceService{all: all}, urlSigner: passthroughSigner{}}
+	h := &VoiceHandler{voiceService: &stubVoiceService{all: all, cap: 25}, urlSigner: passthroughSigner{}}
 
 	req := httptest.NewRequest(http.MethodGet, "/api/servers/server-a/voice/states", nil)
 	req.SetPathValue("serverId", "server-a")
@@ -50,13 +52,13 @@ func TestVoiceStates_FiltersByServerID(t *testing.T) {
 		t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
 	}
 	var env struct {
-		Success bool                 `json:"success"`
-		Data    []models.VoiceState  `json:"data"`
+		Success bool                       `json:"success"`
+		Data    models.VoiceStatesResponse `json:"data"`
 	}
 	if err := json.NewDecoder(rr.Body).Decode(&env); err != nil {
 		t.Fatalf("decode response: %v", err)
 	}
-	got := env.Data
+	got := env.Data.States
 	if len(got) != 2 {
 		t.Fatalf("expected 2 states (server-a only), got %d: %+v", len(got), got)
 	}
@@ -65,6 +67,9 @@ func TestVoiceStates_FiltersByServerID(t *testing.T) {
 			t.Errorf("leak: state for server %q returned in server-a query", st.ServerID)
 		}
 	}
+	if env.Data.MaxParticipants != 25 {
+		t.Errorf("expected instance cap 25 in payload, got %d", env.Data.MaxParticipants)
+	}
 }
 
 func TestVoiceStates_RejectsMissingServerID(t *testing.T) {
@@ -95,13 +100,13 @@ func TestVoiceStates_EmptyServerReturnsEmpty(t *testing.T) {
 		t.Fatalf("expected 200, got %d", rr.Code)
 	}
 	var env struct {
-		Success bool                 `json:"success"`
-		Data    []models.VoiceState  `json:"data"`
+		Success bool                       `json:"success"`
+		Data    models.VoiceStatesResponse `json:"data"`
 	}
 	if err := json.NewDecoder(rr.Body).Decode(&env); err != nil {
 		t.Fatalf("decode response: %v", err)
 	}
-	got := env.Data
+	got := env.Data.States
 	if len(got) != 0 {
 		t.Fatalf("expected empty slice for server with no voice states, got %+v", got)
 	}
@@ -142,13 +147,13 @@ func TestVoiceStates_SignsAvatarOnEgress(t *testing.T) {
 		t.Fatalf("expected SignURL called once with raw avatar path, got %v", signer.signed)
 	}
 	var env struct {
-		Success bool                 `json:"success"`
-		Data    []models.VoiceState  `json:"data"`
+		Success bool                       `json:"success"`
+		Data    models.VoiceStatesResponse `json:"data"`
 	}
 	if err := json.NewDecoder(rr.Body).Decode(&env); err != nil {
 		t.Fatalf("decode response: %v", err)
 	}
-	got := env.Data
+	got := env.Data.States
 	if len(got) != 1 || got[0].AvatarURL != "/api/files/avatars/u1/a.png?signed=1" {
 		t.Fatalf("expected avatar to carry signer output, got %+v", got)
 	}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/pkg/ratelimit"
+	"github.com/akinalp/mqvi/services"
+)
+
+// WebhookHandler manages channel webhooks and serves their public execute
+// endpoint.
+type WebhookHandler struct {
+	webhookService services.WebhookService
+	// executeLimiter throttles the public endpoint per webhook ID — keyed
+	// before the token check, so brute-forcing a token burns the same budget
+	// as posting.
+	executeLimiter *ratelimit.MessageRateLimiter
+}
+
+func NewWebhookHandler(webhookService services.WebhookService, executeLimiter *ratelimit.MessageRateLimiter) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		executeLimiter: executeLimiter,
+	}
+}
+
+// Create handles POST /api/servers/{serverId}/channels/{id}/webhooks
+// (requires MANAGE_WEBHOOKS).
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	channelID := r.PathValue("id")
+
+	user, ok := r.Context().Value(UserContextKey).(*models.User)
+	if !ok {
+		pkg.ErrorWithMessage(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	webhook, err := h.webhookService.Create(r.Context(), serverID, channelID, user.ID, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, webhook)
+}
+
+// List handles GET /api/servers/{serverId}/webhooks (requires MANAGE_WEBHOOKS).
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+
+	webhooks, err := h.webhookService.ListByServer(r.Context(), serverID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, webhooks)
+}
+
+// Delete handles DELETE /api/servers/{serverId}/webhooks/{id}
+// (requires MANAGE_WEBHOOKS).
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	webhookID := r.PathValue("id")
+
+	if err := h.webhookService.Delete(r.Context(), serverID, webhookID); err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, map[string]string{"message": "webhook deleted"})
+}
+
+// Regenerate handles POST /api/servers/{serverId}/webhooks/{id}/regenerate
+// (requires MANAGE_WEBHOOKS) — mints a fresh token, invalidating the old one.
+func (h *WebhookHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverId")
+	webhookID := r.PathValue("id")
+
+	webhook, err := h.webhookService.RegenerateToken(r.Context(), serverID, webhookID)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusOK, webhook)
+}
+
+// Execute handles POST /api/webhooks/{id}/{token} — the public, unauthenticated
+// posting endpoint integrations call.
+func (h *WebhookHandler) Execute(w http.ResponseWriter, r *http.Request) {
+	webhookID := r.PathValue("id")
+	token := r.PathValue("token")
+
+	if userRateLimited(w, h.executeLimiter, webhookID, "webhook messages") {
+		return
+	}
+
+	var req models.ExecuteWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pkg.ErrorWithMessage(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	message, err := h.webhookService.Execute(r.Context(), webhookID, token, &req)
+	if err != nil {
+		pkg.Error(w, err)
+		return
+	}
+
+	pkg.JSON(w, http.StatusCreated, message)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/services"
 	"github.com/akinalp/mqvi/ws"
 )
@@ -26,12 +28,14 @@ type healthReport struct {
 	DB         dbHealth            `json:"db"`
 	WS         wsHealth            `json:"ws"`
 	Push       *services.PushStats `json:"push,omitempty"`
+	LiveKit    *livekitHealth      `json:"livekit,omitempty"`
 	Goroutines int                 `json:"goroutines"`
 }
 
 type dbHealth struct {
 	Reachable bool   `json:"reachable"`
 	LatencyMS int64  `json:"latency_ms"`
+	Migration string `json:"migration,omitempty"` // latest applied migration file
 	InUse     int    `json:"in_use"`
 	Idle      int    `json:"idle"`
 	WaitCount int64  `json:"wait_count"`
@@ -44,13 +48,29 @@ type wsHealth struct {
 	Users       int `json:"users"`
 }
 
-// registerLiveness adds the public liveness endpoint. Static and cheap: it is what a supervisor
-// or an uptime monitor polls, and it must not touch the database.
+// livekitHealth probes each platform instance's Prometheus /metrics endpoint.
+// Unreachable lists instance IDs so the operator knows which box to look at.
+type livekitHealth struct {
+	Instances   int      `json:"instances"`
+	Reachable   int      `json:"reachable"`
+	Unreachable []string `json:"unreachable,omitempty"`
+}
+
+// platformInstanceLister is the readiness probe's view of the LiveKit repository.
+type platformInstanceLister interface {
+	ListPlatformInstances(ctx context.Context) ([]models.LiveKitInstance, error)
+}
+
+// registerLiveness adds the public liveness endpoints. Static and cheap: they are what a
+// supervisor or an uptime monitor polls, and they must not touch the database. /api/health/live
+// is the same probe under the name Kubernetes conventions expect.
 func registerLiveness(mux *http.ServeMux) {
-	mux.HandleFunc("GET /api/health", func(w http.ResponseWriter, r *http.Request) {
+	live := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok", "service": "mqvi"})
-	})
+	}
+	mux.HandleFunc("GET /api/health", live)
+	mux.HandleFunc("GET /api/health/live", live)
 }
 
 // startReadinessServer serves /health/ready on its own loopback listener.
@@ -63,9 +83,9 @@ func registerLiveness(mux *http.ServeMux) {
 //
 // Returns a shutdown func. A bind failure is logged and degrades to no readiness endpoint rather
 // than taking the server down — the app serves fine without it.
-func startReadinessServer(addr string, db *sql.DB, hub *ws.Hub, push services.PushNotifier) func(context.Context) error {
+func startReadinessServer(addr string, db *sql.DB, hub *ws.Hub, push services.PushNotifier, livekit platformInstanceLister) func(context.Context) error {
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /health/ready", readinessHandler(db, hub, push))
+	mux.HandleFunc("GET /health/ready", readinessHandler(db, hub, push, livekit))
 
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -82,7 +102,13 @@ func startReadinessServer(addr string, db *sql.DB, hub *ws.Hub, push services.Pu
 	return srv.Shutdown
 }
 
-func readinessHandler(db *sql.DB, hub *ws.Hub, push services.PushNotifier) http.HandlerFunc {
+func readinessHandler(db *sql.DB, hub *ws.Hub, push services.PushNotifier, livekit platformInstanceLister) http.HandlerFunc {
+	// TLS skip for the same reason as the admin metrics fetch: internal
+	// backend->LiveKit traffic often runs on self-signed certificates.
+	metricsClient := &http.Client{
+		Timeout:   2 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 		defer cancel()
@@ -101,6 +127,13 @@ func readinessHandler(db *sql.DB, hub *ws.Hub, push services.PushNotifier) http.
 			report.Status = "degraded"
 		}
 
+		// Latest applied migration — lets an operator confirm a deploy actually
+		// migrated before traffic is shifted. Best-effort: a failure here is
+		// already covered by the round trip above.
+		_ = db.QueryRowContext(ctx,
+			"SELECT filename FROM schema_migrations ORDER BY filename DESC LIMIT 1",
+		).Scan(&report.DB.Migration)
+
 		stats := db.Stats()
 		report.DB.InUse = stats.InUse
 		report.DB.Idle = stats.Idle
@@ -119,6 +152,11 @@ func readinessHandler(db *sql.DB, hub *ws.Hub, push services.PushNotifier) http.
 			s := p.Stats()
 			report.Push = &s
 		}
+		// Same stance for LiveKit: voice degrades, text serving does not, so an
+		// unreachable instance is reported without flipping readiness.
+		if livekit != nil {
+			report.LiveKit = probeLiveKitInstances(ctx, livekit, metricsClient)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		if report.Status != "ok" {
@@ -127,3 +165,32 @@ func readinessHandler(db *sql.DB, hub *ws.Hub, push services.PushNotifier) http.
 		_ = json.NewEncoder(w).Encode(report)
 	}
 }
+
+// probeLiveKitInstances hits each platform instance's /metrics endpoint. A 200
+// is reachable; anything else (including a listing failure) is not.
+func probeLiveKitInstances(ctx context.Context, livekit platformInstanceLister, client *http.Client) *livekitHealth {
+	lk := &livekitHealth{}
+	instances, err := livekit.ListPlatformInstances(ctx)
+	if err != nil {
+		return lk
+	}
+	lk.Instances = len(instances)
+	for _, inst := range instances {
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, services.LiveKitURLToMetrics(inst.URL), nil)
+		if reqErr != nil {
+			lk.Unreachable = append(lk.Unreachable, inst.ID)
+			continue
+		}
+		resp, httpErr := client.Do(req)
+		if httpErr != nil || resp.StatusCode != http.StatusOK {
+			if httpErr == nil {
+				resp.Body.Close()
+			}
+			lk.Unreachable = append(lk.Unreachable, inst.ID)
+			continue
+		}
+		resp.Body.Close()
+		lk.Reachable++
+	}
+	return lk
+}
@@ -21,9 +21,22 @@ func registerHubCallbacks(
 	channelRepo repository.ChannelRepository,
 	serverRepo repository.ServerRepository,
 	channelPermResolver services.ChannelPermResolver,
+	serverService services.ServerService,
+	presenceAudience services.PresenceAudienceService,
 ) {
 	// ─── Presence Callbacks ───
 
+	// Presence goes to the subject's audience (shared-server members + friends),
+	// not to every connection — online status is a relationship, not a broadcast.
+	broadcastPresence := func(userID string, data ws.PresenceData) {
+		recipients, err := presenceAudience.Recipients(context.Background(), userID)
+		if err != nil {
+			log.Printf("[presence] failed to resolve audience for user %s: %v", userID, err)
+			return
+		}
+		hub.BroadcastToUsers(recipients, ws.Event{Op: ws.OpPresence, Data: data})
+	}
+
 	hub.OnUserFirstConnect(func(userID, _ string) {
 		// Read persistent pref_status from DB (not client-provided — client may differ per device).
 		user, err := userRepo.GetByID(context.Background(), userID)
@@ -39,12 +52,9 @@ func registerHubCallbacks(
 
 		if targetStatus == models.UserStatusOffline {
 			// Invisible — SetInvisible already called in handler.
-			hub.BroadcastToAll(ws.Event{
-				Op: ws.OpPresence,
-				Data: ws.PresenceData{
-					UserID: userID,
-					Status: string(models.UserStatusOffline),
-				},
+			broadcastPresence(userID, ws.PresenceData{
+				UserID: userID,
+				Status: string(models.UserStatusOffline),
 			})
 			log.Printf("[presence] user %s connected as invisible (pref_status=offline)", userID)
 			return
@@ -54,12 +64,10 @@ func registerHubCallbacks(
 			log.Printf("[presence] failed to update status for user %s: %v", userID, updateErr)
 		}
 
-		hub.BroadcastToAll(ws.Event{
-			Op: ws.OpPresence,
-			Data: ws.PresenceData{
-				UserID: userID,
-				Status: string(targetStatus),
-			},
+		broadcastPresence(userID, ws.PresenceData{
+			UserID:         userID,
+			Status:         string(targetStatus),
+			ClientPlatform: hub.BestClientPlatform(userID),
 		})
 		log.Printf("[presence] user %s connected with status %s (from pref_status)", userID, targetStatus)
 	})
@@ -71,15 +79,22 @@ func registerHubCallbacks(
 
 		hub.SetInvisible(userID, false)
 
-		hub.BroadcastToAll(ws.Event{
-			Op: ws.OpPresence,
-			Data: ws.PresenceData{
-				UserID: userID,
-				Status: string(models.UserStatusOffline),
-			},
+		broadcastPresence(userID, ws.PresenceData{
+			UserID: userID,
+			Status: string(models.UserStatusOffline),
 		})
 		log.Printf("[presence] user %s disconnected (DB set to offline)", userID)
 
+		// Temporary-invite members last only as long as their session: the last
+		// disconnect drops every membership that never earned a non-default role.
+		if err := serverService.RemoveTemporaryMemberships(context.Background(), userID); err != nil {
+			log.Printf("[server] temporary membership cleanup failed for user %s: %v", userID, err)
+		}
+
+		// Group call media is SFU-side, but with no connection left there is no
+		// one to ring or to hand a token to — drop them from any group call.
+		p2pCallService.LeaveAllGroupCalls(userID)
+
 		// Voice state is NOT cleaned here — WS disconnect != voice leave.
 		// LiveKit connection is separate; WS may reconnect shortly.
 		// Cleaned by explicit voice_leave or orphan cleanup sweep.
@@ -113,12 +128,17 @@ func registerHubCallbacks(
 
 		hub.SetInvisible(userID, status == string(models.UserStatusOffline))
 
-		hub.BroadcastToAll(ws.Event{
-			Op: ws.OpPresence,
-			Data: ws.PresenceData{
-				UserID: userID,
-				Status: status,
-			},
+		// No platform on "offline" — an invisible user's device type is exactly
+		// the kind of "still around" signal invisibility is meant to hide.
+		clientPlatform := ""
+		if status != string(models.UserStatusOffline) {
+			clientPlatform = hub.BestClientPlatform(userID)
+		}
+
+		broadcastPresence(userID, ws.PresenceData{
+			UserID:         userID,
+			Status:         status,
+			ClientPlatform: clientPlatform,
 		})
 
 		source := "manual"
@@ -130,8 +150,8 @@ func registerHubCallbacks(
 
 	// ─── Voice Callbacks ───
 
-	hub.OnVoiceJoin(func(userID, username, displayName, avatarURL, channelID string, isMuted, isDeafened bool) {
-		if err := voiceService.JoinChannel(userID, username, displayName, avatarURL, channelID, isMuted, isDeafened); err != nil {
+	hub.OnVoiceJoin(func(userID, username, displayName, avatarURL, clientPlatform, channelID string, isMuted, isDeafened bool) {
+		if err := voiceService.JoinChannel(userID, username, displayName, avatarURL, clientPlatform, channelID, isMuted, isDeafened); err != nil {
 			log.Printf("[voice] join error user=%s channel=%s: %v", userID, channelID, err)
 			return
 		}
@@ -166,6 +186,11 @@ func registerHubCallbacks(
 			log.Printf("[voice] admin state update error admin=%s target=%s: %v", adminUserID, targetUserID, err)
 		}
 	})
+	hub.OnVoicePrioritySpeaker(func(userID string, isPriority bool) {
+		if err := voiceService.SetPrioritySpeaker(context.Background(), userID, isPriority); err != nil {
+			log.Printf("[voice] priority speaker toggle error user=%s: %v", userID, err)
+		}
+	})
 	hub.OnVoiceMoveUser(func(moverUserID, targetUserID, targetChannelID string) {
 		if err := voiceService.MoveUser(context.Background(), moverUserID, targetUserID, targetChannelID); err != nil {
 			log.Printf("[voice] move user error mover=%s target=%s channel=%s: %v", moverUserID, targetUserID, targetChannelID, err)
@@ -217,6 +242,30 @@ func registerHubCallbacks(
 		}
 	})
 
+	// ─── Group Call Callbacks ───
+
+	hub.OnGroupCallInitiate(func(callerID, sessionID string, data ws.GroupCallInitiateData) {
+		callType := models.P2PCallType(data.CallType)
+		if err := p2pCallService.InitiateGroupCall(callerID, sessionID, data.ReceiverIDs, callType); err != nil {
+			log.Printf("[group-call] initiate error caller=%s: %v", callerID, err)
+		}
+	})
+	hub.OnGroupCallAccept(func(userID, callID string) {
+		if err := p2pCallService.AcceptGroupCall(userID, callID); err != nil {
+			log.Printf("[group-call] accept error user=%s call=%s: %v", userID, callID, err)
+		}
+	})
+	hub.OnGroupCallDecline(func(userID, callID string) {
+		if err := p2pCallService.DeclineGroupCall(userID, callID); err != nil {
+			log.Printf("[group-call] decline error user=%s call=%s: %v", userID, callID, err)
+		}
+	})
+	hub.OnGroupCallLeave(func(userID, callID string) {
+		if err := p2pCallService.LeaveGroupCall(userID, callID); err != nil {
+			log.Printf("[group-call] leave error user=%s call=%s: %v", userID, callID, err)
+		}
+	})
+
 	// ─── Channel Typing Callback ───
 	// Validates sender has ReadMessages permission, then broadcasts to server members only.
 
@@ -244,7 +293,13 @@ func registerHubCallbacks(
 			}
 		}
 
-		hub.BroadcastToUsers(recipients, ws.Event{
+		// Track the typer so the hub can emit the matching OpTypingStop —
+		// after 8s idle, or immediately when this user's message lands.
+		hub.TrackTyping(channelID, senderUserID, recipients)
+
+		// View-scoped: a typing bubble only renders in the open channel, so
+		// connections subscribed to a different view are skipped entirely.
+		hub.BroadcastToUsersInChannel(recipients, channelID, ws.Event{
 			Op: ws.OpTypingStart,
 			Data: ws.TypingStartData{
 				UserID:    senderUserID,
@@ -5,6 +5,8 @@ import (
 
 	"github.com/akinalp/mqvi/config"
 	"github.com/akinalp/mqvi/handlers"
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg/crypto"
 	"github.com/akinalp/mqvi/pkg/files"
 	"github.com/akinalp/mqvi/services"
 	"github.com/akinalp/mqvi/ws"
@@ -22,6 +24,7 @@ type Handlers struct {
 	Server            *handlers.ServerHandler
 	Invite            *handlers.InviteHandler
 	Pin               *handlers.PinHandler
+	ChannelFollow     *handlers.ChannelFollowHandler
 	Search            *handlers.SearchHandler
 	ReadState         *handlers.ReadStateHandler
 	DM                *handlers.DMHandler
@@ -33,13 +36,18 @@ type Handlers struct {
 	Admin             *handlers.AdminHandler
 	ServerMute        *handlers.ServerMuteHandler
 	ChannelMute       *handlers.ChannelMuteHandler
+	CategoryMute      *handlers.CategoryMuteHandler
+	NotifSetting      *handlers.NotificationSettingHandler
+	KeywordAlert      *handlers.KeywordAlertHandler
 	DMSettings        *handlers.DMSettingsHandler
 	Block             *handlers.BlockHandler
+	Presence          *handlers.PresenceHandler
 	Report            *handlers.ReportHandler
 	Gif               *handlers.GifHandler
 	Device            *handlers.DeviceHandler
 	E2EE              *handlers.E2EEHandler
 	LinkPreview       *handlers.LinkPreviewHandler
+	Export            *handlers.ExportHandler
 	Badge             *handlers.BadgeHandler
 	Preferences       *handlers.PreferencesHandler
 	DownloadPrompt    *handlers.DownloadPromptHandler
@@ -53,36 +61,50 @@ type Handlers struct {
 	Discovery         *handlers.DiscoveryHandler
 	AssetLinks        *handlers.AssetLinksHandler
 	AASA              *handlers.AASAHandler
+	AuditLog          *handlers.AuditLogHandler
+	ScheduledMessage  *handlers.ScheduledMessageHandler
+	Webhook           *handlers.WebhookHandler
+	Recording         *handlers.RecordingHandler
 	WS                *ws.Handler
 }
 
-func initHandlers(svcs *Services, repos *Repositories, limiters *RateLimiters, hub *ws.Hub, cfg *config.Config, encryptionKey []byte, urlSigner services.FileURLSigner) *Handlers {
-	fileLocator := files.NewLocator(cfg.Upload.Dir, cfg.Upload.PublicURL)
+func initHandlers(svcs *Services, repos *Repositories, limiters *RateLimiters, hub *ws.Hub, cfg *config.Config, keychain *crypto.Keychain, urlSigner services.FileURLSigner) *Handlers {
+	fileLocator := files.NewLocator(cfg.Upload.Dir, cfg.Upload.PublicURL, cfg.Upload.DirMode)
+	imageLimits := files.ImageLimits{
+		MaxWidth:  cfg.Upload.MaxImageWidth,
+		MaxHeight: cfg.Upload.MaxImageHeight,
+		MaxPixels: cfg.Upload.MaxImagePixels,
+	}
 	h := &Handlers{
 		Auth:              handlers.NewAuthHandler(svcs.Auth, limiters.Login, limiters.Register, limiters.ForgotPwd, limiters.ResetPwd, urlSigner, time.Duration(cfg.JWT.RefreshTokenExpiry)*24*time.Hour),
 		Channel:           handlers.NewChannelHandler(svcs.Channel),
 		Category:          handlers.NewCategoryHandler(svcs.Category),
-		Message:           handlers.NewMessageHandler(svcs.Message, svcs.Upload, svcs.Storage, cfg.Upload.MaxSize, limiters.Message, urlSigner),
+		Message:           handlers.NewMessageHandler(svcs.Message, svcs.Upload, svcs.Storage, svcs.Server, cfg.Upload.MaxSize, limiters.Message, urlSigner, svcs.Idempotency),
 		Member:            handlers.NewMemberHandler(svcs.Member),
 		Role:              handlers.NewRoleHandler(svcs.Role),
 		Voice:             handlers.NewVoiceHandler(svcs.Voice, urlSigner),
-		Server:            handlers.NewServerHandler(svcs.Server),
+		Server:            handlers.NewServerHandler(svcs.Server, limiters.ServerCreate, svcs.AppLog),
 		Discovery:         handlers.NewDiscoveryHandler(svcs.Discovery, svcs.Server, svcs.Report, svcs.ServerReportUpload, svcs.Storage, urlSigner, cfg.Upload.MaxSize, limiters.Discovery),
 		Invite:            handlers.NewInviteHandler(svcs.Invite),
 		Pin:               handlers.NewPinHandler(svcs.Pin),
+		ChannelFollow:     handlers.NewChannelFollowHandler(svcs.ChannelFollow),
 		Search:            handlers.NewSearchHandler(svcs.Search),
 		ReadState:         handlers.NewReadStateHandler(svcs.ReadState, limiters.ChannelRead),
-		DM:                handlers.NewDMHandler(svcs.DM, svcs.DMUpload, svcs.Storage, cfg.Upload.MaxSize, limiters.Message, limiters.DMRead, urlSigner),
-		Reaction:          handlers.NewReactionHandler(svcs.Reaction),
+		DM:                handlers.NewDMHandler(svcs.DM, svcs.DMUpload, svcs.Storage, cfg.Upload.MaxSize, limiters.Message, limiters.DMRead, urlSigner, svcs.Idempotency),
+		Reaction:          handlers.NewReactionHandler(svcs.Reaction, limiters.Reaction),
 		ChannelPermission: handlers.NewChannelPermissionHandler(svcs.ChannelPermission),
 		Friendship:        handlers.NewFriendshipHandler(svcs.Friendship),
-		Avatar:            handlers.NewAvatarHandler(repos.User, svcs.Member, svcs.Server, fileLocator, svcs.UploadPipeline, urlSigner),
-		Stats:             handlers.NewStatsHandler(repos.User),
-		Admin:             handlers.NewAdminHandler(svcs.LiveKitAdmin, svcs.MetricsHistory, svcs.AdminUser, svcs.AdminServer, svcs.Report, svcs.AppLog, svcs.SettingsBadge, svcs.Voice),
+		Avatar:            handlers.NewAvatarHandler(repos.User, svcs.Member, svcs.Server, fileLocator, svcs.UploadPipeline, urlSigner, imageLimits),
+		Stats:             handlers.NewStatsHandler(repos.User, repos.Server, repos.Message, hub, cfg.StatsCacheTTL),
+		Admin:             handlers.NewAdminHandler(svcs.LiveKitAdmin, svcs.MetricsHistory, svcs.AdminUser, svcs.AdminServer, svcs.Report, svcs.AppLog, svcs.SettingsBadge, svcs.Voice, instanceLimits(cfg)),
 		ServerMute:        handlers.NewServerMuteHandler(svcs.ServerMute),
 		ChannelMute:       handlers.NewChannelMuteHandler(svcs.ChannelMute),
+		CategoryMute:      handlers.NewCategoryMuteHandler(svcs.Notification),
+		NotifSetting:      handlers.NewNotificationSettingHandler(svcs.Notification),
+		KeywordAlert:      handlers.NewKeywordAlertHandler(svcs.KeywordAlert),
 		DMSettings:        handlers.NewDMSettingsHandler(svcs.DMSettings),
 		Block:             handlers.NewBlockHandler(svcs.Block),
+		Presence:          handlers.NewPresenceHandler(svcs.PresenceAudience),
 		Report:            handlers.NewReportHandler(svcs.Report, svcs.ReportUpload, svcs.Storage, cfg.Upload.MaxSize, urlSigner),
 		Gif:               handlers.NewGifHandler(cfg.Klipy.APIKey),
 		AssetLinks:        handlers.NewAssetLinksHandler(cfg.AppLinks.AndroidPackage, cfg.AppLinks.AndroidFingerprints),
@@ -90,19 +112,52 @@ func initHandlers(svcs *Services, repos *Repositories, limiters *RateLimiters, h
 		Device:            handlers.NewDeviceHandler(svcs.Device),
 		E2EE:              handlers.NewE2EEHandler(svcs.E2EE),
 		LinkPreview:       handlers.NewLinkPreviewHandler(svcs.LinkPreview),
+		Export:            handlers.NewExportHandler(svcs.Export),
 		Badge:             handlers.NewBadgeHandler(svcs.Badge, svcs.UploadPipeline),
 		Preferences:       handlers.NewPreferencesHandler(svcs.Preferences),
 		DownloadPrompt:    handlers.NewDownloadPromptHandler(repos.User),
 		Feedback:          handlers.NewFeedbackHandler(svcs.Feedback, svcs.FeedbackUpload, svcs.Storage, svcs.SettingsBadge, cfg.Upload.MaxSize, limiters.Feedback, svcs.AppLog, urlSigner),
 		Soundboard:        handlers.NewSoundboardHandler(svcs.Soundboard, svcs.Storage, cfg.Upload.MaxSize, urlSigner),
 		Storage:           handlers.NewStorageHandler(svcs.Storage),
-		LiveKitWebhook:    handlers.NewLiveKitWebhookHandler(repos.LiveKit, encryptionKey, svcs.AppLog),
+		LiveKitWebhook:    handlers.NewLiveKitWebhookHandler(repos.LiveKit, keychain, svcs.AppLog),
 		VoiceMessage:      handlers.NewVoiceMessageHandler(svcs.VoiceMessage, svcs.UploadPipeline, urlSigner, limiters.Message, cfg.Upload.MaxSize),
 		ICEServer:         handlers.NewICEServerHandler(svcs.TURN, svcs.P2PCall, limiters.ICE),
 		PushToken:         handlers.NewPushTokenHandler(svcs.PushToken),
-		WS:                ws.NewHandler(hub, svcs.Auth, nil, svcs.Voice, repos.User, repos.Server, svcs.ServerMute, svcs.ChannelMute, urlSigner),
+		AuditLog:          handlers.NewAuditLogHandler(svcs.AuditLog),
+		ScheduledMessage:  handlers.NewScheduledMessageHandler(svcs.Scheduler),
+		Webhook:           handlers.NewWebhookHandler(svcs.Webhook, limiters.Webhook),
+		Recording:         handlers.NewRecordingHandler(svcs.Recording),
+		WS:                ws.NewHandler(hub, svcs.Auth, nil, svcs.Voice, repos.User, repos.Server, svcs.ServerMute, svcs.ChannelMute, svcs.Notification, urlSigner, svcs.Friendship, svcs.DM),
 	}
 	// Re-deliver a ringing incoming call to a receiver on (re)connect (offline/push tap).
 	h.WS.SetIncomingCallProvider(svcs.P2PCall)
+	// Ship the granular notification settings in the ready event.
+	h.WS.SetNotificationSettingsProvider(svcs.Notification)
+	// Scope the ready event's online list to shared-server members + friends.
+	h.WS.SetPresencePeerProvider(svcs.PresenceAudience)
 	return h
 }
+
+// instanceLimits assembles the read-only limits snapshot for GET /api/admin/limits
+// from the loaded config plus the compiled-in caps that never reached config.
+func instanceLimits(cfg *config.Config) *models.InstanceLimits {
+	return &models.InstanceLimits{
+		MaxMqviHostedServersPerUser:   services.MaxMqviHostedServersPerUser,
+		ServerCreatePerHour:           cfg.ServerCreatePerHour,
+		MaxVoiceParticipants:          cfg.MaxVoiceParticipants,
+		MaxPinsPerChannel:             cfg.MaxPinsPerChannel,
+		MaxReplyChainDepth:            models.MaxReplyChainDepth,
+		UploadMaxSizeBytes:            cfg.Upload.MaxSize,
+		StorageQuotaBytes:             cfg.Upload.DefaultQuotaBytes,
+		FileRateLimitUserPerMin:       cfg.FileRateLimit.UserPerMin,
+		FileRateLimitIPPerMin:         cfg.FileRateLimit.IPPerMin,
+		MaxReactionsPerUserPerMessage: cfg.Reactions.MaxPerUserPerMessage,
+		MaxReactionEmojisPerMessage:   cfg.Reactions.MaxDistinctPerMessage,
+		MaxMessageLines:               cfg.MessageFormat.MaxLines,
+		MaxMessageBlankLines:          cfg.MessageFormat.MaxBlankLines,
+		VoiceBitrateMin:               cfg.VoiceBitrate.Min,
+		VoiceBitrateMaxHosted:         cfg.VoiceBitrate.HostedMax,
+		VoiceBitrateMaxSelfHosted:     cfg.VoiceBitrate.SelfHostedMax,
+		DefaultMemberPermissions:      cfg.DefaultMemberPermissions,
+	}
+}
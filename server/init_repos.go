@@ -31,12 +31,16 @@ type Repositories struct {
 	MetricsHistory    repository.MetricsHistoryRepository
 	ServerMute        repository.ServerMuteRepository
 	ChannelMute       repository.ChannelMuteRepository
+	CategoryMute      repository.CategoryMuteRepository
+	NotifSetting      repository.NotificationSettingRepository
 	DMSettings        repository.DMSettingsRepository
 	Report            repository.ReportRepository
 	Device            repository.DeviceRepository
 	E2EEBackup        repository.E2EEKeyBackupRepository
 	GroupSession      repository.GroupSessionRepository
 	LinkPreview       repository.LinkPreviewRepository
+	MessageEmbed      repository.MessageEmbedRepository
+	ExportJob         repository.ExportJobRepository
 	Badge             repository.BadgeRepository
 	Preferences       repository.PreferencesRepository
 	RoleMention       repository.RoleMentionRepository
@@ -47,10 +51,17 @@ type Repositories struct {
 	Cleanup           repository.CleanupRepository
 	ScanHashCache     repository.ScanHashCacheRepository
 	VoiceMessage      repository.VoiceMessageRepository
+	KeywordAlert      repository.KeywordAlertRepository
+	ChannelFollow     repository.ChannelFollowRepository
 	PushToken         repository.PushTokenRepository
 	JoinRequest       repository.JoinRequestRepository
 	Discovery         repository.DiscoveryRepository
 	ServerReport      repository.ServerReportRepository
+	AuditLog          repository.AuditLogRepository
+	ScheduledMessage  repository.ScheduledMessageRepository
+	ServerTemplate    repository.ServerTemplateRepository
+	Webhook           repository.WebhookRepository
+	VoiceRecording    repository.VoiceRecordingRepository
 }
 
 // initRepositories creates all repositories from the shared DB connection pool.
@@ -79,12 +90,16 @@ func initRepositories(conn *sql.DB) *Repositories {
 		MetricsHistory:    repository.NewSQLiteMetricsHistoryRepo(conn),
 		ServerMute:        repository.NewSQLiteServerMuteRepo(conn),
 		ChannelMute:       repository.NewSQLiteChannelMuteRepo(conn),
+		CategoryMute:      repository.NewSQLiteCategoryMuteRepo(conn),
+		NotifSetting:      repository.NewSQLiteNotificationSettingRepo(conn),
 		DMSettings:        repository.NewSQLiteDMSettingsRepo(conn),
 		Report:            repository.NewSQLiteReportRepo(conn),
 		Device:            repository.NewSQLiteDeviceRepo(conn),
 		E2EEBackup:        repository.NewSQLiteE2EEBackupRepo(conn),
 		GroupSession:      repository.NewSQLiteGroupSessionRepo(conn),
 		LinkPreview:       repository.NewSQLiteLinkPreviewRepo(conn),
+		MessageEmbed:      repository.NewSQLiteMessageEmbedRepo(conn),
+		ExportJob:         repository.NewSQLiteExportJobRepo(conn),
 		Badge:             repository.NewSQLiteBadgeRepo(conn),
 		Preferences:       repository.NewSQLitePreferencesRepo(conn),
 		RoleMention:       repository.NewSQLiteRoleMentionRepo(conn),
@@ -95,9 +110,16 @@ func initRepositories(conn *sql.DB) *Repositories {
 		Cleanup:           repository.NewSQLiteCleanupRepo(conn),
 		ScanHashCache:     repository.NewSQLiteScanHashCacheRepo(conn),
 		VoiceMessage:      repository.NewSQLiteVoiceMessageRepo(conn),
+		KeywordAlert:      repository.NewSQLiteKeywordAlertRepo(conn),
+		ChannelFollow:     repository.NewSQLiteChannelFollowRepo(conn),
 		PushToken:         repository.NewSQLitePushTokenRepo(conn),
 		JoinRequest:       repository.NewSQLiteJoinRequestRepo(conn),
 		Discovery:         repository.NewSQLiteDiscoveryRepo(conn),
 		ServerReport:      repository.NewSQLiteServerReportRepo(conn),
+		AuditLog:          repository.NewSQLiteAuditLogRepo(conn),
+		ScheduledMessage:  repository.NewSQLiteScheduledMessageRepo(conn),
+		ServerTemplate:    repository.NewSQLiteServerTemplateRepo(conn),
+		Webhook:           repository.NewSQLiteWebhookRepo(conn),
+		VoiceRecording:    repository.NewSQLiteVoiceRecordingRepo(conn),
 	}
 }
@@ -70,10 +70,12 @@ func initRoutes(
 	mux.HandleFunc("POST /api/auth/forgot-password", h.Auth.ForgotPassword)
 	mux.HandleFunc("POST /api/auth/reset-password", h.Auth.ResetPassword)
 	mux.HandleFunc("POST /api/auth/restore", h.Auth.RestoreAccount)
+	mux.HandleFunc("GET /api/auth/password-policy", h.Auth.PasswordPolicy)
 
 	// User
 	mux.Handle("GET /api/users/me", auth(h.Auth.Me))
 	mux.Handle("PATCH /api/users/me/profile", auth(h.Member.UpdateProfile))
+	mux.Handle("PUT /api/users/me/custom-status", auth(h.Member.UpdateCustomStatus))
 	mux.Handle("POST /api/users/me/password", auth(h.Auth.ChangePassword))
 	mux.Handle("PUT /api/users/me/email", auth(h.Auth.ChangeEmail))
 	mux.Handle("POST /api/users/me/avatar", auth(h.Avatar.UploadUserAvatar))
@@ -86,10 +88,17 @@ func initRoutes(
 	mux.Handle("DELETE /api/users/me", auth(h.Auth.SoftDeleteSelf))
 	mux.Handle("PATCH /api/users/me/preferences", auth(h.Preferences.Update))
 	mux.Handle("GET /api/users/me/storage", auth(h.Storage.GetUsage))
+	mux.Handle("POST /api/users/presence", auth(h.Presence.Query))
+	mux.Handle("GET /api/users/me/keyword-alerts", auth(h.KeywordAlert.List))
+	mux.Handle("POST /api/users/me/keyword-alerts", auth(h.KeywordAlert.Add))
+	mux.Handle("DELETE /api/users/me/keyword-alerts/{keyword}", auth(h.KeywordAlert.Remove))
+	mux.Handle("PUT /api/users/me/notifications/{scopeType}/{scopeId}", auth(h.NotifSetting.Update))
 
 	// Servers
 	mux.Handle("GET /api/servers", auth(h.Server.ListMyServers))
 	mux.Handle("POST /api/servers", auth(h.Server.CreateServer))
+	mux.Handle("POST /api/servers/from-template", auth(h.Server.CreateFromTemplate))
+	mux.Handle("GET /api/templates", auth(h.Server.ListTemplates))
 	mux.Handle("POST /api/servers/join", auth(h.Server.JoinServer))
 	mux.Handle("PATCH /api/servers/reorder", auth(h.Server.ReorderServers))
 
@@ -136,6 +145,11 @@ func initRoutes(
 	mux.Handle("GET /api/dms/settings", auth(h.DMSettings.GetSettings))
 	mux.Handle("GET /api/dms", auth(h.DM.ListChannels))
 	mux.Handle("POST /api/dms", auth(h.DM.CreateOrGetChannel))
+	mux.Handle("POST /api/dms/groups", auth(h.DM.CreateGroupChannel))
+
+	// Group DM membership
+	mux.Handle("POST /api/dms/channels/{channelId}/members", auth(h.DM.AddGroupMember))
+	mux.Handle("DELETE /api/dms/channels/{channelId}/members/{userId}", auth(h.DM.RemoveGroupMember))
 
 	// DM Settings — /api/dms/channels/ prefix avoids route ambiguity with /api/dms/{channelId}
 	mux.Handle("POST /api/dms/channels/{channelId}/hide", auth(h.DMSettings.HideDM))
@@ -146,15 +160,18 @@ func initRoutes(
 	mux.Handle("DELETE /api/dms/channels/{channelId}/mute", auth(h.DMSettings.UnmuteDM))
 
 	// DM Request accept/decline
+	mux.Handle("POST /api/dms/channels/{channelId}/create-server", auth(h.Server.CreateFromDM))
 	mux.Handle("POST /api/dms/channels/{channelId}/accept", auth(h.DM.AcceptRequest))
 	mux.Handle("POST /api/dms/channels/{channelId}/decline", auth(h.DM.DeclineRequest))
 
 	// DM Messages
 	mux.Handle("GET /api/dms/{channelId}/messages", auth(h.DM.GetMessages))
+	mux.Handle("GET /api/dms/{channelId}/messages/around/{messageId}", auth(h.DM.GetMessagesAround))
 	mux.Handle("POST /api/dms/{channelId}/messages", auth(h.DM.SendMessage))
 	mux.Handle("PATCH /api/dms/messages/{id}", auth(h.DM.EditMessage))
 	mux.Handle("DELETE /api/dms/messages/{id}", auth(h.DM.DeleteMessage))
 	mux.Handle("POST /api/dms/messages/{id}/reactions", auth(h.DM.ToggleReaction))
+	mux.Handle("GET /api/dms/messages/{id}/reactions", auth(h.DM.ListReactionUsers))
 	mux.Handle("POST /api/dms/messages/{id}/pin", auth(h.DM.PinMessage))
 	mux.Handle("DELETE /api/dms/messages/{id}/pin", auth(h.DM.UnpinMessage))
 	mux.Handle("GET /api/dms/{channelId}/pinned", auth(h.DM.GetPinnedMessages))
@@ -202,6 +219,7 @@ func initRoutes(
 
 	// Channel mutes — literal path before {serverId} wildcard
 	mux.Handle("GET /api/channels/mutes", auth(h.ChannelMute.ListMuted))
+	mux.Handle("GET /api/categories/mutes", auth(h.CategoryMute.ListMuted))
 
 	// Link Preview
 	mux.Handle("GET /api/link-preview", auth(h.LinkPreview.Get))
@@ -258,6 +276,7 @@ func initRoutes(
 
 	// Platform Admin — Badge indicators (new feedback / new reports)
 	mux.Handle("GET /api/admin/badges", authAdmin(h.Admin.GetBadges))
+	mux.Handle("GET /api/admin/limits", authAdmin(h.Admin.GetInstanceLimits))
 
 	// Platform Admin — Feedback
 	mux.Handle("GET /api/admin/feedback", authAdmin(h.Feedback.AdminListTickets))
@@ -295,13 +314,20 @@ func initRoutes(
 	// Server
 	mux.Handle("GET /api/servers/{serverId}", authServer(h.Server.GetServer))
 	mux.Handle("PATCH /api/servers/{serverId}", authServerPerm(models.PermAdmin, h.Server.UpdateServer))
+	mux.Handle("POST /api/servers/{serverId}/template", authServerPerm(models.PermAdmin, h.Server.SaveTemplate))
 	mux.Handle("DELETE /api/servers/{serverId}", authServer(h.Server.DeleteServer))
 	mux.Handle("POST /api/servers/{serverId}/restore", authServerNoMemberCheck(h.Server.RestoreServer))
 	mux.Handle("DELETE /api/servers/{serverId}/permanent", authServerNoMemberCheck(h.Server.HardDeleteServer))
 	mux.Handle("POST /api/servers/{serverId}/leave", authServer(h.Server.LeaveServer))
+	mux.Handle("POST /api/servers/{serverId}/transfer", authServer(h.Server.TransferOwnership))
 	mux.Handle("POST /api/servers/{serverId}/icon", authServerPerm(models.PermAdmin, h.Avatar.UploadServerIcon))
 	mux.Handle("POST /api/servers/{serverId}/banner", authServerPerm(models.PermAdmin, h.Avatar.UploadServerBanner))
 
+	// Server history export (owner-only — enforced in the service layer)
+	mux.Handle("POST /api/servers/{serverId}/export", authServer(h.Export.Start))
+	mux.Handle("GET /api/servers/{serverId}/export/{jobId}", authServer(h.Export.Status))
+	mux.Handle("GET /api/servers/{serverId}/export/{jobId}/download", authServer(h.Export.Download))
+
 	// Public server discovery (login required; not server-scoped)
 	mux.Handle("GET /api/discovery/servers", auth(h.Discovery.ListPublicServers))
 	mux.Handle("GET /api/discovery/servers/{id}", auth(h.Discovery.GetPublicServer))
@@ -309,6 +335,11 @@ func initRoutes(
 	mux.Handle("POST /api/discovery/servers/{id}/report", auth(h.Discovery.ReportServer))
 
 	// Server Mute
+	// Welcome screen / rules gate
+	mux.Handle("GET /api/servers/{serverId}/welcome", authServer(h.Server.GetWelcomeScreen))
+	mux.Handle("PUT /api/servers/{serverId}/welcome", authServerPerm(models.PermAdmin, h.Server.UpdateWelcomeScreen))
+	mux.Handle("POST /api/servers/{serverId}/accept-rules", authServer(h.Server.AcceptRules))
+
 	mux.Handle("POST /api/servers/{serverId}/mute", authServer(h.ServerMute.Mute))
 	mux.Handle("DELETE /api/servers/{serverId}/mute", authServer(h.ServerMute.Unmute))
 
@@ -316,6 +347,10 @@ func initRoutes(
 	mux.Handle("POST /api/servers/{serverId}/channels/{id}/mute", authServer(h.ChannelMute.Mute))
 	mux.Handle("DELETE /api/servers/{serverId}/channels/{id}/mute", authServer(h.ChannelMute.Unmute))
 
+	// Category mute (notification cascade: channel -> category -> server)
+	mux.Handle("POST /api/servers/{serverId}/categories/{id}/mute", authServer(h.CategoryMute.Mute))
+	mux.Handle("DELETE /api/servers/{serverId}/categories/{id}/mute", authServer(h.CategoryMute.Unmute))
+
 	// LiveKit settings
 	mux.Handle("GET /api/servers/{serverId}/livekit", authServerPerm(models.PermAdmin, h.Server.GetLiveKitSettings))
 
@@ -323,6 +358,7 @@ func initRoutes(
 	mux.Handle("GET /api/servers/{serverId}/channels", authServer(h.Channel.List))
 	mux.Handle("POST /api/servers/{serverId}/channels", authServerPerm(models.PermManageChannels, h.Channel.Create))
 	mux.Handle("PATCH /api/servers/{serverId}/channels/reorder", authServerPerm(models.PermManageChannels, h.Channel.Reorder))
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/clone", authServerPerm(models.PermManageChannels, h.Channel.Clone))
 	mux.Handle("PATCH /api/servers/{serverId}/channels/{id}", authServerPerm(models.PermManageChannels, h.Channel.Update))
 	mux.Handle("DELETE /api/servers/{serverId}/channels/{id}", authServerPerm(models.PermManageChannels, h.Channel.Delete))
 
@@ -335,19 +371,44 @@ func initRoutes(
 
 	// Messages
 	mux.Handle("GET /api/servers/{serverId}/channels/{id}/messages", authServer(h.Message.List))
-	mux.Handle("POST /api/servers/{serverId}/channels/{id}/messages", authServer(h.Message.Create))
+	mux.Handle("GET /api/servers/{serverId}/channels/{id}/messages/around/{messageId}", authServer(h.Message.Around))
+	mux.Handle("GET /api/servers/{serverId}/channels/{id}/stats", authServer(h.Message.Stats))
+	// PermLoad: the create and toggle handlers exempt ManageMessages holders
+	// from their rate limiters.
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/messages", authServerPermLoad(h.Message.Create))
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/messages/bulk-delete", authServerPerm(models.PermManageMessages, h.Message.BulkDelete))
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/messages/schedule", authServerPerm(models.PermSendMessages, h.ScheduledMessage.Create))
+	mux.Handle("GET /api/servers/{serverId}/channels/{id}/messages/scheduled", authServer(h.ScheduledMessage.List))
+	mux.Handle("DELETE /api/servers/{serverId}/channels/{id}/messages/scheduled/{messageId}", authServer(h.ScheduledMessage.Delete))
 	mux.Handle("PATCH /api/servers/{serverId}/messages/{id}", authServer(h.Message.Update))
 	mux.Handle("DELETE /api/servers/{serverId}/messages/{id}", authServerPermLoad(h.Message.Delete))
+	mux.Handle("GET /api/servers/{serverId}/messages/{id}/history", authServerPermLoad(h.Message.GetEditHistory))
+
+	// Webhooks — management behind PermManageWebhooks; execute is public, the
+	// URL token is the credential.
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/webhooks", authServerPerm(models.PermManageWebhooks, h.Webhook.Create))
+	mux.Handle("GET /api/servers/{serverId}/webhooks", authServerPerm(models.PermManageWebhooks, h.Webhook.List))
+	mux.Handle("DELETE /api/servers/{serverId}/webhooks/{id}", authServerPerm(models.PermManageWebhooks, h.Webhook.Delete))
+	mux.Handle("POST /api/servers/{serverId}/webhooks/{id}/regenerate", authServerPerm(models.PermManageWebhooks, h.Webhook.Regenerate))
+	mux.HandleFunc("POST /api/webhooks/{id}/{token}", h.Webhook.Execute)
 
 	// Reactions
-	mux.Handle("POST /api/servers/{serverId}/messages/{messageId}/reactions", authServer(h.Reaction.Toggle))
+	mux.Handle("POST /api/servers/{serverId}/messages/{messageId}/reactions", authServerPermLoad(h.Reaction.Toggle))
+	mux.Handle("GET /api/servers/{serverId}/messages/{messageId}/reactions", authServerPermLoad(h.Reaction.ListUsers))
 
 	// Pins
 	mux.Handle("GET /api/servers/{serverId}/channels/{id}/pins", authServer(h.Pin.ListPins))
 	mux.Handle("POST /api/servers/{serverId}/channels/{channelId}/messages/{messageId}/pin", authServerPerm(models.PermManageMessages, h.Pin.Pin))
 	mux.Handle("DELETE /api/servers/{serverId}/channels/{channelId}/messages/{messageId}/pin", authServerPerm(models.PermManageMessages, h.Pin.Unpin))
+	mux.Handle("PATCH /api/servers/{serverId}/channels/{id}/pins/reorder", authServerPerm(models.PermManageMessages, h.Pin.Reorder))
+
+	// Channel follows — cross-server announcement cross-posting
+	mux.Handle("GET /api/servers/{serverId}/channels/{channelId}/follows", authServerPerm(models.PermManageChannels, h.ChannelFollow.List))
+	mux.Handle("POST /api/servers/{serverId}/channels/{channelId}/follows", authServerPerm(models.PermManageChannels, h.ChannelFollow.Follow))
+	mux.Handle("DELETE /api/servers/{serverId}/channels/{channelId}/follows/{targetChannelId}", authServerPerm(models.PermManageChannels, h.ChannelFollow.Unfollow))
 
 	// Read State — literal "read-all" and "unread" before {id} wildcard
+	mux.Handle("GET /api/unreads", auth(h.ReadState.GetAllUnreads))
 	mux.Handle("POST /api/servers/{serverId}/channels/read-all", authServer(h.ReadState.MarkAllRead))
 	mux.Handle("GET /api/servers/{serverId}/channels/unread", authServer(h.ReadState.GetUnreads))
 	mux.Handle("POST /api/servers/{serverId}/channels/{id}/read/mentions", authServer(h.ReadState.MarkMentionSeen))
@@ -359,11 +420,17 @@ func initRoutes(
 	mux.Handle("PATCH /api/servers/{serverId}/members/{id}/roles", authServerPerm(models.PermManageRoles, h.Member.ModifyRoles))
 	mux.Handle("DELETE /api/servers/{serverId}/members/{id}", authServerPerm(models.PermKickMembers, h.Member.Kick))
 	mux.Handle("POST /api/servers/{serverId}/members/{id}/ban", authServerPerm(models.PermBanMembers, h.Member.Ban))
+	mux.Handle("PATCH /api/servers/{serverId}/members/{id}/timeout", authServerPerm(models.PermModerateMembers, h.Member.Timeout))
+	mux.Handle("PATCH /api/servers/{serverId}/members/me/nickname", authServer(h.Member.UpdateOwnNickname))
+	mux.Handle("PATCH /api/servers/{serverId}/members/{id}/nickname", authServerPerm(models.PermManageNicknames, h.Member.UpdateNickname))
 
 	// Bans
 	mux.Handle("GET /api/servers/{serverId}/bans", authServerPerm(models.PermBanMembers, h.Member.GetBans))
 	mux.Handle("DELETE /api/servers/{serverId}/bans/{id}", authServerPerm(models.PermBanMembers, h.Member.Unban))
 
+	// Audit log
+	mux.Handle("GET /api/servers/{serverId}/audit-logs", authServerPerm(models.PermViewAuditLog, h.AuditLog.List))
+
 	// Roles
 	mux.Handle("GET /api/servers/{serverId}/roles", authServer(h.Role.List))
 	mux.Handle("POST /api/servers/{serverId}/roles", authServerPerm(models.PermManageRoles, h.Role.Create))
@@ -373,13 +440,14 @@ func initRoutes(
 
 	// Channel Permissions
 	mux.Handle("GET /api/servers/{serverId}/channels/{id}/permissions", authServer(h.ChannelPermission.ListOverrides))
-	mux.Handle("PUT /api/servers/{serverId}/channels/{channelId}/permissions/{roleId}", authServerPerm(models.PermManageChannels, h.ChannelPermission.SetOverride))
-	mux.Handle("DELETE /api/servers/{serverId}/channels/{channelId}/permissions/{roleId}", authServerPerm(models.PermManageChannels, h.ChannelPermission.DeleteOverride))
+	mux.Handle("PUT /api/servers/{serverId}/channels/{channelId}/permissions/{targetType}/{targetId}", authServerPerm(models.PermManageChannels, h.ChannelPermission.SetOverride))
+	mux.Handle("DELETE /api/servers/{serverId}/channels/{channelId}/permissions/{targetType}/{targetId}", authServerPerm(models.PermManageChannels, h.ChannelPermission.DeleteOverride))
 
 	// Invites
 	mux.Handle("GET /api/servers/{serverId}/invites", authServerPerm(models.PermManageInvites, h.Invite.List))
 	mux.Handle("POST /api/servers/{serverId}/invites", authServerPerm(models.PermManageInvites, h.Invite.Create))
 	mux.Handle("DELETE /api/servers/{serverId}/invites/{code}", authServerPerm(models.PermManageInvites, h.Invite.Delete))
+	mux.Handle("POST /api/servers/{serverId}/vanity", authServerPerm(models.PermManageInvites, h.Server.SetVanity))
 
 	// Join Requests (approval-required servers) — PermApproveMembers gates view + act
 	mux.Handle("GET /api/servers/{serverId}/requests", authServerPerm(models.PermApproveMembers, h.Server.ListJoinRequests))
@@ -399,6 +467,11 @@ func initRoutes(
 	mux.Handle("POST /api/servers/{serverId}/voice/screen-token", authServer(h.Voice.ScreenShareToken))
 	mux.Handle("GET /api/servers/{serverId}/voice/states", authServer(h.Voice.VoiceStates))
 
+	// Voice channel recording (LiveKit egress) — everyone sees the indicator
+	// broadcast, but only PermRecordVoice holders control the egress.
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/recording/start", authServerPerm(models.PermRecordVoice, h.Recording.Start))
+	mux.Handle("POST /api/servers/{serverId}/channels/{id}/recording/stop", authServerPerm(models.PermRecordVoice, h.Recording.Stop))
+
 	// Voice channel ephemeral chat — membership check (must be in voice) lives in the service
 	mux.Handle("GET /api/voice-channels/{channelId}/messages", auth(h.VoiceMessage.List))
 	mux.Handle("POST /api/voice-channels/{channelId}/messages", auth(h.VoiceMessage.Create))
@@ -11,8 +11,10 @@ import (
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg/antivirus"
 	"github.com/akinalp/mqvi/pkg/apns"
+	"github.com/akinalp/mqvi/pkg/crypto"
 	"github.com/akinalp/mqvi/pkg/email"
 	"github.com/akinalp/mqvi/pkg/files"
+	"github.com/akinalp/mqvi/pkg/namefilter"
 	"github.com/akinalp/mqvi/pkg/password"
 	"github.com/akinalp/mqvi/pkg/push"
 	"github.com/akinalp/mqvi/pkg/ratelimit"
@@ -27,6 +29,7 @@ type Services struct {
 	Channel            services.ChannelService
 	Category           services.CategoryService
 	Message            services.MessageService
+	Idempotency        services.IdempotencyService
 	Upload             services.UploadService
 	DMUpload           services.DMUploadService
 	UploadPipeline     services.UploadPipeline
@@ -41,12 +44,16 @@ type Services struct {
 	Reaction           services.ReactionService
 	ChannelPermission  services.ChannelPermissionService
 	Friendship         services.FriendshipService
+	PresenceAudience   services.PresenceAudienceService
 	LiveKitAdmin       services.LiveKitAdminService
 	P2PCall            services.P2PCallService
 	TURN               services.ICEServerProvider
 	MetricsHistory     services.MetricsHistoryService
 	ServerMute         services.ServerMuteService
 	ChannelMute        services.ChannelMuteService
+	Notification       services.NotificationService
+	KeywordAlert       services.KeywordAlertService
+	ChannelFollow      services.ChannelFollowService
 	DMSettings         services.DMSettingsService
 	Block              services.BlockService
 	Report             services.ReportService
@@ -57,6 +64,7 @@ type Services struct {
 	Device             services.DeviceService
 	E2EE               services.E2EEService
 	LinkPreview        services.LinkPreviewService
+	Embed              services.EmbedService
 	Badge              services.BadgeService
 	Preferences        services.PreferencesService
 	AppLog             services.AppLogService
@@ -65,17 +73,26 @@ type Services struct {
 	Soundboard         services.SoundboardService
 	Storage            services.StorageService
 	Cleanup            services.CleanupService
+	Export             services.ExportService
 	SettingsBadge      services.SettingsBadgeService
 	VoiceMessage       services.VoiceMessageService
 	PushToken          services.PushTokenService
 	Push               services.PushNotifier
 	Discovery          services.DiscoveryService
+	AuditLog           services.AuditLogService
+	AuditRetention     services.AuditRetentionService
+	Scheduler          services.SchedulerService
+	MessageExpiry      services.MessageExpiryService
+	MessageRetention   services.MessageRetentionService
+	Webhook            services.WebhookService
+	Recording          services.RecordingService
 	EmailSender        email.EmailSender
 }
 
 type RateLimiters struct {
 	Login     *ratelimit.LoginRateLimiter
 	Message   *ratelimit.MessageRateLimiter
+	Reaction  *ratelimit.MessageRateLimiter
 	Register  *ratelimit.LoginRateLimiter
 	ForgotPwd *ratelimit.LoginRateLimiter
 	ResetPwd  *ratelimit.LoginRateLimiter
@@ -89,14 +106,19 @@ type RateLimiters struct {
 	// DM being read on the desktop.
 	DMRead      *ratelimit.MessageRateLimiter
 	ChannelRead *ratelimit.MessageRateLimiter
+	// ServerCreate throttles POST /api/servers per user (platform admins exempt).
+	ServerCreate *ratelimit.MessageRateLimiter
+	// Webhook throttles the public execute endpoint, keyed by webhook ID —
+	// integrations have no user to key on.
+	Webhook *ratelimit.MessageRateLimiter
 }
 
 // initServices creates all services. Order matters:
 // channelPermService -> voiceService/messageService (dependency)
 // voiceService/p2pCallService -> before Hub callbacks (closure scoping)
-func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *config.Config, encryptionKey []byte, urlSigner services.FileURLSigner) (*Services, *RateLimiters, services.MetricsCollector) {
+func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *config.Config, keychain *crypto.Keychain, urlSigner services.FileURLSigner) (*Services, *RateLimiters, services.MetricsCollector) {
 	// File locator: single source of truth for upload paths and URLs.
-	fileLocator := files.NewLocator(cfg.Upload.Dir, cfg.Upload.PublicURL)
+	fileLocator := files.NewLocator(cfg.Upload.Dir, cfg.Upload.PublicURL, cfg.Upload.DirMode)
 
 	// Storage quota service
 	storageService := services.NewStorageService(repos.Storage, cfg.Upload.DefaultQuotaBytes)
@@ -104,6 +126,13 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 	avScanner := antivirus.NewClamAVScanner(cfg.Antivirus.ClamAVAddr, time.Duration(cfg.Antivirus.TimeoutSeconds)*time.Second)
 	uploadPipeline := services.NewUploadPipeline(fileLocator, avScanner, repos.ScanHashCache, appLogService, cfg.Antivirus)
 
+	// Image dimension caps shared by every image upload entry point.
+	imageLimits := files.ImageLimits{
+		MaxWidth:  cfg.Upload.MaxImageWidth,
+		MaxHeight: cfg.Upload.MaxImageHeight,
+		MaxPixels: cfg.Upload.MaxImagePixels,
+	}
+
 	// File cleanup service (bulk file deletion + quota release for cascading deletes).
 	// Cleanup repo enables the retry queue so failed disk deletes get re-attempted
 	// daily by the embedded cleanup worker (Phase 16 P3).
@@ -114,9 +143,12 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		repos.ChannelPermission, repos.Role, repos.Channel, hub,
 	)
 	voiceService := services.NewVoiceService(
-		repos.Channel, repos.LiveKit, channelPermService, hub, hub, repos.Server, encryptionKey, urlSigner,
+		repos.Channel, repos.LiveKit, channelPermService, hub, hub, repos.Server, keychain, urlSigner,
+		cfg.MaxVoiceParticipants,
 	)
 	p2pCallService := services.NewP2PCallService(repos.Friendship, repos.User, hub, urlSigner, cfg.CallGraceWindow)
+	p2pCallService.SetGroupCallInfra(repos.LiveKit, keychain) // ad-hoc LiveKit rooms for group calls
+	recordingService := services.NewRecordingService(repos.Channel, repos.LiveKit, keychain, repos.VoiceRecording, hub)
 
 	// ICE server provider for P2P calls (STUN + TURN relay fallback).
 	turnService := services.NewTURNService(
@@ -152,21 +184,65 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		breachChecker = password.NewHIBPChecker()
 	}
 
+	passwordPolicy := password.Policy{
+		MinLength:        cfg.PasswordPolicy.MinLength,
+		RequireUppercase: cfg.PasswordPolicy.RequireUppercase,
+		RequireDigit:     cfg.PasswordPolicy.RequireDigit,
+		RequireSymbol:    cfg.PasswordPolicy.RequireSymbol,
+		BcryptCost:       cfg.PasswordPolicy.BcryptCost,
+	}
+
 	authService := services.NewAuthService(
-		repos.User, repos.Session, repos.ResetToken, hub, emailSender, breachChecker,
+		repos.User, repos.Session, repos.ResetToken, hub, emailSender, breachChecker, passwordPolicy,
 		cfg.JWT.Secret, cfg.JWT.AccessTokenExpiry, cfg.JWT.RefreshTokenExpiry,
 	)
-	channelService := services.NewChannelService(repos.Channel, repos.Category, hub, channelPermService, voiceService, voiceService, fileCleanupService)
+	channelService := services.NewChannelService(
+		repos.Channel, repos.Category, repos.ReadState, repos.Pin, repos.Server, repos.LiveKit,
+		repos.ChannelPermission,
+		hub, channelPermService, voiceService, voiceService, fileCleanupService,
+		services.VoiceBitrateLimits{
+			Min:           cfg.VoiceBitrate.Min,
+			HostedMax:     cfg.VoiceBitrate.HostedMax,
+			SelfHostedMax: cfg.VoiceBitrate.SelfHostedMax,
+		},
+	)
 	categoryService := services.NewCategoryService(repos.Category, hub)
 	messageService := services.NewMessageService(
 		repos.Message, repos.Attachment, repos.Channel, repos.User,
 		repos.Mention, repos.RoleMention, repos.Role, repos.Reaction, repos.ReadState,
 		hub, channelPermService, urlSigner, fileLocator, storageService, repos.Server,
 	)
-	uploadService := services.NewUploadService(repos.Attachment, uploadPipeline, cfg.Upload.MaxSize)
-	memberService := services.NewMemberService(repos.User, repos.Role, repos.Ban, repos.Server, hub, voiceService, voiceService, urlSigner)
+	uploadService := services.NewUploadService(repos.Attachment, uploadPipeline, cfg.Upload.MaxSize, imageLimits)
+	// Retry dedup for channel and DM sends (Idempotency-Key header).
+	idempotencyService := services.NewIdempotencyService()
+	// Scheduled messages deliver through the normal create path, so send-time
+	// permission checks come for free.
+	schedulerService := services.NewSchedulerService(repos.ScheduledMessage, repos.Channel, channelPermService, messageService)
+	// Ephemeral-message sweep — removes messages past their expires_at with the
+	// same file/quota/unread bookkeeping as a moderation delete.
+	messageExpiryService := services.NewMessageExpiryService(
+		repos.Message, repos.Attachment, repos.Channel, repos.ReadState,
+		channelPermService, fileLocator, storageService, hub,
+	)
+	// Per-server retention sweep — deletes messages older than the server's
+	// message_retention_days window, daily, in bounded chunks.
+	messageRetentionService := services.NewMessageRetentionService(
+		repos.Message, repos.Attachment, repos.Channel, repos.ReadState,
+		channelPermService, fileLocator, storageService, hub,
+	)
+	// Webhook posts go through the message service so they get the same
+	// broadcast and unread handling as user messages.
+	webhookService := services.NewWebhookService(repos.Webhook, repos.Channel, messageService)
+	memberService := services.NewMemberService(repos.User, repos.Role, repos.Ban, repos.Server, repos.Friendship, hub, voiceService, voiceService, urlSigner)
 	roleService := services.NewRoleService(repos.Role, repos.User, hub)
 
+	// Instance name blocklist (optional) — one filter shared by every name entry point.
+	if nameFilter := namefilter.New(cfg.NameFilter.BlockedWords); nameFilter != nil {
+		authService.SetNameFilter(nameFilter)
+		memberService.SetNameFilter(nameFilter)
+		log.Printf("[main] name blocklist enabled (%d terms merged)", len(cfg.NameFilter.BlockedWords))
+	}
+
 	// Wire live mid-call voice permission enforcement (S3). Post-construction because
 	// voiceService is built after channelPermService (its permission resolver).
 	channelPermService.SetVoiceEnforcer(voiceService)
@@ -177,27 +253,80 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 	// must drop stale cached perms so join/send gates see the change immediately (not ≤30s late).
 	roleService.SetPermCacheInvalidator(channelPermService)
 	memberService.SetPermCacheInvalidator(channelPermService)
+
+	// Wire member-timeout enforcement: a timed-out member can't send, react, or
+	// publish in voice until the timeout lapses.
+	messageService.SetTimeoutChecker(memberService)
+	voiceService.SetTimeoutChecker(memberService)
+	voiceService.SetNicknameGetter(repos.Server)
+
+	// Rules-acceptance gate rides the permission resolver; acceptance invalidates the cache.
+	channelPermService.SetRulesGate(repos.Server)
 	serverService := services.NewServerService(
 		db, repos.Server, repos.LiveKit, repos.Role, repos.Channel,
-		repos.Category, repos.User, repos.Ban, repos.JoinRequest, inviteService, hub, voiceService, voiceService, encryptionKey, urlSigner, fileCleanupService,
+		repos.Category, repos.User, repos.Ban, repos.JoinRequest, repos.ChannelPermission, repos.ServerTemplate,
+		repos.DM, inviteService, hub, voiceService, voiceService, keychain, urlSigner, fileCleanupService,
+		cfg.DefaultMemberPermissions, cfg.Upload.MaxSize,
+	)
+	serverService.SetPermCacheInvalidator(channelPermService)
+	serverService.SetChannelLister(channelService)
+	serverService.SetMemberGetter(memberService)     // member payloads for post-transfer broadcasts
+	authService.SetOwnedServerDeleter(serverService) // self-deleted accounts take their servers down too
+
+	// Audit log: every moderation path records through the same best-effort recorder.
+	auditLogService := services.NewAuditLogService(repos.AuditLog)
+	memberService.SetAuditRecorder(auditLogService)
+	roleService.SetAuditRecorder(auditLogService)
+	channelService.SetAuditRecorder(auditLogService)
+	serverService.SetAuditRecorder(auditLogService)
+	// Audit retention sweep — keeps the append-only log bounded per the
+	// instance's age window and per-server cap.
+	auditRetentionService := services.NewAuditRetentionService(
+		repos.AuditLog, cfg.AuditRetention.Days, cfg.AuditRetention.MaxEntriesPerServer,
 	)
 	livekitAdminService := services.NewLiveKitAdminService(
 		repos.LiveKit, repos.Server, repos.User, repos.Channel,
-		voiceService, encryptionKey, cfg.HetznerAPIToken, urlSigner,
+		voiceService, keychain, cfg.HetznerAPIToken, urlSigner,
 		cfg.Upload.DefaultQuotaBytes,
 	)
-	pinService := services.NewPinService(repos.Pin, repos.Message, repos.Channel, hub, channelPermService, urlSigner)
-	searchService := services.NewSearchService(repos.Search, urlSigner)
-	readStateService := services.NewReadStateService(repos.ReadState, channelPermService)
+	pinService := services.NewPinService(repos.Pin, repos.Message, repos.Channel, hub, channelPermService, urlSigner, cfg.MaxPinsPerChannel)
+	pinService.SetSystemMessenger(messageService)
+	// Join notices land in the default channel; creation notices open the new channel's timeline.
+	serverService.SetSystemMessenger(messageService)
+	channelService.SetSystemMessenger(messageService)
+	searchService := services.NewSearchService(repos.Search, repos.Channel, channelPermService, urlSigner)
+	// NotificationService before ReadStateService (ReadStateService uses it as NotificationMuteResolver)
+	notificationService := services.NewNotificationService(repos.CategoryMute, repos.ChannelMute, repos.ServerMute, repos.Channel, repos.NotifSetting)
+	messageService.SetMentionGate(notificationService) // "nothing" settings stop mention rows at the source
+	readStateService := services.NewReadStateService(repos.ReadState, channelPermService, notificationService)
+
+	keywordAlertService := services.NewKeywordAlertService(repos.KeywordAlert, repos.Channel, notificationService, hub)
+	messageService.SetKeywordHighlighter(keywordAlertService)
+
+	// Setters in both directions: the follow service posts cross-posts through the
+	// message service, which calls back into it on every message broadcast.
+	channelFollowService := services.NewChannelFollowService(repos.ChannelFollow, repos.Channel, channelPermService)
+	channelFollowService.SetSystemMessenger(messageService)
+	messageService.SetCrossposter(channelFollowService)
+
+	formatLimits := models.MessageFormatLimits{
+		MaxLines:    cfg.MessageFormat.MaxLines,
+		MaxBlankRun: cfg.MessageFormat.MaxBlankLines,
+	}
+	messageService.SetFormatLimits(formatLimits)
 
 	// BlockService before DMService (DMService uses it as BlockChecker)
 	blockService := services.NewBlockService(repos.Friendship, repos.User, hub, urlSigner)
+	messageService.SetBlockChecker(blockService) // mentions from someone a user blocked don't ping them
 
 	// DMSettingsService before DMService (DMService uses it as DMSettingsUnhider)
 	dmSettingsService := services.NewDMSettingsService(repos.DMSettings, repos.DM, hub)
 
 	friendshipService := services.NewFriendshipService(repos.Friendship, repos.User, hub, urlSigner)
-	dmService := services.NewDMService(repos.DM, repos.User, hub, blockService, friendshipService, dmSettingsService, urlSigner, fileLocator, storageService)
+	presenceAudienceService := services.NewPresenceAudienceService(repos.Server, repos.Friendship, hub)
+	memberService.SetPresenceAudience(presenceAudienceService) // scope manual presence updates to peers
+	dmService := services.NewDMService(repos.DM, repos.User, hub, blockService, friendshipService, repos.Server, dmSettingsService, urlSigner, fileLocator, storageService, cfg.Reactions.MaxPerUserPerMessage, cfg.Reactions.MaxDistinctPerMessage)
+	dmService.SetFormatLimits(formatLimits)
 	friendshipService.SetDMAcceptor(dmService) // auto-accept pending DMs when friendship is accepted
 	p2pCallService.SetCallLogger(dmService)    // P2P calls write a call-log entry into the DM history
 
@@ -226,8 +355,9 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 	pushService := services.NewPushService(pushSender, apnsSender, repos.PushToken, repos.User, hub, repos.DM, cfg.Push)
 	dmService.SetPushNotifier(pushService)
 	p2pCallService.SetPushNotifier(pushService)
-	dmUploadService := services.NewDMUploadService(repos.DM, uploadPipeline, cfg.Upload.MaxSize)
-	reactionService := services.NewReactionService(repos.Reaction, repos.Message, repos.Channel, hub, channelPermService)
+	dmUploadService := services.NewDMUploadService(repos.DM, uploadPipeline, cfg.Upload.MaxSize, imageLimits)
+	reactionService := services.NewReactionService(repos.Reaction, repos.Message, repos.Channel, repos.Server, hub, channelPermService, urlSigner, cfg.Reactions.MaxPerUserPerMessage, cfg.Reactions.MaxDistinctPerMessage)
+	reactionService.SetTimeoutChecker(memberService)
 	serverMuteService := services.NewServerMuteService(repos.ServerMute)
 	channelMuteService := services.NewChannelMuteService(repos.ChannelMute)
 	reportService := services.NewReportService(repos.Report, repos.ServerReport, repos.User, repos.Server, urlSigner, emailSender)
@@ -243,15 +373,19 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 	discoveryService := services.NewDiscoveryService(repos.Discovery, hub, urlSigner)
 
 	linkPreviewService := services.NewLinkPreviewService(repos.LinkPreview)
+	// Server-side unfurling: new messages get their first link embedded after
+	// the fact, through the same SSRF-guarded fetcher the preview endpoint uses.
+	embedService := services.NewEmbedService(repos.MessageEmbed, repos.Channel, linkPreviewService, channelPermService, hub)
+	messageService.SetEmbedder(embedService)
 	badgeService := services.NewBadgeService(repos.Badge, hub)
 	preferencesService := services.NewPreferencesService(repos.Preferences)
 	metricsHistoryService := services.NewMetricsHistoryService(repos.MetricsHistory, repos.LiveKit)
 	feedbackService := services.NewFeedbackService(repos.Feedback, repos.User, fileLocator, storageService, emailSender)
 	settingsBadgeService := services.NewSettingsBadgeService(repos.User, repos.Feedback, repos.Report)
-	voiceMessageService := services.NewVoiceMessageService(repos.VoiceMessage, voiceService, hub, urlSigner, fileLocator)
-	// Wipe ephemeral voice chat when the last participant leaves the channel.
-	// 5-minute timeout so a hung DB call can't leak the goroutine indefinitely.
-	voiceService.SetOnChannelEmpty(func(channelID string) {
+	voiceMessageService := services.NewVoiceMessageService(repos.VoiceMessage, voiceService, channelPermService, hub, urlSigner, fileLocator)
+	// Voice chat history outlives the voice session; it's only wiped when the
+	// channel is deleted. 5-minute timeout so a hung DB call can't stall forever.
+	channelService.SetVoiceChatCleanup(func(channelID string) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
 		voiceMessageService.CleanupChannel(ctx, channelID)
@@ -268,10 +402,13 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		cfg.HetznerAPIToken,
 		voiceService,
 	)
+	// Owner-triggered server history exports; the cleanup sweep expires the archives.
+	exportService := services.NewExportService(db, repos.ExportJob, repos.Server, cfg.Upload.Dir)
 	cleanupService := services.NewCleanupService(
 		db, repos.Cleanup, repos.ScanHashCache,
 		repos.User, repos.Server,
 		adminUserService, adminServerService,
+		repos.Invite, exportService,
 		fileLocator, appLogService,
 		cfg.Upload.Dir,
 		time.Duration(cfg.Antivirus.CleanCacheTTLHours)*time.Hour,
@@ -280,7 +417,8 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 
 	// Rate limiters
 	loginLimiter := ratelimit.NewLoginRateLimiter(5, 2*time.Minute)
-	messageLimiter := ratelimit.NewMessageRateLimiter(5, 5*time.Second, 15*time.Second)
+	messageLimiter := ratelimit.NewMessageRateLimiter(cfg.MessageRate.Burst, cfg.MessageRate.Window, cfg.MessageRate.Cooldown)
+	reactionLimiter := ratelimit.NewMessageRateLimiter(cfg.ReactionRate.Burst, cfg.ReactionRate.Window, cfg.ReactionRate.Cooldown)
 	registerLimiter := ratelimit.NewLoginRateLimiter(3, 10*time.Minute)                    // 3 registrations per 10 min per IP
 	forgotPwdLimiter := ratelimit.NewLoginRateLimiter(3, 5*time.Minute)                    // 3 forgot-password per 5 min per IP
 	resetPwdLimiter := ratelimit.NewLoginRateLimiter(5, 5*time.Minute)                     // 5 reset attempts per 5 min per IP
@@ -292,6 +430,10 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 	// not. Separate buckets so neither path can spend the other's tokens.
 	dmReadLimiter := ratelimit.NewMessageRateLimiter(30, 10*time.Second, 10*time.Second)
 	channelReadLimiter := ratelimit.NewMessageRateLimiter(30, 10*time.Second, 10*time.Second)
+	// Server creation: the owned-server count limit only covers mqvi-hosted, so without this
+	// a script could spam self-hosted servers. Per-hour window, configurable, admins exempt.
+	serverCreateLimiter := ratelimit.NewMessageRateLimiter(cfg.ServerCreatePerHour, 1*time.Hour, 10*time.Minute)
+	webhookLimiter := ratelimit.NewMessageRateLimiter(30, 1*time.Minute, 30*time.Second) // 30 webhook posts per min per webhook
 
 	svcs := &Services{
 		Auth:               authService,
@@ -299,6 +441,7 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		Channel:            channelService,
 		Category:           categoryService,
 		Message:            messageService,
+		Idempotency:        idempotencyService,
 		Upload:             uploadService,
 		DMUpload:           dmUploadService,
 		UploadPipeline:     uploadPipeline,
@@ -313,12 +456,16 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		Reaction:           reactionService,
 		ChannelPermission:  channelPermService,
 		Friendship:         friendshipService,
+		PresenceAudience:   presenceAudienceService,
 		LiveKitAdmin:       livekitAdminService,
 		P2PCall:            p2pCallService,
 		TURN:               turnService,
 		MetricsHistory:     metricsHistoryService,
 		ServerMute:         serverMuteService,
 		ChannelMute:        channelMuteService,
+		Notification:       notificationService,
+		KeywordAlert:       keywordAlertService,
+		ChannelFollow:      channelFollowService,
 		DMSettings:         dmSettingsService,
 		Block:              blockService,
 		Report:             reportService,
@@ -329,6 +476,7 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		Device:             deviceService,
 		E2EE:               e2eeService,
 		LinkPreview:        linkPreviewService,
+		Embed:              embedService,
 		Badge:              badgeService,
 		Preferences:        preferencesService,
 		AppLog:             appLogService,
@@ -337,25 +485,36 @@ func initServices(db *sql.DB, repos *Repositories, hub ws.EventPublisher, cfg *c
 		Soundboard:         soundboardService,
 		Storage:            storageService,
 		Cleanup:            cleanupService,
+		Export:             exportService,
 		SettingsBadge:      settingsBadgeService,
 		VoiceMessage:       voiceMessageService,
 		PushToken:          pushTokenService,
 		Discovery:          discoveryService,
+		AuditLog:           auditLogService,
+		AuditRetention:     auditRetentionService,
+		Scheduler:          schedulerService,
+		MessageExpiry:      messageExpiryService,
+		MessageRetention:   messageRetentionService,
+		Webhook:            webhookService,
+		Recording:          recordingService,
 		Push:               pushService,
 		EmailSender:        emailSender,
 	}
 
 	limiters := &RateLimiters{
-		Login:       loginLimiter,
-		Message:     messageLimiter,
-		Register:    registerLimiter,
-		ForgotPwd:   forgotPwdLimiter,
-		ResetPwd:    resetPwdLimiter,
-		Feedback:    feedbackLimiter,
-		ICE:         iceLimiter,
-		Discovery:   discoveryLimiter,
-		DMRead:      dmReadLimiter,
-		ChannelRead: channelReadLimiter,
+		Login:        loginLimiter,
+		Message:      messageLimiter,
+		Reaction:     reactionLimiter,
+		Register:     registerLimiter,
+		ForgotPwd:    forgotPwdLimiter,
+		ResetPwd:     resetPwdLimiter,
+		Feedback:     feedbackLimiter,
+		ICE:          iceLimiter,
+		Discovery:    discoveryLimiter,
+		DMRead:       dmReadLimiter,
+		ChannelRead:  channelReadLimiter,
+		ServerCreate: serverCreateLimiter,
+		Webhook:      webhookLimiter,
 	}
 
 	return svcs, limiters, metricsCollector
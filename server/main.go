@@ -24,6 +24,7 @@ import (
 	"github.com/akinalp/mqvi/pkg/fileacl"
 	"github.com/akinalp/mqvi/pkg/files"
 	"github.com/akinalp/mqvi/pkg/i18n"
+	"github.com/akinalp/mqvi/pkg/origin"
 	"github.com/akinalp/mqvi/pkg/ratelimit"
 	"github.com/akinalp/mqvi/pkg/signedurl"
 	"github.com/akinalp/mqvi/repository"
@@ -99,14 +100,11 @@ func main() {
 	// 5. Repository layer
 	repos := initRepositories(db.Conn)
 
-	// 6. Encryption key
-	encryptionKey, err := crypto.DeriveKey(cfg.EncryptionKey)
-	if err != nil {
-		log.Fatalf("[main] invalid ENCRYPTION_KEY: %v", err)
-	}
+	// 6. Encryption keys (primary + optional previous during rotation)
+	keychain := initKeychain(cfg)
 
 	// 7. Startup cleanup + presence reset + LiveKit seed
-	runStartupCleanup(db, repos, cfg, encryptionKey)
+	runStartupCleanup(db, repos, cfg, keychain)
 
 	// 8. Signed URL signer (before services — services need it to sign URLs)
 	fileSigner := initFileSigner(cfg)
@@ -118,9 +116,10 @@ func main() {
 
 	// 9. WebSocket Hub
 	hub := ws.NewHub()
+	hub.SetIdleThreshold(cfg.Server.PresenceIdleTimeout)
 
 	// 10. Service layer (order matters: channelPerm -> voice -> p2pCall -> rest)
-	svcs, limiters, metricsCollector := initServices(db.Conn, repos, hub, cfg, encryptionKey, urlSigner)
+	svcs, limiters, metricsCollector := initServices(db.Conn, repos, hub, cfg, keychain, urlSigner)
 
 	// 10b. Wire structured app logger into Hub and services
 	hub.SetAppLogger(svcs.AppLog)
@@ -129,7 +128,11 @@ func main() {
 	svcs.Auth.SetAppLogger(svcs.AppLog)
 
 	// 11. Hub callbacks (must be after services, before hub.Run)
-	registerHubCallbacks(hub, repos.User, repos.DM, svcs.Voice, svcs.P2PCall, repos.Channel, repos.Server, svcs.ChannelPermission)
+	registerHubCallbacks(hub, repos.User, repos.DM, svcs.Voice, svcs.P2PCall, repos.Channel, repos.Server, svcs.ChannelPermission, svcs.Server, svcs.PresenceAudience)
+
+	// A sent message retracts its author's typing indicator immediately,
+	// instead of waiting for the hub's idle sweep.
+	svcs.Message.SetTypingStopper(hub)
 
 	go hub.Run()
 
@@ -139,9 +142,16 @@ func main() {
 	// Voice AFK checker — kicks idle users based on per-server timeout
 	svcs.Voice.StartAFKChecker()
 
+	// Member timeout sweeper — clears expired moderation timeouts and notifies clients
+	svcs.Member.StartTimeoutSweeper()
+	svcs.Member.StartCustomStatusSweeper()
+
 	// Voice LiveKit reconciliation — reaps phantom states using the SFU as source of truth
 	svcs.Voice.StartLiveKitReconciliation()
 
+	// Voice channel reconciliation — reaps states pointing at deleted channels
+	svcs.Voice.StartChannelReconciliation(cfg.VoiceChannelReconcileInterval)
+
 	// 10b. Metrics collector — background goroutine polling LiveKit instances
 	metricsCollector.Start()
 
@@ -151,8 +161,21 @@ func main() {
 	// 10d. Cleanup worker — daily soft-delete TTL processing, orphan walk, retry queue
 	svcs.Cleanup.Start()
 
+	// 10e. Scheduled-message poller — delivers queued messages when due
+	svcs.Scheduler.Start()
+
+	// 10f. Ephemeral-message sweep — deletes messages past their TTL
+	svcs.MessageExpiry.Start()
+
+	// 10g. Retention sweep — enforces per-server message_retention_days windows
+	svcs.MessageRetention.Start()
+
+	// 10h. Audit retention sweep — prunes audit log entries past the
+	// instance's retention bounds
+	svcs.AuditRetention.Start()
+
 	// 12. Handler layer
-	h := initHandlers(svcs, repos, limiters, hub, cfg, encryptionKey, urlSigner)
+	h := initHandlers(svcs, repos, limiters, hub, cfg, keychain, urlSigner)
 
 	// 13. HTTP router + routes
 	fileACL := fileacl.NewChecker(
@@ -184,7 +207,7 @@ func main() {
 
 	// Readiness lives on its own loopback listener, NOT on the public mux — it hits the database
 	// on every request and reports pool and goroutine internals.
-	stopReadiness := startReadinessServer(cfg.Server.ReadinessAddr, db.Conn, hub, svcs.Push)
+	stopReadiness := startReadinessServer(cfg.Server.ReadinessAddr, db.Conn, hub, svcs.Push, repos.LiveKit)
 
 	// 15. SPA frontend serving
 	frontendFS, hasFrontend := initFrontendFS()
@@ -199,9 +222,9 @@ func main() {
 		}
 	}
 
-	// 15. CORS (shared origin whitelist for both HTTP CORS and WebSocket upgrade)
-	corsHandler, corsOrigins := initCORS(cfg)
-	ws.AllowedOrigins = corsOrigins
+	// 15. CORS (shared origin policy for both HTTP CORS and WebSocket upgrade)
+	corsHandler, originMatcher := initCORS(cfg)
+	ws.AllowOrigin = originMatcher.Allow
 
 	// 16. Final handler
 	apiHandler := corsHandler.Handler(mux)
@@ -277,21 +300,38 @@ func main() {
 	<-done
 	log.Println("[main] shutting down...")
 
+	svcs.Scheduler.Stop()
+	svcs.MessageExpiry.Stop()
+	svcs.MessageRetention.Stop()
+	svcs.AuditRetention.Stop()
 	svcs.Cleanup.Stop()
 	svcs.AppLog.Stop()
 	metricsCollector.Stop()
 	fileLimiter.Stop()
-	hub.Shutdown()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Drain order: notify + stop accepting, wait for in-flight requests,
+	// then close. BeginShutdown tells every WS client the instance is going
+	// away and flips the handler to 503 new connections; srv.Shutdown then
+	// waits (up to the configured window) for in-flight HTTP requests —
+	// uploads included — to finish. Hijacked WS sockets are not srv.Shutdown's
+	// to wait for, so hub.Shutdown force-closes whatever is left at the end.
+	hub.BeginShutdown(cfg.Server.ShutdownDrain)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownDrain)
 	defer cancel()
 
 	if err := stopReadiness(ctx); err != nil {
 		log.Printf("[main] readiness server shutdown: %v", err)
 	}
+
+	log.Printf("[main] draining in-flight requests (up to %s)...", cfg.Server.ShutdownDrain)
+	drainStart := time.Now()
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("[main] forced shutdown: %v", err)
+		hub.Shutdown()
+		log.Fatalf("[main] forced shutdown after %s: %v", time.Since(drainStart).Round(time.Millisecond), err)
 	}
+	log.Printf("[main] requests drained in %s", time.Since(drainStart).Round(time.Millisecond))
+	hub.Shutdown()
 
 	log.Println("[main] server stopped gracefully")
 }
@@ -344,8 +384,30 @@ func (a *fileSignerAdapter) SignURLPtr(fileURL *string) *string {
 	return a.signer.SignPtr(fileURL, a.prefix, a.ttl)
 }
 
+// initKeychain derives the credential-encryption keys from config. Fails fast on a
+// malformed key — a wrong ENCRYPTION_KEY would silently break every voice path.
+// Mirrors initFileSigner's rotation handling: ENCRYPTION_KEY_PREV keeps credentials
+// encrypted under the old key readable while runStartupCleanup rewrites them.
+func initKeychain(cfg *config.Config) *crypto.Keychain {
+	primary, err := crypto.DeriveKey(cfg.EncryptionKey)
+	if err != nil {
+		log.Fatalf("[main] invalid ENCRYPTION_KEY: %v", err)
+	}
+
+	var prev []byte
+	if cfg.EncryptionKeyPrev != "" {
+		prev, err = crypto.DeriveKey(cfg.EncryptionKeyPrev)
+		if err != nil {
+			log.Fatalf("[main] invalid ENCRYPTION_KEY_PREV: %v", err)
+		}
+		log.Println("[main] ENCRYPTION_KEY_PREV set — key rotation in progress, stored credentials will be re-encrypted")
+	}
+
+	return crypto.NewKeychain(primary, prev)
+}
+
 // runStartupCleanup handles one-time DB cleanup and seeding at boot.
-func runStartupCleanup(db *database.DB, repos *Repositories, cfg *config.Config, encryptionKey []byte) {
+func runStartupCleanup(db *database.DB, repos *Repositories, cfg *config.Config, keychain *crypto.Keychain) {
 	// Fix empty-ID LiveKit instances
 	{
 		var emptyLK int
@@ -394,6 +456,53 @@ func runStartupCleanup(db *database.DB, repos *Repositories, cfg *config.Config,
 		}
 	}
 
+	// Re-encrypt stored LiveKit credentials under the new key during a rotation.
+	// Only runs when ENCRYPTION_KEY_PREV is set, and only touches rows the
+	// primary key can't open — a crash mid-pass just leaves some rows for the
+	// next boot. Once every instance logs nothing here, drop ENCRYPTION_KEY_PREV.
+	if keychain.HasPrev() {
+		instances, listErr := repos.LiveKit.ListAllInstances(context.Background())
+		if listErr != nil {
+			log.Printf("[main] warning: key rotation: failed to list livekit instances: %v", listErr)
+		}
+		rewritten := 0
+		for i := range instances {
+			if !keychain.DecryptedWithPrev(instances[i].APIKey) && !keychain.DecryptedWithPrev(instances[i].APISecret) {
+				continue
+			}
+			// ListAllInstances returns a trimmed row — load the full one so the
+			// Update below doesn't null out url/max_servers/hetzner_server_id.
+			inst, getErr := repos.LiveKit.GetByID(context.Background(), instances[i].ID)
+			if getErr != nil {
+				log.Printf("[main] warning: key rotation: failed to load instance %s: %v", instances[i].ID, getErr)
+				continue
+			}
+			apiKey, decErr := keychain.Decrypt(inst.APIKey)
+			if decErr == nil {
+				inst.APIKey, decErr = keychain.Encrypt(apiKey)
+			}
+			var apiSecret string
+			if decErr == nil {
+				apiSecret, decErr = keychain.Decrypt(inst.APISecret)
+			}
+			if decErr == nil {
+				inst.APISecret, decErr = keychain.Encrypt(apiSecret)
+			}
+			if decErr != nil {
+				log.Printf("[main] warning: key rotation: instance %s not re-encrypted (decrypts under neither key): %v", inst.ID, decErr)
+				continue
+			}
+			if updErr := repos.LiveKit.Update(context.Background(), inst); updErr != nil {
+				log.Printf("[main] warning: key rotation: failed to rewrite instance %s: %v", inst.ID, updErr)
+				continue
+			}
+			rewritten++
+		}
+		if rewritten > 0 {
+			log.Printf("[main] key rotation: re-encrypted credentials for %d LiveKit instance(s)", rewritten)
+		}
+	}
+
 	// Reset stale presence to offline
 	{
 		result, resetErr := db.Conn.ExecContext(context.Background(),
@@ -409,11 +518,11 @@ func runStartupCleanup(db *database.DB, repos *Repositories, cfg *config.Config,
 	if cfg.LiveKit.URL != "" && cfg.LiveKit.APIKey != "" && cfg.LiveKit.APISecret != "" {
 		platformInstance, seedErr := repos.LiveKit.GetLeastLoadedPlatformInstance(context.Background())
 		if seedErr != nil {
-			encKey, encErr := crypto.Encrypt(cfg.LiveKit.APIKey, encryptionKey)
+			encKey, encErr := keychain.Encrypt(cfg.LiveKit.APIKey)
 			if encErr != nil {
 				log.Fatalf("[main] failed to encrypt platform livekit key: %v", encErr)
 			}
-			encSecret, encErr := crypto.Encrypt(cfg.LiveKit.APISecret, encryptionKey)
+			encSecret, encErr := keychain.Encrypt(cfg.LiveKit.APISecret)
 			if encErr != nil {
 				log.Fatalf("[main] failed to encrypt platform livekit secret: %v", encErr)
 			}
@@ -452,7 +561,7 @@ func registerFileEndpoint(
 	fileACL *fileacl.Checker,
 	fileLimiter *ratelimit.FileRateLimiter,
 ) {
-	fileLocator := files.NewLocator(cfg.Upload.Dir, cfg.Upload.PublicURL)
+	fileLocator := files.NewLocator(cfg.Upload.Dir, cfg.Upload.PublicURL, cfg.Upload.DirMode)
 	filesHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		escaped := r.URL.EscapedPath()
 		after, found := strings.CutPrefix(escaped, files.URLPathPrefix+"/")
@@ -543,7 +652,7 @@ func initFrontendFS() (fs.FS, bool) {
 	return frontendFS, hasFrontend
 }
 
-func initCORS(cfg *config.Config) (*cors.Cors, []string) {
+func initCORS(cfg *config.Config) (*cors.Cors, *origin.Matcher) {
 	corsOrigins := []string{
 		"http://localhost:3030",
 		"http://localhost:1420",
@@ -552,21 +661,29 @@ func initCORS(cfg *config.Config) (*cors.Cors, []string) {
 		"http://localhost",      // Android Capacitor WebView (legacy)
 		"https://localhost",     // Android Capacitor WebView (Capacitor 6+)
 	}
+	// CORS_ORIGINS entries may also be wildcard patterns
+	// ("https://*.example.com") or regexes ("regex:^https://pr-\d+\.example\.com$")
+	// for deployments whose origins can't be enumerated ahead of time. Plain
+	// entries keep their exact-match behavior.
 	if extra := os.Getenv("CORS_ORIGINS"); extra != "" {
-		for _, origin := range strings.Split(extra, ",") {
-			origin = strings.TrimSpace(origin)
-			if origin != "" {
-				corsOrigins = append(corsOrigins, origin)
+		for _, o := range strings.Split(extra, ",") {
+			o = strings.TrimSpace(o)
+			if o != "" {
+				corsOrigins = append(corsOrigins, o)
 			}
 		}
 	}
-	log.Printf("[cors] allowed origins: %v", corsOrigins)
+	matcher, err := origin.New(corsOrigins)
+	if err != nil {
+		log.Fatalf("[cors] invalid CORS_ORIGINS: %v", err)
+	}
+	log.Printf("[cors] allowed origins (%s): %v", matcher.Describe(), corsOrigins)
 	return cors.New(cors.Options{
-		AllowedOrigins:   corsOrigins,
+		AllowOriginFunc:  matcher.Allow,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Authorization", "Content-Type"},
 		AllowCredentials: true,
-	}), corsOrigins
+	}), matcher
 }
 
 // securityHeaders wraps a handler with standard HTTP security headers.
@@ -9,6 +9,7 @@ import (
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg/authcookie"
 	"github.com/akinalp/mqvi/pkg/fileacl"
+	"github.com/akinalp/mqvi/pkg/password"
 	"github.com/akinalp/mqvi/services"
 	"github.com/akinalp/mqvi/testutil"
 )
@@ -54,7 +55,12 @@ func (s fileTokenAuthStub) SoftDeleteSelf(context.Context, string, string) error
 func (s fileTokenAuthStub) RestoreAccount(context.Context, string, string) (*services.AuthTokens, error) {
 	return nil, nil
 }
-func (s fileTokenAuthStub) SetAppLogger(services.AuthAppLogger) {}
+func (s fileTokenAuthStub) PasswordPolicy() password.Policy {
+	return password.Default()
+}
+func (s fileTokenAuthStub) SetAppLogger(services.AuthAppLogger)               {}
+func (s fileTokenAuthStub) SetNameFilter(services.NameFilter)                 {}
+func (s fileTokenAuthStub) SetOwnedServerDeleter(services.OwnedServerDeleter) {}
 
 func TestReadJWTTokens_CookieOnly(t *testing.T) {
 	r := httptest.NewRequest(http.MethodGet, "/api/files/avatars/u/x.png", nil)
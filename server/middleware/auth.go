@@ -51,7 +51,7 @@ func (m *AuthMiddleware) Require(next http.Handler) http.Handler {
 			return
 		}
 
-		if user.IsPlatformBanned {
+		if user.PlatformBanActive() {
 			pkg.ErrorWithMessage(w, http.StatusForbidden, "account suspended")
 			return
 		}
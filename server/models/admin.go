@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // AdminServerListItem — server info for platform admin panel. Aggregated in a single query.
@@ -38,8 +39,11 @@ type AdminListPageParams struct {
 	Presences []string // users only: presence (online/idle/dnd/offline); OR-combined; empty means all
 	Admin     []string // users only: "admin"/"non_admin"; empty or both means all
 	Types     []string // servers only: "managed"/"self"; empty or both means all
-	Sort      string
-	Dir       string // "asc" | "desc"
+	// InstanceID narrows servers to one assigned LiveKit instance (exact id);
+	// empty means all. Lets an operator audit a single instance's tenants.
+	InstanceID string
+	Sort       string
+	Dir        string // "asc" | "desc"
 }
 
 // AdminUserListPage — paged result for /api/admin/users.
@@ -78,9 +82,11 @@ type AdminUserListItem struct {
 }
 
 // PlatformBanRequest — DeleteMessages: if true, all user messages (server + DM) are purged.
+// BannedUntil nil = permanent ban; a timestamp makes the ban lapse on its own.
 type PlatformBanRequest struct {
-	Reason         string `json:"reason"`
-	DeleteMessages bool   `json:"delete_messages"`
+	Reason         string     `json:"reason"`
+	DeleteMessages bool       `json:"delete_messages"`
+	BannedUntil    *time.Time `json:"banned_until"`
 }
 
 // HardDeleteUserRequest — reason is optional; if provided, user is notified via email.
@@ -22,6 +22,7 @@ const (
 	LogCategoryFeedback    LogCategory = "feedback"
 	LogCategoryLiveKit     LogCategory = "livekit"
 	LogCategoryCleaner     LogCategory = "cleaner"
+	LogCategoryServer      LogCategory = "server"
 )
 
 // AppLog represents a structured log entry stored in SQLite.
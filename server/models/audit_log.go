@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Audit log action types. Stored verbatim in audit_logs.action_type and used
+// as-is for the ?action_type= filter on the list endpoint.
+const (
+	AuditActionMemberKick        = "member_kick"
+	AuditActionMemberBan         = "member_ban"
+	AuditActionMemberUnban       = "member_unban"
+	AuditActionMemberRoles       = "member_roles_update"
+	AuditActionMemberTimeout     = "member_timeout"
+	AuditActionMemberNickname    = "member_nickname_update"
+	AuditActionChannelCreate     = "channel_create"
+	AuditActionChannelDelete     = "channel_delete"
+	AuditActionRoleCreate        = "role_create"
+	AuditActionRoleUpdate        = "role_update"
+	AuditActionRoleDelete        = "role_delete"
+	AuditActionServerUpdate      = "server_update"
+	AuditActionOwnershipTransfer = "ownership_transfer"
+)
+
+// AuditLog is one immutable moderation-action record. Metadata is a JSON blob
+// whose shape depends on ActionType (ban reason, role ids, channel name, ...).
+// Usernames are joined at read time, so the log survives account deletion —
+// the IDs stay, the username comes back nil.
+type AuditLog struct {
+	ID         string    `json:"id"`
+	ServerID   string    `json:"server_id"`
+	ActorID    string    `json:"actor_id"`
+	ActionType string    `json:"action_type"`
+	TargetID   *string   `json:"target_id,omitempty"`
+	Metadata   *string   `json:"metadata,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	ActorUsername  *string `json:"actor_username,omitempty"`
+	TargetUsername *string `json:"target_username,omitempty"`
+}
+
+// AuditLogPage is the cursor-based pagination response.
+type AuditLogPage struct {
+	Entries []AuditLog `json:"entries"`
+	HasMore bool       `json:"has_more"`
+}
@@ -0,0 +1,42 @@
+// Package models — CategoryMute domain model.
+// The middle level of the notification cascade: channel override → category → server.
+// MutedUntil nil = muted forever.
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// CategoryMute represents a user muting a whole category of channels.
+type CategoryMute struct {
+	UserID     string     `json:"user_id"`
+	CategoryID string     `json:"category_id"`
+	ServerID   string     `json:"server_id"`
+	MutedUntil *time.Time `json:"muted_until"` // nil = forever
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// MuteCategoryRequest is the request body for muting a category.
+// Valid durations: "1h", "8h", "7d", "forever". Uses validDurations from server_mute.go.
+type MuteCategoryRequest struct {
+	Duration string `json:"duration"`
+}
+
+// Validate checks the duration field.
+func (r *MuteCategoryRequest) Validate() error {
+	if _, ok := validDurations[r.Duration]; !ok {
+		return fmt.Errorf("invalid duration: %s", r.Duration)
+	}
+	return nil
+}
+
+// ParseMutedUntil converts duration string to *time.Time. "forever" returns nil.
+func (r *MuteCategoryRequest) ParseMutedUntil() *time.Time {
+	d := validDurations[r.Duration]
+	if d == 0 {
+		return nil // forever
+	}
+	t := time.Now().UTC().Add(d)
+	return &t
+}
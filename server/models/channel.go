@@ -14,6 +14,9 @@ const (
 	ChannelTypeVoice ChannelType = "voice"
 )
 
+// DefaultVoiceBitrate is what a voice channel gets when no bitrate is requested.
+const DefaultVoiceBitrate = 64000
+
 type Channel struct {
 	ID         string      `json:"id"`
 	ServerID   string      `json:"server_id"`
@@ -24,7 +27,22 @@ type Channel struct {
 	Position   int         `json:"position"`
 	UserLimit  int         `json:"user_limit"` // 0 = unlimited (voice only)
 	Bitrate    int         `json:"bitrate"`    // voice only
-	CreatedAt  time.Time   `json:"created_at"`
+	// RegionHint is the preferred serving region for voice ("eu-central", ...).
+	// Nil = no preference. Advisory until instance selection goes per-channel.
+	RegionHint *string `json:"region_hint,omitempty"` // voice only
+	// PinAnnouncements: post a system message in the channel when a message is pinned.
+	PinAnnouncements bool `json:"pin_announcements"`
+	// SlowmodeSeconds: minimum gap between two messages from the same user (text only, 0 = off).
+	SlowmodeSeconds int `json:"slowmode_seconds"`
+	// DeleteAfterSeconds: messages posted here auto-delete this long after
+	// creation (text only, 0 = off). Changing it only affects new messages.
+	DeleteAfterSeconds int       `json:"delete_after_seconds"`
+	CreatedAt          time.Time `json:"created_at"`
+	// UnreadCount and PinCount are only populated when the channel list endpoint
+	// is asked for them (GET /api/servers/{serverId}/channels?include=unread,pins).
+	// Nil otherwise, so every other Channel payload stays unchanged.
+	UnreadCount *int `json:"unread_count,omitempty"`
+	PinCount    *int `json:"pin_count,omitempty"`
 }
 
 type Category struct {
@@ -46,6 +64,11 @@ type CreateChannelRequest struct {
 	Type       string `json:"type"`
 	CategoryID string `json:"category_id"`
 	Topic      string `json:"topic"`
+	// Bitrate is voice-only; 0 = instance default. Bounded by the host-type
+	// ceiling in the service layer.
+	Bitrate int `json:"bitrate"`
+	// RegionHint is voice-only; empty = no preference.
+	RegionHint string `json:"region_hint"`
 }
 
 func (r *CreateChannelRequest) Validate() error {
@@ -64,17 +87,67 @@ func (r *CreateChannelRequest) Validate() error {
 		return fmt.Errorf("channel topic must be at most 1024 characters")
 	}
 
+	if r.Bitrate < 0 {
+		return fmt.Errorf("bitrate must not be negative")
+	}
+	if r.Bitrate != 0 && r.Type != string(ChannelTypeVoice) {
+		return fmt.Errorf("bitrate only applies to voice channels")
+	}
+
+	r.RegionHint = strings.ToLower(strings.TrimSpace(r.RegionHint))
+	if r.RegionHint != "" {
+		if r.Type != string(ChannelTypeVoice) {
+			return fmt.Errorf("region hint only applies to voice channels")
+		}
+		if !ValidRegionHint(r.RegionHint) {
+			return fmt.Errorf("region hint must be a lowercase token like \"eu-central\"")
+		}
+	}
+
 	return nil
 }
 
+// ValidRegionHint accepts short lowercase region tokens ("eu-central",
+// "us-east-1"): letters, digits and dashes, 2-32 characters.
+func ValidRegionHint(s string) bool {
+	if len(s) < 2 || len(s) > 32 {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
 // UpdateChannelRequest uses pointers for partial update — nil means "don't change".
 // CategoryID: empty string = remove from category, non-nil = move to target category.
 type UpdateChannelRequest struct {
-	Name       *string `json:"name"`
-	Topic      *string `json:"topic"`
-	CategoryID *string `json:"category_id"`
+	Name             *string `json:"name"`
+	Topic            *string `json:"topic"`
+	CategoryID       *string `json:"category_id"`
+	PinAnnouncements *bool   `json:"pin_announcements"`
+	// Bitrate is voice-only. Bounded by the host-type ceiling in the service layer.
+	Bitrate *int `json:"bitrate"`
+	// RegionHint is voice-only; empty string clears the preference.
+	RegionHint *string `json:"region_hint"`
+	// SlowmodeSeconds is text-only. 0 turns slowmode off.
+	SlowmodeSeconds *int `json:"slowmode_seconds"`
+	// DeleteAfterSeconds is text-only. 0 turns message auto-delete off;
+	// messages already posted keep whatever deadline they were created with.
+	DeleteAfterSeconds *int `json:"delete_after_seconds"`
 }
 
+// MaxSlowmodeSeconds caps the slowmode setting at 6 hours — beyond that the
+// channel is effectively read-only and locking it via permissions is honest.
+const MaxSlowmodeSeconds = 21600
+
+// MaxDeleteAfterSeconds caps message auto-delete at 7 days. Anything the
+// channel wants kept longer than that is not ephemeral — it's retention
+// policy, which this setting is not.
+const MaxDeleteAfterSeconds = 604800
+
 func (r *UpdateChannelRequest) Validate() error {
 	if r.Name != nil {
 		*r.Name = strings.TrimSpace(*r.Name)
@@ -91,6 +164,25 @@ func (r *UpdateChannelRequest) Validate() error {
 		}
 	}
 
+	if r.Bitrate != nil && *r.Bitrate <= 0 {
+		return fmt.Errorf("bitrate must be positive")
+	}
+
+	if r.RegionHint != nil {
+		*r.RegionHint = strings.ToLower(strings.TrimSpace(*r.RegionHint))
+		if *r.RegionHint != "" && !ValidRegionHint(*r.RegionHint) {
+			return fmt.Errorf("region hint must be a lowercase token like \"eu-central\"")
+		}
+	}
+
+	if r.SlowmodeSeconds != nil && (*r.SlowmodeSeconds < 0 || *r.SlowmodeSeconds > MaxSlowmodeSeconds) {
+		return fmt.Errorf("slowmode must be between 0 and %d seconds", MaxSlowmodeSeconds)
+	}
+
+	if r.DeleteAfterSeconds != nil && (*r.DeleteAfterSeconds < 0 || *r.DeleteAfterSeconds > MaxDeleteAfterSeconds) {
+		return fmt.Errorf("delete_after must be between 0 and %d seconds", MaxDeleteAfterSeconds)
+	}
+
 	return nil
 }
 
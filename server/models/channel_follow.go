@@ -0,0 +1,31 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChannelFollow links a source channel to a target channel: new user messages
+// in the source are cross-posted into the target as 'crosspost' system
+// messages. One row per (source, target) pair — UNIQUE in the schema.
+type ChannelFollow struct {
+	ID              string    `json:"id"`
+	SourceChannelID string    `json:"source_channel_id"`
+	TargetChannelID string    `json:"target_channel_id"`
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// FollowChannelRequest is the body of POST .../channels/{channelId}/follows.
+// The path channel is the source being followed; the body names the target
+// that receives the cross-posts.
+type FollowChannelRequest struct {
+	TargetChannelID string `json:"target_channel_id"`
+}
+
+func (r *FollowChannelRequest) Validate() error {
+	if r.TargetChannelID == "" {
+		return fmt.Errorf("target_channel_id is required")
+	}
+	return nil
+}
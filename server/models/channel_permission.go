@@ -2,24 +2,39 @@ package models
 
 import "fmt"
 
-// ChannelPermissionOverride — per-channel role permission override.
+// Override target types — who a channel permission override applies to.
+const (
+	OverrideTargetRole   = "role"
+	OverrideTargetMember = "member"
+)
+
+// ChannelPermissionOverride — per-channel permission override for a role or a
+// specific member.
 //
 // Effective permission calculation (Discord algorithm):
 //   base = OR of all role permissions
-//   for each role: allow |= override.allow, deny |= override.deny
-//   effective = (base & ~deny) | allow
+//   for each role: roleAllow |= override.allow, roleDeny |= override.deny
+//   effective = (base & ~roleDeny) | roleAllow
+//   member override (if any) applies last: (effective & ~deny) | allow
 type ChannelPermissionOverride struct {
-	ChannelID string     `json:"channel_id"`
-	RoleID    string     `json:"role_id"`
-	Allow     Permission `json:"allow"`
-	Deny      Permission `json:"deny"`
+	ChannelID  string     `json:"channel_id"`
+	TargetType string     `json:"target_type"` // "role" | "member"
+	TargetID   string     `json:"target_id"`   // roles.id or users.id
+	Allow      Permission `json:"allow"`
+	Deny       Permission `json:"deny"`
+}
+
+// ValidOverrideTarget reports whether t is a known override target type.
+func ValidOverrideTarget(t string) bool {
+	return t == OverrideTargetRole || t == OverrideTargetMember
 }
 
 // ChannelOverridablePerms — only in-channel activity permissions can be overridden.
 // Server management perms (ManageChannels, ManageRoles, KickMembers, etc.) stay global.
 const ChannelOverridablePerms Permission = PermSendMessages | PermReadMessages |
-	PermManageMessages | PermConnectVoice | PermSpeak | PermStream | PermViewChannel |
-	PermMoveMembers | PermMuteMembers | PermDeafenMembers
+	PermManageMessages | PermConnectVoice | PermSpeak | PermStream | PermVideo |
+	PermViewChannel | PermMoveMembers | PermMuteMembers | PermDeafenMembers |
+	PermPrioritySpeaker
 
 type SetOverrideRequest struct {
 	Allow Permission `json:"allow"`
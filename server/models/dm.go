@@ -9,13 +9,16 @@ import (
 
 // DMChannel — user1_id < user2_id ordering is enforced by the service layer
 // to guarantee a single channel per user pair via UNIQUE constraint.
+// Group channels (IsGroup) leave the pair columns empty; membership lives in
+// dm_channel_members instead.
 type DMChannel struct {
 	ID            string     `json:"id"`
 	User1ID       string     `json:"user1_id"`
 	User2ID       string     `json:"user2_id"`
+	IsGroup       bool       `json:"is_group"`
 	E2EEEnabled   bool       `json:"e2ee_enabled"`
 	Status        string     `json:"status"`       // "accepted" or "pending"
-	InitiatedBy   *string    `json:"initiated_by"` // user ID who sent the request (only for pending)
+	InitiatedBy   *string    `json:"initiated_by"` // user ID who sent the request (only for pending); group creator for groups
 	CreatedAt     time.Time  `json:"created_at"`
 	LastMessageAt *time.Time `json:"last_message_at"`
 }
@@ -25,10 +28,45 @@ const (
 	DMStatusPending  = "pending"
 )
 
+// DMReadState is one user's watermark in one conversation (a dm_reads row).
+// LastReadAt carries the watermark message's timestamp, not the moment of reading.
+type DMReadState struct {
+	LastReadMessageID string    `json:"last_read_message_id"`
+	LastReadAt        time.Time `json:"last_read_at"`
+}
+
+// MaxGroupDMMembers caps a group DM, creator included. Past ~10 people a
+// conversation wants a server, with channels and roles.
+const MaxGroupDMMembers = 10
+
+// CreateGroupDMRequest — MemberIDs excludes the creator (taken from the session).
+type CreateGroupDMRequest struct {
+	MemberIDs []string `json:"member_ids"`
+}
+
+func (r *CreateGroupDMRequest) Validate() error {
+	if len(r.MemberIDs) == 0 {
+		return fmt.Errorf("member_ids is required")
+	}
+	if len(r.MemberIDs) > MaxGroupDMMembers-1 {
+		return fmt.Errorf("a group DM can have at most %d members", MaxGroupDMMembers)
+	}
+	for _, id := range r.MemberIDs {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("member ids cannot be empty")
+		}
+	}
+	return nil
+}
+
 // DMChannelWithUser includes the other participant's info for sidebar rendering.
+// For group channels OtherUser is nil and Members carries every participant —
+// the client renders the group title from those.
 type DMChannelWithUser struct {
 	ID            string     `json:"id"`
 	OtherUser     *User      `json:"other_user"`
+	IsGroup       bool       `json:"is_group"`
+	Members       []User     `json:"members,omitempty"`
 	E2EEEnabled   bool       `json:"e2ee_enabled"`
 	Status        string     `json:"status"`
 	InitiatedBy   *string    `json:"initiated_by"`
@@ -39,6 +77,16 @@ type DMChannelWithUser struct {
 	UnreadCount   int        `json:"unread_count"`
 }
 
+// DMUnreadSummary is the compact per-conversation row the ready event carries:
+// enough to badge and order the DM list after an offline stretch, without
+// shipping message history — the client fetches that when the DM is opened.
+type DMUnreadSummary struct {
+	DMChannelID   string     `json:"dm_channel_id"`
+	OtherUserID   string     `json:"other_user_id"`
+	UnreadCount   int        `json:"unread_count"`
+	LastMessageAt *time.Time `json:"last_message_at"`
+}
+
 // DM message types.
 const (
 	MessageTypeText = "text"
@@ -69,6 +117,8 @@ type DMMessage struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	ReplyToID   *string    `json:"reply_to_id"`
 	IsPinned    bool       `json:"is_pinned"`
+	// IsEmojiOnly is computed at read time, never stored — see Message.
+	IsEmojiOnly bool `json:"is_emoji_only,omitempty"`
 
 	// message_type distinguishes system messages from normal chat. "text" (default)
 	// is a normal/E2EE message; "call" is a plaintext P2P call-log entry.
@@ -203,6 +253,13 @@ type DMMessagePage struct {
 	HasMore  bool        `json:"has_more"`
 }
 
+// DMMessageContextPage mirrors MessageContextPage for DM jump-to-message.
+type DMMessageContextPage struct {
+	Messages  []DMMessage `json:"messages"`
+	HasBefore bool        `json:"has_before"`
+	HasAfter  bool        `json:"has_after"`
+}
+
 type DMSearchResult struct {
 	Messages   []DMMessage `json:"messages"`
 	TotalCount int         `json:"total_count"`
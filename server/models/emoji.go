@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxEmojiOnlyRunes caps how long a message can be and still count as
+// emoji-only. A screenful of emoji is a wall of text, not a reaction —
+// it renders at normal size like everything else.
+const maxEmojiOnlyRunes = 30
+
+// EmojiOnly reports whether content is nothing but emoji (plus the joiners,
+// modifiers and whitespace that hold emoji sequences together). The flag is
+// computed at read time so clients can render such messages oversized without
+// reimplementing Unicode classification per platform. Nil or blank content is
+// never emoji-only, and neither is an encrypted message — Content is nil there.
+func EmojiOnly(content *string) bool {
+	if content == nil {
+		return false
+	}
+	s := strings.TrimSpace(*content)
+	if s == "" {
+		return false
+	}
+
+	emoji := 0
+	runes := 0
+	for _, r := range s {
+		runes++
+		if runes > maxEmojiOnlyRunes {
+			return false
+		}
+		switch {
+		case isEmojiRune(r):
+			emoji++
+		case isEmojiModifier(r) || unicode.IsSpace(r):
+			// Joiners and modifiers don't count as emoji on their own —
+			// "U+200D" alone must not light the flag.
+		default:
+			return false
+		}
+	}
+	return emoji > 0
+}
+
+// isEmojiRune covers the blocks clients actually render as emoji. Keycap
+// sequences ("1️⃣") start with a plain digit and are deliberately out of
+// scope — classifying digits by lookahead isn't worth it for a render hint.
+func isEmojiRune(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		// The SMP emoji planes: emoticons, pictographs, transport, regional
+		// indicators (flags), supplemental and extended-A blocks.
+		return true
+	case r >= 0x2600 && r <= 0x27BF:
+		// Miscellaneous Symbols and Dingbats — ☀, ❤, ✂ and friends.
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF:
+		// Miscellaneous Symbols and Arrows — ⭐, ⬛.
+		return true
+	}
+	return false
+}
+
+// isEmojiModifier covers the glue runes inside emoji sequences: the zero-width
+// joiner (families), variation selector-16 (emoji presentation) and the
+// Fitzpatrick skin-tone modifiers.
+func isEmojiModifier(r rune) bool {
+	return r == 0x200D || r == 0xFE0F || (r >= 0x1F3FB && r <= 0x1F3FF)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEmojiOnly(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	cases := []struct {
+		name    string
+		content *string
+		want    bool
+	}{
+		{"nil content (E2EE)", nil, false},
+		{"empty string", str(""), false},
+		{"whitespace only", str("   \n"), false},
+		{"plain text", str("hello"), false},
+		{"single emoji", str("🔥"), true},
+		{"several emoji", str("🎉🎉🎉"), true},
+		{"emoji with spaces", str("👍 👍 👍"), true},
+		{"skin tone modifier", str("👍🏽"), true},
+		{"zwj family sequence", str("👩‍👩‍👧‍👦"), true},
+		{"heart with variation selector", str("❤️"), true},
+		{"flag sequence", str("🇹🇷"), true},
+		{"text mixed with emoji", str("nice 🔥"), false},
+		{"emoji then punctuation", str("🔥!"), false},
+		{"bare joiner is not emoji", str("‍"), false},
+		{"over the length cap", str(strings.Repeat("😀", maxEmojiOnlyRunes+1)), false},
+		{"exactly at the cap", str(strings.Repeat("😀", maxEmojiOnlyRunes)), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := EmojiOnly(tc.content); got != tc.want {
+				t.Errorf("EmojiOnly(%v) = %v, want %v", tc.content, got, tc.want)
+			}
+		})
+	}
+}
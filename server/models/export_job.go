@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Export archive formats. Both produce a zip; the format picks whether the
+// entries inside it are JSON arrays or CSV tables.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// Export job lifecycle. pending → running → completed | failed.
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// ExportJobTTL is how long a generated archive stays downloadable before the
+// cleanup sweep reclaims it.
+const ExportJobTTL = 24 * time.Hour
+
+// ExportJob tracks one server history export from kick-off to download.
+type ExportJob struct {
+	ID       string `json:"id"`
+	ServerID string `json:"server_id"`
+	UserID   string `json:"user_id"`
+	Format   string `json:"format"`
+	Status   string `json:"status"`
+	// FilePath is the archive's location on disk — never serialized. The
+	// archive is streamed by the owner-only download endpoint, not /api/files.
+	FilePath     *string    `json:"-"`
+	FileSize     int64      `json:"file_size"`
+	MessageCount int        `json:"message_count"`
+	Error        *string    `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// ValidExportFormat reports whether s is a supported export format.
+func ValidExportFormat(s string) bool {
+	return s == ExportFormatJSON || s == ExportFormatCSV
+}
@@ -36,6 +36,7 @@ type FriendshipWithUser struct {
 	AvatarURL       *string `json:"avatar_url"`
 	UserStatus      string  `json:"user_status"`
 	UserCustomStatus *string `json:"user_custom_status"`
+	UserCustomStatusEmoji *string `json:"user_custom_status_emoji"`
 }
 
 type SendFriendRequestRequest struct {
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// GroupCallParticipantState tracks one invitee's relationship to a group call.
+type GroupCallParticipantState string
+
+const (
+	GroupCallStateRinging  GroupCallParticipantState = "ringing"
+	GroupCallStateJoined   GroupCallParticipantState = "joined"
+	GroupCallStateDeclined GroupCallParticipantState = "declined"
+	GroupCallStateLeft     GroupCallParticipantState = "left"
+)
+
+// GroupCall — ephemeral, in-memory only, like P2PCall. Media flows through an
+// ad-hoc LiveKit room on a platform instance instead of a direct peer link, so
+// there is no per-pair SDP relay; the server only tracks who is in the room.
+type GroupCall struct {
+	ID        string      `json:"id"`
+	CallerID  string      `json:"caller_id"`
+	CallType  P2PCallType `json:"call_type"`
+	CreatedAt time.Time   `json:"created_at"`
+
+	// RoomName is the LiveKit room backing this call ("group:{callID}" on the
+	// reserved platform instance).
+	RoomName string `json:"-"`
+
+	// Participants covers everyone who was ever rung, caller included (the
+	// caller starts joined). Keys stay after decline/leave so a late accept
+	// of an ended invitation can be answered precisely.
+	Participants map[string]GroupCallParticipantState `json:"participants"`
+}
+
+// GroupCallParticipantInfo is the broadcast view of one participant.
+type GroupCallParticipantInfo struct {
+	UserID      string                    `json:"user_id"`
+	Username    string                    `json:"username"`
+	DisplayName *string                   `json:"display_name"`
+	AvatarURL   *string                   `json:"avatar_url"`
+	State       GroupCallParticipantState `json:"state"`
+}
+
+// GroupCallBroadcast — payload for group call ring/update events.
+type GroupCallBroadcast struct {
+	ID           string                     `json:"id"`
+	CallerID     string                     `json:"caller_id"`
+	CallType     P2PCallType                `json:"call_type"`
+	CreatedAt    time.Time                  `json:"created_at"`
+	Participants []GroupCallParticipantInfo `json:"participants"`
+}
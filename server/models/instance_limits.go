@@ -0,0 +1,33 @@
+package models
+
+// InstanceLimits is the resolved limits snapshot served to platform admins
+// (GET /api/admin/limits): every cap currently in force, pulled from the loaded
+// config and the compiled-in constants, so an operator can verify settings
+// without reading env vars on the host. Read-only — changing a limit still goes
+// through the environment and a restart. Zero means unlimited where noted.
+type InstanceLimits struct {
+	MaxMqviHostedServersPerUser int `json:"max_mqvi_hosted_servers_per_user"`
+	ServerCreatePerHour         int `json:"server_create_per_hour"`
+	// MaxVoiceParticipants: 0 = no cap.
+	MaxVoiceParticipants int   `json:"max_voice_participants"`
+	MaxPinsPerChannel    int   `json:"max_pins_per_channel"`
+	MaxReplyChainDepth   int   `json:"max_reply_chain_depth"`
+	UploadMaxSizeBytes   int64 `json:"upload_max_size_bytes"`
+	StorageQuotaBytes    int64 `json:"storage_quota_bytes"`
+
+	FileRateLimitUserPerMin int `json:"file_rate_limit_user_per_min"`
+	FileRateLimitIPPerMin   int `json:"file_rate_limit_ip_per_min"`
+
+	MaxReactionsPerUserPerMessage int `json:"max_reactions_per_user_per_message"`
+	MaxReactionEmojisPerMessage   int `json:"max_reaction_emojis_per_message"`
+	// MaxMessageLines / MaxMessageBlankLines: 0 = no shape limit.
+	MaxMessageLines      int `json:"max_message_lines"`
+	MaxMessageBlankLines int `json:"max_message_blank_lines"`
+
+	VoiceBitrateMin       int `json:"voice_bitrate_min"`
+	VoiceBitrateMaxHosted int `json:"voice_bitrate_max_hosted"`
+	// VoiceBitrateMaxSelfHosted: 0 = uncapped.
+	VoiceBitrateMaxSelfHosted int `json:"voice_bitrate_max_self_hosted"`
+
+	DefaultMemberPermissions Permission `json:"default_member_permissions"`
+}
@@ -13,6 +13,7 @@ type Invite struct {
 	CreatedBy *string    `json:"created_by"`
 	MaxUses   int        `json:"max_uses"`   // 0 = unlimited
 	Uses      int        `json:"uses"`
+	Temporary bool       `json:"temporary"`  // joiners are removed on disconnect unless granted a non-default role
 	ExpiresAt *time.Time `json:"expires_at"` // nil = never expires
 	CreatedAt time.Time  `json:"created_at"`
 }
@@ -21,6 +22,9 @@ type InviteWithCreator struct {
 	Invite
 	CreatorUsername    string  `json:"creator_username"`
 	CreatorDisplayName *string `json:"creator_display_name"`
+	// RemainingUses = max_uses - uses, computed by the service for the invite
+	// list UI. nil for unlimited invites.
+	RemainingUses      *int    `json:"remaining_uses"`
 }
 
 // InvitePreview is a public (no auth required) preview for invite cards in chat.
@@ -31,8 +35,10 @@ type InvitePreview struct {
 }
 
 type CreateInviteRequest struct {
-	MaxUses   int `json:"max_uses"`   // 0 = unlimited
-	ExpiresIn int `json:"expires_in"` // minutes, 0 = never
+	MaxUses          int  `json:"max_uses"`           // 0 = unlimited
+	ExpiresIn        int  `json:"expires_in"`         // minutes, 0 = never
+	ExpiresInSeconds int  `json:"expires_in_seconds"` // finer-grained alternative; wins when both are set
+	Temporary        bool `json:"temporary"`          // members who joined via this invite are removed on disconnect
 }
 
 func (r *CreateInviteRequest) Validate() error {
@@ -42,5 +48,8 @@ func (r *CreateInviteRequest) Validate() error {
 	if r.ExpiresIn < 0 {
 		return fmt.Errorf("expires_in cannot be negative")
 	}
+	if r.ExpiresInSeconds < 0 {
+		return fmt.Errorf("expires_in_seconds cannot be negative")
+	}
 	return nil
 }
@@ -0,0 +1,48 @@
+// Package models — KeywordAlert domain model.
+// A keyword alert highlights a user when a word they care about appears in a
+// message, without an @mention. Keywords are stored lowercased and matched
+// against whole words only.
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxKeywordAlertLength caps a single keyword. MaxKeywordAlertsPerUser caps how
+// many one user may register — the set is consulted on every channel message, so
+// it has to stay small.
+const (
+	MaxKeywordAlertLength   = 64
+	MaxKeywordAlertsPerUser = 50
+)
+
+// KeywordAlert represents one highlight word registered by a user.
+type KeywordAlert struct {
+	UserID    string    `json:"user_id"`
+	Keyword   string    `json:"keyword"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddKeywordAlertRequest is the request body for registering a keyword.
+type AddKeywordAlertRequest struct {
+	Keyword string `json:"keyword"`
+}
+
+// Validate normalizes the keyword (trim + lowercase) and rejects empty,
+// multi-word, and over-long terms. Single words only — matching is against a
+// per-message word set, which a phrase could never hit.
+func (r *AddKeywordAlertRequest) Validate() error {
+	r.Keyword = strings.ToLower(strings.TrimSpace(r.Keyword))
+	if r.Keyword == "" {
+		return fmt.Errorf("keyword is required")
+	}
+	if len(r.Keyword) > MaxKeywordAlertLength {
+		return fmt.Errorf("keyword must be at most %d characters", MaxKeywordAlertLength)
+	}
+	if strings.ContainsAny(r.Keyword, " \t\n") {
+		return fmt.Errorf("keyword must be a single word")
+	}
+	return nil
+}
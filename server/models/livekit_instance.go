@@ -9,15 +9,18 @@ import (
 // LiveKitInstance — credentials are stored AES-256-GCM encrypted in DB.
 // Values here are decrypted; json:"-" prevents them from ever reaching the client.
 type LiveKitInstance struct {
-	ID                string    `json:"id"`
-	URL               string    `json:"url"`
-	APIKey            string    `json:"-"`
-	APISecret         string    `json:"-"`
-	IsPlatformManaged bool      `json:"is_platform_managed"`
-	ServerCount       int       `json:"server_count"`
-	MaxServers        int       `json:"max_servers"` // 0 = unlimited
-	HetznerServerID   string    `json:"hetzner_server_id"`
-	CreatedAt         time.Time `json:"created_at"`
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	APIKey            string `json:"-"`
+	APISecret         string `json:"-"`
+	IsPlatformManaged bool   `json:"is_platform_managed"`
+	ServerCount       int    `json:"server_count"`
+	MaxServers        int    `json:"max_servers"` // 0 = unlimited
+	// IsDraining excludes the instance from new-server assignment while existing
+	// servers keep working — set before migrating them off and decommissioning.
+	IsDraining      bool      `json:"is_draining"`
+	HetznerServerID string    `json:"hetzner_server_id"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // LiveKitInstanceAdminView — credentials are NEVER exposed, even to admins.
@@ -27,6 +30,7 @@ type LiveKitInstanceAdminView struct {
 	IsPlatformManaged bool      `json:"is_platform_managed"`
 	ServerCount       int       `json:"server_count"`
 	MaxServers        int       `json:"max_servers"`
+	IsDraining        bool      `json:"is_draining"`
 	HetznerServerID   string    `json:"hetzner_server_id"`
 	CreatedAt         time.Time `json:"created_at"`
 }
@@ -65,6 +69,7 @@ type UpdateLiveKitInstanceRequest struct {
 	APIKey          *string `json:"api_key"`
 	APISecret       *string `json:"api_secret"`
 	MaxServers      *int    `json:"max_servers"`
+	IsDraining      *bool   `json:"is_draining"`
 	HetznerServerID *string `json:"hetzner_server_id"`
 }
 
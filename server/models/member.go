@@ -25,6 +25,38 @@ type MemberWithRoles struct {
 	IsHardDeleted        bool       `json:"is_hard_deleted,omitempty"`
 	Roles                []Role     `json:"roles"`
 	EffectivePermissions Permission `json:"effective_permissions"`
+	// TimeoutUntil is the end of an active moderation timeout (nil when none).
+	// Membership-scoped, so only set on paths that know the server.
+	TimeoutUntil *time.Time `json:"timeout_until,omitempty"`
+	// Nickname is the per-server display name (nil when unset). Membership-scoped
+	// like TimeoutUntil — global paths (e.g. profile-update broadcasts) leave it nil.
+	Nickname *string `json:"nickname,omitempty"`
+}
+
+// MemberListParams narrows and pages the member list. After is the user ID of
+// the previous page's last member (keyset over joined_at, then user ID) —
+// empty means first page. Q filters by username/display-name substring and
+// RoleID keeps only holders of that role.
+type MemberListParams struct {
+	Limit  int
+	After  string
+	Q      string
+	RoleID string
+}
+
+// MemberListPage is one page of members plus the total under the same filters.
+// A page shorter than the requested limit is the last one.
+type MemberListPage struct {
+	Members []MemberWithRoles `json:"members"`
+	Total   int               `json:"total"`
+}
+
+// MemberPageRow couples a user with the membership-scoped columns the member
+// view needs, so the page query does not fan out into per-user lookups.
+type MemberPageRow struct {
+	User         User
+	TimeoutUntil *time.Time
+	Nickname     *string
 }
 
 // ToMemberWithRoles builds a MemberWithRoles from a User and their roles.
@@ -78,6 +110,7 @@ var allowedDMPrivacy = map[string]bool{
 	"everyone":        true,
 	"message_request": true,
 	"friends_only":    true,
+	"shared_servers":  true,
 }
 
 func (r *UpdateProfileRequest) Validate() error {
@@ -94,8 +127,14 @@ func (r *UpdateProfileRequest) Validate() error {
 			}
 		}
 	}
-	if r.DisplayName != nil && utf8.RuneCountInString(*r.DisplayName) > 32 {
-		return fmt.Errorf("display name must be at most 32 characters")
+	if r.DisplayName != nil {
+		// Normalized result replaces the raw input — an emptied name (all zero-width
+		// characters) falls through to the "remove display name" path downstream.
+		name, err := NormalizeDisplayName(*r.DisplayName)
+		if err != nil {
+			return err
+		}
+		r.DisplayName = &name
 	}
 	if r.CustomStatus != nil && utf8.RuneCountInString(*r.CustomStatus) > 128 {
 		return fmt.Errorf("custom status must be at most 128 characters")
@@ -109,6 +148,38 @@ func (r *UpdateProfileRequest) Validate() error {
 	return nil
 }
 
+// MaxCustomStatusDuration caps clear_after_seconds — a "brb" that outlives a
+// week was never meant to auto-clear anyway.
+const MaxCustomStatusDuration = 7 * 24 * time.Hour
+
+// UpdateCustomStatusRequest sets or clears the user's custom status message.
+// Empty text and emoji together clear it. clear_after_seconds = 0 keeps the
+// status until the user changes it.
+type UpdateCustomStatusRequest struct {
+	Text              string `json:"text"`
+	Emoji             string `json:"emoji"`
+	ClearAfterSeconds int    `json:"clear_after_seconds"`
+}
+
+func (r *UpdateCustomStatusRequest) Validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+	if utf8.RuneCountInString(r.Text) > 128 {
+		return fmt.Errorf("custom status must be at most 128 characters")
+	}
+	// A single emoji, but grapheme clusters (flags, skin tones) span several
+	// runes — cap generously instead of counting glyphs.
+	if utf8.RuneCountInString(r.Emoji) > 16 {
+		return fmt.Errorf("custom status emoji is too long")
+	}
+	if r.ClearAfterSeconds < 0 {
+		return fmt.Errorf("clear_after_seconds cannot be negative")
+	}
+	if time.Duration(r.ClearAfterSeconds)*time.Second > MaxCustomStatusDuration {
+		return fmt.Errorf("custom status cannot auto-clear later than %d days out", int(MaxCustomStatusDuration.Hours()/24))
+	}
+	return nil
+}
+
 // RoleModifyRequest uses a declarative approach — the full target role list
 // is sent, and the service diffs against current roles.
 type RoleModifyRequest struct {
@@ -122,6 +193,62 @@ func (r *RoleModifyRequest) Validate() error {
 	return nil
 }
 
+// MaxTimeoutDuration caps how far out a member timeout can be set.
+const MaxTimeoutDuration = 28 * 24 * time.Hour
+
+// TimeoutRequest sets or clears a member's timeout. duration_seconds = 0 clears it.
+type TimeoutRequest struct {
+	DurationSeconds int `json:"duration_seconds"`
+}
+
+func (r *TimeoutRequest) Validate() error {
+	if r.DurationSeconds < 0 {
+		return fmt.Errorf("duration_seconds cannot be negative")
+	}
+	if time.Duration(r.DurationSeconds)*time.Second > MaxTimeoutDuration {
+		return fmt.Errorf("timeout cannot exceed %d days", int(MaxTimeoutDuration.Hours()/24))
+	}
+	return nil
+}
+
+// MaxNicknameLength caps per-server nicknames (matches the username cap).
+const MaxNicknameLength = 32
+
+// UpdateNicknameRequest sets or removes a member's per-server nickname.
+// nil removes the nickname; an empty or whitespace-only string is rejected —
+// "no name" is expressed by removal, never by a blank name.
+type UpdateNicknameRequest struct {
+	Nickname *string `json:"nickname"`
+}
+
+func (r *UpdateNicknameRequest) Validate() error {
+	if r.Nickname == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*r.Nickname)
+	if trimmed == "" {
+		return fmt.Errorf("nickname cannot be blank — send null to remove it")
+	}
+	if utf8.RuneCountInString(trimmed) > MaxNicknameLength {
+		return fmt.Errorf("nickname must be at most %d characters", MaxNicknameLength)
+	}
+	r.Nickname = &trimmed
+	return nil
+}
+
+// EffectiveMemberName resolves the name shown in a server context:
+// nickname > global display_name > username. Only display is server-scoped —
+// mentions keep resolving by the real username.
+func EffectiveMemberName(nickname, displayName *string, username string) string {
+	if nickname != nil && *nickname != "" {
+		return *nickname
+	}
+	if displayName != nil && *displayName != "" {
+		return *displayName
+	}
+	return username
+}
+
 // HighestPosition returns the highest role position in the list.
 // Owner role returns math.MaxInt32 to always outrank any position value.
 func HighestPosition(roles []Role) int {
@@ -11,6 +11,74 @@ import (
 // Must match the frontend constant in client/src/utils/constants.ts.
 const MaxMessageLength = 999
 
+// MaxReplyChainDepth caps how long a reply-to chain may grow before new
+// replies to its tip are rejected. The repositories also use it to bound the
+// recursive chain walk, so a corrupted cycle terminates instead of looping.
+const MaxReplyChainDepth = 20
+
+// MessageFormatLimits caps message shape beyond the raw character limit — the
+// character cap alone still admits a 999-line wall of newlines. Values come
+// from config; a zero value disables that limit. Shared by channel and DM
+// message paths.
+type MessageFormatLimits struct {
+	// MaxLines caps the number of lines in one message.
+	MaxLines int
+	// MaxBlankRun caps consecutive blank lines; longer runs are collapsed to
+	// this length rather than rejected.
+	MaxBlankRun int
+}
+
+// NormalizeFormatting applies the limits to plaintext content: blank-line runs
+// beyond MaxBlankRun are collapsed silently, content over MaxLines is rejected.
+// Callers must not pass ciphertext — the server can't reshape what it can't read.
+func (l MessageFormatLimits) NormalizeFormatting(content string) (string, error) {
+	if l.MaxLines <= 0 && l.MaxBlankRun <= 0 {
+		return content, nil
+	}
+
+	lines := strings.Split(content, "\n")
+
+	if l.MaxBlankRun > 0 {
+		kept := lines[:0]
+		blankRun := 0
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				blankRun++
+				if blankRun > l.MaxBlankRun {
+					continue
+				}
+			} else {
+				blankRun = 0
+			}
+			kept = append(kept, line)
+		}
+		lines = kept
+	}
+
+	if l.MaxLines > 0 && len(lines) > l.MaxLines {
+		return "", fmt.Errorf("message must be at most %d lines", l.MaxLines)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ChannelStats — message totals for the channel info panel and export planning.
+// Timestamps are nil for channels with no messages.
+type ChannelStats struct {
+	ChannelID      string     `json:"channel_id"`
+	MessageCount   int        `json:"message_count"`
+	FirstMessageAt *time.Time `json:"first_message_at"`
+	LastMessageAt  *time.Time `json:"last_message_at"`
+}
+
+// MessageEdit is one stored prior revision of an edited message (channel or DM —
+// both history tables share this shape). Content is the text the message had
+// BEFORE the edit; EditedAt is when that edit happened.
+type MessageEdit struct {
+	Content  string    `json:"content"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
 // MessageReference holds a preview of the replied-to message.
 // If the original message was deleted, Author and Content will be nil.
 type MessageReference struct {
@@ -19,23 +87,80 @@ type MessageReference struct {
 	Content *string `json:"content"`
 }
 
+// MessageType distinguishes user-authored messages from server-generated ones.
+// System messages render from their type ("X pinned a message"); Content is the
+// plain-text fallback.
+type MessageType string
+
+const (
+	MessageTypeDefault       MessageType = "default"
+	MessageTypePin           MessageType = "pin"
+	MessageTypeCrosspost     MessageType = "crosspost"
+	MessageTypeJoin          MessageType = "join"           // "X joined the server"
+	MessageTypeChannelCreate MessageType = "channel_create" // "X created the channel"
+)
+
+// Mention scopes — "@everyone" pings the whole server, "@here" only the
+// members online at send time. Both are gated on PermMentionEveryone and
+// stored on the message so clients can tell a mass ping from a direct one.
+const (
+	MentionScopeEveryone = "everyone"
+	MentionScopeHere     = "here"
+)
+
+// Mention kinds stored per mention row. Direct rows come from <@userId>
+// tokens; "here" rows are the online members an @here expanded to at send
+// time.
+const (
+	MentionKindDirect = "direct"
+	MentionKindHere   = "here"
+)
+
+// IsSystem reports whether the message was generated by the server rather than
+// typed by a user. The empty string counts as default — the column has always
+// been NOT NULL DEFAULT 'default', so "" only appears on rows built in memory.
+func (t MessageType) IsSystem() bool {
+	return t != "" && t != MessageTypeDefault
+}
+
 type Message struct {
 	ID          string       `json:"id"`
 	ChannelID   string       `json:"channel_id"`
 	UserID      string       `json:"user_id"`
+	Type        MessageType  `json:"type"`
 	// ServerID is a transient field (not stored in DB) populated before WS broadcast
 	// so clients know which server a message belongs to without a channel lookup.
 	ServerID    string       `json:"server_id,omitempty"`
 	Content     *string      `json:"content"`
 	EditedAt    *time.Time   `json:"edited_at"`
 	CreatedAt   time.Time    `json:"created_at"`
+	// ExpiresAt is the auto-delete deadline, fixed at create time from the
+	// channel's delete_after_seconds or a per-message override. Nil = kept.
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
 	ReplyToID   *string      `json:"reply_to_id"`
 	Author      *User        `json:"author,omitempty"`       // populated via JOIN
 	Attachments []Attachment    `json:"attachments,omitempty"`
 	Mentions     []string        `json:"mentions"`
 	RoleMentions []string        `json:"role_mentions"`
+	// MentionScope is MentionScopeEveryone/Here when the author mass-pinged
+	// (and held PermMentionEveryone); nil otherwise.
+	MentionScope *string         `json:"mention_scope,omitempty"`
 	Reactions   []ReactionGroup `json:"reactions"`
 	ReferencedMessage *MessageReference `json:"referenced_message,omitempty"`
+	// Embed is the unfurled preview of the first link in the message — written
+	// after create by the async unfurl, so fresh messages arrive without it and
+	// clients patch it in from OpMessageEmbedUpdate.
+	Embed *MessageEmbed `json:"embed,omitempty"`
+	// IsEmojiOnly is computed at read time, never stored: true when Content is
+	// nothing but emoji, so clients can render it oversized.
+	IsEmojiOnly bool `json:"is_emoji_only,omitempty"`
+
+	// Webhook identity, denormalized at send time. Set only on messages posted
+	// through a webhook; UserID then holds the webhook's creator and clients
+	// render the webhook name/avatar instead of the author.
+	WebhookID        *string `json:"webhook_id,omitempty"`
+	WebhookName      *string `json:"webhook_name,omitempty"`
+	WebhookAvatarURL *string `json:"webhook_avatar_url,omitempty"`
 
 	// E2EE fields — when encryption_version > 0, Content is nil
 	// and the payload is in Ciphertext (opaque base64 blob).
@@ -68,12 +193,24 @@ type MessagePage struct {
 	HasMore  bool      `json:"has_more"`
 }
 
+// MessageContextPage is the jump-to-message response: the target message with
+// its surrounding context, ascending. The flags tell the client whether
+// scrolling past either edge of the window has more history to load.
+type MessageContextPage struct {
+	Messages  []Message `json:"messages"`
+	HasBefore bool      `json:"has_before"`
+	HasAfter  bool      `json:"has_after"`
+}
+
 // CreateMessageRequest — E2EE: when encryption_version=1, ciphertext is
 // required and content may be empty. HasFiles is set by the service layer.
 type CreateMessageRequest struct {
 	Content   string  `json:"content"`
 	ReplyToID *string `json:"reply_to_id,omitempty"`
 	HasFiles  bool    `json:"-"`
+	// DeleteAfterSeconds sets this one message's TTL, overriding the channel's
+	// delete_after_seconds either way. Nil inherits the channel setting.
+	DeleteAfterSeconds *int `json:"delete_after_seconds,omitempty"`
 
 	EncryptionVersion int     `json:"encryption_version"`
 	Ciphertext        *string `json:"ciphertext,omitempty"`
@@ -85,6 +222,10 @@ func (r *CreateMessageRequest) Validate() error {
 	r.Content = strings.TrimSpace(r.Content)
 	contentLen := utf8.RuneCountInString(r.Content)
 
+	if r.DeleteAfterSeconds != nil && (*r.DeleteAfterSeconds < 0 || *r.DeleteAfterSeconds > MaxDeleteAfterSeconds) {
+		return fmt.Errorf("delete_after must be between 0 and %d seconds", MaxDeleteAfterSeconds)
+	}
+
 	if r.EncryptionVersion == 1 {
 		if r.Ciphertext == nil || *r.Ciphertext == "" {
 			return fmt.Errorf("ciphertext is required for encrypted messages")
@@ -109,6 +250,34 @@ func (r *CreateMessageRequest) Validate() error {
 	return nil
 }
 
+// MaxBulkDeleteMessages caps one moderation bulk-delete. Big enough to clear a
+// raid burst, small enough that a bad request can't wipe a channel's history.
+const MaxBulkDeleteMessages = 100
+
+// BulkDeleteMessagesRequest names messages explicitly, or — for raid cleanup —
+// targets everything one user posted in the channel within the last WithinMinutes.
+// Exactly one of the two forms must be used.
+type BulkDeleteMessagesRequest struct {
+	MessageIDs    []string `json:"message_ids"`
+	UserID        string   `json:"user_id"`
+	WithinMinutes int      `json:"within_minutes"`
+}
+
+func (r *BulkDeleteMessagesRequest) Validate() error {
+	byIDs := len(r.MessageIDs) > 0
+	byUser := r.UserID != ""
+	if byIDs == byUser {
+		return fmt.Errorf("provide either message_ids or user_id, not both")
+	}
+	if len(r.MessageIDs) > MaxBulkDeleteMessages {
+		return fmt.Errorf("cannot delete more than %d messages at once", MaxBulkDeleteMessages)
+	}
+	if byUser && (r.WithinMinutes < 1 || r.WithinMinutes > 24*60) {
+		return fmt.Errorf("within_minutes must be between 1 and %d", 24*60)
+	}
+	return nil
+}
+
 type UpdateMessageRequest struct {
 	Content string `json:"content"`
 
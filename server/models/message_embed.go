@@ -0,0 +1,15 @@
+// Package models — MessageEmbed, the unfurled link preview pinned to a message.
+package models
+
+// MessageEmbed is the Open Graph metadata unfurled from the first URL in a
+// message. At most one per message, written asynchronously after create —
+// clients receive it via OpMessageEmbedUpdate once the fetch completes.
+type MessageEmbed struct {
+	MessageID   string  `json:"message_id"`
+	URL         string  `json:"url"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url"`
+	SiteName    *string `json:"site_name"`
+	FaviconURL  *string `json:"favicon_url"`
+}
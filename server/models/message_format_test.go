@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// Shape limits exist because the character cap alone admits a wall of newlines:
+// blank-line runs collapse silently, line-count overruns reject.
+func TestMessageFormatLimits_NormalizeFormatting(t *testing.T) {
+	limits := MessageFormatLimits{MaxLines: 5, MaxBlankRun: 2}
+
+	got, err := limits.NormalizeFormatting("a\n\n\n\nb")
+	if err != nil {
+		t.Fatalf("blank-run collapse should not error: %v", err)
+	}
+	if got != "a\n\n\nb" {
+		t.Fatalf("expected run collapsed to 2 blanks, got %q", got)
+	}
+
+	// Whitespace-only lines count as blank — they render identically.
+	got, err = limits.NormalizeFormatting("a\n \n\t\n  \nb")
+	if err != nil {
+		t.Fatalf("whitespace-blank collapse should not error: %v", err)
+	}
+	if strings.Count(got, "\n") != 3 {
+		t.Fatalf("expected whitespace-only lines collapsed, got %q", got)
+	}
+
+	if _, err := limits.NormalizeFormatting("1\n2\n3\n4\n5\n6"); err == nil {
+		t.Fatal("expected rejection for content over MaxLines")
+	}
+
+	// Collapsing runs first can bring an over-long message back under the cap —
+	// trimming is preferred over rejection where it suffices.
+	got, err = limits.NormalizeFormatting("a\n\n\n\n\n\n\nb")
+	if err != nil {
+		t.Fatalf("expected collapse to rescue the message, got error: %v", err)
+	}
+	if got != "a\n\n\nb" {
+		t.Fatalf("unexpected normalized content %q", got)
+	}
+
+	// Zero limits disable everything.
+	wall := strings.Repeat("\n", 50)
+	got, err = (MessageFormatLimits{}).NormalizeFormatting(wall)
+	if err != nil || got != wall {
+		t.Fatalf("zero limits must be a no-op, got %q err %v", got, err)
+	}
+}
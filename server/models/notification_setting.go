@@ -0,0 +1,43 @@
+package models
+
+import "fmt"
+
+// Notification scopes — what a settings row applies to.
+const (
+	NotificationScopeServer  = "server"
+	NotificationScopeChannel = "channel"
+)
+
+// Notification levels — how much of a scope may notify. A channel row overrides
+// its server's row; no row at all means NotificationLevelAll.
+const (
+	NotificationLevelAll      = "all"
+	NotificationLevelMentions = "mentions"
+	NotificationLevelNothing  = "nothing"
+)
+
+// NotificationSetting is one user's preference for one scope (a notification_settings row).
+type NotificationSetting struct {
+	ScopeType string `json:"scope_type"`
+	ScopeID   string `json:"scope_id"`
+	Level     string `json:"level"`
+	// SuppressEveryone silences @everyone-style pings. Only meaningful on server scope.
+	SuppressEveryone bool `json:"suppress_everyone"`
+}
+
+type UpdateNotificationSettingRequest struct {
+	Level            string `json:"level"`
+	SuppressEveryone bool   `json:"suppress_everyone"`
+}
+
+func (r *UpdateNotificationSettingRequest) Validate() error {
+	if r.Level == "" {
+		r.Level = NotificationLevelAll
+	}
+	switch r.Level {
+	case NotificationLevelAll, NotificationLevelMentions, NotificationLevelNothing:
+		return nil
+	default:
+		return fmt.Errorf("level must be one of: all, mentions, nothing")
+	}
+}
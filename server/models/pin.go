@@ -1,14 +1,20 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // PinnedMessage — MessageID has a UNIQUE constraint (one pin per message).
+// PinPosition orders the pin panel (0 = top, ties broken by created_at DESC);
+// it stays 0 until a moderator reorders the channel's pins.
 type PinnedMessage struct {
-	ID        string    `json:"id"`
-	MessageID string    `json:"message_id"`
-	ChannelID string    `json:"channel_id"`
-	PinnedBy  string    `json:"pinned_by"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	MessageID   string    `json:"message_id"`
+	ChannelID   string    `json:"channel_id"`
+	PinnedBy    string    `json:"pinned_by"`
+	PinPosition int       `json:"pin_position"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // PinnedMessageWithDetails includes the full message and who pinned it.
@@ -17,3 +23,26 @@ type PinnedMessageWithDetails struct {
 	Message  *Message `json:"message"`
 	PinnedByUser *User `json:"pinned_by_user,omitempty"`
 }
+
+// ReorderPinsRequest is an ordered list of pinned message IDs, first = top.
+// Pins not listed keep their positions.
+type ReorderPinsRequest struct {
+	MessageIDs []string `json:"message_ids"`
+}
+
+func (r *ReorderPinsRequest) Validate() error {
+	if len(r.MessageIDs) == 0 {
+		return fmt.Errorf("message_ids cannot be empty")
+	}
+	seen := make(map[string]bool, len(r.MessageIDs))
+	for _, id := range r.MessageIDs {
+		if id == "" {
+			return fmt.Errorf("message id cannot be empty")
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate message id: %s", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package models
+
+import "fmt"
+
+// MaxPresenceQueryIDs caps one batch presence query. A friends list or member
+// sidebar refresh asks about at most a screenful of people; anything larger is
+// a scrape.
+const MaxPresenceQueryIDs = 200
+
+// BatchPresenceRequest asks for the current status of a specific set of users —
+// the catch-up path for lists that loaded after the ready event, where waiting
+// for the next presence broadcast would show stale indicators.
+type BatchPresenceRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+func (r *BatchPresenceRequest) Validate() error {
+	if len(r.UserIDs) == 0 {
+		return fmt.Errorf("user_ids is required")
+	}
+	if len(r.UserIDs) > MaxPresenceQueryIDs {
+		return fmt.Errorf("at most %d user ids per query", MaxPresenceQueryIDs)
+	}
+	return nil
+}
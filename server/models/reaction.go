@@ -12,9 +12,45 @@ type Reaction struct {
 }
 
 // ReactionGroup aggregates reactions by emoji for API responses.
-// Users list lets the frontend highlight "you reacted" state.
+// Users list lets the frontend highlight "you reacted" state. Repositories
+// always return the full list (the toggle limit checks depend on it); API
+// responses trim it with PreviewReactions and the reactors endpoint serves
+// the full, detailed list on demand.
 type ReactionGroup struct {
 	Emoji string   `json:"emoji"`
 	Count int      `json:"count"`
 	Users []string `json:"users"`
 }
+
+// ReactionUserPreview is how many user IDs a trimmed ReactionGroup keeps.
+const ReactionUserPreview = 3
+
+// PreviewReactions copies the groups with Users cut down to the first
+// ReactionUserPreview reactors. Count stays the real total.
+func PreviewReactions(groups []ReactionGroup) []ReactionGroup {
+	if groups == nil {
+		return nil
+	}
+	out := make([]ReactionGroup, len(groups))
+	for i, g := range groups {
+		if len(g.Users) > ReactionUserPreview {
+			g.Users = g.Users[:ReactionUserPreview]
+		}
+		out[i] = g
+	}
+	return out
+}
+
+// ReactionUser is one entry in the detailed reactors list.
+type ReactionUser struct {
+	ID          string  `json:"id"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+// ReactionUsersPage is one offset page of reactors plus the total for the emoji.
+type ReactionUsersPage struct {
+	Users []ReactionUser `json:"users"`
+	Total int            `json:"total"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestPreviewReactions_TrimsUsersKeepsCount(t *testing.T) {
+	groups := []ReactionGroup{
+		{Emoji: "👍", Count: 5, Users: []string{"u1", "u2", "u3", "u4", "u5"}},
+		{Emoji: "🎉", Count: 2, Users: []string{"u1", "u2"}},
+	}
+
+	got := PreviewReactions(groups)
+
+	if len(got[0].Users) != ReactionUserPreview || got[0].Count != 5 {
+		t.Errorf("trimmed group = %d users, count %d; want %d users, count 5",
+			len(got[0].Users), got[0].Count, ReactionUserPreview)
+	}
+	if len(got[1].Users) != 2 {
+		t.Errorf("short group trimmed to %d users, want 2 untouched", len(got[1].Users))
+	}
+
+	// The originals must stay intact — the toggle limit checks read them.
+	if len(groups[0].Users) != 5 {
+		t.Errorf("input group mutated: %d users left", len(groups[0].Users))
+	}
+
+	if PreviewReactions(nil) != nil {
+		t.Error("nil input should stay nil")
+	}
+}
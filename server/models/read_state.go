@@ -14,8 +14,29 @@ type ReadState struct {
 // UnreadInfo — sidebar unread badges. Mention watermark is the tuple
 // (LastMentionSeenAt, LastMentionSeenMessageID) compared lexicographically.
 type UnreadInfo struct {
-	ChannelID                string     `json:"channel_id"`
-	UnreadCount              int        `json:"unread_count"`
+	ChannelID   string `json:"channel_id"`
+	UnreadCount int    `json:"unread_count"`
+	// MentionCount is how many of the unread messages mention the user — the red
+	// badge, as opposed to the generic unread dot UnreadCount drives.
+	MentionCount             int        `json:"mention_count"`
 	LastMentionSeenAt        *time.Time `json:"last_mention_seen_at"`
 	LastMentionSeenMessageID *string    `json:"last_mention_seen_message_id"`
+	// NotificationsMuted: the channel is muted directly, via its category, or via the
+	// server. The client keeps the unread dot but drops the mention badge/notification.
+	NotificationsMuted bool `json:"notifications_muted,omitempty"`
+}
+
+// UnreadSummary is the per-server unread payload: the channel rows plus the
+// mention total the server-level badge shows. Muted channels keep their row
+// but stay out of the total.
+type UnreadSummary struct {
+	Channels      []UnreadInfo `json:"channels"`
+	TotalMentions int          `json:"total_mentions"`
+}
+
+// ServerUnreadInfo — UnreadInfo plus the owning server, for the cross-server
+// snapshot (GET /api/unreads). The client buckets rows by ServerID.
+type ServerUnreadInfo struct {
+	ServerID string `json:"server_id"`
+	UnreadInfo
 }
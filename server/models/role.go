@@ -28,10 +28,29 @@ const (
 	PermUseSoundboard                          // 65536
 	PermManageSoundboard                       // 131072
 	PermApproveMembers                         // 262144
+	PermVideo                                  // 524288
+	PermViewAuditLog                           // 1048576
+	PermModerateMembers                        // 2097152
+	PermPrioritySpeaker                        // 4194304
+	PermManageWebhooks                         // 8388608
+	PermMentionEveryone                        // 16777216
+	PermRecordVoice                            // 33554432
+	PermEmbedLinks                             // 67108864
+	PermManageNicknames                        // 134217728
 )
 
 // PermAll is the sum of all permissions. Update when adding new perms: (1 << N) - 1
-const PermAll Permission = (1 << 19) - 1
+const PermAll Permission = (1 << 28) - 1
+
+// PermReadOnly is what a member keeps while locked behind the rules-acceptance gate:
+// they can look, not act.
+const PermReadOnly = PermViewChannel | PermReadMessages
+
+// PermDefaultMember is the permission set the auto-created "everyone" role starts
+// with on new servers. Instance operators can override it with
+// MQVI_DEFAULT_MEMBER_PERMS; this is the fallback when that is unset.
+const PermDefaultMember = PermViewChannel | PermReadMessages | PermSendMessages |
+	PermConnectVoice | PermSpeak | PermVideo | PermUseSoundboard | PermEmbedLinks
 
 // Has checks if a permission is set. Admin bypasses all checks.
 func (p Permission) Has(perm Permission) bool {
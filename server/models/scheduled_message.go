@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxScheduleAhead caps how far in the future a message can be queued.
+const MaxScheduleAhead = 30 * 24 * time.Hour
+
+// ScheduledMessage is a queued channel message awaiting delivery. It carries
+// only what the send needs — the real Message row is created when it fires.
+type ScheduledMessage struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channel_id"`
+	UserID    string    `json:"user_id"`
+	Content   string    `json:"content"`
+	SendAt    time.Time `json:"send_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ScheduleMessageRequest queues a message for future delivery.
+type ScheduleMessageRequest struct {
+	Content string    `json:"content"`
+	SendAt  time.Time `json:"send_at"`
+}
+
+func (r *ScheduleMessageRequest) Validate() error {
+	r.Content = strings.TrimSpace(r.Content)
+	contentLen := utf8.RuneCountInString(r.Content)
+
+	if contentLen < 1 {
+		return fmt.Errorf("message content is required")
+	}
+	if contentLen > MaxMessageLength {
+		return fmt.Errorf("message content must be at most %d characters", MaxMessageLength)
+	}
+
+	now := time.Now().UTC()
+	if !r.SendAt.After(now) {
+		return fmt.Errorf("send_at must be in the future")
+	}
+	if r.SendAt.After(now.Add(MaxScheduleAhead)) {
+		return fmt.Errorf("send_at must be within 30 days")
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -12,11 +13,21 @@ type Server struct {
 	Name               string    `json:"name"`
 	IconURL            *string   `json:"icon_url"`
 	OwnerID            string    `json:"owner_id"`
-	IsPublic           bool      `json:"is_public"` // listed in the public servers directory (upcoming); joinable without an invite
+	// IsPublic (the repurposed invite_required column — migration 075) lists the server in
+	// the discovery directory AND opens the codeless join path (POST
+	// /api/discovery/servers/{id}/join). false = invite code only. Bans and
+	// approval_required apply to both paths identically.
+	IsPublic           bool      `json:"is_public"`
 	E2EEEnabled        bool      `json:"e2ee_enabled"`
 	ApprovalRequired   bool      `json:"approval_required"` // joins via invite require approval by a PermApproveMembers holder
+	// DisallowSelfReactions blocks members from reacting to their own messages
+	// (migration 120). Off by default; removals are never blocked.
+	DisallowSelfReactions bool   `json:"disallow_self_reactions"`
 	LiveKitInstanceID  *string   `json:"livekit_instance_id,omitempty"` // nil = no voice
 	AFKTimeoutMinutes  int       `json:"afk_timeout_minutes"`           // 15/30/45/60, default 60
+	// MessageRetentionDays (migration 122) auto-deletes messages older than the
+	// window via the daily retention sweep. 0 = keep forever. Pins are exempt.
+	MessageRetentionDays int     `json:"message_retention_days"`
 	// Discovery metadata. Description/BannerURL/Category are owner-editable and optional.
 	// Verified/Featured are platform-admin flags.
 	Description        *string   `json:"description,omitempty"`
@@ -24,6 +35,12 @@ type Server struct {
 	Category           *string   `json:"category,omitempty"`
 	Verified           bool      `json:"verified"`
 	Featured           bool      `json:"featured"`
+	// Per-server upload overrides (migration 113). Nil max size falls back to
+	// the platform default; the type columns hold comma-separated rules in the
+	// UploadPolicy format.
+	UploadMaxSize      *int64    `json:"upload_max_size,omitempty"`
+	UploadAllowedTypes *string   `json:"upload_allowed_types,omitempty"`
+	UploadBlockedTypes *string   `json:"upload_blocked_types,omitempty"`
 	// Soft-delete state. DeletedByAdmin=1 → owner cannot restore (admin moderation).
 	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
 	DeletedBy          *string    `json:"deleted_by,omitempty"`
@@ -46,6 +63,53 @@ var ValidServerCategories = map[string]bool{
 // MaxServerDescriptionLength bounds the discovery description.
 const MaxServerDescriptionLength = 300
 
+// MaxMessageRetentionDays caps the retention window at ten years — beyond that
+// the setting is indistinguishable from "keep forever", which 0 already means.
+const MaxMessageRetentionDays = 3650
+
+// Welcome screen limits. Rules text is long-form (it is the thing people scroll past),
+// the description is a greeting line.
+const (
+	MaxWelcomeDescriptionLength = 300
+	MaxRulesTextLength          = 4000
+	MaxSuggestedChannels        = 5
+)
+
+// WelcomeConfig is the per-server welcome screen: what a new member sees before (or
+// instead of) the channel list, plus the optional rules-acceptance gate. Doubles as the
+// PATCH body — PermAdmin writes the whole thing, so partial-update pointers buy nothing.
+type WelcomeConfig struct {
+	Description         *string  `json:"description"`
+	SuggestedChannelIDs []string `json:"suggested_channel_ids"`
+	RulesText           *string  `json:"rules_text"`
+	// RulesRequired turns on the gate: members who joined while it is on are read-only
+	// until they accept. Members already inside when it flips on are grandfathered in.
+	RulesRequired bool `json:"rules_required"`
+}
+
+func (c *WelcomeConfig) Validate() error {
+	if c.Description != nil {
+		*c.Description = strings.TrimSpace(*c.Description)
+		if utf8.RuneCountInString(*c.Description) > MaxWelcomeDescriptionLength {
+			return fmt.Errorf("welcome description must be at most %d characters", MaxWelcomeDescriptionLength)
+		}
+	}
+	if c.RulesText != nil {
+		*c.RulesText = strings.TrimSpace(*c.RulesText)
+		if utf8.RuneCountInString(*c.RulesText) > MaxRulesTextLength {
+			return fmt.Errorf("rules text must be at most %d characters", MaxRulesTextLength)
+		}
+	}
+	if len(c.SuggestedChannelIDs) > MaxSuggestedChannels {
+		return fmt.Errorf("at most %d suggested channels", MaxSuggestedChannels)
+	}
+	// A gate with nothing to accept is a lockout, not a rule.
+	if c.RulesRequired && (c.RulesText == nil || *c.RulesText == "") {
+		return fmt.Errorf("rules_required needs a non-empty rules_text")
+	}
+	return nil
+}
+
 // ServerListItem is the minimal data needed for the server icon sidebar.
 //
 // E2EEEnabled is carried here, not just on the full Server, because the client renders channels
@@ -131,12 +195,19 @@ type UpdateServerRequest struct {
 	IsPublic          *bool   `json:"is_public"`
 	E2EEEnabled       *bool   `json:"e2ee_enabled"`
 	ApprovalRequired  *bool   `json:"approval_required"`
+	DisallowSelfReactions *bool `json:"disallow_self_reactions,omitempty"`
 	Description       *string `json:"description,omitempty"`
 	Category          *string `json:"category,omitempty"`
 	AFKTimeoutMinutes *int    `json:"afk_timeout_minutes,omitempty"`
+	MessageRetentionDays *int `json:"message_retention_days,omitempty"`
 	LiveKitURL        *string `json:"livekit_url,omitempty"`
 	LiveKitKey        *string `json:"livekit_key,omitempty"`
 	LiveKitSecret     *string `json:"livekit_secret,omitempty"`
+	// Upload overrides. A max size of 0 resets to the platform default; an
+	// empty list clears the corresponding restriction.
+	UploadMaxSize      *int64    `json:"upload_max_size,omitempty"`
+	UploadAllowedTypes *[]string `json:"upload_allowed_types,omitempty"`
+	UploadBlockedTypes *[]string `json:"upload_blocked_types,omitempty"`
 }
 
 func (r *UpdateServerRequest) HasLiveKitUpdate() bool {
@@ -165,6 +236,30 @@ func (r *UpdateServerRequest) Validate() error {
 			return fmt.Errorf("afk_timeout_minutes must be 15, 30, 45, or 60")
 		}
 	}
+	if r.MessageRetentionDays != nil {
+		v := *r.MessageRetentionDays
+		if v < 0 || v > MaxMessageRetentionDays {
+			return fmt.Errorf("message_retention_days must be between 0 and %d (0 = keep forever)", MaxMessageRetentionDays)
+		}
+	}
+	if r.UploadMaxSize != nil && *r.UploadMaxSize < 0 {
+		return fmt.Errorf("upload_max_size must not be negative")
+	}
+	for _, list := range []*[]string{r.UploadAllowedTypes, r.UploadBlockedTypes} {
+		if list == nil {
+			continue
+		}
+		if len(*list) > MaxUploadTypeRules {
+			return fmt.Errorf("at most %d upload type rules are allowed", MaxUploadTypeRules)
+		}
+		for i, rule := range *list {
+			rule = strings.ToLower(strings.TrimSpace(rule))
+			if !ValidUploadTypeRule(rule) {
+				return fmt.Errorf("invalid upload type rule: %q (use \"image/png\", \"image/*\" or \".pdf\")", (*list)[i])
+			}
+			(*list)[i] = rule
+		}
+	}
 	// All 3 LiveKit fields are required together
 	if r.HasLiveKitUpdate() {
 		if r.LiveKitURL == nil || strings.TrimSpace(*r.LiveKitURL) == "" {
@@ -180,6 +275,24 @@ func (r *UpdateServerRequest) Validate() error {
 	return nil
 }
 
+// TransferOwnershipRequest hands the server to another member. Password re-entry is
+// required — a hijacked session must not be enough to give a community away.
+type TransferOwnershipRequest struct {
+	NewOwnerID string `json:"new_owner_id"`
+	Password   string `json:"password"`
+}
+
+func (r *TransferOwnershipRequest) Validate() error {
+	r.NewOwnerID = strings.TrimSpace(r.NewOwnerID)
+	if r.NewOwnerID == "" {
+		return fmt.Errorf("new_owner_id is required")
+	}
+	if r.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	return nil
+}
+
 type JoinServerRequest struct {
 	InviteCode string `json:"invite_code"`
 }
@@ -192,6 +305,24 @@ func (r *JoinServerRequest) Validate() error {
 	return nil
 }
 
+var vanityCodeRegex = regexp.MustCompile(`^[a-z0-9-]{3,32}$`)
+
+// SetVanityRequest sets the server's vanity invite code. An empty code clears it.
+type SetVanityRequest struct {
+	Code string `json:"code"`
+}
+
+func (r *SetVanityRequest) Validate() error {
+	r.Code = strings.TrimSpace(r.Code)
+	if r.Code == "" {
+		return nil
+	}
+	if !vanityCodeRegex.MatchString(r.Code) {
+		return fmt.Errorf("vanity code must be 3-32 characters of lowercase letters, digits, or dashes")
+	}
+	return nil
+}
+
 // ReorderServersRequest — per-user server list ordering.
 type ReorderServersRequest struct {
 	Items []PositionUpdate `json:"items"`
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ServerTemplate is a reusable snapshot of a server's structure — categories,
+// channels, roles and channel permission overrides, never messages or members.
+// Public templates are listed to everyone; private ones only to their creator.
+type ServerTemplate struct {
+	ID        string            `json:"id"`
+	CreatorID string            `json:"creator_id"`
+	Name      string            `json:"name"`
+	IsPublic  bool              `json:"is_public"`
+	Structure TemplateStructure `json:"structure"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// TemplateStructure is the snapshot document. Channels reference categories
+// and roles by slice index rather than ID — every ID is minted fresh when the
+// template is instantiated, and the snapshot must not dangle when the source
+// server is deleted.
+type TemplateStructure struct {
+	Categories []TemplateCategory `json:"categories"`
+	Roles      []TemplateRole     `json:"roles"`
+	Channels   []TemplateChannel  `json:"channels"`
+}
+
+type TemplateCategory struct {
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+// TemplateRole snapshots a role minus its per-server identity. The owner role
+// is never captured — instantiation always creates a fresh one for the new
+// owner. At most one entry has IsDefault set; it supplies the permissions of
+// the new server's "everyone" role.
+type TemplateRole struct {
+	Name        string     `json:"name"`
+	Color       string     `json:"color"`
+	Position    int        `json:"position"`
+	Permissions Permission `json:"permissions"`
+	IsDefault   bool       `json:"is_default"`
+	Mentionable bool       `json:"mentionable"`
+}
+
+// TemplateOverride is a channel permission override keyed by role index into
+// TemplateStructure.Roles.
+type TemplateOverride struct {
+	RoleIndex int        `json:"role_index"`
+	Allow     Permission `json:"allow"`
+	Deny      Permission `json:"deny"`
+}
+
+type TemplateChannel struct {
+	Name               string             `json:"name"`
+	Type               ChannelType        `json:"type"`
+	Topic              *string            `json:"topic,omitempty"`
+	CategoryIndex      *int               `json:"category_index,omitempty"`
+	Position           int                `json:"position"`
+	UserLimit          int                `json:"user_limit,omitempty"`
+	Bitrate            int                `json:"bitrate,omitempty"`
+	PinAnnouncements   bool               `json:"pin_announcements"`
+	SlowmodeSeconds    int                `json:"slowmode_seconds,omitempty"`
+	DeleteAfterSeconds int                `json:"delete_after_seconds,omitempty"`
+	Overrides          []TemplateOverride `json:"overrides,omitempty"`
+}
+
+type SaveTemplateRequest struct {
+	Name     string `json:"name"`
+	IsPublic bool   `json:"is_public"`
+}
+
+func (r *SaveTemplateRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	nameLen := utf8.RuneCountInString(r.Name)
+	if nameLen < 1 || nameLen > 100 {
+		return fmt.Errorf("template name must be between 1 and 100 characters")
+	}
+	return nil
+}
+
+// CreateFromTemplateRequest instantiates a template as a new mqvi-hosted
+// server. The server name is validated by the create path itself.
+type CreateFromTemplateRequest struct {
+	TemplateID string `json:"template_id"`
+	Name       string `json:"name"`
+}
+
+func (r *CreateFromTemplateRequest) Validate() error {
+	if strings.TrimSpace(r.TemplateID) == "" {
+		return fmt.Errorf("template_id is required")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// UploadPolicy is the effective upload rule set for one attachment destination.
+// MaxSize 0 means "use the platform default". Type rules are lowercase MIME
+// types ("application/pdf"), MIME prefixes ("image/*"), or filename extensions
+// (".exe"). BlockedTypes always wins; an empty AllowedTypes list allows
+// everything not blocked.
+type UploadPolicy struct {
+	MaxSize      int64    `json:"max_size"`
+	AllowedTypes []string `json:"allowed_types,omitempty"`
+	BlockedTypes []string `json:"blocked_types,omitempty"`
+}
+
+// TypeAllowed reports whether a file with this MIME type and filename passes
+// the policy. Callers check the declared and the sniffed type separately so a
+// renamed file cannot slip through on its Content-Type header alone.
+func (p UploadPolicy) TypeAllowed(mimeType, filename string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	for _, rule := range p.BlockedTypes {
+		if typeRuleMatches(rule, mimeType, ext) {
+			return false
+		}
+	}
+	if len(p.AllowedTypes) == 0 {
+		return true
+	}
+	for _, rule := range p.AllowedTypes {
+		if typeRuleMatches(rule, mimeType, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeRuleMatches(rule, mimeType, ext string) bool {
+	switch {
+	case rule == "":
+		return false
+	case strings.HasPrefix(rule, "."):
+		return ext == rule
+	case strings.HasSuffix(rule, "/*"):
+		return strings.HasPrefix(mimeType, strings.TrimSuffix(rule, "*"))
+	default:
+		return mimeType == rule
+	}
+}
+
+// MaxUploadTypeRules bounds each per-server type list.
+const MaxUploadTypeRules = 50
+
+// ValidUploadTypeRule reports whether a configured rule is well-formed: an
+// extension like ".pdf" or a MIME type like "image/png" / "image/*".
+func ValidUploadTypeRule(rule string) bool {
+	if strings.HasPrefix(rule, ".") {
+		return len(rule) >= 2 && len(rule) <= 16 && !strings.ContainsAny(rule[1:], "./\\ ")
+	}
+	slash := strings.Index(rule, "/")
+	return slash > 0 && slash < len(rule)-1 && !strings.ContainsAny(rule, " \\")
+}
+
+// SplitTypeRules converts the comma-separated column form into the list form.
+func SplitTypeRules(s *string) []string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	parts := strings.Split(*s, ",")
+	rules := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			rules = append(rules, p)
+		}
+	}
+	return rules
+}
+
+// JoinTypeRules is the inverse of SplitTypeRules; rules are stored lowercase.
+// nil for an empty list keeps the column NULL, i.e. "no restriction".
+func JoinTypeRules(rules []string) *string {
+	if len(rules) == 0 {
+		return nil
+	}
+	joined := strings.ToLower(strings.Join(rules, ","))
+	return &joined
+}
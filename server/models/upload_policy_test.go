@@ -0,0 +1,58 @@
+package models
+
+import "testing"
+
+func TestUploadPolicy_TypeAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   UploadPolicy
+		mimeType string
+		filename string
+		want     bool
+	}{
+		{"no rules allows everything", UploadPolicy{}, "application/x-msdownload", "setup.exe", true},
+		{"blocked exact mime", UploadPolicy{BlockedTypes: []string{"text/html"}}, "text/html", "page.html", false},
+		{"blocked extension", UploadPolicy{BlockedTypes: []string{".exe"}}, "application/octet-stream", "setup.exe", false},
+		{"blocked mime wildcard", UploadPolicy{BlockedTypes: []string{"video/*"}}, "video/mp4", "clip.mp4", false},
+		{"allowlist match", UploadPolicy{AllowedTypes: []string{"image/*", ".pdf"}}, "image/png", "pic.png", true},
+		{"allowlist extension match", UploadPolicy{AllowedTypes: []string{"image/*", ".pdf"}}, "application/pdf", "doc.pdf", true},
+		{"allowlist miss", UploadPolicy{AllowedTypes: []string{"image/*"}}, "audio/mpeg", "song.mp3", false},
+		{"blocklist wins over allowlist", UploadPolicy{AllowedTypes: []string{"image/*"}, BlockedTypes: []string{"image/svg+xml"}}, "image/svg+xml", "icon.svg", false},
+		{"case-insensitive inputs", UploadPolicy{BlockedTypes: []string{".exe"}}, "application/octet-stream", "SETUP.EXE", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.TypeAllowed(tt.mimeType, tt.filename); got != tt.want {
+				t.Errorf("TypeAllowed(%q, %q) = %v, want %v", tt.mimeType, tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidUploadTypeRule(t *testing.T) {
+	for _, rule := range []string{".pdf", ".gz", "image/png", "image/*", "application/vnd.ms-excel"} {
+		if !ValidUploadTypeRule(rule) {
+			t.Errorf("rule %q should be valid", rule)
+		}
+	}
+	for _, rule := range []string{"", ".", "pdf", "image/", "/png", ". pdf", ".a/b"} {
+		if ValidUploadTypeRule(rule) {
+			t.Errorf("rule %q should be invalid", rule)
+		}
+	}
+}
+
+func TestTypeRuleRoundTrip(t *testing.T) {
+	joined := JoinTypeRules([]string{"Image/*", ".PDF"})
+	if joined == nil || *joined != "image/*,.pdf" {
+		t.Fatalf("JoinTypeRules = %v, want lowercase comma form", joined)
+	}
+	rules := SplitTypeRules(joined)
+	if len(rules) != 2 || rules[0] != "image/*" || rules[1] != ".pdf" {
+		t.Fatalf("SplitTypeRules = %v", rules)
+	}
+	if JoinTypeRules(nil) != nil || SplitTypeRules(nil) != nil {
+		t.Fatal("empty list must stay nil (column NULL = no restriction)")
+	}
+}
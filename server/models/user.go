@@ -5,7 +5,10 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
@@ -24,15 +27,19 @@ const (
 )
 
 type User struct {
-	ID                    string     `json:"id"`
-	Username              string     `json:"username"`
-	DisplayName           *string    `json:"display_name"`
-	AvatarURL             *string    `json:"avatar_url"`
-	WallpaperURL          *string    `json:"wallpaper_url"`
-	PasswordHash          string     `json:"-"`
-	Status                UserStatus `json:"status"`
-	PrefStatus            UserStatus `json:"pref_status"`
-	CustomStatus          *string    `json:"custom_status"`
+	ID                string     `json:"id"`
+	Username          string     `json:"username"`
+	DisplayName       *string    `json:"display_name"`
+	AvatarURL         *string    `json:"avatar_url"`
+	WallpaperURL      *string    `json:"wallpaper_url"`
+	PasswordHash      string     `json:"-"`
+	Status            UserStatus `json:"status"`
+	PrefStatus        UserStatus `json:"pref_status"`
+	CustomStatus      *string    `json:"custom_status"`
+	CustomStatusEmoji *string    `json:"custom_status_emoji"`
+	// CustomStatusExpiresAt auto-clears the custom status via the background
+	// sweep. NULL = the status stays until the user changes it.
+	CustomStatusExpiresAt *time.Time `json:"custom_status_expires_at,omitempty"`
 	Email                 *string    `json:"email"`
 	Language              string     `json:"language"`
 	DMPrivacy             string     `json:"dm_privacy"`
@@ -43,6 +50,7 @@ type User struct {
 	PlatformBanReason     string     `json:"-"`
 	PlatformBannedBy      string     `json:"-"`
 	PlatformBannedAt      *time.Time `json:"-"`
+	PlatformBannedUntil   *time.Time `json:"-"`
 	// Soft-delete state. DeletedAt non-null + IsHardDeleted=0 → recoverable account.
 	// IsHardDeleted=1 → anonymized tombstone (username renamed, personal data wiped).
 	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
@@ -55,6 +63,16 @@ type User struct {
 	CreatedAt          time.Time  `json:"created_at"`
 }
 
+// PlatformBanActive reports whether the platform ban still blocks access.
+// Permanent bans (no expiry) always do; temporary bans stop at their expiry.
+// The flag itself is not cleared on expiry — only an admin action does that.
+func (u *User) PlatformBanActive() bool {
+	if !u.IsPlatformBanned {
+		return false
+	}
+	return u.PlatformBannedUntil == nil || time.Now().Before(*u.PlatformBannedUntil)
+}
+
 type CreateUserRequest struct {
 	Username    string `json:"username"`
 	Password    string `json:"password"`
@@ -86,10 +104,11 @@ func (r *CreateUserRequest) Validate() error {
 		return fmt.Errorf("password is required")
 	}
 
-	r.DisplayName = strings.TrimSpace(r.DisplayName)
-	if utf8.RuneCountInString(r.DisplayName) > 32 {
-		return fmt.Errorf("display name must be at most 32 characters")
+	displayName, err := NormalizeDisplayName(r.DisplayName)
+	if err != nil {
+		return err
 	}
+	r.DisplayName = displayName
 
 	r.Email = strings.TrimSpace(r.Email)
 	if r.Email != "" && !emailRegex.MatchString(r.Email) {
@@ -150,3 +169,44 @@ func isValidUsernameChar(ch rune) bool {
 		(ch >= '0' && ch <= '9') ||
 		ch == '_'
 }
+
+// MaxDisplayNameLength caps display names after normalization.
+// Must match the frontend constant in client/src/utils/constants.ts.
+const MaxDisplayNameLength = 32
+
+// NormalizeDisplayName applies the display-name policy shared by registration and
+// profile update. Usernames are locked to ASCII (isValidUsernameChar); display names
+// stay free-form, so they get sanitized instead:
+//   - NFKC folding collapses compatibility homoglyphs (fullwidth "Ａｄｍｉｎ" → "Admin")
+//     so the instance blocklist sees what the reader sees
+//   - control, zero-width, and bidi-override characters are stripped
+//   - whitespace runs collapse to a single space, leading/trailing trimmed
+//
+// The length cap applies to the cleaned name — padding a short name with zero-width
+// characters can't overflow it.
+func NormalizeDisplayName(s string) (string, error) {
+	folded := norm.NFKC.String(s)
+	var b strings.Builder
+	b.Grow(len(folded))
+	lastSpace := true // leading whitespace drops
+	for _, ch := range folded {
+		if unicode.IsSpace(ch) {
+			if !lastSpace {
+				b.WriteRune(' ')
+				lastSpace = true
+			}
+			continue
+		}
+		// IsGraphic is false for Cc (control) and Cf (zero-width, bidi overrides).
+		if !unicode.IsGraphic(ch) {
+			continue
+		}
+		b.WriteRune(ch)
+		lastSpace = false
+	}
+	name := strings.TrimRight(b.String(), " ")
+	if utf8.RuneCountInString(name) > MaxDisplayNameLength {
+		return "", fmt.Errorf("display name must be at most %d characters", MaxDisplayNameLength)
+	}
+	return name, nil
+}
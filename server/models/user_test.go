@@ -1,6 +1,9 @@
 package models
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // users.language is NOT NULL DEFAULT 'en', but every INSERT names the column, so the default
 // never fires and an unset field lands as "". That empty string reads as "no preference": the
@@ -34,3 +37,41 @@ func TestCreateUserRequest_ValidateNormalizesLanguage(t *testing.T) {
 		})
 	}
 }
+
+// Display names are free-form unicode, which is exactly what makes them the vector for
+// staff impersonation and invisible-character tricks — the policy folds and strips
+// before the length cap so none of those survive to the database.
+func TestNormalizeDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain name untouched", "Alice", "Alice"},
+		{"whitespace collapsed and trimmed", "  Alice   B  ", "Alice B"},
+		{"zero-width characters stripped", "Ad​min‍", "Admin"},
+		{"fullwidth homoglyphs folded", "Ａｄｍｉｎ", "Admin"},
+		{"control characters stripped", "Al\x00ice\r\n", "Alice"},
+		{"all-invisible name empties", "​​", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeDisplayName(tt.in)
+			if err != nil {
+				t.Fatalf("normalize: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDisplayName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	// The cap applies after stripping, so invisible padding can't smuggle an over-long name.
+	if _, err := NormalizeDisplayName(strings.Repeat("a", MaxDisplayNameLength+1)); err == nil {
+		t.Error("over-long display name must be rejected")
+	}
+	if got, err := NormalizeDisplayName("​" + strings.Repeat("a", MaxDisplayNameLength)); err != nil || len(got) != MaxDisplayNameLength {
+		t.Errorf("zero-width padding must not count toward the cap: got %q, err %v", got, err)
+	}
+}
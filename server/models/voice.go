@@ -17,10 +17,26 @@ type VoiceState struct {
 	IsStreaming bool   `json:"is_streaming"`
 	// ShareQuality is the ceiling the sharer picked ("720p"/"1080p"), not what is being sent —
 	// the stream follows the shared window's own size under it. Empty when not sharing.
-	ShareQuality     string    `json:"share_quality,omitempty"`
-	IsServerMuted    bool      `json:"is_server_muted"`
-	IsServerDeafened bool      `json:"is_server_deafened"`
-	LastActivity     time.Time `json:"-"` // not serialized — server-side AFK tracking only
+	ShareQuality     string `json:"share_quality,omitempty"`
+	IsServerMuted    bool   `json:"is_server_muted"`
+	IsServerDeafened bool   `json:"is_server_deafened"`
+	// IsPrioritySpeaker marks the user whose audio clients duck others under.
+	// Toggled via WS (gated by PermPrioritySpeaker); purely a client-side hint —
+	// the SFU token grant is unchanged.
+	IsPrioritySpeaker bool `json:"is_priority_speaker"`
+	// ClientPlatform is the platform of the connection that joined voice
+	// ("desktop"/"web"/"mobile"), for the UI's device indicator. Empty when the
+	// client didn't send the hint; purely informational.
+	ClientPlatform string    `json:"client_platform,omitempty"`
+	LastActivity   time.Time `json:"-"` // not serialized — server-side AFK tracking only
+}
+
+// VoiceStatesResponse is the server-scoped voice states payload.
+// MaxParticipants is the instance-wide per-channel cap (0 = no cap) so clients
+// can show "channel full" before a join attempt bounces off GenerateToken.
+type VoiceStatesResponse struct {
+	States          []VoiceState `json:"states"`
+	MaxParticipants int          `json:"max_participants"`
 }
 
 type VoiceTokenRequest struct {
@@ -32,4 +48,11 @@ type VoiceTokenResponse struct {
 	URL            string `json:"url"`
 	ChannelID      string `json:"channel_id"`
 	E2EEPassphrase string `json:"e2ee_passphrase,omitempty"`
+	// Bitrate is the channel's effective audio bitrate (already bounded by the
+	// host-type ceiling at set time) so the client publishes at the right rate.
+	Bitrate int `json:"bitrate,omitempty"`
+	// Region echoes the channel's region hint, when one is set. The room still
+	// lives on the server's instance; the hint is for client-side tuning and
+	// future per-region routing.
+	Region string `json:"region,omitempty"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// VoiceRecording is the metadata of a completed voice channel recording. The
+// media itself is written by the LiveKit egress service to wherever that
+// service is configured to store output — FileURL records the location the
+// egress reported, it is not a path under this server's upload tree.
+type VoiceRecording struct {
+	ID              string     `json:"id"`
+	ServerID        string     `json:"server_id"`
+	ChannelID       string     `json:"channel_id"`
+	EgressID        string     `json:"egress_id"`
+	FileURL         *string    `json:"file_url"`
+	DurationSeconds int        `json:"duration_seconds"`
+	StartedBy       string     `json:"started_by"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+}
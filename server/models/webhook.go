@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxWebhookNameLength caps both the webhook's own name and the per-message
+// username override.
+const MaxWebhookNameLength = 80
+
+// Webhook is a tokened posting endpoint for integrations (CI, RSS, alerts):
+// anyone holding the token can post into the webhook's channel without a user
+// account.
+type Webhook struct {
+	ID        string  `json:"id"`
+	ServerID  string  `json:"server_id"`
+	ChannelID string  `json:"channel_id"`
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatar_url"`
+	// Token is the credential for the public execute endpoint. Only surfaced
+	// on routes gated by PermManageWebhooks.
+	Token     string    `json:"token"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+}
+
+func (r *CreateWebhookRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("webhook name is required")
+	}
+	if utf8.RuneCountInString(r.Name) > MaxWebhookNameLength {
+		return fmt.Errorf("webhook name must be at most %d characters", MaxWebhookNameLength)
+	}
+	return nil
+}
+
+// ExecuteWebhookRequest is the body the public execute endpoint accepts.
+// Username and AvatarURL override the webhook's defaults for this one message.
+type ExecuteWebhookRequest struct {
+	Content   string  `json:"content"`
+	Username  string  `json:"username,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+}
+
+func (r *ExecuteWebhookRequest) Validate() error {
+	r.Content = strings.TrimSpace(r.Content)
+	r.Username = strings.TrimSpace(r.Username)
+
+	if r.Content == "" {
+		return fmt.Errorf("message content is required")
+	}
+	if utf8.RuneCountInString(r.Content) > MaxMessageLength {
+		return fmt.Errorf("message content must be at most %d characters", MaxMessageLength)
+	}
+	if utf8.RuneCountInString(r.Username) > MaxWebhookNameLength {
+		return fmt.Errorf("username must be at most %d characters", MaxWebhookNameLength)
+	}
+	return nil
+}
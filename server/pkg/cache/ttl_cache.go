@@ -92,6 +92,20 @@ func (c *TTLCache[K, V]) DeleteFunc(predicate func(key K) bool) {
 	}
 }
 
+// DeleteValueFunc removes all entries whose key/value pair matches the predicate.
+// Needed when the key alone can't identify affected entries (e.g. dropping every
+// per-channel viewer set that contains a given user).
+func (c *TTLCache[K, V]) DeleteValueFunc(predicate func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.entries {
+		if predicate(key, e.value) {
+			delete(c.entries, key)
+		}
+	}
+}
+
 func (c *TTLCache[K, V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNeedsReencrypt means a stored ciphertext decrypted under neither the
+// primary nor the previous key. After a key rotation this is what every voice
+// path would otherwise report as an opaque GCM failure — callers surface it so
+// operators know to restore the old key as ENCRYPTION_KEY_PREV and restart,
+// which re-encrypts the stored credentials under the new key.
+var ErrNeedsReencrypt = errors.New("credentials need re-encryption (encrypted under a key that is no longer configured)")
+
+// Keychain is the key-rotation counterpart of signedurl.Signer: encryption
+// always uses the primary key, decryption accepts the primary or the previous
+// one. Keeping the old key configured during a rotation lets stored LiveKit
+// credentials stay readable until they are rewritten under the new key.
+type Keychain struct {
+	primary []byte // key for all new ciphertexts
+	prev    []byte // previous key accepted for decryption (may be nil)
+}
+
+// NewKeychain creates a Keychain. Keys are raw 32-byte AES-256 keys (use
+// DeriveKey on the hex form first). prev may be nil if no rotation is in progress.
+func NewKeychain(primary, prev []byte) *Keychain {
+	return &Keychain{primary: primary, prev: prev}
+}
+
+// HasPrev reports whether a previous key is configured, i.e. a rotation is in
+// progress and stored ciphertexts may still need rewriting.
+func (k *Keychain) HasPrev() bool {
+	return k.prev != nil
+}
+
+// Encrypt encrypts under the primary key only.
+func (k *Keychain) Encrypt(plaintext string) (string, error) {
+	return Encrypt(plaintext, k.primary)
+}
+
+// Decrypt tries the primary key, then the previous one. A ciphertext neither
+// key opens returns ErrNeedsReencrypt (wrapped) rather than a bare GCM error.
+func (k *Keychain) Decrypt(encoded string) (string, error) {
+	plaintext, err := Decrypt(encoded, k.primary)
+	if err == nil {
+		return plaintext, nil
+	}
+	if k.prev != nil {
+		if plaintext, prevErr := Decrypt(encoded, k.prev); prevErr == nil {
+			return plaintext, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %v", ErrNeedsReencrypt, err)
+}
+
+// DecryptedWithPrev reports whether only the previous key opens the ciphertext —
+// i.e. the stored value predates the rotation and should be rewritten.
+func (k *Keychain) DecryptedWithPrev(encoded string) bool {
+	if _, err := Decrypt(encoded, k.primary); err == nil {
+		return false
+	}
+	if k.prev == nil {
+		return false
+	}
+	_, err := Decrypt(encoded, k.prev)
+	return err == nil
+}
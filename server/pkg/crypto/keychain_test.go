@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+)
+
+func testKey(t *testing.T, fill byte) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = fill
+	}
+	return key
+}
+
+func TestKeychainDecrypt_FallsBackToPrev(t *testing.T) {
+	oldKey := testKey(t, 0x01)
+	newKey := testKey(t, 0x02)
+
+	// Encrypted before the rotation, under what is now the previous key.
+	stored, err := Encrypt("lk-api-secret", oldKey)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	kc := NewKeychain(newKey, oldKey)
+	plaintext, err := kc.Decrypt(stored)
+	if err != nil {
+		t.Fatalf("decrypt with prev key: %v", err)
+	}
+	if plaintext != "lk-api-secret" {
+		t.Fatalf("expected original plaintext, got %q", plaintext)
+	}
+	if !kc.DecryptedWithPrev(stored) {
+		t.Fatal("expected DecryptedWithPrev to flag a pre-rotation ciphertext")
+	}
+
+	// After the re-encrypt pass the primary key opens it directly.
+	rewritten, err := kc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("re-encrypt: %v", err)
+	}
+	if kc.DecryptedWithPrev(rewritten) {
+		t.Fatal("rewritten ciphertext should decrypt under the primary key")
+	}
+}
+
+func TestKeychainDecrypt_UnknownKeyNeedsReencrypt(t *testing.T) {
+	stored, err := Encrypt("lk-api-secret", testKey(t, 0x01))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// Operator rotated the key without keeping the old one around.
+	kc := NewKeychain(testKey(t, 0x02), nil)
+	if _, err := kc.Decrypt(stored); !errors.Is(err, ErrNeedsReencrypt) {
+		t.Fatalf("expected ErrNeedsReencrypt, got %v", err)
+	}
+
+	// Same result when the previous key is set but also wrong.
+	kc = NewKeychain(testKey(t, 0x02), testKey(t, 0x03))
+	if _, err := kc.Decrypt(stored); !errors.Is(err, ErrNeedsReencrypt) {
+		t.Fatalf("expected ErrNeedsReencrypt with wrong prev key, got %v", err)
+	}
+}
@@ -13,6 +13,8 @@ const (
 	CodePasswordContainsIdentity = "password_contains_identity"
 	CodePasswordTooLong          = "password_too_long"
 	CodePasswordBreached         = "password_breached"
+	CodePasswordTooCommon        = "password_too_common"
+	CodePasswordMissingClass     = "password_missing_class"
 
 	// The conversation mandates E2EE and the message came in unencrypted. Coded so the client can
 	// say why instead of falling back to a generic send failure.
@@ -20,6 +22,10 @@ const (
 	// The reverse: an encrypted message aimed at a conversation that does not use E2EE, where
 	// nobody could decrypt it.
 	CodeEncryptionNotAvailable = "encryption_not_available"
+
+	// A username, display name, or nickname hit the instance blocklist. Deliberately one code
+	// for every field and no detail — the matched term must not leak.
+	CodeNameNotAllowed = "name_not_allowed"
 )
 
 type codedError struct {
@@ -53,6 +59,7 @@ var (
 	ErrConflict      = errors.New("conflict") // concurrent write lost the race
 	ErrInternal      = errors.New("internal error")
 	ErrQuotaExceeded = errors.New("storage quota exceeded")
+	ErrRateLimited   = errors.New("rate limited") // service-level rate limit (handler limiters write 429 directly)
 
 	// E2EE errors
 	ErrDeviceNotFound  = errors.New("device not found")
@@ -0,0 +1,65 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+
+	_ "image/gif"  // register DecodeConfig for GIF
+	_ "image/jpeg" // register DecodeConfig for JPEG
+	_ "image/png"  // register DecodeConfig for PNG
+)
+
+// ErrImageTooLarge means an image's declared dimensions exceed the configured
+// limits. Callers wrap it into their own error domain (pkg.ErrBadRequest).
+var ErrImageTooLarge = errors.New("image dimensions exceed the allowed maximum")
+
+// ImageLimits bounds the dimensions an uploaded image may declare. File-size
+// caps don't catch decompression bombs — a few-hundred-KB PNG can declare
+// 30000×30000 and take gigabytes to decode, crashing client renderers. Limits
+// are read from the image HEADER only (image.DecodeConfig), so checking costs a
+// few bytes of I/O regardless of file size. A zero value in any field disables
+// that bound.
+type ImageLimits struct {
+	MaxWidth  int
+	MaxHeight int
+	MaxPixels int // width × height cap — catches extreme single-dimension strips
+}
+
+// Check reads the image header from f and returns ErrImageTooLarge (wrapped
+// with the offending dimensions) if it declares dimensions over the limits.
+// f is rewound to the start afterwards so the caller can store it.
+//
+// Formats without a registered header decoder (webp, avif, ...) pass
+// unchecked — this is a renderer safety net, not a validation gate, and
+// rejecting everything we cannot read would break legitimate uploads.
+func (l ImageLimits) Check(f io.ReadSeeker) error {
+	if l.MaxWidth == 0 && l.MaxHeight == 0 && l.MaxPixels == 0 {
+		return nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seek image for dimension check: %w", err)
+	}
+
+	cfg, _, err := image.DecodeConfig(f)
+	if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
+		return fmt.Errorf("rewind image after dimension check: %w", seekErr)
+	}
+	if err != nil {
+		// Unknown format or corrupt header — can't read dimensions cheaply,
+		// let it through rather than guess.
+		return nil
+	}
+
+	if l.MaxWidth > 0 && cfg.Width > l.MaxWidth {
+		return fmt.Errorf("%w: width %d exceeds max %d", ErrImageTooLarge, cfg.Width, l.MaxWidth)
+	}
+	if l.MaxHeight > 0 && cfg.Height > l.MaxHeight {
+		return fmt.Errorf("%w: height %d exceeds max %d", ErrImageTooLarge, cfg.Height, l.MaxHeight)
+	}
+	if l.MaxPixels > 0 && cfg.Width > 0 && cfg.Height > l.MaxPixels/cfg.Width {
+		return fmt.Errorf("%w: %d×%d exceeds max %d pixels", ErrImageTooLarge, cfg.Width, cfg.Height, l.MaxPixels)
+	}
+	return nil
+}
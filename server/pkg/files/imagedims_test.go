@@ -0,0 +1,64 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+)
+
+// pngOf returns an encoded width×height PNG as a seekable reader.
+func pngOf(t *testing.T, width, height int) io.ReadSeeker {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestImageLimits_Check(t *testing.T) {
+	limits := ImageLimits{MaxWidth: 10, MaxHeight: 8, MaxPixels: 50}
+
+	if err := limits.Check(pngOf(t, 10, 5)); err != nil {
+		t.Fatalf("within limits: unexpected error %v", err)
+	}
+	if err := limits.Check(pngOf(t, 11, 5)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("over max width: got %v, want ErrImageTooLarge", err)
+	}
+	if err := limits.Check(pngOf(t, 5, 9)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("over max height: got %v, want ErrImageTooLarge", err)
+	}
+	// 8×8 fits both sides but exceeds the 50-pixel cap.
+	if err := limits.Check(pngOf(t, 8, 8)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("over pixel cap: got %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestImageLimits_ZeroValueDisablesChecks(t *testing.T) {
+	if err := (ImageLimits{}).Check(pngOf(t, 100, 100)); err != nil {
+		t.Fatalf("zero-value limits must pass everything, got %v", err)
+	}
+}
+
+func TestImageLimits_UnreadableFormatPasses(t *testing.T) {
+	limits := ImageLimits{MaxWidth: 1, MaxHeight: 1}
+	junk := bytes.NewReader([]byte("RIFF....WEBPVP8 not a decodable header"))
+	if err := limits.Check(junk); err != nil {
+		t.Fatalf("unreadable format must degrade gracefully, got %v", err)
+	}
+}
+
+func TestImageLimits_CheckRewindsReader(t *testing.T) {
+	r := pngOf(t, 2, 2)
+	if err := (ImageLimits{MaxWidth: 10}).Check(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The caller stores the file right after the check — it must read from
+	// the start, not from wherever DecodeConfig stopped.
+	if pos, _ := r.Seek(0, io.SeekCurrent); pos != 0 {
+		t.Fatalf("reader left at offset %d, want 0", pos)
+	}
+}
@@ -2,17 +2,23 @@
 // and URL generation. All upload services and handlers must go through Locator
 // rather than constructing paths or URLs directly.
 //
-// Layout under cfg.Upload.Dir:
+// Layout under cfg.Upload.Dir (scope directories are sharded by the first two
+// characters of the scope ID so no kind directory accumulates tens of
+// thousands of entries):
 //
-//	messages/<messageID>/<filename>
-//	dm/<dmMessageID>/<filename>
-//	avatars/<userID>/<filename>
-//	wallpapers/<userID>/<filename>
-//	badges/global/<filename>
-//	soundboards/<serverID>/<filename>
-//	server-icons/<serverID>/<filename>
-//	feedback/<ticketID>/<filename>
-//	reports/<reportID>/<filename>
+//	messages/<shard>/<messageID>/<filename>
+//	dm/<shard>/<dmMessageID>/<filename>
+//	avatars/<shard>/<userID>/<filename>
+//	wallpapers/<shard>/<userID>/<filename>
+//	badges/gl/global/<filename>
+//	soundboards/<shard>/<serverID>/<filename>
+//	server-icons/<shard>/<serverID>/<filename>
+//	feedback/<shard>/<ticketID>/<filename>
+//	reports/<shard>/<reportID>/<filename>
+//
+// URLs are layout-independent (no shard segment), so stored URLs survive the
+// sharding change; pre-shard files at <kind>/<scopeID>/ are migrated lazily the
+// first time they are served or deleted.
 //
 // URL format (relative, suitable for DB storage):
 //
@@ -129,13 +135,19 @@ func validateSegment(s string) error { return ValidateSegment(s) }
 
 // Locator builds disk paths and public URLs for stored files.
 type Locator struct {
-	uploadDir    string // absolute or relative root, e.g. "./data/uploads"
-	uploadDirAbs string // resolved absolute root used for containment checks
-	publicURL    string // base URL prefix used for absolute URL generation, e.g. "https://mqvi.net". Empty = relative.
+	uploadDir    string      // absolute or relative root, e.g. "./data/uploads"
+	uploadDirAbs string      // resolved absolute root used for containment checks
+	publicURL    string      // base URL prefix used for absolute URL generation, e.g. "https://mqvi.net". Empty = relative.
+	dirMode      os.FileMode // permission bits for directories the Locator creates
 }
 
-// NewLocator constructs a Locator. publicURL may be empty (relative URLs).
-func NewLocator(uploadDir, publicURL string) *Locator {
+// DefaultDirMode is applied when a Locator is built with mode 0 — callers that
+// don't care about directory permissions get the historical behaviour.
+const DefaultDirMode os.FileMode = 0o755
+
+// NewLocator constructs a Locator. publicURL may be empty (relative URLs);
+// dirMode 0 means DefaultDirMode.
+func NewLocator(uploadDir, publicURL string, dirMode os.FileMode) *Locator {
 	clean := strings.TrimRight(filepath.Clean(uploadDir), `/\`)
 	abs, err := filepath.Abs(clean)
 	if err != nil {
@@ -143,13 +155,28 @@ func NewLocator(uploadDir, publicURL string) *Locator {
 		// path so callers still get a usable Locator.
 		abs = clean
 	}
+	if dirMode == 0 {
+		dirMode = DefaultDirMode
+	}
 	return &Locator{
 		uploadDir:    clean,
 		uploadDirAbs: abs,
 		publicURL:    strings.TrimRight(publicURL, "/"),
+		dirMode:      dirMode,
 	}
 }
 
+// ShardOf returns the shard directory for a scope ID: its first two characters
+// (the whole ID when shorter). Scope IDs are random hex, so this spreads scope
+// directories evenly across at most 256 shards per kind. Exported so the
+// cleanup walker can recognise a sharded path as canonical.
+func ShardOf(scopeID string) string {
+	if len(scopeID) < 2 {
+		return scopeID
+	}
+	return scopeID[:2]
+}
+
 // UploadDir returns the configured root directory.
 func (l *Locator) UploadDir() string {
 	return l.uploadDir
@@ -185,6 +212,12 @@ func (l *Locator) DiskDir(kind Kind, scopeID string) (string, error) {
 	if err := validateSegment(scopeID); err != nil {
 		return "", fmt.Errorf("scopeID: %w", err)
 	}
+	return l.safeJoin(string(kind), ShardOf(scopeID), scopeID)
+}
+
+// legacyDiskDir is the pre-shard flat location of a scope directory. Only used
+// for lazy migration and delete fallback — nothing writes here any more.
+func (l *Locator) legacyDiskDir(kind Kind, scopeID string) (string, error) {
 	return l.safeJoin(string(kind), scopeID)
 }
 
@@ -200,7 +233,7 @@ func (l *Locator) DiskPath(kind Kind, scopeID, filename string) (string, error)
 	if err := validateSegment(filename); err != nil {
 		return "", fmt.Errorf("filename: %w", err)
 	}
-	return l.safeJoin(string(kind), scopeID, filename)
+	return l.safeJoin(string(kind), ShardOf(scopeID), scopeID, filename)
 }
 
 // EnsureDir creates the destination directory if missing.
@@ -209,7 +242,7 @@ func (l *Locator) EnsureDir(kind Kind, scopeID string) error {
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := os.MkdirAll(dir, l.dirMode); err != nil {
 		return fmt.Errorf("create upload dir: %w", err)
 	}
 	return nil
@@ -312,7 +345,37 @@ func (l *Locator) ResolveServePath(rawPath string) (string, error) {
 	if err := validateSegment(filename); err != nil {
 		return "", fmt.Errorf("filename: %w", err)
 	}
-	return l.safeJoin(kindStr, scopeID, filename)
+
+	disk, err := l.safeJoin(kindStr, ShardOf(scopeID), scopeID, filename)
+	if err != nil {
+		return "", err
+	}
+	if _, statErr := os.Stat(disk); statErr == nil {
+		return disk, nil
+	}
+	// Not at the sharded location — migrate a pre-shard scope directory lazily by
+	// renaming it into its shard. Rename failing because another request won the
+	// race is fine: re-stat and serve the sharded path.
+	legacyDir, err := l.legacyDiskDir(Kind(kindStr), scopeID)
+	if err != nil {
+		return "", err
+	}
+	if info, statErr := os.Stat(legacyDir); statErr == nil && info.IsDir() {
+		shardDir, joinErr := l.safeJoin(kindStr, ShardOf(scopeID))
+		if joinErr != nil {
+			return "", joinErr
+		}
+		if mkErr := os.MkdirAll(shardDir, l.dirMode); mkErr == nil {
+			_ = os.Rename(legacyDir, filepath.Join(shardDir, scopeID))
+		}
+		if _, statErr := os.Stat(disk); statErr == nil {
+			return disk, nil
+		}
+		// Migration did not produce the file (rename failed and the legacy dir is
+		// still in place) — serve from the legacy location.
+		return filepath.Join(legacyDir, filename), nil
+	}
+	return disk, nil
 }
 
 // DeleteFromURL removes a file referenced by its stored URL. Supports both the
@@ -335,6 +398,8 @@ func (l *Locator) DeleteFromURLChecked(storedURL string) error {
 		return nil
 	case strings.HasPrefix(storedURL, URLPathPrefix+"/"):
 		raw := strings.TrimPrefix(storedURL, URLPathPrefix+"/")
+		// ResolveServePath migrates a pre-shard file into its shard first, so the
+		// remove below covers both layouts.
 		disk, err := l.ResolveServePath(raw)
 		if err != nil {
 			return nil
@@ -9,7 +9,7 @@ import (
 )
 
 func TestLocator_RelativeURL_HappyPath(t *testing.T) {
-	l := NewLocator(t.TempDir(), "")
+	l := NewLocator(t.TempDir(), "", 0)
 	got, err := l.RelativeURL(KindMessage, "msg-123", "abc.png")
 	if err != nil {
 		t.Fatal(err)
@@ -21,7 +21,7 @@ func TestLocator_RelativeURL_HappyPath(t *testing.T) {
 }
 
 func TestLocator_RelativeURL_EscapesUnsafeURLChars(t *testing.T) {
-	l := NewLocator(t.TempDir(), "")
+	l := NewLocator(t.TempDir(), "", 0)
 	// scopeID with space (allowed by validateSegment) must be percent-encoded
 	got, err := l.RelativeURL(KindAvatar, "user 1", "x.png")
 	if err != nil {
@@ -44,7 +44,7 @@ func TestLocator_AbsoluteURL(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			l := NewLocator(t.TempDir(), tt.publicURL)
+			l := NewLocator(t.TempDir(), tt.publicURL, 0)
 			got, err := l.AbsoluteURL(KindAvatar, "u1", "x.png")
 			if err != nil {
 				t.Fatal(err)
@@ -58,7 +58,7 @@ func TestLocator_AbsoluteURL(t *testing.T) {
 
 func TestLocator_RejectsTraversalScope(t *testing.T) {
 	root := t.TempDir()
-	l := NewLocator(root, "")
+	l := NewLocator(root, "", 0)
 
 	cases := []string{
 		"..",
@@ -88,7 +88,7 @@ func TestLocator_RejectsTraversalScope(t *testing.T) {
 
 func TestLocator_RejectsTraversalFilename(t *testing.T) {
 	root := t.TempDir()
-	l := NewLocator(root, "")
+	l := NewLocator(root, "", 0)
 
 	cases := []string{"..", ".", "", "a/b", `a\b`, "with\x00null"}
 	for _, fn := range cases {
@@ -100,7 +100,7 @@ func TestLocator_RejectsTraversalFilename(t *testing.T) {
 
 func TestLocator_SaveFile_DoesNotEscapeRoot(t *testing.T) {
 	root := t.TempDir()
-	l := NewLocator(root, "")
+	l := NewLocator(root, "", 0)
 
 	// Even if validateSegment somehow passed, safeJoin should refuse anything
 	// that resolves outside the upload root. We exercise that path via the
@@ -126,7 +126,7 @@ func TestLocator_SaveFile_DoesNotEscapeRoot(t *testing.T) {
 
 func TestLocator_ResolveServePath(t *testing.T) {
 	root := t.TempDir()
-	l := NewLocator(root, "")
+	l := NewLocator(root, "", 0)
 
 	// Set up a file
 	dir := filepath.Join(root, "messages", "m1")
@@ -239,7 +239,7 @@ func TestGenerateDiskFilename(t *testing.T) {
 
 func TestSaveFile_RoundTrip(t *testing.T) {
 	root := t.TempDir()
-	l := NewLocator(root, "")
+	l := NewLocator(root, "", 0)
 
 	rel, err := l.SaveFile(KindMessage, "msg-1", "hello.txt", func(dst *os.File) error {
 		_, err := dst.WriteString("hello")
@@ -252,7 +252,7 @@ func TestSaveFile_RoundTrip(t *testing.T) {
 		t.Fatalf("relative URL: %q", rel)
 	}
 
-	disk := filepath.Join(root, "messages", "msg-1", "hello.txt")
+	disk := filepath.Join(root, "messages", "ms", "msg-1", "hello.txt")
 	data, err := os.ReadFile(disk)
 	if err != nil {
 		t.Fatalf("ReadFile: %v", err)
@@ -264,7 +264,7 @@ func TestSaveFile_RoundTrip(t *testing.T) {
 
 func TestDeleteFromURL_BothLayouts(t *testing.T) {
 	root := t.TempDir()
-	l := NewLocator(root, "")
+	l := NewLocator(root, "", 0)
 
 	newPath := filepath.Join(root, "messages", "m1", "a.txt")
 	if err := os.MkdirAll(filepath.Dir(newPath), 0o755); err != nil {
@@ -327,3 +327,50 @@ func TestIsValidKind(t *testing.T) {
 		}
 	}
 }
+
+// Serving a file still sitting at the pre-shard flat path must move its scope
+// directory into the shard and keep the stored URL working — that is the whole
+// lazy-migration contract.
+func TestResolveServePath_MigratesPreShardLayout(t *testing.T) {
+	root := t.TempDir()
+	l := NewLocator(root, "", 0)
+
+	legacy := filepath.Join(root, "messages", "abc123", "doc.pdf")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("pdf"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	disk, err := l.ResolveServePath("messages/abc123/doc.pdf")
+	if err != nil {
+		t.Fatalf("ResolveServePath: %v", err)
+	}
+	want := filepath.Join(root, "messages", "ab", "abc123", "doc.pdf")
+	if disk != want {
+		t.Fatalf("resolved %q, want sharded path %q", disk, want)
+	}
+	if _, err := os.ReadFile(disk); err != nil {
+		t.Fatalf("migrated file unreadable: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatalf("legacy path still exists after migration (err=%v)", err)
+	}
+
+	// Second resolve is a plain hit on the sharded path.
+	again, err := l.ResolveServePath("messages/abc123/doc.pdf")
+	if err != nil || again != want {
+		t.Fatalf("re-resolve after migration: path=%q err=%v", again, err)
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	if got := ShardOf("abc123"); got != "ab" {
+		t.Errorf("ShardOf(abc123) = %q, want ab", got)
+	}
+	// Degenerate short IDs shard to themselves rather than panicking.
+	if got := ShardOf("a"); got != "a" {
+		t.Errorf("ShardOf(a) = %q, want a", got)
+	}
+}
@@ -0,0 +1,142 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	// ThumbnailMaxEdge is the longest edge of a generated preview. Chat
+	// renders previews small; anything beyond this is wasted bytes on mobile.
+	ThumbnailMaxEdge = 400
+
+	// thumbnailMaxSourceEdge bounds the FULL decode that generation needs.
+	// ImageLimits.Check only reads the header; actually decoding is where a
+	// decompression bomb bites, so images declaring a larger edge are never
+	// decoded here — they just get no preview.
+	thumbnailMaxSourceEdge = 8000
+
+	thumbnailJPEGQuality = 80
+)
+
+// MakeThumbnail decodes a JPEG or PNG from f and returns a downscaled preview
+// (longest edge ThumbnailMaxEdge) encoded in the source format, plus its
+// dimensions. It returns (nil, 0, 0, nil) when no preview is warranted: an
+// unsupported MIME type, or a source already small enough to serve as its own
+// preview. Errors cover unreadable images and sources too large to decode
+// safely — callers treat both as "no preview", never as an upload failure.
+func MakeThumbnail(f io.ReadSeeker, mimeType string) ([]byte, int, int, error) {
+	if mimeType != "image/jpeg" && mimeType != "image/png" {
+		return nil, 0, 0, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, 0, fmt.Errorf("seek image for thumbnail: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("read image header for thumbnail: %w", err)
+	}
+	if cfg.Width > thumbnailMaxSourceEdge || cfg.Height > thumbnailMaxSourceEdge {
+		return nil, 0, 0, fmt.Errorf("%w: %d×%d exceeds %dpx decode bound",
+			ErrImageTooLarge, cfg.Width, cfg.Height, thumbnailMaxSourceEdge)
+	}
+	if cfg.Width <= ThumbnailMaxEdge && cfg.Height <= ThumbnailMaxEdge {
+		return nil, 0, 0, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, 0, fmt.Errorf("rewind image for thumbnail decode: %w", err)
+	}
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("decode image for thumbnail: %w", err)
+	}
+
+	thumb := downscale(src, ThumbnailMaxEdge)
+
+	var buf bytes.Buffer
+	switch mimeType {
+	case "image/png":
+		// PNG stays PNG so transparency survives.
+		err = png.Encode(&buf, thumb)
+	default:
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailJPEGQuality})
+	}
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("encode thumbnail: %w", err)
+	}
+
+	bounds := thumb.Bounds()
+	return buf.Bytes(), bounds.Dx(), bounds.Dy(), nil
+}
+
+// downscale resizes src so its longest edge is maxEdge, averaging the source
+// pixels behind each destination pixel (box filter). Stdlib-only by design —
+// quality is plenty for a 400px preview and it avoids an image dependency.
+func downscale(src image.Image, maxEdge int) *image.NRGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+
+	dw, dh := maxEdge, maxEdge
+	if sw >= sh {
+		dh = sh * maxEdge / sw
+	} else {
+		dw = sw * maxEdge / sh
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0 := sb.Min.Y + dy*sh/dh
+		y1 := sb.Min.Y + (dy+1)*sh/dh
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for dx := 0; dx < dw; dx++ {
+			x0 := sb.Min.X + dx*sw/dw
+			x1 := sb.Min.X + (dx+1)*sw/dw
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := src.At(x, y).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+			dst.SetNRGBA(dx, dy, nrgbaFromAverages(r/n, g/n, b/n, a/n))
+		}
+	}
+	return dst
+}
+
+// nrgbaFromAverages converts averaged 16-bit premultiplied channels back to
+// 8-bit non-premultiplied NRGBA.
+func nrgbaFromAverages(r, g, b, a uint64) color.NRGBA {
+	if a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+		A: uint8(a >> 8),
+	}
+}
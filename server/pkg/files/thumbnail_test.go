@@ -0,0 +1,51 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestMakeThumbnail_DownscalesToMaxEdge(t *testing.T) {
+	data, w, h, err := MakeThumbnail(pngOf(t, 1600, 800), "image/png")
+	if err != nil {
+		t.Fatalf("make thumbnail: %v", err)
+	}
+	if w != ThumbnailMaxEdge || h != ThumbnailMaxEdge/2 {
+		t.Fatalf("thumbnail is %d×%d, want %d×%d (aspect preserved)", w, h, ThumbnailMaxEdge, ThumbnailMaxEdge/2)
+	}
+
+	// The output must itself be a decodable image of the claimed size.
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode generated thumbnail: %v", err)
+	}
+	if format != "png" {
+		t.Errorf("PNG source produced %s thumbnail, want png (transparency)", format)
+	}
+	if cfg.Width != w || cfg.Height != h {
+		t.Errorf("encoded dimensions %d×%d disagree with returned %d×%d", cfg.Width, cfg.Height, w, h)
+	}
+}
+
+// Images already within the preview size, and non-image types, get no
+// thumbnail — and that is not an error.
+func TestMakeThumbnail_SkipsSmallAndNonImages(t *testing.T) {
+	data, _, _, err := MakeThumbnail(pngOf(t, 300, 200), "image/png")
+	if err != nil || data != nil {
+		t.Errorf("small image: got data=%v err=%v, want nil/nil", data != nil, err)
+	}
+	data, _, _, err = MakeThumbnail(bytes.NewReader([]byte("plain text")), "text/plain")
+	if err != nil || data != nil {
+		t.Errorf("non-image: got data=%v err=%v, want nil/nil", data != nil, err)
+	}
+}
+
+// The decode bound must trip on declared dimensions BEFORE a full decode —
+// that is the whole point of the bomb guard.
+func TestMakeThumbnail_RefusesOversizedDeclaredDimensions(t *testing.T) {
+	if _, _, _, err := MakeThumbnail(pngOf(t, thumbnailMaxSourceEdge+1, 10), "image/png"); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("oversized source: got %v, want ErrImageTooLarge", err)
+	}
+}
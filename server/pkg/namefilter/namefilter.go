@@ -0,0 +1,105 @@
+// Package namefilter screens user-chosen names — usernames, display names, nicknames —
+// against an instance-level blocklist. Usernames are global, so this list is global too;
+// per-server automod lists cannot carry it because a name follows the account everywhere.
+package namefilter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Filter holds the normalized blocked terms. A nil *Filter blocks nothing, so callers
+// can hold one unconditionally and skip the "is the feature on" branch.
+type Filter struct {
+	words []string
+}
+
+// New builds a filter from raw terms. Terms are normalized the same way candidate
+// names are, so "Bad_Word" in the list catches "b a d w o r d" in a name. Empty and
+// duplicate terms are dropped; an empty list yields nil (filtering disabled).
+func New(terms []string) *Filter {
+	seen := make(map[string]bool, len(terms))
+	words := make([]string, 0, len(terms))
+	for _, t := range terms {
+		n := normalize(t)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		words = append(words, n)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return &Filter{words: words}
+}
+
+// LoadFile reads one term per line, skipping blanks and '#' comments — the same shape
+// as every other list file an operator maintains by hand.
+func LoadFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening blocked-name list: %w", err)
+	}
+	defer f.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading blocked-name list: %w", err)
+	}
+	return terms, nil
+}
+
+// Blocked reports whether the name contains any blocked term. The caller is expected
+// to answer with a generic "that name isn't allowed" — echoing the matched term back
+// hands the user a diff tool for probing the list.
+func (f *Filter) Blocked(name string) bool {
+	if f == nil {
+		return false
+	}
+	n := normalize(name)
+	if n == "" {
+		return false
+	}
+	for _, w := range f.words {
+		if strings.Contains(n, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize NFKC-folds, lowercases, and strips the separators a name may legally
+// contain. Checking the raw string instead would wave "b_a_d" through while rejecting
+// "bad" — the trivial evasion every first-day user discovers. The NFKC fold closes the
+// compatibility-homoglyph variant of the same trick ("ｂａｄ" matches "bad"); zero-width
+// and other invisible characters are dropped so they can't split a blocked term either.
+func normalize(s string) string {
+	s = norm.NFKC.String(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, ch := range strings.ToLower(s) {
+		switch ch {
+		case ' ', '_', '-', '.':
+			continue
+		}
+		if !unicode.IsGraphic(ch) {
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package namefilter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBlocked(t *testing.T) {
+	f := New([]string{"badword", "Sl_ur", ""})
+
+	tests := []struct {
+		name      string
+		candidate string
+		want      bool
+	}{
+		{"exact match", "badword", true},
+		{"case does not hide it", "BadWord", true},
+		{"embedded in a longer name", "xX_badword_Xx", true},
+		// Separators are stripped on both sides, so the list entry "Sl_ur"
+		// and the evasive "s.l.u.r" meet in the middle.
+		{"separator evasion", "b_a_d.w o-r_d", true},
+		{"list terms are normalized too", "slur", true},
+
+		{"a clean name", "patrick", false},
+		{"empty name", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Blocked(tt.candidate); got != tt.want {
+				t.Errorf("Blocked(%q) = %v, want %v", tt.candidate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_EmptyListDisablesFiltering(t *testing.T) {
+	if f := New(nil); f != nil {
+		t.Fatalf("New(nil) = %v, want nil", f)
+	}
+	// The nil filter must be safe to call — callers hold it unconditionally.
+	var f *Filter
+	if f.Blocked("anything") {
+		t.Error("nil filter blocked a name")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocked.txt")
+	content := "# comment\nbadword\n\n  spaced  \n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	terms, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	want := []string{"badword", "spaced"}
+	if len(terms) != len(want) {
+		t.Fatalf("LoadFile() = %v, want %v", terms, want)
+	}
+	for i := range want {
+		if terms[i] != want[i] {
+			t.Errorf("terms[%d] = %q, want %q", i, terms[i], want[i])
+		}
+	}
+}
+
+func TestLoadFile_MissingFileIsAnError(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "absent.txt")); err == nil {
+		t.Error("LoadFile() on a missing file returned nil error — a typoed path would silently disable the filter")
+	}
+}
+
+// NFKC folding in normalize: a blocked term rewritten in compatibility homoglyphs or
+// split with zero-width characters must still match, or the filter is one copy-paste
+// away from useless.
+func TestBlocked_HomoglyphAndInvisibleEvasion(t *testing.T) {
+	f := New([]string{"admin"})
+	for _, name := range []string{"ａｄｍｉｎ", "Ad​min", "𝐚𝐝𝐦𝐢𝐧"} {
+		if !f.Blocked(name) {
+			t.Errorf("Blocked(%q) = false, want true", name)
+		}
+	}
+}
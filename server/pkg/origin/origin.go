@@ -0,0 +1,108 @@
+// Package origin matches request origins against an allowlist that may mix
+// exact origins, wildcard subdomain patterns and regular expressions. Exact
+// entries behave the way the plain CORS origin list always has; the other two
+// forms exist for deployments whose origins cannot be enumerated ahead of time
+// (preview environments, per-branch subdomains). Patterns are compiled once at
+// startup so a typo fails the boot, not a request.
+package origin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegexPrefix marks an allowlist entry as a regular expression, e.g.
+// "regex:^https://pr-\d+\.example\.com$". The remainder is compiled verbatim —
+// anchoring is the author's responsibility.
+const RegexPrefix = "regex:"
+
+// Matcher answers "is this Origin header allowed?". Build one with New; the
+// zero value allows nothing.
+type Matcher struct {
+	exact     map[string]bool
+	wildcards []*regexp.Regexp
+	regexps   []*regexp.Regexp
+}
+
+// New compiles an allowlist. Entries are classified by shape: a "regex:"
+// prefix compiles the rest as a regular expression, a "*" makes a wildcard
+// pattern, anything else is an exact origin. Wildcards are deliberately
+// narrow — the "*" must be the entire leftmost host label
+// ("https://*.example.com") — because that is the only form with obvious
+// semantics; it matches one or more subdomain labels and never the bare apex.
+// An invalid entry returns an error so startup can refuse the policy.
+func New(entries []string) (*Matcher, error) {
+	m := &Matcher{exact: make(map[string]bool, len(entries))}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(entry, RegexPrefix):
+			re, err := regexp.Compile(strings.TrimPrefix(entry, RegexPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("invalid origin regex %q: %w", entry, err)
+			}
+			m.regexps = append(m.regexps, re)
+		case strings.Contains(entry, "*"):
+			re, err := compileWildcard(entry)
+			if err != nil {
+				return nil, err
+			}
+			m.wildcards = append(m.wildcards, re)
+		default:
+			m.exact[strings.ToLower(entry)] = true
+		}
+	}
+	return m, nil
+}
+
+// compileWildcard turns "scheme://*.domain" into an anchored regexp. The "*"
+// stands for one or more host labels, so both "app.example.com" and
+// "pr-1.preview.example.com" match "https://*.example.com" — but the apex
+// "example.com" does not, and neither does any host merely ending in the
+// domain ("evilexample.com").
+func compileWildcard(pattern string) (*regexp.Regexp, error) {
+	scheme, host, ok := strings.Cut(pattern, "://")
+	if !ok || scheme == "" || !strings.HasPrefix(host, "*.") || strings.Count(pattern, "*") != 1 {
+		return nil, fmt.Errorf("invalid origin wildcard %q: must look like \"https://*.example.com\"", pattern)
+	}
+	domain := host[len("*."):]
+	if domain == "" || strings.ContainsAny(domain, "/ ") {
+		return nil, fmt.Errorf("invalid origin wildcard %q: missing domain after \"*.\"", pattern)
+	}
+	expr := "^" + regexp.QuoteMeta(strings.ToLower(scheme)) + `://(?:[a-z0-9-]+\.)*[a-z0-9-]+\.` +
+		regexp.QuoteMeta(strings.ToLower(domain)) + "$"
+	return regexp.Compile(expr)
+}
+
+// Allow reports whether origin matches any entry. Exact and wildcard entries
+// compare case-insensitively (scheme and host are case-insensitive per RFC
+// 3986); regex entries see the origin as sent.
+func (m *Matcher) Allow(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	lower := strings.ToLower(origin)
+	if m.exact[lower] {
+		return true
+	}
+	for _, re := range m.wildcards {
+		if re.MatchString(lower) {
+			return true
+		}
+	}
+	for _, re := range m.regexps {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe summarizes the compiled policy for the startup log.
+func (m *Matcher) Describe() string {
+	return fmt.Sprintf("%d exact, %d wildcard, %d regex", len(m.exact), len(m.wildcards), len(m.regexps))
+}
@@ -0,0 +1,67 @@
+package origin
+
+import "testing"
+
+func TestMatcher_Allow(t *testing.T) {
+	m, err := New([]string{
+		"https://app.example.com",
+		"https://*.preview.example.com",
+		`regex:^https://pr-\d+\.ci\.example\.com$`,
+		"capacitor://localhost",
+		"", // blank entries are skipped, not errors
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	cases := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"HTTPS://APP.EXAMPLE.COM", true}, // scheme and host are case-insensitive
+		{"https://app.example.com.evil.com", false},
+		{"capacitor://localhost", true},
+
+		{"https://a.preview.example.com", true},
+		{"https://deep.nested.preview.example.com", true},
+		{"https://preview.example.com", false},     // apex: the "*" needs a label
+		{"https://evilpreview.example.com", false}, // suffix is not containment
+		{"http://a.preview.example.com", false},    // scheme must match
+		{"https://a.preview.example.com:8443", false},
+
+		{"https://pr-42.ci.example.com", true},
+		{"https://pr-x.ci.example.com", false},
+
+		{"", false},
+		{"https://unrelated.com", false},
+	}
+	for _, tc := range cases {
+		if got := m.Allow(tc.origin); got != tc.want {
+			t.Errorf("Allow(%q) = %v, want %v", tc.origin, got, tc.want)
+		}
+	}
+}
+
+func TestNew_RejectsBadPatternsAtStartup(t *testing.T) {
+	bad := []string{
+		"regex:^https://(unclosed$", // invalid regex
+		"https://sub.*.example.com", // "*" must be the leftmost label
+		"https://*example.com",      // no "." after the "*"
+		"*.example.com",             // missing scheme
+		"https://*.",                // missing domain
+		"https://*.a.com/*",         // more than one "*"
+	}
+	for _, pattern := range bad {
+		if _, err := New([]string{pattern}); err == nil {
+			t.Errorf("New(%q) accepted an invalid pattern", pattern)
+		}
+	}
+}
+
+func TestZeroMatcherAllowsNothing(t *testing.T) {
+	var m Matcher
+	if m.Allow("https://anything.com") {
+		t.Error("zero Matcher must deny every origin")
+	}
+}
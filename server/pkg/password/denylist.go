@@ -0,0 +1,26 @@
+package password
+
+// commonPasswords is a small embedded denylist of the passwords that top every breach
+// corpus. Matched case-insensitively and exactly — fuzzy matching ("p@ssword1") is the
+// breach checker's job. At the default 12-character floor most of these are unreachable
+// anyway; the list earns its keep on instances that lower MQVI_PASSWORD_MIN_LENGTH, and
+// it must never be the argument for lowering it.
+var commonPasswords = map[string]struct{}{}
+
+func init() {
+	for _, p := range []string{
+		"123456", "123456789", "12345678", "1234567890", "123456789012",
+		"password", "password1", "password123", "password1234", "passwort",
+		"qwerty", "qwerty123", "qwertyuiop", "qwertyuiop123", "1q2w3e4r5t6y",
+		"abc123", "abcd1234", "iloveyou", "iloveyou1234",
+		"admin", "admin123", "administrator", "letmein", "letmein12345",
+		"welcome", "welcome1", "welcome12345", "monkey", "dragon",
+		"sunshine", "princess", "football", "baseball", "superman",
+		"trustno1", "master", "shadow", "michael", "jennifer",
+		"111111", "000000", "121212", "654321", "666666",
+		"1qaz2wsx", "zaq12wsx", "passw0rd", "p@ssw0rd", "changeme",
+		"secret", "secret123", "default", "test1234", "temp1234",
+	} {
+		commonPasswords[p] = struct{}{}
+	}
+}
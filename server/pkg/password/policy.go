@@ -4,8 +4,12 @@ package password
 
 import (
 	"errors"
+	"fmt"
 	"strings"
+	"unicode"
 	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // MinLength follows NIST SP 800-63B: a length floor instead of composition rules. Requiring
@@ -19,6 +23,11 @@ const MinLength = 12
 // pre-hashing before bcrypt, which changes the stored format.
 const MaxBytes = 72
 
+// DefaultBcryptCost is what passwords are hashed at when the operator has not set
+// MQVI_PASSWORD_BCRYPT_COST. Raising it slows offline cracking of a stolen database;
+// it also slows every login by the same factor.
+const DefaultBcryptCost = 12
+
 // The identity is a problem when the password is made of it, not when it happens to contain
 // it: user "ali" must still be able to write "kaliteli sabun kullanırım". So it is rejected
 // only when it takes up at least a third of the password (identityShare), or when stripping
@@ -33,17 +42,63 @@ var (
 	ErrTooLong          = errors.New("password is too long (72 bytes; Turkish letters count as two)")
 	ErrContainsIdentity = errors.New("password must not be built out of your username or email")
 	ErrBreached         = errors.New("this password has appeared in a data breach, choose another")
+	ErrTooCommon        = errors.New("this password is among the most commonly used, choose another")
+	ErrMissingClass     = errors.New("password is missing a required character class")
 )
 
+// Policy is the instance's configured password rules. The zero value is not usable —
+// construct via Default or fill every field from config.
+type Policy struct {
+	// MinLength is the rune-count floor. The length check is the load-bearing rule;
+	// everything below is optional.
+	MinLength int
+	// RequireUppercase, RequireDigit and RequireSymbol are composition rules, off by
+	// default — NIST advises against them, and the MinLength doc above says why. They
+	// exist for instances whose compliance regime demands them anyway.
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	// BcryptCost is the work factor for new hashes. bcrypt encodes the cost in the hash,
+	// so existing hashes keep theirs until the password next changes.
+	BcryptCost int
+}
+
+// Default is the policy an instance runs with no knobs set.
+func Default() Policy {
+	return Policy{MinLength: MinLength, BcryptCost: DefaultBcryptCost}
+}
+
+// Cost returns the bcrypt work factor, clamped into bcrypt's accepted range so a zero-value
+// or out-of-range policy degrades to the default instead of making every hash call fail.
+func (p Policy) Cost() int {
+	if p.BcryptCost < bcrypt.MinCost || p.BcryptCost > bcrypt.MaxCost {
+		return DefaultBcryptCost
+	}
+	return p.BcryptCost
+}
+
 // Validate applies the offline rules. The breach lookup is separate — it needs the network.
-func Validate(password, username, email string) error {
-	if utf8.RuneCountInString(password) < MinLength {
-		return ErrTooShort
+func (p Policy) Validate(password, username, email string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		if p.MinLength == MinLength {
+			return ErrTooShort
+		}
+		return fmt.Errorf("%w: at least %d characters on this instance", ErrTooShort, p.MinLength)
 	}
 	if len(password) > MaxBytes {
 		return ErrTooLong
 	}
 
+	if err := p.checkClasses(password); err != nil {
+		return err
+	}
+
+	// The list is tiny next to the breach corpus, but it is always there — the HIBP lookup
+	// is optional and allowed to fail open.
+	if _, common := commonPasswords[strings.ToLower(password)]; common {
+		return ErrTooCommon
+	}
+
 	if builtFrom(password, username) {
 		return ErrContainsIdentity
 	}
@@ -55,6 +110,34 @@ func Validate(password, username, email string) error {
 	return nil
 }
 
+func (p Policy) checkClasses(password string) error {
+	var hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	switch {
+	case p.RequireUppercase && !hasUpper:
+		return fmt.Errorf("%w: an uppercase letter", ErrMissingClass)
+	case p.RequireDigit && !hasDigit:
+		return fmt.Errorf("%w: a digit", ErrMissingClass)
+	case p.RequireSymbol && !hasSymbol:
+		return fmt.Errorf("%w: a symbol", ErrMissingClass)
+	}
+	return nil
+}
+
+// Validate applies the default policy. Kept for callers that have no config in reach.
+func Validate(password, username, email string) error {
+	return Default().Validate(password, username, email)
+}
+
 // builtFrom reports whether the password is made of the identity rather than merely containing
 // it. Two ways it can be: the identity is a large slice of it, or it is repeated until almost
 // nothing else remains.
@@ -63,3 +63,58 @@ func TestMaxBytesMatchesBcrypt(t *testing.T) {
 		t.Fatalf("bcrypt accepted %d bytes — MaxBytes is lower than it needs to be", MaxBytes+1)
 	}
 }
+
+func TestPolicyComplexityRequirements(t *testing.T) {
+	p := Default()
+	p.RequireUppercase = true
+	p.RequireDigit = true
+	p.RequireSymbol = true
+
+	if err := p.Validate("mavi kedi kahve iciyor", "", ""); !errors.Is(err, ErrMissingClass) {
+		t.Errorf("passphrase with no classes = %v, want ErrMissingClass", err)
+	}
+	if err := p.Validate("Mavi kedi 3 kahve icti!", "", ""); err != nil {
+		t.Errorf("passphrase satisfying every class = %v, want nil", err)
+	}
+	// Off by default — the same passphrase must pass a default policy.
+	if err := Default().Validate("mavi kedi kahve iciyor", "", ""); err != nil {
+		t.Errorf("default policy = %v, want nil", err)
+	}
+}
+
+func TestPolicyConfigurableMinLength(t *testing.T) {
+	p := Default()
+	p.MinLength = 16
+
+	if err := p.Validate("abcdefghijkl", "", ""); !errors.Is(err, ErrTooShort) {
+		t.Errorf("12 runes against a 16 floor = %v, want ErrTooShort", err)
+	}
+	if err := p.Validate("abcdefghijklmnop", "", ""); err != nil {
+		t.Errorf("16 runes against a 16 floor = %v, want nil", err)
+	}
+}
+
+func TestDenylistRejectsCommonPasswords(t *testing.T) {
+	p := Default()
+	p.MinLength = 8 // the lowest config allows — where the list actually bites
+
+	if err := p.Validate("Password1234", "", ""); !errors.Is(err, ErrTooCommon) {
+		t.Errorf("denylisted password (case-folded) = %v, want ErrTooCommon", err)
+	}
+	if err := p.Validate("qwertyuiop123", "", ""); !errors.Is(err, ErrTooCommon) {
+		t.Errorf("denylisted password = %v, want ErrTooCommon", err)
+	}
+}
+
+// An unset or absurd cost must degrade to the default, not fail every hash call.
+func TestPolicyCostClampsToBcryptRange(t *testing.T) {
+	if got := (Policy{}).Cost(); got != DefaultBcryptCost {
+		t.Errorf("zero-value cost = %d, want %d", got, DefaultBcryptCost)
+	}
+	if got := (Policy{BcryptCost: 99}).Cost(); got != DefaultBcryptCost {
+		t.Errorf("out-of-range cost = %d, want %d", got, DefaultBcryptCost)
+	}
+	if got := (Policy{BcryptCost: 14}).Cost(); got != 14 {
+		t.Errorf("in-range cost = %d, want 14", got)
+	}
+}
@@ -24,6 +24,14 @@ type MessageRateLimiter struct {
 	window      time.Duration
 	cooldown    time.Duration
 	stopCleanup chan struct{}
+	stopOnce    sync.Once
+}
+
+// Stop terminates the cleanup goroutine. Idempotent; used by tests.
+func (rl *MessageRateLimiter) Stop() {
+	rl.stopOnce.Do(func() {
+		close(rl.stopCleanup)
+	})
 }
 
 func NewMessageRateLimiter(maxMessages int, window, cooldown time.Duration) *MessageRateLimiter {
@@ -43,44 +51,53 @@ func NewMessageRateLimiter(maxMessages int, window, cooldown time.Duration) *Mes
 // Allow checks if the user can send a message.
 // Flow: cooldown active → reject; window expired → reset; within window → count++.
 func (rl *MessageRateLimiter) Allow(userID string) bool {
+	return rl.AllowWithResult(userID).Allowed
+}
+
+// AllowWithResult is Allow plus a bucket snapshot for the X-RateLimit-* headers.
+// During a cooldown, Reset is the cooldown end rather than the window end —
+// that is when the budget actually comes back.
+func (rl *MessageRateLimiter) AllowWithResult(userID string) Result {
 	now := time.Now()
 
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	b, exists := rl.buckets[userID]
-	if !exists {
-		rl.buckets[userID] = &messageBucket{count: 1, windowStart: now}
-		return true
-	}
+	switch {
+	case !exists:
+		b = &messageBucket{count: 1, windowStart: now}
+		rl.buckets[userID] = b
 
 	// Still in cooldown?
-	if !b.cooldownUntil.IsZero() && now.Before(b.cooldownUntil) {
-		return false
-	}
+	case !b.cooldownUntil.IsZero() && now.Before(b.cooldownUntil):
+		return Result{Allowed: false, Limit: rl.maxMessages, Reset: b.cooldownUntil}
 
 	// Cooldown expired — start new window
-	if !b.cooldownUntil.IsZero() {
+	case !b.cooldownUntil.IsZero():
 		b.count = 1
 		b.windowStart = now
 		b.cooldownUntil = time.Time{}
-		return true
-	}
 
 	// Window expired — start new window
-	if now.Sub(b.windowStart) > rl.window {
+	case now.Sub(b.windowStart) > rl.window:
 		b.count = 1
 		b.windowStart = now
-		return true
-	}
 
-	b.count++
-	if b.count > rl.maxMessages {
-		b.cooldownUntil = now.Add(rl.cooldown)
-		return false
+	default:
+		b.count++
+		if b.count > rl.maxMessages {
+			b.cooldownUntil = now.Add(rl.cooldown)
+			return Result{Allowed: false, Limit: rl.maxMessages, Reset: b.cooldownUntil}
+		}
 	}
 
-	return true
+	return Result{
+		Allowed:   true,
+		Limit:     rl.maxMessages,
+		Remaining: rl.maxMessages - b.count,
+		Reset:     b.windowStart.Add(rl.window),
+	}
 }
 
 // CooldownSeconds returns the remaining cooldown in seconds for the Retry-After header.
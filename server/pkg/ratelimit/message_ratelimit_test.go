@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMessageRateLimiterAllowsBurstThenCoolsDown(t *testing.T) {
+	rl := NewMessageRateLimiter(5, 50*time.Millisecond, 80*time.Millisecond)
+	defer rl.Stop()
+
+	for i := 0; i < 5; i++ {
+		if !rl.Allow("u1") {
+			t.Fatalf("message %d rejected within burst", i+1)
+		}
+	}
+
+	if rl.Allow("u1") {
+		t.Fatal("expected message 6 to trip the limit")
+	}
+	if rl.Allow("u1") {
+		t.Fatal("expected cooldown to keep rejecting")
+	}
+	if secs := rl.CooldownSeconds("u1"); secs < 1 {
+		t.Fatalf("CooldownSeconds = %d, want >= 1 for the Retry-After header", secs)
+	}
+
+	// Buckets are per user — u1's cooldown must not spill onto u2.
+	if !rl.Allow("u2") {
+		t.Fatal("another user should be unaffected")
+	}
+}
+
+func TestMessageRateLimiterSteadyStateAcrossWindows(t *testing.T) {
+	rl := NewMessageRateLimiter(2, 30*time.Millisecond, 30*time.Millisecond)
+	defer rl.Stop()
+
+	// Staying at or under the budget every window never triggers a cooldown.
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 2; i++ {
+			if !rl.Allow("u1") {
+				t.Fatalf("round %d message %d rejected in steady state", round+1, i+1)
+			}
+		}
+		time.Sleep(40 * time.Millisecond)
+	}
+}
+
+func TestMessageRateLimiterRecoversAfterCooldown(t *testing.T) {
+	rl := NewMessageRateLimiter(1, 20*time.Millisecond, 30*time.Millisecond)
+	defer rl.Stop()
+
+	rl.Allow("u1")
+	if rl.Allow("u1") {
+		t.Fatal("expected second message to trip the limit")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if !rl.Allow("u1") {
+		t.Fatal("expected a fresh window once the cooldown expired")
+	}
+}
+
+func TestMessageRateLimiterCleanupDropsStaleBuckets(t *testing.T) {
+	rl := NewMessageRateLimiter(1, 10*time.Millisecond, 10*time.Millisecond)
+	defer rl.Stop()
+
+	rl.Allow("stale")
+	rl.Allow("stale") // trips the limit, so the cooldown must expire too
+
+	time.Sleep(30 * time.Millisecond)
+	rl.Allow("active")
+	rl.cleanup()
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	if _, ok := rl.buckets["stale"]; ok {
+		t.Error("stale bucket should have been removed")
+	}
+	if _, ok := rl.buckets["active"]; !ok {
+		t.Error("active bucket should survive cleanup")
+	}
+}
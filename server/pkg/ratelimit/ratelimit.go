@@ -40,6 +40,11 @@ func NewLoginRateLimiter(maxAttempts int, window time.Duration) *LoginRateLimite
 // Allow checks if a login attempt is permitted. Each call increments the counter.
 // Call Reset() after successful login to clear the counter.
 func (rl *LoginRateLimiter) Allow(ip string) bool {
+	return rl.AllowWithResult(ip).Allowed
+}
+
+// AllowWithResult is Allow plus a bucket snapshot for the X-RateLimit-* headers.
+func (rl *LoginRateLimiter) AllowWithResult(ip string) Result {
 	now := time.Now()
 
 	rl.mu.Lock()
@@ -47,18 +52,25 @@ func (rl *LoginRateLimiter) Allow(ip string) bool {
 
 	b, exists := rl.buckets[ip]
 	if !exists {
-		rl.buckets[ip] = &bucket{count: 1, windowStart: now}
-		return true
-	}
-
-	if now.Sub(b.windowStart) > rl.window {
+		b = &bucket{count: 1, windowStart: now}
+		rl.buckets[ip] = b
+	} else if now.Sub(b.windowStart) > rl.window {
 		b.count = 1
 		b.windowStart = now
-		return true
+	} else {
+		b.count++
 	}
 
-	b.count++
-	return b.count <= rl.maxAttempts
+	remaining := rl.maxAttempts - b.count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   b.count <= rl.maxAttempts,
+		Limit:     rl.maxAttempts,
+		Remaining: remaining,
+		Reset:     b.windowStart.Add(rl.window),
+	}
 }
 
 // Reset clears the counter after a successful login.
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Result is the outcome of one Allow decision plus enough bucket state for the
+// standard X-RateLimit-* response headers, so well-behaved clients can back
+// off before they ever see a 429.
+type Result struct {
+	Allowed   bool
+	Limit     int       // budget per window
+	Remaining int       // calls left in the current window (0 when rejected)
+	Reset     time.Time // when the budget is whole again (window or cooldown end)
+}
+
+// RetryAfterSeconds is the whole-second wait until Reset, rounded up.
+func (res Result) RetryAfterSeconds() int {
+	remaining := time.Until(res.Reset)
+	if remaining <= 0 {
+		return 0
+	}
+	return int(remaining.Seconds()) + 1
+}
+
+// SetHeaders writes X-RateLimit-Limit/-Remaining/-Reset on every response and
+// Retry-After on rejections. Every rate-limited endpoint translates through
+// here, so one client-side backoff strategy covers them all.
+func (res Result) SetHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(res.Reset.Unix(), 10))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(res.RetryAfterSeconds()))
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMessageLimiterResultCountsDownAndRejects(t *testing.T) {
+	rl := NewMessageRateLimiter(3, time.Minute, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		res := rl.AllowWithResult("u1")
+		if !res.Allowed {
+			t.Fatalf("call %d rejected", i+1)
+		}
+		if res.Limit != 3 {
+			t.Fatalf("limit = %d, want 3", res.Limit)
+		}
+		if want := 3 - (i + 1); res.Remaining != want {
+			t.Fatalf("call %d: remaining = %d, want %d", i+1, res.Remaining, want)
+		}
+	}
+
+	res := rl.AllowWithResult("u1")
+	if res.Allowed {
+		t.Fatal("expected call 4 to be rejected")
+	}
+	if res.Remaining != 0 {
+		t.Fatalf("rejected remaining = %d, want 0", res.Remaining)
+	}
+	// During cooldown, Reset must point at the cooldown end — that's when the
+	// budget actually comes back, not the window end.
+	if until := time.Until(res.Reset); until < 50*time.Second {
+		t.Fatalf("reset only %v out, want the ~1m cooldown", until)
+	}
+	if res.RetryAfterSeconds() < 1 {
+		t.Fatalf("retry after = %d, want >= 1 on rejection", res.RetryAfterSeconds())
+	}
+}
+
+func TestLoginLimiterResultTracksWindow(t *testing.T) {
+	rl := NewLoginRateLimiter(2, time.Minute)
+
+	if res := rl.AllowWithResult("203.0.113.10"); !res.Allowed || res.Remaining != 1 {
+		t.Fatalf("first attempt: allowed=%v remaining=%d", res.Allowed, res.Remaining)
+	}
+	rl.AllowWithResult("203.0.113.10")
+	if res := rl.AllowWithResult("203.0.113.10"); res.Allowed {
+		t.Fatal("expected third attempt to be rejected")
+	}
+
+	// Reset (successful login) restores the full budget.
+	rl.Reset("203.0.113.10")
+	if res := rl.AllowWithResult("203.0.113.10"); !res.Allowed || res.Remaining != 1 {
+		t.Fatalf("post-reset attempt: allowed=%v remaining=%d", res.Allowed, res.Remaining)
+	}
+}
+
+func TestResultSetHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Result{Allowed: true, Limit: 5, Remaining: 2, Reset: time.Now().Add(time.Minute)}.SetHeaders(rec)
+
+	if got := rec.Header().Get("X-RateLimit-Limit"); got != "5" {
+		t.Errorf("X-RateLimit-Limit = %q, want 5", got)
+	}
+	if got := rec.Header().Get("X-RateLimit-Remaining"); got != "2" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 2", got)
+	}
+	if rec.Header().Get("X-RateLimit-Reset") == "" {
+		t.Error("X-RateLimit-Reset missing")
+	}
+	if rec.Header().Get("Retry-After") != "" {
+		t.Error("Retry-After must only be set on rejections")
+	}
+
+	rec = httptest.NewRecorder()
+	Result{Allowed: false, Limit: 5, Reset: time.Now().Add(30 * time.Second)}.SetHeaders(rec)
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After missing on rejection")
+	}
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// AuditLogRepository defines data access for server audit logs. Entries are
+// append-only from the API's point of view: there is deliberately no Update,
+// and no caller-directed Delete. Rows leave the table only through the
+// retention sweep (PruneOlderThan/PruneOverPerServerCap) or when their server
+// is deleted (FK cascade).
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	// ListByServer returns entries newest-first with actor/target usernames
+	// joined in. actionType filters exact matches when non-empty; beforeID is
+	// an exclusive cursor; limit is applied as-is (callers fetch limit+1 to
+	// detect further pages).
+	ListByServer(ctx context.Context, serverID, actionType, beforeID string, limit int) ([]models.AuditLog, error)
+	// PruneOlderThan deletes at most limit entries older than the given number
+	// of days, oldest first, and reports how many went. The limit bounds one
+	// transaction; callers loop until a short batch.
+	PruneOlderThan(ctx context.Context, days, limit int) (int64, error)
+	// PruneOverPerServerCap deletes at most limit entries that fall beyond the
+	// newest maxPerServer entries of their server, oldest first.
+	PruneOverPerServerCap(ctx context.Context, maxPerServer, limit int) (int64, error)
+}
@@ -0,0 +1,14 @@
+// Package repository — CategoryMuteRepository interface.
+// Per-user category mute settings. Expired mutes are lazily filtered on read.
+package repository
+
+import "context"
+
+// CategoryMuteRepository defines data access for category mutes.
+type CategoryMuteRepository interface {
+	// Upsert adds or updates a category mute. nil mutedUntil means indefinite.
+	Upsert(ctx context.Context, userID, categoryID, serverID string, mutedUntil *string) error
+	Delete(ctx context.Context, userID, categoryID string) error
+	// GetMutedCategoryIDs returns active (non-expired) muted category IDs.
+	GetMutedCategoryIDs(ctx context.Context, userID string) ([]string, error)
+}
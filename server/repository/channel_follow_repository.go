@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// ChannelFollowRepository defines data access for channel follows.
+type ChannelFollowRepository interface {
+	Create(ctx context.Context, follow *models.ChannelFollow) error
+
+	// Delete removes the follow for a (source, target) pair.
+	Delete(ctx context.Context, sourceChannelID, targetChannelID string) error
+
+	// ListBySource returns follows whose source is the given channel —
+	// both the fan-out lookup and the management list.
+	ListBySource(ctx context.Context, sourceChannelID string) ([]models.ChannelFollow, error)
+
+	// Exists reports whether a (source, target) follow is already in place.
+	Exists(ctx context.Context, sourceChannelID, targetChannelID string) (bool, error)
+}
@@ -6,23 +6,35 @@ import (
 	"github.com/akinalp/mqvi/models"
 )
 
-// ChannelPermissionRepository defines data access for per-channel role permission overrides.
-// Each (channel_id, role_id) pair stores allow/deny bitmasks that override the role's base permissions.
+// ChannelPermissionRepository defines data access for per-channel permission overrides.
+// Each (channel_id, target_type, target_id) triple stores allow/deny bitmasks; targets
+// are either roles (applied together) or a single member (applied last, on top).
 type ChannelPermissionRepository interface {
-	// GetByChannel returns all overrides for a channel.
+	// GetByChannel returns all overrides for a channel (role and member targets).
 	GetByChannel(ctx context.Context, channelID string) ([]models.ChannelPermissionOverride, error)
 
-	// GetByChannelAndRoles returns overrides for specific roles in a channel (for permission resolution).
+	// GetByChannelAndRoles returns role-target overrides for specific roles in a channel
+	// (for permission resolution).
 	GetByChannelAndRoles(ctx context.Context, channelID string, roleIDs []string) ([]models.ChannelPermissionOverride, error)
 
-	// GetByRoles returns ALL channel overrides for the given roles (across all channels).
-	// Used for bulk permission resolution when filtering channel lists (avoids N+1).
+	// GetMemberOverride returns the member-target override for one user in a channel,
+	// or nil when none exists.
+	GetMemberOverride(ctx context.Context, channelID, userID string) (*models.ChannelPermissionOverride, error)
+
+	// GetByRoles returns ALL role-target channel overrides for the given roles (across
+	// all channels). Used for bulk permission resolution when filtering channel lists
+	// (avoids N+1).
 	GetByRoles(ctx context.Context, roleIDs []string) ([]models.ChannelPermissionOverride, error)
 
-	// Set creates or updates an override for a (channel_id, role_id) pair (UPSERT).
+	// GetByMember returns ALL member-target overrides for one user across all channels
+	// (the member-side counterpart of GetByRoles for visibility filtering).
+	GetByMember(ctx context.Context, userID string) ([]models.ChannelPermissionOverride, error)
+
+	// Set creates or updates an override for a (channel_id, target_type, target_id)
+	// triple (UPSERT).
 	Set(ctx context.Context, override *models.ChannelPermissionOverride) error
 
-	Delete(ctx context.Context, channelID, roleID string) error
+	Delete(ctx context.Context, channelID, targetType, targetID string) error
 
 	// DeleteAllByChannel removes all overrides for a channel.
 	DeleteAllByChannel(ctx context.Context, channelID string) error
@@ -13,6 +13,9 @@ type DMRepository interface {
 	GetChannelByID(ctx context.Context, id string) (*models.DMChannel, error)
 	ListChannels(ctx context.Context, userID string) ([]models.DMChannelWithUser, error)
 	CreateChannel(ctx context.Context, channel *models.DMChannel) error
+	// CreateGroupChannel inserts a group channel (pair columns NULL) and its
+	// initial member rows, the creator included in memberIDs.
+	CreateGroupChannel(ctx context.Context, channel *models.DMChannel, memberIDs []string) error
 	UpdateChannelStatus(ctx context.Context, channelID, status string) error
 	SetInitiatedBy(ctx context.Context, channelID, userID string) error
 	CountMessagesBySender(ctx context.Context, channelID, userID string) (int, error)
@@ -22,6 +25,16 @@ type DMRepository interface {
 	// future) on the DM channel — used to suppress push notifications.
 	IsChannelMuted(ctx context.Context, userID, channelID string) (bool, error)
 
+	// Membership (dm_channel_members). 1:1 channels have their pair backfilled
+	// into the join table, so these read uniformly across both kinds.
+	GetChannelMemberIDs(ctx context.Context, channelID string) ([]string, error)
+	IsChannelMember(ctx context.Context, channelID, userID string) (bool, error)
+	AddChannelMember(ctx context.Context, channelID, userID string) error
+	RemoveChannelMember(ctx context.Context, channelID, userID string) error
+	// GetGroupMembersByChannelIDs batch-loads member profiles for the channel
+	// list (avoids N+1 on group channels).
+	GetGroupMembersByChannelIDs(ctx context.Context, channelIDs []string) (map[string][]models.User, error)
+
 	// Read state
 	//
 	// MarkRead advances the user's read watermark to the given message and reports whether
@@ -37,6 +50,9 @@ type DMRepository interface {
 	// the other participant. Read back AFTER a MarkRead rather than assumed to be zero —
 	// a message can land between the client picking a watermark and the write landing.
 	CountUnread(ctx context.Context, userID, channelID string) (int, error)
+	// GetReadState returns the user's current watermark in the conversation,
+	// nil (no error) when they have never marked it read.
+	GetReadState(ctx context.Context, userID, channelID string) (*models.DMReadState, error)
 	// HasRead reports whether the user's watermark has passed this message. It is what a
 	// deferred push consults before it fires: "the user read it" is PROVED here, never claimed
 	// by a client. A message id that isn't in the channel reads as unread.
@@ -44,15 +60,26 @@ type DMRepository interface {
 
 	// Message operations
 	GetMessages(ctx context.Context, channelID string, beforeID string, limit int) ([]models.DMMessage, error)
+	// GetMessagesAround mirrors MessageRepository.GetAroundID for dm_messages:
+	// the target plus up to limit messages each side, ascending, with flags for
+	// more history in either direction.
+	GetMessagesAround(ctx context.Context, channelID, messageID string, limit int) ([]models.DMMessage, bool, bool, error)
 	GetMessageByID(ctx context.Context, id string) (*models.DMMessage, error)
 	CreateMessage(ctx context.Context, msg *models.DMMessage) error
 	UpdateMessage(ctx context.Context, id string, req *models.UpdateDMMessageRequest) error
 	DeleteMessage(ctx context.Context, id string) error
+	// ReplyChainDepth mirrors MessageRepository.ReplyChainDepth for dm_messages.
+	ReplyChainDepth(ctx context.Context, messageID string, maxDepth int) (int, error)
+	// RecordEdit mirrors MessageRepository.RecordEdit for dm_message_edits.
+	RecordEdit(ctx context.Context, messageID, oldContent string, keep int) error
 
 	// Reaction operations
 	ToggleReaction(ctx context.Context, messageID, userID, emoji string) (added bool, err error)
+	CountUserReactions(ctx context.Context, messageID, userID string) (int, error)
 	GetReactionsByMessageID(ctx context.Context, messageID string) ([]models.ReactionGroup, error)
 	GetReactionsByMessageIDs(ctx context.Context, messageIDs []string) (map[string][]models.ReactionGroup, error)
+	// ListReactors mirrors ReactionRepository.ListReactors for dm_reactions.
+	ListReactors(ctx context.Context, messageID, emoji string, limit, offset int) ([]models.ReactionUser, int, error)
 
 	// Pin operations
 	PinMessage(ctx context.Context, messageID string) error
@@ -0,0 +1,152 @@
+// Package repository — ExportJobRepository, server history export job rows.
+// Rows go away with the server via ON DELETE CASCADE; the cleanup sweep
+// deletes expired ones (and their archives) after the TTL.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+// ExportJobRepository defines data access for export jobs.
+type ExportJobRepository interface {
+	Create(ctx context.Context, job *models.ExportJob) error
+	GetByID(ctx context.Context, id string) (*models.ExportJob, error)
+	// HasActive reports whether the server already has a pending or running job,
+	// so a second kick-off doesn't duplicate the work.
+	HasActive(ctx context.Context, serverID string) (bool, error)
+	MarkRunning(ctx context.Context, id string) error
+	MarkCompleted(ctx context.Context, id, filePath string, fileSize int64, messageCount int) error
+	MarkFailed(ctx context.Context, id, reason string) error
+	// ListExpired returns jobs created before the cutoff, whatever their status —
+	// a job stuck in running past the TTL is dead and its partial file is garbage.
+	ListExpired(ctx context.Context, cutoff time.Time) ([]models.ExportJob, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type sqliteExportJobRepo struct {
+	db *sql.DB
+}
+
+func NewSQLiteExportJobRepo(db *sql.DB) ExportJobRepository {
+	return &sqliteExportJobRepo{db: db}
+}
+
+const exportJobSelect = `
+	SELECT id, server_id, user_id, format, status, file_path, file_size, message_count, error, created_at, completed_at
+	FROM export_jobs`
+
+func (r *sqliteExportJobRepo) Create(ctx context.Context, job *models.ExportJob) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO export_jobs (id, server_id, user_id, format, status)
+		VALUES (?, ?, ?, ?, ?)
+	`, job.ID, job.ServerID, job.UserID, job.Format, job.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteExportJobRepo) GetByID(ctx context.Context, id string) (*models.ExportJob, error) {
+	row := r.db.QueryRowContext(ctx, exportJobSelect+` WHERE id = ?`, id)
+	job, err := scanExportJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: export job not found", pkg.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *sqliteExportJobRepo) HasActive(ctx context.Context, serverID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM export_jobs
+		WHERE server_id = ? AND status IN (?, ?)
+	`, serverID, models.ExportStatusPending, models.ExportStatusRunning).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check active export jobs: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *sqliteExportJobRepo) MarkRunning(ctx context.Context, id string) error {
+	return r.setStatus(ctx, `UPDATE export_jobs SET status = ? WHERE id = ?`,
+		models.ExportStatusRunning, id)
+}
+
+func (r *sqliteExportJobRepo) MarkCompleted(ctx context.Context, id, filePath string, fileSize int64, messageCount int) error {
+	return r.setStatus(ctx, `
+		UPDATE export_jobs
+		SET status = ?, file_path = ?, file_size = ?, message_count = ?, completed_at = datetime('now')
+		WHERE id = ?
+	`, models.ExportStatusCompleted, filePath, fileSize, messageCount, id)
+}
+
+func (r *sqliteExportJobRepo) MarkFailed(ctx context.Context, id, reason string) error {
+	return r.setStatus(ctx, `
+		UPDATE export_jobs
+		SET status = ?, error = ?, completed_at = datetime('now')
+		WHERE id = ?
+	`, models.ExportStatusFailed, reason, id)
+}
+
+func (r *sqliteExportJobRepo) setStatus(ctx context.Context, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: export job not found", pkg.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *sqliteExportJobRepo) ListExpired(ctx context.Context, cutoff time.Time) ([]models.ExportJob, error) {
+	rows, err := r.db.QueryContext(ctx, exportJobSelect+` WHERE created_at < ?`,
+		cutoff.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired export jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []models.ExportJob
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expired export job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *sqliteExportJobRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE id = ?`, id)
+	return err
+}
+
+// scanExportJob scans one row from exportJobSelect; works for both *sql.Row and *sql.Rows.
+func scanExportJob(row interface{ Scan(...any) error }) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := row.Scan(
+		&job.ID, &job.ServerID, &job.UserID, &job.Format, &job.Status,
+		&job.FilePath, &job.FileSize, &job.MessageCount, &job.Error,
+		&job.CreatedAt, &job.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
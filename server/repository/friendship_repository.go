@@ -22,6 +22,10 @@ type FriendshipRepository interface {
 	// ListIncoming returns pending requests where friend_id = me.
 	ListIncoming(ctx context.Context, userID string) ([]models.FriendshipWithUser, error)
 
+	// CountIncoming is ListIncoming as a bare count — the ready-event badge
+	// without the user join.
+	CountIncoming(ctx context.Context, userID string) (int, error)
+
 	// ListOutgoing returns pending requests where user_id = me.
 	ListOutgoing(ctx context.Context, userID string) ([]models.FriendshipWithUser, error)
 
@@ -36,4 +40,9 @@ type FriendshipRepository interface {
 
 	// IsBlocked checks if a block exists in either direction between two users.
 	IsBlocked(ctx context.Context, userA, userB string) (bool, error)
+
+	// HasBlocked checks one direction only: blockerID has blocked targetID.
+	// Used where the two sides differ — a mention from someone you blocked must
+	// not ping you, but your block should not mute your own mentions for them.
+	HasBlocked(ctx context.Context, blockerID, targetID string) (bool, error)
 }
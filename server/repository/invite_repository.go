@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 )
@@ -20,4 +21,7 @@ type InviteRepository interface {
 	// DecrementUses gives back one use (compensation for a post-consume join failure).
 	// Best-effort: guarded by uses > 0, 0 rows is a no-op, not an error.
 	DecrementUses(ctx context.Context, code string) error
+	// DeleteExpired removes every invite whose expires_at is at or before now and
+	// returns how many were deleted. Invites without an expiry are never touched.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,19 @@
+// Package repository — KeywordAlertRepository interface.
+// Per-user highlight words, matched against message content on create.
+package repository
+
+import "context"
+
+// KeywordAlertRepository defines data access for keyword alerts.
+type KeywordAlertRepository interface {
+	// Add registers a keyword for a user. Idempotent — re-adding an existing
+	// keyword is not an error.
+	Add(ctx context.Context, userID, keyword string) error
+	Delete(ctx context.Context, userID, keyword string) error
+	// ListByUser returns the user's keywords in insertion order.
+	ListByUser(ctx context.Context, userID string) ([]string, error)
+	// GetByUserIDs returns keywords for many users in one query — the shape the
+	// message-create path needs (candidate recipients are the channel's online
+	// viewers). Users without keywords are absent from the map.
+	GetByUserIDs(ctx context.Context, userIDs []string) (map[string][]string, error)
+}
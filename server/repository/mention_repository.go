@@ -6,6 +6,9 @@ import "context"
 // GetByMessageIDs batch-loads mentions for multiple messages (avoids N+1).
 type MentionRepository interface {
 	SaveMentions(ctx context.Context, messageID string, userIDs []string) error
+	// SaveMentionsWithKind is SaveMentions with an explicit kind (e.g. the
+	// "here" rows an @here expands to). Rows already present keep their kind.
+	SaveMentionsWithKind(ctx context.Context, messageID string, userIDs []string, kind string) error
 	DeleteByMessageID(ctx context.Context, messageID string) error
 	GetMentionedUserIDs(ctx context.Context, messageID string) ([]string, error)
 	GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string][]string, error)
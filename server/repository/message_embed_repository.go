@@ -0,0 +1,89 @@
+// Package repository — MessageEmbedRepository, per-message link embed rows.
+// One row per message (the first URL's unfurled metadata); rows go away with
+// the message via ON DELETE CASCADE.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// MessageEmbedRepository defines data access for message link embeds.
+type MessageEmbedRepository interface {
+	Upsert(ctx context.Context, embed *models.MessageEmbed) error
+	// GetByMessageIDs batch-loads embeds for multiple messages (avoids N+1).
+	// Messages without an embed simply have no map entry.
+	GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string]*models.MessageEmbed, error)
+	Delete(ctx context.Context, messageID string) error
+}
+
+type sqliteMessageEmbedRepo struct {
+	db *sql.DB
+}
+
+func NewSQLiteMessageEmbedRepo(db *sql.DB) MessageEmbedRepository {
+	return &sqliteMessageEmbedRepo{db: db}
+}
+
+func (r *sqliteMessageEmbedRepo) Upsert(ctx context.Context, embed *models.MessageEmbed) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO message_embeds (message_id, url, title, description, image_url, site_name, favicon_url)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(message_id) DO UPDATE SET
+			url = excluded.url,
+			title = excluded.title,
+			description = excluded.description,
+			image_url = excluded.image_url,
+			site_name = excluded.site_name,
+			favicon_url = excluded.favicon_url
+	`, embed.MessageID, embed.URL, embed.Title, embed.Description,
+		embed.ImageURL, embed.SiteName, embed.FaviconURL)
+	if err != nil {
+		return fmt.Errorf("failed to upsert message embed: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteMessageEmbedRepo) GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string]*models.MessageEmbed, error) {
+	result := make(map[string]*models.MessageEmbed)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(messageIDs))
+	args := make([]interface{}, len(messageIDs))
+	for i, id := range messageIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT message_id, url, title, description, image_url, site_name, favicon_url
+		FROM message_embeds WHERE message_id IN (%s)
+	`, strings.Join(placeholders, ", "))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch get message embeds: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.MessageEmbed
+		if err := rows.Scan(&e.MessageID, &e.URL, &e.Title, &e.Description,
+			&e.ImageURL, &e.SiteName, &e.FaviconURL); err != nil {
+			return nil, err
+		}
+		result[e.MessageID] = &e
+	}
+	return result, rows.Err()
+}
+
+func (r *sqliteMessageEmbedRepo) Delete(ctx context.Context, messageID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM message_embeds WHERE message_id = ?`, messageID)
+	return err
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 )
@@ -12,6 +13,43 @@ type MessageRepository interface {
 	Create(ctx context.Context, message *models.Message) error
 	GetByID(ctx context.Context, id string) (*models.Message, error)
 	GetByChannelID(ctx context.Context, channelID string, beforeID string, limit int) ([]models.Message, error)
+	// GetAroundID returns the target message with up to limit messages on each
+	// side, ascending. The booleans report whether more history exists before
+	// and after the returned window. pkg.ErrNotFound when the target is not in
+	// the channel.
+	GetAroundID(ctx context.Context, channelID, messageID string, limit int) ([]models.Message, bool, bool, error)
+	// ReplyChainDepth counts messages in the reply chain starting at messageID
+	// (inclusive), following reply_to_id upward. The walk stops at maxDepth, so
+	// the result is capped there even for longer (or cyclic) chains.
+	ReplyChainDepth(ctx context.Context, messageID string, maxDepth int) (int, error)
 	Update(ctx context.Context, message *models.Message) error
 	Delete(ctx context.Context, id string) error
+	// GetByIDs returns id/channel/author/created_at for the given messages —
+	// the light fields bulk delete needs for validation and bookkeeping.
+	// Missing IDs are simply absent from the result.
+	GetByIDs(ctx context.Context, ids []string) ([]models.Message, error)
+	// ListRecentIDsByUser returns up to limit message IDs one user posted in the
+	// channel since the given time, newest first.
+	ListRecentIDsByUser(ctx context.Context, channelID, userID string, since time.Time, limit int) ([]string, error)
+	// ListExpired returns up to limit messages whose expires_at has passed,
+	// oldest deadline first, skipping pinned messages. Same light projection
+	// as GetByIDs — what the expiry sweep needs for bookkeeping.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error)
+	// ListRetentionExpired returns up to limit messages older than their
+	// server's message_retention_days window, oldest first, skipping pinned
+	// messages and servers with retention off. Same projection as ListExpired.
+	ListRetentionExpired(ctx context.Context, limit int) ([]models.Message, error)
+	// DeleteBulk deletes the named messages, restricted to the channel — a single
+	// statement, so the batch lands or fails atomically. Returns rows deleted.
+	DeleteBulk(ctx context.Context, channelID string, ids []string) (int, error)
+	// Count returns the total number of channel messages. Used by public stats.
+	Count(ctx context.Context) (int, error)
+	// GetChannelStats returns count plus first/last message timestamps for one
+	// channel — a single aggregate over the channel_id index, no row scan.
+	GetChannelStats(ctx context.Context, channelID string) (*models.ChannelStats, error)
+	// RecordEdit stores the pre-edit content as a revision, then prunes the
+	// message's history down to the newest `keep` rows.
+	RecordEdit(ctx context.Context, messageID, oldContent string, keep int) error
+	// GetEditHistory returns a message's stored revisions, newest first.
+	GetEditHistory(ctx context.Context, messageID string) ([]models.MessageEdit, error)
 }
@@ -0,0 +1,20 @@
+// Package repository — NotificationSettingRepository interface.
+// Granular per-user notification settings (level + @everyone suppression) keyed
+// by scope. Sits alongside the binary server/channel mute tables, not on top of
+// them: absence of a row means the default level.
+package repository
+
+import (
+	"context"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// NotificationSettingRepository defines data access for notification settings.
+type NotificationSettingRepository interface {
+	Upsert(ctx context.Context, userID string, setting *models.NotificationSetting) error
+	// Get returns nil (no error) when the user has no row for this scope.
+	Get(ctx context.Context, userID, scopeType, scopeID string) (*models.NotificationSetting, error)
+	ListByUser(ctx context.Context, userID string) ([]models.NotificationSetting, error)
+	Delete(ctx context.Context, userID, scopeType, scopeID string) error
+}
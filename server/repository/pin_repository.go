@@ -13,4 +13,12 @@ type PinRepository interface {
 	Unpin(ctx context.Context, messageID string) error
 	IsPinned(ctx context.Context, messageID string) (bool, error)
 	CountByChannelID(ctx context.Context, channelID string) (int, error)
+	// UpdatePositions atomically rewrites pin_position for the given messages,
+	// in list order (first = 0). Scoped to channelID so a message ID from
+	// another channel cannot be moved through this call.
+	UpdatePositions(ctx context.Context, channelID string, messageIDs []string) error
+	// CountsByServerID returns pin counts for every channel in the server that
+	// has at least one pin, in one GROUP BY query. Channels absent from the map
+	// have no pins.
+	CountsByServerID(ctx context.Context, serverID string) (map[string]int, error)
 }
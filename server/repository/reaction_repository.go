@@ -14,4 +14,10 @@ type ReactionRepository interface {
 	Toggle(ctx context.Context, messageID, userID, emoji string) (added bool, err error)
 	GetByMessageID(ctx context.Context, messageID string) ([]models.ReactionGroup, error)
 	GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string][]models.ReactionGroup, error)
+	// CountUserReactions counts how many distinct emoji this user has on this
+	// message (one row per emoji thanks to the UNIQUE constraint).
+	CountUserReactions(ctx context.Context, messageID, userID string) (int, error)
+	// ListReactors returns one offset page of users who reacted with the emoji,
+	// in reaction order, plus the total for that emoji.
+	ListReactors(ctx context.Context, messageID, emoji string, limit, offset int) ([]models.ReactionUser, int, error)
 }
@@ -11,6 +11,10 @@ import (
 type ReadStateRepository interface {
 	Upsert(ctx context.Context, userID, channelID, messageID string) error
 	GetUnreadCounts(ctx context.Context, userID, serverID string) ([]models.UnreadInfo, error)
+	// GetAllUnreadCounts is GetUnreadCounts across every server the user is a member
+	// of, in one aggregate query — the snapshot a client pulls after a long disconnect
+	// instead of replaying the event stream.
+	GetAllUnreadCounts(ctx context.Context, userID string) ([]models.ServerUnreadInfo, error)
 	// MarkAllRead marks all text channels in a server as read (upserts each channel's latest message).
 	MarkAllRead(ctx context.Context, userID, serverID string) error
 	// IncrementUnreadCounts bumps unread_count for every user with a channel_reads
@@ -13,6 +13,9 @@ type RoleRepository interface {
 	GetAllByServer(ctx context.Context, serverID string) ([]models.Role, error)
 	GetDefaultByServer(ctx context.Context, serverID string) (*models.Role, error)
 	GetByUserIDAndServer(ctx context.Context, userID, serverID string) ([]models.Role, error)
+	// GetByServerForUsers batch-loads the listed users' roles in one query,
+	// keyed by user ID. Users without roles have no entry in the map.
+	GetByServerForUsers(ctx context.Context, serverID string, userIDs []string) (map[string][]models.Role, error)
 	GetMaxPosition(ctx context.Context, serverID string) (int, error)
 
 	// ─── Write ───
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// ScheduledMessageRepository defines data access for the scheduled-message
+// queue. Rows are pending work: delivered or cancelled rows are deleted,
+// never updated.
+type ScheduledMessageRepository interface {
+	Create(ctx context.Context, msg *models.ScheduledMessage) error
+	GetByID(ctx context.Context, id string) (*models.ScheduledMessage, error)
+	// GetPendingByChannelAndUser lists one author's queue for a channel,
+	// soonest first.
+	GetPendingByChannelAndUser(ctx context.Context, channelID, userID string) ([]models.ScheduledMessage, error)
+	// GetDue returns messages whose send_at has passed, oldest first, capped
+	// at limit so one poll can't stall on a huge backlog.
+	GetDue(ctx context.Context, now time.Time, limit int) ([]models.ScheduledMessage, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -12,8 +12,23 @@ type SearchResult struct {
 	TotalCount int              `json:"total_count"`
 }
 
+// SearchFilter narrows a server-scoped search. The zero value searches every
+// channel in the server with no author or attachment restriction.
+type SearchFilter struct {
+	// ChannelID restricts the search to a single channel.
+	ChannelID *string
+	// VisibleChannelIDs restricts results to the given channels. nil means no
+	// restriction; a non-nil empty slice matches nothing (fail closed).
+	VisibleChannelIDs []string
+	// FromUsername restricts results to messages authored by this username
+	// (case-insensitive). An unknown username simply matches nothing.
+	FromUsername *string
+	// HasAttachment keeps only messages carrying at least one attachment.
+	HasAttachment bool
+}
+
 // SearchRepository defines data access for FTS5 full-text message search.
-// serverID is required. channelID is optional (nil searches all server channels).
+// serverID is required; filter narrows the result set further.
 type SearchRepository interface {
-	Search(ctx context.Context, query string, serverID string, channelID *string, limit, offset int) (*SearchResult, error)
+	Search(ctx context.Context, query string, serverID string, filter SearchFilter, limit, offset int) (*SearchResult, error)
 }
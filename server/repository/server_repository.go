@@ -2,10 +2,17 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 )
 
+// ExpiredTimeout identifies a membership whose timeout the sweeper just cleared.
+type ExpiredTimeout struct {
+	ServerID string
+	UserID   string
+}
+
 // ServerRepository defines data access for servers and membership.
 type ServerRepository interface {
 	// ─── Server CRUD ───
@@ -40,6 +47,8 @@ type ServerRepository interface {
 	// ListActiveServerIDsByOwner returns IDs of non-soft-deleted servers owned by this user.
 	// Used by admin hard-delete to broadcast server_delete to members before cascade.
 	ListActiveServerIDsByOwner(ctx context.Context, ownerID string) ([]string, error)
+	// Count returns the number of active (not soft-deleted) servers. Used by public stats.
+	Count(ctx context.Context) (int, error)
 
 	// ─── Membership ───
 
@@ -47,6 +56,10 @@ type ServerRepository interface {
 	AddMember(ctx context.Context, serverID, userID string) error
 	RemoveMember(ctx context.Context, serverID, userID string) error
 	IsMember(ctx context.Context, serverID, userID string) (bool, error)
+	// HaveSharedServer reports whether two users are both members of at least
+	// one active (not soft-deleted) server. Used by the shared_servers DM
+	// privacy setting.
+	HaveSharedServer(ctx context.Context, userA, userB string) (bool, error)
 	GetMemberCount(ctx context.Context, serverID string) (int, error)
 	// GetMemberUserIDs returns all member user IDs for a server. Used by server
 	// restore to broadcast events to members who are not currently subscribed
@@ -55,6 +68,57 @@ type ServerRepository interface {
 	GetMemberUserIDs(ctx context.Context, serverID string) ([]string, error)
 	// GetMemberServerIDs returns all server IDs a user belongs to (for WS hub client.ServerIDs).
 	GetMemberServerIDs(ctx context.Context, userID string) ([]string, error)
+	// ListMemberPage returns one keyset page of members (joined_at order) plus
+	// the total count under the same filters. Soft-deleted users are excluded.
+	ListMemberPage(ctx context.Context, serverID string, params models.MemberListParams) ([]models.MemberPageRow, int, error)
+
+	// ─── Welcome screen / rules gate ───
+
+	GetWelcomeConfig(ctx context.Context, serverID string) (*models.WelcomeConfig, error)
+	UpdateWelcomeConfig(ctx context.Context, serverID string, cfg *models.WelcomeConfig) error
+	// SetRulesAccepted marks the membership as having accepted the server rules.
+	SetRulesAccepted(ctx context.Context, serverID, userID string) error
+	// RulesPending reports whether the member is still held read-only by the gate:
+	// rules_required on the server AND rules_accepted=0 on the membership.
+	RulesPending(ctx context.Context, serverID, userID string) (bool, error)
+
+	// SetMemberTimeout sets (nil clears) the member's moderation timeout.
+	// Returns ErrNotFound when the user is not a member of the server.
+	SetMemberTimeout(ctx context.Context, serverID, userID string, until *time.Time) error
+	// GetMemberTimeout returns the raw timeout_until (nil when unset or when the
+	// user is not a member). Callers decide whether a past timestamp still counts.
+	GetMemberTimeout(ctx context.Context, serverID, userID string) (*time.Time, error)
+	// ClearExpiredTimeouts nulls every timeout_until at or before now and returns
+	// the memberships it cleared, so the sweeper can notify clients.
+	ClearExpiredTimeouts(ctx context.Context, now time.Time) ([]ExpiredTimeout, error)
+
+	// SetMemberNickname sets (nil clears) the member's per-server nickname.
+	// Returns ErrNotFound when the user is not a member of the server.
+	SetMemberNickname(ctx context.Context, serverID, userID string, nickname *string) error
+	// GetMemberNickname returns the member's nickname (nil when unset or when
+	// the user is not a member).
+	GetMemberNickname(ctx context.Context, serverID, userID string) (*string, error)
+
+	// MarkMemberTemporary flags a membership as created by a temporary invite.
+	// Returns ErrNotFound when the user is not a member of the server.
+	MarkMemberTemporary(ctx context.Context, serverID, userID string) error
+	// ListRemovableTemporaryMemberships returns the server IDs of this user's
+	// temporary memberships that hold no non-default role — the ones the
+	// disconnect hook is allowed to remove.
+	ListRemovableTemporaryMemberships(ctx context.Context, userID string) ([]string, error)
+
+	// TransferOwnership reassigns owner_id from oldOwnerID to newOwnerID and moves the
+	// owner-role assignment with it, in one transaction. Returns ErrConflict when the
+	// server is no longer owned by oldOwnerID.
+	TransferOwnership(ctx context.Context, serverID, oldOwnerID, newOwnerID string) error
+
+	// SetVanityCode sets (nil clears) the server's vanity invite code. Overwriting frees
+	// the previous slug. Returns ErrConflict when another server already holds the code.
+	SetVanityCode(ctx context.Context, serverID string, code *string) error
+	// GetVanityCode returns the server's vanity code, nil when unset.
+	GetVanityCode(ctx context.Context, serverID string) (*string, error)
+	// GetServerIDByVanityCode resolves a vanity code to its active server's ID.
+	GetServerIDByVanityCode(ctx context.Context, code string) (string, error)
 
 	// UpdateMemberPositions updates a user's server ordering. Runs in a transaction.
 	UpdateMemberPositions(ctx context.Context, userID string, items []models.PositionUpdate) error
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// ServerTemplateRepository defines data access for server structure templates.
+// The structure document is stored as JSON and (un)marshalled here, so callers
+// only ever see the typed models.TemplateStructure.
+type ServerTemplateRepository interface {
+	Create(ctx context.Context, template *models.ServerTemplate) error
+	GetByID(ctx context.Context, id string) (*models.ServerTemplate, error)
+	// ListVisible returns the templates userID may instantiate: every public
+	// one plus their own private ones, newest first.
+	ListVisible(ctx context.Context, userID string) ([]models.ServerTemplate, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -105,7 +105,7 @@ func TestBuildAdminServerFilter_Type(t *testing.T) {
 		`INSERT INTO servers (id, name, owner_id, livekit_instance_id) VALUES ('s_orphan','O','o1','li_gone')`)
 
 	run := func(statuses, types []string) []string {
-		where, args := buildAdminServerFilter(statuses, types, "")
+		where, args := buildAdminServerFilter(statuses, types, "", "")
 		q := `SELECT s.id FROM servers s
 			LEFT JOIN users u ON s.owner_id = u.id
 			LEFT JOIN livekit_instances li ON s.livekit_instance_id = li.id ` + where
@@ -118,4 +118,17 @@ func TestBuildAdminServerFilter_Type(t *testing.T) {
 	eqIDs(t, run(nil, []string{"self"}), []string{"s_self", "s_none"})
 	// Both type values = no filter (all four).
 	eqIDs(t, run(nil, []string{"managed", "self"}), []string{"s_managed", "s_self", "s_orphan", "s_none"})
+
+	// Instance filter is an exact id match — NULL assignments never match, and it
+	// stacks with the type filter like every other dimension.
+	runInstance := func(types []string, instanceID string) []string {
+		where, args := buildAdminServerFilter(nil, types, "", instanceID)
+		q := `SELECT s.id FROM servers s
+			LEFT JOIN users u ON s.owner_id = u.id
+			LEFT JOIN livekit_instances li ON s.livekit_instance_id = li.id ` + where
+		return queryIDs(t, db, q, args...)
+	}
+	eqIDs(t, runInstance(nil, "li_managed"), []string{"s_managed"})
+	eqIDs(t, runInstance(nil, "li_gone"), []string{"s_orphan"})
+	eqIDs(t, runInstance([]string{"self"}, "li_managed"), nil)
 }
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// eqOrderedIDs is eqIDs without the sort — page order is part of the contract here.
+func eqOrderedIDs(t *testing.T, got, want []string) {
+	t.Helper()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("ids got %v want %v", got, want)
+	}
+}
+
+// ListAdminUsersPaged must report the unpaged total alongside every page, keep a
+// stable order across page boundaries, and apply the active-voice overlay inside
+// the SQL last_activity sort — patching the page after the query cannot fix a
+// row the sort already pushed onto another page.
+func TestListAdminUsersPaged(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteUserRepo(f.DB)
+	ctx := context.Background()
+
+	for i, id := range []string{"pa", "pb", "pc", "pd", "pe"} {
+		if _, err := f.DB.Exec(
+			`INSERT INTO users (id, username, password_hash, created_at) VALUES (?, ?, 'x', ?)`,
+			id, "user_"+id, fmt.Sprintf("2024-01-0%d 00:00:00", i+1),
+		); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+
+	page := func(p models.AdminListPageParams, voice []string) ([]string, int) {
+		t.Helper()
+		result, err := repo.ListAdminUsersPaged(ctx, p, 0, voice)
+		if err != nil {
+			t.Fatalf("list admin users: %v", err)
+		}
+		ids := make([]string, 0, len(result.Items))
+		for _, item := range result.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids, result.Total
+	}
+
+	// Newest-first paging; the total ignores limit/offset.
+	ids, total := page(models.AdminListPageParams{Limit: 2, Offset: 0, Sort: "created_at", Dir: "desc"}, nil)
+	eqOrderedIDs(t, ids, []string{"pe", "pd"})
+	if total != 5 {
+		t.Fatalf("total = %d, want 5", total)
+	}
+	ids, _ = page(models.AdminListPageParams{Limit: 2, Offset: 2, Sort: "created_at", Dir: "desc"}, nil)
+	eqOrderedIDs(t, ids, []string{"pc", "pb"})
+
+	// Search narrows the total, not just the returned page.
+	ids, total = page(models.AdminListPageParams{Limit: 10, Search: "user_pc"}, nil)
+	eqOrderedIDs(t, ids, []string{"pc"})
+	if total != 1 {
+		t.Fatalf("search total = %d, want 1", total)
+	}
+
+	// An unrecognized sort key never reaches SQL; it falls back to created_at DESC.
+	ids, _ = page(models.AdminListPageParams{Limit: 1, Sort: "username; DROP TABLE users"}, nil)
+	eqOrderedIDs(t, ids, []string{"pe"})
+
+	// A user currently in voice counts as active "now" for the last_activity sort
+	// even though the DB only stamps last_voice_activity at join time.
+	server := f.Server(dbtest.ServerSeed{})
+	channel := f.Channel("", server)
+	msg := f.Message(dbtest.MessageSeed{ChannelID: channel, UserID: "pa", Content: dbtest.Ptr("old news")})
+	f.ExecWithoutForeignKeys(`UPDATE messages SET created_at = '2024-06-01 00:00:00' WHERE id = ?`, msg)
+
+	ids, _ = page(models.AdminListPageParams{Limit: 2, Sort: "last_activity", Dir: "desc"}, []string{"pb"})
+	eqOrderedIDs(t, ids, []string{"pb", "pa"})
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+)
+
+type sqliteAuditLogRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteAuditLogRepo(db database.TxQuerier) AuditLogRepository {
+	return &sqliteAuditLogRepo{db: db}
+}
+
+func (r *sqliteAuditLogRepo) Create(ctx context.Context, entry *models.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (id, server_id, actor_id, action_type, target_id, metadata)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.ID, entry.ServerID, entry.ActorID, entry.ActionType, entry.TargetID, entry.Metadata,
+	).Scan(&entry.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListByServer pages newest-first. created_at is second-granular, so the
+// cursor compares rowid — strict insertion order, no ties to skip over.
+func (r *sqliteAuditLogRepo) ListByServer(ctx context.Context, serverID, actionType, beforeID string, limit int) ([]models.AuditLog, error) {
+	query := `
+		SELECT l.id, l.server_id, l.actor_id, l.action_type, l.target_id, l.metadata, l.created_at,
+		       a.username, t.username
+		FROM audit_logs l
+		LEFT JOIN users a ON l.actor_id = a.id
+		LEFT JOIN users t ON l.target_id = t.id
+		WHERE l.server_id = ?`
+	args := []any{serverID}
+
+	if actionType != "" {
+		query += ` AND l.action_type = ?`
+		args = append(args, actionType)
+	}
+	if beforeID != "" {
+		query += ` AND l.rowid < (SELECT rowid FROM audit_logs WHERE id = ?)`
+		args = append(args, beforeID)
+	}
+
+	query += `
+		ORDER BY l.rowid DESC
+		LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AuditLog
+	for rows.Next() {
+		var entry models.AuditLog
+		var targetID, metadata, actorName, targetName sql.NullString
+
+		if err := rows.Scan(
+			&entry.ID, &entry.ServerID, &entry.ActorID, &entry.ActionType,
+			&targetID, &metadata, &entry.CreatedAt,
+			&actorName, &targetName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+
+		if targetID.Valid {
+			entry.TargetID = &targetID.String
+		}
+		if metadata.Valid {
+			entry.Metadata = &metadata.String
+		}
+		if actorName.Valid {
+			entry.ActorUsername = &actorName.String
+		}
+		if targetName.Valid {
+			entry.TargetUsername = &targetName.String
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// PruneOlderThan computes the cutoff inside SQL — created_at is written by
+// CURRENT_TIMESTAMP, so comparing against datetime() stays in the same text
+// format. Oldest rows go first so a bounded batch never leaves a gap in the
+// middle of the log.
+func (r *sqliteAuditLogRepo) PruneOlderThan(ctx context.Context, days, limit int) (int64, error) {
+	query := `
+		DELETE FROM audit_logs
+		WHERE rowid IN (
+			SELECT rowid FROM audit_logs
+			WHERE created_at < datetime('now', '-' || ? || ' days')
+			ORDER BY rowid
+			LIMIT ?
+		)`
+
+	res, err := r.db.ExecContext(ctx, query, days, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit logs by age: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// PruneOverPerServerCap ranks each server's entries newest-first and deletes
+// rows ranked past the cap, oldest first. rowid is strict insertion order, so
+// the ranking never ties.
+func (r *sqliteAuditLogRepo) PruneOverPerServerCap(ctx context.Context, maxPerServer, limit int) (int64, error) {
+	query := `
+		DELETE FROM audit_logs
+		WHERE rowid IN (
+			SELECT rowid FROM (
+				SELECT rowid, ROW_NUMBER() OVER (PARTITION BY server_id ORDER BY rowid DESC) AS rank
+				FROM audit_logs
+			)
+			WHERE rank > ?
+			ORDER BY rowid
+			LIMIT ?
+		)`
+
+	res, err := r.db.ExecContext(ctx, query, maxPerServer, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit logs over per-server cap: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// Both prune cutoffs are computed inside SQL against CURRENT_TIMESTAMP-format
+// created_at values, so they get exercised on a real database — a format
+// mismatch would silently prune nothing.
+func TestAuditLogPruning(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteAuditLogRepo(f.DB)
+	ctx := context.Background()
+
+	owner := f.User("")
+	srvA := f.Server(dbtest.ServerSeed{OwnerID: owner})
+	srvB := f.Server(dbtest.ServerSeed{OwnerID: owner})
+
+	insert := func(id, serverID string) {
+		t.Helper()
+		if _, err := f.DB.Exec(
+			`INSERT INTO audit_logs (id, server_id, actor_id, action_type) VALUES (?, ?, ?, 'member_kick')`,
+			id, serverID, owner,
+		); err != nil {
+			t.Fatalf("insert %s: %v", id, err)
+		}
+	}
+	remaining := func() map[string]bool {
+		t.Helper()
+		rows, err := f.DB.Query(`SELECT id FROM audit_logs`)
+		if err != nil {
+			t.Fatalf("query remaining: %v", err)
+		}
+		defer rows.Close()
+		out := make(map[string]bool)
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			out[id] = true
+		}
+		return out
+	}
+
+	// Age pruning: one stale entry per server, one fresh one on A.
+	insert("a-stale", srvA)
+	insert("b-stale", srvB)
+	insert("a-fresh", srvA)
+	if _, err := f.DB.Exec(
+		`UPDATE audit_logs SET created_at = datetime('now', '-120 days') WHERE id IN ('a-stale', 'b-stale')`,
+	); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+
+	pruned, err := repo.PruneOlderThan(ctx, 90, 100)
+	if err != nil {
+		t.Fatalf("PruneOlderThan: %v", err)
+	}
+	if pruned != 2 {
+		t.Fatalf("PruneOlderThan pruned %d, want 2", pruned)
+	}
+	if left := remaining(); len(left) != 1 || !left["a-fresh"] {
+		t.Fatalf("after age prune remaining = %v, want only a-fresh", left)
+	}
+
+	// Cap pruning: four entries on A (with a-fresh), two on B — a cap of 3
+	// drops only A's oldest, counted per server rather than globally.
+	insert("a-2", srvA)
+	insert("a-3", srvA)
+	insert("a-4", srvA)
+	insert("b-1", srvB)
+	insert("b-2", srvB)
+
+	pruned, err = repo.PruneOverPerServerCap(ctx, 3, 100)
+	if err != nil {
+		t.Fatalf("PruneOverPerServerCap: %v", err)
+	}
+	if pruned != 1 {
+		t.Fatalf("PruneOverPerServerCap pruned %d, want 1", pruned)
+	}
+	left := remaining()
+	if left["a-fresh"] || len(left) != 5 {
+		t.Fatalf("after cap prune remaining = %v, want a-fresh gone and 5 kept", left)
+	}
+}
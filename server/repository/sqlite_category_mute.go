@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+)
+
+type sqliteCategoryMuteRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteCategoryMuteRepo(db database.TxQuerier) CategoryMuteRepository {
+	return &sqliteCategoryMuteRepo{db: db}
+}
+
+// Upsert creates or updates a category mute. nil mutedUntil = indefinite.
+func (r *sqliteCategoryMuteRepo) Upsert(ctx context.Context, userID, categoryID, serverID string, mutedUntil *string) error {
+	query := `
+		INSERT INTO category_mutes (user_id, category_id, server_id, muted_until)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id, category_id)
+		DO UPDATE SET muted_until = excluded.muted_until,
+		              created_at = CURRENT_TIMESTAMP`
+
+	_, err := r.db.ExecContext(ctx, query, userID, categoryID, serverID, mutedUntil)
+	if err != nil {
+		return fmt.Errorf("failed to upsert category mute: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteCategoryMuteRepo) Delete(ctx context.Context, userID, categoryID string) error {
+	query := `DELETE FROM category_mutes WHERE user_id = ? AND category_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete category mute: %w", err)
+	}
+	return nil
+}
+
+// GetMutedCategoryIDs returns active muted category IDs (lazy expiry via WHERE).
+func (r *sqliteCategoryMuteRepo) GetMutedCategoryIDs(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		SELECT category_id FROM category_mutes
+		WHERE user_id = ?
+		  AND (muted_until IS NULL OR muted_until > datetime('now'))`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get muted category ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan muted category id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -21,8 +21,8 @@ func NewSQLiteChannelRepo(db database.TxQuerier) ChannelRepository {
 
 func (r *sqliteChannelRepo) Create(ctx context.Context, channel *models.Channel) error {
 	query := `
-		INSERT INTO channels (id, server_id, name, type, category_id, topic, position, user_limit, bitrate)
-		VALUES (lower(hex(randomblob(8))), ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO channels (id, server_id, name, type, category_id, topic, position, user_limit, bitrate, region_hint, pin_announcements)
+		VALUES (lower(hex(randomblob(8))), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id, created_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -34,6 +34,8 @@ func (r *sqliteChannelRepo) Create(ctx context.Context, channel *models.Channel)
 		channel.Position,
 		channel.UserLimit,
 		channel.Bitrate,
+		channel.RegionHint,
+		channel.PinAnnouncements,
 	).Scan(&channel.ID, &channel.CreatedAt)
 
 	if err != nil {
@@ -45,13 +47,13 @@ func (r *sqliteChannelRepo) Create(ctx context.Context, channel *models.Channel)
 
 func (r *sqliteChannelRepo) GetByID(ctx context.Context, id string) (*models.Channel, error) {
 	query := `
-		SELECT id, server_id, name, type, category_id, topic, position, user_limit, bitrate, created_at
+		SELECT id, server_id, name, type, category_id, topic, position, user_limit, bitrate, region_hint, pin_announcements, slowmode_seconds, delete_after_seconds, created_at
 		FROM channels WHERE id = ?`
 
 	ch := &models.Channel{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&ch.ID, &ch.ServerID, &ch.Name, &ch.Type, &ch.CategoryID, &ch.Topic,
-		&ch.Position, &ch.UserLimit, &ch.Bitrate, &ch.CreatedAt,
+		&ch.Position, &ch.UserLimit, &ch.Bitrate, &ch.RegionHint, &ch.PinAnnouncements, &ch.SlowmodeSeconds, &ch.DeleteAfterSeconds, &ch.CreatedAt,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -66,7 +68,7 @@ func (r *sqliteChannelRepo) GetByID(ctx context.Context, id string) (*models.Cha
 
 func (r *sqliteChannelRepo) GetAllByServer(ctx context.Context, serverID string) ([]models.Channel, error) {
 	query := `
-		SELECT id, server_id, name, type, category_id, topic, position, user_limit, bitrate, created_at
+		SELECT id, server_id, name, type, category_id, topic, position, user_limit, bitrate, region_hint, pin_announcements, slowmode_seconds, delete_after_seconds, created_at
 		FROM channels WHERE server_id = ? ORDER BY position ASC`
 
 	rows, err := r.db.QueryContext(ctx, query, serverID)
@@ -80,7 +82,7 @@ func (r *sqliteChannelRepo) GetAllByServer(ctx context.Context, serverID string)
 		var ch models.Channel
 		if err := rows.Scan(
 			&ch.ID, &ch.ServerID, &ch.Name, &ch.Type, &ch.CategoryID, &ch.Topic,
-			&ch.Position, &ch.UserLimit, &ch.Bitrate, &ch.CreatedAt,
+			&ch.Position, &ch.UserLimit, &ch.Bitrate, &ch.RegionHint, &ch.PinAnnouncements, &ch.SlowmodeSeconds, &ch.DeleteAfterSeconds, &ch.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan channel row: %w", err)
 		}
@@ -96,7 +98,7 @@ func (r *sqliteChannelRepo) GetAllByServer(ctx context.Context, serverID string)
 
 func (r *sqliteChannelRepo) GetByCategoryID(ctx context.Context, categoryID string) ([]models.Channel, error) {
 	query := `
-		SELECT id, server_id, name, type, category_id, topic, position, user_limit, bitrate, created_at
+		SELECT id, server_id, name, type, category_id, topic, position, user_limit, bitrate, region_hint, pin_announcements, slowmode_seconds, delete_after_seconds, created_at
 		FROM channels WHERE category_id = ? ORDER BY position ASC`
 
 	rows, err := r.db.QueryContext(ctx, query, categoryID)
@@ -110,7 +112,7 @@ func (r *sqliteChannelRepo) GetByCategoryID(ctx context.Context, categoryID stri
 		var ch models.Channel
 		if err := rows.Scan(
 			&ch.ID, &ch.ServerID, &ch.Name, &ch.Type, &ch.CategoryID, &ch.Topic,
-			&ch.Position, &ch.UserLimit, &ch.Bitrate, &ch.CreatedAt,
+			&ch.Position, &ch.UserLimit, &ch.Bitrate, &ch.RegionHint, &ch.PinAnnouncements, &ch.SlowmodeSeconds, &ch.DeleteAfterSeconds, &ch.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan channel row: %w", err)
 		}
@@ -126,10 +128,10 @@ func (r *sqliteChannelRepo) GetByCategoryID(ctx context.Context, categoryID stri
 
 func (r *sqliteChannelRepo) Update(ctx context.Context, channel *models.Channel) error {
 	query := `
-		UPDATE channels SET name = ?, topic = ?, category_id = ?
+		UPDATE channels SET name = ?, topic = ?, category_id = ?, pin_announcements = ?, bitrate = ?, region_hint = ?, slowmode_seconds = ?, delete_after_seconds = ?
 		WHERE id = ?`
 
-	result, err := r.db.ExecContext(ctx, query, channel.Name, channel.Topic, channel.CategoryID, channel.ID)
+	result, err := r.db.ExecContext(ctx, query, channel.Name, channel.Topic, channel.CategoryID, channel.PinAnnouncements, channel.Bitrate, channel.RegionHint, channel.SlowmodeSeconds, channel.DeleteAfterSeconds, channel.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update channel: %w", err)
 	}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+type sqliteChannelFollowRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteChannelFollowRepo(db database.TxQuerier) ChannelFollowRepository {
+	return &sqliteChannelFollowRepo{db: db}
+}
+
+func (r *sqliteChannelFollowRepo) Create(ctx context.Context, follow *models.ChannelFollow) error {
+	query := `INSERT INTO channel_follows (id, source_channel_id, target_channel_id, created_by)
+	          VALUES (lower(hex(randomblob(8))), ?, ?, ?)
+	          RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		follow.SourceChannelID, follow.TargetChannelID, follow.CreatedBy,
+	).Scan(&follow.ID, &follow.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("channel follow create: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteChannelFollowRepo) Delete(ctx context.Context, sourceChannelID, targetChannelID string) error {
+	query := `DELETE FROM channel_follows WHERE source_channel_id = ? AND target_channel_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, sourceChannelID, targetChannelID)
+	if err != nil {
+		return fmt.Errorf("channel follow delete: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("channel follow delete rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: channel follow from %s to %s", pkg.ErrNotFound, sourceChannelID, targetChannelID)
+	}
+
+	return nil
+}
+
+func (r *sqliteChannelFollowRepo) ListBySource(ctx context.Context, sourceChannelID string) ([]models.ChannelFollow, error) {
+	query := `SELECT id, source_channel_id, target_channel_id, created_by, created_at
+	          FROM channel_follows WHERE source_channel_id = ?
+	          ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, sourceChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("channel follow list by source: %w", err)
+	}
+	defer rows.Close()
+
+	follows := []models.ChannelFollow{}
+	for rows.Next() {
+		var f models.ChannelFollow
+		if err := rows.Scan(&f.ID, &f.SourceChannelID, &f.TargetChannelID, &f.CreatedBy, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("channel follow list scan: %w", err)
+		}
+		follows = append(follows, f)
+	}
+
+	return follows, rows.Err()
+}
+
+func (r *sqliteChannelFollowRepo) Exists(ctx context.Context, sourceChannelID, targetChannelID string) (bool, error) {
+	query := `SELECT EXISTS(
+		SELECT 1 FROM channel_follows WHERE source_channel_id = ? AND target_channel_id = ?
+	)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, sourceChannelID, targetChannelID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("channel follow exists check: %w", err)
+	}
+	return exists, nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -18,7 +20,7 @@ func NewSQLiteChannelPermRepo(db database.TxQuerier) ChannelPermissionRepository
 }
 
 func (r *sqliteChannelPermRepo) GetByChannel(ctx context.Context, channelID string) ([]models.ChannelPermissionOverride, error) {
-	query := `SELECT channel_id, role_id, allow, deny FROM channel_permissions WHERE channel_id = ?`
+	query := `SELECT channel_id, target_type, target_id, allow, deny FROM channel_permissions WHERE channel_id = ?`
 
 	rows, err := r.db.QueryContext(ctx, query, channelID)
 	if err != nil {
@@ -26,20 +28,7 @@ func (r *sqliteChannelPermRepo) GetByChannel(ctx context.Context, channelID stri
 	}
 	defer rows.Close()
 
-	var overrides []models.ChannelPermissionOverride
-	for rows.Next() {
-		var o models.ChannelPermissionOverride
-		if err := rows.Scan(&o.ChannelID, &o.RoleID, &o.Allow, &o.Deny); err != nil {
-			return nil, fmt.Errorf("failed to scan channel permission row: %w", err)
-		}
-		overrides = append(overrides, o)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating channel permission rows: %w", err)
-	}
-
-	return overrides, nil
+	return scanOverrides(rows)
 }
 
 func (r *sqliteChannelPermRepo) GetByChannelAndRoles(ctx context.Context, channelID string, roleIDs []string) ([]models.ChannelPermissionOverride, error) {
@@ -56,7 +45,8 @@ func (r *sqliteChannelPermRepo) GetByChannelAndRoles(ctx context.Context, channe
 	}
 
 	query := fmt.Sprintf(
-		`SELECT channel_id, role_id, allow, deny FROM channel_permissions WHERE channel_id = ? AND role_id IN (%s)`,
+		`SELECT channel_id, target_type, target_id, allow, deny FROM channel_permissions
+		 WHERE channel_id = ? AND target_type = 'role' AND target_id IN (%s)`,
 		strings.Join(placeholders, ","),
 	)
 
@@ -66,23 +56,29 @@ func (r *sqliteChannelPermRepo) GetByChannelAndRoles(ctx context.Context, channe
 	}
 	defer rows.Close()
 
-	var overrides []models.ChannelPermissionOverride
-	for rows.Next() {
-		var o models.ChannelPermissionOverride
-		if err := rows.Scan(&o.ChannelID, &o.RoleID, &o.Allow, &o.Deny); err != nil {
-			return nil, fmt.Errorf("failed to scan channel permission row: %w", err)
-		}
-		overrides = append(overrides, o)
-	}
+	return scanOverrides(rows)
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating channel permission rows: %w", err)
+// GetMemberOverride returns the member-target override for one user, nil when none.
+func (r *sqliteChannelPermRepo) GetMemberOverride(ctx context.Context, channelID, userID string) (*models.ChannelPermissionOverride, error) {
+	query := `SELECT channel_id, target_type, target_id, allow, deny FROM channel_permissions
+		WHERE channel_id = ? AND target_type = 'member' AND target_id = ?`
+
+	var o models.ChannelPermissionOverride
+	err := r.db.QueryRowContext(ctx, query, channelID, userID).Scan(
+		&o.ChannelID, &o.TargetType, &o.TargetID, &o.Allow, &o.Deny,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member override: %w", err)
 	}
 
-	return overrides, nil
+	return &o, nil
 }
 
-// GetByRoles returns all channel overrides for the given roles (used for visibility filtering).
+// GetByRoles returns all role-target channel overrides for the given roles (used for visibility filtering).
 func (r *sqliteChannelPermRepo) GetByRoles(ctx context.Context, roleIDs []string) ([]models.ChannelPermissionOverride, error) {
 	if len(roleIDs) == 0 {
 		return nil, nil
@@ -96,7 +92,8 @@ func (r *sqliteChannelPermRepo) GetByRoles(ctx context.Context, roleIDs []string
 	}
 
 	query := fmt.Sprintf(
-		`SELECT channel_id, role_id, allow, deny FROM channel_permissions WHERE role_id IN (%s)`,
+		`SELECT channel_id, target_type, target_id, allow, deny FROM channel_permissions
+		 WHERE target_type = 'role' AND target_id IN (%s)`,
 		strings.Join(placeholders, ","),
 	)
 
@@ -106,10 +103,28 @@ func (r *sqliteChannelPermRepo) GetByRoles(ctx context.Context, roleIDs []string
 	}
 	defer rows.Close()
 
+	return scanOverrides(rows)
+}
+
+// GetByMember returns all member-target overrides for one user (used for visibility filtering).
+func (r *sqliteChannelPermRepo) GetByMember(ctx context.Context, userID string) ([]models.ChannelPermissionOverride, error) {
+	query := `SELECT channel_id, target_type, target_id, allow, deny FROM channel_permissions
+		WHERE target_type = 'member' AND target_id = ?`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel permissions by member: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOverrides(rows)
+}
+
+func scanOverrides(rows *sql.Rows) ([]models.ChannelPermissionOverride, error) {
 	var overrides []models.ChannelPermissionOverride
 	for rows.Next() {
 		var o models.ChannelPermissionOverride
-		if err := rows.Scan(&o.ChannelID, &o.RoleID, &o.Allow, &o.Deny); err != nil {
+		if err := rows.Scan(&o.ChannelID, &o.TargetType, &o.TargetID, &o.Allow, &o.Deny); err != nil {
 			return nil, fmt.Errorf("failed to scan channel permission row: %w", err)
 		}
 		overrides = append(overrides, o)
@@ -124,14 +139,14 @@ func (r *sqliteChannelPermRepo) GetByRoles(ctx context.Context, roleIDs []string
 
 func (r *sqliteChannelPermRepo) Set(ctx context.Context, override *models.ChannelPermissionOverride) error {
 	query := `
-		INSERT INTO channel_permissions (channel_id, role_id, allow, deny)
-		VALUES (?, ?, ?, ?)
-		ON CONFLICT (channel_id, role_id) DO UPDATE SET
+		INSERT INTO channel_permissions (channel_id, target_type, target_id, allow, deny)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (channel_id, target_type, target_id) DO UPDATE SET
 			allow = excluded.allow,
 			deny = excluded.deny`
 
 	_, err := r.db.ExecContext(ctx, query,
-		override.ChannelID, override.RoleID, override.Allow, override.Deny,
+		override.ChannelID, override.TargetType, override.TargetID, override.Allow, override.Deny,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to set channel permission: %w", err)
@@ -140,10 +155,10 @@ func (r *sqliteChannelPermRepo) Set(ctx context.Context, override *models.Channe
 	return nil
 }
 
-func (r *sqliteChannelPermRepo) Delete(ctx context.Context, channelID, roleID string) error {
-	query := `DELETE FROM channel_permissions WHERE channel_id = ? AND role_id = ?`
+func (r *sqliteChannelPermRepo) Delete(ctx context.Context, channelID, targetType, targetID string) error {
+	query := `DELETE FROM channel_permissions WHERE channel_id = ? AND target_type = ? AND target_id = ?`
 
-	result, err := r.db.ExecContext(ctx, query, channelID, roleID)
+	result, err := r.db.ExecContext(ctx, query, channelID, targetType, targetID)
 	if err != nil {
 		return fmt.Errorf("failed to delete channel permission: %w", err)
 	}
@@ -52,11 +52,12 @@ func (r *sqliteDMRepo) GetChannelByUsers(ctx context.Context, user1ID, user2ID s
 func (r *sqliteDMRepo) GetChannelByID(ctx context.Context, id string) (*models.DMChannel, error) {
 	var ch models.DMChannel
 	var lastMsgAt sql.NullTime
-	var initiatedBy sql.NullString
+	// The pair columns are NULL for group channels.
+	var user1ID, user2ID, initiatedBy sql.NullString
 	err := r.db.QueryRowContext(ctx,
-		"SELECT id, user1_id, user2_id, e2ee_enabled, status, initiated_by, created_at, last_message_at FROM dm_channels WHERE id = ?",
+		"SELECT id, user1_id, user2_id, is_group, e2ee_enabled, status, initiated_by, created_at, last_message_at FROM dm_channels WHERE id = ?",
 		id,
-	).Scan(&ch.ID, &ch.User1ID, &ch.User2ID, &ch.E2EEEnabled, &ch.Status, &initiatedBy, &ch.CreatedAt, &lastMsgAt)
+	).Scan(&ch.ID, &user1ID, &user2ID, &ch.IsGroup, &ch.E2EEEnabled, &ch.Status, &initiatedBy, &ch.CreatedAt, &lastMsgAt)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("%w: DM channel not found", pkg.ErrNotFound)
@@ -64,6 +65,8 @@ func (r *sqliteDMRepo) GetChannelByID(ctx context.Context, id string) (*models.D
 	if err != nil {
 		return nil, fmt.Errorf("failed to get DM channel: %w", err)
 	}
+	ch.User1ID = user1ID.String
+	ch.User2ID = user2ID.String
 	if lastMsgAt.Valid {
 		ch.LastMessageAt = &lastMsgAt.Time
 	}
@@ -187,6 +190,22 @@ func (r *sqliteDMRepo) MarkReadLatest(ctx context.Context, userID, channelID str
 	return n > 0, nil
 }
 
+func (r *sqliteDMRepo) GetReadState(ctx context.Context, userID, channelID string) (*models.DMReadState, error) {
+	state := &models.DMReadState{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT last_read_message_id, last_read_at
+		FROM dm_reads
+		WHERE user_id = ? AND dm_channel_id = ?`,
+		userID, channelID).Scan(&state.LastReadMessageID, &state.LastReadAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DM read state: %w", err)
+	}
+	return state, nil
+}
+
 // CountUnread counts what the other participant has sent since the user's watermark.
 // Call logs are not messages — a finished call must not leave a badge behind.
 //
@@ -228,8 +247,12 @@ func (r *sqliteDMRepo) HasRead(ctx context.Context, userID, channelID, messageID
 // Joins user_dm_settings to filter hidden channels and include pin/mute state.
 // Sorted: pinned first (by activity), then unpinned by activity.
 func (r *sqliteDMRepo) ListChannels(ctx context.Context, userID string) ([]models.DMChannelWithUser, error) {
+	// Membership comes from dm_channel_members, so the one query covers 1:1 and
+	// group channels. The users join is LEFT: it resolves the "other user" for a
+	// pair and misses entirely for groups (NULL pair columns), whose members are
+	// batch-loaded below.
 	query := `
-		SELECT dc.id, dc.e2ee_enabled, dc.status, dc.initiated_by, dc.created_at, dc.last_message_at,
+		SELECT dc.id, dc.is_group, dc.e2ee_enabled, dc.status, dc.initiated_by, dc.created_at, dc.last_message_at,
 			u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
 			COALESCE(ds.is_pinned, 0),
 			CASE WHEN ds.muted_until IS NOT NULL AND ds.muted_until > datetime('now') THEN 1 ELSE 0 END,
@@ -237,42 +260,49 @@ func (r *sqliteDMRepo) ListChannels(ctx context.Context, userID string) ([]model
 			  WHERE m.dm_channel_id = dc.id
 			    AND ` + dmUnreadPredicate + `)
 		FROM dm_channels dc
-		JOIN users u ON u.id = CASE
+		JOIN dm_channel_members me ON me.dm_channel_id = dc.id AND me.user_id = ?
+		LEFT JOIN users u ON u.id = CASE
 			WHEN dc.user1_id = ? THEN dc.user2_id
 			ELSE dc.user1_id
 		END
 		LEFT JOIN user_dm_settings ds ON ds.user_id = ? AND ds.dm_channel_id = dc.id
-		WHERE (dc.user1_id = ? OR dc.user2_id = ?)
-		  AND COALESCE(ds.is_hidden, 0) = 0
+		WHERE COALESCE(ds.is_hidden, 0) = 0
 		ORDER BY COALESCE(ds.is_pinned, 0) DESC,
 		         COALESCE(dc.last_message_at, dc.created_at) DESC`
 
-	// 4 for the unread predicate (it reads the watermark itself), then the joins and the filter.
+	// 4 for the unread predicate (it reads the watermark itself), then the joins.
 	rows, err := r.db.QueryContext(ctx, query,
 		userID, userID, userID, userID, // dmUnreadPredicate
+		userID, // membership join
 		userID, // other-user CASE
-		userID, // user_dm_settings
-		userID, userID) // membership filter
+		userID) // user_dm_settings
 	if err != nil {
 		return nil, fmt.Errorf("failed to list DM channels: %w", err)
 	}
 	defer rows.Close()
 
 	var channels []models.DMChannelWithUser
+	var groupIDs []string
 	for rows.Next() {
 		var ch models.DMChannelWithUser
 		var user models.User
+		var otherID, otherUsername, otherStatus sql.NullString
+		var otherHardDeleted sql.NullBool
 		var displayName, avatarURL, initiatedBy sql.NullString
 		var lastMsgAt sql.NullTime
 		var isPinned, isMuted int
 
 		if err := rows.Scan(
-			&ch.ID, &ch.E2EEEnabled, &ch.Status, &initiatedBy, &ch.CreatedAt, &lastMsgAt,
-			&user.ID, &user.Username, &displayName, &avatarURL, &user.Status, &user.DeletedAt, &user.IsHardDeleted,
+			&ch.ID, &ch.IsGroup, &ch.E2EEEnabled, &ch.Status, &initiatedBy, &ch.CreatedAt, &lastMsgAt,
+			&otherID, &otherUsername, &displayName, &avatarURL, &otherStatus, &user.DeletedAt, &otherHardDeleted,
 			&isPinned, &isMuted, &ch.UnreadCount,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan DM channel: %w", err)
 		}
+		user.ID = otherID.String
+		user.Username = otherUsername.String
+		user.Status = models.UserStatus(otherStatus.String)
+		user.IsHardDeleted = otherHardDeleted.Bool
 
 		if lastMsgAt.Valid {
 			ch.LastMessageAt = &lastMsgAt.Time
@@ -287,7 +317,12 @@ func (r *sqliteDMRepo) ListChannels(ctx context.Context, userID string) ([]model
 			ch.InitiatedBy = &initiatedBy.String
 		}
 
-		ch.OtherUser = &user
+		if otherID.Valid {
+			ch.OtherUser = &user
+		}
+		if ch.IsGroup {
+			groupIDs = append(groupIDs, ch.ID)
+		}
 		ch.IsPinned = isPinned == 1
 		ch.IsMuted = isMuted == 1
 		channels = append(channels, ch)
@@ -297,6 +332,18 @@ func (r *sqliteDMRepo) ListChannels(ctx context.Context, userID string) ([]model
 		return nil, fmt.Errorf("error iterating DM channels: %w", err)
 	}
 
+	if len(groupIDs) > 0 {
+		members, err := r.GetGroupMembersByChannelIDs(ctx, groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range channels {
+			if channels[i].IsGroup {
+				channels[i].Members = members[channels[i].ID]
+			}
+		}
+	}
+
 	if channels == nil {
 		channels = []models.DMChannelWithUser{}
 	}
@@ -316,9 +363,142 @@ func (r *sqliteDMRepo) CreateChannel(ctx context.Context, channel *models.DMChan
 	if lastMsgAt.Valid {
 		channel.LastMessageAt = &lastMsgAt.Time
 	}
+
+	// Mirror the pair into dm_channel_members so membership reads work the same
+	// for 1:1 and group channels (migration 098 backfilled existing pairs).
+	for _, userID := range []string{channel.User1ID, channel.User2ID} {
+		if err := r.AddChannelMember(ctx, channel.ID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sqliteDMRepo) CreateGroupChannel(ctx context.Context, channel *models.DMChannel, memberIDs []string) error {
+	var lastMsgAt sql.NullTime
+	err := r.db.QueryRowContext(ctx,
+		"INSERT INTO dm_channels (is_group, status, initiated_by) VALUES (1, ?, ?) RETURNING id, created_at, last_message_at",
+		channel.Status, channel.InitiatedBy,
+	).Scan(&channel.ID, &channel.CreatedAt, &lastMsgAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create group DM channel: %w", err)
+	}
+	channel.IsGroup = true
+	if lastMsgAt.Valid {
+		channel.LastMessageAt = &lastMsgAt.Time
+	}
+
+	for _, userID := range memberIDs {
+		if err := r.AddChannelMember(ctx, channel.ID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sqliteDMRepo) GetChannelMemberIDs(ctx context.Context, channelID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT user_id FROM dm_channel_members WHERE dm_channel_id = ? ORDER BY created_at, user_id",
+		channelID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DM channel members: %w", err)
+	}
+	defer rows.Close()
+
+	var memberIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan DM channel member: %w", err)
+		}
+		memberIDs = append(memberIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating DM channel members: %w", err)
+	}
+	return memberIDs, nil
+}
+
+func (r *sqliteDMRepo) IsChannelMember(ctx context.Context, channelID, userID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM dm_channel_members WHERE dm_channel_id = ? AND user_id = ?",
+		channelID, userID,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check DM channel membership: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *sqliteDMRepo) AddChannelMember(ctx context.Context, channelID, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT OR IGNORE INTO dm_channel_members (dm_channel_id, user_id) VALUES (?, ?)",
+		channelID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add DM channel member: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteDMRepo) RemoveChannelMember(ctx context.Context, channelID, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM dm_channel_members WHERE dm_channel_id = ? AND user_id = ?",
+		channelID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove DM channel member: %w", err)
+	}
 	return nil
 }
 
+func (r *sqliteDMRepo) GetGroupMembersByChannelIDs(ctx context.Context, channelIDs []string) (map[string][]models.User, error) {
+	result := make(map[string][]models.User)
+	if len(channelIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.Repeat("?,", len(channelIDs)-1) + "?"
+	args := make([]any, len(channelIDs))
+	for i, id := range channelIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cm.dm_channel_id, u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted
+		FROM dm_channel_members cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE cm.dm_channel_id IN (`+placeholders+`)
+		ORDER BY cm.created_at, u.username`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group DM members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var channelID string
+		var user models.User
+		var displayName, avatarURL sql.NullString
+		if err := rows.Scan(&channelID, &user.ID, &user.Username, &displayName, &avatarURL, &user.Status, &user.DeletedAt, &user.IsHardDeleted); err != nil {
+			return nil, fmt.Errorf("failed to scan group DM member: %w", err)
+		}
+		if displayName.Valid {
+			user.DisplayName = &displayName.String
+		}
+		if avatarURL.Valid {
+			user.AvatarURL = &avatarURL.String
+		}
+		result[channelID] = append(result[channelID], user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating group DM members: %w", err)
+	}
+	return result, nil
+}
+
 func (r *sqliteDMRepo) UpdateChannelStatus(ctx context.Context, channelID, status string) error {
 	_, err := r.db.ExecContext(ctx,
 		"UPDATE dm_channels SET status = ? WHERE id = ?",
@@ -452,6 +632,87 @@ func (r *sqliteDMRepo) GetMessages(ctx context.Context, channelID string, before
 	return messages, nil
 }
 
+// GetMessagesAround anchors on the target's (created_at, rowid) cursor — the
+// same predicate GetMessages pages with — and fetches one extra row per
+// direction to learn whether more history exists past the window.
+func (r *sqliteDMRepo) GetMessagesAround(ctx context.Context, channelID, messageID string, limit int) ([]models.DMMessage, bool, bool, error) {
+	const selectClause = `
+		SELECT m.id, m.dm_channel_id, m.user_id, m.content, m.edited_at, m.created_at,
+		       m.reply_to_id, m.is_pinned,
+		       m.encryption_version, m.ciphertext, m.sender_device_id, m.e2ee_metadata, m.message_type, m.call_meta,
+		       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
+		       rm.id, rm.content,
+		       ru.id, ru.username, ru.display_name, ru.avatar_url, ru.deleted_at, ru.is_hard_deleted
+		FROM dm_messages m
+		LEFT JOIN users u ON m.user_id = u.id
+		LEFT JOIN dm_messages rm ON m.reply_to_id = rm.id
+		LEFT JOIN users ru ON rm.user_id = ru.id`
+
+	run := func(query string, args ...any) ([]models.DMMessage, error) {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DM messages: %w", err)
+		}
+		defer rows.Close()
+
+		var messages []models.DMMessage
+		for rows.Next() {
+			msg, err := scanDMMessageRow(rows)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, *msg)
+		}
+		return messages, rows.Err()
+	}
+
+	// The target row doubles as the channel check.
+	target, err := run(selectClause+`
+		WHERE m.id = ? AND m.dm_channel_id = ?`, messageID, channelID)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if len(target) == 0 {
+		return nil, false, false, fmt.Errorf("%w: message not found in this conversation", pkg.ErrNotFound)
+	}
+
+	before, err := run(selectClause+`
+		WHERE m.dm_channel_id = ?
+		  AND (m.created_at, m.rowid) <
+		      (SELECT created_at, rowid FROM dm_messages WHERE id = ?)
+		ORDER BY m.created_at DESC, m.rowid DESC
+		LIMIT ?`, channelID, messageID, limit+1)
+	if err != nil {
+		return nil, false, false, err
+	}
+	hasBefore := len(before) > limit
+	if hasBefore {
+		before = before[:limit]
+	}
+	// DESC from the DB; flip to ascending before assembling the window.
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
+	}
+
+	after, err := run(selectClause+`
+		WHERE m.dm_channel_id = ?
+		  AND (m.created_at, m.rowid) >
+		      (SELECT created_at, rowid FROM dm_messages WHERE id = ?)
+		ORDER BY m.created_at ASC, m.rowid ASC
+		LIMIT ?`, channelID, messageID, limit+1)
+	if err != nil {
+		return nil, false, false, err
+	}
+	hasAfter := len(after) > limit
+	if hasAfter {
+		after = after[:limit]
+	}
+
+	window := append(before, target[0])
+	window = append(window, after...)
+	return window, hasBefore, hasAfter, nil
+}
+
 func (r *sqliteDMRepo) GetMessageByID(ctx context.Context, id string) (*models.DMMessage, error) {
 	query := `
 		SELECT m.id, m.dm_channel_id, m.user_id, m.content, m.edited_at, m.created_at,
@@ -559,6 +820,28 @@ func (r *sqliteDMRepo) CreateMessage(ctx context.Context, msg *models.DMMessage)
 	return nil
 }
 
+// RecordEdit stores the pre-edit plaintext as a revision in dm_message_edits,
+// pruned to the newest `keep` rows (rowid is the revision order).
+func (r *sqliteDMRepo) RecordEdit(ctx context.Context, messageID, oldContent string, keep int) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO dm_message_edits (message_id, old_content) VALUES (?, ?)`,
+		messageID, oldContent,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record DM edit: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx,
+		`DELETE FROM dm_message_edits
+		 WHERE message_id = ? AND rowid NOT IN (
+		     SELECT rowid FROM dm_message_edits WHERE message_id = ? ORDER BY rowid DESC LIMIT ?)`,
+		messageID, messageID, keep,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune DM edit history: %w", err)
+	}
+	return nil
+}
+
 // UpdateMessage edits a DM message.
 // E2EE messages update ciphertext; plaintext messages update content.
 func (r *sqliteDMRepo) UpdateMessage(ctx context.Context, id string, req *models.UpdateDMMessageRequest) error {
@@ -602,6 +885,27 @@ func (r *sqliteDMRepo) UpdateMessage(ctx context.Context, id string, req *models
 	return nil
 }
 
+// ReplyChainDepth is the dm_messages twin of the channel-message walk; the
+// depth bound in the recursive term keeps a corrupted cycle from spinning.
+func (r *sqliteDMRepo) ReplyChainDepth(ctx context.Context, messageID string, maxDepth int) (int, error) {
+	query := `
+		WITH RECURSIVE chain(reply_to_id, depth) AS (
+			SELECT reply_to_id, 1 FROM dm_messages WHERE id = ?
+			UNION ALL
+			SELECT m.reply_to_id, c.depth + 1
+			FROM dm_messages m
+			JOIN chain c ON m.id = c.reply_to_id
+			WHERE c.depth < ?
+		)
+		SELECT COALESCE(MAX(depth), 0) FROM chain`
+
+	var depth int
+	if err := r.db.QueryRowContext(ctx, query, messageID, maxDepth).Scan(&depth); err != nil {
+		return 0, fmt.Errorf("failed to measure DM reply chain depth: %w", err)
+	}
+	return depth, nil
+}
+
 func (r *sqliteDMRepo) DeleteMessage(ctx context.Context, id string) error {
 	result, err := r.db.ExecContext(ctx, "DELETE FROM dm_messages WHERE id = ?", id)
 	if err != nil {
@@ -651,6 +955,16 @@ func (r *sqliteDMRepo) ToggleReaction(ctx context.Context, messageID, userID, em
 	return false, nil
 }
 
+// CountUserReactions counts the user's distinct emoji on one DM message.
+func (r *sqliteDMRepo) CountUserReactions(ctx context.Context, messageID, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM dm_reactions WHERE dm_message_id = ? AND user_id = ?`
+	if err := r.db.QueryRowContext(ctx, query, messageID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count user DM reactions: %w", err)
+	}
+	return count, nil
+}
+
 func (r *sqliteDMRepo) GetReactionsByMessageID(ctx context.Context, messageID string) ([]models.ReactionGroup, error) {
 	query := `
 		SELECT emoji, COUNT(*) as count, GROUP_CONCAT(user_id) as users
@@ -718,6 +1032,35 @@ func (r *sqliteDMRepo) GetReactionsByMessageIDs(ctx context.Context, messageIDs
 	return result, nil
 }
 
+// ListReactors — see DMRepository.ListReactors.
+func (r *sqliteDMRepo) ListReactors(ctx context.Context, messageID, emoji string, limit, offset int) ([]models.ReactionUser, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM dm_reactions WHERE dm_message_id = ? AND emoji = ?`
+	if err := r.db.QueryRowContext(ctx, countQuery, messageID, emoji).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count DM reactors: %w", err)
+	}
+
+	query := `
+		SELECT u.id, u.username, u.display_name, u.avatar_url
+		FROM dm_reactions re
+		INNER JOIN users u ON u.id = re.user_id
+		WHERE re.dm_message_id = ? AND re.emoji = ?
+		ORDER BY re.created_at ASC, u.id ASC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, messageID, emoji, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list DM reactors: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := scanReactionUsers(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 // ─── Pin Operations ───
 
 func (r *sqliteDMRepo) PinMessage(ctx context.Context, messageID string) error {
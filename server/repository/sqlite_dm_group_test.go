@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+	_ "modernc.org/sqlite"
+)
+
+// Group DMs share dm_channels with 1:1 conversations but keep membership in
+// dm_channel_members (migration 098). These tests run the real migration chain,
+// so the rebuild — drop, rename, trigger recreation, pair backfill — is what is
+// actually exercised, not a hand-built schema.
+func newGroupDMDB(t *testing.T) *sqliteDMRepo {
+	t.Helper()
+	migFS, err := fs.Sub(database.EmbeddedMigrations, "migrations")
+	if err != nil {
+		t.Fatalf("sub migrations: %v", err)
+	}
+	db, err := database.New(filepath.Join(t.TempDir(), "dm.db"), migFS)
+	if err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Conn.Close() })
+
+	for _, id := range []string{"alice", "bob", "carol"} {
+		if _, err := db.Conn.Exec(
+			`INSERT INTO users (id, username, password_hash) VALUES (?, ?, 'x')`, id, id,
+		); err != nil {
+			t.Fatalf("seed user %s: %v", id, err)
+		}
+	}
+	return &sqliteDMRepo{db: db.Conn}
+}
+
+func TestGroupDM_CreateAndList(t *testing.T) {
+	repo := newGroupDMDB(t)
+	ctx := context.Background()
+
+	group := &models.DMChannel{Status: models.DMStatusAccepted}
+	if err := repo.CreateGroupChannel(ctx, group, []string{"alice", "bob", "carol"}); err != nil {
+		t.Fatalf("CreateGroupChannel: %v", err)
+	}
+	if group.ID == "" || !group.IsGroup {
+		t.Fatalf("group channel not populated: id=%q is_group=%v", group.ID, group.IsGroup)
+	}
+
+	// A plain pair channel next to it — the two kinds must list side by side.
+	pair := &models.DMChannel{User1ID: "alice", User2ID: "bob", Status: models.DMStatusAccepted}
+	if err := repo.CreateChannel(ctx, pair); err != nil {
+		t.Fatalf("CreateChannel: %v", err)
+	}
+
+	channels, err := repo.ListChannels(ctx, "alice")
+	if err != nil {
+		t.Fatalf("ListChannels: %v", err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("alice sees %d channels, want 2", len(channels))
+	}
+
+	for _, ch := range channels {
+		switch ch.ID {
+		case group.ID:
+			if !ch.IsGroup {
+				t.Errorf("group channel listed with is_group=false")
+			}
+			if ch.OtherUser != nil {
+				t.Errorf("group channel has an other_user: %+v", ch.OtherUser)
+			}
+			if len(ch.Members) != 3 {
+				t.Errorf("group lists %d members, want 3", len(ch.Members))
+			}
+		case pair.ID:
+			if ch.IsGroup {
+				t.Errorf("pair channel listed with is_group=true")
+			}
+			if ch.OtherUser == nil || ch.OtherUser.ID != "bob" {
+				t.Errorf("pair channel other_user = %+v, want bob", ch.OtherUser)
+			}
+		default:
+			t.Errorf("unexpected channel %s in list", ch.ID)
+		}
+	}
+
+	// Carol is only in the group; the pair must not leak to her.
+	channels, err = repo.ListChannels(ctx, "carol")
+	if err != nil {
+		t.Fatalf("ListChannels(carol): %v", err)
+	}
+	if len(channels) != 1 || channels[0].ID != group.ID {
+		t.Fatalf("carol sees %d channels, want just the group", len(channels))
+	}
+}
+
+func TestGroupDM_MembershipAddRemove(t *testing.T) {
+	repo := newGroupDMDB(t)
+	ctx := context.Background()
+
+	group := &models.DMChannel{Status: models.DMStatusAccepted}
+	if err := repo.CreateGroupChannel(ctx, group, []string{"alice", "bob"}); err != nil {
+		t.Fatalf("CreateGroupChannel: %v", err)
+	}
+
+	member, err := repo.IsChannelMember(ctx, group.ID, "carol")
+	if err != nil {
+		t.Fatalf("IsChannelMember: %v", err)
+	}
+	if member {
+		t.Fatal("carol is a member before being added")
+	}
+
+	if err := repo.AddChannelMember(ctx, group.ID, "carol"); err != nil {
+		t.Fatalf("AddChannelMember: %v", err)
+	}
+	memberIDs, err := repo.GetChannelMemberIDs(ctx, group.ID)
+	if err != nil {
+		t.Fatalf("GetChannelMemberIDs: %v", err)
+	}
+	if len(memberIDs) != 3 {
+		t.Fatalf("got %d members after add, want 3: %v", len(memberIDs), memberIDs)
+	}
+
+	if err := repo.RemoveChannelMember(ctx, group.ID, "carol"); err != nil {
+		t.Fatalf("RemoveChannelMember: %v", err)
+	}
+	member, err = repo.IsChannelMember(ctx, group.ID, "carol")
+	if err != nil {
+		t.Fatalf("IsChannelMember after remove: %v", err)
+	}
+	if member {
+		t.Fatal("carol still a member after removal")
+	}
+}
+
+// CreateChannel must mirror the pair into dm_channel_members — membership reads
+// go through the join table for both kinds of channel.
+func TestGroupDM_PairCreateSeedsMemberRows(t *testing.T) {
+	repo := newGroupDMDB(t)
+	ctx := context.Background()
+
+	pair := &models.DMChannel{User1ID: "alice", User2ID: "bob", Status: models.DMStatusAccepted}
+	if err := repo.CreateChannel(ctx, pair); err != nil {
+		t.Fatalf("CreateChannel: %v", err)
+	}
+
+	memberIDs, err := repo.GetChannelMemberIDs(ctx, pair.ID)
+	if err != nil {
+		t.Fatalf("GetChannelMemberIDs: %v", err)
+	}
+	if len(memberIDs) != 2 {
+		t.Fatalf("pair channel has %d member rows, want 2: %v", len(memberIDs), memberIDs)
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+func TestExportJobLifecycle(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteExportJobRepo(f.DB)
+	ctx := context.Background()
+
+	f.User("exp_u")
+	serverID := f.Server(dbtest.ServerSeed{ID: "exp_s", OwnerID: "exp_u"})
+
+	job := &models.ExportJob{
+		ID:       "exp_j1",
+		ServerID: serverID,
+		UserID:   "exp_u",
+		Format:   models.ExportFormatJSON,
+		Status:   models.ExportStatusPending,
+	}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	active, err := repo.HasActive(ctx, serverID)
+	if err != nil {
+		t.Fatalf("HasActive: %v", err)
+	}
+	if !active {
+		t.Fatal("expected a pending job to count as active")
+	}
+
+	if err := repo.MarkRunning(ctx, job.ID); err != nil {
+		t.Fatalf("MarkRunning: %v", err)
+	}
+	if err := repo.MarkCompleted(ctx, job.ID, "/tmp/exp_j1.zip", 1234, 7); err != nil {
+		t.Fatalf("MarkCompleted: %v", err)
+	}
+
+	got, err := repo.GetByID(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.ExportStatusCompleted {
+		t.Fatalf("Status = %q, want completed", got.Status)
+	}
+	if got.FilePath == nil || *got.FilePath != "/tmp/exp_j1.zip" {
+		t.Fatalf("FilePath = %v, want /tmp/exp_j1.zip", got.FilePath)
+	}
+	if got.FileSize != 1234 || got.MessageCount != 7 {
+		t.Fatalf("FileSize/MessageCount = %d/%d, want 1234/7", got.FileSize, got.MessageCount)
+	}
+	if got.CompletedAt == nil {
+		t.Fatal("CompletedAt not set by MarkCompleted")
+	}
+
+	// A completed job no longer blocks new exports.
+	active, err = repo.HasActive(ctx, serverID)
+	if err != nil {
+		t.Fatalf("HasActive after completion: %v", err)
+	}
+	if active {
+		t.Fatal("completed job must not count as active")
+	}
+
+	if _, err := repo.GetByID(ctx, "exp_missing"); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("GetByID(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.MarkRunning(ctx, "exp_missing"); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("MarkRunning(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExportJobListExpired(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteExportJobRepo(f.DB)
+	ctx := context.Background()
+
+	f.User("exp_u")
+	serverID := f.Server(dbtest.ServerSeed{ID: "exp_s", OwnerID: "exp_u"})
+
+	for _, id := range []string{"exp_old", "exp_fresh"} {
+		job := &models.ExportJob{
+			ID: id, ServerID: serverID, UserID: "exp_u",
+			Format: models.ExportFormatCSV, Status: models.ExportStatusCompleted,
+		}
+		if err := repo.Create(ctx, job); err != nil {
+			t.Fatalf("Create %s: %v", id, err)
+		}
+	}
+	// Backdate one job past the TTL; stuck-running jobs expire the same way.
+	if _, err := f.DB.Exec(
+		`UPDATE export_jobs SET created_at = datetime('now', '-2 days'), status = 'running' WHERE id = 'exp_old'`,
+	); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+
+	expired, err := repo.ListExpired(ctx, time.Now().UTC().Add(-models.ExportJobTTL))
+	if err != nil {
+		t.Fatalf("ListExpired: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != "exp_old" {
+		t.Fatalf("ListExpired = %+v, want only exp_old", expired)
+	}
+
+	if err := repo.Delete(ctx, "exp_old"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, "exp_old"); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("GetByID after delete = %v, want ErrNotFound", err)
+	}
+}
@@ -78,7 +78,7 @@ func (r *sqliteFriendshipRepo) ListFriends(ctx context.Context, userID string) (
 	// must not show up in the active social graph.
 	query := `
 		SELECT f.id, f.status, f.created_at AS created_at,
-		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status
+		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status, u.custom_status_emoji
 		FROM friendships f
 		JOIN users u ON u.id = f.friend_id
 		WHERE f.user_id = ? AND f.status = 'accepted' AND u.deleted_at IS NULL
@@ -86,7 +86,7 @@ func (r *sqliteFriendshipRepo) ListFriends(ctx context.Context, userID string) (
 		UNION
 
 		SELECT f.id, f.status, f.created_at AS created_at,
-		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status
+		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status, u.custom_status_emoji
 		FROM friendships f
 		JOIN users u ON u.id = f.user_id
 		WHERE f.friend_id = ? AND f.status = 'accepted' AND u.deleted_at IS NULL
@@ -104,11 +104,11 @@ func (r *sqliteFriendshipRepo) ListFriends(ctx context.Context, userID string) (
 	for rows.Next() {
 		var fw models.FriendshipWithUser
 		var displayName string
-		var avatarURL, customStatus sql.NullString
+		var avatarURL, customStatus, customStatusEmoji sql.NullString
 
 		if err := rows.Scan(
 			&fw.ID, &fw.Status, &fw.CreatedAt,
-			&fw.UserID, &fw.Username, &displayName, &avatarURL, &fw.UserStatus, &customStatus,
+			&fw.UserID, &fw.Username, &displayName, &avatarURL, &fw.UserStatus, &customStatus, &customStatusEmoji,
 		); err != nil {
 			return nil, fmt.Errorf("friendship list friends scan: %w", err)
 		}
@@ -122,6 +122,9 @@ func (r *sqliteFriendshipRepo) ListFriends(ctx context.Context, userID string) (
 		if customStatus.Valid {
 			fw.UserCustomStatus = &customStatus.String
 		}
+		if customStatusEmoji.Valid {
+			fw.UserCustomStatusEmoji = &customStatusEmoji.String
+		}
 
 		friends = append(friends, fw)
 	}
@@ -133,7 +136,7 @@ func (r *sqliteFriendshipRepo) ListIncoming(ctx context.Context, userID string)
 	// Sender must be active — pending requests from deleted senders are hidden.
 	query := `
 		SELECT f.id, f.status, f.created_at,
-		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status
+		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status, u.custom_status_emoji
 		FROM friendships f
 		JOIN users u ON u.id = f.user_id
 		WHERE f.friend_id = ? AND f.status = 'pending' AND u.deleted_at IS NULL
@@ -143,11 +146,27 @@ func (r *sqliteFriendshipRepo) ListIncoming(ctx context.Context, userID string)
 	return r.scanFriendshipList(ctx, query, userID)
 }
 
+// CountIncoming mirrors ListIncoming's filters (pending, sender still active)
+// so the badge never disagrees with the list it summarizes.
+func (r *sqliteFriendshipRepo) CountIncoming(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM friendships f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.friend_id = ? AND f.status = 'pending' AND u.deleted_at IS NULL`, userID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("friendship count incoming: %w", err)
+	}
+	return count, nil
+}
+
 func (r *sqliteFriendshipRepo) ListOutgoing(ctx context.Context, userID string) ([]models.FriendshipWithUser, error) {
 	// Target must be active — outgoing requests to deleted targets are hidden.
 	query := `
 		SELECT f.id, f.status, f.created_at,
-		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status
+		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status, u.custom_status_emoji
 		FROM friendships f
 		JOIN users u ON u.id = f.friend_id
 		WHERE f.user_id = ? AND f.status = 'pending' AND u.deleted_at IS NULL
@@ -222,7 +241,7 @@ func (r *sqliteFriendshipRepo) ListBlocked(ctx context.Context, userID string) (
 	// in the list if restored. Block enforcement (DM/friend/etc.) is unaffected.
 	query := `
 		SELECT f.id, f.status, f.created_at,
-		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status
+		       u.id, u.username, COALESCE(u.display_name, ''), u.avatar_url, u.status, u.custom_status, u.custom_status_emoji
 		FROM friendships f
 		JOIN users u ON u.id = f.friend_id
 		WHERE f.user_id = ? AND f.status = 'blocked' AND u.deleted_at IS NULL
@@ -250,6 +269,22 @@ func (r *sqliteFriendshipRepo) IsBlocked(ctx context.Context, userA, userB strin
 	return exists, nil
 }
 
+// HasBlocked checks one direction only: blockerID has blocked targetID.
+func (r *sqliteFriendshipRepo) HasBlocked(ctx context.Context, blockerID, targetID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM friendships
+			WHERE status = 'blocked' AND user_id = ? AND friend_id = ?
+		)`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, blockerID, targetID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("friendship has blocked check: %w", err)
+	}
+	return exists, nil
+}
+
 // scanFriendshipList is a shared scan helper for ListIncoming, ListOutgoing, and ListBlocked.
 func (r *sqliteFriendshipRepo) scanFriendshipList(ctx context.Context, query string, userID string) ([]models.FriendshipWithUser, error) {
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -262,11 +297,11 @@ func (r *sqliteFriendshipRepo) scanFriendshipList(ctx context.Context, query str
 	for rows.Next() {
 		var fw models.FriendshipWithUser
 		var displayName string
-		var avatarURL, customStatus sql.NullString
+		var avatarURL, customStatus, customStatusEmoji sql.NullString
 
 		if err := rows.Scan(
 			&fw.ID, &fw.Status, &fw.CreatedAt,
-			&fw.UserID, &fw.Username, &displayName, &avatarURL, &fw.UserStatus, &customStatus,
+			&fw.UserID, &fw.Username, &displayName, &avatarURL, &fw.UserStatus, &customStatus, &customStatusEmoji,
 		); err != nil {
 			return nil, fmt.Errorf("friendship list scan: %w", err)
 		}
@@ -280,6 +315,9 @@ func (r *sqliteFriendshipRepo) scanFriendshipList(ctx context.Context, query str
 		if customStatus.Valid {
 			fw.UserCustomStatus = &customStatus.String
 		}
+		if customStatusEmoji.Valid {
+			fw.UserCustomStatusEmoji = &customStatusEmoji.String
+		}
 
 		results = append(results, fw)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/akinalp/mqvi/database"
 	"github.com/akinalp/mqvi/models"
@@ -20,13 +21,13 @@ func NewSQLiteInviteRepo(db database.TxQuerier) InviteRepository {
 
 func (r *sqliteInviteRepo) GetByCode(ctx context.Context, code string) (*models.Invite, error) {
 	query := `
-		SELECT code, server_id, created_by, max_uses, uses, expires_at, created_at
+		SELECT code, server_id, created_by, max_uses, uses, temporary, expires_at, created_at
 		FROM invites WHERE code = ?`
 
 	invite := &models.Invite{}
 	err := r.db.QueryRowContext(ctx, query, code).Scan(
 		&invite.Code, &invite.ServerID, &invite.CreatedBy, &invite.MaxUses,
-		&invite.Uses, &invite.ExpiresAt, &invite.CreatedAt,
+		&invite.Uses, &invite.Temporary, &invite.ExpiresAt, &invite.CreatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -42,7 +43,7 @@ func (r *sqliteInviteRepo) GetByCode(ctx context.Context, code string) (*models.
 // ListByServer returns a server's invites with creator info via LEFT JOIN.
 func (r *sqliteInviteRepo) ListByServer(ctx context.Context, serverID string) ([]models.InviteWithCreator, error) {
 	query := `
-		SELECT i.code, i.server_id, i.created_by, i.max_uses, i.uses, i.expires_at, i.created_at,
+		SELECT i.code, i.server_id, i.created_by, i.max_uses, i.uses, i.temporary, i.expires_at, i.created_at,
 		       COALESCE(u.username, ''), u.display_name
 		FROM invites i
 		LEFT JOIN users u ON u.id = i.created_by
@@ -60,7 +61,7 @@ func (r *sqliteInviteRepo) ListByServer(ctx context.Context, serverID string) ([
 		var inv models.InviteWithCreator
 		if err := rows.Scan(
 			&inv.Code, &inv.ServerID, &inv.CreatedBy, &inv.MaxUses,
-			&inv.Uses, &inv.ExpiresAt, &inv.CreatedAt,
+			&inv.Uses, &inv.Temporary, &inv.ExpiresAt, &inv.CreatedAt,
 			&inv.CreatorUsername, &inv.CreatorDisplayName,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan invite: %w", err)
@@ -77,11 +78,11 @@ func (r *sqliteInviteRepo) ListByServer(ctx context.Context, serverID string) ([
 
 func (r *sqliteInviteRepo) Create(ctx context.Context, invite *models.Invite) error {
 	query := `
-		INSERT INTO invites (code, server_id, created_by, max_uses, uses, expires_at)
-		VALUES (?, ?, ?, ?, 0, ?)`
+		INSERT INTO invites (code, server_id, created_by, max_uses, uses, temporary, expires_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?)`
 
 	_, err := r.db.ExecContext(ctx, query,
-		invite.Code, invite.ServerID, invite.CreatedBy, invite.MaxUses, invite.ExpiresAt,
+		invite.Code, invite.ServerID, invite.CreatedBy, invite.MaxUses, invite.Temporary, invite.ExpiresAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create invite: %w", err)
@@ -147,3 +148,22 @@ func (r *sqliteInviteRepo) DecrementUses(ctx context.Context, code string) error
 	}
 	return nil
 }
+
+// DeleteExpired bulk-deletes invites past their expiry. Validate already rejects them, so
+// this is housekeeping (keeps the invites table and server invite lists from accumulating
+// dead codes), driven by the cleanup worker.
+func (r *sqliteInviteRepo) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	query := `DELETE FROM invites WHERE expires_at IS NOT NULL AND expires_at <= ?`
+
+	result, err := r.db.ExecContext(ctx, query, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired invites: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return int(affected), nil
+}
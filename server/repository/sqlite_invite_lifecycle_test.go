@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// DeleteExpired must only sweep invites whose expiry has passed — never
+// unexpired codes and never codes with no expiry at all.
+func TestInviteDeleteExpired(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteInviteRepo(f.DB)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	expired := &models.Invite{Code: "expiredcode00001", ServerID: serverID, ExpiresAt: &past}
+	live := &models.Invite{Code: "livecode00000001", ServerID: serverID, ExpiresAt: &future}
+	eternal := &models.Invite{Code: "eternalcode00001", ServerID: serverID}
+	for _, inv := range []*models.Invite{expired, live, eternal} {
+		if err := repo.Create(ctx, inv); err != nil {
+			t.Fatalf("create invite %s: %v", inv.Code, err)
+		}
+	}
+
+	n, err := repo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("delete expired: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("deleted %d invites, want 1", n)
+	}
+	if _, err := repo.GetByCode(ctx, expired.Code); !errors.Is(err, pkg.ErrNotFound) {
+		t.Errorf("expired invite still resolvable: %v", err)
+	}
+	for _, code := range []string{live.Code, eternal.Code} {
+		if _, err := repo.GetByCode(ctx, code); err != nil {
+			t.Errorf("surviving invite %s: %v", code, err)
+		}
+	}
+}
+
+// A temporary membership is removable on disconnect until the member earns a
+// non-default role; the default (everyone) role does not protect it.
+func TestTemporaryMembership_RemovableUntilRoled(t *testing.T) {
+	f := dbtest.New(t)
+	serverRepo := NewSQLiteServerRepo(f.DB)
+	roleRepo := NewSQLiteRoleRepo(f.DB)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	userID := f.User("")
+
+	if err := serverRepo.MarkMemberTemporary(ctx, serverID, userID); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("mark on non-member: got %v, want ErrNotFound", err)
+	}
+
+	if err := serverRepo.AddMember(ctx, serverID, userID); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	if err := serverRepo.MarkMemberTemporary(ctx, serverID, userID); err != nil {
+		t.Fatalf("mark temporary: %v", err)
+	}
+
+	removable, err := serverRepo.ListRemovableTemporaryMemberships(ctx, userID)
+	if err != nil {
+		t.Fatalf("list removable: %v", err)
+	}
+	if len(removable) != 1 || removable[0] != serverID {
+		t.Fatalf("removable = %v, want [%s]", removable, serverID)
+	}
+
+	// The default role everyone gets on join must not anchor the membership.
+	everyone := &models.Role{ServerID: serverID, Name: "everyone", IsDefault: true}
+	if err := roleRepo.Create(ctx, everyone); err != nil {
+		t.Fatalf("create default role: %v", err)
+	}
+	if err := roleRepo.AssignToUser(ctx, userID, everyone.ID, serverID); err != nil {
+		t.Fatalf("assign default role: %v", err)
+	}
+	if removable, _ = serverRepo.ListRemovableTemporaryMemberships(ctx, userID); len(removable) != 1 {
+		t.Fatalf("default role should not protect the membership, removable = %v", removable)
+	}
+
+	// A granted non-default role makes the membership permanent.
+	mod := &models.Role{ServerID: serverID, Name: "mod", Position: 1}
+	if err := roleRepo.Create(ctx, mod); err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	if err := roleRepo.AssignToUser(ctx, userID, mod.ID, serverID); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+	if removable, _ = serverRepo.ListRemovableTemporaryMemberships(ctx, userID); len(removable) != 0 {
+		t.Fatalf("roled member still removable: %v", removable)
+	}
+}
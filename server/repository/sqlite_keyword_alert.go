@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/akinalp/mqvi/database"
+)
+
+type sqliteKeywordAlertRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteKeywordAlertRepo(db database.TxQuerier) KeywordAlertRepository {
+	return &sqliteKeywordAlertRepo{db: db}
+}
+
+func (r *sqliteKeywordAlertRepo) Add(ctx context.Context, userID, keyword string) error {
+	query := `
+		INSERT INTO user_keyword_alerts (user_id, keyword)
+		VALUES (?, ?)
+		ON CONFLICT(user_id, keyword) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, userID, keyword)
+	if err != nil {
+		return fmt.Errorf("failed to add keyword alert: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteKeywordAlertRepo) Delete(ctx context.Context, userID, keyword string) error {
+	query := `DELETE FROM user_keyword_alerts WHERE user_id = ? AND keyword = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, keyword)
+	if err != nil {
+		return fmt.Errorf("failed to delete keyword alert: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteKeywordAlertRepo) ListByUser(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		SELECT keyword FROM user_keyword_alerts
+		WHERE user_id = ?
+		ORDER BY created_at ASC, keyword ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keyword alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var keywords []string
+	for rows.Next() {
+		var kw string
+		if err := rows.Scan(&kw); err != nil {
+			return nil, fmt.Errorf("failed to scan keyword alert: %w", err)
+		}
+		keywords = append(keywords, kw)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating keyword alerts: %w", err)
+	}
+
+	if keywords == nil {
+		keywords = []string{}
+	}
+
+	return keywords, nil
+}
+
+// GetByUserIDs batches keyword lookup for the message-create hot path: one IN
+// query instead of a round trip per online viewer.
+func (r *sqliteKeywordAlertRepo) GetByUserIDs(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	if len(userIDs) == 0 {
+		return map[string][]string{}, nil
+	}
+
+	placeholders := strings.Repeat("?,", len(userIDs))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	query := fmt.Sprintf(
+		`SELECT user_id, keyword FROM user_keyword_alerts WHERE user_id IN (%s)`,
+		placeholders,
+	)
+
+	args := make([]any, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyword alerts by users: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]string)
+	for rows.Next() {
+		var userID, kw string
+		if err := rows.Scan(&userID, &kw); err != nil {
+			return nil, fmt.Errorf("failed to scan keyword alert row: %w", err)
+		}
+		result[userID] = append(result[userID], kw)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating keyword alert rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -52,13 +52,13 @@ func (r *sqliteLiveKitRepo) GetByID(ctx context.Context, id string) (*models.Liv
 	query := `
 		SELECT id, url, api_key, api_secret, is_platform_managed,
 		       (SELECT COUNT(*) FROM servers WHERE livekit_instance_id = livekit_instances.id) AS server_count,
-		       max_servers, hetzner_server_id, created_at
+		       max_servers, is_draining, hetzner_server_id, created_at
 		FROM livekit_instances WHERE id = ?`
 
 	inst := &models.LiveKitInstance{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&inst.ID, &inst.URL, &inst.APIKey, &inst.APISecret,
-		&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.HetznerServerID, &inst.CreatedAt,
+		&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.IsDraining, &inst.HetznerServerID, &inst.CreatedAt,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -75,7 +75,7 @@ func (r *sqliteLiveKitRepo) GetByServerID(ctx context.Context, serverID string)
 	query := `
 		SELECT li.id, li.url, li.api_key, li.api_secret, li.is_platform_managed,
 		       (SELECT COUNT(*) FROM servers WHERE livekit_instance_id = li.id) AS server_count,
-		       li.max_servers, li.hetzner_server_id, li.created_at
+		       li.max_servers, li.is_draining, li.hetzner_server_id, li.created_at
 		FROM livekit_instances li
 		INNER JOIN servers s ON s.livekit_instance_id = li.id
 		WHERE s.id = ?`
@@ -83,7 +83,7 @@ func (r *sqliteLiveKitRepo) GetByServerID(ctx context.Context, serverID string)
 	inst := &models.LiveKitInstance{}
 	err := r.db.QueryRowContext(ctx, query, serverID).Scan(
 		&inst.ID, &inst.URL, &inst.APIKey, &inst.APISecret,
-		&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.HetznerServerID, &inst.CreatedAt,
+		&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.IsDraining, &inst.HetznerServerID, &inst.CreatedAt,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -97,14 +97,16 @@ func (r *sqliteLiveKitRepo) GetByServerID(ctx context.Context, serverID string)
 }
 
 // GetLeastLoadedPlatformInstance returns the platform-managed instance with fewest servers
-// that still has capacity. max_servers = 0 means unlimited.
+// that still has capacity. max_servers = 0 means unlimited. Draining instances are
+// never picked — they only serve the servers already attached to them.
 func (r *sqliteLiveKitRepo) GetLeastLoadedPlatformInstance(ctx context.Context) (*models.LiveKitInstance, error) {
 	query := `
 		SELECT id, url, api_key, api_secret, is_platform_managed,
 		       (SELECT COUNT(*) FROM servers WHERE livekit_instance_id = livekit_instances.id) AS server_count,
-		       max_servers, hetzner_server_id, created_at
+		       max_servers, is_draining, hetzner_server_id, created_at
 		FROM livekit_instances
 		WHERE is_platform_managed = 1
+		  AND is_draining = 0
 		  AND (max_servers = 0 OR (SELECT COUNT(*) FROM servers WHERE livekit_instance_id = livekit_instances.id) < max_servers)
 		ORDER BY server_count ASC
 		LIMIT 1`
@@ -112,7 +114,7 @@ func (r *sqliteLiveKitRepo) GetLeastLoadedPlatformInstance(ctx context.Context)
 	inst := &models.LiveKitInstance{}
 	err := r.db.QueryRowContext(ctx, query).Scan(
 		&inst.ID, &inst.URL, &inst.APIKey, &inst.APISecret,
-		&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.HetznerServerID, &inst.CreatedAt,
+		&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.IsDraining, &inst.HetznerServerID, &inst.CreatedAt,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -164,10 +166,10 @@ func (r *sqliteLiveKitRepo) DecrementServerCount(ctx context.Context, instanceID
 }
 
 func (r *sqliteLiveKitRepo) Update(ctx context.Context, instance *models.LiveKitInstance) error {
-	query := `UPDATE livekit_instances SET url = ?, api_key = ?, api_secret = ?, max_servers = ?, hetzner_server_id = ? WHERE id = ?`
+	query := `UPDATE livekit_instances SET url = ?, api_key = ?, api_secret = ?, max_servers = ?, is_draining = ?, hetzner_server_id = ? WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query,
-		instance.URL, instance.APIKey, instance.APISecret, instance.MaxServers, instance.HetznerServerID, instance.ID,
+		instance.URL, instance.APIKey, instance.APISecret, instance.MaxServers, instance.IsDraining, instance.HetznerServerID, instance.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update livekit instance: %w", err)
@@ -206,7 +208,7 @@ func (r *sqliteLiveKitRepo) ListPlatformInstances(ctx context.Context) ([]models
 	query := `
 		SELECT id, url, api_key, api_secret, is_platform_managed,
 		       (SELECT COUNT(*) FROM servers WHERE livekit_instance_id = livekit_instances.id) AS server_count,
-		       max_servers, hetzner_server_id, created_at
+		       max_servers, is_draining, hetzner_server_id, created_at
 		FROM livekit_instances
 		WHERE is_platform_managed = 1
 		ORDER BY created_at ASC`
@@ -222,7 +224,7 @@ func (r *sqliteLiveKitRepo) ListPlatformInstances(ctx context.Context) ([]models
 		var inst models.LiveKitInstance
 		if err := rows.Scan(
 			&inst.ID, &inst.URL, &inst.APIKey, &inst.APISecret,
-			&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.HetznerServerID, &inst.CreatedAt,
+			&inst.IsPlatformManaged, &inst.ServerCount, &inst.MaxServers, &inst.IsDraining, &inst.HetznerServerID, &inst.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan livekit instance row: %w", err)
 		}
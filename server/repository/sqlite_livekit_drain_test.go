@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// Load balancing must skip draining instances even when they are the least
+// loaded — they keep serving attached servers but take no new ones.
+func TestGetLeastLoadedPlatformInstance_SkipsDraining(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteLiveKitRepo(f.DB)
+	ctx := context.Background()
+
+	if _, err := f.DB.Exec(`
+		INSERT INTO livekit_instances (id, url, api_key, api_secret, is_platform_managed, is_draining) VALUES
+			('lk_drain', 'ws://a', 'k', 's', 1, 1),
+			('lk_live',  'ws://b', 'k', 's', 1, 0);
+	`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	// Attach a server to the live instance so the draining one is strictly less loaded.
+	owner := f.User("")
+	if _, err := f.DB.Exec(
+		`INSERT INTO servers (id, name, owner_id, livekit_instance_id) VALUES ('sv1', 'S', ?, 'lk_live')`,
+		owner,
+	); err != nil {
+		t.Fatalf("seed server: %v", err)
+	}
+
+	inst, err := repo.GetLeastLoadedPlatformInstance(ctx)
+	if err != nil {
+		t.Fatalf("get least loaded: %v", err)
+	}
+	if inst.ID != "lk_live" {
+		t.Fatalf("picked %s, want the non-draining lk_live", inst.ID)
+	}
+
+	// With the last live instance draining too, there is nothing to assign.
+	if _, err := f.DB.Exec(`UPDATE livekit_instances SET is_draining = 1 WHERE id = 'lk_live'`); err != nil {
+		t.Fatalf("drain lk_live: %v", err)
+	}
+	if _, err := repo.GetLeastLoadedPlatformInstance(ctx); err == nil {
+		t.Fatal("expected no instance once every platform instance is draining")
+	}
+}
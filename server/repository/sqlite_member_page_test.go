@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// seedMember inserts a membership with an explicit joined_at so keyset order
+// is deterministic — dbtest rows otherwise share a CURRENT_TIMESTAMP second.
+func seedMember(t *testing.T, f *dbtest.Fixture, serverID, userID, joinedAt string) {
+	t.Helper()
+	_, err := f.DB.Exec(
+		`INSERT INTO server_members (server_id, user_id, joined_at) VALUES (?, ?, ?)`,
+		serverID, userID, joinedAt,
+	)
+	if err != nil {
+		t.Fatalf("seed member %s: %v", userID, err)
+	}
+}
+
+func TestListMemberPage(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteServerRepo(f.DB)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	for i, id := range []string{"ma", "mb", "mc", "md", "me"} {
+		f.User(id)
+		seedMember(t, f, serverID, id, fmt.Sprintf("2024-03-%02d 10:00:00", i+1))
+	}
+
+	page := func(p models.MemberListParams) ([]models.MemberPageRow, int) {
+		t.Helper()
+		rows, total, err := repo.ListMemberPage(ctx, serverID, p)
+		if err != nil {
+			t.Fatalf("ListMemberPage(%+v): %v", p, err)
+		}
+		return rows, total
+	}
+	ids := func(rows []models.MemberPageRow) []string {
+		out := make([]string, len(rows))
+		for i := range rows {
+			out[i] = rows[i].User.ID
+		}
+		return out
+	}
+
+	// First page in join order; total counts all members, not just the page.
+	rows, total := page(models.MemberListParams{Limit: 2})
+	eqOrderedIDs(t, ids(rows), []string{"ma", "mb"})
+	if total != 5 {
+		t.Fatalf("total = %d, want 5", total)
+	}
+
+	// Cursor continues after the previous page's last member; the final short
+	// page signals the end of the list.
+	rows, _ = page(models.MemberListParams{Limit: 2, After: "mb"})
+	eqOrderedIDs(t, ids(rows), []string{"mc", "md"})
+	rows, _ = page(models.MemberListParams{Limit: 2, After: "md"})
+	eqOrderedIDs(t, ids(rows), []string{"me"})
+
+	// q matches username and display name; total follows the filter.
+	if _, err := f.DB.Exec(`UPDATE users SET display_name = 'Crab Dealer' WHERE id = 'ma'`); err != nil {
+		t.Fatalf("set display name: %v", err)
+	}
+	rows, total = page(models.MemberListParams{Limit: 10, Q: "user_mc"})
+	eqOrderedIDs(t, ids(rows), []string{"mc"})
+	if total != 1 {
+		t.Fatalf("username-filtered total = %d, want 1", total)
+	}
+	rows, _ = page(models.MemberListParams{Limit: 10, Q: "crab"})
+	eqOrderedIDs(t, ids(rows), []string{"ma"})
+
+	// role_id keeps only holders of that role.
+	if _, err := f.DB.Exec(
+		`INSERT INTO roles (id, server_id, name, color, position, permissions) VALUES ('r_mod', ?, 'Mod', '#fff', 1, 0)`,
+		serverID,
+	); err != nil {
+		t.Fatalf("seed role: %v", err)
+	}
+	for _, uid := range []string{"mb", "md"} {
+		if _, err := f.DB.Exec(
+			`INSERT INTO user_roles (user_id, role_id, server_id) VALUES (?, 'r_mod', ?)`,
+			uid, serverID,
+		); err != nil {
+			t.Fatalf("assign role to %s: %v", uid, err)
+		}
+	}
+	rows, total = page(models.MemberListParams{Limit: 10, RoleID: "r_mod"})
+	eqOrderedIDs(t, ids(rows), []string{"mb", "md"})
+	if total != 2 {
+		t.Fatalf("role-filtered total = %d, want 2", total)
+	}
+
+	// Soft-deleted users drop out of both the page and the total.
+	if _, err := f.DB.Exec(`UPDATE users SET deleted_at = datetime('now') WHERE id = 'me'`); err != nil {
+		t.Fatalf("soft-delete user: %v", err)
+	}
+	_, total = page(models.MemberListParams{Limit: 10})
+	if total != 4 {
+		t.Fatalf("total after soft delete = %d, want 4", total)
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// timeout_until round-trips through the membership row: set, read, clear
+// explicitly with nil, and clear in bulk once expired.
+func TestMemberTimeout_SetGetClearAndSweep(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteServerRepo(f.DB)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	userID := f.User("")
+	if err := repo.AddMember(ctx, serverID, userID); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+
+	until := time.Now().Add(time.Hour)
+	if err := repo.SetMemberTimeout(ctx, serverID, userID, &until); err != nil {
+		t.Fatalf("set timeout: %v", err)
+	}
+	got, err := repo.GetMemberTimeout(ctx, serverID, userID)
+	if err != nil {
+		t.Fatalf("get timeout: %v", err)
+	}
+	if got == nil || got.Sub(until).Abs() > time.Second {
+		t.Fatalf("stored timeout = %v, want ~%v", got, until)
+	}
+
+	// A sweep with "now" before expiry must not touch the row.
+	cleared, err := repo.ClearExpiredTimeouts(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("sweep before expiry: %v", err)
+	}
+	if len(cleared) != 0 {
+		t.Fatalf("unexpired timeout swept: %+v", cleared)
+	}
+
+	// Once "now" passes the expiry, the sweep clears and reports the membership.
+	cleared, err = repo.ClearExpiredTimeouts(ctx, time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("sweep after expiry: %v", err)
+	}
+	if len(cleared) != 1 || cleared[0].ServerID != serverID || cleared[0].UserID != userID {
+		t.Fatalf("sweep result = %+v, want one entry for %s/%s", cleared, serverID, userID)
+	}
+	if got, _ = repo.GetMemberTimeout(ctx, serverID, userID); got != nil {
+		t.Fatalf("timeout not cleared by sweep: %v", got)
+	}
+
+	// Explicit clear: set again, then nil it out.
+	if err := repo.SetMemberTimeout(ctx, serverID, userID, &until); err != nil {
+		t.Fatalf("re-set timeout: %v", err)
+	}
+	if err := repo.SetMemberTimeout(ctx, serverID, userID, nil); err != nil {
+		t.Fatalf("clear timeout: %v", err)
+	}
+	if got, _ = repo.GetMemberTimeout(ctx, serverID, userID); got != nil {
+		t.Fatalf("timeout not cleared explicitly: %v", got)
+	}
+}
+
+func TestMemberTimeout_SetOnNonMemberIsNotFound(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteServerRepo(f.DB)
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	outsider := f.User("")
+
+	until := time.Now().Add(time.Hour)
+	err := repo.SetMemberTimeout(context.Background(), serverID, outsider, &until)
+	if !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("set on non-member: got %v, want ErrNotFound", err)
+	}
+}
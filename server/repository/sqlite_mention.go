@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
 )
 
 type sqliteMentionRepo struct {
@@ -16,22 +17,29 @@ func NewSQLiteMentionRepo(db database.TxQuerier) MentionRepository {
 	return &sqliteMentionRepo{db: db}
 }
 
-// SaveMentions batch-inserts mentions for a message.
+// SaveMentions batch-inserts direct mentions for a message.
 // INSERT OR IGNORE skips duplicates if the same user is mentioned multiple times.
 func (r *sqliteMentionRepo) SaveMentions(ctx context.Context, messageID string, userIDs []string) error {
+	return r.SaveMentionsWithKind(ctx, messageID, userIDs, models.MentionKindDirect)
+}
+
+// SaveMentionsWithKind batch-inserts mentions with an explicit kind. OR IGNORE
+// keeps the first kind written: save direct rows before @here expansion so a
+// user both named and online keeps the stronger direct kind.
+func (r *sqliteMentionRepo) SaveMentionsWithKind(ctx context.Context, messageID string, userIDs []string, kind string) error {
 	if len(userIDs) == 0 {
 		return nil
 	}
 
 	placeholders := make([]string, len(userIDs))
-	args := make([]interface{}, 0, len(userIDs)*2)
+	args := make([]interface{}, 0, len(userIDs)*3)
 	for i, uid := range userIDs {
-		placeholders[i] = "(?, ?)"
-		args = append(args, messageID, uid)
+		placeholders[i] = "(?, ?, ?)"
+		args = append(args, messageID, uid, kind)
 	}
 
 	query := fmt.Sprintf(
-		"INSERT OR IGNORE INTO message_mentions (message_id, user_id) VALUES %s",
+		"INSERT OR IGNORE INTO message_mentions (message_id, user_id, kind) VALUES %s",
 		strings.Join(placeholders, ", "),
 	)
 
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/akinalp/mqvi/database"
@@ -21,21 +22,32 @@ func NewSQLiteMessageRepo(db database.TxQuerier) MessageRepository {
 }
 
 func (r *sqliteMessageRepo) Create(ctx context.Context, message *models.Message) error {
+	if message.Type == "" {
+		message.Type = models.MessageTypeDefault
+	}
+
 	query := `
-		INSERT INTO messages (id, channel_id, user_id, content, reply_to_id,
-			encryption_version, ciphertext, sender_device_id, e2ee_metadata)
-		VALUES (lower(hex(randomblob(8))), ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO messages (id, channel_id, user_id, type, content, reply_to_id,
+			encryption_version, ciphertext, sender_device_id, e2ee_metadata,
+			webhook_id, webhook_name, webhook_avatar_url, mention_scope, expires_at)
+		VALUES (lower(hex(randomblob(8))), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id, created_at`
 
 	err := r.db.QueryRowContext(ctx, query,
 		message.ChannelID,
 		message.UserID,
+		message.Type,
 		message.Content,
 		message.ReplyToID,
 		message.EncryptionVersion,
 		message.Ciphertext,
 		message.SenderDeviceID,
 		message.E2EEMetadata,
+		message.WebhookID,
+		message.WebhookName,
+		message.WebhookAvatarURL,
+		message.MentionScope,
+		message.ExpiresAt,
 	).Scan(&message.ID, &message.CreatedAt)
 
 	if err != nil {
@@ -49,8 +61,9 @@ func (r *sqliteMessageRepo) GetByID(ctx context.Context, id string) (*models.Mes
 	// LEFT JOIN: message stays visible even if author is deleted.
 	// Reply reference (rm/ru) loaded via LEFT JOIN.
 	query := `
-		SELECT m.id, m.channel_id, m.user_id, m.content, m.edited_at, m.created_at, m.reply_to_id,
+		SELECT m.id, m.channel_id, m.user_id, m.type, m.content, m.edited_at, m.created_at, m.reply_to_id,
 		       m.encryption_version, m.ciphertext, m.sender_device_id, m.e2ee_metadata,
+		       m.webhook_id, m.webhook_name, m.webhook_avatar_url, m.mention_scope, m.expires_at,
 		       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
 		       rm.id, rm.content,
 		       ru.id, ru.username, ru.display_name, ru.avatar_url, ru.deleted_at, ru.is_hard_deleted
@@ -70,8 +83,9 @@ func (r *sqliteMessageRepo) GetByID(ctx context.Context, id string) (*models.Mes
 	var refAuthorIsHardDeleted sql.NullBool
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Content, &msg.EditedAt, &msg.CreatedAt, &msg.ReplyToID,
+		&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Type, &msg.Content, &msg.EditedAt, &msg.CreatedAt, &msg.ReplyToID,
 		&msg.EncryptionVersion, &msg.Ciphertext, &msg.SenderDeviceID, &msg.E2EEMetadata,
+		&msg.WebhookID, &msg.WebhookName, &msg.WebhookAvatarURL, &msg.MentionScope, &msg.ExpiresAt,
 		&authorID, &author.Username, &author.DisplayName, &author.AvatarURL, &author.Status, &author.DeletedAt, &author.IsHardDeleted,
 		&refMsgID, &refMsgContent,
 		&refAuthorID, &refAuthorUsername, &refAuthorDisplayName, &refAuthorAvatarURL, &refAuthorDeletedAt, &refAuthorIsHardDeleted,
@@ -98,50 +112,98 @@ func (r *sqliteMessageRepo) GetByID(ctx context.Context, id string) (*models.Mes
 // GetByChannelID returns messages with cursor-based pagination.
 // Reply references are loaded via LEFT JOIN (max 1 per message, so JOIN is preferred over batch).
 // Results are DESC-ordered (frontend reverses for display).
-func (r *sqliteMessageRepo) GetByChannelID(ctx context.Context, channelID string, beforeID string, limit int) ([]models.Message, error) {
-	var query string
-	var args []any
+// messageSelect is the joined projection scanMessageRows expects.
+const messageSelect = `
+		SELECT m.id, m.channel_id, m.user_id, m.type, m.content, m.edited_at, m.created_at, m.reply_to_id,
+		       m.encryption_version, m.ciphertext, m.sender_device_id, m.e2ee_metadata,
+		       m.webhook_id, m.webhook_name, m.webhook_avatar_url, m.mention_scope, m.expires_at,
+		       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
+		       rm.id, rm.content,
+		       ru.id, ru.username, ru.display_name, ru.avatar_url, ru.deleted_at, ru.is_hard_deleted
+		FROM messages m
+		LEFT JOIN users u ON m.user_id = u.id
+		LEFT JOIN messages rm ON m.reply_to_id = rm.id
+		LEFT JOIN users ru ON rm.user_id = ru.id`
 
+func (r *sqliteMessageRepo) GetByChannelID(ctx context.Context, channelID string, beforeID string, limit int) ([]models.Message, error) {
 	if beforeID == "" {
-		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.edited_at, m.created_at, m.reply_to_id,
-			       m.encryption_version, m.ciphertext, m.sender_device_id, m.e2ee_metadata,
-			       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
-			       rm.id, rm.content,
-			       ru.id, ru.username, ru.display_name, ru.avatar_url, ru.deleted_at, ru.is_hard_deleted
-			FROM messages m
-			LEFT JOIN users u ON m.user_id = u.id
-			LEFT JOIN messages rm ON m.reply_to_id = rm.id
-			LEFT JOIN users ru ON rm.user_id = ru.id
+		return r.queryMessages(ctx, messageSelect+`
 			WHERE m.channel_id = ?
 			ORDER BY m.created_at DESC
-			LIMIT ?`
-		args = []any{channelID, limit}
-	} else {
-		// Cursor pagination: fetch messages older than beforeID's created_at
-		query = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.edited_at, m.created_at, m.reply_to_id,
-			       m.encryption_version, m.ciphertext, m.sender_device_id, m.e2ee_metadata,
-			       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
-			       rm.id, rm.content,
-			       ru.id, ru.username, ru.display_name, ru.avatar_url, ru.deleted_at, ru.is_hard_deleted
-			FROM messages m
-			LEFT JOIN users u ON m.user_id = u.id
-			LEFT JOIN messages rm ON m.reply_to_id = rm.id
-			LEFT JOIN users ru ON rm.user_id = ru.id
-			WHERE m.channel_id = ?
-			  AND m.created_at < (SELECT created_at FROM messages WHERE id = ?)
-			ORDER BY m.created_at DESC
-			LIMIT ?`
-		args = []any{channelID, beforeID, limit}
+			LIMIT ?`, channelID, limit)
 	}
 
+	// Cursor pagination: fetch messages older than beforeID's created_at
+	return r.queryMessages(ctx, messageSelect+`
+		WHERE m.channel_id = ?
+		  AND m.created_at < (SELECT created_at FROM messages WHERE id = ?)
+		ORDER BY m.created_at DESC
+		LIMIT ?`, channelID, beforeID, limit)
+}
+
+// GetAroundID anchors on the target's created_at, reusing the cursor predicate
+// GetByChannelID pages with. One extra row per direction answers "is there
+// more history past this window" without a second query.
+func (r *sqliteMessageRepo) GetAroundID(ctx context.Context, channelID, messageID string, limit int) ([]models.Message, bool, bool, error) {
+	// The target row doubles as the channel check — a message ID from another
+	// channel (or another server) comes back not found.
+	target, err := r.queryMessages(ctx, messageSelect+`
+		WHERE m.id = ? AND m.channel_id = ?`, messageID, channelID)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if len(target) == 0 {
+		return nil, false, false, fmt.Errorf("%w: message not found in this channel", pkg.ErrNotFound)
+	}
+
+	before, err := r.queryMessages(ctx, messageSelect+`
+		WHERE m.channel_id = ?
+		  AND m.created_at < (SELECT created_at FROM messages WHERE id = ?)
+		ORDER BY m.created_at DESC
+		LIMIT ?`, channelID, messageID, limit+1)
+	if err != nil {
+		return nil, false, false, err
+	}
+	hasBefore := len(before) > limit
+	if hasBefore {
+		before = before[:limit]
+	}
+	// DESC from the DB; flip to ascending before assembling the window.
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
+	}
+
+	after, err := r.queryMessages(ctx, messageSelect+`
+		WHERE m.channel_id = ?
+		  AND m.created_at > (SELECT created_at FROM messages WHERE id = ?)
+		ORDER BY m.created_at ASC
+		LIMIT ?`, channelID, messageID, limit+1)
+	if err != nil {
+		return nil, false, false, err
+	}
+	hasAfter := len(after) > limit
+	if hasAfter {
+		after = after[:limit]
+	}
+
+	window := append(before, target[0])
+	window = append(window, after...)
+	return window, hasBefore, hasAfter, nil
+}
+
+// queryMessages runs a messageSelect-shaped query and scans the rows.
+func (r *sqliteMessageRepo) queryMessages(ctx context.Context, query string, args ...any) ([]models.Message, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages by channel: %w", err)
 	}
 	defer rows.Close()
+	return scanMessageRows(rows)
+}
 
+// scanMessageRows reads joined message rows (the messageSelect projection)
+// into models, building author and reply-reference from the joined columns.
+func scanMessageRows(rows *sql.Rows) ([]models.Message, error) {
 	var messages []models.Message
 	for rows.Next() {
 		var msg models.Message
@@ -154,8 +216,9 @@ func (r *sqliteMessageRepo) GetByChannelID(ctx context.Context, channelID string
 		var refAuthorIsHardDeleted sql.NullBool
 
 		if err := rows.Scan(
-			&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Content, &msg.EditedAt, &msg.CreatedAt, &msg.ReplyToID,
+			&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Type, &msg.Content, &msg.EditedAt, &msg.CreatedAt, &msg.ReplyToID,
 			&msg.EncryptionVersion, &msg.Ciphertext, &msg.SenderDeviceID, &msg.E2EEMetadata,
+			&msg.WebhookID, &msg.WebhookName, &msg.WebhookAvatarURL, &msg.MentionScope, &msg.ExpiresAt,
 			&authorID, &author.Username, &author.DisplayName, &author.AvatarURL, &author.Status, &author.DeletedAt, &author.IsHardDeleted,
 			&refMsgID, &refMsgContent,
 			&refAuthorID, &refAuthorUsername, &refAuthorDisplayName, &refAuthorAvatarURL, &refAuthorDeletedAt, &refAuthorIsHardDeleted,
@@ -194,7 +257,7 @@ func (r *sqliteMessageRepo) Update(ctx context.Context, message *models.Message)
 		result, err = r.db.ExecContext(ctx,
 			`UPDATE messages
 			 SET encryption_version = 1, ciphertext = ?, sender_device_id = ?, e2ee_metadata = ?,
-			     content = NULL, edited_at = ?
+			     content = NULL, mention_scope = NULL, edited_at = ?
 			 WHERE id = ?`,
 			message.Ciphertext, message.SenderDeviceID, message.E2EEMetadata, now, message.ID,
 		)
@@ -202,9 +265,9 @@ func (r *sqliteMessageRepo) Update(ctx context.Context, message *models.Message)
 		result, err = r.db.ExecContext(ctx,
 			`UPDATE messages
 			 SET encryption_version = 0, content = ?, ciphertext = NULL, sender_device_id = NULL,
-			     e2ee_metadata = NULL, edited_at = ?
+			     e2ee_metadata = NULL, mention_scope = ?, edited_at = ?
 			 WHERE id = ?`,
-			message.Content, now, message.ID,
+			message.Content, message.MentionScope, now, message.ID,
 		)
 	}
 	if err != nil {
@@ -223,8 +286,31 @@ func (r *sqliteMessageRepo) Update(ctx context.Context, message *models.Message)
 	return nil
 }
 
+// ReplyChainDepth walks reply_to_id upward from messageID. The depth guard in
+// the recursive term doubles as cycle protection: a corrupted self- or mutual
+// reference just saturates at maxDepth, which callers treat as "too deep".
+func (r *sqliteMessageRepo) ReplyChainDepth(ctx context.Context, messageID string, maxDepth int) (int, error) {
+	query := `
+		WITH RECURSIVE chain(reply_to_id, depth) AS (
+			SELECT reply_to_id, 1 FROM messages WHERE id = ?
+			UNION ALL
+			SELECT m.reply_to_id, c.depth + 1
+			FROM messages m
+			JOIN chain c ON m.id = c.reply_to_id
+			WHERE c.depth < ?
+		)
+		SELECT COALESCE(MAX(depth), 0) FROM chain`
+
+	var depth int
+	if err := r.db.QueryRowContext(ctx, query, messageID, maxDepth).Scan(&depth); err != nil {
+		return 0, fmt.Errorf("failed to measure reply chain depth: %w", err)
+	}
+	return depth, nil
+}
+
 func (r *sqliteMessageRepo) Delete(ctx context.Context, id string) error {
-	// Attachments CASCADE-deleted. Reply references preserved (no FK):
+	// Attachments and mention rows CASCADE-deleted — mention badges must not
+	// count messages that no longer exist. Reply references preserved (no FK):
 	// reply_to_id stays, LEFT JOIN returns NULL -> frontend shows "deleted message".
 	result, err := r.db.ExecContext(ctx, `DELETE FROM messages WHERE id = ?`, id)
 	if err != nil {
@@ -242,6 +328,238 @@ func (r *sqliteMessageRepo) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *sqliteMessageRepo) GetByIDs(ctx context.Context, ids []string) ([]models.Message, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.Repeat("?,", len(ids))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	query := fmt.Sprintf(
+		`SELECT id, channel_id, user_id, created_at FROM messages WHERE id IN (%s)`,
+		placeholders,
+	)
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages by ids: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		var m models.Message
+		if err := rows.Scan(&m.ID, &m.ChannelID, &m.UserID, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *sqliteMessageRepo) ListRecentIDsByUser(ctx context.Context, channelID, userID string, since time.Time, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM messages
+		WHERE channel_id = ? AND user_id = ? AND created_at >= ?
+		ORDER BY created_at DESC
+		LIMIT ?`,
+		channelID, userID, since.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent messages by user: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan message id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *sqliteMessageRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error) {
+	// Pins are excluded here, not at create time: a pin is an explicit "keep
+	// this", and unpinning re-exposes the stored deadline. The partial index
+	// on expires_at keeps this a no-op when nothing can expire.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, channel_id, user_id, created_at FROM messages
+		WHERE expires_at IS NOT NULL AND expires_at <= ?
+		  AND NOT EXISTS (SELECT 1 FROM pinned_messages pm WHERE pm.message_id = messages.id)
+		ORDER BY expires_at
+		LIMIT ?`,
+		now.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		var m models.Message
+		if err := rows.Scan(&m.ID, &m.ChannelID, &m.UserID, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan expired message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *sqliteMessageRepo) ListRetentionExpired(ctx context.Context, limit int) ([]models.Message, error) {
+	// The cutoff is computed per server inside SQL, against the same
+	// CURRENT_TIMESTAMP format created_at was written with — a moving window,
+	// so changing message_retention_days needs no row rewrites. Pins are
+	// excluded the same way ListExpired excludes them.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.channel_id, m.user_id, m.created_at
+		FROM messages m
+		JOIN channels c ON c.id = m.channel_id
+		JOIN servers s ON s.id = c.server_id
+		WHERE s.message_retention_days > 0
+		  AND m.created_at < datetime('now', '-' || s.message_retention_days || ' days')
+		  AND NOT EXISTS (SELECT 1 FROM pinned_messages pm WHERE pm.message_id = m.id)
+		ORDER BY m.created_at
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retention-expired messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []models.Message
+	for rows.Next() {
+		var m models.Message
+		if err := rows.Scan(&m.ID, &m.ChannelID, &m.UserID, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan retention-expired message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *sqliteMessageRepo) DeleteBulk(ctx context.Context, channelID string, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.Repeat("?,", len(ids))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	// The channel_id guard makes the statement safe on its own: even if the
+	// caller's validation raced a message move, nothing outside the channel goes.
+	// Attachments CASCADE; reply_to_id references stay (see Delete above).
+	query := fmt.Sprintf(
+		`DELETE FROM messages WHERE channel_id = ? AND id IN (%s)`,
+		placeholders,
+	)
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, channelID)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk delete messages: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return int(affected), nil
+}
+
+func (r *sqliteMessageRepo) GetChannelStats(ctx context.Context, channelID string) (*models.ChannelStats, error) {
+	query := `
+		SELECT COUNT(*), MIN(created_at), MAX(created_at)
+		FROM messages
+		WHERE channel_id = ?`
+
+	// MIN/MAX strip the column's declared type, so the driver returns the stored
+	// text instead of a parsed time — scan as string and parse here.
+	stats := &models.ChannelStats{ChannelID: channelID}
+	var first, last sql.NullString
+	err := r.db.QueryRowContext(ctx, query, channelID).Scan(&stats.MessageCount, &first, &last)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel stats: %w", err)
+	}
+	if first.Valid {
+		if t, err := time.Parse(time.DateTime, first.String); err == nil {
+			stats.FirstMessageAt = &t
+		}
+	}
+	if last.Valid {
+		if t, err := time.Parse(time.DateTime, last.String); err == nil {
+			stats.LastMessageAt = &t
+		}
+	}
+	return stats, nil
+}
+
+func (r *sqliteMessageRepo) RecordEdit(ctx context.Context, messageID, oldContent string, keep int) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO message_edits (message_id, old_content) VALUES (?, ?)`,
+		messageID, oldContent,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record edit: %w", err)
+	}
+	// edited_at is second-granular, so rowid is the revision order.
+	_, err = r.db.ExecContext(ctx,
+		`DELETE FROM message_edits
+		 WHERE message_id = ? AND rowid NOT IN (
+		     SELECT rowid FROM message_edits WHERE message_id = ? ORDER BY rowid DESC LIMIT ?)`,
+		messageID, messageID, keep,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune edit history: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteMessageRepo) GetEditHistory(ctx context.Context, messageID string) ([]models.MessageEdit, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT old_content, edited_at FROM message_edits WHERE message_id = ? ORDER BY rowid DESC`,
+		messageID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edit history: %w", err)
+	}
+	defer rows.Close()
+
+	edits := []models.MessageEdit{}
+	for rows.Next() {
+		var e models.MessageEdit
+		if err := rows.Scan(&e.Content, &e.EditedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan edit: %w", err)
+		}
+		edits = append(edits, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating edit rows: %w", err)
+	}
+
+	return edits, nil
+}
+
+func (r *sqliteMessageRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM messages`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+	return count, nil
+}
+
 // buildMessageReference builds a MessageReference from LEFT JOIN results.
 //
 // Three cases:
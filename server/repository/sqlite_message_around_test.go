@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+func assertWindowIDs(t *testing.T, got []models.Message, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("window has %d messages, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i] {
+			t.Fatalf("window[%d] = %s, want %s", i, got[i].ID, want[i])
+		}
+	}
+}
+
+func TestGetAroundID(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	f.User("ar_u")
+	serverID := f.Server(dbtest.ServerSeed{ID: "ar_s", OwnerID: "ar_u"})
+	channelID := f.Channel("", serverID)
+
+	// Distinct timestamps — seeded rows otherwise share one CURRENT_TIMESTAMP
+	// second and the window anchors on created_at.
+	ids := make([]string, 7)
+	for i := range ids {
+		ids[i] = f.Message(dbtest.MessageSeed{
+			ID: fmt.Sprintf("ar_m%d", i), ChannelID: channelID, UserID: "ar_u",
+			Content: dbtest.Ptr(fmt.Sprintf("message %d", i)),
+		})
+		if _, err := f.DB.Exec(`UPDATE messages SET created_at = ? WHERE id = ?`,
+			fmt.Sprintf("2024-05-01 10:00:%02d", i), ids[i]); err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+	}
+
+	t.Run("mid-history window has both flags set", func(t *testing.T) {
+		window, hasBefore, hasAfter, err := repo.GetAroundID(ctx, channelID, ids[3], 2)
+		if err != nil {
+			t.Fatalf("GetAroundID: %v", err)
+		}
+		assertWindowIDs(t, window, ids[1:6])
+		if !hasBefore || !hasAfter {
+			t.Fatalf("hasBefore=%v hasAfter=%v, want both true", hasBefore, hasAfter)
+		}
+	})
+
+	t.Run("window at the oldest message", func(t *testing.T) {
+		window, hasBefore, hasAfter, err := repo.GetAroundID(ctx, channelID, ids[0], 2)
+		if err != nil {
+			t.Fatalf("GetAroundID: %v", err)
+		}
+		assertWindowIDs(t, window, ids[0:3])
+		if hasBefore || !hasAfter {
+			t.Fatalf("hasBefore=%v hasAfter=%v, want false/true", hasBefore, hasAfter)
+		}
+	})
+
+	t.Run("window at the newest message", func(t *testing.T) {
+		window, hasBefore, hasAfter, err := repo.GetAroundID(ctx, channelID, ids[6], 2)
+		if err != nil {
+			t.Fatalf("GetAroundID: %v", err)
+		}
+		assertWindowIDs(t, window, ids[4:7])
+		if !hasBefore || hasAfter {
+			t.Fatalf("hasBefore=%v hasAfter=%v, want true/false", hasBefore, hasAfter)
+		}
+	})
+
+	t.Run("limit wider than history returns everything", func(t *testing.T) {
+		window, hasBefore, hasAfter, err := repo.GetAroundID(ctx, channelID, ids[3], 10)
+		if err != nil {
+			t.Fatalf("GetAroundID: %v", err)
+		}
+		assertWindowIDs(t, window, ids)
+		if hasBefore || hasAfter {
+			t.Fatalf("hasBefore=%v hasAfter=%v, want both false", hasBefore, hasAfter)
+		}
+	})
+
+	t.Run("target in another channel reads as not found", func(t *testing.T) {
+		otherChannel := f.Channel("", serverID)
+		_, _, _, err := repo.GetAroundID(ctx, otherChannel, ids[3], 2)
+		if !errors.Is(err, pkg.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unknown target reads as not found", func(t *testing.T) {
+		_, _, _, err := repo.GetAroundID(ctx, channelID, "ar_missing", 2)
+		if !errors.Is(err, pkg.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestDMGetMessagesAround(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteDMRepo(f.DB)
+	ctx := context.Background()
+
+	f.User("ard_a")
+	f.User("ard_b")
+	channelID := f.DMChannel("ard_dc", "ard_a", "ard_b")
+
+	ids := make([]string, 5)
+	for i := range ids {
+		ids[i] = f.DMMessage(dbtest.DMMessageSeed{
+			ID: fmt.Sprintf("ard_m%d", i), DMChannelID: channelID, UserID: "ard_a",
+			Content: dbtest.Ptr(fmt.Sprintf("dm %d", i)),
+		})
+		if _, err := f.DB.Exec(`UPDATE dm_messages SET created_at = ? WHERE id = ?`,
+			fmt.Sprintf("2024-05-02 09:00:%02d", i), ids[i]); err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+	}
+
+	window, hasBefore, hasAfter, err := repo.GetMessagesAround(ctx, channelID, ids[2], 1)
+	if err != nil {
+		t.Fatalf("GetMessagesAround: %v", err)
+	}
+	if len(window) != 3 || window[0].ID != ids[1] || window[1].ID != ids[2] || window[2].ID != ids[3] {
+		t.Fatalf("window = %v, want [%s %s %s]", window, ids[1], ids[2], ids[3])
+	}
+	if !hasBefore || !hasAfter {
+		t.Fatalf("hasBefore=%v hasAfter=%v, want both true", hasBefore, hasAfter)
+	}
+
+	_, _, _, err = repo.GetMessagesAround(ctx, "ard_other", ids[2], 1)
+	if !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a foreign conversation, got %v", err)
+	}
+}
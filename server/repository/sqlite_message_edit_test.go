@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+func TestMessageRecordEdit_OrderAndPrune(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	id := f.Message(dbtest.MessageSeed{Content: dbtest.Ptr("current")})
+
+	// One past the cap — the oldest revision must fall off.
+	for i := 0; i < 4; i++ {
+		if err := repo.RecordEdit(ctx, id, fmt.Sprintf("rev-%d", i), 3); err != nil {
+			t.Fatalf("record edit %d: %v", i, err)
+		}
+	}
+
+	history, err := repo.GetEditHistory(ctx, id)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("history length = %d, want the cap (3)", len(history))
+	}
+	// Newest first; rev-0 pruned.
+	for i, want := range []string{"rev-3", "rev-2", "rev-1"} {
+		if history[i].Content != want {
+			t.Errorf("history[%d] = %q, want %q", i, history[i].Content, want)
+		}
+	}
+}
+
+func TestMessageRecordEdit_PruneIsPerMessage(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	a := f.Message(dbtest.MessageSeed{Content: dbtest.Ptr("a")})
+	b := f.Message(dbtest.MessageSeed{Content: dbtest.Ptr("b")})
+
+	if err := repo.RecordEdit(ctx, a, "a-old", 1); err != nil {
+		t.Fatalf("record a: %v", err)
+	}
+	// Filling b's history must not evict a's.
+	if err := repo.RecordEdit(ctx, b, "b-old", 1); err != nil {
+		t.Fatalf("record b: %v", err)
+	}
+
+	history, err := repo.GetEditHistory(ctx, a)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(history) != 1 || history[0].Content != "a-old" {
+		t.Fatalf("history for a = %+v, want its own single revision", history)
+	}
+}
+
+func TestMessageGetEditHistory_EmptyForUnedited(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+
+	id := f.Message(dbtest.MessageSeed{Content: dbtest.Ptr("never edited")})
+
+	history, err := repo.GetEditHistory(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get history: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("history = %+v, want empty", history)
+	}
+}
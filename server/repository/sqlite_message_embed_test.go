@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+func TestMessageEmbedRoundTrip(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageEmbedRepo(f.DB)
+	ctx := context.Background()
+
+	f.User("emb_u")
+	serverID := f.Server(dbtest.ServerSeed{ID: "emb_s", OwnerID: "emb_u"})
+	channelID := f.Channel("", serverID)
+	withEmbed := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: "emb_u", Content: dbtest.Ptr("https://example.com")})
+	plain := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: "emb_u", Content: dbtest.Ptr("no links")})
+
+	embed := &models.MessageEmbed{
+		MessageID: withEmbed,
+		URL:       "https://example.com",
+		Title:     dbtest.Ptr("Example"),
+	}
+	if err := repo.Upsert(ctx, embed); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	// Upsert on the same message replaces, not duplicates.
+	embed.Title = dbtest.Ptr("Example, revised")
+	if err := repo.Upsert(ctx, embed); err != nil {
+		t.Fatalf("second Upsert: %v", err)
+	}
+
+	got, err := repo.GetByMessageIDs(ctx, []string{withEmbed, plain})
+	if err != nil {
+		t.Fatalf("GetByMessageIDs: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d embeds, want 1 (no entry for the plain message)", len(got))
+	}
+	if e := got[withEmbed]; e == nil || e.Title == nil || *e.Title != "Example, revised" {
+		t.Fatalf("embed = %+v, want the revised upsert", got[withEmbed])
+	}
+
+	if err := repo.Delete(ctx, withEmbed); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	got, err = repo.GetByMessageIDs(ctx, []string{withEmbed})
+	if err != nil {
+		t.Fatalf("GetByMessageIDs after delete: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatal("embed survived Delete")
+	}
+}
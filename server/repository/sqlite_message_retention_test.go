@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// The retention cutoff is computed inside SQL against the CURRENT_TIMESTAMP
+// format created_at was written with — this test exercises that comparison on
+// a real database, because a format mismatch would silently match nothing.
+func TestListRetentionExpired(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	owner := f.User("")
+	retained := f.Server(dbtest.ServerSeed{OwnerID: owner})
+	forever := f.Server(dbtest.ServerSeed{OwnerID: owner})
+
+	if _, err := f.DB.Exec(`UPDATE servers SET message_retention_days = 30 WHERE id = ?`, retained); err != nil {
+		t.Fatalf("set retention: %v", err)
+	}
+
+	chRetained := f.Channel("", retained)
+	chForever := f.Channel("", forever)
+
+	old := f.Message(dbtest.MessageSeed{ChannelID: chRetained, UserID: owner, Content: dbtest.Ptr("old")})
+	// A message inside the window stays regardless of server setting.
+	f.Message(dbtest.MessageSeed{ChannelID: chRetained, UserID: owner, Content: dbtest.Ptr("recent")})
+	pinned := f.Message(dbtest.MessageSeed{ChannelID: chRetained, UserID: owner, Content: dbtest.Ptr("pinned")})
+	keeper := f.Message(dbtest.MessageSeed{ChannelID: chForever, UserID: owner, Content: dbtest.Ptr("no retention")})
+
+	// Backdate everything but the recent message past the 30-day window.
+	for _, id := range []string{old, pinned, keeper} {
+		if _, err := f.DB.Exec(
+			`UPDATE messages SET created_at = datetime('now', '-60 days') WHERE id = ?`, id,
+		); err != nil {
+			t.Fatalf("backdate %s: %v", id, err)
+		}
+	}
+	if _, err := f.DB.Exec(
+		`INSERT INTO pinned_messages (message_id, channel_id, pinned_by) VALUES (?, ?, ?)`,
+		pinned, chRetained, owner,
+	); err != nil {
+		t.Fatalf("pin message: %v", err)
+	}
+
+	expired, err := repo.ListRetentionExpired(ctx, 100)
+	if err != nil {
+		t.Fatalf("ListRetentionExpired: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != old {
+		t.Fatalf("ListRetentionExpired = %+v, want only %s (pins and retention-off servers exempt)", expired, old)
+	}
+}
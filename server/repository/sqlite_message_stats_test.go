@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// The stats aggregate must bucket by channel and report nil timestamps for an
+// empty channel — MIN/MAX over zero rows come back as SQL NULL, and scanning
+// them into time.Time directly would error.
+func TestGetChannelStats(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	author := f.User("")
+	serverID := f.Server(dbtest.ServerSeed{})
+	busy := f.Channel("", serverID)
+	empty := f.Channel("", serverID)
+	for i := 0; i < 3; i++ {
+		f.Message(dbtest.MessageSeed{ChannelID: busy, UserID: author, Content: dbtest.Ptr("hi")})
+	}
+
+	stats, err := repo.GetChannelStats(ctx, busy)
+	if err != nil {
+		t.Fatalf("get channel stats: %v", err)
+	}
+	if stats.ChannelID != busy || stats.MessageCount != 3 {
+		t.Fatalf("expected 3 messages for %s, got %+v", busy, stats)
+	}
+	if stats.FirstMessageAt == nil || stats.LastMessageAt == nil {
+		t.Fatalf("expected timestamps for a non-empty channel, got %+v", stats)
+	}
+	if stats.LastMessageAt.Before(*stats.FirstMessageAt) {
+		t.Fatalf("last message precedes first: %+v", stats)
+	}
+
+	stats, err = repo.GetChannelStats(ctx, empty)
+	if err != nil {
+		t.Fatalf("get channel stats for empty channel: %v", err)
+	}
+	if stats.MessageCount != 0 {
+		t.Fatalf("expected zero count for empty channel, got %+v", stats)
+	}
+	if stats.FirstMessageAt != nil || stats.LastMessageAt != nil {
+		t.Fatalf("expected nil timestamps for empty channel, got %+v", stats)
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+)
+
+type sqliteNotificationSettingRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteNotificationSettingRepo(db database.TxQuerier) NotificationSettingRepository {
+	return &sqliteNotificationSettingRepo{db: db}
+}
+
+func (r *sqliteNotificationSettingRepo) Upsert(ctx context.Context, userID string, setting *models.NotificationSetting) error {
+	query := `
+		INSERT INTO notification_settings (user_id, scope_type, scope_id, level, suppress_everyone)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, scope_type, scope_id)
+		DO UPDATE SET level = excluded.level,
+		              suppress_everyone = excluded.suppress_everyone`
+
+	_, err := r.db.ExecContext(ctx, query, userID, setting.ScopeType, setting.ScopeID, setting.Level, setting.SuppressEveryone)
+	if err != nil {
+		return fmt.Errorf("failed to upsert notification setting: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteNotificationSettingRepo) Get(ctx context.Context, userID, scopeType, scopeID string) (*models.NotificationSetting, error) {
+	query := `
+		SELECT scope_type, scope_id, level, suppress_everyone
+		FROM notification_settings
+		WHERE user_id = ? AND scope_type = ? AND scope_id = ?`
+
+	var s models.NotificationSetting
+	err := r.db.QueryRowContext(ctx, query, userID, scopeType, scopeID).
+		Scan(&s.ScopeType, &s.ScopeID, &s.Level, &s.SuppressEveryone)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification setting: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *sqliteNotificationSettingRepo) ListByUser(ctx context.Context, userID string) ([]models.NotificationSetting, error) {
+	query := `
+		SELECT scope_type, scope_id, level, suppress_everyone
+		FROM notification_settings
+		WHERE user_id = ?`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification settings: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []models.NotificationSetting
+	for rows.Next() {
+		var s models.NotificationSetting
+		if err := rows.Scan(&s.ScopeType, &s.ScopeID, &s.Level, &s.SuppressEveryone); err != nil {
+			return nil, fmt.Errorf("failed to scan notification setting: %w", err)
+		}
+		settings = append(settings, s)
+	}
+	return settings, rows.Err()
+}
+
+func (r *sqliteNotificationSettingRepo) Delete(ctx context.Context, userID, scopeType, scopeID string) error {
+	query := `DELETE FROM notification_settings WHERE user_id = ? AND scope_type = ? AND scope_id = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, scopeType, scopeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification setting: %w", err)
+	}
+	return nil
+}
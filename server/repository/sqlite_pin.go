@@ -23,7 +23,7 @@ func NewSQLitePinRepo(db database.TxQuerier) PinRepository {
 // 3-way JOIN: pinned_messages -> messages -> users.
 func (r *sqlitePinRepo) GetByChannelID(ctx context.Context, channelID string) ([]models.PinnedMessageWithDetails, error) {
 	query := `
-		SELECT p.id, p.message_id, p.channel_id, p.pinned_by, p.created_at,
+		SELECT p.id, p.message_id, p.channel_id, p.pinned_by, p.pin_position, p.created_at,
 		       m.id, m.channel_id, m.user_id, m.content, m.edited_at, m.created_at,
 		       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted,
 		       pb.id, pb.username, pb.display_name, pb.avatar_url, pb.deleted_at, pb.is_hard_deleted
@@ -32,7 +32,7 @@ func (r *sqlitePinRepo) GetByChannelID(ctx context.Context, channelID string) ([
 		LEFT JOIN users u ON m.user_id = u.id
 		LEFT JOIN users pb ON p.pinned_by = pb.id
 		WHERE p.channel_id = ?
-		ORDER BY p.created_at DESC`
+		ORDER BY p.pin_position, p.created_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, query, channelID)
 	if err != nil {
@@ -50,7 +50,7 @@ func (r *sqlitePinRepo) GetByChannelID(ctx context.Context, channelID string) ([
 		var pinnedByID sql.NullString
 
 		if err := rows.Scan(
-			&pin.ID, &pin.MessageID, &pin.ChannelID, &pin.PinnedBy, &pin.CreatedAt,
+			&pin.ID, &pin.MessageID, &pin.ChannelID, &pin.PinnedBy, &pin.PinPosition, &pin.CreatedAt,
 			&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Content, &msg.EditedAt, &msg.CreatedAt,
 			&authorID, &author.Username, &author.DisplayName, &author.AvatarURL, &author.Status, &author.DeletedAt, &author.IsHardDeleted,
 			&pinnedByID, &pinnedByUser.Username, &pinnedByUser.DisplayName, &pinnedByUser.AvatarURL, &pinnedByUser.DeletedAt, &pinnedByUser.IsHardDeleted,
@@ -137,6 +137,43 @@ func (r *sqlitePinRepo) IsPinned(ctx context.Context, messageID string) (bool, e
 	return count > 0, nil
 }
 
+// UpdatePositions atomically rewrites pin_position for the given messages in
+// list order. Any ID that is not a pin in this channel aborts the whole batch.
+func (r *sqlitePinRepo) UpdatePositions(ctx context.Context, channelID string, messageIDs []string) error {
+	sqlDB, ok := r.db.(*sql.DB)
+	if !ok {
+		return fmt.Errorf("UpdatePositions requires *sql.DB to start transaction")
+	}
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		`UPDATE pinned_messages SET pin_position = ? WHERE message_id = ? AND channel_id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare pin position statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, messageID := range messageIDs {
+		result, err := stmt.ExecContext(ctx, i, messageID, channelID)
+		if err != nil {
+			return fmt.Errorf("failed to update position for pin %s: %w", messageID, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check rows affected for pin %s: %w", messageID, err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("%w: message %s is not pinned in this channel", pkg.ErrNotFound, messageID)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // CountByChannelID returns the pin count for a channel. Used to enforce per-channel pin limits.
 func (r *sqlitePinRepo) CountByChannelID(ctx context.Context, channelID string) (int, error) {
 	var count int
@@ -148,3 +185,32 @@ func (r *sqlitePinRepo) CountByChannelID(ctx context.Context, channelID string)
 	}
 	return count, nil
 }
+
+// CountsByServerID returns per-channel pin counts for a whole server in one
+// GROUP BY query. Used by the channel list endpoint's ?include=pins.
+func (r *sqlitePinRepo) CountsByServerID(ctx context.Context, serverID string) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT p.channel_id, COUNT(*)
+		FROM pinned_messages p
+		JOIN channels c ON p.channel_id = c.id
+		WHERE c.server_id = ?
+		GROUP BY p.channel_id`, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pinned messages by server: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var channelID string
+		var count int
+		if err := rows.Scan(&channelID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan pin count row: %w", err)
+		}
+		counts[channelID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pin count rows: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// CountsByServerID must bucket by channel, stay scoped to one server, and omit
+// channels without pins entirely (the caller treats a missing key as zero).
+func TestCountsByServerID(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLitePinRepo(f.DB)
+	ctx := context.Background()
+
+	author := f.User("")
+	serverID := f.Server(dbtest.ServerSeed{})
+	otherServerID := f.Server(dbtest.ServerSeed{})
+	pinned := f.Channel("", serverID)
+	unpinned := f.Channel("", serverID)
+	foreign := f.Channel("", otherServerID)
+
+	pin := func(channelID string) {
+		msgID := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: author, Content: dbtest.Ptr("pin me")})
+		if err := repo.Pin(ctx, &models.PinnedMessage{MessageID: msgID, ChannelID: channelID, PinnedBy: author}); err != nil {
+			t.Fatalf("pin message in %s: %v", channelID, err)
+		}
+	}
+	pin(pinned)
+	pin(pinned)
+	pin(foreign)
+
+	counts, err := repo.CountsByServerID(ctx, serverID)
+	if err != nil {
+		t.Fatalf("counts by server: %v", err)
+	}
+	if len(counts) != 1 {
+		t.Fatalf("expected only the pinned channel in the map, got %v", counts)
+	}
+	if counts[pinned] != 2 {
+		t.Errorf("expected 2 pins for %s, got %d", pinned, counts[pinned])
+	}
+	if _, ok := counts[unpinned]; ok {
+		t.Errorf("channel without pins must be absent from the map, got %v", counts)
+	}
+	if _, ok := counts[foreign]; ok {
+		t.Errorf("other server's channel leaked into the map: %v", counts)
+	}
+}
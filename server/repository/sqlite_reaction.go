@@ -50,6 +50,16 @@ func (r *sqliteReactionRepo) Toggle(ctx context.Context, messageID, userID, emoj
 	return false, nil
 }
 
+// CountUserReactions counts the user's distinct emoji on one message.
+func (r *sqliteReactionRepo) CountUserReactions(ctx context.Context, messageID, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM reactions WHERE message_id = ? AND user_id = ?`
+	if err := r.db.QueryRowContext(ctx, query, messageID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count user reactions: %w", err)
+	}
+	return count, nil
+}
+
 func (r *sqliteReactionRepo) GetByMessageID(ctx context.Context, messageID string) ([]models.ReactionGroup, error) {
 	query := `
 		SELECT emoji, COUNT(*) as count, GROUP_CONCAT(user_id) as users
@@ -118,6 +128,50 @@ func (r *sqliteReactionRepo) GetByMessageIDs(ctx context.Context, messageIDs []s
 	return result, nil
 }
 
+// ListReactors — see ReactionRepository.ListReactors.
+func (r *sqliteReactionRepo) ListReactors(ctx context.Context, messageID, emoji string, limit, offset int) ([]models.ReactionUser, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM reactions WHERE message_id = ? AND emoji = ?`
+	if err := r.db.QueryRowContext(ctx, countQuery, messageID, emoji).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count reactors: %w", err)
+	}
+
+	query := `
+		SELECT u.id, u.username, u.display_name, u.avatar_url
+		FROM reactions re
+		INNER JOIN users u ON u.id = re.user_id
+		WHERE re.message_id = ? AND re.emoji = ?
+		ORDER BY re.created_at ASC, u.id ASC
+		LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, messageID, emoji, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list reactors: %w", err)
+	}
+	defer rows.Close()
+
+	users, err := scanReactionUsers(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func scanReactionUsers(rows *sql.Rows) ([]models.ReactionUser, error) {
+	users := []models.ReactionUser{}
+	for rows.Next() {
+		var u models.ReactionUser
+		if err := rows.Scan(&u.ID, &u.Username, &u.DisplayName, &u.AvatarURL); err != nil {
+			return nil, fmt.Errorf("scan reactor row: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate reactor rows: %w", err)
+	}
+	return users, nil
+}
+
 func scanReactionGroups(rows *sql.Rows) ([]models.ReactionGroup, error) {
 	var groups []models.ReactionGroup
 	for rows.Next() {
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+func TestListReactors_OrderAndPaging(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteReactionRepo(f.DB)
+	ctx := context.Background()
+
+	messageID := f.Message(dbtest.MessageSeed{})
+	react := func(uid, emoji, createdAt string) {
+		t.Helper()
+		_, err := f.DB.Exec(
+			`INSERT INTO reactions (id, message_id, user_id, emoji, created_at) VALUES (?, ?, ?, ?, ?)`,
+			"re_"+uid, messageID, uid, emoji, createdAt,
+		)
+		if err != nil {
+			t.Fatalf("seed reaction %s: %v", uid, err)
+		}
+	}
+	// Explicit created_at keeps reaction order stable across the shared
+	// CURRENT_TIMESTAMP second.
+	for i, uid := range []string{"ra", "rb", "rc", "rd"} {
+		f.User(uid)
+		react(uid, "👍", fmt.Sprintf("2024-05-01 10:00:%02d", i))
+	}
+	f.User("rx")
+	react("rx", "🎉", "2024-05-01 10:01:00")
+
+	users, total, err := repo.ListReactors(ctx, messageID, "👍", 2, 0)
+	if err != nil {
+		t.Fatalf("list reactors: %v", err)
+	}
+	if total != 4 {
+		t.Fatalf("total = %d, want 4 (other emoji must not count)", total)
+	}
+	if len(users) != 2 || users[0].ID != "ra" || users[1].ID != "rb" {
+		t.Fatalf("first page = %+v, want ra, rb in reaction order", users)
+	}
+	if users[0].Username != "user_ra" {
+		t.Errorf("username not joined: %q", users[0].Username)
+	}
+
+	users, _, err = repo.ListReactors(ctx, messageID, "👍", 2, 2)
+	if err != nil {
+		t.Fatalf("list reactors page 2: %v", err)
+	}
+	if len(users) != 2 || users[0].ID != "rc" || users[1].ID != "rd" {
+		t.Fatalf("second page = %+v, want rc, rd", users)
+	}
+
+	users, total, err = repo.ListReactors(ctx, messageID, "🚫", 10, 0)
+	if err != nil {
+		t.Fatalf("list reactors unknown emoji: %v", err)
+	}
+	if total != 0 || len(users) != 0 {
+		t.Fatalf("unknown emoji: total %d, %d users; want empty page", total, len(users))
+	}
+}
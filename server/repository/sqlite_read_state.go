@@ -72,9 +72,12 @@ func (r *sqliteReadStateRepo) DecrementUnreadForDeleted(ctx context.Context, cha
 
 // GetUnreadCounts returns per-channel unread counts + mention watermarks.
 // Returns rows when unread_count > 0 OR a mention watermark is set (so the watermark survives refresh).
+// mention_count joins mention rows against live messages past the last-read
+// pointer, so deleted messages (CASCADE clears their mention rows) and
+// already-read mentions never inflate the badge.
 func (r *sqliteReadStateRepo) GetUnreadCounts(ctx context.Context, userID, serverID string) ([]models.UnreadInfo, error) {
 	query := `
-		SELECT id, unread_count, last_mention_seen_at, last_mention_seen_message_id FROM (
+		SELECT id, unread_count, mention_count, last_mention_seen_at, last_mention_seen_message_id FROM (
 			SELECT c.id,
 			       CASE WHEN cr.user_id IS NOT NULL
 			            THEN cr.unread_count
@@ -82,14 +85,23 @@ func (r *sqliteReadStateRepo) GetUnreadCounts(ctx context.Context, userID, serve
 			                  WHERE m.channel_id = c.id
 			                    AND m.user_id != ?)
 			       END as unread_count,
+			       (SELECT COUNT(*) FROM message_mentions mm
+			        JOIN messages m ON m.id = mm.message_id
+			        WHERE mm.user_id = ?
+			          AND m.channel_id = c.id
+			          AND m.user_id != ?
+			          AND (cr.last_read_message_id IS NULL
+			               OR m.created_at > (SELECT created_at FROM messages
+			                                  WHERE id = cr.last_read_message_id))
+			       ) as mention_count,
 			       cr.last_mention_seen_at as last_mention_seen_at,
 			       cr.last_mention_seen_message_id as last_mention_seen_message_id
 			FROM channels c
 			LEFT JOIN channel_reads cr ON cr.channel_id = c.id AND cr.user_id = ?
 			WHERE c.type = 'text' AND c.server_id = ?
-		) WHERE unread_count > 0 OR last_mention_seen_at IS NOT NULL`
+		) WHERE unread_count > 0 OR mention_count > 0 OR last_mention_seen_at IS NOT NULL`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, userID, serverID)
+	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID, serverID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get unread counts: %w", err)
 	}
@@ -101,6 +113,7 @@ func (r *sqliteReadStateRepo) GetUnreadCounts(ctx context.Context, userID, serve
 		if err := rows.Scan(
 			&info.ChannelID,
 			&info.UnreadCount,
+			&info.MentionCount,
 			&info.LastMentionSeenAt,
 			&info.LastMentionSeenMessageID,
 		); err != nil {
@@ -120,6 +133,68 @@ func (r *sqliteReadStateRepo) GetUnreadCounts(ctx context.Context, userID, serve
 	return unreads, nil
 }
 
+// GetAllUnreadCounts is the cross-server twin of GetUnreadCounts: one query over every
+// server the user is a member of, same per-channel CASE fallback and same row filter.
+func (r *sqliteReadStateRepo) GetAllUnreadCounts(ctx context.Context, userID string) ([]models.ServerUnreadInfo, error) {
+	query := `
+		SELECT server_id, id, unread_count, mention_count, last_mention_seen_at, last_mention_seen_message_id FROM (
+			SELECT c.server_id, c.id,
+			       CASE WHEN cr.user_id IS NOT NULL
+			            THEN cr.unread_count
+			            ELSE (SELECT COUNT(*) FROM messages m
+			                  WHERE m.channel_id = c.id
+			                    AND m.user_id != ?)
+			       END as unread_count,
+			       (SELECT COUNT(*) FROM message_mentions mm
+			        JOIN messages m ON m.id = mm.message_id
+			        WHERE mm.user_id = ?
+			          AND m.channel_id = c.id
+			          AND m.user_id != ?
+			          AND (cr.last_read_message_id IS NULL
+			               OR m.created_at > (SELECT created_at FROM messages
+			                                  WHERE id = cr.last_read_message_id))
+			       ) as mention_count,
+			       cr.last_mention_seen_at as last_mention_seen_at,
+			       cr.last_mention_seen_message_id as last_mention_seen_message_id
+			FROM channels c
+			JOIN server_members sm ON sm.server_id = c.server_id AND sm.user_id = ?
+			LEFT JOIN channel_reads cr ON cr.channel_id = c.id AND cr.user_id = ?
+			WHERE c.type = 'text'
+		) WHERE unread_count > 0 OR mention_count > 0 OR last_mention_seen_at IS NOT NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	var unreads []models.ServerUnreadInfo
+	for rows.Next() {
+		var info models.ServerUnreadInfo
+		if err := rows.Scan(
+			&info.ServerID,
+			&info.ChannelID,
+			&info.UnreadCount,
+			&info.MentionCount,
+			&info.LastMentionSeenAt,
+			&info.LastMentionSeenMessageID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan server unread info: %w", err)
+		}
+		unreads = append(unreads, info)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating server unread rows: %w", err)
+	}
+
+	if unreads == nil {
+		unreads = []models.ServerUnreadInfo{}
+	}
+
+	return unreads, nil
+}
+
 // SetMentionSeen advances the (created_at, message_id) tuple watermark.
 // Channel-guarded via id+channel_id match in the SELECT; monotonic via lex compare.
 func (r *sqliteReadStateRepo) SetMentionSeen(ctx context.Context, userID, channelID, mentionMessageID string) error {
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// The cross-server snapshot must only surface servers the user is a member of,
+// tag every row with its server, and keep the COUNT(*) fallback for channels
+// the user has never opened — same semantics as the per-server query, batched.
+func TestGetAllUnreadCounts(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteReadStateRepo(f.DB)
+	ctx := context.Background()
+
+	reader := f.User("")
+	author := f.User("")
+	serverA := f.Server(dbtest.ServerSeed{})
+	serverB := f.Server(dbtest.ServerSeed{})
+	outside := f.Server(dbtest.ServerSeed{})
+	chanA := f.Channel("", serverA)
+	chanB := f.Channel("", serverB)
+	chanOut := f.Channel("", outside)
+	for _, serverID := range []string{serverA, serverB} {
+		f.ExecWithoutForeignKeys(
+			`INSERT INTO server_members (server_id, user_id) VALUES (?, ?)`,
+			serverID, reader,
+		)
+	}
+	for _, channelID := range []string{chanA, chanB, chanOut} {
+		f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: author, Content: dbtest.Ptr("hi")})
+	}
+
+	unreads, err := repo.GetAllUnreadCounts(ctx, reader)
+	if err != nil {
+		t.Fatalf("get all unread counts: %v", err)
+	}
+	if len(unreads) != 2 {
+		t.Fatalf("expected 2 rows (member servers only), got %d: %+v", len(unreads), unreads)
+	}
+	byServer := map[string]int{}
+	for _, info := range unreads {
+		byServer[info.ServerID] = info.UnreadCount
+	}
+	if byServer[serverA] != 1 || byServer[serverB] != 1 {
+		t.Fatalf("expected one unread per member server, got %v", byServer)
+	}
+	if _, ok := byServer[outside]; ok {
+		t.Fatalf("non-member server leaked into the snapshot: %v", byServer)
+	}
+
+	// Marking a channel read drops it from the snapshot entirely — the snapshot
+	// only carries channels with unreads or a mention watermark.
+	latest := f.Message(dbtest.MessageSeed{ChannelID: chanA, UserID: author, Content: dbtest.Ptr("latest")})
+	if err := repo.Upsert(ctx, reader, chanA, latest); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	unreads, err = repo.GetAllUnreadCounts(ctx, reader)
+	if err != nil {
+		t.Fatalf("get all unread counts after read: %v", err)
+	}
+	if len(unreads) != 1 || unreads[0].ServerID != serverB {
+		t.Fatalf("expected only the unread server to remain, got %+v", unreads)
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// mention_count must follow the last-read pointer: only mentions past the
+// watermark count, self-authored messages never do, and a deleted message's
+// mentions vanish with it (FK CASCADE) instead of inflating the badge.
+func TestGetUnreadCounts_MentionCount(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteReadStateRepo(f.DB)
+	mentions := NewSQLiteMentionRepo(f.DB)
+	messages := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	reader := f.User("")
+	author := f.User("")
+	server := f.Server(dbtest.ServerSeed{})
+	channel := f.Channel("", server)
+
+	m1 := f.Message(dbtest.MessageSeed{ChannelID: channel, UserID: author, Content: dbtest.Ptr("hey <@" + reader + ">")})
+	m2 := f.Message(dbtest.MessageSeed{ChannelID: channel, UserID: author, Content: dbtest.Ptr("no ping")})
+	m3 := f.Message(dbtest.MessageSeed{ChannelID: channel, UserID: author, Content: dbtest.Ptr("again <@" + reader + ">")})
+	selfPing := f.Message(dbtest.MessageSeed{ChannelID: channel, UserID: reader, Content: dbtest.Ptr("note to self <@" + reader + ">")})
+
+	// Seeded rows share a CURRENT_TIMESTAMP second; the watermark compare is
+	// strict, so spread them out explicitly.
+	for i, id := range []string{m1, m2, m3, selfPing} {
+		f.ExecWithoutForeignKeys(
+			`UPDATE messages SET created_at = ? WHERE id = ?`,
+			"2025-01-01 10:0"+string(rune('0'+i))+":00", id,
+		)
+	}
+
+	for _, id := range []string{m1, m3, selfPing} {
+		if err := mentions.SaveMentions(ctx, id, []string{reader}); err != nil {
+			t.Fatalf("save mentions for %s: %v", id, err)
+		}
+	}
+
+	unreads, err := repo.GetUnreadCounts(ctx, reader, server)
+	if err != nil {
+		t.Fatalf("get unread counts: %v", err)
+	}
+	if len(unreads) != 1 {
+		t.Fatalf("expected 1 row, got %+v", unreads)
+	}
+	if unreads[0].MentionCount != 2 {
+		t.Errorf("expected 2 mentions before any read (self-ping excluded), got %d", unreads[0].MentionCount)
+	}
+
+	// Reading up to m1 leaves only the later mention; the row must survive on
+	// mention_count alone even with unread_count reset to 0.
+	if err := repo.Upsert(ctx, reader, channel, m1); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	unreads, err = repo.GetUnreadCounts(ctx, reader, server)
+	if err != nil {
+		t.Fatalf("get unread counts after read: %v", err)
+	}
+	if len(unreads) != 1 || unreads[0].MentionCount != 1 {
+		t.Fatalf("expected 1 mention past the watermark, got %+v", unreads)
+	}
+
+	// Deleting the mentioning message takes its mention rows with it.
+	if err := messages.Delete(ctx, m3); err != nil {
+		t.Fatalf("delete message: %v", err)
+	}
+	unreads, err = repo.GetUnreadCounts(ctx, reader, server)
+	if err != nil {
+		t.Fatalf("get unread counts after delete: %v", err)
+	}
+	if len(unreads) != 0 {
+		t.Fatalf("expected no rows after the mention was deleted, got %+v", unreads)
+	}
+}
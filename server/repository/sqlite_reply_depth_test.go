@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// The reply-depth walk backs the service-side chain cap. It must count the
+// chain from its tip and, crucially, terminate on a cyclic reply_to_id graph
+// (unreachable through the API, but exactly what the cap defends against).
+func TestReplyChainDepth(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteMessageRepo(f.DB)
+	ctx := context.Background()
+
+	channelID := f.Channel("", "")
+	root := f.Message(dbtest.MessageSeed{ChannelID: channelID, Content: dbtest.Ptr("root")})
+	mid := f.Message(dbtest.MessageSeed{ChannelID: channelID, Content: dbtest.Ptr("mid")})
+	tip := f.Message(dbtest.MessageSeed{ChannelID: channelID, Content: dbtest.Ptr("tip")})
+	for id, replyTo := range map[string]string{mid: root, tip: mid} {
+		if _, err := f.DB.ExecContext(ctx,
+			`UPDATE messages SET reply_to_id = ? WHERE id = ?`, replyTo, id); err != nil {
+			t.Fatalf("link reply: %v", err)
+		}
+	}
+
+	depth, err := repo.ReplyChainDepth(ctx, root, 20)
+	if err != nil {
+		t.Fatalf("depth of root: %v", err)
+	}
+	if depth != 1 {
+		t.Fatalf("message with no reply reference should have depth 1, got %d", depth)
+	}
+
+	depth, err = repo.ReplyChainDepth(ctx, tip, 20)
+	if err != nil {
+		t.Fatalf("depth of tip: %v", err)
+	}
+	if depth != 3 {
+		t.Fatalf("three-message chain should have depth 3, got %d", depth)
+	}
+
+	// A missing id reads as depth 0 — the caller has already resolved the
+	// referenced message, so this only matters for not erroring.
+	depth, err = repo.ReplyChainDepth(ctx, "no-such-message", 20)
+	if err != nil {
+		t.Fatalf("depth of missing message: %v", err)
+	}
+	if depth != 0 {
+		t.Fatalf("missing message should read as depth 0, got %d", depth)
+	}
+
+	// Close the chain into a cycle: the walk must stop at maxDepth instead of
+	// recursing forever, and the saturated value is what the service rejects.
+	if _, err := f.DB.ExecContext(ctx,
+		`UPDATE messages SET reply_to_id = ? WHERE id = ?`, tip, root); err != nil {
+		t.Fatalf("create cycle: %v", err)
+	}
+	depth, err = repo.ReplyChainDepth(ctx, tip, 20)
+	if err != nil {
+		t.Fatalf("depth of cyclic chain: %v", err)
+	}
+	if depth != 20 {
+		t.Fatalf("cyclic chain should saturate at maxDepth 20, got %d", depth)
+	}
+}
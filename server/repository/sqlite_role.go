@@ -126,6 +126,51 @@ func (r *sqliteRoleRepo) GetByUserIDAndServer(ctx context.Context, userID, serve
 	return roles, nil
 }
 
+// GetByServerForUsers — see RoleRepository.GetByServerForUsers.
+func (r *sqliteRoleRepo) GetByServerForUsers(ctx context.Context, serverID string, userIDs []string) (map[string][]models.Role, error) {
+	byUser := make(map[string][]models.Role, len(userIDs))
+	if len(userIDs) == 0 {
+		return byUser, nil
+	}
+
+	query := `
+		SELECT ur.user_id, r.id, r.server_id, r.name, r.color, r.position, r.permissions, r.is_default, r.is_owner, r.mentionable, r.created_at
+		FROM roles r
+		INNER JOIN user_roles ur ON r.id = ur.role_id AND r.server_id = ur.server_id
+		WHERE ur.server_id = ? AND ur.user_id IN (` + sqlPlaceholders(len(userIDs)) + `)
+		ORDER BY r.position DESC`
+
+	args := make([]any, 0, len(userIDs)+1)
+	args = append(args, serverID)
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles for users: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var role models.Role
+		if err := rows.Scan(
+			&userID, &role.ID, &role.ServerID, &role.Name, &role.Color, &role.Position,
+			&role.Permissions, &role.IsDefault, &role.IsOwner, &role.Mentionable, &role.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan role row: %w", err)
+		}
+		byUser[userID] = append(byUser[userID], role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating role rows: %w", err)
+	}
+
+	return byUser, nil
+}
+
 func (r *sqliteRoleRepo) GetMaxPosition(ctx context.Context, serverID string) (int, error) {
 	var maxPos int
 	err := r.db.QueryRowContext(ctx,
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+type sqliteScheduledMessageRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteScheduledMessageRepo(db database.TxQuerier) ScheduledMessageRepository {
+	return &sqliteScheduledMessageRepo{db: db}
+}
+
+func (r *sqliteScheduledMessageRepo) Create(ctx context.Context, msg *models.ScheduledMessage) error {
+	query := `
+		INSERT INTO scheduled_messages (id, channel_id, user_id, content, send_at)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		msg.ID, msg.ChannelID, msg.UserID, msg.Content, msg.SendAt.UTC(),
+	).Scan(&msg.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteScheduledMessageRepo) GetByID(ctx context.Context, id string) (*models.ScheduledMessage, error) {
+	query := `
+		SELECT id, channel_id, user_id, content, send_at, created_at
+		FROM scheduled_messages WHERE id = ?`
+
+	msg := &models.ScheduledMessage{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Content, &msg.SendAt, &msg.CreatedAt,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, pkg.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled message: %w", err)
+	}
+
+	return msg, nil
+}
+
+func (r *sqliteScheduledMessageRepo) GetPendingByChannelAndUser(ctx context.Context, channelID, userID string) ([]models.ScheduledMessage, error) {
+	query := `
+		SELECT id, channel_id, user_id, content, send_at, created_at
+		FROM scheduled_messages
+		WHERE channel_id = ? AND user_id = ?
+		ORDER BY send_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, channelID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanScheduledMessages(rows)
+}
+
+func (r *sqliteScheduledMessageRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]models.ScheduledMessage, error) {
+	query := `
+		SELECT id, channel_id, user_id, content, send_at, created_at
+		FROM scheduled_messages
+		WHERE send_at <= ?
+		ORDER BY send_at ASC
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled messages: %w", err)
+	}
+	defer rows.Close()
+
+	return scanScheduledMessages(rows)
+}
+
+func (r *sqliteScheduledMessageRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM scheduled_messages WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduled message: %w", err)
+	}
+	return nil
+}
+
+func scanScheduledMessages(rows *sql.Rows) ([]models.ScheduledMessage, error) {
+	var messages []models.ScheduledMessage
+	for rows.Next() {
+		var msg models.ScheduledMessage
+		if err := rows.Scan(
+			&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Content, &msg.SendAt, &msg.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
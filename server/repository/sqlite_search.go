@@ -19,7 +19,7 @@ func NewSQLiteSearchRepo(db database.TxQuerier) SearchRepository {
 }
 
 // Search performs FTS5 full-text search with BM25 ranking.
-func (r *sqliteSearchRepo) Search(ctx context.Context, query string, serverID string, channelID *string, limit, offset int) (*SearchResult, error) {
+func (r *sqliteSearchRepo) Search(ctx context.Context, query string, serverID string, filter SearchFilter, limit, offset int) (*SearchResult, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 25
 	}
@@ -31,28 +31,57 @@ func (r *sqliteSearchRepo) Search(ctx context.Context, query string, serverID st
 	if safeQuery == "" {
 		return &SearchResult{Messages: []models.Message{}, TotalCount: 0}, nil
 	}
+	// A non-nil empty visible set means the caller can see no channels at all.
+	if filter.VisibleChannelIDs != nil && len(filter.VisibleChannelIDs) == 0 {
+		return &SearchResult{Messages: []models.Message{}, TotalCount: 0}, nil
+	}
 
-	var countQuery string
-	var countArgs []any
-
-	if channelID != nil {
-		countQuery = `
-			SELECT COUNT(*)
-			FROM messages_fts fts
-			JOIN messages m ON m.rowid = fts.rowid
-			JOIN channels ch ON ch.id = m.channel_id
-			WHERE messages_fts MATCH ? AND ch.server_id = ? AND m.channel_id = ?`
-		countArgs = []any{safeQuery, serverID, *channelID}
-	} else {
-		countQuery = `
-			SELECT COUNT(*)
-			FROM messages_fts fts
-			JOIN messages m ON m.rowid = fts.rowid
-			JOIN channels ch ON ch.id = m.channel_id
-			WHERE messages_fts MATCH ? AND ch.server_id = ?`
-		countArgs = []any{safeQuery, serverID}
+	// A message matches when its content matches OR one of its attachments' filenames
+	// matches. Both FTS hits carry a BM25 rank; the best (most negative) one orders the
+	// merged result, so a strong filename hit ranks like a strong content hit.
+	const matchCTE = `
+		WITH matches(mrowid, rank) AS (
+			SELECT rowid, rank FROM messages_fts WHERE messages_fts MATCH ?
+			UNION ALL
+			SELECT msg.rowid, fa.rank
+			FROM attachments_fts fa
+			JOIN attachments a ON a.rowid = fa.rowid
+			JOIN messages msg ON msg.id = a.message_id
+			WHERE attachments_fts MATCH ?
+		),
+		best(mrowid, rank) AS (
+			SELECT mrowid, MIN(rank) FROM matches GROUP BY mrowid
+		)`
+
+	resultFilter := ""
+	filterArgs := []any{serverID}
+	if filter.ChannelID != nil {
+		resultFilter = " AND m.channel_id = ?"
+		filterArgs = append(filterArgs, *filter.ChannelID)
+	}
+	if len(filter.VisibleChannelIDs) > 0 {
+		placeholders := strings.Repeat("?,", len(filter.VisibleChannelIDs)-1) + "?"
+		resultFilter += " AND m.channel_id IN (" + placeholders + ")"
+		for _, id := range filter.VisibleChannelIDs {
+			filterArgs = append(filterArgs, id)
+		}
+	}
+	if filter.FromUsername != nil {
+		resultFilter += " AND m.user_id IN (SELECT id FROM users WHERE username = ? COLLATE NOCASE)"
+		filterArgs = append(filterArgs, *filter.FromUsername)
+	}
+	if filter.HasAttachment {
+		resultFilter += " AND EXISTS (SELECT 1 FROM attachments fat WHERE fat.message_id = m.id)"
 	}
 
+	countQuery := matchCTE + `
+		SELECT COUNT(*)
+		FROM best
+		JOIN messages m ON m.rowid = best.mrowid
+		JOIN channels ch ON ch.id = m.channel_id
+		WHERE ch.server_id = ?` + resultFilter
+	countArgs := append([]any{safeQuery, safeQuery}, filterArgs...)
+
 	var totalCount int
 	if err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount); err != nil {
 		return nil, fmt.Errorf("failed to count search results: %w", err)
@@ -62,34 +91,18 @@ func (r *sqliteSearchRepo) Search(ctx context.Context, query string, serverID st
 		return &SearchResult{Messages: []models.Message{}, TotalCount: 0}, nil
 	}
 
-	var dataQuery string
-	var dataArgs []any
-
-	if channelID != nil {
-		dataQuery = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.edited_at, m.created_at,
-			       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted
-			FROM messages_fts fts
-			JOIN messages m ON m.rowid = fts.rowid
-			JOIN channels ch ON ch.id = m.channel_id
-			LEFT JOIN users u ON m.user_id = u.id
-			WHERE messages_fts MATCH ? AND ch.server_id = ? AND m.channel_id = ?
-			ORDER BY fts.rank
-			LIMIT ? OFFSET ?`
-		dataArgs = []any{safeQuery, serverID, *channelID, limit, offset}
-	} else {
-		dataQuery = `
-			SELECT m.id, m.channel_id, m.user_id, m.content, m.edited_at, m.created_at,
-			       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted
-			FROM messages_fts fts
-			JOIN messages m ON m.rowid = fts.rowid
-			JOIN channels ch ON ch.id = m.channel_id
-			LEFT JOIN users u ON m.user_id = u.id
-			WHERE messages_fts MATCH ? AND ch.server_id = ?
-			ORDER BY fts.rank
-			LIMIT ? OFFSET ?`
-		dataArgs = []any{safeQuery, serverID, limit, offset}
-	}
+	dataQuery := matchCTE + `
+		SELECT m.id, m.channel_id, m.user_id, m.type, m.content, m.edited_at, m.created_at,
+		       u.id, u.username, u.display_name, u.avatar_url, u.status, u.deleted_at, u.is_hard_deleted
+		FROM best
+		JOIN messages m ON m.rowid = best.mrowid
+		JOIN channels ch ON ch.id = m.channel_id
+		LEFT JOIN users u ON m.user_id = u.id
+		WHERE ch.server_id = ?` + resultFilter + `
+		ORDER BY best.rank
+		LIMIT ? OFFSET ?`
+	dataArgs := append([]any{safeQuery, safeQuery}, filterArgs...)
+	dataArgs = append(dataArgs, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, dataQuery, dataArgs...)
 	if err != nil {
@@ -104,7 +117,7 @@ func (r *sqliteSearchRepo) Search(ctx context.Context, query string, serverID st
 		var authorID sql.NullString
 
 		if err := rows.Scan(
-			&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Content, &msg.EditedAt, &msg.CreatedAt,
+			&msg.ID, &msg.ChannelID, &msg.UserID, &msg.Type, &msg.Content, &msg.EditedAt, &msg.CreatedAt,
 			&authorID, &author.Username, &author.DisplayName, &author.AvatarURL, &author.Status, &author.DeletedAt, &author.IsHardDeleted,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan search result row: %w", err)
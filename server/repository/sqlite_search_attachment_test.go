@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// Migration 091 indexes attachment filenames next to message content, so a filename query
+// finds the carrying message even when its text never mentions the file. The trigger pair
+// and the search repo's merged match are one mechanism — this drives both through the repos.
+func TestSearch_FindsMessagesByAttachmentFilename(t *testing.T) {
+	f := dbtest.New(t)
+	attachmentRepo := NewSQLiteAttachmentRepo(f.DB)
+	searchRepo := NewSQLiteSearchRepo(f.DB)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	channelID := f.Channel("", serverID)
+	msgID := f.Message(dbtest.MessageSeed{ChannelID: channelID, Content: dbtest.Ptr("here you go")})
+
+	att := &models.Attachment{
+		MessageID: msgID,
+		Filename:  "quarterly-report.pdf",
+		FileURL:   "/api/files/messages/c1/quarterly-report.pdf",
+	}
+	if err := attachmentRepo.Create(ctx, att); err != nil {
+		t.Fatalf("create attachment: %v", err)
+	}
+
+	res, err := searchRepo.Search(ctx, "quarterly", serverID, SearchFilter{}, 25, 0)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if res.TotalCount != 1 || len(res.Messages) != 1 || res.Messages[0].ID != msgID {
+		t.Fatalf("filename query should find the carrying message, got count=%d messages=%v", res.TotalCount, res.Messages)
+	}
+
+	// Content matches must keep working through the merged query.
+	res, err = searchRepo.Search(ctx, "here you", serverID, SearchFilter{}, 25, 0)
+	if err != nil {
+		t.Fatalf("search by content: %v", err)
+	}
+	if res.TotalCount != 1 {
+		t.Fatalf("content query should still match, got count=%d", res.TotalCount)
+	}
+
+	// A message matching on both content and filename is one result, not two.
+	if _, err := f.DB.ExecContext(ctx,
+		`UPDATE messages SET content = 'the quarterly numbers' WHERE id = ?`, msgID); err != nil {
+		t.Fatalf("update content: %v", err)
+	}
+	res, err = searchRepo.Search(ctx, "quarterly", serverID, SearchFilter{}, 25, 0)
+	if err != nil {
+		t.Fatalf("search after overlap: %v", err)
+	}
+	if res.TotalCount != 1 || len(res.Messages) != 1 {
+		t.Fatalf("overlapping content+filename match must dedupe to one result, got count=%d len=%d", res.TotalCount, len(res.Messages))
+	}
+
+	// Deleting the attachment removes the filename from the index (trigger attachments_ad).
+	if err := attachmentRepo.Delete(ctx, att.ID); err != nil {
+		t.Fatalf("delete attachment: %v", err)
+	}
+	res, err = searchRepo.Search(ctx, "report.pdf", serverID, SearchFilter{}, 25, 0)
+	if err != nil {
+		t.Fatalf("search after delete: %v", err)
+	}
+	if res.TotalCount != 0 {
+		t.Fatalf("deleted attachment's filename must leave the index, got count=%d", res.TotalCount)
+	}
+}
@@ -5,9 +5,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/akinalp/mqvi/database"
 	"github.com/akinalp/mqvi/models"
@@ -44,18 +46,20 @@ func (r *sqliteServerRepo) Create(ctx context.Context, server *models.Server) er
 
 func (r *sqliteServerRepo) GetByID(ctx context.Context, serverID string) (*models.Server, error) {
 	query := `
-		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, livekit_instance_id, afk_timeout_minutes,
+		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, disallow_self_reactions, livekit_instance_id, afk_timeout_minutes, message_retention_days,
 			deleted_at, deleted_by, deleted_by_admin, created_at,
-			description, banner_url, category, verified, featured
+			description, banner_url, category, verified, featured,
+			upload_max_size, upload_allowed_types, upload_blocked_types
 		FROM servers WHERE id = ?`
 
 	s := &models.Server{}
 	err := r.db.QueryRowContext(ctx, query, serverID).Scan(
 		&s.ID, &s.Name, &s.IconURL, &s.OwnerID,
-		&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes,
+		&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.DisallowSelfReactions, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes, &s.MessageRetentionDays,
 		&s.DeletedAt, &s.DeletedBy, &s.DeletedByAdmin,
 		&s.CreatedAt,
 		&s.Description, &s.BannerURL, &s.Category, &s.Verified, &s.Featured,
+		&s.UploadMaxSize, &s.UploadAllowedTypes, &s.UploadBlockedTypes,
 	)
 
 	if errors.Is(err, sql.ErrNoRows) {
@@ -70,12 +74,14 @@ func (r *sqliteServerRepo) GetByID(ctx context.Context, serverID string) (*model
 
 func (r *sqliteServerRepo) Update(ctx context.Context, server *models.Server) error {
 	query := `
-		UPDATE servers SET name = ?, icon_url = ?, banner_url = ?, is_public = ?, e2ee_enabled = ?, approval_required = ?, description = ?, category = ?, livekit_instance_id = ?, afk_timeout_minutes = ?
+		UPDATE servers SET name = ?, icon_url = ?, banner_url = ?, is_public = ?, e2ee_enabled = ?, approval_required = ?, disallow_self_reactions = ?, description = ?, category = ?, livekit_instance_id = ?, afk_timeout_minutes = ?, message_retention_days = ?,
+			upload_max_size = ?, upload_allowed_types = ?, upload_blocked_types = ?
 		WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query,
 		server.Name, server.IconURL, server.BannerURL, server.IsPublic,
-		server.E2EEEnabled, server.ApprovalRequired, server.Description, server.Category, server.LiveKitInstanceID, server.AFKTimeoutMinutes, server.ID,
+		server.E2EEEnabled, server.ApprovalRequired, server.DisallowSelfReactions, server.Description, server.Category, server.LiveKitInstanceID, server.AFKTimeoutMinutes, server.MessageRetentionDays,
+		server.UploadMaxSize, server.UploadAllowedTypes, server.UploadBlockedTypes, server.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update server: %w", err)
@@ -156,18 +162,20 @@ func (r *sqliteServerRepo) IsE2EEEnabled(ctx context.Context, serverID string) (
 
 func (r *sqliteServerRepo) GetActiveByID(ctx context.Context, serverID string) (*models.Server, error) {
 	query := `
-		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, livekit_instance_id, afk_timeout_minutes,
+		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, disallow_self_reactions, livekit_instance_id, afk_timeout_minutes, message_retention_days,
 			deleted_at, deleted_by, deleted_by_admin, created_at,
-			description, banner_url, category, verified, featured
+			description, banner_url, category, verified, featured,
+			upload_max_size, upload_allowed_types, upload_blocked_types
 		FROM servers WHERE id = ? AND deleted_at IS NULL`
 
 	s := &models.Server{}
 	err := r.db.QueryRowContext(ctx, query, serverID).Scan(
 		&s.ID, &s.Name, &s.IconURL, &s.OwnerID,
-		&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes,
+		&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.DisallowSelfReactions, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes, &s.MessageRetentionDays,
 		&s.DeletedAt, &s.DeletedBy, &s.DeletedByAdmin,
 		&s.CreatedAt,
 		&s.Description, &s.BannerURL, &s.Category, &s.Verified, &s.Featured,
+		&s.UploadMaxSize, &s.UploadAllowedTypes, &s.UploadBlockedTypes,
 	)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, pkg.ErrNotFound
@@ -222,9 +230,10 @@ func (r *sqliteServerRepo) Restore(ctx context.Context, serverID string) error {
 
 func (r *sqliteServerRepo) ListDeletedByOwner(ctx context.Context, ownerID string) ([]models.Server, error) {
 	query := `
-		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, livekit_instance_id, afk_timeout_minutes,
+		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, disallow_self_reactions, livekit_instance_id, afk_timeout_minutes, message_retention_days,
 			deleted_at, deleted_by, deleted_by_admin, created_at,
-			description, banner_url, category, verified, featured
+			description, banner_url, category, verified, featured,
+			upload_max_size, upload_allowed_types, upload_blocked_types
 		FROM servers WHERE owner_id = ? AND deleted_at IS NOT NULL
 		ORDER BY deleted_at DESC`
 	rows, err := r.db.QueryContext(ctx, query, ownerID)
@@ -238,10 +247,11 @@ func (r *sqliteServerRepo) ListDeletedByOwner(ctx context.Context, ownerID strin
 		var s models.Server
 		if err := rows.Scan(
 			&s.ID, &s.Name, &s.IconURL, &s.OwnerID,
-			&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes,
+			&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.DisallowSelfReactions, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes, &s.MessageRetentionDays,
 			&s.DeletedAt, &s.DeletedBy, &s.DeletedByAdmin,
 			&s.CreatedAt,
 			&s.Description, &s.BannerURL, &s.Category, &s.Verified, &s.Featured,
+			&s.UploadMaxSize, &s.UploadAllowedTypes, &s.UploadBlockedTypes,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan deleted server: %w", err)
 		}
@@ -270,11 +280,21 @@ func (r *sqliteServerRepo) ListActiveServerIDsByOwner(ctx context.Context, owner
 	return ids, rows.Err()
 }
 
+func (r *sqliteServerRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM servers WHERE deleted_at IS NULL`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count servers: %w", err)
+	}
+	return count, nil
+}
+
 func (r *sqliteServerRepo) ListSoftDeletedExpired(ctx context.Context, ttlDays int) ([]models.Server, error) {
 	query := `
-		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, livekit_instance_id, afk_timeout_minutes,
+		SELECT id, name, icon_url, owner_id, is_public, e2ee_enabled, approval_required, disallow_self_reactions, livekit_instance_id, afk_timeout_minutes, message_retention_days,
 			deleted_at, deleted_by, deleted_by_admin, created_at,
-			description, banner_url, category, verified, featured
+			description, banner_url, category, verified, featured,
+			upload_max_size, upload_allowed_types, upload_blocked_types
 		FROM servers
 		WHERE deleted_at IS NOT NULL
 		  AND deleted_at < datetime('now', ?)
@@ -290,10 +310,11 @@ func (r *sqliteServerRepo) ListSoftDeletedExpired(ctx context.Context, ttlDays i
 		var s models.Server
 		if err := rows.Scan(
 			&s.ID, &s.Name, &s.IconURL, &s.OwnerID,
-			&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes,
+			&s.IsPublic, &s.E2EEEnabled, &s.ApprovalRequired, &s.DisallowSelfReactions, &s.LiveKitInstanceID, &s.AFKTimeoutMinutes, &s.MessageRetentionDays,
 			&s.DeletedAt, &s.DeletedBy, &s.DeletedByAdmin,
 			&s.CreatedAt,
 			&s.Description, &s.BannerURL, &s.Category, &s.Verified, &s.Featured,
+			&s.UploadMaxSize, &s.UploadAllowedTypes, &s.UploadBlockedTypes,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan expired server: %w", err)
 		}
@@ -338,11 +359,14 @@ func (r *sqliteServerRepo) GetUserServers(ctx context.Context, userID string) ([
 
 func (r *sqliteServerRepo) AddMember(ctx context.Context, serverID, userID string) error {
 	// New member appended at end: position = max + 1 (atomic via subquery).
+	// rules_accepted starts at 0 only when the server's gate is on at join time —
+	// existing members keep their grandfathered 1 (migration default).
 	query := `
-		INSERT OR IGNORE INTO server_members (server_id, user_id, position)
-		VALUES (?, ?, COALESCE((SELECT MAX(position) FROM server_members WHERE user_id = ?), -1) + 1)`
+		INSERT OR IGNORE INTO server_members (server_id, user_id, position, rules_accepted)
+		VALUES (?, ?, COALESCE((SELECT MAX(position) FROM server_members WHERE user_id = ?), -1) + 1,
+		        CASE WHEN (SELECT rules_required FROM servers WHERE id = ?) = 1 THEN 0 ELSE 1 END)`
 
-	_, err := r.db.ExecContext(ctx, query, serverID, userID, userID)
+	_, err := r.db.ExecContext(ctx, query, serverID, userID, userID, serverID)
 	if err != nil {
 		return fmt.Errorf("failed to add server member: %w", err)
 	}
@@ -350,6 +374,91 @@ func (r *sqliteServerRepo) AddMember(ctx context.Context, serverID, userID strin
 	return nil
 }
 
+// ─── Welcome screen / rules gate ───
+
+func (r *sqliteServerRepo) GetWelcomeConfig(ctx context.Context, serverID string) (*models.WelcomeConfig, error) {
+	query := `
+		SELECT welcome_description, welcome_channel_ids, rules_text, rules_required
+		FROM servers WHERE id = ? AND deleted_at IS NULL`
+
+	var cfg models.WelcomeConfig
+	var channelIDs sql.NullString
+	err := r.db.QueryRowContext(ctx, query, serverID).Scan(
+		&cfg.Description, &channelIDs, &cfg.RulesText, &cfg.RulesRequired,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: server not found", pkg.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get welcome config: %w", err)
+	}
+
+	cfg.SuggestedChannelIDs = []string{}
+	if channelIDs.Valid && channelIDs.String != "" {
+		if err := json.Unmarshal([]byte(channelIDs.String), &cfg.SuggestedChannelIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode suggested channel ids: %w", err)
+		}
+	}
+	return &cfg, nil
+}
+
+func (r *sqliteServerRepo) UpdateWelcomeConfig(ctx context.Context, serverID string, cfg *models.WelcomeConfig) error {
+	channelIDs, err := json.Marshal(cfg.SuggestedChannelIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode suggested channel ids: %w", err)
+	}
+
+	query := `
+		UPDATE servers
+		SET welcome_description = ?, welcome_channel_ids = ?, rules_text = ?, rules_required = ?
+		WHERE id = ? AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query,
+		cfg.Description, string(channelIDs), cfg.RulesText, cfg.RulesRequired, serverID)
+	if err != nil {
+		return fmt.Errorf("failed to update welcome config: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: server not found", pkg.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *sqliteServerRepo) SetRulesAccepted(ctx context.Context, serverID, userID string) error {
+	query := `UPDATE server_members SET rules_accepted = 1 WHERE server_id = ? AND user_id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, serverID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to set rules accepted: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("%w: membership not found", pkg.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *sqliteServerRepo) RulesPending(ctx context.Context, serverID, userID string) (bool, error) {
+	// One row only when the gate is on AND this member has not accepted. A missing
+	// membership is "not pending" — the membership check itself belongs to middleware.
+	query := `
+		SELECT 1 FROM server_members m
+		JOIN servers s ON s.id = m.server_id
+		WHERE m.server_id = ? AND m.user_id = ?
+		  AND s.rules_required = 1 AND m.rules_accepted = 0`
+
+	var one int
+	err := r.db.QueryRowContext(ctx, query, serverID, userID).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check rules gate: %w", err)
+	}
+	return true, nil
+}
+
 func (r *sqliteServerRepo) RemoveMember(ctx context.Context, serverID, userID string) error {
 	result, err := r.db.ExecContext(ctx,
 		`DELETE FROM server_members WHERE server_id = ? AND user_id = ?`,
@@ -394,6 +503,347 @@ func (r *sqliteServerRepo) IsMember(ctx context.Context, serverID, userID string
 	return true, nil
 }
 
+func (r *sqliteServerRepo) HaveSharedServer(ctx context.Context, userA, userB string) (bool, error) {
+	query := `
+		SELECT 1 FROM server_members a
+		JOIN server_members b ON b.server_id = a.server_id
+		JOIN servers s ON s.id = a.server_id AND s.deleted_at IS NULL
+		WHERE a.user_id = ? AND b.user_id = ?
+		LIMIT 1`
+
+	var dummy int
+	err := r.db.QueryRowContext(ctx, query, userA, userB).Scan(&dummy)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check shared server membership: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListMemberPage — see ServerRepository.ListMemberPage. The cursor is resolved
+// server-side from the After user ID; keyset over (joined_at, user_id) keeps
+// the order stable while members join or leave between pages.
+func (r *sqliteServerRepo) ListMemberPage(ctx context.Context, serverID string, params models.MemberListParams) ([]models.MemberPageRow, int, error) {
+	where := `sm.server_id = ? AND u.deleted_at IS NULL`
+	args := []any{serverID}
+
+	if q := strings.TrimSpace(params.Q); q != "" {
+		like := "%" + q + "%"
+		where += ` AND (u.username LIKE ? COLLATE NOCASE OR COALESCE(u.display_name, '') LIKE ? COLLATE NOCASE)`
+		args = append(args, like, like)
+	}
+	if params.RoleID != "" {
+		where += ` AND EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = u.id AND ur.server_id = sm.server_id AND ur.role_id = ?)`
+		args = append(args, params.RoleID)
+	}
+
+	// Total under the same filters, without the cursor — constant across pages.
+	var total int
+	countQuery := `SELECT COUNT(*) FROM server_members sm INNER JOIN users u ON u.id = sm.user_id WHERE ` + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	if params.After != "" {
+		where += ` AND (sm.joined_at, sm.user_id) > (SELECT joined_at, user_id FROM server_members WHERE server_id = ? AND user_id = ?)`
+		args = append(args, serverID, params.After)
+	}
+
+	query := `
+		SELECT u.id, u.username, u.display_name, u.avatar_url, u.status, u.custom_status,
+		       u.created_at, u.deleted_at, u.is_hard_deleted, sm.timeout_until, sm.nickname
+		FROM server_members sm
+		INNER JOIN users u ON u.id = sm.user_id
+		WHERE ` + where + `
+		ORDER BY sm.joined_at ASC, sm.user_id ASC
+		LIMIT ?`
+	args = append(args, params.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list member page: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.MemberPageRow{}
+	for rows.Next() {
+		var row models.MemberPageRow
+		if err := rows.Scan(
+			&row.User.ID, &row.User.Username, &row.User.DisplayName, &row.User.AvatarURL,
+			&row.User.Status, &row.User.CustomStatus, &row.User.CreatedAt,
+			&row.User.DeletedAt, &row.User.IsHardDeleted, &row.TimeoutUntil, &row.Nickname,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan member row: %w", err)
+		}
+		members = append(members, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating member rows: %w", err)
+	}
+
+	return members, total, nil
+}
+
+func (r *sqliteServerRepo) SetMemberTimeout(ctx context.Context, serverID, userID string, until *time.Time) error {
+	var value any
+	if until != nil {
+		value = until.UTC()
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE server_members SET timeout_until = ? WHERE server_id = ? AND user_id = ?`,
+		value, serverID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to set member timeout: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: user is not a member of this server", pkg.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *sqliteServerRepo) GetMemberTimeout(ctx context.Context, serverID, userID string) (*time.Time, error) {
+	query := `SELECT timeout_until FROM server_members WHERE server_id = ? AND user_id = ?`
+
+	var until *time.Time
+	err := r.db.QueryRowContext(ctx, query, serverID, userID).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member timeout: %w", err)
+	}
+	return until, nil
+}
+
+func (r *sqliteServerRepo) SetMemberNickname(ctx context.Context, serverID, userID string, nickname *string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE server_members SET nickname = ? WHERE server_id = ? AND user_id = ?`,
+		nickname, serverID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to set member nickname: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: user is not a member of this server", pkg.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *sqliteServerRepo) GetMemberNickname(ctx context.Context, serverID, userID string) (*string, error) {
+	query := `SELECT nickname FROM server_members WHERE server_id = ? AND user_id = ?`
+
+	var nickname *string
+	err := r.db.QueryRowContext(ctx, query, serverID, userID).Scan(&nickname)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member nickname: %w", err)
+	}
+	return nickname, nil
+}
+
+func (r *sqliteServerRepo) ClearExpiredTimeouts(ctx context.Context, now time.Time) ([]ExpiredTimeout, error) {
+	// Select-then-clear instead of UPDATE..RETURNING; a timeout set between the
+	// two statements has a later expiry and survives the WHERE either way.
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT server_id, user_id FROM server_members WHERE timeout_until IS NOT NULL AND timeout_until <= ?`,
+		now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired timeouts: %w", err)
+	}
+	defer rows.Close()
+
+	var expired []ExpiredTimeout
+	for rows.Next() {
+		var e ExpiredTimeout
+		if err := rows.Scan(&e.ServerID, &e.UserID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired timeout: %w", err)
+		}
+		expired = append(expired, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired timeouts: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE server_members SET timeout_until = NULL WHERE timeout_until IS NOT NULL AND timeout_until <= ?`,
+		now.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to clear expired timeouts: %w", err)
+	}
+	return expired, nil
+}
+
+func (r *sqliteServerRepo) MarkMemberTemporary(ctx context.Context, serverID, userID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE server_members SET is_temporary = 1 WHERE server_id = ? AND user_id = ?`,
+		serverID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark member temporary: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: user is not a member of this server", pkg.ErrNotFound)
+	}
+	return nil
+}
+
+// ListRemovableTemporaryMemberships keeps the role check in SQL so the decision and the
+// read are one snapshot: a temporary member holding ANY non-default role (granted after
+// joining) is excluded and keeps the membership.
+func (r *sqliteServerRepo) ListRemovableTemporaryMemberships(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		SELECT sm.server_id
+		FROM server_members sm
+		WHERE sm.user_id = ? AND sm.is_temporary = 1
+		  AND NOT EXISTS (
+			SELECT 1 FROM user_roles ur
+			INNER JOIN roles ro ON ro.id = ur.role_id AND ro.server_id = ur.server_id
+			WHERE ur.user_id = sm.user_id AND ur.server_id = sm.server_id AND ro.is_default = 0
+		  )`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list temporary memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var serverIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan temporary membership: %w", err)
+		}
+		serverIDs = append(serverIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating temporary memberships: %w", err)
+	}
+	return serverIDs, nil
+}
+
+func (r *sqliteServerRepo) SetVanityCode(ctx context.Context, serverID string, code *string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE servers SET vanity_code = ? WHERE id = ? AND deleted_at IS NULL`,
+		code, serverID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: vanity code is already taken by another server", pkg.ErrConflict)
+		}
+		return fmt.Errorf("failed to set vanity code: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return pkg.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sqliteServerRepo) GetVanityCode(ctx context.Context, serverID string) (*string, error) {
+	var code *string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT vanity_code FROM servers WHERE id = ?`, serverID).Scan(&code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vanity code: %w", err)
+	}
+	return code, nil
+}
+
+// TransferOwnership reassigns owner_id and moves the owner-role assignment to the new
+// owner in one transaction. Memberships and default-role rows are untouched, so the old
+// owner is left as an ordinary member.
+func (r *sqliteServerRepo) TransferOwnership(ctx context.Context, serverID, oldOwnerID, newOwnerID string) error {
+	sqlDB, ok := r.db.(*sql.DB)
+	if !ok {
+		return fmt.Errorf("TransferOwnership requires *sql.DB to start transaction")
+	}
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE servers SET owner_id = ? WHERE id = ? AND owner_id = ? AND deleted_at IS NULL`,
+		newOwnerID, serverID, oldOwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to reassign owner: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		// The service verified ownership before calling; losing it here means a
+		// concurrent transfer (or delete) got there first.
+		return pkg.ErrConflict
+	}
+
+	var ownerRoleID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM roles WHERE server_id = ? AND is_owner = 1`, serverID).Scan(&ownerRoleID)
+	if err != nil {
+		return fmt.Errorf("failed to find owner role: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM user_roles WHERE server_id = ? AND role_id = ?`,
+		serverID, ownerRoleID); err != nil {
+		return fmt.Errorf("failed to unassign owner role: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO user_roles (user_id, role_id, server_id) VALUES (?, ?, ?)`,
+		newOwnerID, ownerRoleID, serverID); err != nil {
+		return fmt.Errorf("failed to assign owner role: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ownership transfer: %w", err)
+	}
+	return nil
+}
+
+// GetServerIDByVanityCode only matches active servers: a soft-deleted server keeps its
+// slug reserved (restore brings it back) but must not be joinable through it.
+func (r *sqliteServerRepo) GetServerIDByVanityCode(ctx context.Context, code string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id FROM servers WHERE vanity_code = ? AND deleted_at IS NULL`, code).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", pkg.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve vanity code: %w", err)
+	}
+	return id, nil
+}
+
 func (r *sqliteServerRepo) GetMemberCount(ctx context.Context, serverID string) (int, error) {
 	query := `SELECT COUNT(*) FROM server_members WHERE server_id = ?`
 
@@ -526,7 +976,7 @@ func serverTypeClause(types []string) string {
 
 // buildAdminServerFilter — WHERE fragment shared by data and count queries.
 // Filters combine with AND across dimensions, OR within a dimension.
-func buildAdminServerFilter(statuses, types []string, search string) (string, []any) {
+func buildAdminServerFilter(statuses, types []string, search, instanceID string) (string, []any) {
 	var clauses []string
 	var args []any
 
@@ -536,6 +986,10 @@ func buildAdminServerFilter(statuses, types []string, search string) (string, []
 	if c := serverTypeClause(types); c != "" {
 		clauses = append(clauses, c)
 	}
+	if id := strings.TrimSpace(instanceID); id != "" {
+		clauses = append(clauses, "s.livekit_instance_id = ?")
+		args = append(args, id)
+	}
 
 	if q := strings.TrimSpace(search); q != "" {
 		like := "%" + q + "%"
@@ -568,7 +1022,7 @@ func buildVoiceServerOverlay(serverIDs []string) (cte string, override string, a
 
 // ListAdminServersPaged — see ServerRepository.ListAdminServersPaged.
 func (r *sqliteServerRepo) ListAdminServersPaged(ctx context.Context, params models.AdminListPageParams, activeVoiceServerIDs []string) (models.AdminServerListPage, error) {
-	whereSQL, whereArgs := buildAdminServerFilter(params.Statuses, params.Types, params.Search)
+	whereSQL, whereArgs := buildAdminServerFilter(params.Statuses, params.Types, params.Search, params.InstanceID)
 
 	sortExpr, ok := adminServerSortColumns[params.Sort]
 	if !ok {
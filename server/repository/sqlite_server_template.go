@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+type sqliteServerTemplateRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteServerTemplateRepo(db database.TxQuerier) ServerTemplateRepository {
+	return &sqliteServerTemplateRepo{db: db}
+}
+
+func (r *sqliteServerTemplateRepo) Create(ctx context.Context, template *models.ServerTemplate) error {
+	structure, err := json.Marshal(template.Structure)
+	if err != nil {
+		return fmt.Errorf("failed to marshal template structure: %w", err)
+	}
+
+	query := `
+		INSERT INTO server_templates (id, creator_id, name, is_public, structure)
+		VALUES (lower(hex(randomblob(8))), ?, ?, ?, ?)
+		RETURNING id, created_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		template.CreatorID, template.Name, template.IsPublic, string(structure),
+	).Scan(&template.ID, &template.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create server template: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteServerTemplateRepo) GetByID(ctx context.Context, id string) (*models.ServerTemplate, error) {
+	query := `
+		SELECT id, creator_id, name, is_public, structure, created_at
+		FROM server_templates WHERE id = ?`
+
+	template := &models.ServerTemplate{}
+	var structure string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&template.ID, &template.CreatorID, &template.Name, &template.IsPublic,
+		&structure, &template.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: template not found", pkg.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server template: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(structure), &template.Structure); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template structure: %w", err)
+	}
+
+	return template, nil
+}
+
+func (r *sqliteServerTemplateRepo) ListVisible(ctx context.Context, userID string) ([]models.ServerTemplate, error) {
+	query := `
+		SELECT id, creator_id, name, is_public, structure, created_at
+		FROM server_templates
+		WHERE is_public = 1 OR creator_id = ?
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []models.ServerTemplate
+	for rows.Next() {
+		var t models.ServerTemplate
+		var structure string
+		if err := rows.Scan(&t.ID, &t.CreatorID, &t.Name, &t.IsPublic, &structure, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan server template: %w", err)
+		}
+		if err := json.Unmarshal([]byte(structure), &t.Structure); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal template structure: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *sqliteServerTemplateRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM server_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete server template: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: template not found", pkg.ErrNotFound)
+	}
+	return nil
+}
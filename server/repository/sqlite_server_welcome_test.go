@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// Real migrations: the gate hinges on column defaults (rules_accepted DEFAULT 1) and the
+// CASE inside AddMember, neither of which exists in a hand-written schema.
+func newWelcomeTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := dbtest.New(t).DB
+	ctx := context.Background()
+	mustExec := func(q string, args ...any) {
+		t.Helper()
+		if _, err := db.ExecContext(ctx, q, args...); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	mustExec(`INSERT INTO users (id, username, password_hash) VALUES ('owner', 'owner', 'x'), ('alice', 'alice', 'x'), ('bob', 'bob', 'x')`)
+	mustExec(`INSERT INTO servers (id, name, owner_id) VALUES ('s1', 'test', 'owner')`)
+	return db
+}
+
+func TestWelcomeConfig_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := newWelcomeTestDB(t)
+	repo := NewSQLiteServerRepo(db)
+
+	desc := "welcome!"
+	rules := "be kind"
+	in := &models.WelcomeConfig{
+		Description:         &desc,
+		SuggestedChannelIDs: []string{"c1", "c2"},
+		RulesText:           &rules,
+		RulesRequired:       true,
+	}
+	if err := repo.UpdateWelcomeConfig(ctx, "s1", in); err != nil {
+		t.Fatalf("UpdateWelcomeConfig: %v", err)
+	}
+
+	out, err := repo.GetWelcomeConfig(ctx, "s1")
+	if err != nil {
+		t.Fatalf("GetWelcomeConfig: %v", err)
+	}
+	if out.Description == nil || *out.Description != desc {
+		t.Errorf("description = %v, want %q", out.Description, desc)
+	}
+	if out.RulesText == nil || *out.RulesText != rules {
+		t.Errorf("rules_text = %v, want %q", out.RulesText, rules)
+	}
+	if !out.RulesRequired {
+		t.Error("rules_required lost in round trip")
+	}
+	if len(out.SuggestedChannelIDs) != 2 || out.SuggestedChannelIDs[0] != "c1" {
+		t.Errorf("suggested_channel_ids = %v", out.SuggestedChannelIDs)
+	}
+}
+
+func TestWelcomeConfig_MissingServerIsNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLiteServerRepo(newWelcomeTestDB(t))
+
+	if _, err := repo.GetWelcomeConfig(ctx, "nope"); !errors.Is(err, pkg.ErrNotFound) {
+		t.Errorf("GetWelcomeConfig = %v, want ErrNotFound", err)
+	}
+	if err := repo.UpdateWelcomeConfig(ctx, "nope", &models.WelcomeConfig{}); !errors.Is(err, pkg.ErrNotFound) {
+		t.Errorf("UpdateWelcomeConfig = %v, want ErrNotFound", err)
+	}
+}
+
+// The gate's whole lifecycle: a member who joined before the gate existed stays free, a
+// member who joins while it is on is pending until they accept.
+func TestRulesGate_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := newWelcomeTestDB(t)
+	repo := NewSQLiteServerRepo(db)
+
+	// alice joins before the gate turns on — grandfathered.
+	if err := repo.AddMember(ctx, "s1", "alice"); err != nil {
+		t.Fatalf("AddMember(alice): %v", err)
+	}
+	rules := "be kind"
+	if err := repo.UpdateWelcomeConfig(ctx, "s1", &models.WelcomeConfig{RulesText: &rules, RulesRequired: true}); err != nil {
+		t.Fatalf("enable gate: %v", err)
+	}
+	if pending, err := repo.RulesPending(ctx, "s1", "alice"); err != nil || pending {
+		t.Errorf("alice pending = %v, %v — enabling the gate must not lock out existing members", pending, err)
+	}
+
+	// bob joins while the gate is on — pending until he accepts.
+	if err := repo.AddMember(ctx, "s1", "bob"); err != nil {
+		t.Fatalf("AddMember(bob): %v", err)
+	}
+	if pending, err := repo.RulesPending(ctx, "s1", "bob"); err != nil || !pending {
+		t.Errorf("bob pending = %v, %v, want true", pending, err)
+	}
+	if err := repo.SetRulesAccepted(ctx, "s1", "bob"); err != nil {
+		t.Fatalf("SetRulesAccepted: %v", err)
+	}
+	if pending, err := repo.RulesPending(ctx, "s1", "bob"); err != nil || pending {
+		t.Errorf("bob pending after accept = %v, %v, want false", pending, err)
+	}
+
+	// Turning the gate off frees anyone still pending without touching their flag.
+	if err := repo.AddMember(ctx, "s1", "owner"); err != nil {
+		t.Fatalf("AddMember(owner): %v", err)
+	}
+	if err := repo.UpdateWelcomeConfig(ctx, "s1", &models.WelcomeConfig{RulesRequired: false}); err != nil {
+		t.Fatalf("disable gate: %v", err)
+	}
+	if pending, err := repo.RulesPending(ctx, "s1", "owner"); err != nil || pending {
+		t.Errorf("pending with gate off = %v, %v, want false", pending, err)
+	}
+}
+
+func TestSetRulesAccepted_NoMembershipIsNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLiteServerRepo(newWelcomeTestDB(t))
+
+	if err := repo.SetRulesAccepted(ctx, "s1", "alice"); !errors.Is(err, pkg.ErrNotFound) {
+		t.Errorf("SetRulesAccepted = %v, want ErrNotFound", err)
+	}
+}
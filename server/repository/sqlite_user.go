@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/akinalp/mqvi/database"
 	"github.com/akinalp/mqvi/models"
@@ -53,18 +54,18 @@ func (r *sqliteUserRepo) Create(ctx context.Context, user *models.User) error {
 
 func (r *sqliteUserRepo) GetByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users WHERE id = ?`
 
 	user := &models.User{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.WallpaperURL,
-		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.Email,
+		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.CustomStatusEmoji, &user.CustomStatusExpiresAt, &user.Email,
 		&user.Language, &user.DMPrivacy, &user.IsPlatformAdmin, &user.IsPlatformBanned, &user.HasSeenDownloadPrompt, &user.HasSeenWelcome,
-		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt,
+		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt, &user.PlatformBannedUntil,
 		&user.DeletedAt, &user.DeletedByAdmin, &user.IsHardDeleted, &user.TokenVersion,
 		&user.FeedbackLastSeenAt, &user.ReportsLastSeenAt,
 		&user.CreatedAt,
@@ -82,18 +83,18 @@ func (r *sqliteUserRepo) GetByID(ctx context.Context, id string) (*models.User,
 
 func (r *sqliteUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users WHERE username = ? COLLATE NOCASE`
 
 	user := &models.User{}
 	err := r.db.QueryRowContext(ctx, query, username).Scan(
 		&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.WallpaperURL,
-		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.Email,
+		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.CustomStatusEmoji, &user.CustomStatusExpiresAt, &user.Email,
 		&user.Language, &user.DMPrivacy, &user.IsPlatformAdmin, &user.IsPlatformBanned, &user.HasSeenDownloadPrompt, &user.HasSeenWelcome,
-		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt,
+		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt, &user.PlatformBannedUntil,
 		&user.DeletedAt, &user.DeletedByAdmin, &user.IsHardDeleted, &user.TokenVersion,
 		&user.FeedbackLastSeenAt, &user.ReportsLastSeenAt,
 		&user.CreatedAt,
@@ -113,18 +114,18 @@ func (r *sqliteUserRepo) GetByUsername(ctx context.Context, username string) (*m
 // Used for auth paths where deleted users must be invisible.
 func (r *sqliteUserRepo) GetActiveByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users WHERE id = ? AND deleted_at IS NULL`
 
 	user := &models.User{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.WallpaperURL,
-		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.Email,
+		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.CustomStatusEmoji, &user.CustomStatusExpiresAt, &user.Email,
 		&user.Language, &user.DMPrivacy, &user.IsPlatformAdmin, &user.IsPlatformBanned, &user.HasSeenDownloadPrompt, &user.HasSeenWelcome,
-		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt,
+		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt, &user.PlatformBannedUntil,
 		&user.DeletedAt, &user.DeletedByAdmin, &user.IsHardDeleted, &user.TokenVersion,
 		&user.FeedbackLastSeenAt, &user.ReportsLastSeenAt,
 		&user.CreatedAt,
@@ -141,18 +142,18 @@ func (r *sqliteUserRepo) GetActiveByID(ctx context.Context, id string) (*models.
 // GetActiveByUsername returns the user only if not soft-deleted/tombstone.
 func (r *sqliteUserRepo) GetActiveByUsername(ctx context.Context, username string) (*models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users WHERE username = ? COLLATE NOCASE AND deleted_at IS NULL`
 
 	user := &models.User{}
 	err := r.db.QueryRowContext(ctx, query, username).Scan(
 		&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.WallpaperURL,
-		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.Email,
+		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.CustomStatusEmoji, &user.CustomStatusExpiresAt, &user.Email,
 		&user.Language, &user.DMPrivacy, &user.IsPlatformAdmin, &user.IsPlatformBanned, &user.HasSeenDownloadPrompt, &user.HasSeenWelcome,
-		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt,
+		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt, &user.PlatformBannedUntil,
 		&user.DeletedAt, &user.DeletedByAdmin, &user.IsHardDeleted, &user.TokenVersion,
 		&user.FeedbackLastSeenAt, &user.ReportsLastSeenAt,
 		&user.CreatedAt,
@@ -168,9 +169,9 @@ func (r *sqliteUserRepo) GetActiveByUsername(ctx context.Context, username strin
 
 func (r *sqliteUserRepo) GetAll(ctx context.Context) ([]models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users ORDER BY username`
 
@@ -185,9 +186,9 @@ func (r *sqliteUserRepo) GetAll(ctx context.Context) ([]models.User, error) {
 		var user models.User
 		if err := rows.Scan(
 			&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.WallpaperURL,
-			&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.Email,
+			&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.CustomStatusEmoji, &user.CustomStatusExpiresAt, &user.Email,
 			&user.Language, &user.DMPrivacy, &user.IsPlatformAdmin, &user.IsPlatformBanned, &user.HasSeenDownloadPrompt, &user.HasSeenWelcome,
-			&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt,
+			&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt, &user.PlatformBannedUntil,
 			&user.DeletedAt, &user.DeletedByAdmin, &user.IsHardDeleted, &user.TokenVersion,
 			&user.FeedbackLastSeenAt, &user.ReportsLastSeenAt,
 			&user.CreatedAt,
@@ -265,6 +266,65 @@ func (r *sqliteUserRepo) UpdatePrefStatus(ctx context.Context, userID string, pr
 	return nil
 }
 
+func (r *sqliteUserRepo) UpdateCustomStatus(ctx context.Context, userID string, text, emoji *string, expiresAt *time.Time) error {
+	query := `UPDATE users SET custom_status = ?, custom_status_emoji = ?, custom_status_expires_at = ? WHERE id = ?`
+
+	var expires any
+	if expiresAt != nil {
+		expires = expiresAt.UTC()
+	}
+	result, err := r.db.ExecContext(ctx, query, text, emoji, expires, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update custom status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *sqliteUserRepo) ClearExpiredCustomStatuses(ctx context.Context, now time.Time) ([]string, error) {
+	// Select-then-clear instead of UPDATE..RETURNING; a status set between the
+	// two statements has a later (or no) expiry and survives the WHERE either way.
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id FROM users WHERE custom_status_expires_at IS NOT NULL AND custom_status_expires_at <= ?`,
+		now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired custom statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan expired custom status: %w", err)
+		}
+		userIDs = append(userIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired custom statuses: %w", err)
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	if _, err := r.db.ExecContext(ctx,
+		`UPDATE users SET custom_status = NULL, custom_status_emoji = NULL, custom_status_expires_at = NULL
+		 WHERE custom_status_expires_at IS NOT NULL AND custom_status_expires_at <= ?`,
+		now.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to clear expired custom statuses: %w", err)
+	}
+
+	return userIDs, nil
+}
+
 func (r *sqliteUserRepo) UpdatePassword(ctx context.Context, userID, oldPasswordHash, newPasswordHash string) (int, error) {
 	if tx, ok := r.db.(*sql.Tx); ok {
 		return r.updatePasswordTx(ctx, tx, userID, oldPasswordHash, newPasswordHash)
@@ -390,18 +450,18 @@ func (r *sqliteUserRepo) UpdateWallpaper(ctx context.Context, userID string, wal
 
 func (r *sqliteUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users WHERE email = ?`
 
 	user := &models.User{}
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID, &user.Username, &user.DisplayName, &user.AvatarURL, &user.WallpaperURL,
-		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.Email,
+		&user.PasswordHash, &user.Status, &user.PrefStatus, &user.CustomStatus, &user.CustomStatusEmoji, &user.CustomStatusExpiresAt, &user.Email,
 		&user.Language, &user.DMPrivacy, &user.IsPlatformAdmin, &user.IsPlatformBanned, &user.HasSeenDownloadPrompt, &user.HasSeenWelcome,
-		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt,
+		&user.PlatformBanReason, &user.PlatformBannedBy, &user.PlatformBannedAt, &user.PlatformBannedUntil,
 		&user.DeletedAt, &user.DeletedByAdmin, &user.IsHardDeleted, &user.TokenVersion,
 		&user.FeedbackLastSeenAt, &user.ReportsLastSeenAt,
 		&user.CreatedAt,
@@ -685,16 +745,17 @@ func (r *sqliteUserRepo) UpdateLastVoiceActivity(ctx context.Context, userID str
 
 // ─── Platform Ban ───
 
-func (r *sqliteUserRepo) PlatformBan(ctx context.Context, userID, reason, bannedBy string) error {
+func (r *sqliteUserRepo) PlatformBan(ctx context.Context, userID, reason, bannedBy string, bannedUntil *time.Time) error {
 	query := `
 		UPDATE users
 		SET is_platform_banned = 1,
 			platform_ban_reason = ?,
 			platform_banned_by = ?,
-			platform_banned_at = CURRENT_TIMESTAMP
+			platform_banned_at = CURRENT_TIMESTAMP,
+			platform_banned_until = ?
 		WHERE id = ?`
 
-	result, err := r.db.ExecContext(ctx, query, reason, bannedBy, userID)
+	result, err := r.db.ExecContext(ctx, query, reason, bannedBy, bannedUntil, userID)
 	if err != nil {
 		return fmt.Errorf("failed to platform ban user: %w", err)
 	}
@@ -716,7 +777,8 @@ func (r *sqliteUserRepo) PlatformUnban(ctx context.Context, userID string) error
 		SET is_platform_banned = 0,
 			platform_ban_reason = '',
 			platform_banned_by = '',
-			platform_banned_at = NULL
+			platform_banned_at = NULL,
+			platform_banned_until = NULL
 		WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query, userID)
@@ -736,10 +798,12 @@ func (r *sqliteUserRepo) PlatformUnban(ctx context.Context, userID string) error
 }
 
 // IsEmailPlatformBanned checks the platform_bans table for a banned email.
-// Persists even after user hard-delete.
+// Persists even after user hard-delete. Expired temporary bans do not count.
 func (r *sqliteUserRepo) IsEmailPlatformBanned(ctx context.Context, email string) (bool, error) {
 	var count int
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM platform_bans WHERE email = ? COLLATE NOCASE`, email).Scan(&count)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM platform_bans WHERE email = ? COLLATE NOCASE AND (banned_until IS NULL OR banned_until > datetime('now'))`,
+		email).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check email platform ban: %w", err)
 	}
@@ -748,7 +812,9 @@ func (r *sqliteUserRepo) IsEmailPlatformBanned(ctx context.Context, email string
 
 func (r *sqliteUserRepo) IsUsernamePlatformBanned(ctx context.Context, username string) (bool, error) {
 	var count int
-	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM platform_bans WHERE username = ? COLLATE NOCASE`, username).Scan(&count)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM platform_bans WHERE username = ? COLLATE NOCASE AND (banned_until IS NULL OR banned_until > datetime('now'))`,
+		username).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check username platform ban: %w", err)
 	}
@@ -764,11 +830,20 @@ func (r *sqliteUserRepo) IsPlatformBannedByUserID(ctx context.Context, userID st
 	return count > 0, nil
 }
 
-func (r *sqliteUserRepo) InsertPlatformBan(ctx context.Context, email, username, userID, reason, bannedBy string) error {
+// InsertPlatformBan upserts on user_id: re-banning a user whose temporary ban
+// expired overwrites the stale record instead of tripping the UNIQUE constraint.
+func (r *sqliteUserRepo) InsertPlatformBan(ctx context.Context, email, username, userID, reason, bannedBy string, bannedUntil *time.Time) error {
 	id := uuid.New().String()
 	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO platform_bans (id, email, username, user_id, reason, banned_by) VALUES (?, ?, ?, ?, ?, ?)`,
-		id, email, username, userID, reason, bannedBy,
+		`INSERT INTO platform_bans (id, email, username, user_id, reason, banned_by, banned_until) VALUES (?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(user_id) DO UPDATE SET
+			email = excluded.email,
+			username = excluded.username,
+			reason = excluded.reason,
+			banned_by = excluded.banned_by,
+			banned_at = datetime('now'),
+			banned_until = excluded.banned_until`,
+		id, email, username, userID, reason, bannedBy, bannedUntil,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert platform ban: %w", err)
@@ -927,6 +1002,8 @@ func (r *sqliteUserRepo) HardDeleteUser(ctx context.Context, userID string, byAd
 				avatar_url = NULL,
 				wallpaper_url = NULL,
 				custom_status = NULL,
+				custom_status_emoji = NULL,
+				custom_status_expires_at = NULL,
 				password_hash = '',
 				status = 'offline',
 				pref_status = 'offline',
@@ -1089,9 +1166,9 @@ func (r *sqliteUserRepo) Restore(ctx context.Context, userID string) error {
 // Used by cleanup worker to identify users ready for tombstone hard-delete.
 func (r *sqliteUserRepo) ListSoftDeletedExpired(ctx context.Context, ttlDays int) ([]models.User, error) {
 	query := `
-		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status,
+		SELECT id, username, display_name, avatar_url, wallpaper_url, password_hash, status, pref_status, custom_status, custom_status_emoji, custom_status_expires_at,
 			email, language, dm_privacy, is_platform_admin, is_platform_banned, has_seen_download_prompt, has_seen_welcome,
-			platform_ban_reason, platform_banned_by, platform_banned_at,
+			platform_ban_reason, platform_banned_by, platform_banned_at, platform_banned_until,
 			deleted_at, deleted_by_admin, is_hard_deleted, token_version, feedback_last_seen_at, reports_last_seen_at, created_at
 		FROM users
 		WHERE deleted_at IS NOT NULL
@@ -1109,9 +1186,9 @@ func (r *sqliteUserRepo) ListSoftDeletedExpired(ctx context.Context, ttlDays int
 		var u models.User
 		if err := rows.Scan(
 			&u.ID, &u.Username, &u.DisplayName, &u.AvatarURL, &u.WallpaperURL,
-			&u.PasswordHash, &u.Status, &u.PrefStatus, &u.CustomStatus, &u.Email,
+			&u.PasswordHash, &u.Status, &u.PrefStatus, &u.CustomStatus, &u.CustomStatusEmoji, &u.CustomStatusExpiresAt, &u.Email,
 			&u.Language, &u.DMPrivacy, &u.IsPlatformAdmin, &u.IsPlatformBanned, &u.HasSeenDownloadPrompt, &u.HasSeenWelcome,
-			&u.PlatformBanReason, &u.PlatformBannedBy, &u.PlatformBannedAt,
+			&u.PlatformBanReason, &u.PlatformBannedBy, &u.PlatformBannedAt, &u.PlatformBannedUntil,
 			&u.DeletedAt, &u.DeletedByAdmin, &u.IsHardDeleted, &u.TokenVersion,
 			&u.FeedbackLastSeenAt, &u.ReportsLastSeenAt,
 			&u.CreatedAt,
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// Vanity slugs are globally unique, freed on overwrite, and only resolve while
+// the server is active.
+func TestVanityCode_UniqueFreedOnChange(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteServerRepo(f.DB)
+	ctx := context.Background()
+
+	serverA := f.Server(dbtest.ServerSeed{})
+	serverB := f.Server(dbtest.ServerSeed{})
+
+	gaming := "gaming"
+	if err := repo.SetVanityCode(ctx, serverA, &gaming); err != nil {
+		t.Fatalf("set vanity: %v", err)
+	}
+	if id, err := repo.GetServerIDByVanityCode(ctx, gaming); err != nil || id != serverA {
+		t.Fatalf("resolve = (%q, %v), want (%q, nil)", id, err, serverA)
+	}
+
+	// Taken slug → conflict for the other server.
+	if err := repo.SetVanityCode(ctx, serverB, &gaming); !errors.Is(err, pkg.ErrConflict) {
+		t.Fatalf("duplicate slug: got %v, want ErrConflict", err)
+	}
+
+	// Changing A's slug frees the old one for B.
+	other := "other-slug"
+	if err := repo.SetVanityCode(ctx, serverA, &other); err != nil {
+		t.Fatalf("change vanity: %v", err)
+	}
+	if err := repo.SetVanityCode(ctx, serverB, &gaming); err != nil {
+		t.Fatalf("freed slug should be takeable: %v", err)
+	}
+
+	// Clearing works and an unknown slug does not resolve.
+	if err := repo.SetVanityCode(ctx, serverA, nil); err != nil {
+		t.Fatalf("clear vanity: %v", err)
+	}
+	if code, err := repo.GetVanityCode(ctx, serverA); err != nil || code != nil {
+		t.Fatalf("cleared vanity = (%v, %v), want (nil, nil)", code, err)
+	}
+	if _, err := repo.GetServerIDByVanityCode(ctx, other); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("stale slug resolved: %v", err)
+	}
+}
+
+func TestVanityCode_SoftDeletedServerDoesNotResolve(t *testing.T) {
+	f := dbtest.New(t)
+	repo := NewSQLiteServerRepo(f.DB)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	slug := "doomed"
+	if err := repo.SetVanityCode(ctx, serverID, &slug); err != nil {
+		t.Fatalf("set vanity: %v", err)
+	}
+
+	owner := f.User("")
+	if err := repo.SoftDelete(ctx, serverID, owner, false); err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if _, err := repo.GetServerIDByVanityCode(ctx, slug); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("soft-deleted server's slug resolved: %v", err)
+	}
+}
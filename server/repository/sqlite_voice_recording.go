@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+)
+
+type sqliteVoiceRecordingRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteVoiceRecordingRepo(db database.TxQuerier) VoiceRecordingRepository {
+	return &sqliteVoiceRecordingRepo{db: db}
+}
+
+func (r *sqliteVoiceRecordingRepo) Create(ctx context.Context, recording *models.VoiceRecording) error {
+	query := `
+		INSERT INTO voice_recordings (id, server_id, channel_id, egress_id, file_url, duration_seconds, started_by, started_at, ended_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		recording.ID, recording.ServerID, recording.ChannelID, recording.EgressID,
+		recording.FileURL, recording.DurationSeconds, recording.StartedBy,
+		recording.StartedAt, recording.EndedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create voice recording: %w", err)
+	}
+	return nil
+}
+
+func (r *sqliteVoiceRecordingRepo) ListByChannel(ctx context.Context, channelID string) ([]models.VoiceRecording, error) {
+	query := `
+		SELECT id, server_id, channel_id, egress_id, file_url, duration_seconds, started_by, started_at, ended_at
+		FROM voice_recordings
+		WHERE channel_id = ?
+		ORDER BY started_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voice recordings: %w", err)
+	}
+	defer rows.Close()
+
+	recordings := []models.VoiceRecording{}
+	for rows.Next() {
+		var rec models.VoiceRecording
+		if err := rows.Scan(
+			&rec.ID, &rec.ServerID, &rec.ChannelID, &rec.EgressID,
+			&rec.FileURL, &rec.DurationSeconds, &rec.StartedBy,
+			&rec.StartedAt, &rec.EndedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan voice recording: %w", err)
+		}
+		recordings = append(recordings, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating voice recordings: %w", err)
+	}
+
+	return recordings, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+type sqliteWebhookRepo struct {
+	db database.TxQuerier
+}
+
+func NewSQLiteWebhookRepo(db database.TxQuerier) WebhookRepository {
+	return &sqliteWebhookRepo{db: db}
+}
+
+func (r *sqliteWebhookRepo) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, server_id, channel_id, name, avatar_url, token, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.ID, webhook.ServerID, webhook.ChannelID, webhook.Name,
+		webhook.AvatarURL, webhook.Token, webhook.CreatedBy,
+	).Scan(&webhook.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sqliteWebhookRepo) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	query := `
+		SELECT id, server_id, channel_id, name, avatar_url, token, created_by, created_at
+		FROM webhooks WHERE id = ?`
+
+	webhook := &models.Webhook{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&webhook.ID, &webhook.ServerID, &webhook.ChannelID, &webhook.Name,
+		&webhook.AvatarURL, &webhook.Token, &webhook.CreatedBy, &webhook.CreatedAt,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, pkg.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (r *sqliteWebhookRepo) ListByServer(ctx context.Context, serverID string) ([]models.Webhook, error) {
+	query := `
+		SELECT id, server_id, channel_id, name, avatar_url, token, created_by, created_at
+		FROM webhooks WHERE server_id = ?
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(
+			&webhook.ID, &webhook.ServerID, &webhook.ChannelID, &webhook.Name,
+			&webhook.AvatarURL, &webhook.Token, &webhook.CreatedBy, &webhook.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook row: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook rows: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *sqliteWebhookRepo) UpdateToken(ctx context.Context, id, token string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE webhooks SET token = ? WHERE id = ?`, token, id)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook token: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *sqliteWebhookRepo) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if affected == 0 {
+		return pkg.ErrNotFound
+	}
+
+	return nil
+}
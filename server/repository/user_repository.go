@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 )
@@ -23,6 +24,12 @@ type UserRepository interface {
 	Update(ctx context.Context, user *models.User) error
 	UpdateStatus(ctx context.Context, userID string, status models.UserStatus) error
 	UpdatePrefStatus(ctx context.Context, userID string, prefStatus models.UserStatus) error
+	// UpdateCustomStatus sets (or clears — all nil) the custom status fields.
+	// nil expiresAt = the status stays until the user changes it.
+	UpdateCustomStatus(ctx context.Context, userID string, text, emoji *string, expiresAt *time.Time) error
+	// ClearExpiredCustomStatuses nulls custom statuses whose expiry has passed
+	// and returns the affected user IDs so the change can be broadcast.
+	ClearExpiredCustomStatuses(ctx context.Context, now time.Time) ([]string, error)
 	// UpdatePassword writes the password, bumps token_version, and revokes all sessions.
 	// oldPasswordHash guards user-initiated changes; a mismatch returns pkg.ErrConflict.
 	// Empty oldPasswordHash skips that guard for already-authorized reset/admin flows.
@@ -55,7 +62,8 @@ type UserRepository interface {
 	// ─── Platform Ban ───
 
 	// PlatformBan blocks login, WS connect, and re-registration with the same email.
-	PlatformBan(ctx context.Context, userID, reason, bannedBy string) error
+	// bannedUntil nil = permanent; otherwise the ban lapses once the timestamp passes.
+	PlatformBan(ctx context.Context, userID, reason, bannedBy string, bannedUntil *time.Time) error
 	PlatformUnban(ctx context.Context, userID string) error
 	// IsEmailPlatformBanned checks the platform_bans table for a banned email.
 	IsEmailPlatformBanned(ctx context.Context, email string) (bool, error)
@@ -64,7 +72,7 @@ type UserRepository interface {
 	// IsPlatformBannedByUserID checks if a platform_bans record exists for a user ID (works after hard-delete).
 	IsPlatformBannedByUserID(ctx context.Context, userID string) (bool, error)
 	// InsertPlatformBan adds a persistent ban record that survives user hard-delete.
-	InsertPlatformBan(ctx context.Context, email, username, userID, reason, bannedBy string) error
+	InsertPlatformBan(ctx context.Context, email, username, userID, reason, bannedBy string, bannedUntil *time.Time) error
 	// DeletePlatformBan removes the persistent ban record for a user.
 	DeletePlatformBan(ctx context.Context, userID string) error
 	// DeleteAllMessagesByUser removes all messages (server + DM) and attachments for a user.
@@ -6,7 +6,7 @@ import (
 	"github.com/akinalp/mqvi/models"
 )
 
-// VoiceMessageRepository — ephemeral chat for active voice channel sessions.
+// VoiceMessageRepository — text chat attached to voice channels.
 // DeleteByChannel returns the IDs that were wiped so the caller can clean up files on disk.
 type VoiceMessageRepository interface {
 	Create(ctx context.Context, message *models.VoiceMessage) error
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// VoiceRecordingRepository persists completed voice channel recordings.
+// Active recordings never touch the database — RecordingService tracks them
+// in memory and writes a row only once the egress has been stopped.
+type VoiceRecordingRepository interface {
+	Create(ctx context.Context, recording *models.VoiceRecording) error
+	ListByChannel(ctx context.Context, channelID string) ([]models.VoiceRecording, error)
+}
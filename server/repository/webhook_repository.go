@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/akinalp/mqvi/models"
+)
+
+// WebhookRepository defines data access for channel webhooks.
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id string) (*models.Webhook, error)
+	ListByServer(ctx context.Context, serverID string) ([]models.Webhook, error)
+	// UpdateToken replaces the token; the old one stops working immediately.
+	UpdateToken(ctx context.Context, id, token string) error
+	Delete(ctx context.Context, id string) error
+}
@@ -23,7 +23,9 @@ import (
 
 // AdminUserService handles platform-level user ban and deletion.
 type AdminUserService interface {
-	PlatformBanUser(ctx context.Context, adminUserID, targetUserID, reason string, deleteMessages bool) error
+	// PlatformBanUser blocks all access platform-wide. bannedUntil nil = permanent;
+	// otherwise the ban lapses once the timestamp passes (checked on each auth).
+	PlatformBanUser(ctx context.Context, adminUserID, targetUserID, reason string, bannedUntil *time.Time, deleteMessages bool) error
 	PlatformUnbanUser(ctx context.Context, adminUserID, targetUserID string) error
 	// SoftDeleteUser marks the user soft-deleted (recoverable for 30 days).
 	SoftDeleteUser(ctx context.Context, adminUserID, targetUserID, reason string) error
@@ -76,11 +78,15 @@ func NewAdminUserService(
 	}
 }
 
-func (s *adminUserService) PlatformBanUser(ctx context.Context, adminUserID, targetUserID, reason string, deleteMessages bool) error {
+func (s *adminUserService) PlatformBanUser(ctx context.Context, adminUserID, targetUserID, reason string, bannedUntil *time.Time, deleteMessages bool) error {
 	if adminUserID == targetUserID {
 		return fmt.Errorf("%w: cannot ban yourself", pkg.ErrBadRequest)
 	}
 
+	if bannedUntil != nil && !bannedUntil.After(time.Now()) {
+		return fmt.Errorf("%w: ban expiry must be in the future", pkg.ErrBadRequest)
+	}
+
 	target, err := s.userRepo.GetByID(ctx, targetUserID)
 	if err != nil {
 		return fmt.Errorf("target user not found: %w", err)
@@ -90,7 +96,8 @@ func (s *adminUserService) PlatformBanUser(ctx context.Context, adminUserID, tar
 		return fmt.Errorf("%w: cannot ban a platform admin", pkg.ErrForbidden)
 	}
 
-	if target.IsPlatformBanned {
+	// An expired temporary ban is not "already banned" — re-banning overwrites it.
+	if target.PlatformBanActive() {
 		return fmt.Errorf("%w: user is already banned", pkg.ErrBadRequest)
 	}
 
@@ -111,10 +118,10 @@ func (s *adminUserService) PlatformBanUser(ctx context.Context, adminUserID, tar
 	}
 	if err := database.WithTx(ctx, s.db, func(tx *sql.Tx) error {
 		txRepo := repository.NewSQLiteUserRepo(tx)
-		if err := txRepo.PlatformBan(ctx, targetUserID, reason, adminUserID); err != nil {
+		if err := txRepo.PlatformBan(ctx, targetUserID, reason, adminUserID, bannedUntil); err != nil {
 			return fmt.Errorf("failed to ban user: %w", err)
 		}
-		if err := txRepo.InsertPlatformBan(ctx, banEmail, target.Username, targetUserID, reason, adminUserID); err != nil {
+		if err := txRepo.InsertPlatformBan(ctx, banEmail, target.Username, targetUserID, reason, adminUserID, bannedUntil); err != nil {
 			return fmt.Errorf("failed to insert platform ban record: %w", err)
 		}
 		return nil
@@ -130,7 +137,7 @@ func (s *adminUserService) PlatformBanUser(ctx context.Context, adminUserID, tar
 	}
 
 	s.voiceKit.DisconnectUser(targetUserID)
-	s.hub.DisconnectUser(targetUserID)
+	s.hub.DisconnectUserWithReason(targetUserID, ws.DisconnectReasonBanned)
 
 	// Best-effort email notification
 	if reason != "" && target.Email != nil && s.emailSender != nil {
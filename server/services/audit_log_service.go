@@ -0,0 +1,84 @@
+// Package services — AuditLogService: server moderation audit trail.
+//
+// Kicks, bans, role and channel changes, and server settings updates each
+// leave one immutable entry. Writes are best-effort: a failed audit insert is
+// logged and never fails the moderation action it describes. Reads are gated
+// behind PermViewAuditLog at the route layer.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogService records and lists server moderation actions.
+type AuditLogService interface {
+	// Record writes one entry. Best-effort — errors are logged, not returned.
+	// targetID may be "" for actions without a subject; metadata is marshalled
+	// to JSON, pass nil for none.
+	Record(ctx context.Context, serverID, actorID, actionType, targetID string, metadata any)
+	List(ctx context.Context, serverID, actionType, beforeID string, limit int) (*models.AuditLogPage, error)
+}
+
+// AuditRecorder is the write-only ISP consumed by the services that emit
+// entries (member, channel, role, server). AuditLogService implements it.
+type AuditRecorder interface {
+	Record(ctx context.Context, serverID, actorID, actionType, targetID string, metadata any)
+}
+
+type auditLogService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditLogService(auditRepo repository.AuditLogRepository) AuditLogService {
+	return &auditLogService{auditRepo: auditRepo}
+}
+
+func (s *auditLogService) Record(ctx context.Context, serverID, actorID, actionType, targetID string, metadata any) {
+	entry := &models.AuditLog{
+		ID:         uuid.New().String(),
+		ServerID:   serverID,
+		ActorID:    actorID,
+		ActionType: actionType,
+	}
+	if targetID != "" {
+		entry.TargetID = &targetID
+	}
+	if metadata != nil {
+		raw, err := json.Marshal(metadata)
+		if err != nil {
+			log.Printf("[audit] failed to marshal metadata for %s server=%s: %v", actionType, serverID, err)
+		} else {
+			meta := string(raw)
+			entry.Metadata = &meta
+		}
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		log.Printf("[audit] failed to record %s server=%s actor=%s: %v", actionType, serverID, actorID, err)
+	}
+}
+
+func (s *auditLogService) List(ctx context.Context, serverID, actionType, beforeID string, limit int) (*models.AuditLogPage, error) {
+	// Fetch one extra row to detect whether another page exists.
+	entries, err := s.auditRepo.ListByServer(ctx, serverID, actionType, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(entries) > limit
+	if hasMore {
+		entries = entries[:limit]
+	}
+	if entries == nil {
+		entries = []models.AuditLog{}
+	}
+
+	return &models.AuditLogPage{Entries: entries, HasMore: hasMore}, nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+type recordedAudit struct {
+	serverID   string
+	actorID    string
+	actionType string
+	targetID   string
+	metadata   any
+}
+
+// auditRecorderStub captures Record calls without touching a database.
+type auditRecorderStub struct {
+	entries []recordedAudit
+}
+
+func (a *auditRecorderStub) Record(_ context.Context, serverID, actorID, actionType, targetID string, metadata any) {
+	a.entries = append(a.entries, recordedAudit{serverID, actorID, actionType, targetID, metadata})
+}
+
+// Stubs embed the repository interface so only the methods kick/ban/unban
+// reach need to exist.
+type auditServerRepo struct {
+	repository.ServerRepository
+}
+
+func (auditServerRepo) RemoveMember(context.Context, string, string) error { return nil }
+
+type auditBanRepo struct {
+	repository.BanRepository
+}
+
+func (auditBanRepo) Create(context.Context, *models.Ban) error    { return nil }
+func (auditBanRepo) Delete(context.Context, string, string) error { return nil }
+
+func auditMemberService(rec *auditRecorderStub) MemberService {
+	svc := NewMemberService(
+		&testutil.MockUserRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+				return &models.User{ID: id, Username: id}, nil
+			},
+		},
+		&testutil.MockRoleRepo{
+			// Actor outranks target so the hierarchy gate passes.
+			GetByUserIDAndServerFn: func(_ context.Context, userID, _ string) ([]models.Role, error) {
+				if userID == "mod" {
+					return []models.Role{{Position: 5}}, nil
+				}
+				return []models.Role{{Position: 1}}, nil
+			},
+		},
+		auditBanRepo{},
+		auditServerRepo{},
+		nil,
+		&testutil.MockEventPublisher{},
+		nil, nil,
+		&testutil.MockFileURLSigner{},
+	)
+	if rec != nil {
+		svc.SetAuditRecorder(rec)
+	}
+	return svc
+}
+
+// Every moderation action on a member leaves exactly one audit entry naming
+// the actor — the log answers "who did this", not just "what happened".
+func TestMemberModeration_EmitsAuditEntries(t *testing.T) {
+	rec := &auditRecorderStub{}
+	svc := auditMemberService(rec)
+	ctx := context.Background()
+
+	if err := svc.Kick(ctx, "s1", "mod", "troll"); err != nil {
+		t.Fatalf("Kick: %v", err)
+	}
+	if err := svc.Ban(ctx, "s1", "mod", "troll", "spam"); err != nil {
+		t.Fatalf("Ban: %v", err)
+	}
+	if err := svc.Unban(ctx, "s1", "mod", "troll"); err != nil {
+		t.Fatalf("Unban: %v", err)
+	}
+
+	want := []string{models.AuditActionMemberKick, models.AuditActionMemberBan, models.AuditActionMemberUnban}
+	if len(rec.entries) != len(want) {
+		t.Fatalf("recorded %d entries, want %d: %+v", len(rec.entries), len(want), rec.entries)
+	}
+	for i, e := range rec.entries {
+		if e.actionType != want[i] {
+			t.Errorf("entry %d action = %q, want %q", i, e.actionType, want[i])
+		}
+		if e.serverID != "s1" || e.actorID != "mod" || e.targetID != "troll" {
+			t.Errorf("entry %d = %+v, want s1/mod/troll", i, e)
+		}
+	}
+
+	// The ban carries its reason so the log explains itself.
+	meta, ok := rec.entries[1].metadata.(map[string]string)
+	if !ok || meta["reason"] != "spam" {
+		t.Errorf("ban metadata = %v, want reason=spam", rec.entries[1].metadata)
+	}
+}
+
+// The recorder is optional wiring — moderation must not depend on it.
+func TestMemberModeration_NilRecorderIsSafe(t *testing.T) {
+	svc := auditMemberService(nil)
+	if err := svc.Kick(context.Background(), "s1", "mod", "troll"); err != nil {
+		t.Fatalf("Kick without a recorder: %v", err)
+	}
+}
+
+// auditRepoStub serves canned entries and remembers what List asked for.
+type auditRepoStub struct {
+	repository.AuditLogRepository
+	entries   []models.AuditLog
+	created   []*models.AuditLog
+	gotAction string
+}
+
+func (r *auditRepoStub) Create(_ context.Context, entry *models.AuditLog) error {
+	r.created = append(r.created, entry)
+	return nil
+}
+
+func (r *auditRepoStub) ListByServer(_ context.Context, _, actionType, _ string, limit int) ([]models.AuditLog, error) {
+	r.gotAction = actionType
+	if limit > len(r.entries) {
+		return r.entries, nil
+	}
+	return r.entries[:limit], nil
+}
+
+func TestAuditLogList_CursorHasMore(t *testing.T) {
+	repo := &auditRepoStub{}
+	for i := 0; i < 5; i++ {
+		repo.entries = append(repo.entries, models.AuditLog{ID: "e", ServerID: "s1"})
+	}
+	svc := NewAuditLogService(repo)
+
+	page, err := svc.List(context.Background(), "s1", "", "", 4)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !page.HasMore || len(page.Entries) != 4 {
+		t.Fatalf("got %d entries, has_more=%v; want 4 entries with more", len(page.Entries), page.HasMore)
+	}
+
+	page, err = svc.List(context.Background(), "s1", models.AuditActionMemberBan, "", 10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if page.HasMore || len(page.Entries) != 5 {
+		t.Fatalf("got %d entries, has_more=%v; want all 5 and no more", len(page.Entries), page.HasMore)
+	}
+	if repo.gotAction != models.AuditActionMemberBan {
+		t.Errorf("action filter %q not passed through", models.AuditActionMemberBan)
+	}
+}
+
+func TestAuditLogRecord_MarshalsMetadata(t *testing.T) {
+	repo := &auditRepoStub{}
+	svc := NewAuditLogService(repo)
+
+	svc.Record(context.Background(), "s1", "mod", models.AuditActionMemberBan, "troll",
+		map[string]string{"reason": "spam"})
+	svc.Record(context.Background(), "s1", "mod", models.AuditActionServerUpdate, "", nil)
+
+	if len(repo.created) != 2 {
+		t.Fatalf("created %d entries, want 2", len(repo.created))
+	}
+	ban := repo.created[0]
+	if ban.TargetID == nil || *ban.TargetID != "troll" {
+		t.Errorf("ban target = %v, want troll", ban.TargetID)
+	}
+	if ban.Metadata == nil || *ban.Metadata != `{"reason":"spam"}` {
+		t.Errorf("ban metadata = %v, want reason JSON", ban.Metadata)
+	}
+	update := repo.created[1]
+	if update.TargetID != nil || update.Metadata != nil {
+		t.Errorf("server update entry = %+v, want nil target and metadata", update)
+	}
+}
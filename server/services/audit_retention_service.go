@@ -0,0 +1,180 @@
+// Package services — AuditRetentionService: keeps the audit_logs table
+// bounded.
+//
+// The audit log is append-only at the API; without a sweep it grows for the
+// life of the instance. Two instance-level bounds apply (config.AuditRetention):
+// an age window and a per-server entry cap, either of which may be disabled
+// with 0. Pruning runs daily in bounded batches so a long-lived table is
+// drained without holding one giant delete transaction over the database.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akinalp/mqvi/repository"
+)
+
+// auditRetentionSweepInterval is how often the sweep runs. Both bounds are
+// coarse (days, total entries), so daily is enough.
+const auditRetentionSweepInterval = 24 * time.Hour
+
+// auditRetentionStartupDelay delays the first sweep past boot so restarts
+// never skip a day but the sweep stays out of startup's way.
+const auditRetentionStartupDelay = 5 * time.Minute
+
+// auditRetentionBatchSize caps one delete batch. The sweep loops batches
+// until the backlog drains, so this bounds transaction size, not total work.
+const auditRetentionBatchSize = 500
+
+// AuditRetentionService prunes audit log entries past the instance's
+// retention bounds.
+type AuditRetentionService interface {
+	// Start launches the sweep poller goroutine. Idempotent; a no-op when
+	// both retention bounds are disabled.
+	Start()
+	// Stop signals the poller to exit and waits for an in-flight sweep to
+	// finish. Safe to call before Start (no-op).
+	Stop()
+	// RunOnce drains the current backlog in bounded batches, synchronously.
+	// Used by the poller and exposed for tests.
+	RunOnce(ctx context.Context) error
+}
+
+type auditRetentionService struct {
+	auditRepo    repository.AuditLogRepository
+	days         int // 0 = no age bound
+	maxPerServer int // 0 = no per-server cap
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewAuditRetentionService(auditRepo repository.AuditLogRepository, days, maxPerServer int) AuditRetentionService {
+	return &auditRetentionService{
+		auditRepo:    auditRepo,
+		days:         days,
+		maxPerServer: maxPerServer,
+	}
+}
+
+func (s *auditRetentionService) Start() {
+	if s.days == 0 && s.maxPerServer == 0 {
+		log.Println("[audit] retention disabled, entries are kept forever")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.started = true
+
+	go s.pollLoop(ctx)
+}
+
+func (s *auditRetentionService) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	cancel := s.cancel
+	done := s.done
+	s.started = false
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+	log.Println("[audit] retention sweep stopped")
+}
+
+func (s *auditRetentionService) pollLoop(ctx context.Context) {
+	defer close(s.done)
+
+	startup := time.NewTimer(auditRetentionStartupDelay)
+	defer startup.Stop()
+	ticker := time.NewTicker(auditRetentionSweepInterval)
+	defer ticker.Stop()
+
+	sweep := func() {
+		runCtx, cancel := context.WithTimeout(context.Background(), auditRetentionSweepInterval)
+		if err := s.RunOnce(runCtx); err != nil {
+			log.Printf("[audit] retention sweep failed: %v", err)
+		}
+		cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-startup.C:
+			sweep()
+		case <-ticker.C:
+			sweep()
+		}
+	}
+}
+
+// RunOnce applies both bounds, each drained batch by batch until a short
+// batch signals the end. A failure mid-drain leaves the rest for the next
+// pass — over-retention rows stay past their bound, so the sweep is resumable
+// by construction.
+func (s *auditRetentionService) RunOnce(ctx context.Context) error {
+	total := int64(0)
+
+	if s.days > 0 {
+		n, err := s.drain(ctx, func(ctx context.Context) (int64, error) {
+			return s.auditRepo.PruneOlderThan(ctx, s.days, auditRetentionBatchSize)
+		})
+		total += n
+		if err != nil {
+			return fmt.Errorf("failed to prune audit logs older than %d days: %w", s.days, err)
+		}
+	}
+	if s.maxPerServer > 0 {
+		n, err := s.drain(ctx, func(ctx context.Context) (int64, error) {
+			return s.auditRepo.PruneOverPerServerCap(ctx, s.maxPerServer, auditRetentionBatchSize)
+		})
+		total += n
+		if err != nil {
+			return fmt.Errorf("failed to prune audit logs over the per-server cap of %d: %w", s.maxPerServer, err)
+		}
+	}
+
+	if total > 0 {
+		log.Printf("[audit] pruned %d audit log entries past retention", total)
+	}
+	return nil
+}
+
+// drain loops one bounded prune until it comes up short. It returns the count
+// pruned so far even on error, so the caller's log stays honest about partial
+// progress.
+func (s *auditRetentionService) drain(ctx context.Context, prune func(context.Context) (int64, error)) (int64, error) {
+	total := int64(0)
+	for {
+		n, err := prune(ctx)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < auditRetentionBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/repository"
+)
+
+// auditPruneRepo simulates a backlog of over-retention rows: each prune call
+// takes up to limit rows off the relevant counter, like the bounded SQL
+// deletes do.
+type auditPruneRepo struct {
+	repository.AuditLogRepository
+	staleRows int64
+	overCap   int64
+	ageCalls  int
+	capCalls  int
+}
+
+func (r *auditPruneRepo) PruneOlderThan(_ context.Context, days, limit int) (int64, error) {
+	r.ageCalls++
+	n := min(int64(limit), r.staleRows)
+	r.staleRows -= n
+	return n, nil
+}
+
+func (r *auditPruneRepo) PruneOverPerServerCap(_ context.Context, maxPerServer, limit int) (int64, error) {
+	r.capCalls++
+	n := min(int64(limit), r.overCap)
+	r.overCap -= n
+	return n, nil
+}
+
+func TestAuditRetentionRunOnce_DrainsBacklogInBatches(t *testing.T) {
+	// Two full batches plus one leftover: the drain must keep going past the
+	// first batch and stop on the short one.
+	repo := &auditPruneRepo{staleRows: 2*auditRetentionBatchSize + 1, overCap: 3}
+	svc := NewAuditRetentionService(repo, 90, 10000)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if repo.staleRows != 0 || repo.overCap != 0 {
+		t.Fatalf("backlog not drained: %d stale, %d over cap left", repo.staleRows, repo.overCap)
+	}
+	if repo.ageCalls != 3 {
+		t.Fatalf("age prune ran %d times, want 3 (two full batches + short)", repo.ageCalls)
+	}
+}
+
+func TestAuditRetentionRunOnce_SkipsDisabledBounds(t *testing.T) {
+	repo := &auditPruneRepo{staleRows: 10, overCap: 10}
+	svc := NewAuditRetentionService(repo, 0, 0)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if repo.ageCalls != 0 || repo.capCalls != 0 {
+		t.Fatalf("disabled bounds still pruned (age %d, cap %d calls)", repo.ageCalls, repo.capCalls)
+	}
+}
@@ -26,6 +26,20 @@ type AuthAppLogger interface {
 	Log(level models.LogLevel, category models.LogCategory, userID, serverID *string, message string, metadata map[string]string)
 }
 
+// OwnedServerDeleter soft-deletes every server a user owns (ISP — ServerService
+// implements it). A self-deleted account must not leave its communities ownerless
+// for the recovery window.
+type OwnedServerDeleter interface {
+	SoftDeleteOwnedServers(ctx context.Context, userID string) error
+}
+
+// NameFilter screens user-chosen names against the instance-wide blocklist (ISP —
+// pkg/namefilter implements it). Shared by every path that accepts a name: registration,
+// profile update, and per-server nicknames. May be nil (filtering disabled).
+type NameFilter interface {
+	Blocked(name string) bool
+}
+
 type AuthService interface {
 	Register(ctx context.Context, req *models.CreateUserRequest) (*AuthTokens, error)
 	Login(ctx context.Context, req *models.LoginRequest) (*AuthTokens, error)
@@ -53,7 +67,16 @@ type AuthService interface {
 	// Returns auth tokens like a regular login. Tombstone (is_hard_deleted=1) is not recoverable.
 	RestoreAccount(ctx context.Context, username, password string) (*AuthTokens, error)
 
+	// PasswordPolicy returns the active policy so the client can render the
+	// requirements before a password is submitted.
+	PasswordPolicy() password.Policy
+
 	SetAppLogger(logger AuthAppLogger)
+	// SetNameFilter wires the instance name blocklist post-construction.
+	SetNameFilter(filter NameFilter)
+	// SetOwnedServerDeleter wires the owned-server teardown consulted on self-delete.
+	// Nil-safe: unwired means owned servers wait for the tombstone cascade.
+	SetOwnedServerDeleter(d OwnedServerDeleter)
 }
 
 // AccountDeletedError signals that login was attempted on a soft-deleted account.
@@ -84,7 +107,10 @@ type authService struct {
 	hub           ws.EventPublisher
 	emailSender   email.EmailSender // nil if RESEND_API_KEY not set
 	breachChecker password.BreachChecker
+	policy        password.Policy
 	appLogger     AuthAppLogger
+	nameFilter    NameFilter         // may be nil (no blocklist configured)
+	serverDeleter OwnedServerDeleter // optional — set post-construction via SetOwnedServerDeleter
 	jwtSecret     []byte
 	accessExp     time.Duration
 	refreshExp    time.Duration
@@ -94,6 +120,28 @@ func (s *authService) SetAppLogger(logger AuthAppLogger) {
 	s.appLogger = logger
 }
 
+func (s *authService) SetNameFilter(filter NameFilter) {
+	s.nameFilter = filter
+}
+
+func (s *authService) SetOwnedServerDeleter(d OwnedServerDeleter) {
+	s.serverDeleter = d
+}
+
+// checkName applies the instance blocklist. The error is deliberately generic — naming the
+// matched term would let a user binary-search the list one registration attempt at a time.
+func (s *authService) checkName(names ...string) error {
+	if s.nameFilter == nil {
+		return nil
+	}
+	for _, n := range names {
+		if s.nameFilter.Blocked(n) {
+			return pkg.WithCode(fmt.Errorf("%w: that name isn't allowed", pkg.ErrBadRequest), pkg.CodeNameNotAllowed)
+		}
+	}
+	return nil
+}
+
 func (s *authService) logWarn(userID *string, message string, metadata map[string]string) {
 	if s.appLogger != nil {
 		s.appLogger.Log(models.LogLevelWarn, models.LogCategoryAuth, userID, nil, message, metadata)
@@ -107,6 +155,7 @@ func NewAuthService(
 	hub ws.EventPublisher,
 	emailSender email.EmailSender,
 	breachChecker password.BreachChecker,
+	policy password.Policy,
 	jwtSecret string,
 	accessExpMinutes int,
 	refreshExpDays int,
@@ -118,12 +167,17 @@ func NewAuthService(
 		hub:           hub,
 		emailSender:   emailSender,
 		breachChecker: breachChecker,
+		policy:        policy,
 		jwtSecret:     []byte(jwtSecret),
 		accessExp:     time.Duration(accessExpMinutes) * time.Minute,
 		refreshExp:    time.Duration(refreshExpDays) * 24 * time.Hour,
 	}
 }
 
+func (s *authService) PasswordPolicy() password.Policy {
+	return s.policy
+}
+
 func emailOf(u *models.User) string {
 	if u == nil || u.Email == nil {
 		return ""
@@ -133,7 +187,7 @@ func emailOf(u *models.User) string {
 
 // Coded so the client can translate the rejection rather than match on English text.
 func (s *authService) checkPasswordOffline(newPassword, username, email string) error {
-	switch err := password.Validate(newPassword, username, email); {
+	switch err := s.policy.Validate(newPassword, username, email); {
 	case err == nil:
 		return nil
 	case errors.Is(err, password.ErrTooShort):
@@ -142,6 +196,10 @@ func (s *authService) checkPasswordOffline(newPassword, username, email string)
 		return pkg.WithCode(fmt.Errorf("%w: %v", pkg.ErrBadRequest, err), pkg.CodePasswordTooLong)
 	case errors.Is(err, password.ErrContainsIdentity):
 		return pkg.WithCode(fmt.Errorf("%w: %v", pkg.ErrBadRequest, err), pkg.CodePasswordContainsIdentity)
+	case errors.Is(err, password.ErrTooCommon):
+		return pkg.WithCode(fmt.Errorf("%w: %v", pkg.ErrBadRequest, err), pkg.CodePasswordTooCommon)
+	case errors.Is(err, password.ErrMissingClass):
+		return pkg.WithCode(fmt.Errorf("%w: %v", pkg.ErrBadRequest, err), pkg.CodePasswordMissingClass)
 	default:
 		return fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
 	}
@@ -178,6 +236,10 @@ func (s *authService) Register(ctx context.Context, req *models.CreateUserReques
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
 
+	if err := s.checkName(req.Username, req.DisplayName); err != nil {
+		return nil, err
+	}
+
 	if err := s.checkPasswordOffline(req.Password, req.Username, req.Email); err != nil {
 		return nil, err
 	}
@@ -215,7 +277,7 @@ func (s *authService) Register(ctx context.Context, req *models.CreateUserReques
 		return nil, err
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), s.policy.Cost())
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -268,7 +330,7 @@ func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (*Aut
 		return nil, fmt.Errorf("%w: invalid username or password", pkg.ErrUnauthorized)
 	}
 
-	if user.IsPlatformBanned {
+	if user.PlatformBanActive() {
 		s.logWarn(&user.ID, "Login blocked: account suspended", map[string]string{
 			"username": req.Username,
 		})
@@ -322,7 +384,7 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*A
 		return nil, err
 	}
 
-	if user.IsPlatformBanned {
+	if user.PlatformBanActive() {
 		s.logWarn(&user.ID, "Token refresh blocked: account suspended", map[string]string{
 			"username": user.Username,
 		})
@@ -410,7 +472,7 @@ func (s *authService) ChangePassword(ctx context.Context, userID, currentPasswor
 		return nil, err
 	}
 
-	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), 12)
+	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.policy.Cost())
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash new password: %w", err)
 	}
@@ -491,11 +553,26 @@ func (s *authService) SoftDeleteSelf(ctx context.Context, userID, password strin
 		log.Printf("[auth] failed to delete sessions for soft-deleted user %s: %v", userID, err)
 	}
 
+	// Owned servers go down with the account — soft-deleted through the normal owner
+	// flow, so members are told and the servers stay restorable alongside the account.
+	// Best-effort: a failure here leaves them for the tombstone cascade.
+	if s.serverDeleter != nil {
+		if err := s.serverDeleter.SoftDeleteOwnedServers(ctx, userID); err != nil {
+			log.Printf("[auth] failed to soft-delete owned servers for user %s: %v", userID, err)
+		}
+	}
+
 	// Disconnect realtime WS connections.
 	if s.hub != nil {
 		s.hub.DisconnectUser(userID)
 	}
 
+	// Minimal audit record — the event, not the person. Deliberately no username or
+	// email: deletion is the user asking for those to stop being written down.
+	if s.appLogger != nil {
+		s.appLogger.Log(models.LogLevelInfo, models.LogCategoryAuth, &userID, nil, "account self-deleted", nil)
+	}
+
 	return nil
 }
 
@@ -519,7 +596,7 @@ func (s *authService) RestoreAccount(ctx context.Context, username, password str
 		return nil, fmt.Errorf("%w: invalid username or password", pkg.ErrUnauthorized)
 	}
 
-	if user.IsPlatformBanned {
+	if user.PlatformBanActive() {
 		return nil, fmt.Errorf("%w: account suspended", pkg.ErrForbidden)
 	}
 
@@ -663,7 +740,7 @@ func (s *authService) ResetPassword(ctx context.Context, token, newPassword stri
 		return err
 	}
 
-	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), 12)
+	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.policy.Cost())
 	if err != nil {
 		return fmt.Errorf("failed to hash new password: %w", err)
 	}
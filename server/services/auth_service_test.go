@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,7 +34,7 @@ func preHashPassword(t *testing.T, password string) string {
 const testRegisterPassword = "correct horse battery staple"
 
 func newTestAuthService(userRepo *testutil.MockUserRepo, sessionRepo *testutil.MockSessionRepo) AuthService {
-	return NewAuthService(userRepo, sessionRepo, &testutil.MockResetRepo{}, &testutil.MockEventPublisher{}, &testutil.MockEmailSender{}, password.NoopChecker{}, testJWTSecret, 15, 7)
+	return NewAuthService(userRepo, sessionRepo, &testutil.MockResetRepo{}, &testutil.MockEventPublisher{}, &testutil.MockEmailSender{}, password.NoopChecker{}, password.Default(), testJWTSecret, 15, 7)
 }
 
 func TestRegister(t *testing.T) {
@@ -215,6 +216,42 @@ func TestLogin(t *testing.T) {
 			wantErr: true,
 			errIs:   pkg.ErrForbidden,
 		},
+		{
+			name: "should return forbidden while a temporary ban is still running",
+			req:  &models.LoginRequest{Username: "tempbanned", Password: "password123"},
+			setupRepo: func(ur *testutil.MockUserRepo, sr *testutil.MockSessionRepo) {
+				until := time.Now().Add(time.Hour)
+				ur.GetByUsernameFn = func(ctx context.Context, username string) (*models.User, error) {
+					return &models.User{
+						ID:                  "user-3",
+						Username:            "tempbanned",
+						PasswordHash:        hashedPassword,
+						IsPlatformBanned:    true,
+						PlatformBannedUntil: &until,
+					}, nil
+				}
+			},
+			wantErr: true,
+			errIs:   pkg.ErrForbidden,
+		},
+		{
+			name: "should login once a temporary ban has expired",
+			req:  &models.LoginRequest{Username: "testuser", Password: "password123"},
+			setupRepo: func(ur *testutil.MockUserRepo, sr *testutil.MockSessionRepo) {
+				until := time.Now().Add(-time.Minute)
+				ur.GetByUsernameFn = func(ctx context.Context, username string) (*models.User, error) {
+					return &models.User{
+						ID:                  "user-4",
+						Username:            "testuser",
+						PasswordHash:        hashedPassword,
+						Status:              models.UserStatusOffline,
+						IsPlatformBanned:    true,
+						PlatformBannedUntil: &until,
+					}, nil
+				}
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -801,3 +838,65 @@ func TestPasswordRejectionsCarryACode(t *testing.T) {
 		})
 	}
 }
+
+// recordingServerDeleter counts owned-server teardown calls from self-deletion.
+type recordingServerDeleter struct {
+	calls  int
+	userID string
+}
+
+func (d *recordingServerDeleter) SoftDeleteOwnedServers(_ context.Context, userID string) error {
+	d.calls++
+	d.userID = userID
+	return nil
+}
+
+// recordingAppLogger captures audit messages so the test can check what they carry.
+type recordingAppLogger struct {
+	messages []string
+}
+
+func (l *recordingAppLogger) Log(_ models.LogLevel, _ models.LogCategory, _, _ *string, message string, _ map[string]string) {
+	l.messages = append(l.messages, message)
+}
+
+// Self-deletion takes the user's own servers down with them — otherwise their
+// communities sit ownerless for the whole recovery window — and leaves a minimal
+// audit record carrying the user ID only, never the username or email.
+func TestSoftDeleteSelf_TearsDownOwnedServersAndLogsWithoutPII(t *testing.T) {
+	hash := preHashPassword(t, "hunter2 but much longer")
+	userRepo := &testutil.MockUserRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: "alice", PasswordHash: hash}, nil
+		},
+	}
+	svc := newTestAuthService(userRepo, &testutil.MockSessionRepo{})
+
+	deleter := &recordingServerDeleter{}
+	logger := &recordingAppLogger{}
+	svc.SetOwnedServerDeleter(deleter)
+	svc.SetAppLogger(logger)
+
+	if err := svc.SoftDeleteSelf(context.Background(), "u1", "hunter2 but much longer"); err != nil {
+		t.Fatalf("SoftDeleteSelf: %v", err)
+	}
+	if deleter.calls != 1 || deleter.userID != "u1" {
+		t.Errorf("owned-server teardown ran %d times for %q, want once for u1", deleter.calls, deleter.userID)
+	}
+	if len(logger.messages) != 1 {
+		t.Fatalf("%d audit records, want 1", len(logger.messages))
+	}
+	if strings.Contains(logger.messages[0], "alice") {
+		t.Errorf("audit record leaked the username: %q", logger.messages[0])
+	}
+
+	// Wrong password: nothing torn down, nothing recorded.
+	deleter.calls = 0
+	logger.messages = nil
+	if err := svc.SoftDeleteSelf(context.Background(), "u1", "wrong"); !errors.Is(err, pkg.ErrUnauthorized) {
+		t.Fatalf("wrong password accepted, err=%v", err)
+	}
+	if deleter.calls != 0 || len(logger.messages) != 0 {
+		t.Error("a rejected self-delete still tore down servers or wrote an audit record")
+	}
+}
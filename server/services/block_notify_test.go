@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// blockFriendRepo embeds the interface so only the methods BlockUser reaches need to exist.
+type blockFriendRepo struct {
+	repository.FriendshipRepository
+
+	existing *models.Friendship
+	created  *models.Friendship
+}
+
+func (r *blockFriendRepo) GetByPair(context.Context, string, string) (*models.Friendship, error) {
+	if r.existing == nil {
+		return nil, pkg.ErrNotFound
+	}
+	return r.existing, nil
+}
+
+func (r *blockFriendRepo) Create(_ context.Context, f *models.Friendship) error {
+	r.created = f
+	return nil
+}
+
+func (r *blockFriendRepo) Delete(context.Context, string) error { return nil }
+
+// A block is the blocker's business. The target must not get an event naming
+// who blocked them — that is a confirmation signal, not a feature.
+func TestBlockUser_TargetIsNotTold(t *testing.T) {
+	repo := &blockFriendRepo{}
+	hub := &recordingHub{}
+	userRepo := &testutil.MockUserRepo{
+		GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id}, nil
+		},
+	}
+	svc := NewBlockService(repo, userRepo, hub, &testutil.MockFileURLSigner{})
+
+	if err := svc.BlockUser(context.Background(), "alice", "bob"); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+
+	if repo.created == nil || repo.created.UserID != "alice" || repo.created.FriendID != "bob" {
+		t.Fatalf("block record = %+v, want alice->bob", repo.created)
+	}
+	if len(hub.eventsFor("alice", ws.OpUserBlock)) != 1 {
+		t.Error("the blocker's own sessions were not told")
+	}
+	if got := hub.eventsFor("bob", ws.OpUserBlock); len(got) != 0 {
+		t.Errorf("the blocked user received %d block events, want none", len(got))
+	}
+}
+
+// Blocking someone you are friends with ends the friendship; the other side
+// sees a plain friend removal, indistinguishable from an unfriend.
+func TestBlockUser_ExistingFriendshipEndsQuietly(t *testing.T) {
+	repo := &blockFriendRepo{
+		existing: &models.Friendship{ID: "f1", UserID: "bob", FriendID: "alice", Status: models.FriendshipStatusAccepted},
+	}
+	hub := &recordingHub{}
+	userRepo := &testutil.MockUserRepo{
+		GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id}, nil
+		},
+	}
+	svc := NewBlockService(repo, userRepo, hub, &testutil.MockFileURLSigner{})
+
+	if err := svc.BlockUser(context.Background(), "alice", "bob"); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+
+	if repo.created == nil || repo.created.Status != models.FriendshipStatusBlocked {
+		t.Fatalf("block record = %+v, want a blocked status row", repo.created)
+	}
+	if len(hub.eventsFor("bob", ws.OpFriendRemove)) != 1 {
+		t.Error("bob did not see the friendship end")
+	}
+	if len(hub.eventsFor("bob", ws.OpUserBlock)) != 0 {
+		t.Error("bob was told about the block itself")
+	}
+}
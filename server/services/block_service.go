@@ -29,6 +29,8 @@ type BlockService interface {
 	ListBlocked(ctx context.Context, userID string) ([]models.FriendshipWithUser, error)
 	// IsBlocked checks bidirectional block between two users. Also satisfies BlockChecker ISP.
 	IsBlocked(ctx context.Context, userA, userB string) (bool, error)
+	// HasBlocked checks one direction only. Also satisfies DirectionalBlockChecker ISP.
+	HasBlocked(ctx context.Context, blockerID, targetID string) (bool, error)
 }
 
 // BlockChecker is a minimal ISP interface for block checks (used by dmService etc.).
@@ -118,19 +120,15 @@ func (s *blockService) BlockUser(ctx context.Context, blockerID, targetID string
 		return fmt.Errorf("failed to create block record: %w", err)
 	}
 
-	// Notify both parties
+	// Only the blocker's own sessions learn about the block. The target sees
+	// the friendship removal above at most — telling them who blocked them
+	// hands harassers a confirmation signal.
 	s.hub.BroadcastToUser(blockerID, ws.Event{
 		Op: ws.OpUserBlock,
 		Data: map[string]string{
 			"user_id": targetID,
 		},
 	})
-	s.hub.BroadcastToUser(targetID, ws.Event{
-		Op: ws.OpUserBlock,
-		Data: map[string]string{
-			"user_id": blockerID,
-		},
-	})
 
 	return nil
 }
@@ -183,3 +181,8 @@ func (s *blockService) ListBlocked(ctx context.Context, userID string) ([]models
 func (s *blockService) IsBlocked(ctx context.Context, userA, userB string) (bool, error) {
 	return s.friendRepo.IsBlocked(ctx, userA, userB)
 }
+
+// HasBlocked checks one direction only — true if blockerID blocked targetID.
+func (s *blockService) HasBlocked(ctx context.Context, blockerID, targetID string) (bool, error) {
+	return s.friendRepo.HasBlocked(ctx, blockerID, targetID)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// Interface-embedding stubs: only the host-type lookup path is implemented.
+
+type stubBitrateServerRepo struct {
+	repository.ServerRepository
+	instanceID *string
+}
+
+func (s *stubBitrateServerRepo) GetByID(_ context.Context, id string) (*models.Server, error) {
+	return &models.Server{ID: id, LiveKitInstanceID: s.instanceID}, nil
+}
+
+type stubBitrateLiveKitRepo struct {
+	repository.LiveKitRepository
+	platformManaged bool
+}
+
+func (s *stubBitrateLiveKitRepo) GetByID(_ context.Context, id string) (*models.LiveKitInstance, error) {
+	return &models.LiveKitInstance{ID: id, IsPlatformManaged: s.platformManaged}, nil
+}
+
+func newBitrateTestService(platformManaged bool, channelRepo *testutil.MockChannelRepo) ChannelService {
+	instanceID := "lk1"
+	return NewChannelService(
+		channelRepo, nil, nil, nil,
+		&stubBitrateServerRepo{instanceID: &instanceID},
+		&stubBitrateLiveKitRepo{platformManaged: platformManaged},
+		nil,
+		&testutil.MockBroadcaster{}, nil, nil, nil, nil,
+		VoiceBitrateLimits{Min: 8000, HostedMax: 96000, SelfHostedMax: 0},
+	)
+}
+
+func voiceChannelRepo(serverID string) *testutil.MockChannelRepo {
+	return &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+			return &models.Channel{ID: id, ServerID: serverID, Type: models.ChannelTypeVoice, Bitrate: models.DefaultVoiceBitrate}, nil
+		},
+	}
+}
+
+func TestUpdateChannel_BitrateCappedForHosted(t *testing.T) {
+	svc := newBitrateTestService(true, voiceChannelRepo("s1"))
+
+	over := 128000
+	_, err := svc.Update(context.Background(), "s1", "c1", &models.UpdateChannelRequest{Bitrate: &over})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("expected bad request for bitrate above the hosted ceiling, got %v", err)
+	}
+
+	within := 96000
+	ch, err := svc.Update(context.Background(), "s1", "c1", &models.UpdateChannelRequest{Bitrate: &within})
+	if err != nil {
+		t.Fatalf("bitrate at the ceiling should be accepted: %v", err)
+	}
+	if ch.Bitrate != within {
+		t.Fatalf("expected bitrate %d persisted, got %d", within, ch.Bitrate)
+	}
+}
+
+func TestUpdateChannel_BitrateUncappedForSelfHosted(t *testing.T) {
+	svc := newBitrateTestService(false, voiceChannelRepo("s1"))
+
+	high := 320000
+	ch, err := svc.Update(context.Background(), "s1", "c1", &models.UpdateChannelRequest{Bitrate: &high})
+	if err != nil {
+		t.Fatalf("self-hosted bitrate should be uncapped: %v", err)
+	}
+	if ch.Bitrate != high {
+		t.Fatalf("expected bitrate %d persisted, got %d", high, ch.Bitrate)
+	}
+
+	low := 4000
+	_, err = svc.Update(context.Background(), "s1", "c1", &models.UpdateChannelRequest{Bitrate: &low})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("expected bad request below the bitrate floor, got %v", err)
+	}
+}
+
+func TestUpdateChannel_BitrateRejectedForTextChannel(t *testing.T) {
+	channelRepo := &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+			return &models.Channel{ID: id, ServerID: "s1", Type: models.ChannelTypeText}, nil
+		},
+	}
+	svc := newBitrateTestService(true, channelRepo)
+
+	bitrate := 64000
+	_, err := svc.Update(context.Background(), "s1", "c1", &models.UpdateChannelRequest{Bitrate: &bitrate})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("expected bad request for bitrate on a text channel, got %v", err)
+	}
+}
+
+func TestCreateChannel_DefaultBitrateClampedToCeiling(t *testing.T) {
+	instanceID := "lk1"
+	svc := NewChannelService(
+		&testutil.MockChannelRepo{}, nil, nil, nil,
+		&stubBitrateServerRepo{instanceID: &instanceID},
+		&stubBitrateLiveKitRepo{platformManaged: true},
+		nil,
+		&testutil.MockBroadcaster{}, nil, nil, nil, nil,
+		// Ceiling below the default: creation must clamp, not fail.
+		VoiceBitrateLimits{Min: 8000, HostedMax: 32000, SelfHostedMax: 0},
+	)
+
+	ch, err := svc.Create(context.Background(), "s1", "u1", &models.CreateChannelRequest{Name: "voice", Type: "voice"})
+	if err != nil {
+		t.Fatalf("create with default bitrate: %v", err)
+	}
+	if ch.Bitrate != 32000 {
+		t.Fatalf("expected default bitrate clamped to 32000, got %d", ch.Bitrate)
+	}
+
+	over := 64000
+	_, err = svc.Create(context.Background(), "s1", "u1", &models.CreateChannelRequest{Name: "voice", Type: "voice", Bitrate: over})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("expected bad request for explicit bitrate above the ceiling, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// clonePermRepo embeds the interface so only the two override calls the clone
+// path makes need to exist.
+type clonePermRepo struct {
+	repository.ChannelPermissionRepository
+	overrides []models.ChannelPermissionOverride
+	copied    []models.ChannelPermissionOverride
+}
+
+func (r *clonePermRepo) GetByChannel(_ context.Context, _ string) ([]models.ChannelPermissionOverride, error) {
+	return r.overrides, nil
+}
+
+func (r *clonePermRepo) Set(_ context.Context, override *models.ChannelPermissionOverride) error {
+	r.copied = append(r.copied, *override)
+	return nil
+}
+
+func cloneTestService(chanRepo *testutil.MockChannelRepo, permRepo *clonePermRepo) ChannelService {
+	return NewChannelService(
+		chanRepo, nil, nil, nil, nil, nil, permRepo,
+		&testutil.MockBroadcaster{}, nil, nil, nil, nil,
+		VoiceBitrateLimits{},
+	)
+}
+
+func TestCloneChannel_CopiesSettingsAndOverrides(t *testing.T) {
+	catID := "cat1"
+	topic := "rules"
+	source := &models.Channel{
+		ID: "ch1", ServerID: "s1", Name: "general", Type: models.ChannelTypeText,
+		CategoryID: &catID, Topic: &topic, Position: 2,
+		SlowmodeSeconds: 30, DeleteAfterSeconds: 60, PinAnnouncements: true,
+	}
+	var created *models.Channel
+	chanRepo := &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) { return source, nil },
+		CreateFn: func(_ context.Context, channel *models.Channel) error {
+			channel.ID = "ch2"
+			created = channel
+			return nil
+		},
+		GetMaxPositionFn: func(_ context.Context, categoryID string) (int, error) {
+			if categoryID != catID {
+				t.Errorf("max position looked up for category %q, want %q", categoryID, catID)
+			}
+			return 5, nil
+		},
+	}
+	permRepo := &clonePermRepo{overrides: []models.ChannelPermissionOverride{
+		{ChannelID: "ch1", TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: models.PermSendMessages, Deny: models.PermViewChannel},
+		{ChannelID: "ch1", TargetType: models.OverrideTargetRole, TargetID: "r2", Deny: models.PermSendMessages},
+	}}
+	svc := cloneTestService(chanRepo, permRepo)
+
+	clone, err := svc.Clone(context.Background(), "s1", "admin", "ch1")
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if created == nil || clone.ID != "ch2" {
+		t.Fatalf("clone was not created, got %+v", clone)
+	}
+	if clone.Name != "general" || clone.Topic == nil || *clone.Topic != "rules" ||
+		clone.SlowmodeSeconds != 30 || clone.DeleteAfterSeconds != 60 || !clone.PinAnnouncements {
+		t.Errorf("clone dropped settings: %+v", clone)
+	}
+	// End of the same category, after the current maximum.
+	if clone.CategoryID == nil || *clone.CategoryID != catID || clone.Position != 6 {
+		t.Errorf("clone placed at category=%v position=%d, want %s/6", clone.CategoryID, clone.Position, catID)
+	}
+	if len(permRepo.copied) != 2 {
+		t.Fatalf("copied %d overrides, want 2", len(permRepo.copied))
+	}
+	for _, o := range permRepo.copied {
+		if o.ChannelID != "ch2" {
+			t.Errorf("override copied onto channel %s, want ch2", o.ChannelID)
+		}
+	}
+}
+
+func TestCloneChannel_RejectsChannelFromAnotherServer(t *testing.T) {
+	chanRepo := &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+			return &models.Channel{ID: id, ServerID: "other"}, nil
+		},
+		CreateFn: func(_ context.Context, _ *models.Channel) error {
+			t.Error("a cross-server clone must not create anything")
+			return nil
+		},
+	}
+	svc := cloneTestService(chanRepo, &clonePermRepo{})
+
+	if _, err := svc.Clone(context.Background(), "s1", "admin", "ch1"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("cross-server clone = %v, want ErrForbidden", err)
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+)
+
+type ChannelFollowService interface {
+	// Follow makes the target channel follow the source channel in the route's
+	// server. The caller already holds ManageChannels on the source's server
+	// (route middleware); the target end is checked here.
+	Follow(ctx context.Context, serverID, sourceChannelID, userID string, req *models.FollowChannelRequest) (*models.ChannelFollow, error)
+	Unfollow(ctx context.Context, serverID, sourceChannelID, targetChannelID string) error
+	ListFollows(ctx context.Context, serverID, sourceChannelID string) ([]models.ChannelFollow, error)
+	// CrosspostMessage fans a freshly created user message out to follower
+	// channels. Called from the message broadcast path; best-effort.
+	CrosspostMessage(message *models.Message)
+	SetSystemMessenger(m SystemMessenger)
+}
+
+type channelFollowService struct {
+	followRepo      repository.ChannelFollowRepository
+	channelRepo     repository.ChannelRepository
+	permResolver    ChannelPermResolver
+	systemMessenger SystemMessenger
+}
+
+// SetSystemMessenger wires the message service in after construction — the
+// message service is built first and takes this service as its crosspost hook.
+func (s *channelFollowService) SetSystemMessenger(m SystemMessenger) {
+	s.systemMessenger = m
+}
+
+func NewChannelFollowService(
+	followRepo repository.ChannelFollowRepository,
+	channelRepo repository.ChannelRepository,
+	permResolver ChannelPermResolver,
+) ChannelFollowService {
+	return &channelFollowService{
+		followRepo:   followRepo,
+		channelRepo:  channelRepo,
+		permResolver: permResolver,
+	}
+}
+
+func (s *channelFollowService) Follow(ctx context.Context, serverID, sourceChannelID, userID string, req *models.FollowChannelRequest) (*models.ChannelFollow, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+	}
+	if req.TargetChannelID == sourceChannelID {
+		return nil, fmt.Errorf("%w: a channel cannot follow itself", pkg.ErrBadRequest)
+	}
+
+	source, err := s.channelRepo.GetByID(ctx, sourceChannelID)
+	if err != nil {
+		return nil, err
+	}
+	// IDOR guard: the source channel must belong to the route's server.
+	if source == nil || source.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+	if source.Type != models.ChannelTypeText {
+		return nil, fmt.Errorf("%w: only text channels can be followed", pkg.ErrBadRequest)
+	}
+
+	target, err := s.channelRepo.GetByID(ctx, req.TargetChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%w: target channel not found", pkg.ErrNotFound)
+	}
+	if target.Type != models.ChannelTypeText {
+		return nil, fmt.Errorf("%w: cross-posts can only target text channels", pkg.ErrBadRequest)
+	}
+
+	// Consent on the receiving end: the caller must hold ManageChannels on the
+	// target channel too. Resolution fails for non-members, so this also keeps
+	// follows inside servers the caller is actually in.
+	targetPerms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, req.TargetChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target channel permissions: %w", err)
+	}
+	if !targetPerms.Has(models.PermManageChannels) {
+		return nil, fmt.Errorf("%w: missing manage channels permission on the target channel", pkg.ErrForbidden)
+	}
+
+	exists, err := s.followRepo.Exists(ctx, sourceChannelID, req.TargetChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: channel is already following", pkg.ErrConflict)
+	}
+
+	follow := &models.ChannelFollow{
+		SourceChannelID: sourceChannelID,
+		TargetChannelID: req.TargetChannelID,
+		CreatedBy:       userID,
+	}
+	if err := s.followRepo.Create(ctx, follow); err != nil {
+		return nil, err
+	}
+	return follow, nil
+}
+
+func (s *channelFollowService) Unfollow(ctx context.Context, serverID, sourceChannelID, targetChannelID string) error {
+	source, err := s.channelRepo.GetByID(ctx, sourceChannelID)
+	if err != nil {
+		return err
+	}
+	if source == nil || source.ServerID != serverID {
+		return fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+
+	return s.followRepo.Delete(ctx, sourceChannelID, targetChannelID)
+}
+
+func (s *channelFollowService) ListFollows(ctx context.Context, serverID, sourceChannelID string) ([]models.ChannelFollow, error) {
+	source, err := s.channelRepo.GetByID(ctx, sourceChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil || source.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+
+	return s.followRepo.ListBySource(ctx, sourceChannelID)
+}
+
+// CrosspostMessage copies a new user message into every follower channel as a
+// 'crosspost' system message attributed to the original author.
+//
+// Only plaintext user messages cross the boundary: system messages are skipped
+// so a cross-post landing in a followed channel can't cascade into a loop, and
+// E2EE ciphertext is keyed to its own server's members and would be noise
+// everywhere else. Each target is best-effort — one broken follower channel
+// must not block the rest.
+func (s *channelFollowService) CrosspostMessage(message *models.Message) {
+	if message.Type != models.MessageTypeDefault {
+		return
+	}
+	if message.EncryptionVersion != 0 || message.Content == nil {
+		return
+	}
+	if s.systemMessenger == nil {
+		return
+	}
+
+	ctx := context.Background()
+	follows, err := s.followRepo.ListBySource(ctx, message.ChannelID)
+	if err != nil {
+		log.Printf("[follow] failed to list followers of channel %s: %v", message.ChannelID, err)
+		return
+	}
+
+	for _, follow := range follows {
+		if _, err := s.systemMessenger.CreateSystemMessage(
+			ctx, follow.TargetChannelID, message.UserID, models.MessageTypeCrosspost, *message.Content, nil,
+		); err != nil {
+			log.Printf("[follow] failed to cross-post message %s to channel %s: %v", message.ID, follow.TargetChannelID, err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// crosspostRecorder captures cross-posts with their content — the pin test's
+// recorder drops content, and content fidelity is the point here.
+type crosspostRecorder struct {
+	calls []struct {
+		channelID string
+		userID    string
+		msgType   models.MessageType
+		content   string
+	}
+}
+
+func (r *crosspostRecorder) CreateSystemMessage(ctx context.Context, channelID, userID string, msgType models.MessageType, content string, replyToID *string) (*models.Message, error) {
+	r.calls = append(r.calls, struct {
+		channelID string
+		userID    string
+		msgType   models.MessageType
+		content   string
+	}{channelID, userID, msgType, content})
+	return &models.Message{ChannelID: channelID, UserID: userID, Type: msgType}, nil
+}
+
+func newFollowTestService(t *testing.T, perms models.Permission) (ChannelFollowService, *dbtest.Fixture, *crosspostRecorder) {
+	t.Helper()
+	f := dbtest.New(t)
+	svc := NewChannelFollowService(
+		repository.NewSQLiteChannelFollowRepo(f.DB),
+		repository.NewSQLiteChannelRepo(f.DB),
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(ctx context.Context, userID, channelID string) (models.Permission, error) {
+				return perms, nil
+			},
+		},
+	)
+	recorder := &crosspostRecorder{}
+	svc.SetSystemMessenger(recorder)
+	return svc, f, recorder
+}
+
+func TestFollow_CreatesAndRejectsInvalid(t *testing.T) {
+	svc, f, _ := newFollowTestService(t, models.PermManageChannels)
+	ctx := context.Background()
+
+	sourceServer := f.Server(dbtest.ServerSeed{})
+	targetServer := f.Server(dbtest.ServerSeed{})
+	source := f.Channel("", sourceServer)
+	target := f.Channel("", targetServer)
+	userID := f.User("")
+
+	follow, err := svc.Follow(ctx, sourceServer, source, userID, &models.FollowChannelRequest{TargetChannelID: target})
+	if err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if follow.SourceChannelID != source || follow.TargetChannelID != target || follow.CreatedBy != userID {
+		t.Errorf("follow row carried wrong fields: %+v", follow)
+	}
+
+	// Duplicate pair is a conflict, not a second row.
+	if _, err := svc.Follow(ctx, sourceServer, source, userID, &models.FollowChannelRequest{TargetChannelID: target}); !errors.Is(err, pkg.ErrConflict) {
+		t.Errorf("duplicate follow: got %v, want ErrConflict", err)
+	}
+
+	// A channel can't follow itself.
+	if _, err := svc.Follow(ctx, sourceServer, source, userID, &models.FollowChannelRequest{TargetChannelID: source}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("self follow: got %v, want ErrBadRequest", err)
+	}
+
+	// IDOR guard: the source must belong to the route's server.
+	if _, err := svc.Follow(ctx, targetServer, source, userID, &models.FollowChannelRequest{TargetChannelID: target}); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("cross-server source: got %v, want ErrForbidden", err)
+	}
+
+	// Voice channels are out on both ends.
+	if _, err := f.DB.ExecContext(ctx, `UPDATE channels SET type = 'voice' WHERE id = ?`, target); err != nil {
+		t.Fatalf("set voice type: %v", err)
+	}
+	if _, err := svc.Follow(ctx, sourceServer, source, userID, &models.FollowChannelRequest{TargetChannelID: target}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("voice target: got %v, want ErrBadRequest", err)
+	}
+}
+
+// The target end needs ManageChannels too — the route middleware only covers
+// the source server, so this is the receiving admin's consent.
+func TestFollow_RequiresManageChannelsOnTarget(t *testing.T) {
+	svc, f, _ := newFollowTestService(t, models.PermViewChannel)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	source := f.Channel("", serverID)
+	target := f.Channel("", f.Server(dbtest.ServerSeed{}))
+	userID := f.User("")
+
+	if _, err := svc.Follow(ctx, serverID, source, userID, &models.FollowChannelRequest{TargetChannelID: target}); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("follow without target perms: got %v, want ErrForbidden", err)
+	}
+}
+
+func TestCrosspostMessage_FansOutUserMessagesOnly(t *testing.T) {
+	svc, f, recorder := newFollowTestService(t, models.PermManageChannels)
+	ctx := context.Background()
+
+	sourceServer := f.Server(dbtest.ServerSeed{})
+	source := f.Channel("", sourceServer)
+	targetA := f.Channel("", f.Server(dbtest.ServerSeed{}))
+	targetB := f.Channel("", f.Server(dbtest.ServerSeed{}))
+	userID := f.User("")
+
+	for _, target := range []string{targetA, targetB} {
+		if _, err := svc.Follow(ctx, sourceServer, source, userID, &models.FollowChannelRequest{TargetChannelID: target}); err != nil {
+			t.Fatalf("follow %s: %v", target, err)
+		}
+	}
+
+	content := "release is out"
+	svc.CrosspostMessage(&models.Message{
+		ID:        "m1",
+		ChannelID: source,
+		UserID:    userID,
+		Type:      models.MessageTypeDefault,
+		Content:   &content,
+	})
+
+	if len(recorder.calls) != 2 {
+		t.Fatalf("expected 2 cross-posts, got %d", len(recorder.calls))
+	}
+	seen := map[string]bool{}
+	for _, call := range recorder.calls {
+		seen[call.channelID] = true
+		if call.msgType != models.MessageTypeCrosspost {
+			t.Errorf("cross-post type = %q, want crosspost", call.msgType)
+		}
+		if call.content != content {
+			t.Errorf("cross-post content = %q, want original content", call.content)
+		}
+		if call.userID != userID {
+			t.Errorf("cross-post attributed to %q, want original author", call.userID)
+		}
+	}
+	if !seen[targetA] || !seen[targetB] {
+		t.Errorf("cross-posts went to %v, want both followers", seen)
+	}
+
+	// System messages never cross the boundary — that's the loop guard: a
+	// cross-post landing in a followed channel stays put.
+	recorder.calls = nil
+	svc.CrosspostMessage(&models.Message{ChannelID: source, UserID: userID, Type: models.MessageTypeCrosspost, Content: &content})
+	svc.CrosspostMessage(&models.Message{ChannelID: source, UserID: userID, Type: models.MessageTypePin, Content: &content})
+	if len(recorder.calls) != 0 {
+		t.Errorf("system messages must not be cross-posted, got %d calls", len(recorder.calls))
+	}
+
+	// Neither does ciphertext — it's keyed to the source server's members.
+	svc.CrosspostMessage(&models.Message{ChannelID: source, UserID: userID, Type: models.MessageTypeDefault, EncryptionVersion: 1})
+	if len(recorder.calls) != 0 {
+		t.Errorf("encrypted messages must not be cross-posted, got %d calls", len(recorder.calls))
+	}
+}
+
+// Deleting either channel takes the follow row with it (ON DELETE CASCADE) —
+// the unfollow-on-delete cleanup lives in the schema, not in service code.
+func TestChannelDelete_CascadesFollows(t *testing.T) {
+	svc, f, _ := newFollowTestService(t, models.PermManageChannels)
+	ctx := context.Background()
+
+	sourceServer := f.Server(dbtest.ServerSeed{})
+	source := f.Channel("", sourceServer)
+	target := f.Channel("", f.Server(dbtest.ServerSeed{}))
+	userID := f.User("")
+
+	if _, err := svc.Follow(ctx, sourceServer, source, userID, &models.FollowChannelRequest{TargetChannelID: target}); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	if _, err := f.DB.ExecContext(ctx, `DELETE FROM channels WHERE id = ?`, target); err != nil {
+		t.Fatalf("delete target channel: %v", err)
+	}
+
+	var count int
+	if err := f.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM channel_follows WHERE source_channel_id = ?`, source).Scan(&count); err != nil {
+		t.Fatalf("count follows: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("follow survived target channel deletion: %d rows", count)
+	}
+}
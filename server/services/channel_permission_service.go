@@ -1,6 +1,6 @@
 // Package services — ChannelPermissionService: per-channel permission overrides.
 //
-// Discord-style per-channel role overrides with allow/deny bits.
+// Discord-style per-channel role and member overrides with allow/deny bits.
 //
 // Permission resolution (Discord algorithm):
 //
@@ -8,6 +8,7 @@
 //	channelAllow = OR of override allows for user's roles
 //	channelDeny  = OR of override denies for user's roles
 //	effective    = (base & ~channelDeny) | channelAllow
+//	member override last (highest precedence): (effective & ~deny) | allow
 //
 // Admin bypasses all overrides.
 package services
@@ -39,6 +40,10 @@ type ChannelPermResolver interface {
 	// on a just-changed permission — e.g. live voice enforcement after a role/member change,
 	// which does NOT invalidate this cache — sees current state, not a ≤30s-stale entry.
 	ResolveChannelPermissionsFresh(ctx context.Context, userID, channelID string) (models.Permission, error)
+	// AllowedViewers filters candidates down to users with ViewChannel + ReadMessages on the
+	// channel, against a per-channel decision cache. This is the broadcast fan-out path: it
+	// runs once per message per channel, so it must not resolve permissions per user per call.
+	AllowedViewers(ctx context.Context, channelID string, candidates []string) []string
 }
 
 // VoiceChannelPermissionEnforcer re-applies effective voice permissions at the SFU for
@@ -60,21 +65,34 @@ type PermissionCacheInvalidator interface {
 	InvalidateAllPermissions()
 }
 
+// RulesGate reports whether a member is still held read-only behind the server's
+// rules-acceptance gate (ISP — ServerRepository implements it). Riding the permission
+// resolver means every gate — send, react, voice join — enforces it for free.
+type RulesGate interface {
+	RulesPending(ctx context.Context, serverID, userID string) (bool, error)
+}
+
 // ChannelPermissionService manages per-channel permission overrides.
 type ChannelPermissionService interface {
 	GetOverrides(ctx context.Context, serverID, channelID string) ([]models.ChannelPermissionOverride, error)
-	// SetOverride creates or updates an override. If allow=0 and deny=0, deletes it (revert to inherit).
-	SetOverride(ctx context.Context, serverID, channelID, roleID string, req *models.SetOverrideRequest) error
-	DeleteOverride(ctx context.Context, serverID, channelID, roleID string) error
+	// SetOverride creates or updates an override for a role or member target.
+	// If allow=0 and deny=0, deletes it (revert to inherit).
+	SetOverride(ctx context.Context, serverID, channelID, targetType, targetID string, req *models.SetOverrideRequest) error
+	DeleteOverride(ctx context.Context, serverID, channelID, targetType, targetID string) error
 	// ResolveChannelPermissions computes effective permissions for a user in a channel.
 	ResolveChannelPermissions(ctx context.Context, userID, channelID string) (models.Permission, error)
 	// ResolveChannelPermissionsFresh is ResolveChannelPermissions with a forced cache refresh.
 	ResolveChannelPermissionsFresh(ctx context.Context, userID, channelID string) (models.Permission, error)
+	// AllowedViewers filters candidates against the per-channel viewer-set cache (see
+	// ChannelPermResolver).
+	AllowedViewers(ctx context.Context, channelID string, candidates []string) []string
 	// BuildVisibilityFilter builds a per-user channel visibility filter for ViewChannel checks.
 	BuildVisibilityFilter(ctx context.Context, userID, serverID string) (*ChannelVisibilityFilter, error)
 	// SetVoiceEnforcer wires the voice enforcer (post-construction — voiceService is built
 	// after this service, which it depends on).
 	SetVoiceEnforcer(enforcer VoiceChannelPermissionEnforcer)
+	// SetRulesGate wires the rules-acceptance gate post-construction.
+	SetRulesGate(gate RulesGate)
 	PermissionCacheInvalidator
 }
 
@@ -84,16 +102,26 @@ type channelPermService struct {
 	channelGetter ChannelGetter
 	hub           ws.Broadcaster
 	voiceEnforcer VoiceChannelPermissionEnforcer // set post-construction, may be nil
+	rulesGate     RulesGate                      // set post-construction, may be nil
 
 	// Cache for ResolveChannelPermissions results. Key: "userID:channelID".
 	// Invalidated per-channel when overrides change.
 	permCache *cache.TTLCache[string, models.Permission]
+
+	// Per-channel view/read decisions for broadcast scoping, keyed by channelID.
+	// Value maps userID -> allowed; maps are copy-on-write (never mutated after Set) so
+	// concurrent broadcasts read consistent snapshots. Invalidated alongside permCache.
+	viewerCache *cache.TTLCache[string, map[string]bool]
 }
 
 func (s *channelPermService) SetVoiceEnforcer(enforcer VoiceChannelPermissionEnforcer) {
 	s.voiceEnforcer = enforcer
 }
 
+func (s *channelPermService) SetRulesGate(gate RulesGate) {
+	s.rulesGate = gate
+}
+
 // enforceChannelVoice re-checks the channel's voice participants after an override change.
 // Fire-and-forget so the admin's request isn't blocked on LiveKit I/O.
 func (s *channelPermService) enforceChannelVoice(channelID string) {
@@ -114,6 +142,7 @@ func NewChannelPermissionService(
 		channelGetter: channelGetter,
 		hub:           hub,
 		permCache:     cache.New[string, models.Permission](permCacheTTL, permCacheCleanup),
+		viewerCache:   cache.New[string, map[string]bool](permCacheTTL, permCacheCleanup),
 	}
 }
 
@@ -139,7 +168,10 @@ func (s *channelPermService) GetOverrides(ctx context.Context, serverID, channel
 	return overrides, nil
 }
 
-func (s *channelPermService) SetOverride(ctx context.Context, serverID, channelID, roleID string, req *models.SetOverrideRequest) error {
+func (s *channelPermService) SetOverride(ctx context.Context, serverID, channelID, targetType, targetID string, req *models.SetOverrideRequest) error {
+	if !models.ValidOverrideTarget(targetType) {
+		return fmt.Errorf("%w: target type must be 'role' or 'member'", pkg.ErrBadRequest)
+	}
 	if err := req.Validate(); err != nil {
 		return fmt.Errorf("invalid override request: %w", err)
 	}
@@ -155,8 +187,8 @@ func (s *channelPermService) SetOverride(ctx context.Context, serverID, channelI
 
 	// allow=0, deny=0 -> no effect (same as inherit), delete
 	if req.Allow == 0 && req.Deny == 0 {
-		if err := s.permRepo.Delete(ctx, channelID, roleID); err != nil {
-			log.Printf("[channel-perm] failed to delete override (idempotent, non-fatal) channel=%s role=%s: %v", channelID, roleID, err)
+		if err := s.permRepo.Delete(ctx, channelID, targetType, targetID); err != nil {
+			log.Printf("[channel-perm] failed to delete override (idempotent, non-fatal) channel=%s %s=%s: %v", channelID, targetType, targetID, err)
 		}
 
 		s.invalidateChannelCache(channelID)
@@ -165,8 +197,9 @@ func (s *channelPermService) SetOverride(ctx context.Context, serverID, channelI
 		s.hub.BroadcastToAll(ws.Event{
 			Op: ws.OpChannelPermissionDelete,
 			Data: map[string]string{
-				"channel_id": channelID,
-				"role_id":    roleID,
+				"channel_id":  channelID,
+				"target_type": targetType,
+				"target_id":   targetID,
 			},
 		})
 
@@ -174,10 +207,11 @@ func (s *channelPermService) SetOverride(ctx context.Context, serverID, channelI
 	}
 
 	override := &models.ChannelPermissionOverride{
-		ChannelID: channelID,
-		RoleID:    roleID,
-		Allow:     req.Allow,
-		Deny:      req.Deny,
+		ChannelID:  channelID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Allow:      req.Allow,
+		Deny:       req.Deny,
 	}
 
 	if err := s.permRepo.Set(ctx, override); err != nil {
@@ -195,7 +229,11 @@ func (s *channelPermService) SetOverride(ctx context.Context, serverID, channelI
 	return nil
 }
 
-func (s *channelPermService) DeleteOverride(ctx context.Context, serverID, channelID, roleID string) error {
+func (s *channelPermService) DeleteOverride(ctx context.Context, serverID, channelID, targetType, targetID string) error {
+	if !models.ValidOverrideTarget(targetType) {
+		return fmt.Errorf("%w: target type must be 'role' or 'member'", pkg.ErrBadRequest)
+	}
+
 	// IDOR guard: the channel must belong to the route's server.
 	channel, err := s.channelGetter.GetByID(ctx, channelID)
 	if err != nil {
@@ -205,7 +243,7 @@ func (s *channelPermService) DeleteOverride(ctx context.Context, serverID, chann
 		return fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
 	}
 
-	if err := s.permRepo.Delete(ctx, channelID, roleID); err != nil {
+	if err := s.permRepo.Delete(ctx, channelID, targetType, targetID); err != nil {
 		return fmt.Errorf("failed to delete channel override: %w", err)
 	}
 
@@ -215,8 +253,9 @@ func (s *channelPermService) DeleteOverride(ctx context.Context, serverID, chann
 	s.hub.BroadcastToAll(ws.Event{
 		Op: ws.OpChannelPermissionDelete,
 		Data: map[string]string{
-			"channel_id": channelID,
-			"role_id":    roleID,
+			"channel_id":  channelID,
+			"target_type": targetType,
+			"target_id":   targetID,
 		},
 	})
 
@@ -249,7 +288,12 @@ func (s *channelPermService) BuildVisibilityFilter(ctx context.Context, userID,
 		return nil, fmt.Errorf("failed to get role overrides for visibility filter: %w", err)
 	}
 
-	if len(overrides) == 0 {
+	memberOverrides, err := s.permRepo.GetByMember(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member overrides for visibility filter: %w", err)
+	}
+
+	if len(overrides) == 0 && len(memberOverrides) == 0 {
 		return &ChannelVisibilityFilter{
 			HasBaseView:     hasBaseView,
 			HiddenChannels:  make(map[string]bool),
@@ -257,7 +301,7 @@ func (s *channelPermService) BuildVisibilityFilter(ctx context.Context, userID,
 		}, nil
 	}
 
-	// Group overrides by channel, OR allow/deny across all user roles
+	// Group role overrides by channel, OR allow/deny across all user roles
 	type channelOverride struct {
 		allow models.Permission
 		deny  models.Permission
@@ -273,11 +317,27 @@ func (s *channelPermService) BuildVisibilityFilter(ctx context.Context, userID,
 		co.deny |= o.Deny
 	}
 
+	// Member overrides apply on top of the role result for their channel —
+	// same precedence as in ResolveChannelPermissions. Keyed per channel; a
+	// channel may have a member override without any role override. Overrides
+	// from channels in other servers can surface here, but their channel IDs
+	// never appear in this server's list, so the extra entries are inert.
+	memberByChannel := make(map[string]models.ChannelPermissionOverride, len(memberOverrides))
+	for _, o := range memberOverrides {
+		memberByChannel[o.ChannelID] = o
+		if _, ok := byChannel[o.ChannelID]; !ok {
+			byChannel[o.ChannelID] = &channelOverride{}
+		}
+	}
+
 	hidden := make(map[string]bool)
 	granted := make(map[string]bool)
 
 	for channelID, co := range byChannel {
 		effective := (base & ^co.deny) | co.allow
+		if mo, ok := memberByChannel[channelID]; ok {
+			effective = (effective & ^mo.Deny) | mo.Allow
+		}
 		hasView := effective.Has(models.PermViewChannel)
 
 		if hasBaseView && !hasView {
@@ -326,37 +386,96 @@ func (s *channelPermService) ResolveChannelPermissions(ctx context.Context, user
 		roleIDs[i] = role.ID
 	}
 
-	// Admin bypasses all overrides
+	var effective models.Permission
 	if base.Has(models.PermAdmin) {
-		s.permCache.Set(cacheKey, models.PermAll)
-		return models.PermAll, nil
-	}
+		// Admin bypasses all overrides
+		effective = models.PermAll
+	} else {
+		overrides, err := s.permRepo.GetByChannelAndRoles(ctx, channelID, roleIDs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get channel overrides for roles: %w", err)
+		}
 
-	overrides, err := s.permRepo.GetByChannelAndRoles(ctx, channelID, roleIDs)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get channel overrides for roles: %w", err)
-	}
+		// OR all override allow/deny bits across user's roles.
+		// In the formula (base & ~deny) | allow, allow overrides deny for the same bit.
+		var channelAllow, channelDeny models.Permission
+		for _, o := range overrides {
+			channelAllow |= o.Allow
+			channelDeny |= o.Deny
+		}
 
-	if len(overrides) == 0 {
-		s.permCache.Set(cacheKey, base)
-		return base, nil
-	}
+		effective = (base & ^channelDeny) | channelAllow
 
-	// OR all override allow/deny bits across user's roles.
-	// In the formula (base & ~deny) | allow, allow overrides deny for the same bit.
-	var channelAllow, channelDeny models.Permission
-	for _, o := range overrides {
-		channelAllow |= o.Allow
-		channelDeny |= o.Deny
+		// Member-specific override applies last, on top of the role result —
+		// highest precedence short of Admin, so it can both carve a single user
+		// out of a role grant and let one user into an otherwise-hidden channel.
+		memberOv, err := s.permRepo.GetMemberOverride(ctx, channelID, userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get member override: %w", err)
+		}
+		if memberOv != nil {
+			effective = (effective & ^memberOv.Deny) | memberOv.Allow
+		}
 	}
 
-	effective := (base & ^channelDeny) | channelAllow
+	// Rules gate: until the member accepts the server rules they are read-only,
+	// whatever their roles say. Applied before caching, so acceptance must invalidate
+	// this user's entries (AcceptRules does).
+	if s.rulesGate != nil && effective != 0 {
+		pending, err := s.rulesGate.RulesPending(ctx, channel.ServerID, userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check rules gate: %w", err)
+		}
+		if pending {
+			effective &= models.PermReadOnly
+		}
+	}
 
 	s.permCache.Set(cacheKey, effective)
 
 	return effective, nil
 }
 
+// AllowedViewers filters candidates (typically the server's online users) down to those
+// allowed to see channel-scoped events. Decisions come from a per-channel cached set;
+// only users not yet in the set cost a permission resolution, so a busy channel settles
+// into pure map lookups per message instead of O(online users) resolver calls.
+func (s *channelPermService) AllowedViewers(ctx context.Context, channelID string, candidates []string) []string {
+	decisions, _ := s.viewerCache.Get(channelID)
+
+	var missing []string
+	for _, userID := range candidates {
+		if _, seen := decisions[userID]; !seen {
+			missing = append(missing, userID)
+		}
+	}
+
+	if len(missing) > 0 {
+		// Copy-on-write: extend a clone and re-Set so readers never see a map mid-write.
+		next := make(map[string]bool, len(decisions)+len(missing))
+		for userID, allowed := range decisions {
+			next[userID] = allowed
+		}
+		for _, userID := range missing {
+			perms, err := s.ResolveChannelPermissions(ctx, userID, channelID)
+			if err != nil {
+				continue // undecided — retried on the next broadcast
+			}
+			next[userID] = perms.Has(models.PermViewChannel) && perms.Has(models.PermReadMessages)
+		}
+		s.viewerCache.Set(channelID, next)
+		decisions = next
+	}
+
+	allowed := make([]string, 0, len(candidates))
+	for _, userID := range candidates {
+		if decisions[userID] {
+			allowed = append(allowed, userID)
+		}
+	}
+	return allowed
+}
+
 // invalidateChannelCache clears all cached permissions for a given channel.
 // Uses suffix match on "userID:channelID" keys since we can't know which users are affected.
 func (s *channelPermService) invalidateChannelCache(channelID string) {
@@ -364,6 +483,7 @@ func (s *channelPermService) invalidateChannelCache(channelID string) {
 	s.permCache.DeleteFunc(func(key string) bool {
 		return strings.HasSuffix(key, suffix)
 	})
+	s.viewerCache.Delete(channelID)
 }
 
 // InvalidateUserPermissions drops every cached entry for one user across all channels.
@@ -374,6 +494,11 @@ func (s *channelPermService) InvalidateUserPermissions(userID string) {
 	s.permCache.DeleteFunc(func(key string) bool {
 		return strings.HasPrefix(key, prefix)
 	})
+	// Viewer sets are keyed by channel, so scan values for the user.
+	s.viewerCache.DeleteValueFunc(func(_ string, set map[string]bool) bool {
+		_, ok := set[userID]
+		return ok
+	})
 }
 
 // InvalidateAllPermissions clears the entire permission cache. Used when a role's
@@ -383,4 +508,5 @@ func (s *channelPermService) InvalidateUserPermissions(userID string) {
 // next access) is an acceptable cost for correctness.
 func (s *channelPermService) InvalidateAllPermissions() {
 	s.permCache.Clear()
+	s.viewerCache.Clear()
 }
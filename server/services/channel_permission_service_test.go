@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/testutil"
 )
 
@@ -102,7 +103,7 @@ func TestResolveChannelPermissions(t *testing.T) {
 				{ID: "r1", Permissions: models.PermSendMessages | models.PermReadMessages},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: channelID, RoleID: "r1", Allow: 0, Deny: models.PermSendMessages},
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: 0, Deny: models.PermSendMessages},
 			},
 			want: models.PermReadMessages, // SendMessages stripped by deny
 		},
@@ -112,7 +113,7 @@ func TestResolveChannelPermissions(t *testing.T) {
 				{ID: "r1", Permissions: models.PermReadMessages},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: channelID, RoleID: "r1", Allow: models.PermSendMessages, Deny: 0},
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: models.PermSendMessages, Deny: 0},
 			},
 			want: models.PermReadMessages | models.PermSendMessages,
 		},
@@ -122,7 +123,7 @@ func TestResolveChannelPermissions(t *testing.T) {
 				{ID: "r1", Permissions: models.PermSendMessages | models.PermReadMessages},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: channelID, RoleID: "r1", Allow: models.PermSendMessages, Deny: models.PermSendMessages},
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: models.PermSendMessages, Deny: models.PermSendMessages},
 			},
 			// (base & ^deny) | allow => removes SendMessages then adds it back
 			want: models.PermSendMessages | models.PermReadMessages,
@@ -142,8 +143,8 @@ func TestResolveChannelPermissions(t *testing.T) {
 				{ID: "r1", Permissions: models.PermSendMessages | models.PermReadMessages | models.PermConnectVoice},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: channelID, RoleID: "r1", Allow: 0, Deny: models.PermSendMessages},
-				{ChannelID: channelID, RoleID: "r2", Allow: models.PermSpeak, Deny: models.PermConnectVoice},
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: 0, Deny: models.PermSendMessages},
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r2", Allow: models.PermSpeak, Deny: models.PermConnectVoice},
 			},
 			// channelDeny = SendMessages | ConnectVoice
 			// channelAllow = Speak
@@ -277,7 +278,7 @@ func TestBuildVisibilityFilter(t *testing.T) {
 				{ID: "r1", Permissions: models.PermViewChannel | models.PermSendMessages},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: "chan-secret", RoleID: "r1", Allow: 0, Deny: models.PermViewChannel},
+				{ChannelID: "chan-secret", TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: 0, Deny: models.PermViewChannel},
 			},
 			wantHasBaseView: true,
 			wantHidden:      map[string]bool{"chan-secret": true},
@@ -289,7 +290,7 @@ func TestBuildVisibilityFilter(t *testing.T) {
 				{ID: "r1", Permissions: models.PermSendMessages}, // no ViewChannel
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: "chan-special", RoleID: "r1", Allow: models.PermViewChannel, Deny: 0},
+				{ChannelID: "chan-special", TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: models.PermViewChannel, Deny: 0},
 			},
 			wantHasBaseView: false,
 			wantHidden:      map[string]bool{},
@@ -301,8 +302,8 @@ func TestBuildVisibilityFilter(t *testing.T) {
 				{ID: "r1", Permissions: models.PermViewChannel | models.PermSendMessages},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: "chan-hidden", RoleID: "r1", Allow: 0, Deny: models.PermViewChannel},
-				{ChannelID: "chan-visible", RoleID: "r1", Allow: models.PermSpeak, Deny: 0}, // no ViewChannel change
+				{ChannelID: "chan-hidden", TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: 0, Deny: models.PermViewChannel},
+				{ChannelID: "chan-visible", TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: models.PermSpeak, Deny: 0}, // no ViewChannel change
 			},
 			wantHasBaseView: true,
 			wantHidden:      map[string]bool{"chan-hidden": true},
@@ -323,8 +324,8 @@ func TestBuildVisibilityFilter(t *testing.T) {
 				{ID: "r2", Permissions: 0},
 			},
 			overrides: []models.ChannelPermissionOverride{
-				{ChannelID: "chan-1", RoleID: "r1", Allow: 0, Deny: 0},
-				{ChannelID: "chan-1", RoleID: "r2", Allow: models.PermViewChannel, Deny: 0},
+				{ChannelID: "chan-1", TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: 0, Deny: 0},
+				{ChannelID: "chan-1", TargetType: models.OverrideTargetRole, TargetID: "r2", Allow: models.PermViewChannel, Deny: 0},
 			},
 			wantHasBaseView: false,
 			wantHidden:      map[string]bool{},
@@ -424,3 +425,219 @@ func TestBuildVisibilityFilter_PermRepoError(t *testing.T) {
 		t.Fatal("expected error when perm repo fails")
 	}
 }
+
+// ─── AllowedViewers (broadcast fan-out cache) ───
+
+// The fan-out path must not resolve permissions per user per message: once a channel's
+// viewer set is warm, repeat broadcasts are pure cache hits. Invalidation hooks (override
+// change, user role change, full flush) must each drop the set.
+func TestAllowedViewers_CachesAndInvalidates(t *testing.T) {
+	const channelID, serverID = "chan-1", "srv-1"
+	resolves := 0
+	svc := newTestChannelPermService(
+		&testutil.MockChannelPermRepo{},
+		&testutil.MockRoleRepo{
+			GetByUserIDAndServerFn: func(_ context.Context, userID, _ string) ([]models.Role, error) {
+				resolves++
+				if userID == "blocked" {
+					return nil, nil // no roles -> no permissions
+				}
+				return []models.Role{{ID: "r1", ServerID: serverID, Permissions: models.PermViewChannel | models.PermReadMessages}}, nil
+			},
+		},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+				return &models.Channel{ID: channelID, ServerID: serverID}, nil
+			},
+		},
+		&testutil.MockBroadcaster{},
+	)
+	ctx := context.Background()
+	candidates := []string{"viewer", "blocked"}
+
+	allowed := svc.AllowedViewers(ctx, channelID, candidates)
+	if len(allowed) != 1 || allowed[0] != "viewer" {
+		t.Fatalf("expected only the permitted viewer, got %v", allowed)
+	}
+	if resolves != 2 {
+		t.Fatalf("cold cache should resolve each candidate once, resolved %d times", resolves)
+	}
+
+	// Warm cache: the same broadcast again must not resolve anyone.
+	svc.AllowedViewers(ctx, channelID, candidates)
+	if resolves != 2 {
+		t.Fatalf("warm cache should not re-resolve, resolved %d times", resolves)
+	}
+
+	// A new candidate costs exactly one resolution; the warm entries stay cached.
+	svc.AllowedViewers(ctx, channelID, []string{"viewer", "blocked", "late-joiner"})
+	if resolves != 3 {
+		t.Fatalf("only the new candidate should resolve, resolved %d times", resolves)
+	}
+
+	// Per-user invalidation drops the channel's set (it contains the user). Rebuilding it
+	// re-resolves only the invalidated user — the other candidate still hits the perm cache.
+	svc.InvalidateUserPermissions("viewer")
+	svc.AllowedViewers(ctx, channelID, candidates)
+	if resolves != 4 {
+		t.Fatalf("invalidated user should be re-resolved from roles, resolved %d times", resolves)
+	}
+}
+
+// ─── Member-specific overrides ───
+
+// Member overrides apply after role overrides: one user can be let into a channel
+// their roles hide, and one user can be carved out of a grant their roles carry.
+func TestResolveChannelPermissions_MemberOverride(t *testing.T) {
+	const (
+		userID    = "user-1"
+		channelID = "chan-1"
+		serverID  = "srv-1"
+	)
+
+	tests := []struct {
+		name      string
+		roles     []models.Role
+		overrides []models.ChannelPermissionOverride
+		memberOv  *models.ChannelPermissionOverride
+		want      models.Permission
+	}{
+		{
+			name: "member allow grants view in an otherwise-hidden channel",
+			roles: []models.Role{
+				{ID: "r1", Permissions: models.PermViewChannel | models.PermReadMessages},
+			},
+			overrides: []models.ChannelPermissionOverride{
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r1", Deny: models.PermViewChannel},
+			},
+			memberOv: &models.ChannelPermissionOverride{
+				ChannelID: channelID, TargetType: models.OverrideTargetMember, TargetID: userID,
+				Allow: models.PermViewChannel,
+			},
+			want: models.PermViewChannel | models.PermReadMessages,
+		},
+		{
+			name: "member deny strips a permission the roles grant",
+			roles: []models.Role{
+				{ID: "r1", Permissions: models.PermSendMessages | models.PermReadMessages},
+			},
+			memberOv: &models.ChannelPermissionOverride{
+				ChannelID: channelID, TargetType: models.OverrideTargetMember, TargetID: userID,
+				Deny: models.PermSendMessages,
+			},
+			want: models.PermReadMessages,
+		},
+		{
+			name: "member deny beats a role allow for the same bit",
+			roles: []models.Role{
+				{ID: "r1", Permissions: models.PermReadMessages},
+			},
+			overrides: []models.ChannelPermissionOverride{
+				{ChannelID: channelID, TargetType: models.OverrideTargetRole, TargetID: "r1", Allow: models.PermSendMessages},
+			},
+			memberOv: &models.ChannelPermissionOverride{
+				ChannelID: channelID, TargetType: models.OverrideTargetMember, TargetID: userID,
+				Deny: models.PermSendMessages,
+			},
+			want: models.PermReadMessages,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permRepo := &testutil.MockChannelPermRepo{
+				GetByChannelAndRolesFn: func(_ context.Context, _ string, _ []string) ([]models.ChannelPermissionOverride, error) {
+					return tt.overrides, nil
+				},
+				GetMemberOverrideFn: func(_ context.Context, _, uid string) (*models.ChannelPermissionOverride, error) {
+					if tt.memberOv != nil && uid == tt.memberOv.TargetID {
+						return tt.memberOv, nil
+					}
+					return nil, nil
+				},
+			}
+			roleRepo := &testutil.MockRoleRepo{
+				GetByUserIDAndServerFn: func(_ context.Context, _, _ string) ([]models.Role, error) {
+					return tt.roles, nil
+				},
+			}
+			channelRepo := &testutil.MockChannelRepo{
+				GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+					return &models.Channel{ID: channelID, ServerID: serverID}, nil
+				},
+			}
+
+			svc := newTestChannelPermService(permRepo, roleRepo, channelRepo, &testutil.MockBroadcaster{})
+			got, err := svc.ResolveChannelPermissions(context.Background(), userID, channelID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got permission %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// A member allow must reach the visibility filter too, or the channel list would
+// still omit a channel the user can in fact resolve permissions for.
+func TestBuildVisibilityFilter_MemberOverride(t *testing.T) {
+	const userID = "user-1"
+
+	roleRepo := &testutil.MockRoleRepo{
+		GetByUserIDAndServerFn: func(_ context.Context, _, _ string) ([]models.Role, error) {
+			return []models.Role{
+				{ID: "r1", Permissions: models.PermViewChannel | models.PermSendMessages},
+			}, nil
+		},
+	}
+	permRepo := &testutil.MockChannelPermRepo{
+		GetByRolesFn: func(_ context.Context, _ []string) ([]models.ChannelPermissionOverride, error) {
+			return []models.ChannelPermissionOverride{
+				// Both channels hidden from the role.
+				{ChannelID: "chan-a", TargetType: models.OverrideTargetRole, TargetID: "r1", Deny: models.PermViewChannel},
+				{ChannelID: "chan-b", TargetType: models.OverrideTargetRole, TargetID: "r1", Deny: models.PermViewChannel},
+			}, nil
+		},
+		GetByMemberFn: func(_ context.Context, _ string) ([]models.ChannelPermissionOverride, error) {
+			return []models.ChannelPermissionOverride{
+				// ...but this user is let back into chan-a, and additionally
+				// denied a channel with no role override at all.
+				{ChannelID: "chan-a", TargetType: models.OverrideTargetMember, TargetID: userID, Allow: models.PermViewChannel},
+				{ChannelID: "chan-c", TargetType: models.OverrideTargetMember, TargetID: userID, Deny: models.PermViewChannel},
+			}, nil
+		},
+	}
+
+	svc := newTestChannelPermService(permRepo, roleRepo, &testutil.MockChannelRepo{}, &testutil.MockBroadcaster{})
+	filter, err := svc.BuildVisibilityFilter(context.Background(), userID, "srv-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if filter.HiddenChannels["chan-a"] {
+		t.Error("chan-a should be visible — member allow overrides the role deny")
+	}
+	if !filter.HiddenChannels["chan-b"] {
+		t.Error("chan-b should stay hidden — no member override there")
+	}
+	if !filter.HiddenChannels["chan-c"] {
+		t.Error("chan-c should be hidden by the member-only deny")
+	}
+}
+
+// Target types outside role|member must be rejected before touching storage.
+func TestSetOverride_RejectsUnknownTargetType(t *testing.T) {
+	svc := newTestChannelPermService(
+		&testutil.MockChannelPermRepo{},
+		&testutil.MockRoleRepo{},
+		&testutil.MockChannelRepo{},
+		&testutil.MockBroadcaster{},
+	)
+
+	err := svc.SetOverride(context.Background(), "srv-1", "chan-1", "webhook", "x",
+		&models.SetOverrideRequest{Allow: models.PermSendMessages})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("SetOverride with unknown target type = %v, want ErrBadRequest", err)
+	}
+}
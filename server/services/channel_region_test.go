@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+func TestUpdateChannel_RegionHint(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("set, normalize and clear", func(t *testing.T) {
+		svc := newBitrateTestService(true, voiceChannelRepo("s1"))
+
+		hint := "  EU-Central "
+		ch, err := svc.Update(ctx, "s1", "c1", &models.UpdateChannelRequest{RegionHint: &hint})
+		if err != nil {
+			t.Fatalf("set region hint: %v", err)
+		}
+		if ch.RegionHint == nil || *ch.RegionHint != "eu-central" {
+			t.Fatalf("RegionHint = %v, want normalized \"eu-central\"", ch.RegionHint)
+		}
+
+		clear := ""
+		ch, err = svc.Update(ctx, "s1", "c1", &models.UpdateChannelRequest{RegionHint: &clear})
+		if err != nil {
+			t.Fatalf("clear region hint: %v", err)
+		}
+		if ch.RegionHint != nil {
+			t.Fatalf("RegionHint = %q, want nil after clearing", *ch.RegionHint)
+		}
+	})
+
+	t.Run("rejected on text channels", func(t *testing.T) {
+		textRepo := &testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "s1", Type: models.ChannelTypeText}, nil
+			},
+		}
+		svc := newBitrateTestService(true, textRepo)
+
+		hint := "eu-central"
+		_, err := svc.Update(ctx, "s1", "c1", &models.UpdateChannelRequest{RegionHint: &hint})
+		if !errors.Is(err, pkg.ErrBadRequest) {
+			t.Fatalf("expected bad request for region hint on a text channel, got %v", err)
+		}
+	})
+
+	t.Run("malformed hints are rejected", func(t *testing.T) {
+		svc := newBitrateTestService(true, voiceChannelRepo("s1"))
+
+		for _, bad := range []string{"x", "Nürnberg", "eu central", "a-very-long-region-token-that-keeps-going"} {
+			hint := bad
+			if _, err := svc.Update(ctx, "s1", "c1", &models.UpdateChannelRequest{RegionHint: &hint}); !errors.Is(err, pkg.ErrBadRequest) {
+				t.Errorf("hint %q: expected bad request, got %v", bad, err)
+			}
+		}
+	})
+}
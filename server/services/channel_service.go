@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
@@ -30,6 +31,14 @@ type VoiceChannelDisconnector interface {
 	DisconnectUser(userID string)
 }
 
+// VoiceBitrateLimits is the instance bitrate policy for voice channels, split by
+// host type (mirrors config.VoiceBitrateConfig). A zero max means uncapped.
+type VoiceBitrateLimits struct {
+	Min           int
+	HostedMax     int
+	SelfHostedMax int
+}
+
 type ChannelVisibilityFilter struct {
 	IsAdmin         bool
 	HasBaseView     bool
@@ -53,42 +62,90 @@ func (f *ChannelVisibilityFilter) CanSee(channelID string) bool {
 // ChannelService handles channel CRUD. All list operations are server-scoped.
 type ChannelService interface {
 	GetAllGrouped(ctx context.Context, serverID, userID string) ([]models.CategoryWithChannels, error)
-	Create(ctx context.Context, serverID string, req *models.CreateChannelRequest) (*models.Channel, error)
+	// GetAllGroupedWithMeta is GetAllGrouped plus optional per-channel unread and
+	// pin counts (GET ...?include=unread,pins), so the client renders the full
+	// sidebar in one request instead of three.
+	GetAllGroupedWithMeta(ctx context.Context, serverID, userID string, includeUnread, includePins bool) ([]models.CategoryWithChannels, error)
+	Create(ctx context.Context, serverID, actorID string, req *models.CreateChannelRequest) (*models.Channel, error)
+	// Clone creates a copy of a channel — settings and permission overrides
+	// included — placed at the end of the source's category. Admin convenience
+	// for stamping out templated channel sets.
+	Clone(ctx context.Context, serverID, actorID, id string) (*models.Channel, error)
 	Update(ctx context.Context, serverID string, id string, req *models.UpdateChannelRequest) (*models.Channel, error)
-	Delete(ctx context.Context, serverID string, id string) error
+	Delete(ctx context.Context, serverID, actorID string, id string) error
 	ReorderChannels(ctx context.Context, serverID string, req *models.ReorderChannelsRequest, userID string) ([]models.CategoryWithChannels, error)
+	// SetAuditRecorder wires the audit log post-construction.
+	SetAuditRecorder(rec AuditRecorder)
+	// SetSystemMessenger wires the creation-notice writer post-construction.
+	SetSystemMessenger(m SystemMessenger)
+	// SetVoiceChatCleanup installs a callback that purges a deleted voice
+	// channel's text chat. Set post-construction; nil-safe if left unwired.
+	SetVoiceChatCleanup(fn func(channelID string))
 }
 
 type channelService struct {
-	channelRepo   repository.ChannelRepository
-	categoryRepo  repository.CategoryRepository
-	hub           ws.Broadcaster
-	visChecker    ChannelVisibilityChecker
-	voiceProvider UserVoiceChannelProvider
-	voiceDisc     VoiceChannelDisconnector
-	fileCleanup   FileCleanupService
+	channelRepo     repository.ChannelRepository
+	categoryRepo    repository.CategoryRepository
+	readStateRepo   repository.ReadStateRepository
+	pinRepo         repository.PinRepository
+	serverRepo      repository.ServerRepository
+	livekitRepo     repository.LiveKitRepository
+	channelPermRepo repository.ChannelPermissionRepository
+	hub             ws.Broadcaster
+	visChecker      ChannelVisibilityChecker
+	voiceProvider   UserVoiceChannelProvider
+	voiceDisc       VoiceChannelDisconnector
+	fileCleanup     FileCleanupService
+	bitrateLimits   VoiceBitrateLimits
+	audit           AuditRecorder          // set post-construction, may be nil
+	voiceChatWipe   func(channelID string) // set post-construction, may be nil
+	systemMessenger SystemMessenger        // set post-construction, may be nil
 }
 
 func NewChannelService(
 	channelRepo repository.ChannelRepository,
 	categoryRepo repository.CategoryRepository,
+	readStateRepo repository.ReadStateRepository,
+	pinRepo repository.PinRepository,
+	serverRepo repository.ServerRepository,
+	livekitRepo repository.LiveKitRepository,
+	channelPermRepo repository.ChannelPermissionRepository,
 	hub ws.Broadcaster,
 	visChecker ChannelVisibilityChecker,
 	voiceProvider UserVoiceChannelProvider,
 	voiceDisc VoiceChannelDisconnector,
 	fileCleanup FileCleanupService,
+	bitrateLimits VoiceBitrateLimits,
 ) ChannelService {
 	return &channelService{
-		channelRepo:   channelRepo,
-		categoryRepo:  categoryRepo,
-		hub:           hub,
-		visChecker:    visChecker,
-		voiceProvider: voiceProvider,
-		voiceDisc:     voiceDisc,
-		fileCleanup:   fileCleanup,
+		channelRepo:     channelRepo,
+		categoryRepo:    categoryRepo,
+		readStateRepo:   readStateRepo,
+		pinRepo:         pinRepo,
+		serverRepo:      serverRepo,
+		livekitRepo:     livekitRepo,
+		channelPermRepo: channelPermRepo,
+		hub:             hub,
+		visChecker:      visChecker,
+		voiceProvider:   voiceProvider,
+		voiceDisc:       voiceDisc,
+		fileCleanup:     fileCleanup,
+		bitrateLimits:   bitrateLimits,
 	}
 }
 
+func (s *channelService) SetAuditRecorder(rec AuditRecorder) {
+	s.audit = rec
+}
+
+func (s *channelService) SetSystemMessenger(m SystemMessenger) {
+	s.systemMessenger = m
+}
+
+func (s *channelService) SetVoiceChatCleanup(fn func(channelID string)) {
+	s.voiceChatWipe = fn
+}
+
 func (s *channelService) GetAllGrouped(ctx context.Context, serverID, userID string) ([]models.CategoryWithChannels, error) {
 	categories, err := s.categoryRepo.GetAllByServer(ctx, serverID)
 	if err != nil {
@@ -155,13 +212,67 @@ func (s *channelService) GetAllGrouped(ctx context.Context, serverID, userID str
 	return result, nil
 }
 
-func (s *channelService) Create(ctx context.Context, serverID string, req *models.CreateChannelRequest) (*models.Channel, error) {
+// GetAllGroupedWithMeta decorates GetAllGrouped's tree with per-channel unread
+// and pin counts. Each include costs one server-scoped query, fetched only when
+// asked for. Counts only decorate channels that already passed the visibility
+// filter, so the repo-level lookups cannot leak hidden channels.
+func (s *channelService) GetAllGroupedWithMeta(ctx context.Context, serverID, userID string, includeUnread, includePins bool) ([]models.CategoryWithChannels, error) {
+	grouped, err := s.GetAllGrouped(ctx, serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !includeUnread && !includePins {
+		return grouped, nil
+	}
+
+	var unreadByChannel map[string]int
+	if includeUnread {
+		infos, err := s.readStateRepo.GetUnreadCounts(ctx, userID, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get unread counts: %w", err)
+		}
+		unreadByChannel = make(map[string]int, len(infos))
+		for _, info := range infos {
+			unreadByChannel[info.ChannelID] = info.UnreadCount
+		}
+	}
+
+	var pinsByChannel map[string]int
+	if includePins {
+		pinsByChannel, err = s.pinRepo.CountsByServerID(ctx, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pin counts: %w", err)
+		}
+	}
+
+	for ci := range grouped {
+		for i := range grouped[ci].Channels {
+			ch := &grouped[ci].Channels[i]
+			if includeUnread {
+				n := unreadByChannel[ch.ID]
+				ch.UnreadCount = &n
+			}
+			if includePins {
+				n := pinsByChannel[ch.ID]
+				ch.PinCount = &n
+			}
+		}
+	}
+
+	return grouped, nil
+}
+
+func (s *channelService) Create(ctx context.Context, serverID, actorID string, req *models.CreateChannelRequest) (*models.Channel, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
 
 	if req.CategoryID != "" {
-		if _, err := s.categoryRepo.GetByID(ctx, req.CategoryID); err != nil {
+		// The target category must exist AND live in the same server — same
+		// guard as Update's reassignment, or a crafted create could file the
+		// channel under another server's category.
+		cat, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
+		if err != nil || cat == nil || cat.ServerID != serverID {
 			return nil, fmt.Errorf("%w: category not found", pkg.ErrBadRequest)
 		}
 	}
@@ -172,10 +283,11 @@ func (s *channelService) Create(ctx context.Context, serverID string, req *model
 	}
 
 	channel := &models.Channel{
-		ServerID: serverID,
-		Name:     req.Name,
-		Type:     models.ChannelType(req.Type),
-		Position: maxPos + 1,
+		ServerID:         serverID,
+		Name:             req.Name,
+		Type:             models.ChannelType(req.Type),
+		Position:         maxPos + 1,
+		PinAnnouncements: true,
 	}
 
 	if req.CategoryID != "" {
@@ -185,7 +297,25 @@ func (s *channelService) Create(ctx context.Context, serverID string, req *model
 		channel.Topic = &req.Topic
 	}
 	if channel.Type == models.ChannelTypeVoice {
-		channel.Bitrate = 64000
+		maxBitrate, err := s.maxBitrateFor(ctx, serverID)
+		if err != nil {
+			return nil, err
+		}
+		bitrate := req.Bitrate
+		if bitrate == 0 {
+			// Default, clamped so a ceiling below it can't make plain channel
+			// creation fail.
+			bitrate = models.DefaultVoiceBitrate
+			if maxBitrate > 0 && maxBitrate < bitrate {
+				bitrate = maxBitrate
+			}
+		} else if err := checkBitrateBounds(bitrate, s.bitrateLimits.Min, maxBitrate); err != nil {
+			return nil, err
+		}
+		channel.Bitrate = bitrate
+		if req.RegionHint != "" {
+			channel.RegionHint = &req.RegionHint
+		}
 	}
 
 	if err := s.channelRepo.Create(ctx, channel); err != nil {
@@ -197,9 +327,123 @@ func (s *channelService) Create(ctx context.Context, serverID string, req *model
 		Data: nil,
 	})
 
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionChannelCreate, channel.ID,
+			map[string]string{"name": channel.Name, "type": string(channel.Type)})
+	}
+
+	// Open the new channel's timeline with a creation notice. Text channels
+	// only — voice channel chat is for in-call talk.
+	if channel.Type == models.ChannelTypeText && s.systemMessenger != nil {
+		if _, err := s.systemMessenger.CreateSystemMessage(ctx, channel.ID, actorID, models.MessageTypeChannelCreate, "created the channel", nil); err != nil {
+			log.Printf("[channel] failed to post creation message (channel=%s): %v", channel.ID, err)
+		}
+	}
+
 	return channel, nil
 }
 
+func (s *channelService) Clone(ctx context.Context, serverID, actorID, id string) (*models.Channel, error) {
+	source, err := s.channelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// IDOR guard: the channel must belong to the route's server.
+	if source == nil || source.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+
+	categoryID := ""
+	if source.CategoryID != nil {
+		categoryID = *source.CategoryID
+	}
+	maxPos, err := s.channelRepo.GetMaxPosition(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get max position: %w", err)
+	}
+
+	// Every channel-level setting carries over; only identity and position are
+	// new. Messages, pins and read states stay with the source.
+	clone := &models.Channel{
+		ServerID:           serverID,
+		Name:               source.Name,
+		Type:               source.Type,
+		CategoryID:         source.CategoryID,
+		Topic:              source.Topic,
+		Position:           maxPos + 1,
+		UserLimit:          source.UserLimit,
+		Bitrate:            source.Bitrate,
+		RegionHint:         source.RegionHint,
+		PinAnnouncements:   source.PinAnnouncements,
+		SlowmodeSeconds:    source.SlowmodeSeconds,
+		DeleteAfterSeconds: source.DeleteAfterSeconds,
+	}
+
+	if err := s.channelRepo.Create(ctx, clone); err != nil {
+		return nil, fmt.Errorf("failed to create channel clone: %w", err)
+	}
+
+	// Copy the permission overrides before anyone hears about the clone, so no
+	// client window exists where it is visible with the wrong permissions.
+	overrides, err := s.channelPermRepo.GetByChannel(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source channel overrides: %w", err)
+	}
+	for i := range overrides {
+		overrides[i].ChannelID = clone.ID
+		if err := s.channelPermRepo.Set(ctx, &overrides[i]); err != nil {
+			return nil, fmt.Errorf("failed to copy channel override: %w", err)
+		}
+	}
+
+	s.hub.BroadcastToAll(ws.Event{
+		Op: ws.OpChannelCreate,
+		Data: map[string]any{
+			"channel":   clone,
+			"overrides": overrides,
+		},
+	})
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionChannelCreate, clone.ID,
+			map[string]string{"name": clone.Name, "type": string(clone.Type), "cloned_from": source.ID})
+	}
+
+	return clone, nil
+}
+
+// maxBitrateFor resolves the bitrate ceiling for a server by how its voice is
+// hosted: a platform-managed LiveKit instance gets the mqvi-hosted ceiling,
+// anything else (own instance, or no voice at all) the self-hosted one. 0 = uncapped.
+func (s *channelService) maxBitrateFor(ctx context.Context, serverID string) (int, error) {
+	server, err := s.serverRepo.GetByID(ctx, serverID)
+	if err != nil {
+		return 0, err
+	}
+	if server.LiveKitInstanceID == nil {
+		return s.bitrateLimits.SelfHostedMax, nil
+	}
+	instance, err := s.livekitRepo.GetByID(ctx, *server.LiveKitInstanceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get livekit instance: %w", err)
+	}
+	if instance.IsPlatformManaged {
+		return s.bitrateLimits.HostedMax, nil
+	}
+	return s.bitrateLimits.SelfHostedMax, nil
+}
+
+// checkBitrateBounds rejects a requested bitrate outside [min, max]; max 0 = uncapped.
+func checkBitrateBounds(bitrate, min, max int) error {
+	if bitrate < min {
+		return fmt.Errorf("%w: bitrate must be at least %d", pkg.ErrBadRequest, min)
+	}
+	if max > 0 && bitrate > max {
+		return fmt.Errorf("%w: bitrate exceeds this server's host-type ceiling of %d", pkg.ErrBadRequest, max)
+	}
+	return nil
+}
+
 func (s *channelService) Update(ctx context.Context, serverID string, id string, req *models.UpdateChannelRequest) (*models.Channel, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
@@ -218,7 +462,9 @@ func (s *channelService) Update(ctx context.Context, serverID string, id string,
 		channel.Name = *req.Name
 	}
 	if req.Topic != nil {
-		channel.Topic = req.Topic
+		// An empty string clears the topic — stored as NULL so channels that never
+		// had one and channels whose topic was removed look the same.
+		channel.Topic = nilIfEmpty(*req.Topic)
 	}
 	if req.CategoryID != nil {
 		if *req.CategoryID == "" {
@@ -232,6 +478,44 @@ func (s *channelService) Update(ctx context.Context, serverID string, id string,
 			channel.CategoryID = req.CategoryID
 		}
 	}
+	if req.PinAnnouncements != nil {
+		channel.PinAnnouncements = *req.PinAnnouncements
+	}
+	if req.SlowmodeSeconds != nil {
+		if channel.Type != models.ChannelTypeText {
+			return nil, fmt.Errorf("%w: slowmode only applies to text channels", pkg.ErrBadRequest)
+		}
+		channel.SlowmodeSeconds = *req.SlowmodeSeconds
+	}
+	if req.DeleteAfterSeconds != nil {
+		if channel.Type != models.ChannelTypeText {
+			return nil, fmt.Errorf("%w: message auto-delete only applies to text channels", pkg.ErrBadRequest)
+		}
+		channel.DeleteAfterSeconds = *req.DeleteAfterSeconds
+	}
+	if req.Bitrate != nil {
+		if channel.Type != models.ChannelTypeVoice {
+			return nil, fmt.Errorf("%w: bitrate only applies to voice channels", pkg.ErrBadRequest)
+		}
+		maxBitrate, err := s.maxBitrateFor(ctx, serverID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBitrateBounds(*req.Bitrate, s.bitrateLimits.Min, maxBitrate); err != nil {
+			return nil, err
+		}
+		channel.Bitrate = *req.Bitrate
+	}
+	if req.RegionHint != nil {
+		if channel.Type != models.ChannelTypeVoice {
+			return nil, fmt.Errorf("%w: region hint only applies to voice channels", pkg.ErrBadRequest)
+		}
+		if *req.RegionHint == "" {
+			channel.RegionHint = nil
+		} else {
+			channel.RegionHint = req.RegionHint
+		}
+	}
 
 	if err := s.channelRepo.Update(ctx, channel); err != nil {
 		return nil, err
@@ -245,7 +529,7 @@ func (s *channelService) Update(ctx context.Context, serverID string, id string,
 	return channel, nil
 }
 
-func (s *channelService) Delete(ctx context.Context, serverID string, id string) error {
+func (s *channelService) Delete(ctx context.Context, serverID, actorID string, id string) error {
 	// IDOR guard: the channel must belong to the route's server.
 	channel, err := s.channelRepo.GetByID(ctx, id)
 	if err != nil {
@@ -283,6 +567,17 @@ func (s *channelService) Delete(ctx context.Context, serverID string, id string)
 		}
 	}
 
+	// Voice chat history persists across empty channels, so the only thing
+	// that takes it down is the channel itself going away.
+	if channel.Type == models.ChannelTypeVoice && s.voiceChatWipe != nil {
+		s.voiceChatWipe(id)
+	}
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionChannelDelete, id,
+			map[string]string{"name": channel.Name})
+	}
+
 	return nil
 }
 
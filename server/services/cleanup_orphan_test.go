@@ -75,7 +75,7 @@ func orphanWalker(db *sql.DB, uploadDir string) *cleanupService {
 	return &cleanupService{
 		db:          db,
 		uploadDir:   uploadDir,
-		fileDeleter: files.NewLocator(uploadDir, ""),
+		fileDeleter: files.NewLocator(uploadDir, "", 0),
 		appLog:      stubAppLog{},
 	}
 }
@@ -205,7 +205,13 @@ var notStoredFiles = map[string]string{
 	"link_previews.url":             "remote page being previewed",
 	"link_previews.image_url":       "remote Open Graph image",
 	"link_previews.favicon_url":     "remote favicon",
+	"message_embeds.url":            "remote page the message linked to",
+	"message_embeds.image_url":      "remote Open Graph image",
+	"message_embeds.favicon_url":    "remote favicon",
 	"livekit_instances.url":         "the SFU's address, not a file",
+	"webhooks.avatar_url":           "integration-supplied remote image, never uploaded here",
+	"messages.webhook_avatar_url":   "copy of the webhook's remote avatar at send time",
+	"voice_recordings.file_url":     "written by the LiveKit egress service to its own storage",
 }
 
 // The kind test above catches a new files.Kind. This catches the other shape: a new file
@@ -65,6 +65,17 @@ type ServerExpirer interface {
 	ExpireSoftDeletedServer(ctx context.Context, serverID string) error
 }
 
+// ExpiredInviteDeleter narrows InviteRepository to the one method the worker needs.
+type ExpiredInviteDeleter interface {
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// ExpiredExportDeleter is the sweep-driven half of ExportService: reclaims
+// export archives older than their TTL along with their job rows.
+type ExpiredExportDeleter interface {
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
 type cleanupService struct {
 	db            *sql.DB
 	cleanupRepo   repository.CleanupRepository
@@ -73,6 +84,8 @@ type cleanupService struct {
 	serverLister  SoftDeletedServerLister
 	userExpirer   UserExpirer
 	serverExpirer ServerExpirer
+	inviteDeleter ExpiredInviteDeleter
+	exportDeleter ExpiredExportDeleter
 	fileDeleter   FileDeleter
 	appLog        AppLogService
 	uploadDir     string
@@ -96,6 +109,8 @@ func NewCleanupService(
 	serverLister SoftDeletedServerLister,
 	userExpirer UserExpirer,
 	serverExpirer ServerExpirer,
+	inviteDeleter ExpiredInviteDeleter,
+	exportDeleter ExpiredExportDeleter,
 	fileDeleter FileDeleter,
 	appLog AppLogService,
 	uploadDir string,
@@ -110,6 +125,8 @@ func NewCleanupService(
 		serverLister:  serverLister,
 		userExpirer:   userExpirer,
 		serverExpirer: serverExpirer,
+		inviteDeleter: inviteDeleter,
+		exportDeleter: exportDeleter,
 		fileDeleter:   fileDeleter,
 		appLog:        appLog,
 		uploadDir:     uploadDir,
@@ -203,6 +220,8 @@ type runStats struct {
 	usersFailed        int
 	serversExpired     int
 	serversFailed      int
+	invitesDeleted     int
+	exportsDeleted     int
 	orphansDeleted     int
 	orphansEnqueued    int
 	quarantineDeleted  int
@@ -220,6 +239,8 @@ func (s *cleanupService) RunOnce(ctx context.Context) error {
 	s.processRetryQueue(ctx, &st)
 	s.expireUsers(ctx, &st)
 	s.expireServers(ctx, &st)
+	s.deleteExpiredInvites(ctx, &st)
+	s.deleteExpiredExports(ctx, &st)
 	s.cleanQuarantine(ctx, &st)
 	s.pruneScanCache(ctx, &st)
 	s.walkOrphans(ctx, &st)
@@ -233,9 +254,10 @@ func (s *cleanupService) RunOnce(ctx context.Context) error {
 		level = models.LogLevelWarn
 	}
 	msg := fmt.Sprintf(
-		"cleanup sweep: users=%d/%d servers=%d/%d retries=%d/%d (gaveup=%d) quarantine=%d scan_cache=%d orphans=%d (queued=%d) duration=%s",
+		"cleanup sweep: users=%d/%d servers=%d/%d invites=%d exports=%d retries=%d/%d (gaveup=%d) quarantine=%d scan_cache=%d orphans=%d (queued=%d) duration=%s",
 		st.usersExpired, st.usersExpired+st.usersFailed,
 		st.serversExpired, st.serversExpired+st.serversFailed,
+		st.invitesDeleted, st.exportsDeleted,
 		st.retriesSucceeded, st.retriesProcessed, st.retriesGaveUp,
 		st.quarantineDeleted, st.scanCachePruned,
 		st.orphansDeleted, st.orphansEnqueued,
@@ -246,6 +268,8 @@ func (s *cleanupService) RunOnce(ctx context.Context) error {
 		"users_failed":       itoa(st.usersFailed),
 		"servers_expired":    itoa(st.serversExpired),
 		"servers_failed":     itoa(st.serversFailed),
+		"invites_deleted":    itoa(st.invitesDeleted),
+		"exports_deleted":    itoa(st.exportsDeleted),
 		"retries_processed":  itoa(st.retriesProcessed),
 		"retries_succeeded":  itoa(st.retriesSucceeded),
 		"retries_gaveup":     itoa(st.retriesGaveUp),
@@ -350,6 +374,30 @@ func (s *cleanupService) processRetryQueue(ctx context.Context, st *runStats) {
 	}
 }
 
+func (s *cleanupService) deleteExpiredInvites(ctx context.Context, st *runStats) {
+	if s.inviteDeleter == nil {
+		return
+	}
+	n, err := s.inviteDeleter.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("[cleanup] delete expired invites failed: %v", err)
+		return
+	}
+	st.invitesDeleted = n
+}
+
+func (s *cleanupService) deleteExpiredExports(ctx context.Context, st *runStats) {
+	if s.exportDeleter == nil {
+		return
+	}
+	n, err := s.exportDeleter.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		log.Printf("[cleanup] delete expired exports failed: %v", err)
+		return
+	}
+	st.exportsDeleted = n
+}
+
 func (s *cleanupService) expireUsers(ctx context.Context, st *runStats) {
 	users, err := s.userLister.ListSoftDeletedExpired(ctx, models.SoftDeleteTTLDays)
 	if err != nil {
@@ -431,9 +479,19 @@ func (s *cleanupService) walkOrphans(ctx context.Context, st *runStats) {
 			return nil
 		}
 		parts := strings.Split(filepath.ToSlash(rel), "/")
-		if len(parts) != 3 {
-			// Skip anything outside the canonical kind/scope/filename layout
-			// (legacy /api/uploads/* if any survived migration, partial uploads).
+		// Canonical layout is kind/shard/scope/filename; pre-shard files still at
+		// kind/scope/filename are swept too (lazy migration only moves files that
+		// get served). Anything else (legacy /api/uploads/*, partial uploads) is
+		// skipped.
+		switch len(parts) {
+		case 4:
+			if parts[1] != files.ShardOf(parts[2]) {
+				return nil
+			}
+			parts = []string{parts[0], parts[2], parts[3]}
+		case 3:
+			// pre-shard flat layout
+		default:
 			return nil
 		}
 		// Only sweep a kind we know how to prove live. An unrecognised or unregistered kind
@@ -102,7 +102,7 @@ func TestCrossServerOwnership_ForeignResourcesAreRefused(t *testing.T) {
 		}},
 		{"channel delete", func() error {
 			s := &channelService{channelRepo: channelOn(foreignServer)}
-			return s.Delete(ctx, routeServer, "channel-1")
+			return s.Delete(ctx, routeServer, "actor", "channel-1")
 		}},
 		{"channel overrides read", func() error {
 			s := &channelPermService{channelGetter: channelOn(foreignServer)}
@@ -111,11 +111,11 @@ func TestCrossServerOwnership_ForeignResourcesAreRefused(t *testing.T) {
 		}},
 		{"channel override write", func() error {
 			s := &channelPermService{channelGetter: channelOn(foreignServer)}
-			return s.SetOverride(ctx, routeServer, "channel-1", "role-1", &models.SetOverrideRequest{Allow: models.PermReadMessages})
+			return s.SetOverride(ctx, routeServer, "channel-1", models.OverrideTargetRole, "role-1", &models.SetOverrideRequest{Allow: models.PermReadMessages})
 		}},
 		{"channel override delete", func() error {
 			s := &channelPermService{channelGetter: channelOn(foreignServer)}
-			return s.DeleteOverride(ctx, routeServer, "channel-1", "role-1")
+			return s.DeleteOverride(ctx, routeServer, "channel-1", models.OverrideTargetRole, "role-1")
 		}},
 		{"pin", func() error {
 			s := &pinService{
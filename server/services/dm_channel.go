@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
@@ -69,6 +70,29 @@ func (s *dmService) GetOrCreateChannel(ctx context.Context, userID, otherUserID
 		}
 	}
 
+	// shared_servers: the requester must share at least one server with the
+	// target. Friends pass too — friendship is the stricter relationship.
+	if !isPlatformAdmin && otherUser.DMPrivacy == "shared_servers" {
+		allowed := false
+		if s.friendChecker != nil {
+			friends, err := s.friendChecker.AreFriends(ctx, userID, otherUserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check friendship: %w", err)
+			}
+			allowed = friends
+		}
+		if !allowed && s.sharedChecker != nil {
+			shared, err := s.sharedChecker.HaveSharedServer(ctx, userID, otherUserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check shared servers: %w", err)
+			}
+			allowed = shared
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: this user only accepts messages from people in their servers", pkg.ErrForbidden)
+		}
+	}
+
 	// Channel always starts as "accepted" — pending status is set on first message in SendMessage
 	channel := &models.DMChannel{
 		User1ID: user1,
@@ -126,6 +150,30 @@ func (s *dmService) ListChannels(ctx context.Context, userID string) ([]models.D
 	return channels, nil
 }
 
+// UnreadSummaries reuses the ListChannels query (which already computes unread
+// counts) and keeps only conversations with something unread — counts and the
+// latest activity, never message history.
+func (s *dmService) UnreadSummaries(ctx context.Context, userID string) ([]models.DMUnreadSummary, error) {
+	channels, err := s.dmRepo.ListChannels(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.DMUnreadSummary, 0)
+	for _, ch := range channels {
+		if ch.UnreadCount == 0 || ch.OtherUser == nil {
+			continue
+		}
+		summaries = append(summaries, models.DMUnreadSummary{
+			DMChannelID:   ch.ID,
+			OtherUserID:   ch.OtherUser.ID,
+			UnreadCount:   ch.UnreadCount,
+			LastMessageAt: ch.LastMessageAt,
+		})
+	}
+	return summaries, nil
+}
+
 // MarkRead records that the user has read this conversation up to messageID (empty means
 // all of it), then tells their other devices so the badge and any delivered notification
 // clear there too. Reading on the desktop is what should silence the phone.
@@ -134,13 +182,13 @@ func (s *dmService) ListChannels(ctx context.Context, userID string) ([]models.D
 // arrive between the client choosing a watermark and this write landing, and reporting an
 // optimistic zero would hide it.
 func (s *dmService) MarkRead(ctx context.Context, userID, channelID, messageID string) (int, error) {
-	if _, err := s.verifyChannelMembership(ctx, userID, channelID); err != nil {
+	channel, err := s.verifyChannelMembership(ctx, userID, channelID)
+	if err != nil {
 		return 0, err
 	}
 
 	// No message named: the client is clearing a conversation it hasn't loaded.
 	var moved bool
-	var err error
 	if messageID == "" {
 		moved, err = s.dmRepo.MarkReadLatest(ctx, userID, channelID)
 	} else {
@@ -171,6 +219,29 @@ func (s *dmService) MarkRead(ctx context.Context, userID, channelID, messageID s
 		},
 	})
 
+	// Read receipt for the pair partner — deliberately a different op from OpDMRead:
+	// that one clears the reader's own badge, this one tells the sender their message
+	// was seen. read_at is "now" because the broadcast only fires when the watermark
+	// moved, i.e. at the moment of reading. Groups get none (per-member receipts for a
+	// ten-person conversation are a different feature).
+	if !channel.IsGroup {
+		if state, stateErr := s.dmRepo.GetReadState(ctx, userID, channelID); stateErr == nil && state != nil {
+			otherUserID := channel.User1ID
+			if otherUserID == userID {
+				otherUserID = channel.User2ID
+			}
+			s.hub.BroadcastToUser(otherUserID, ws.Event{
+				Op: ws.OpDMReadReceipt,
+				Data: map[string]any{
+					"dm_channel_id":        channelID,
+					"user_id":              userID,
+					"last_read_message_id": state.LastReadMessageID,
+					"read_at":              time.Now().UTC(),
+				},
+			})
+		}
+	}
+
 	// Devices with no live socket keep showing the notification until they are opened;
 	// a data push retracts it there. Only worth sending once nothing is left unread.
 	if unread == 0 && s.pushNotifier != nil {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/ws"
 )
 
@@ -18,6 +19,24 @@ func (s *dmService) ToggleReaction(ctx context.Context, userID, messageID, emoji
 		return err
 	}
 
+	// Same limits as server-channel reactions; removing is always allowed.
+	existing, err := s.dmRepo.GetReactionsByMessageID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get reactions before toggle: %w", err)
+	}
+	if adds, newEmoji := reactionToggleAdds(existing, userID, emoji); adds {
+		if newEmoji && len(existing) >= s.maxDistinctReactions {
+			return fmt.Errorf("%w: this message has reached its limit of %d different reactions", pkg.ErrBadRequest, s.maxDistinctReactions)
+		}
+		userCount, countErr := s.dmRepo.CountUserReactions(ctx, messageID, userID)
+		if countErr != nil {
+			return fmt.Errorf("failed to count user reactions: %w", countErr)
+		}
+		if userCount >= s.maxReactionsPerUser {
+			return fmt.Errorf("%w: you can add at most %d reactions to one message", pkg.ErrBadRequest, s.maxReactionsPerUser)
+		}
+	}
+
 	_, err = s.dmRepo.ToggleReaction(ctx, messageID, userID, emoji)
 	if err != nil {
 		return fmt.Errorf("failed to toggle DM reaction: %w", err)
@@ -28,25 +47,60 @@ func (s *dmService) ToggleReaction(ctx context.Context, userID, messageID, emoji
 		return fmt.Errorf("failed to get updated reactions: %w", err)
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op: ws.OpDMReactionUpdate,
 		Data: map[string]any{
 			"dm_message_id": messageID,
 			"dm_channel_id": msg.DMChannelID,
-			"reactions":     reactions,
+			"reactions":     models.PreviewReactions(reactions),
 		},
 	})
 
 	return nil
 }
 
+// ListReactors returns the paginated user list for one emoji on a DM message.
+// Membership of the DM channel is the whole access check.
+func (s *dmService) ListReactors(ctx context.Context, userID, messageID, emoji string, limit, offset int) (*models.ReactionUsersPage, error) {
+	if emoji == "" {
+		return nil, fmt.Errorf("%w: emoji is required", pkg.ErrBadRequest)
+	}
+
+	if _, _, err := s.verifyMessageAccess(ctx, userID, messageID); err != nil {
+		return nil, err
+	}
+
+	users, total, err := s.dmRepo.ListReactors(ctx, messageID, emoji, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DM reactors: %w", err)
+	}
+	for i := range users {
+		users[i].AvatarURL = s.urlSigner.SignURLPtr(users[i].AvatarURL)
+	}
+
+	return &models.ReactionUsersPage{Users: users, Total: total}, nil
+}
+
 // ─── Pins ───
 
+// verifyPinPermission keeps 1:1 pins open to both members but, in a group,
+// lets only the creator curate them — the same rule RemoveGroupMember applies
+// to pruning members.
+func (s *dmService) verifyPinPermission(channel *models.DMChannel, userID string) error {
+	if channel.IsGroup && (channel.InitiatedBy == nil || *channel.InitiatedBy != userID) {
+		return fmt.Errorf("%w: only the group creator can pin or unpin messages", pkg.ErrForbidden)
+	}
+	return nil
+}
+
 func (s *dmService) PinMessage(ctx context.Context, userID, messageID string) error {
 	msg, channel, err := s.verifyMessageAccess(ctx, userID, messageID)
 	if err != nil {
 		return err
 	}
+	if err := s.verifyPinPermission(channel, userID); err != nil {
+		return err
+	}
 
 	if err := s.dmRepo.PinMessage(ctx, messageID); err != nil {
 		return fmt.Errorf("failed to pin DM message: %w", err)
@@ -61,7 +115,7 @@ func (s *dmService) PinMessage(ctx context.Context, userID, messageID string) er
 		return err
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op: ws.OpDMMessagePin,
 		Data: map[string]any{
 			"dm_channel_id": msg.DMChannelID,
@@ -77,12 +131,15 @@ func (s *dmService) UnpinMessage(ctx context.Context, userID, messageID string)
 	if err != nil {
 		return err
 	}
+	if err := s.verifyPinPermission(channel, userID); err != nil {
+		return err
+	}
 
 	if err := s.dmRepo.UnpinMessage(ctx, messageID); err != nil {
 		return fmt.Errorf("failed to unpin DM message: %w", err)
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op: ws.OpDMMessageUnpin,
 		Data: map[string]any{
 			"dm_channel_id": msg.DMChannelID,
@@ -154,7 +211,7 @@ func (s *dmService) ToggleE2EE(ctx context.Context, userID, channelID string, en
 
 	channel.E2EEEnabled = enabled
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op:   "dm_channel_update",
 		Data: channel,
 	})
@@ -0,0 +1,169 @@
+// Package services — group DM lifecycle (create / add member / remove member).
+// Group channels live in the same dm_channels table as 1:1 conversations but
+// keep their membership in dm_channel_members; the 1:1 privacy/request flow
+// does not apply to them.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/ws"
+)
+
+func (s *dmService) CreateGroupChannel(ctx context.Context, creatorID string, memberIDs []string) (*models.DMChannelWithUser, error) {
+	// Dedupe and drop the creator — clients routinely include them.
+	seen := map[string]bool{creatorID: true}
+	others := make([]string, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if !seen[id] {
+			seen[id] = true
+			others = append(others, id)
+		}
+	}
+
+	if len(others) == 0 {
+		return nil, fmt.Errorf("%w: a group DM needs at least one other member", pkg.ErrBadRequest)
+	}
+	// Two distinct participants is just a DM — reuse the 1:1 flow (and its
+	// privacy checks) instead of minting a degenerate group.
+	if len(others) == 1 {
+		return s.GetOrCreateChannel(ctx, creatorID, others[0])
+	}
+	if len(others)+1 > models.MaxGroupDMMembers {
+		return nil, fmt.Errorf("%w: a group DM can have at most %d members", pkg.ErrBadRequest, models.MaxGroupDMMembers)
+	}
+
+	// Every participant must be an active user.
+	members := make([]models.User, 0, len(others)+1)
+	for _, id := range append([]string{creatorID}, others...) {
+		user, err := s.userRepo.GetActiveByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, pkg.ErrNotFound) {
+				return nil, fmt.Errorf("%w: user not found", pkg.ErrNotFound)
+			}
+			return nil, fmt.Errorf("failed to look up user: %w", err)
+		}
+		user.PasswordHash = ""
+		user.AvatarURL = s.urlSigner.SignURLPtr(user.AvatarURL)
+		members = append(members, *user)
+	}
+
+	channel := &models.DMChannel{
+		Status:      models.DMStatusAccepted,
+		InitiatedBy: &creatorID, // the creator — remove-member permission hangs off this
+	}
+	allIDs := make([]string, 0, len(members))
+	for _, m := range members {
+		allIDs = append(allIDs, m.ID)
+	}
+	if err := s.dmRepo.CreateGroupChannel(ctx, channel, allIDs); err != nil {
+		return nil, fmt.Errorf("failed to create group DM channel: %w", err)
+	}
+
+	result := &models.DMChannelWithUser{
+		ID:            channel.ID,
+		IsGroup:       true,
+		Members:       members,
+		Status:        channel.Status,
+		InitiatedBy:   channel.InitiatedBy,
+		CreatedAt:     channel.CreatedAt,
+		LastMessageAt: channel.LastMessageAt,
+	}
+
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
+		Op:   ws.OpDMChannelCreate,
+		Data: result,
+	})
+
+	return result, nil
+}
+
+func (s *dmService) AddGroupMember(ctx context.Context, userID, channelID, memberID string) error {
+	channel, err := s.verifyChannelMembership(ctx, userID, channelID)
+	if err != nil {
+		return err
+	}
+	if !channel.IsGroup {
+		return fmt.Errorf("%w: not a group DM channel", pkg.ErrBadRequest)
+	}
+
+	if _, err := s.userRepo.GetActiveByID(ctx, memberID); err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			return fmt.Errorf("%w: user not found", pkg.ErrNotFound)
+		}
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	memberIDs, err := s.dmRepo.GetChannelMemberIDs(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	for _, id := range memberIDs {
+		if id == memberID {
+			return fmt.Errorf("%w: user is already a member", pkg.ErrBadRequest)
+		}
+	}
+	if len(memberIDs)+1 > models.MaxGroupDMMembers {
+		return fmt.Errorf("%w: a group DM can have at most %d members", pkg.ErrBadRequest, models.MaxGroupDMMembers)
+	}
+
+	if err := s.dmRepo.AddChannelMember(ctx, channelID, memberID); err != nil {
+		return err
+	}
+
+	// After the insert, so the new member's sessions get the update too.
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
+		Op: ws.OpDMGroupMemberUpdate,
+		Data: map[string]any{
+			"dm_channel_id": channelID,
+			"action":        "add",
+			"user_id":       memberID,
+		},
+	})
+	return nil
+}
+
+func (s *dmService) RemoveGroupMember(ctx context.Context, userID, channelID, memberID string) error {
+	channel, err := s.verifyChannelMembership(ctx, userID, channelID)
+	if err != nil {
+		return err
+	}
+	if !channel.IsGroup {
+		return fmt.Errorf("%w: not a group DM channel", pkg.ErrBadRequest)
+	}
+
+	// Anyone can leave; only the creator removes someone else.
+	if memberID != userID && (channel.InitiatedBy == nil || *channel.InitiatedBy != userID) {
+		return fmt.Errorf("%w: only the group creator can remove other members", pkg.ErrForbidden)
+	}
+
+	member, err := s.dmRepo.IsChannelMember(ctx, channelID, memberID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return fmt.Errorf("%w: user is not a member", pkg.ErrBadRequest)
+	}
+
+	if err := s.dmRepo.RemoveChannelMember(ctx, channelID, memberID); err != nil {
+		return err
+	}
+
+	event := ws.Event{
+		Op: ws.OpDMGroupMemberUpdate,
+		Data: map[string]any{
+			"dm_channel_id": channelID,
+			"action":        "remove",
+			"user_id":       memberID,
+		},
+	}
+	// Remaining members — plus the removed user, whose sidebar must drop the
+	// conversation (broadcastToChannelMembers no longer reaches them).
+	s.broadcastToChannelMembers(ctx, channel, event)
+	s.hub.BroadcastToUser(memberID, event)
+	return nil
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// groupDMRepo embeds the interface so only the methods the group flows reach need to exist.
+type groupDMRepo struct {
+	repository.DMRepository
+
+	channel   *models.DMChannel
+	memberIDs []string
+	message   *models.DMMessage
+
+	createdGroup     bool
+	createdGroupWith []string
+	pairLookups      int
+	removed          []string
+	pinned           []string
+	unpinned         []string
+}
+
+func (r *groupDMRepo) GetChannelByUsers(context.Context, string, string) (*models.DMChannel, error) {
+	r.pairLookups++
+	return r.channel, nil
+}
+
+func (r *groupDMRepo) GetChannelByID(context.Context, string) (*models.DMChannel, error) {
+	return r.channel, nil
+}
+
+func (r *groupDMRepo) CountUnread(context.Context, string, string) (int, error) { return 0, nil }
+
+func (r *groupDMRepo) CreateGroupChannel(_ context.Context, channel *models.DMChannel, memberIDs []string) error {
+	r.createdGroup = true
+	r.createdGroupWith = memberIDs
+	channel.ID = "g1"
+	channel.IsGroup = true
+	r.channel = channel
+	r.memberIDs = memberIDs
+	return nil
+}
+
+func (r *groupDMRepo) GetChannelMemberIDs(context.Context, string) ([]string, error) {
+	return r.memberIDs, nil
+}
+
+func (r *groupDMRepo) IsChannelMember(_ context.Context, _, userID string) (bool, error) {
+	for _, id := range r.memberIDs {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *groupDMRepo) RemoveChannelMember(_ context.Context, _, userID string) error {
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+func (r *groupDMRepo) GetMessageByID(context.Context, string) (*models.DMMessage, error) {
+	return r.message, nil
+}
+
+func (r *groupDMRepo) PinMessage(_ context.Context, messageID string) error {
+	r.pinned = append(r.pinned, messageID)
+	return nil
+}
+
+func (r *groupDMRepo) UnpinMessage(_ context.Context, messageID string) error {
+	r.unpinned = append(r.unpinned, messageID)
+	return nil
+}
+
+func (r *groupDMRepo) GetAttachmentsByMessageIDs(context.Context, []string) (map[string][]models.DMAttachment, error) {
+	return nil, nil
+}
+
+func (r *groupDMRepo) GetReactionsByMessageIDs(context.Context, []string) (map[string][]models.ReactionGroup, error) {
+	return nil, nil
+}
+
+func groupDMService(repo *groupDMRepo) (*dmService, *recordingHub) {
+	hub := &recordingHub{}
+	userRepo := &testutil.MockUserRepo{
+		GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id}, nil
+		},
+		GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id}, nil
+		},
+	}
+	return &dmService{
+		dmRepo:    repo,
+		userRepo:  userRepo,
+		hub:       hub,
+		urlSigner: &testutil.MockFileURLSigner{},
+	}, hub
+}
+
+func TestCreateGroupChannel_CreatesGroupAndNotifiesEveryMember(t *testing.T) {
+	repo := &groupDMRepo{}
+	svc, hub := groupDMService(repo)
+
+	// Duplicates and the creator themselves must be folded away.
+	result, err := svc.CreateGroupChannel(context.Background(), "alice", []string{"bob", "carol", "bob", "alice"})
+	if err != nil {
+		t.Fatalf("CreateGroupChannel: %v", err)
+	}
+
+	if !repo.createdGroup {
+		t.Fatal("no group channel was created")
+	}
+	if len(repo.createdGroupWith) != 3 {
+		t.Fatalf("group created with %d members, want 3: %v", len(repo.createdGroupWith), repo.createdGroupWith)
+	}
+	if !result.IsGroup || len(result.Members) != 3 {
+		t.Fatalf("result is_group=%v members=%d, want a 3-member group", result.IsGroup, len(result.Members))
+	}
+
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		if len(hub.eventsFor(userID, ws.OpDMChannelCreate)) != 1 {
+			t.Errorf("%s did not receive the channel-create event", userID)
+		}
+	}
+}
+
+func TestCreateGroupChannel_TwoPeopleFallBackToThePairFlow(t *testing.T) {
+	repo := &groupDMRepo{
+		channel: &models.DMChannel{ID: "c1", User1ID: "alice", User2ID: "bob", Status: models.DMStatusAccepted},
+	}
+	svc, _ := groupDMService(repo)
+
+	result, err := svc.CreateGroupChannel(context.Background(), "alice", []string{"bob", "bob"})
+	if err != nil {
+		t.Fatalf("CreateGroupChannel: %v", err)
+	}
+
+	if repo.createdGroup {
+		t.Fatal("a degenerate 2-person group was created instead of reusing the 1:1 flow")
+	}
+	if repo.pairLookups == 0 || result.ID != "c1" {
+		t.Fatalf("expected the existing pair channel back, got %+v", result)
+	}
+}
+
+func TestCreateGroupChannel_EnforcesTheMemberCap(t *testing.T) {
+	repo := &groupDMRepo{}
+	svc, _ := groupDMService(repo)
+
+	memberIDs := make([]string, models.MaxGroupDMMembers) // +creator = one over the cap
+	for i := range memberIDs {
+		memberIDs[i] = string(rune('a' + i))
+	}
+
+	_, err := svc.CreateGroupChannel(context.Background(), "creator", memberIDs)
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("oversized group error = %v, want ErrBadRequest", err)
+	}
+	if repo.createdGroup {
+		t.Fatal("oversized group was created anyway")
+	}
+}
+
+func TestRemoveGroupMember_OnlyTheCreatorRemovesOthers(t *testing.T) {
+	creator := "alice"
+	repo := &groupDMRepo{
+		channel:   &models.DMChannel{ID: "g1", IsGroup: true, InitiatedBy: &creator},
+		memberIDs: []string{"alice", "bob", "carol"},
+	}
+	svc, hub := groupDMService(repo)
+	ctx := context.Background()
+
+	// A plain member pruning someone else is a permission problem.
+	if err := svc.RemoveGroupMember(ctx, "bob", "g1", "carol"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("member removing another member: err = %v, want ErrForbidden", err)
+	}
+
+	// Leaving is always allowed.
+	if err := svc.RemoveGroupMember(ctx, "bob", "g1", "bob"); err != nil {
+		t.Fatalf("member leaving: %v", err)
+	}
+	if len(repo.removed) != 1 || repo.removed[0] != "bob" {
+		t.Fatalf("removed = %v, want [bob]", repo.removed)
+	}
+	if len(hub.eventsFor("bob", ws.OpDMGroupMemberUpdate)) == 0 {
+		t.Error("the removed member was not told")
+	}
+
+	// And the creator removes anyone.
+	if err := svc.RemoveGroupMember(ctx, "alice", "g1", "carol"); err != nil {
+		t.Fatalf("creator removing a member: %v", err)
+	}
+}
+
+func TestGroupDMPins_OnlyTheCreatorPinsAndUnpins(t *testing.T) {
+	creator := "alice"
+	repo := &groupDMRepo{
+		channel:   &models.DMChannel{ID: "g1", IsGroup: true, InitiatedBy: &creator},
+		memberIDs: []string{"alice", "bob", "carol"},
+		message:   &models.DMMessage{ID: "m1", DMChannelID: "g1", UserID: "bob"},
+	}
+	svc, _ := groupDMService(repo)
+	ctx := context.Background()
+
+	if err := svc.PinMessage(ctx, "bob", "m1"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("member pinning in a group: err = %v, want ErrForbidden", err)
+	}
+	if err := svc.UnpinMessage(ctx, "bob", "m1"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("member unpinning in a group: err = %v, want ErrForbidden", err)
+	}
+	if len(repo.pinned)+len(repo.unpinned) != 0 {
+		t.Fatalf("pin state changed anyway: pinned %v, unpinned %v", repo.pinned, repo.unpinned)
+	}
+
+	if err := svc.PinMessage(ctx, "alice", "m1"); err != nil {
+		t.Fatalf("creator pinning: %v", err)
+	}
+	if err := svc.UnpinMessage(ctx, "alice", "m1"); err != nil {
+		t.Fatalf("creator unpinning: %v", err)
+	}
+}
+
+func TestPairDMPins_StayOpenToBothMembers(t *testing.T) {
+	repo := &groupDMRepo{
+		channel: &models.DMChannel{ID: "c1", User1ID: "alice", User2ID: "bob", Status: models.DMStatusAccepted},
+		message: &models.DMMessage{ID: "m1", DMChannelID: "c1", UserID: "alice"},
+	}
+	svc, _ := groupDMService(repo)
+
+	// The recipient, not the author, pins — either side of a 1:1 may.
+	if err := svc.PinMessage(context.Background(), "bob", "m1"); err != nil {
+		t.Fatalf("pair member pinning: %v", err)
+	}
+	if len(repo.pinned) != 1 {
+		t.Fatalf("pinned = %v, want [m1]", repo.pinned)
+	}
+}
@@ -16,9 +16,10 @@ import (
 type stubDMRepo struct {
 	repository.DMRepository
 
-	channel *models.DMChannel
-	moved   bool
-	unread  int
+	channel   *models.DMChannel
+	moved     bool
+	unread    int
+	readState *models.DMReadState
 
 	markedMessageID string // "" means MarkReadLatest was used
 	markReadCalls   int
@@ -44,6 +45,14 @@ func (r *stubDMRepo) CountUnread(context.Context, string, string) (int, error) {
 	return r.unread, nil
 }
 
+func (r *stubDMRepo) GetReadState(context.Context, string, string) (*models.DMReadState, error) {
+	return r.readState, nil
+}
+
+func (r *stubDMRepo) IsChannelMember(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+
 // readPush records the retraction so the test can assert on the push GATE, not just the push.
 type readPush struct {
 	retracted []string // "userID|channelID"
@@ -177,3 +186,52 @@ func TestMarkRead_EmptyMessageIDMeansTheWholeConversation(t *testing.T) {
 		t.Errorf("marked read up to %q, want m7", repo.markedMessageID)
 	}
 }
+
+// Marking read is also what tells the sender their message was seen. The receipt goes to
+// the OTHER participant only, as its own op — OpDMRead stays private to the reader.
+func TestMarkRead_SendsReadReceiptToTheOtherParticipant(t *testing.T) {
+	svc, repo, hub, _ := markReadService(true, 0)
+	repo.readState = &models.DMReadState{LastReadMessageID: "m1"}
+
+	if _, err := svc.MarkRead(context.Background(), "alice", "c1", "m1"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+
+	receipts := hub.eventsFor("bob", ws.OpDMReadReceipt)
+	if len(receipts) != 1 {
+		t.Fatalf("%d receipts to the other participant, want 1", len(receipts))
+	}
+	data, _ := receipts[0].Data.(map[string]any)
+	if data["user_id"] != "alice" || data["last_read_message_id"] != "m1" || data["dm_channel_id"] != "c1" {
+		t.Errorf("receipt carried %v", data)
+	}
+	if data["read_at"] == nil {
+		t.Error("receipt is missing read_at")
+	}
+	if n := len(hub.eventsFor("alice", ws.OpDMReadReceipt)); n != 0 {
+		t.Errorf("the reader got %d of their own receipts", n)
+	}
+}
+
+// A no-op mark must not leak a receipt (it would re-date an old read), and group
+// conversations get none at all.
+func TestMarkRead_NoReceiptWhenNothingMovedOrInGroups(t *testing.T) {
+	svc, repo, hub, _ := markReadService(false, 0)
+	repo.readState = &models.DMReadState{LastReadMessageID: "m1"}
+	if _, err := svc.MarkRead(context.Background(), "alice", "c1", "m1"); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if n := len(hub.eventsFor("bob", ws.OpDMReadReceipt)); n != 0 {
+		t.Errorf("a no-op mark still sent %d receipts", n)
+	}
+
+	svc, repo, hub, _ = markReadService(true, 0)
+	repo.readState = &models.DMReadState{LastReadMessageID: "m1"}
+	repo.channel = &models.DMChannel{ID: "g1", IsGroup: true}
+	if _, err := svc.MarkRead(context.Background(), "alice", "g1", "m1"); err != nil {
+		t.Fatalf("MarkRead (group): %v", err)
+	}
+	if n := len(hub.eventsFor("bob", ws.OpDMReadReceipt)); n != 0 {
+		t.Errorf("a group mark-read sent %d receipts", n)
+	}
+}
@@ -21,6 +21,10 @@ func (s *dmService) SetPushNotifier(n PushNotifier) {
 	s.pushNotifier = n
 }
 
+func (s *dmService) SetFormatLimits(limits models.MessageFormatLimits) {
+	s.formatLimits = limits
+}
+
 func (s *dmService) GetMessages(ctx context.Context, userID, channelID string, beforeID string, limit int) (*models.DMMessagePage, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 50
@@ -59,6 +63,34 @@ func (s *dmService) GetMessages(ctx context.Context, userID, channelID string, b
 	}, nil
 }
 
+// GetMessagesAround returns the target DM message with up to limit messages on
+// each side, ascending — "jump to message" from DM search and pins.
+func (s *dmService) GetMessagesAround(ctx context.Context, userID, channelID, messageID string, limit int) (*models.DMMessageContextPage, error) {
+	// Per direction, so the full window is up to 2*limit+1 messages.
+	if limit <= 0 || limit > 50 {
+		limit = 25
+	}
+
+	if _, err := s.verifyChannelMembership(ctx, userID, channelID); err != nil {
+		return nil, err
+	}
+
+	messages, hasBefore, hasAfter, err := s.dmRepo.GetMessagesAround(ctx, channelID, messageID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichMessages(ctx, messages); err != nil {
+		return nil, err
+	}
+
+	return &models.DMMessageContextPage{
+		Messages:  messages,
+		HasBefore: hasBefore,
+		HasAfter:  hasAfter,
+	}, nil
+}
+
 // enforceDMEncryptionPolicy makes the message's encryption match the conversation's setting, in both
 // directions. The client alone picks the path, so a client that misreads the state would store the
 // message in the clear — or, the other way, as ciphertext nobody in the conversation can decrypt.
@@ -83,6 +115,14 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
+	// Shape limits apply to plaintext only — ciphertext can't be reshaped.
+	if req.EncryptionVersion == 0 {
+		content, err := s.formatLimits.NormalizeFormatting(req.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+		}
+		req.Content = content
+	}
 
 	channel, err := s.verifyChannelMembership(ctx, userID, channelID)
 	if err != nil {
@@ -93,6 +133,106 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 		return nil, err
 	}
 
+	// Group channels skip the pair gate entirely — there is no single "other
+	// user" to check for deletion, blocks, or DM privacy, and the request flow
+	// does not apply (groups are created accepted).
+	otherUserID := ""
+	if !channel.IsGroup {
+		otherUserID, err = s.enforcePairSendPolicy(ctx, userID, channelID, channel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Reply validation
+	if req.ReplyToID != nil && *req.ReplyToID != "" {
+		refMsg, err := s.dmRepo.GetMessageByID(ctx, *req.ReplyToID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: referenced message not found", pkg.ErrBadRequest)
+		}
+		if refMsg.DMChannelID != channelID {
+			return nil, fmt.Errorf("%w: referenced message is not in this DM channel", pkg.ErrBadRequest)
+		}
+		depth, err := s.dmRepo.ReplyChainDepth(ctx, refMsg.ID, models.MaxReplyChainDepth)
+		if err != nil {
+			return nil, err
+		}
+		if depth >= models.MaxReplyChainDepth {
+			return nil, fmt.Errorf("%w: reply chain is too deep", pkg.ErrBadRequest)
+		}
+	}
+
+	var contentPtr *string
+	if req.Content != "" {
+		contentPtr = &req.Content
+	}
+
+	msg := &models.DMMessage{
+		DMChannelID:       channelID,
+		UserID:            userID,
+		Content:           contentPtr,
+		ReplyToID:         req.ReplyToID,
+		EncryptionVersion: req.EncryptionVersion,
+		Ciphertext:        req.Ciphertext,
+		SenderDeviceID:    req.SenderDeviceID,
+		E2EEMetadata:      req.E2EEMetadata,
+	}
+
+	if err := s.dmRepo.CreateMessage(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to create DM message: %w", err)
+	}
+
+	author, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message author: %w", err)
+	}
+	author.PasswordHash = ""
+	author.AvatarURL = s.urlSigner.SignURLPtr(author.AvatarURL)
+	msg.Author = author
+
+	// Load reply reference
+	if msg.ReplyToID != nil && *msg.ReplyToID != "" {
+		refMsg, err := s.dmRepo.GetMessageByID(ctx, *msg.ReplyToID)
+		if err == nil {
+			ref := &models.MessageReference{
+				ID:      refMsg.ID,
+				Content: refMsg.Content,
+			}
+			if refMsg.Author != nil {
+				refMsg.Author.PasswordHash = ""
+				refMsg.Author.AvatarURL = s.urlSigner.SignURLPtr(refMsg.Author.AvatarURL)
+				ref.Author = refMsg.Author
+			}
+			msg.ReferencedMessage = ref
+		}
+	}
+
+	msg.Attachments = []models.DMAttachment{}
+	msg.Reactions = []models.ReactionGroup{}
+	msg.IsEmojiOnly = models.EmojiOnly(msg.Content)
+
+	// Auto-unhide: if a participant hid this DM, show it again on new message (best-effort)
+	if s.unhider != nil {
+		if channel.IsGroup {
+			if memberIDs, mErr := s.dmRepo.GetChannelMemberIDs(ctx, channelID); mErr == nil {
+				for _, memberID := range memberIDs {
+					_ = s.unhider.UnhideForNewMessage(ctx, memberID, channelID)
+				}
+			}
+		} else {
+			_ = s.unhider.UnhideForNewMessage(ctx, otherUserID, channelID)
+			_ = s.unhider.UnhideForNewMessage(ctx, userID, channelID)
+		}
+	}
+
+	return msg, nil
+}
+
+// enforcePairSendPolicy runs the 1:1-only send gates — active recipient, the
+// bidirectional block check, and the DM privacy/request flow — and returns the
+// other participant's id. It may flip the channel to pending (first message to
+// a message_request recipient); the caller's channel reflects that.
+func (s *dmService) enforcePairSendPolicy(ctx context.Context, userID, channelID string, channel *models.DMChannel) (string, error) {
 	otherUserID := channel.User1ID
 	if channel.User1ID == userID {
 		otherUserID = channel.User2ID
@@ -102,9 +242,9 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 	// but the other party is gone.
 	if _, err := s.userRepo.GetActiveByID(ctx, otherUserID); err != nil {
 		if errors.Is(err, pkg.ErrNotFound) {
-			return nil, fmt.Errorf("%w: recipient is no longer available", pkg.ErrNotFound)
+			return "", fmt.Errorf("%w: recipient is no longer available", pkg.ErrNotFound)
 		}
-		return nil, fmt.Errorf("failed to look up recipient: %w", err)
+		return "", fmt.Errorf("failed to look up recipient: %w", err)
 	}
 
 	sender, _ := s.userRepo.GetByID(ctx, userID)
@@ -114,10 +254,10 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 	if !isPlatformAdmin && s.blockChecker != nil {
 		blocked, err := s.blockChecker.IsBlocked(ctx, userID, otherUserID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check block status: %w", err)
+			return "", fmt.Errorf("failed to check block status: %w", err)
 		}
 		if blocked {
-			return nil, fmt.Errorf("%w: cannot send message to blocked user", pkg.ErrForbidden)
+			return "", fmt.Errorf("%w: cannot send message to blocked user", pkg.ErrForbidden)
 		}
 	}
 
@@ -127,11 +267,11 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 		if channel.Status == models.DMStatusPending {
 			// Initiator already sent their 1 message
 			if channel.InitiatedBy != nil && *channel.InitiatedBy == userID {
-				return nil, fmt.Errorf("%w: dm_request_pending", pkg.ErrForbidden)
+				return "", fmt.Errorf("%w: dm_request_pending", pkg.ErrForbidden)
 			}
 			// Recipient must accept first
 			if channel.InitiatedBy != nil && *channel.InitiatedBy != userID {
-				return nil, fmt.Errorf("%w: dm_request_not_accepted", pkg.ErrForbidden)
+				return "", fmt.Errorf("%w: dm_request_not_accepted", pkg.ErrForbidden)
 			}
 		}
 
@@ -143,7 +283,7 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 			if recipient != nil && recipient.DMPrivacy == "message_request" {
 				friends, err := s.friendChecker.AreFriends(ctx, userID, otherUserID)
 				if err != nil {
-					return nil, fmt.Errorf("failed to check friendship: %w", err)
+					return "", fmt.Errorf("failed to check friendship: %w", err)
 				}
 				if !friends {
 					// Skip request flow if the conversation is already established:
@@ -151,12 +291,12 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 					// previously accepted and a back-and-forth is in progress.
 					otherMsgCount, err := s.dmRepo.CountMessagesBySender(ctx, channelID, otherUserID)
 					if err != nil {
-						return nil, fmt.Errorf("failed to count messages: %w", err)
+						return "", fmt.Errorf("failed to count messages: %w", err)
 					}
 					if otherMsgCount == 0 {
 						msgCount, err := s.dmRepo.CountMessagesBySender(ctx, channelID, userID)
 						if err != nil {
-							return nil, fmt.Errorf("failed to count messages: %w", err)
+							return "", fmt.Errorf("failed to count messages: %w", err)
 						}
 						if msgCount == 0 {
 							// First message: transition channel to pending
@@ -165,7 +305,7 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 							channel.InitiatedBy = &userID
 							_ = s.dmRepo.SetInitiatedBy(ctx, channelID, userID)
 						} else {
-							return nil, fmt.Errorf("%w: dm_request_pending", pkg.ErrForbidden)
+							return "", fmt.Errorf("%w: dm_request_pending", pkg.ErrForbidden)
 						}
 					}
 				}
@@ -174,81 +314,44 @@ func (s *dmService) SendMessage(ctx context.Context, userID, channelID string, r
 			if recipient != nil && recipient.DMPrivacy == "friends_only" {
 				friends, err := s.friendChecker.AreFriends(ctx, userID, otherUserID)
 				if err != nil {
-					return nil, fmt.Errorf("failed to check friendship: %w", err)
+					return "", fmt.Errorf("failed to check friendship: %w", err)
 				}
 				if !friends {
-					return nil, fmt.Errorf("%w: this user only accepts messages from friends", pkg.ErrForbidden)
+					return "", fmt.Errorf("%w: this user only accepts messages from friends", pkg.ErrForbidden)
 				}
 			}
-		}
-	}
-
-	// Reply validation
-	if req.ReplyToID != nil && *req.ReplyToID != "" {
-		refMsg, err := s.dmRepo.GetMessageByID(ctx, *req.ReplyToID)
-		if err != nil {
-			return nil, fmt.Errorf("%w: referenced message not found", pkg.ErrBadRequest)
-		}
-		if refMsg.DMChannelID != channelID {
-			return nil, fmt.Errorf("%w: referenced message is not in this DM channel", pkg.ErrBadRequest)
-		}
-	}
-
-	var contentPtr *string
-	if req.Content != "" {
-		contentPtr = &req.Content
-	}
-
-	msg := &models.DMMessage{
-		DMChannelID:       channelID,
-		UserID:            userID,
-		Content:           contentPtr,
-		ReplyToID:         req.ReplyToID,
-		EncryptionVersion: req.EncryptionVersion,
-		Ciphertext:        req.Ciphertext,
-		SenderDeviceID:    req.SenderDeviceID,
-		E2EEMetadata:      req.E2EEMetadata,
-	}
-
-	if err := s.dmRepo.CreateMessage(ctx, msg); err != nil {
-		return nil, fmt.Errorf("failed to create DM message: %w", err)
-	}
-
-	author, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get message author: %w", err)
-	}
-	author.PasswordHash = ""
-	author.AvatarURL = s.urlSigner.SignURLPtr(author.AvatarURL)
-	msg.Author = author
-
-	// Load reply reference
-	if msg.ReplyToID != nil && *msg.ReplyToID != "" {
-		refMsg, err := s.dmRepo.GetMessageByID(ctx, *msg.ReplyToID)
-		if err == nil {
-			ref := &models.MessageReference{
-				ID:      refMsg.ID,
-				Content: refMsg.Content,
-			}
-			if refMsg.Author != nil {
-				refMsg.Author.PasswordHash = ""
-				refMsg.Author.AvatarURL = s.urlSigner.SignURLPtr(refMsg.Author.AvatarURL)
-				ref.Author = refMsg.Author
+			// shared_servers at send time. Friends pass, and so does an
+			// established conversation (the recipient has replied before) —
+			// the channel predates the setting, and tightening privacy must
+			// not kill ongoing threads.
+			if recipient != nil && recipient.DMPrivacy == "shared_servers" {
+				friends, err := s.friendChecker.AreFriends(ctx, userID, otherUserID)
+				if err != nil {
+					return "", fmt.Errorf("failed to check friendship: %w", err)
+				}
+				if !friends {
+					shared := false
+					if s.sharedChecker != nil {
+						shared, err = s.sharedChecker.HaveSharedServer(ctx, userID, otherUserID)
+						if err != nil {
+							return "", fmt.Errorf("failed to check shared servers: %w", err)
+						}
+					}
+					if !shared {
+						otherMsgCount, err := s.dmRepo.CountMessagesBySender(ctx, channelID, otherUserID)
+						if err != nil {
+							return "", fmt.Errorf("failed to count messages: %w", err)
+						}
+						if otherMsgCount == 0 {
+							return "", fmt.Errorf("%w: this user only accepts messages from people in their servers", pkg.ErrForbidden)
+						}
+					}
+				}
 			}
-			msg.ReferencedMessage = ref
 		}
 	}
 
-	msg.Attachments = []models.DMAttachment{}
-	msg.Reactions = []models.ReactionGroup{}
-
-	// Auto-unhide: if either user hid this DM, show it again on new message (best-effort)
-	if s.unhider != nil {
-		_ = s.unhider.UnhideForNewMessage(ctx, otherUserID, channelID)
-		_ = s.unhider.UnhideForNewMessage(ctx, userID, channelID)
-	}
-
-	return msg, nil
+	return otherUserID, nil
 }
 
 // CreateCallLog writes a plaintext system message recording a finished P2P call
@@ -292,9 +395,10 @@ func (s *dmService) CreateCallLog(ctx context.Context, callerID, receiverID stri
 	return nil
 }
 
-// BroadcastCreate sends the DM message to both users after file uploads complete.
+// BroadcastCreate sends the DM message to every participant after file uploads complete.
 func (s *dmService) BroadcastCreate(message *models.DMMessage) {
-	channel, err := s.dmRepo.GetChannelByID(context.Background(), message.DMChannelID)
+	ctx := context.Background()
+	channel, err := s.dmRepo.GetChannelByID(ctx, message.DMChannelID)
 	if err != nil {
 		return
 	}
@@ -303,35 +407,54 @@ func (s *dmService) BroadcastCreate(message *models.DMMessage) {
 		Op:   ws.OpDMMessageCreate,
 		Data: message,
 	}
-	s.broadcastToBothUsers(channel, event)
+	s.broadcastToChannelMembers(ctx, channel, event)
 
 	// Push the offline recipient (mobile), off the WS/response path. Skips call-log
 	// system messages and conversations the recipient muted. Mute-check failure fails
 	// open (push) so a transient DB error never silently swallows notifications.
 	if s.pushNotifier != nil && message.MessageType != models.MessageTypeCall {
 		go func() {
-			recipientID := channel.User1ID
-			if recipientID == message.UserID {
-				recipientID = channel.User2ID
-			}
-			muted, err := s.dmRepo.IsChannelMuted(context.Background(), recipientID, channel.ID)
-			if err != nil {
-				log.Printf("[dm] push mute check failed for %s/%s: %v", recipientID, channel.ID, err)
-			}
-			if muted {
-				return
+			ctx := context.Background()
+			var recipients []string
+			if channel.IsGroup {
+				memberIDs, err := s.dmRepo.GetChannelMemberIDs(ctx, channel.ID)
+				if err != nil {
+					log.Printf("[dm] push member lookup failed for %s: %v", channel.ID, err)
+					return
+				}
+				for _, memberID := range memberIDs {
+					if memberID != message.UserID {
+						recipients = append(recipients, memberID)
+					}
+				}
+			} else {
+				recipientID := channel.User1ID
+				if recipientID == message.UserID {
+					recipientID = channel.User2ID
+				}
+				recipients = []string{recipientID}
 			}
+
 			content := ""
 			if message.Content != nil {
 				content = *message.Content
 			}
-			s.pushNotifier.NotifyDM(recipientID, pushDisplayName(message.Author), content, message.EncryptionVersion == 1, channel.ID, message.UserID, message.ID)
+			for _, recipientID := range recipients {
+				muted, err := s.dmRepo.IsChannelMuted(ctx, recipientID, channel.ID)
+				if err != nil {
+					log.Printf("[dm] push mute check failed for %s/%s: %v", recipientID, channel.ID, err)
+				}
+				if muted {
+					continue
+				}
+				s.pushNotifier.NotifyDM(recipientID, pushDisplayName(message.Author), content, message.EncryptionVersion == 1, channel.ID, message.UserID, message.ID)
+			}
 		}()
 	}
 
 	// If channel just became pending, notify both users
 	if channel.Status == models.DMStatusPending {
-		s.broadcastToBothUsers(channel, ws.Event{
+		s.broadcastToChannelMembers(ctx, channel, ws.Event{
 			Op: ws.OpDMChannelStatusChange,
 			Data: map[string]any{
 				"dm_channel_id": channel.ID,
@@ -346,6 +469,13 @@ func (s *dmService) EditMessage(ctx context.Context, userID, messageID string, r
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
+	if req.EncryptionVersion == 0 {
+		content, err := s.formatLimits.NormalizeFormatting(req.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+		}
+		req.Content = content
+	}
 
 	msg, channel, err := s.verifyMessageAccess(ctx, userID, messageID)
 	if err != nil {
@@ -362,6 +492,14 @@ func (s *dmService) EditMessage(ctx context.Context, userID, messageID string, r
 		return nil, err
 	}
 
+	// Same revision bookkeeping as channel edits: plaintext only, no-ops skipped.
+	if msg.EncryptionVersion == 0 && msg.Content != nil &&
+		!(req.EncryptionVersion == 0 && req.Content == *msg.Content) {
+		if err := s.dmRepo.RecordEdit(ctx, messageID, *msg.Content, maxStoredEditsPerMessage); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.dmRepo.UpdateMessage(ctx, messageID, req); err != nil {
 		return nil, err
 	}
@@ -376,7 +514,7 @@ func (s *dmService) EditMessage(ctx context.Context, userID, messageID string, r
 		return nil, err
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op:   ws.OpDMMessageUpdate,
 		Data: &enriched[0],
 	})
@@ -430,7 +568,7 @@ func (s *dmService) DeleteMessage(ctx context.Context, userID, messageID string)
 		}
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op: ws.OpDMMessageDelete,
 		Data: map[string]string{
 			"id":            messageID,
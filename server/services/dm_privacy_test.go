@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+type stubFriendChecker struct{ friends bool }
+
+func (s stubFriendChecker) AreFriends(context.Context, string, string) (bool, error) {
+	return s.friends, nil
+}
+
+type stubSharedChecker struct{ shared bool }
+
+func (s stubSharedChecker) HaveSharedServer(context.Context, string, string) (bool, error) {
+	return s.shared, nil
+}
+
+// privacyDMRepo serves the GetOrCreateChannel flow: no existing channel unless
+// seeded, and records whether a new one was created.
+type privacyDMRepo struct {
+	repository.DMRepository
+
+	existing *models.DMChannel
+	created  bool
+}
+
+func (r *privacyDMRepo) GetChannelByUsers(context.Context, string, string) (*models.DMChannel, error) {
+	return r.existing, nil
+}
+
+func (r *privacyDMRepo) CountUnread(context.Context, string, string) (int, error) { return 0, nil }
+
+func (r *privacyDMRepo) CreateChannel(_ context.Context, channel *models.DMChannel) error {
+	r.created = true
+	channel.ID = "new"
+	return nil
+}
+
+func privacyDMService(repo *privacyDMRepo, dmPrivacy string, friends, shared bool) *dmService {
+	userRepo := &testutil.MockUserRepo{
+		GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id, DMPrivacy: dmPrivacy}, nil
+		},
+		GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id}, nil
+		},
+	}
+	return &dmService{
+		dmRepo:        repo,
+		userRepo:      userRepo,
+		hub:           &recordingHub{},
+		friendChecker: stubFriendChecker{friends: friends},
+		sharedChecker: stubSharedChecker{shared: shared},
+		urlSigner:     &testutil.MockFileURLSigner{},
+	}
+}
+
+func TestGetOrCreateChannel_SharedServersPrivacy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stranger without a shared server is rejected", func(t *testing.T) {
+		repo := &privacyDMRepo{}
+		svc := privacyDMService(repo, "shared_servers", false, false)
+
+		_, err := svc.GetOrCreateChannel(ctx, "alice", "bob")
+		if !errors.Is(err, pkg.ErrForbidden) {
+			t.Fatalf("GetOrCreateChannel = %v, want ErrForbidden", err)
+		}
+		if repo.created {
+			t.Fatal("channel was created despite the privacy rejection")
+		}
+	})
+
+	t.Run("shared server member may open the channel", func(t *testing.T) {
+		repo := &privacyDMRepo{}
+		svc := privacyDMService(repo, "shared_servers", false, true)
+
+		if _, err := svc.GetOrCreateChannel(ctx, "alice", "bob"); err != nil {
+			t.Fatalf("GetOrCreateChannel: %v", err)
+		}
+		if !repo.created {
+			t.Fatal("channel was not created")
+		}
+	})
+
+	t.Run("friend passes without a shared server", func(t *testing.T) {
+		repo := &privacyDMRepo{}
+		svc := privacyDMService(repo, "shared_servers", true, false)
+
+		if _, err := svc.GetOrCreateChannel(ctx, "alice", "bob"); err != nil {
+			t.Fatalf("GetOrCreateChannel: %v", err)
+		}
+	})
+
+	t.Run("existing channel survives a later tightening", func(t *testing.T) {
+		repo := &privacyDMRepo{
+			existing: &models.DMChannel{ID: "old", User1ID: "alice", User2ID: "bob", Status: models.DMStatusAccepted},
+		}
+		svc := privacyDMService(repo, "shared_servers", false, false)
+
+		result, err := svc.GetOrCreateChannel(ctx, "alice", "bob")
+		if err != nil {
+			t.Fatalf("GetOrCreateChannel: %v", err)
+		}
+		if result.ID != "old" {
+			t.Fatalf("got channel %s, want the existing one", result.ID)
+		}
+	})
+}
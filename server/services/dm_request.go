@@ -32,7 +32,7 @@ func (s *dmService) AcceptRequest(ctx context.Context, userID, channelID string)
 		return fmt.Errorf("failed to accept DM request: %w", err)
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op: ws.OpDMRequestAccept,
 		Data: map[string]string{
 			"dm_channel_id": channelID,
@@ -57,7 +57,7 @@ func (s *dmService) DeclineRequest(ctx context.Context, userID, channelID string
 		return fmt.Errorf("%w: only the recipient can decline a DM request", pkg.ErrForbidden)
 	}
 
-	s.broadcastToBothUsers(channel, ws.Event{
+	s.broadcastToChannelMembers(ctx, channel, ws.Event{
 		Op: ws.OpDMRequestDecline,
 		Data: map[string]string{
 			"dm_channel_id": channelID,
@@ -86,7 +86,7 @@ func (s *dmService) AcceptPendingChannels(ctx context.Context, userA, userB stri
 		return fmt.Errorf("failed to auto-accept pending DM: %w", err)
 	}
 
-	s.broadcastToBothUsers(ch, ws.Event{
+	s.broadcastToChannelMembers(ctx, ch, ws.Event{
 		Op: ws.OpDMRequestAccept,
 		Data: map[string]string{
 			"dm_channel_id": ch.ID,
@@ -22,7 +22,18 @@ import (
 
 type DMService interface {
 	GetOrCreateChannel(ctx context.Context, userID, otherUserID string) (*models.DMChannelWithUser, error)
+	// CreateGroupChannel opens a group DM between the creator and memberIDs
+	// (up to models.MaxGroupDMMembers in total). Fewer than 3 distinct
+	// participants falls back to the 1:1 GetOrCreateChannel flow.
+	CreateGroupChannel(ctx context.Context, creatorID string, memberIDs []string) (*models.DMChannelWithUser, error)
+	// AddGroupMember / RemoveGroupMember — any member can add; members remove
+	// themselves, only the creator removes others.
+	AddGroupMember(ctx context.Context, userID, channelID, memberID string) error
+	RemoveGroupMember(ctx context.Context, userID, channelID, memberID string) error
 	ListChannels(ctx context.Context, userID string) ([]models.DMChannelWithUser, error)
+	// UnreadSummaries is the ready-event digest of the DM list: one compact row
+	// per conversation with unread messages.
+	UnreadSummaries(ctx context.Context, userID string) ([]models.DMUnreadSummary, error)
 
 	// MarkRead advances the user's read watermark and tells their OTHER devices, so a
 	// conversation read on one of them stops showing unread — and stops showing a
@@ -30,6 +41,9 @@ type DMService interface {
 	MarkRead(ctx context.Context, userID, channelID, messageID string) (int, error)
 
 	GetMessages(ctx context.Context, userID, channelID string, beforeID string, limit int) (*models.DMMessagePage, error)
+	// GetMessagesAround serves "jump to message" for DMs: the target with up to
+	// limit messages on each side, ascending, plus has-more flags per direction.
+	GetMessagesAround(ctx context.Context, userID, channelID, messageID string, limit int) (*models.DMMessageContextPage, error)
 	SendMessage(ctx context.Context, userID, channelID string, req *models.CreateDMMessageRequest) (*models.DMMessage, error)
 	BroadcastCreate(message *models.DMMessage)
 	CreateCallLog(ctx context.Context, callerID, receiverID string, meta models.CallMeta) error
@@ -41,6 +55,7 @@ type DMService interface {
 	AcceptPendingChannels(ctx context.Context, userA, userB string) error
 
 	ToggleReaction(ctx context.Context, userID, messageID, emoji string) error
+	ListReactors(ctx context.Context, userID, messageID, emoji string, limit, offset int) (*models.ReactionUsersPage, error)
 	PinMessage(ctx context.Context, userID, messageID string) error
 	UnpinMessage(ctx context.Context, userID, messageID string) error
 	GetPinnedMessages(ctx context.Context, userID, channelID string) ([]models.DMMessage, error)
@@ -48,6 +63,9 @@ type DMService interface {
 	ToggleE2EE(ctx context.Context, userID, channelID string, enabled bool) (*models.DMChannel, error)
 
 	SetPushNotifier(n PushNotifier)
+	// SetFormatLimits installs the configured message-shape caps applied to
+	// plaintext content on send and edit. Zero limits leave content untouched.
+	SetFormatLimits(limits models.MessageFormatLimits)
 }
 
 // FriendshipChecker is a minimal ISP interface for friend checks (used by dmService).
@@ -55,17 +73,29 @@ type FriendshipChecker interface {
 	AreFriends(ctx context.Context, userA, userB string) (bool, error)
 }
 
+// SharedServerChecker is the membership-overlap check behind the
+// shared_servers DM privacy setting (implemented by ServerRepository).
+type SharedServerChecker interface {
+	HaveSharedServer(ctx context.Context, userA, userB string) (bool, error)
+}
+
 type dmService struct {
 	dmRepo         repository.DMRepository
 	userRepo       repository.UserRepository
 	hub            ws.Broadcaster
 	blockChecker   BlockChecker
 	friendChecker  FriendshipChecker
+	sharedChecker  SharedServerChecker
 	unhider        DMSettingsUnhider
 	urlSigner      FileURLSigner
 	fileDeleter    FileDeleter
 	storageService StorageService
 	pushNotifier   PushNotifier
+	// Reaction limits shared with reactionService — see config.ReactionsConfig.
+	maxReactionsPerUser  int
+	maxDistinctReactions int
+	// Shape caps shared with messageService — see config.MessageFormatConfig.
+	formatLimits models.MessageFormatLimits
 }
 
 func NewDMService(
@@ -74,10 +104,13 @@ func NewDMService(
 	hub ws.Broadcaster,
 	blockChecker BlockChecker,
 	friendshipChecker FriendshipChecker,
+	sharedChecker SharedServerChecker,
 	unhider DMSettingsUnhider,
 	urlSigner FileURLSigner,
 	fileDeleter FileDeleter,
 	storageService StorageService,
+	maxReactionsPerUser int,
+	maxDistinctReactions int,
 ) DMService {
 	return &dmService{
 		dmRepo:         dmRepo,
@@ -85,10 +118,14 @@ func NewDMService(
 		hub:            hub,
 		blockChecker:   blockChecker,
 		friendChecker:  friendshipChecker,
+		sharedChecker:  sharedChecker,
 		unhider:        unhider,
 		urlSigner:      urlSigner,
 		fileDeleter:    fileDeleter,
 		storageService: storageService,
+
+		maxReactionsPerUser:  maxReactionsPerUser,
+		maxDistinctReactions: maxDistinctReactions,
 	}
 }
 
@@ -102,10 +139,24 @@ func sortUserIDs(a, b string) (string, string) {
 	return b, a
 }
 
-func (s *dmService) broadcastToBothUsers(channel *models.DMChannel, event ws.Event) {
-	s.hub.BroadcastToUser(channel.User1ID, event)
-	if channel.User1ID != channel.User2ID {
-		s.hub.BroadcastToUser(channel.User2ID, event)
+// broadcastToChannelMembers fans an event out to every participant — the pair
+// for a 1:1 channel, the member list for a group. Member lookup failure is
+// swallowed: a broadcast is best-effort, the write it announces already landed.
+func (s *dmService) broadcastToChannelMembers(ctx context.Context, channel *models.DMChannel, event ws.Event) {
+	if !channel.IsGroup {
+		s.hub.BroadcastToUser(channel.User1ID, event)
+		if channel.User1ID != channel.User2ID {
+			s.hub.BroadcastToUser(channel.User2ID, event)
+		}
+		return
+	}
+
+	memberIDs, err := s.dmRepo.GetChannelMemberIDs(ctx, channel.ID)
+	if err != nil {
+		return
+	}
+	for _, memberID := range memberIDs {
+		s.hub.BroadcastToUser(memberID, event)
 	}
 }
 
@@ -114,6 +165,16 @@ func (s *dmService) verifyChannelMembership(ctx context.Context, userID, channel
 	if err != nil {
 		return nil, err
 	}
+	if channel.IsGroup {
+		member, err := s.dmRepo.IsChannelMember(ctx, channelID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			return nil, fmt.Errorf("%w: not a member of this DM channel", pkg.ErrForbidden)
+		}
+		return channel, nil
+	}
 	if channel.User1ID != userID && channel.User2ID != userID {
 		return nil, fmt.Errorf("%w: not a member of this DM channel", pkg.ErrForbidden)
 	}
@@ -170,10 +231,11 @@ func (s *dmService) enrichMessages(ctx context.Context, messages []models.DMMess
 		if messages[i].Attachments == nil {
 			messages[i].Attachments = []models.DMAttachment{}
 		}
-		messages[i].Reactions = reactionMap[messages[i].ID]
+		messages[i].Reactions = models.PreviewReactions(reactionMap[messages[i].ID])
 		if messages[i].Reactions == nil {
 			messages[i].Reactions = []models.ReactionGroup{}
 		}
+		messages[i].IsEmojiOnly = models.EmojiOnly(messages[i].Content)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"strings"
@@ -18,20 +19,23 @@ type DMUploadService interface {
 }
 
 type dmUploadService struct {
-	dmRepo   repository.DMRepository
-	pipeline UploadPipeline
-	maxSize  int64
+	dmRepo      repository.DMRepository
+	pipeline    UploadPipeline
+	maxSize     int64
+	imageLimits files.ImageLimits
 }
 
 func NewDMUploadService(
 	dmRepo repository.DMRepository,
 	pipeline UploadPipeline,
 	maxSize int64,
+	imageLimits files.ImageLimits,
 ) DMUploadService {
 	return &dmUploadService{
-		dmRepo:   dmRepo,
-		pipeline: pipeline,
-		maxSize:  maxSize,
+		dmRepo:      dmRepo,
+		pipeline:    pipeline,
+		maxSize:     maxSize,
+		imageLimits: imageLimits,
 	}
 }
 
@@ -47,8 +51,21 @@ func (s *dmUploadService) Upload(ctx context.Context, dmMessageID string, file m
 	mimeBase := strings.Split(contentType, ";")[0]
 	mimeBase = strings.TrimSpace(mimeBase)
 
-	// No upload-time MIME restriction — serve-time handles XSS prevention.
-	_ = isEncrypted
+	// DMs have no per-server settings, so the platform policy applies — today
+	// that means size only, but the check shares the channel-upload code path.
+	if err := checkUploadType(file, header, mimeBase, models.UploadPolicy{MaxSize: s.maxSize}, isEncrypted); err != nil {
+		return nil, err
+	}
+
+	// Same dimension guard as channel attachments; ciphertext is skipped.
+	if !isEncrypted && strings.HasPrefix(mimeBase, "image/") {
+		if err := s.imageLimits.Check(file); err != nil {
+			if errors.Is(err, files.ErrImageTooLarge) {
+				return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+			}
+			return nil, err
+		}
+	}
 
 	stored, err := s.pipeline.Store(ctx, files.KindDM, dmMessageID, file, header, s.maxSize)
 	if err != nil {
@@ -63,6 +80,12 @@ func (s *dmUploadService) Upload(ctx context.Context, dmMessageID string, file m
 		FileSize:    &fileSize,
 		MimeType:    &mimeBase,
 	}
+	// Server-side fallback mirroring the channel path: clients that sent no
+	// preview get a downscaled plaintext JPEG/PNG; ciphertext can't be decoded
+	// and other types store nil dimensions.
+	if thumb == nil && !isEncrypted {
+		thumb = generateThumbnail(file, header.Filename, mimeBase)
+	}
 	if t := storeThumbnail(ctx, s.pipeline, files.KindDM, dmMessageID, thumb); t != nil {
 		attachment.ThumbURL, attachment.ThumbWidth, attachment.ThumbHeight = &t.URL, t.Width, t.Height
 		attachment.ThumbSize = &t.Size
@@ -0,0 +1,125 @@
+// Package services — EmbedService: unfurls the first URL of a new message into
+// a stored embed. Runs entirely off the request path: the message broadcast
+// goes out immediately and the embed follows as its own OpMessageEmbedUpdate
+// once the fetch completes. The fetch itself (SSRF protection, timeouts, body
+// cap, URL-keyed cache) is LinkPreviewService's job; this service decides when
+// to unfurl and who hears about the result.
+package services
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// EmbedService manages link embeds on messages. Implements MessageEmbedder.
+type EmbedService interface {
+	// UnfurlMessage fetches and stores the embed for a newly created message,
+	// then broadcasts OpMessageEmbedUpdate to the channel's viewers. Intended
+	// to run in a goroutine — all failures are silent no-ops.
+	UnfurlMessage(message *models.Message)
+	// EmbedsForMessages batch-loads stored embeds for history enrichment.
+	EmbedsForMessages(ctx context.Context, messageIDs []string) (map[string]*models.MessageEmbed, error)
+}
+
+type embedService struct {
+	embedRepo    repository.MessageEmbedRepository
+	channelRepo  repository.ChannelRepository
+	linkPreview  LinkPreviewService
+	permResolver ChannelPermResolver
+	hub          ws.BroadcastAndOnline
+}
+
+func NewEmbedService(
+	embedRepo repository.MessageEmbedRepository,
+	channelRepo repository.ChannelRepository,
+	linkPreview LinkPreviewService,
+	permResolver ChannelPermResolver,
+	hub ws.BroadcastAndOnline,
+) EmbedService {
+	return &embedService{
+		embedRepo:    embedRepo,
+		channelRepo:  channelRepo,
+		linkPreview:  linkPreview,
+		permResolver: permResolver,
+		hub:          hub,
+	}
+}
+
+// urlPattern matches the first http(s) URL in message content. Trailing
+// punctuation is trimmed afterwards — "check https://example.com." should not
+// fetch a URL ending in a dot.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// extractFirstURL returns the first URL in content, or "" if there is none.
+func extractFirstURL(content string) string {
+	match := urlPattern.FindString(content)
+	return strings.TrimRight(match, ".,;:!?)")
+}
+
+func (s *embedService) UnfurlMessage(message *models.Message) {
+	// E2EE content is unreadable and system messages don't carry user links.
+	if message.Content == nil || message.EncryptionVersion != 0 || message.Type.IsSystem() {
+		return
+	}
+	rawURL := extractFirstURL(*message.Content)
+	if rawURL == "" {
+		return
+	}
+
+	// Detached from the request context — the author already got their 201.
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// Authors without PermEmbedLinks post plain links, no unfurl.
+	perms, err := s.permResolver.ResolveChannelPermissions(ctx, message.UserID, message.ChannelID)
+	if err != nil || !perms.Has(models.PermEmbedLinks) {
+		return
+	}
+
+	preview, err := s.linkPreview.GetPreview(ctx, rawURL)
+	if err != nil || preview == nil {
+		return
+	}
+	// A page with no usable metadata renders as nothing — don't store it.
+	if preview.Title == nil && preview.Description == nil && preview.ImageURL == nil {
+		return
+	}
+
+	embed := &models.MessageEmbed{
+		MessageID:   message.ID,
+		URL:         preview.URL,
+		Title:       preview.Title,
+		Description: preview.Description,
+		ImageURL:    preview.ImageURL,
+		SiteName:    preview.SiteName,
+		FaviconURL:  preview.FaviconURL,
+	}
+	if err := s.embedRepo.Upsert(ctx, embed); err != nil {
+		log.Printf("[embed] failed to store embed for message %s: %v", message.ID, err)
+		return
+	}
+
+	// Same audience as the message broadcast: online server members with
+	// ViewChannel + ReadMessages on the channel.
+	channel, err := s.channelRepo.GetByID(ctx, message.ChannelID)
+	if err != nil || channel == nil {
+		return
+	}
+	online := s.hub.GetOnlineUserIDsForServer(channel.ServerID)
+	viewers := s.permResolver.AllowedViewers(ctx, message.ChannelID, online)
+	s.hub.BroadcastToUsers(viewers, ws.Event{
+		Op:   ws.OpMessageEmbedUpdate,
+		Data: embed,
+	})
+}
+
+func (s *embedService) EmbedsForMessages(ctx context.Context, messageIDs []string) (map[string]*models.MessageEmbed, error) {
+	return s.embedRepo.GetByMessageIDs(ctx, messageIDs)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+func TestExtractFirstURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no url", "just some text", ""},
+		{"plain url", "look at https://example.com/page", "https://example.com/page"},
+		{"first of several", "https://first.example https://second.example", "https://first.example"},
+		{"trailing punctuation trimmed", "see https://example.com/doc.", "https://example.com/doc"},
+		{"closing paren trimmed", "(source: https://example.com/a)", "https://example.com/a"},
+		{"http scheme", "legacy http://example.com link", "http://example.com"},
+		{"scheme-less is ignored", "visit example.com today", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractFirstURL(tt.content); got != tt.want {
+				t.Errorf("extractFirstURL(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeLinkPreview serves a canned preview and records whether it was asked.
+type fakeLinkPreview struct {
+	preview *models.LinkPreview
+	err     error
+	gotURL  string
+}
+
+func (f *fakeLinkPreview) GetPreview(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	f.gotURL = rawURL
+	return f.preview, f.err
+}
+
+// fakeEmbedRepo captures upserts in memory.
+type fakeEmbedRepo struct {
+	stored map[string]*models.MessageEmbed
+}
+
+func (f *fakeEmbedRepo) Upsert(ctx context.Context, embed *models.MessageEmbed) error {
+	if f.stored == nil {
+		f.stored = make(map[string]*models.MessageEmbed)
+	}
+	f.stored[embed.MessageID] = embed
+	return nil
+}
+
+func (f *fakeEmbedRepo) GetByMessageIDs(ctx context.Context, messageIDs []string) (map[string]*models.MessageEmbed, error) {
+	return f.stored, nil
+}
+
+func (f *fakeEmbedRepo) Delete(ctx context.Context, messageID string) error {
+	delete(f.stored, messageID)
+	return nil
+}
+
+func TestUnfurlMessage(t *testing.T) {
+	const canEmbed = models.PermViewChannel | models.PermReadMessages | models.PermEmbedLinks
+
+	newMessage := func(content string) *models.Message {
+		return &models.Message{ID: "m1", ChannelID: "c1", UserID: "author", Content: &content}
+	}
+
+	setup := func(authorPerms models.Permission) (*embedService, *fakeEmbedRepo, *fakeLinkPreview, *[]ws.Event) {
+		embedRepo := &fakeEmbedRepo{}
+		fetcher := &fakeLinkPreview{preview: &models.LinkPreview{
+			URL:   "https://example.com/article",
+			Title: testutil.Ptr("An Article"),
+		}}
+		var events []ws.Event
+		hub := &testutil.MockBroadcastAndOnline{
+			GetOnlineUserIDsForServerFn: func(serverID string) []string { return []string{"author", "viewer"} },
+		}
+		hub.BroadcastToUsersFn = func(userIDs []string, event ws.Event) {
+			events = append(events, event)
+		}
+		resolver := &testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(ctx context.Context, userID, channelID string) (models.Permission, error) {
+				if userID == "author" {
+					return authorPerms, nil
+				}
+				return models.PermViewChannel | models.PermReadMessages, nil
+			},
+		}
+		channelRepo := &testutil.MockChannelRepo{
+			GetByIDFn: func(ctx context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "s1"}, nil
+			},
+		}
+		svc := NewEmbedService(embedRepo, channelRepo, fetcher, resolver, hub).(*embedService)
+		return svc, embedRepo, fetcher, &events
+	}
+
+	t.Run("stores the embed and broadcasts it", func(t *testing.T) {
+		svc, embedRepo, fetcher, events := setup(canEmbed)
+		svc.UnfurlMessage(newMessage("read https://example.com/article now"))
+
+		if fetcher.gotURL != "https://example.com/article" {
+			t.Fatalf("fetched %q, want the message's URL", fetcher.gotURL)
+		}
+		embed := embedRepo.stored["m1"]
+		if embed == nil || embed.Title == nil || *embed.Title != "An Article" {
+			t.Fatalf("stored embed = %+v, want the fetched metadata", embed)
+		}
+		if len(*events) != 1 || (*events)[0].Op != ws.OpMessageEmbedUpdate {
+			t.Fatalf("events = %+v, want one OpMessageEmbedUpdate", *events)
+		}
+	})
+
+	t.Run("author without PermEmbedLinks means no fetch", func(t *testing.T) {
+		svc, embedRepo, fetcher, events := setup(models.PermViewChannel | models.PermReadMessages)
+		svc.UnfurlMessage(newMessage("read https://example.com/article now"))
+
+		if fetcher.gotURL != "" {
+			t.Fatalf("URL was fetched despite missing permission")
+		}
+		if len(embedRepo.stored) != 0 || len(*events) != 0 {
+			t.Fatal("embed stored or broadcast despite missing permission")
+		}
+	})
+
+	t.Run("messages without links are a no-op", func(t *testing.T) {
+		svc, _, fetcher, _ := setup(canEmbed)
+		svc.UnfurlMessage(newMessage("no links here"))
+		if fetcher.gotURL != "" {
+			t.Fatalf("unexpected fetch of %q", fetcher.gotURL)
+		}
+	})
+
+	t.Run("encrypted messages are never unfurled", func(t *testing.T) {
+		svc, _, fetcher, _ := setup(canEmbed)
+		msg := newMessage("https://example.com/article")
+		msg.Content = nil
+		msg.EncryptionVersion = 1
+		svc.UnfurlMessage(msg)
+		if fetcher.gotURL != "" {
+			t.Fatalf("unexpected fetch of %q", fetcher.gotURL)
+		}
+	})
+
+	t.Run("metadata-free pages are not stored", func(t *testing.T) {
+		svc, embedRepo, fetcher, events := setup(canEmbed)
+		fetcher.preview = &models.LinkPreview{URL: "https://example.com/article"}
+		svc.UnfurlMessage(newMessage("https://example.com/article"))
+
+		if len(embedRepo.stored) != 0 || len(*events) != 0 {
+			t.Fatal("empty preview stored or broadcast")
+		}
+	})
+}
@@ -0,0 +1,451 @@
+// Package services — ExportService: owner-triggered server history export.
+//
+// StartExport kicks off an async job that streams the server's messages,
+// attachment metadata and member list into a zip archive under
+// uploads/exports/<server_id>/<job_id>.zip. Each archive entry is written
+// straight from a DB cursor — one row in memory at a time — so a large server
+// exports with flat memory. The archive is streamed back by the owner-only
+// download endpoint, never served from /api/files; the cleanup sweep reclaims
+// it after models.ExportJobTTL.
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+
+	"github.com/google/uuid"
+)
+
+// ExportService manages server history export jobs.
+type ExportService interface {
+	// StartExport validates ownership and kicks off the async job.
+	StartExport(ctx context.Context, serverID, userID, format string) (*models.ExportJob, error)
+	// GetJob returns a job for status polling. Server-scoped; only the job's creator may see it.
+	GetJob(ctx context.Context, serverID, jobID, userID string) (*models.ExportJob, error)
+	// OpenArchive re-checks ownership and opens the completed archive for streaming.
+	// The caller owns closing the returned file.
+	OpenArchive(ctx context.Context, serverID, jobID, userID string) (*os.File, *models.ExportJob, error)
+	// DeleteExpired removes jobs (and their archives) older than models.ExportJobTTL.
+	// Driven by the cleanup sweep.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// ExportServerGetter narrows ServerRepository to the lookup the export flow needs.
+type ExportServerGetter interface {
+	GetActiveByID(ctx context.Context, serverID string) (*models.Server, error)
+}
+
+type exportService struct {
+	db           *sql.DB // cross-table streaming queries, like fileCleanupService
+	jobRepo      repository.ExportJobRepository
+	serverGetter ExportServerGetter
+	uploadDir    string
+}
+
+func NewExportService(db *sql.DB, jobRepo repository.ExportJobRepository, serverGetter ExportServerGetter, uploadDir string) ExportService {
+	return &exportService{
+		db:           db,
+		jobRepo:      jobRepo,
+		serverGetter: serverGetter,
+		uploadDir:    uploadDir,
+	}
+}
+
+func (s *exportService) StartExport(ctx context.Context, serverID, userID, format string) (*models.ExportJob, error) {
+	if format == "" {
+		format = models.ExportFormatJSON
+	}
+	if !models.ValidExportFormat(format) {
+		return nil, fmt.Errorf("%w: format must be 'json' or 'csv'", pkg.ErrBadRequest)
+	}
+
+	server, err := s.serverGetter.GetActiveByID(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if server.OwnerID != userID {
+		return nil, fmt.Errorf("%w: only the server owner can export the server", pkg.ErrForbidden)
+	}
+
+	active, err := s.jobRepo.HasActive(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check active export jobs: %w", err)
+	}
+	if active {
+		return nil, fmt.Errorf("%w: an export for this server is already in progress", pkg.ErrBadRequest)
+	}
+
+	job := &models.ExportJob{
+		ID:        uuid.New().String(),
+		ServerID:  serverID,
+		UserID:    userID,
+		Format:    format,
+		Status:    models.ExportStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go s.run(job.ID, serverID, server.Name, format)
+
+	return job, nil
+}
+
+func (s *exportService) GetJob(ctx context.Context, serverID, jobID, userID string) (*models.ExportJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	// Server-scoped: a job ID from another server's URL is indistinguishable
+	// from a missing one.
+	if job.ServerID != serverID {
+		return nil, fmt.Errorf("%w: export job not found", pkg.ErrNotFound)
+	}
+	if job.UserID != userID {
+		return nil, fmt.Errorf("%w: only the owner who started the export can access it", pkg.ErrForbidden)
+	}
+	return job, nil
+}
+
+func (s *exportService) OpenArchive(ctx context.Context, serverID, jobID, userID string) (*os.File, *models.ExportJob, error) {
+	job, err := s.GetJob(ctx, serverID, jobID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != models.ExportStatusCompleted || job.FilePath == nil {
+		return nil, nil, fmt.Errorf("%w: export is not ready", pkg.ErrBadRequest)
+	}
+	f, err := os.Open(*job.FilePath)
+	if os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("%w: export archive has expired", pkg.ErrNotFound)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open export archive: %w", err)
+	}
+	return f, job, nil
+}
+
+// DeleteExpired removes jobs older than the TTL, archive first. A failed disk
+// delete keeps the row so the next sweep retries it.
+func (s *exportService) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.jobRepo.ListExpired(ctx, now.Add(-models.ExportJobTTL))
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, job := range jobs {
+		if job.FilePath != nil {
+			if err := os.Remove(*job.FilePath); err != nil && !os.IsNotExist(err) {
+				log.Printf("[export] failed to delete expired archive %s: %v", *job.FilePath, err)
+				continue
+			}
+		}
+		if err := s.jobRepo.Delete(ctx, job.ID); err != nil {
+			log.Printf("[export] failed to delete expired job %s: %v", job.ID, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// run executes one export job end to end. Detached from the request context —
+// the kick-off response has long been sent by the time a large server finishes.
+func (s *exportService) run(jobID, serverID, serverName, format string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if err := s.jobRepo.MarkRunning(ctx, jobID); err != nil {
+		log.Printf("[export] job %s: mark running failed: %v", jobID, err)
+		return
+	}
+
+	path, size, count, err := s.writeArchive(ctx, jobID, serverID, serverName, format)
+	if err != nil {
+		log.Printf("[export] job %s failed: %v", jobID, err)
+		if path != "" {
+			_ = os.Remove(path)
+		}
+		if mfErr := s.jobRepo.MarkFailed(ctx, jobID, err.Error()); mfErr != nil {
+			log.Printf("[export] job %s: mark failed failed: %v", jobID, mfErr)
+		}
+		return
+	}
+	if err := s.jobRepo.MarkCompleted(ctx, jobID, path, size, count); err != nil {
+		log.Printf("[export] job %s: mark completed failed: %v", jobID, err)
+	}
+}
+
+// writeArchive streams the server's data into the zip. Returns the archive
+// path even on error so the caller can remove the partial file.
+func (s *exportService) writeArchive(ctx context.Context, jobID, serverID, serverName, format string) (string, int64, int, error) {
+	dir := filepath.Join(s.uploadDir, "exports", serverID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", 0, 0, fmt.Errorf("create export dir: %w", err)
+	}
+	path := filepath.Join(dir, jobID+".zip")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return path, 0, 0, fmt.Errorf("create archive: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	if err := s.writeManifest(zw, serverID, serverName, format); err != nil {
+		return path, 0, 0, err
+	}
+	if err := s.writeMembers(ctx, zw, serverID, format); err != nil {
+		return path, 0, 0, err
+	}
+	count, err := s.writeMessages(ctx, zw, serverID, format)
+	if err != nil {
+		return path, 0, 0, err
+	}
+	if err := s.writeAttachments(ctx, zw, serverID, format); err != nil {
+		return path, 0, 0, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return path, 0, 0, fmt.Errorf("close archive: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return path, 0, 0, fmt.Errorf("stat archive: %w", err)
+	}
+	return path, info.Size(), count, nil
+}
+
+func (s *exportService) writeManifest(zw *zip.Writer, serverID, serverName, format string) error {
+	w, err := zw.Create("server.json")
+	if err != nil {
+		return fmt.Errorf("create manifest entry: %w", err)
+	}
+	return json.NewEncoder(w).Encode(map[string]any{
+		"server_id":   serverID,
+		"server_name": serverName,
+		"format":      format,
+		"exported_at": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Per-row export shapes. Unexported — they exist only to give the archive
+// stable field names decoupled from the API models.
+type exportMember struct {
+	UserID      string    `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name,omitempty"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+type exportMessage struct {
+	ID          string `json:"id"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	Content     string `json:"content"`
+	// Encrypted marks E2EE messages. Their ciphertext is omitted — it is
+	// useless without the members' device keys, which the server never has.
+	Encrypted bool       `json:"encrypted,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+}
+
+type exportAttachment struct {
+	MessageID string    `json:"message_id"`
+	Filename  string    `json:"filename"`
+	FileURL   string    `json:"file_url"`
+	FileSize  int64     `json:"file_size"`
+	MimeType  string    `json:"mime_type,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *exportService) writeMembers(ctx context.Context, zw *zip.Writer, serverID, format string) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT sm.user_id, u.username, COALESCE(u.display_name, ''), sm.joined_at
+		FROM server_members sm
+		JOIN users u ON u.id = sm.user_id
+		WHERE sm.server_id = ?
+		ORDER BY sm.joined_at, sm.user_id`, serverID)
+	if err != nil {
+		return fmt.Errorf("query members: %w", err)
+	}
+	defer rows.Close()
+
+	out, err := newEntryWriter(zw, "members", format,
+		[]string{"user_id", "username", "display_name", "joined_at"})
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var m exportMember
+		if err := rows.Scan(&m.UserID, &m.Username, &m.DisplayName, &m.JoinedAt); err != nil {
+			return fmt.Errorf("scan member: %w", err)
+		}
+		if err := out.write(m, []string{m.UserID, m.Username, m.DisplayName, m.JoinedAt.UTC().Format(time.RFC3339)}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate members: %w", err)
+	}
+	return out.close()
+}
+
+func (s *exportService) writeMessages(ctx context.Context, zw *zip.Writer, serverID, format string) (int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT m.id, m.channel_id, c.name, m.user_id, COALESCE(u.username, ''),
+		       COALESCE(m.content, ''), m.encryption_version, m.created_at, m.edited_at
+		FROM messages m
+		JOIN channels c ON c.id = m.channel_id
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE c.server_id = ?
+		ORDER BY m.channel_id, m.created_at, m.rowid`, serverID)
+	if err != nil {
+		return 0, fmt.Errorf("query messages: %w", err)
+	}
+	defer rows.Close()
+
+	out, err := newEntryWriter(zw, "messages", format,
+		[]string{"id", "channel_id", "channel_name", "user_id", "username", "content", "encrypted", "created_at", "edited_at"})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for rows.Next() {
+		var m exportMessage
+		var encVersion int
+		if err := rows.Scan(&m.ID, &m.ChannelID, &m.ChannelName, &m.UserID, &m.Username,
+			&m.Content, &encVersion, &m.CreatedAt, &m.EditedAt); err != nil {
+			return 0, fmt.Errorf("scan message: %w", err)
+		}
+		m.Encrypted = encVersion > 0
+		if m.Encrypted {
+			m.Content = ""
+		}
+		edited := ""
+		if m.EditedAt != nil {
+			edited = m.EditedAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{m.ID, m.ChannelID, m.ChannelName, m.UserID, m.Username, m.Content,
+			fmt.Sprintf("%t", m.Encrypted), m.CreatedAt.UTC().Format(time.RFC3339), edited}
+		if err := out.write(m, record); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate messages: %w", err)
+	}
+	return count, out.close()
+}
+
+func (s *exportService) writeAttachments(ctx context.Context, zw *zip.Writer, serverID, format string) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.message_id, a.filename, a.file_url, COALESCE(a.file_size, 0), COALESCE(a.mime_type, ''), a.created_at
+		FROM attachments a
+		JOIN messages m ON m.id = a.message_id
+		JOIN channels c ON c.id = m.channel_id
+		WHERE c.server_id = ?
+		ORDER BY a.created_at, a.id`, serverID)
+	if err != nil {
+		return fmt.Errorf("query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	out, err := newEntryWriter(zw, "attachments", format,
+		[]string{"message_id", "filename", "file_url", "file_size", "mime_type", "created_at"})
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var a exportAttachment
+		if err := rows.Scan(&a.MessageID, &a.Filename, &a.FileURL, &a.FileSize, &a.MimeType, &a.CreatedAt); err != nil {
+			return fmt.Errorf("scan attachment: %w", err)
+		}
+		record := []string{a.MessageID, a.Filename, a.FileURL,
+			fmt.Sprintf("%d", a.FileSize), a.MimeType, a.CreatedAt.UTC().Format(time.RFC3339)}
+		if err := out.write(a, record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate attachments: %w", err)
+	}
+	return out.close()
+}
+
+// entryWriter streams one archive entry row by row, as a JSON array or a CSV
+// table depending on the job's format. Neither path buffers the full data set.
+type entryWriter struct {
+	json  io.Writer
+	csv   *csv.Writer
+	first bool
+}
+
+func newEntryWriter(zw *zip.Writer, name, format string, csvHeader []string) (*entryWriter, error) {
+	if format == models.ExportFormatCSV {
+		w, err := zw.Create(name + ".csv")
+		if err != nil {
+			return nil, fmt.Errorf("create %s entry: %w", name, err)
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, fmt.Errorf("write %s header: %w", name, err)
+		}
+		return &entryWriter{csv: cw}, nil
+	}
+	w, err := zw.Create(name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("create %s entry: %w", name, err)
+	}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, fmt.Errorf("write %s: %w", name, err)
+	}
+	return &entryWriter{json: w, first: true}, nil
+}
+
+func (e *entryWriter) write(row any, record []string) error {
+	if e.csv != nil {
+		return e.csv.Write(record)
+	}
+	sep := ",\n"
+	if e.first {
+		sep = "\n"
+		e.first = false
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.json, sep); err != nil {
+		return err
+	}
+	_, err = e.json.Write(data)
+	return err
+}
+
+func (e *entryWriter) close() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+	_, err := io.WriteString(e.json, "\n]\n")
+	return err
+}
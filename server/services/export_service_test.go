@@ -0,0 +1,240 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+type stubExportServerGetter struct {
+	server *models.Server
+}
+
+func (s stubExportServerGetter) GetActiveByID(_ context.Context, serverID string) (*models.Server, error) {
+	if s.server == nil || s.server.ID != serverID {
+		return nil, pkg.ErrNotFound
+	}
+	return s.server, nil
+}
+
+// seedExportServer builds a small server: an owner and a member, one channel
+// with two plaintext messages, one encrypted message, and one attachment.
+func seedExportServer(t *testing.T, f *dbtest.Fixture) (serverID, ownerID string) {
+	t.Helper()
+	ownerID = f.User("ex_owner")
+	memberID := f.User("ex_member")
+	serverID = f.Server(dbtest.ServerSeed{ID: "ex_s", OwnerID: ownerID})
+	for _, uid := range []string{ownerID, memberID} {
+		if _, err := f.DB.Exec(
+			`INSERT INTO server_members (server_id, user_id) VALUES (?, ?)`, serverID, uid,
+		); err != nil {
+			t.Fatalf("seed member %s: %v", uid, err)
+		}
+	}
+	channelID := f.Channel("ex_c", serverID)
+	f.Message(dbtest.MessageSeed{ID: "ex_m1", ChannelID: channelID, UserID: ownerID, Content: dbtest.Ptr("hello")})
+	f.Message(dbtest.MessageSeed{ID: "ex_m2", ChannelID: channelID, UserID: memberID, Content: dbtest.Ptr("hi there")})
+	f.Message(dbtest.MessageSeed{
+		ID: "ex_m3", ChannelID: channelID, UserID: memberID,
+		EncryptionVersion: 1, Ciphertext: dbtest.Ptr("ciphertext"),
+	})
+	if _, err := f.DB.Exec(
+		`INSERT INTO attachments (id, message_id, filename, file_url, file_size, mime_type)
+		 VALUES ('ex_a1', 'ex_m1', 'photo.png', '/api/files/messages/ex_c/photo.png', 42, 'image/png')`,
+	); err != nil {
+		t.Fatalf("seed attachment: %v", err)
+	}
+	return serverID, ownerID
+}
+
+// waitForJob polls until the async run leaves pending/running.
+func waitForJob(t *testing.T, svc ExportService, serverID, jobID, userID string) *models.ExportJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := svc.GetJob(context.Background(), serverID, jobID, userID)
+		if err != nil {
+			t.Fatalf("GetJob: %v", err)
+		}
+		if job.Status == models.ExportStatusCompleted || job.Status == models.ExportStatusFailed {
+			return job
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("export job did not finish in time")
+	return nil
+}
+
+func readZipEntry(t *testing.T, zr *zip.Reader, name string) []byte {
+	t.Helper()
+	for _, entry := range zr.File {
+		if entry.Name != name {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return data
+	}
+	t.Fatalf("archive is missing entry %s", name)
+	return nil
+}
+
+func TestExportService(t *testing.T) {
+	f := dbtest.New(t)
+	serverID, ownerID := seedExportServer(t, f)
+	uploadDir := t.TempDir()
+	getter := stubExportServerGetter{server: &models.Server{ID: serverID, Name: "Export Me", OwnerID: ownerID}}
+	svc := NewExportService(f.DB, repository.NewSQLiteExportJobRepo(f.DB), getter, uploadDir)
+	ctx := context.Background()
+
+	t.Run("only the owner can start", func(t *testing.T) {
+		if _, err := svc.StartExport(ctx, serverID, "ex_member", models.ExportFormatJSON); !errors.Is(err, pkg.ErrForbidden) {
+			t.Fatalf("non-owner StartExport = %v, want ErrForbidden", err)
+		}
+	})
+
+	t.Run("unknown format rejected", func(t *testing.T) {
+		if _, err := svc.StartExport(ctx, serverID, ownerID, "xml"); !errors.Is(err, pkg.ErrBadRequest) {
+			t.Fatalf("StartExport(xml) = %v, want ErrBadRequest", err)
+		}
+	})
+
+	t.Run("json export round trip", func(t *testing.T) {
+		job, err := svc.StartExport(ctx, serverID, ownerID, "")
+		if err != nil {
+			t.Fatalf("StartExport: %v", err)
+		}
+		if job.Format != models.ExportFormatJSON {
+			t.Fatalf("Format = %q, want json default", job.Format)
+		}
+
+		done := waitForJob(t, svc, serverID, job.ID, ownerID)
+		if done.Status != models.ExportStatusCompleted {
+			t.Fatalf("job ended %q (error: %v)", done.Status, done.Error)
+		}
+		if done.MessageCount != 3 {
+			t.Fatalf("MessageCount = %d, want 3", done.MessageCount)
+		}
+
+		// The job is scoped to its server and its creator.
+		if _, err := svc.GetJob(ctx, "other_server", job.ID, ownerID); !errors.Is(err, pkg.ErrNotFound) {
+			t.Fatalf("GetJob(other server) = %v, want ErrNotFound", err)
+		}
+		if _, err := svc.GetJob(ctx, serverID, job.ID, "ex_member"); !errors.Is(err, pkg.ErrForbidden) {
+			t.Fatalf("GetJob(other user) = %v, want ErrForbidden", err)
+		}
+
+		archive, _, err := svc.OpenArchive(ctx, serverID, job.ID, ownerID)
+		if err != nil {
+			t.Fatalf("OpenArchive: %v", err)
+		}
+		defer archive.Close()
+		info, err := archive.Stat()
+		if err != nil {
+			t.Fatalf("stat archive: %v", err)
+		}
+		zr, err := zip.NewReader(archive, info.Size())
+		if err != nil {
+			t.Fatalf("open zip: %v", err)
+		}
+
+		var messages []map[string]any
+		if err := json.Unmarshal(readZipEntry(t, zr, "messages.json"), &messages); err != nil {
+			t.Fatalf("parse messages.json: %v", err)
+		}
+		if len(messages) != 3 {
+			t.Fatalf("messages.json has %d rows, want 3", len(messages))
+		}
+		for _, m := range messages {
+			if m["id"] == "ex_m3" {
+				if m["content"] != "" || m["encrypted"] != true {
+					t.Fatalf("encrypted message exported as %+v — ciphertext must stay out", m)
+				}
+			}
+		}
+
+		var members []map[string]any
+		if err := json.Unmarshal(readZipEntry(t, zr, "members.json"), &members); err != nil {
+			t.Fatalf("parse members.json: %v", err)
+		}
+		if len(members) != 2 {
+			t.Fatalf("members.json has %d rows, want 2", len(members))
+		}
+
+		var attachments []map[string]any
+		if err := json.Unmarshal(readZipEntry(t, zr, "attachments.json"), &attachments); err != nil {
+			t.Fatalf("parse attachments.json: %v", err)
+		}
+		if len(attachments) != 1 || attachments[0]["filename"] != "photo.png" {
+			t.Fatalf("attachments.json = %+v, want the seeded photo.png", attachments)
+		}
+	})
+
+	t.Run("csv export and sweep expiry", func(t *testing.T) {
+		job, err := svc.StartExport(ctx, serverID, ownerID, models.ExportFormatCSV)
+		if err != nil {
+			t.Fatalf("StartExport(csv): %v", err)
+		}
+		done := waitForJob(t, svc, serverID, job.ID, ownerID)
+		if done.Status != models.ExportStatusCompleted {
+			t.Fatalf("job ended %q (error: %v)", done.Status, done.Error)
+		}
+
+		archive, _, err := svc.OpenArchive(ctx, serverID, job.ID, ownerID)
+		if err != nil {
+			t.Fatalf("OpenArchive: %v", err)
+		}
+		info, _ := archive.Stat()
+		zr, err := zip.NewReader(archive, info.Size())
+		if err != nil {
+			t.Fatalf("open zip: %v", err)
+		}
+		records, err := csv.NewReader(bytes.NewReader(readZipEntry(t, zr, "messages.csv"))).ReadAll()
+		archive.Close()
+		if err != nil {
+			t.Fatalf("parse messages.csv: %v", err)
+		}
+		if len(records) != 4 { // header + 3 messages
+			t.Fatalf("messages.csv has %d rows, want 4", len(records))
+		}
+
+		archivePath := *done.FilePath
+		if _, err := f.DB.Exec(
+			`UPDATE export_jobs SET created_at = datetime('now', '-2 days')`,
+		); err != nil {
+			t.Fatalf("backdate jobs: %v", err)
+		}
+		n, err := svc.DeleteExpired(ctx, time.Now().UTC())
+		if err != nil {
+			t.Fatalf("DeleteExpired: %v", err)
+		}
+		if n < 1 {
+			t.Fatalf("DeleteExpired removed %d jobs, want at least 1", n)
+		}
+		if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+			t.Fatalf("expired archive still on disk: %v", err)
+		}
+		if _, err := svc.GetJob(ctx, serverID, job.ID, ownerID); !errors.Is(err, pkg.ErrNotFound) {
+			t.Fatalf("GetJob after expiry = %v, want ErrNotFound", err)
+		}
+	})
+}
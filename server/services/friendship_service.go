@@ -22,6 +22,9 @@ type FriendshipService interface {
 	RemoveFriend(ctx context.Context, userID, targetUserID string) error
 	ListFriends(ctx context.Context, userID string) ([]models.FriendshipWithUser, error)
 	ListRequests(ctx context.Context, userID string) (*FriendRequestsResponse, error)
+	// CountIncomingRequests backs the ready-event badge: how many pending
+	// requests await the user, without the full request payload.
+	CountIncomingRequests(ctx context.Context, userID string) (int, error)
 	SetDMAcceptor(acceptor DMRequestAcceptor)
 }
 
@@ -380,3 +383,7 @@ func (s *friendshipService) ListRequests(ctx context.Context, userID string) (*F
 		Outgoing: outgoing,
 	}, nil
 }
+
+func (s *friendshipService) CountIncomingRequests(ctx context.Context, userID string) (int, error) {
+	return s.friendRepo.CountIncoming(ctx, userID)
+}
@@ -0,0 +1,466 @@
+// Package services — group voice/video calls among friends.
+// Two-party calls stay pure P2P (p2p_call_service.go); from three participants
+// up, media needs an SFU, so the call gets an ad-hoc room on a platform LiveKit
+// instance and the server coordinates membership and room tokens instead of
+// relaying SDP. State is in-memory like activeCalls — a restart drops the
+// bookkeeping, and LiveKit's own room timeout reaps the orphaned room.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/ws"
+
+	"github.com/google/uuid"
+	"github.com/livekit/protocol/auth"
+)
+
+// MaxGroupCallParticipants caps a group call, caller included. Beyond this a
+// server voice channel is the right tool.
+const MaxGroupCallParticipants = 8
+
+// PlatformInstanceGetter resolves the platform LiveKit instance backing ad-hoc
+// group call rooms. ISP — satisfied by repository.LiveKitRepository.
+type PlatformInstanceGetter interface {
+	GetLeastLoadedPlatformInstance(ctx context.Context) (*models.LiveKitInstance, error)
+}
+
+// LiveKitSecretDecrypter decrypts stored LiveKit credentials. ISP — satisfied
+// by *crypto.Keychain.
+type LiveKitSecretDecrypter interface {
+	Decrypt(ciphertext string) (string, error)
+}
+
+// groupCallRoom pins the LiveKit instance a call was created on. Decrypted
+// once at initiate; every token for the call must come from the same instance
+// or later joiners would land in a different room.
+type groupCallRoom struct {
+	url       string
+	apiKey    string
+	apiSecret string
+}
+
+func (s *p2pCallService) SetGroupCallInfra(instances PlatformInstanceGetter, secrets LiveKitSecretDecrypter) {
+	s.platformInstances = instances
+	s.secretDecrypter = secrets
+}
+
+// userBusyLocked reports whether the user is reserved by any call, P2P or
+// group — one call per user covers both kinds. Caller MUST hold s.mu.
+func (s *p2pCallService) userBusyLocked(userID string) bool {
+	_, inP2P := s.userCalls[userID]
+	_, inGroup := s.userGroupCalls[userID]
+	return inP2P || inGroup
+}
+
+func (s *p2pCallService) InitiateGroupCall(callerID, sessionID string, receiverIDs []string, callType models.P2PCallType) error {
+	if callType != models.P2PCallTypeVoice && callType != models.P2PCallTypeVideo {
+		return fmt.Errorf("%w: invalid call type", pkg.ErrBadRequest)
+	}
+
+	// Dedupe and drop the caller from their own invite list.
+	seen := map[string]struct{}{callerID: {}}
+	receivers := make([]string, 0, len(receiverIDs))
+	for _, id := range receiverIDs {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		receivers = append(receivers, id)
+	}
+	if len(receivers) == 0 {
+		return fmt.Errorf("%w: no receivers", pkg.ErrBadRequest)
+	}
+	// Exactly two participants is not a group call — the direct peer link is
+	// cheaper and E2E by construction.
+	if len(receivers) == 1 {
+		return s.InitiateCall(callerID, sessionID, receivers[0], callType)
+	}
+	if len(receivers) > MaxGroupCallParticipants-1 {
+		return fmt.Errorf("%w: too many participants (max %d)", pkg.ErrBadRequest, MaxGroupCallParticipants)
+	}
+	if s.platformInstances == nil || s.secretDecrypter == nil {
+		return fmt.Errorf("%w: group calls are not available on this instance", pkg.ErrBadRequest)
+	}
+
+	ctx := context.Background()
+
+	// Same gate as a 1:1 call, per receiver: active users, accepted friendship.
+	if _, err := s.userGetter.GetActiveByID(ctx, callerID); err != nil {
+		return fmt.Errorf("%w: caller not available", pkg.ErrForbidden)
+	}
+	for _, receiverID := range receivers {
+		if _, err := s.userGetter.GetActiveByID(ctx, receiverID); err != nil {
+			return fmt.Errorf("%w: receiver is no longer available", pkg.ErrNotFound)
+		}
+		friendship, err := s.friendChecker.GetByPair(ctx, callerID, receiverID)
+		if err != nil || friendship.Status != models.FriendshipStatusAccepted {
+			return fmt.Errorf("%w: not friends", pkg.ErrForbidden)
+		}
+	}
+
+	// Reserve the room before any call state exists — DB/decrypt I/O stays
+	// outside the lock, and a failure here leaves nothing to clean up.
+	instance, err := s.platformInstances.GetLeastLoadedPlatformInstance(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: no voice infrastructure available", pkg.ErrBadRequest)
+	}
+	apiKey, err := s.secretDecrypter.Decrypt(instance.APIKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt livekit api key: %w", err)
+	}
+	apiSecret, err := s.secretDecrypter.Decrypt(instance.APISecret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt livekit api secret: %w", err)
+	}
+
+	call := &models.GroupCall{
+		ID:           uuid.New().String(),
+		CallerID:     callerID,
+		CallType:     callType,
+		CreatedAt:    time.Now().UTC(),
+		Participants: map[string]models.GroupCallParticipantState{callerID: models.GroupCallStateJoined},
+	}
+	call.RoomName = "group:" + call.ID
+
+	// Atomic busy-check + reservation, same discipline as InitiateCall. Busy
+	// receivers fail the whole initiate rather than being silently skipped —
+	// the caller picked this set of people, a partial ring should be their
+	// explicit retry, not a surprise.
+	s.mu.Lock()
+	if s.userBusyLocked(callerID) {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: already in a call", pkg.ErrBadRequest)
+	}
+	for _, receiverID := range receivers {
+		if s.userBusyLocked(receiverID) {
+			s.mu.Unlock()
+			s.hub.BroadcastToUser(callerID, ws.Event{
+				Op:   ws.OpP2PCallBusy,
+				Data: map[string]string{"receiver_id": receiverID},
+			})
+			return fmt.Errorf("%w: user is busy", pkg.ErrBadRequest)
+		}
+	}
+	for _, receiverID := range receivers {
+		call.Participants[receiverID] = models.GroupCallStateRinging
+		s.userGroupCalls[receiverID] = call.ID
+	}
+	s.groupCalls[call.ID] = call
+	s.userGroupCalls[callerID] = call.ID
+	s.groupRooms[call.ID] = groupCallRoom{url: instance.URL, apiKey: apiKey, apiSecret: apiSecret}
+	s.groupRingTimers[call.ID] = time.AfterFunc(ringingTimeout, func() { s.timeoutGroupRinging(call.ID) })
+	snapshot := snapshotParticipants(call)
+	recipients := participantIDs(call)
+	s.mu.Unlock()
+
+	// User lookups and broadcasts happen off the lock, against the snapshot —
+	// a concurrent accept can't tear the roster mid-build.
+	broadcast := s.buildGroupBroadcast(ctx, call.ID, call.CallerID, call.CallType, call.CreatedAt, snapshot)
+
+	log.Printf("[group-call] initiated: %s -> %d receivers (type=%s, id=%s)", callerID, len(receivers), callType, call.ID)
+
+	for _, uid := range recipients {
+		s.hub.BroadcastToUser(uid, ws.Event{Op: ws.OpGroupCallInitiate, Data: broadcast})
+	}
+
+	// The caller is in the room from the start.
+	s.sendGroupToken(ctx, call.ID, callerID)
+	return nil
+}
+
+func (s *p2pCallService) AcceptGroupCall(userID, callID string) error {
+	ctx := context.Background()
+
+	s.mu.Lock()
+	call, exists := s.groupCalls[callID]
+	if !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: call not found", pkg.ErrNotFound)
+	}
+	state, invited := call.Participants[userID]
+	if !invited {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: not invited to this call", pkg.ErrForbidden)
+	}
+	// Ringing -> joined is the normal accept; left -> joined is a mid-call
+	// rejoin (the invitation outlives a hang-up as long as the call does).
+	if state != models.GroupCallStateRinging && state != models.GroupCallStateLeft {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: already handled", pkg.ErrBadRequest)
+	}
+	if state == models.GroupCallStateLeft && s.userBusyLocked(userID) {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: already in a call", pkg.ErrBadRequest)
+	}
+	call.Participants[userID] = models.GroupCallStateJoined
+	s.userGroupCalls[userID] = callID
+	recipients := participantIDs(call)
+	s.mu.Unlock()
+
+	log.Printf("[group-call] %s joined call %s", userID, callID)
+
+	update := map[string]string{"call_id": callID, "user_id": userID, "state": string(models.GroupCallStateJoined)}
+	for _, uid := range recipients {
+		s.hub.BroadcastToUser(uid, ws.Event{Op: ws.OpGroupCallUpdate, Data: update})
+	}
+
+	s.sendGroupToken(ctx, callID, userID)
+	return nil
+}
+
+func (s *p2pCallService) DeclineGroupCall(userID, callID string) error {
+	return s.dropGroupParticipant(userID, callID, models.GroupCallStateRinging, models.GroupCallStateDeclined)
+}
+
+func (s *p2pCallService) LeaveGroupCall(userID, callID string) error {
+	return s.dropGroupParticipant(userID, callID, models.GroupCallStateJoined, models.GroupCallStateLeft)
+}
+
+// dropGroupParticipant is decline and leave: move one participant from
+// `from` to `to`, free their reservation, and end the call if nothing viable
+// remains (no pending ring and fewer than two people in the room).
+func (s *p2pCallService) dropGroupParticipant(userID, callID string, from, to models.GroupCallParticipantState) error {
+	s.mu.Lock()
+	call, exists := s.groupCalls[callID]
+	if !exists {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: call not found", pkg.ErrNotFound)
+	}
+	if call.Participants[userID] != from {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: already handled", pkg.ErrBadRequest)
+	}
+	call.Participants[userID] = to
+	if s.userGroupCalls[userID] == callID {
+		delete(s.userGroupCalls, userID)
+	}
+	recipients := participantIDs(call)
+	ended := s.endGroupCallIfDeadLocked(call)
+	s.mu.Unlock()
+
+	log.Printf("[group-call] %s %s call %s", userID, to, callID)
+
+	update := map[string]string{"call_id": callID, "user_id": userID, "state": string(to)}
+	for _, uid := range recipients {
+		s.hub.BroadcastToUser(uid, ws.Event{Op: ws.OpGroupCallUpdate, Data: update})
+	}
+	if ended {
+		s.broadcastGroupEnd(recipients, callID, "empty")
+	}
+	return nil
+}
+
+func (s *p2pCallService) LeaveAllGroupCalls(userID string) {
+	s.mu.RLock()
+	callID, inCall := s.userGroupCalls[userID]
+	var state models.GroupCallParticipantState
+	if inCall {
+		if call, exists := s.groupCalls[callID]; exists {
+			state = call.Participants[userID]
+		}
+	}
+	s.mu.RUnlock()
+	if !inCall {
+		return
+	}
+
+	// A disconnected ringing invitee simply missed the call; a disconnected
+	// member left the room.
+	var err error
+	switch state {
+	case models.GroupCallStateRinging:
+		err = s.DeclineGroupCall(userID, callID)
+	case models.GroupCallStateJoined:
+		err = s.LeaveGroupCall(userID, callID)
+	}
+	if err != nil {
+		log.Printf("[group-call] disconnect cleanup failed for user %s: %v", userID, err)
+	}
+}
+
+// timeoutGroupRinging fires when invitations have been out too long: everyone
+// still ringing has missed the call. Joined members are untouched — if at
+// least two people made it into the room, the call goes on without the rest.
+func (s *p2pCallService) timeoutGroupRinging(callID string) {
+	s.mu.Lock()
+	call, exists := s.groupCalls[callID]
+	if !exists {
+		delete(s.groupRingTimers, callID)
+		s.mu.Unlock()
+		return
+	}
+	delete(s.groupRingTimers, callID)
+	var missed []string
+	for uid, state := range call.Participants {
+		if state == models.GroupCallStateRinging {
+			call.Participants[uid] = models.GroupCallStateLeft
+			if s.userGroupCalls[uid] == callID {
+				delete(s.userGroupCalls, uid)
+			}
+			missed = append(missed, uid)
+		}
+	}
+	recipients := participantIDs(call)
+	ended := s.endGroupCallIfDeadLocked(call)
+	s.mu.Unlock()
+
+	if len(missed) == 0 && !ended {
+		return
+	}
+	log.Printf("[group-call] ring timeout for call %s (%d missed)", callID, len(missed))
+
+	for _, uid := range missed {
+		update := map[string]string{"call_id": callID, "user_id": uid, "state": string(models.GroupCallStateLeft)}
+		for _, recipient := range recipients {
+			s.hub.BroadcastToUser(recipient, ws.Event{Op: ws.OpGroupCallUpdate, Data: update})
+		}
+	}
+	if ended {
+		s.broadcastGroupEnd(recipients, callID, "timeout")
+	}
+}
+
+// endGroupCallIfDeadLocked tears the call down when it can no longer become a
+// conversation: nobody is still ringing and fewer than two participants are in
+// the room. Caller MUST hold s.mu; returns whether the call was ended.
+func (s *p2pCallService) endGroupCallIfDeadLocked(call *models.GroupCall) bool {
+	joined, ringing := 0, 0
+	for _, state := range call.Participants {
+		switch state {
+		case models.GroupCallStateJoined:
+			joined++
+		case models.GroupCallStateRinging:
+			ringing++
+		}
+	}
+	if ringing > 0 || joined >= 2 {
+		return false
+	}
+	for uid, state := range call.Participants {
+		if state == models.GroupCallStateJoined {
+			call.Participants[uid] = models.GroupCallStateLeft
+		}
+		if s.userGroupCalls[uid] == call.ID {
+			delete(s.userGroupCalls, uid)
+		}
+	}
+	delete(s.groupCalls, call.ID)
+	delete(s.groupRooms, call.ID)
+	if t, ok := s.groupRingTimers[call.ID]; ok {
+		t.Stop()
+		delete(s.groupRingTimers, call.ID)
+	}
+	return true
+}
+
+func (s *p2pCallService) broadcastGroupEnd(recipients []string, callID, reason string) {
+	log.Printf("[group-call] call %s ended (%s)", callID, reason)
+	data := map[string]string{"call_id": callID, "reason": reason}
+	for _, uid := range recipients {
+		s.hub.BroadcastToUser(uid, ws.Event{Op: ws.OpGroupCallEnd, Data: data})
+	}
+}
+
+// sendGroupToken mints a room token for one participant and delivers it to
+// their connections only. Never under the lock — user lookup and JWT signing
+// are I/O.
+func (s *p2pCallService) sendGroupToken(ctx context.Context, callID, userID string) {
+	s.mu.RLock()
+	call, exists := s.groupCalls[callID]
+	room, hasRoom := s.groupRooms[callID]
+	var roomName string
+	if exists {
+		roomName = call.RoomName
+	}
+	s.mu.RUnlock()
+	if !exists || !hasRoom {
+		return
+	}
+
+	name := userID
+	if user, err := s.userGetter.GetByID(ctx, userID); err == nil {
+		name = user.Username
+		if user.DisplayName != nil && *user.DisplayName != "" {
+			name = *user.DisplayName
+		}
+	}
+
+	canPublish := true
+	canSubscribe := true
+	canPublishData := true
+	grant := &auth.VideoGrant{
+		RoomJoin:       true,
+		Room:           roomName,
+		CanPublish:     &canPublish,
+		CanSubscribe:   &canSubscribe,
+		CanPublishData: &canPublishData,
+	}
+	at := auth.NewAccessToken(room.apiKey, room.apiSecret)
+	at.AddGrant(grant).
+		SetIdentity(userID).
+		SetName(name).
+		SetValidFor(24 * time.Hour)
+	token, err := at.ToJWT()
+	if err != nil {
+		s.logError(&userID, "group call token generation failed", map[string]string{
+			"call_id": callID, "error": err.Error(),
+		})
+		return
+	}
+
+	s.hub.BroadcastToUser(userID, ws.Event{
+		Op: ws.OpGroupCallToken,
+		Data: map[string]string{
+			"call_id": callID,
+			"token":   token,
+			"url":     room.url,
+		},
+	})
+}
+
+// buildGroupBroadcast builds the ring/roster payload from a participant
+// snapshot. Runs off the lock — user info lookups are DB I/O.
+func (s *p2pCallService) buildGroupBroadcast(ctx context.Context, callID, callerID string, callType models.P2PCallType, createdAt time.Time, snapshot map[string]models.GroupCallParticipantState) *models.GroupCallBroadcast {
+	b := &models.GroupCallBroadcast{
+		ID:        callID,
+		CallerID:  callerID,
+		CallType:  callType,
+		CreatedAt: createdAt,
+	}
+	for uid, state := range snapshot {
+		info := models.GroupCallParticipantInfo{UserID: uid, Username: uid, State: state}
+		if user, err := s.userGetter.GetByID(ctx, uid); err == nil {
+			info.Username = user.Username
+			info.DisplayName = user.DisplayName
+			info.AvatarURL = s.urlSigner.SignURLPtr(user.AvatarURL)
+		}
+		b.Participants = append(b.Participants, info)
+	}
+	return b
+}
+
+// snapshotParticipants copies the roster for use outside the lock.
+// Caller MUST hold s.mu.
+func snapshotParticipants(call *models.GroupCall) map[string]models.GroupCallParticipantState {
+	snapshot := make(map[string]models.GroupCallParticipantState, len(call.Participants))
+	for uid, state := range call.Participants {
+		snapshot[uid] = state
+	}
+	return snapshot
+}
+
+// participantIDs returns everyone ever attached to the call — updates and the
+// end event go to all of them, so a declined invitee's UI can settle too.
+func participantIDs(call *models.GroupCall) []string {
+	ids := make([]string, 0, len(call.Participants))
+	for uid := range call.Participants {
+		ids = append(ids, uid)
+	}
+	return ids
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/ws"
+)
+
+type fakePlatformInstances struct{}
+
+func (fakePlatformInstances) GetLeastLoadedPlatformInstance(_ context.Context) (*models.LiveKitInstance, error) {
+	return &models.LiveKitInstance{ID: "lk1", URL: "wss://lk.test", APIKey: "key", APISecret: "secret-material-long-enough-to-sign"}, nil
+}
+
+type fakeSecrets struct{}
+
+func (fakeSecrets) Decrypt(ciphertext string) (string, error) { return ciphertext, nil }
+
+func newGroupCallService(hub ws.BroadcastAndOnline) P2PCallService {
+	svc := NewP2PCallService(fakeFriendChecker{}, fakeUserGetter{}, hub, fakeURLSigner{}, time.Second)
+	svc.SetGroupCallInfra(fakePlatformInstances{}, fakeSecrets{})
+	return svc
+}
+
+// got reports whether the recording hub delivered at least one `op` to userID.
+func got(hub *recordingHub, userID, op string) bool {
+	return len(hub.eventsFor(userID, op)) > 0
+}
+
+func TestInitiateGroupCall_OneReceiverFallsBackToP2P(t *testing.T) {
+	hub := &recordingHub{}
+	svc := newGroupCallService(hub)
+
+	if err := svc.InitiateGroupCall("alice", "sess1", []string{"bob"}, models.P2PCallTypeVoice); err != nil {
+		t.Fatalf("initiate: %v", err)
+	}
+
+	call := svc.GetUserCall("alice")
+	if call == nil || call.ReceiverID != "bob" {
+		t.Fatalf("expected a plain P2P call for a single receiver, got %+v", call)
+	}
+	if got(hub, "bob", ws.OpGroupCallInitiate) {
+		t.Error("single-receiver invite must not ring as a group call")
+	}
+}
+
+func TestGroupCall_Lifecycle(t *testing.T) {
+	hub := &recordingHub{}
+	svc := newGroupCallService(hub)
+
+	if err := svc.InitiateGroupCall("alice", "sess1", []string{"bob", "carol"}, models.P2PCallTypeVoice); err != nil {
+		t.Fatalf("initiate: %v", err)
+	}
+
+	// Everyone (caller included) gets the ring; the caller also gets a room token.
+	for _, uid := range []string{"alice", "bob", "carol"} {
+		if !got(hub, uid, ws.OpGroupCallInitiate) {
+			t.Errorf("user %s did not receive the group call ring", uid)
+		}
+	}
+	if !got(hub, "alice", ws.OpGroupCallToken) {
+		t.Error("caller did not receive a room token")
+	}
+
+	// A ringing invitee is busy for 1:1 calls — same one-call-per-user rule.
+	if err := svc.InitiateCall("dave", "sess2", "bob", models.P2PCallTypeVoice); err == nil {
+		t.Error("expected busy error calling a ringing group invitee")
+	}
+
+	raw := svc.(*p2pCallService)
+	callID := raw.userGroupCalls["alice"]
+
+	if err := svc.AcceptGroupCall("bob", callID); err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	if !got(hub, "bob", ws.OpGroupCallToken) {
+		t.Error("joining invitee did not receive a room token")
+	}
+	if err := svc.DeclineGroupCall("carol", callID); err != nil {
+		t.Fatalf("decline: %v", err)
+	}
+	// Two in the room, one declined — call still alive.
+	raw.mu.RLock()
+	_, alive := raw.groupCalls[callID]
+	raw.mu.RUnlock()
+	if !alive {
+		t.Fatal("call ended while two participants were still in the room")
+	}
+
+	// Last meaningful leave collapses the call and frees everyone.
+	if err := svc.LeaveGroupCall("bob", callID); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	for _, uid := range []string{"alice", "bob", "carol"} {
+		if !got(hub, uid, ws.OpGroupCallEnd) {
+			t.Errorf("user %s did not receive the end event", uid)
+		}
+	}
+	raw.mu.RLock()
+	_, stillThere := raw.groupCalls[callID]
+	reserved := len(raw.userGroupCalls)
+	raw.mu.RUnlock()
+	if stillThere || reserved != 0 {
+		t.Errorf("group call state not cleaned up (call=%v, reservations=%d)", stillThere, reserved)
+	}
+}
@@ -0,0 +1,86 @@
+// Package services — idempotency keys for message creation.
+// A mobile client on a flaky network retries a POST whose response it never
+// saw; without dedup the retry lands as a duplicate message. The client sends
+// an Idempotency-Key header, and a repeat of a recently seen key returns the
+// original response instead of inserting again.
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// idempotencyTTL is how long a key shields against retries. Client retry
+// loops give up within seconds; a few minutes covers even a backgrounded app
+// resuming, without the store growing past recent traffic.
+const idempotencyTTL = 5 * time.Minute
+
+// maxIdempotencyKeyLen guards the store against abusive header sizes.
+const maxIdempotencyKeyLen = 128
+
+// IdempotencyService remembers the response produced for a user's
+// Idempotency-Key. In-memory with TTL, like the other ephemeral call/typing
+// state — a restart forgets keys, which at worst readmits one duplicate.
+type IdempotencyService interface {
+	// Replay returns the response stored for (userID, key) within the TTL.
+	Replay(userID, key string) (any, bool)
+	// Remember stores the response produced for (userID, key). Oversized keys
+	// are ignored rather than rejected — the request itself already succeeded.
+	Remember(userID, key string, response any)
+}
+
+type idempotencyService struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry // userID|key -> entry
+}
+
+type idempotencyEntry struct {
+	response any
+	expires  time.Time
+}
+
+// NewIdempotencyService creates the in-memory idempotency key store.
+func NewIdempotencyService() IdempotencyService {
+	return &idempotencyService{entries: make(map[string]idempotencyEntry)}
+}
+
+func idempotencyKey(userID, key string) string { return userID + "|" + key }
+
+func (s *idempotencyService) Replay(userID, key string) (any, bool) {
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[idempotencyKey(userID, key)]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(s.entries, idempotencyKey(userID, key))
+		return nil, false
+	}
+	return entry.response, true
+}
+
+func (s *idempotencyService) Remember(userID, key string, response any) {
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Opportunistic sweep: expired entries are only worth chasing once the map
+	// has grown past what the TTL window should hold.
+	if len(s.entries) > 4096 {
+		now := time.Now()
+		for k, entry := range s.entries {
+			if now.After(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	s.entries[idempotencyKey(userID, key)] = idempotencyEntry{
+		response: response,
+		expires:  time.Now().Add(idempotencyTTL),
+	}
+}
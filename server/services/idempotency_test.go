@@ -0,0 +1,49 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_ReplayIsPerUser(t *testing.T) {
+	svc := NewIdempotencyService()
+	svc.Remember("alice", "k1", "first response")
+
+	if got, ok := svc.Replay("alice", "k1"); !ok || got != "first response" {
+		t.Fatalf("Replay = %v, %v; want the stored response", got, ok)
+	}
+	// The same key from another user is a different request entirely.
+	if _, ok := svc.Replay("bob", "k1"); ok {
+		t.Error("key must be scoped per user")
+	}
+	if _, ok := svc.Replay("alice", "other"); ok {
+		t.Error("unknown key must not replay")
+	}
+}
+
+func TestIdempotency_ExpiredKeyDoesNotReplay(t *testing.T) {
+	svc := NewIdempotencyService().(*idempotencyService)
+	svc.Remember("alice", "k1", "stale")
+	entry := svc.entries[idempotencyKey("alice", "k1")]
+	entry.expires = time.Now().Add(-time.Second)
+	svc.entries[idempotencyKey("alice", "k1")] = entry
+
+	if _, ok := svc.Replay("alice", "k1"); ok {
+		t.Error("expired key must not replay")
+	}
+}
+
+func TestIdempotency_IgnoresEmptyAndOversizedKeys(t *testing.T) {
+	svc := NewIdempotencyService()
+	svc.Remember("alice", "", "nope")
+	if _, ok := svc.Replay("alice", ""); ok {
+		t.Error("empty key must never match")
+	}
+
+	long := strings.Repeat("x", maxIdempotencyKeyLen+1)
+	svc.Remember("alice", long, "nope")
+	if _, ok := svc.Replay("alice", long); ok {
+		t.Error("oversized key must be ignored")
+	}
+}
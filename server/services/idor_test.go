@@ -43,7 +43,7 @@ func TestSetOverride_CrossServerDenied(t *testing.T) {
 		&testutil.MockBroadcaster{},
 	)
 
-	err := svc.SetOverride(context.Background(), "srv-1", "chan-1", "role-1",
+	err := svc.SetOverride(context.Background(), "srv-1", "chan-1", models.OverrideTargetRole, "role-1",
 		&models.SetOverrideRequest{Allow: models.PermSendMessages})
 	if !errors.Is(err, pkg.ErrForbidden) {
 		t.Errorf("cross-server SetOverride should be forbidden, got %v", err)
@@ -67,12 +67,15 @@ func TestToggleReaction_UnauthorizedDenied(t *testing.T) {
 			},
 		},
 		&testutil.MockChannelRepo{},
+		nil, // serverRepo — unauthorized actors are rejected before any server lookup
 		&testutil.MockBroadcastAndOnline{},
 		&testutil.MockChannelPermResolver{
 			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
 				return 0, nil // no permissions on the channel
 			},
 		},
+		&testutil.MockFileURLSigner{},
+		5, 20,
 	)
 
 	err := svc.ToggleReaction(context.Background(), "m1", "attacker", "👍")
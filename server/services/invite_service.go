@@ -70,9 +70,15 @@ func (s *inviteService) Create(ctx context.Context, serverID, createdBy string,
 		ServerID:  serverID,
 		CreatedBy: &createdBy,
 		MaxUses:   req.MaxUses,
+		Temporary: req.Temporary,
 	}
 
-	if req.ExpiresIn > 0 {
+	// expires_in_seconds wins over the coarser minute-granularity expires_in when both are set.
+	switch {
+	case req.ExpiresInSeconds > 0:
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInSeconds) * time.Second)
+		invite.ExpiresAt = &expiresAt
+	case req.ExpiresIn > 0:
 		expiresAt := time.Now().Add(time.Duration(req.ExpiresIn) * time.Minute)
 		invite.ExpiresAt = &expiresAt
 	}
@@ -100,6 +106,17 @@ func (s *inviteService) ListByServer(ctx context.Context, serverID string) ([]mo
 		invites = []models.InviteWithCreator{}
 	}
 
+	// Remaining uses is derived, not stored — uses is the authoritative counter.
+	for i := range invites {
+		if invites[i].MaxUses > 0 {
+			remaining := invites[i].MaxUses - invites[i].Uses
+			if remaining < 0 {
+				remaining = 0
+			}
+			invites[i].RemainingUses = &remaining
+		}
+	}
+
 	return invites, nil
 }
 
@@ -0,0 +1,169 @@
+// Package services — per-user keyword alerts (highlight words).
+// CRUD for the user's keyword set plus the message-create matching path, which
+// runs against the channel's online viewers only.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// KeywordAlertService manages highlight words and emits keyword_highlight
+// events during message creation.
+type KeywordAlertService interface {
+	List(ctx context.Context, userID string) ([]string, error)
+	Add(ctx context.Context, userID string, req *models.AddKeywordAlertRequest) error
+	Remove(ctx context.Context, userID, keyword string) error
+	// NotifyKeywordHighlights matches message content against the viewers'
+	// keywords and sends each match a keyword_highlight event. viewerIDs is the
+	// already-filtered online+permitted audience of the message broadcast, so no
+	// extra permission work happens here. Satisfies KeywordHighlighter.
+	NotifyKeywordHighlights(message *models.Message, viewerIDs []string)
+}
+
+type keywordAlertService struct {
+	keywordRepo   repository.KeywordAlertRepository
+	channelGetter ChannelGetter
+	muteResolver  NotificationMuteResolver
+	hub           ws.Broadcaster
+}
+
+func NewKeywordAlertService(
+	keywordRepo repository.KeywordAlertRepository,
+	channelGetter ChannelGetter,
+	muteResolver NotificationMuteResolver,
+	hub ws.Broadcaster,
+) KeywordAlertService {
+	return &keywordAlertService{
+		keywordRepo:   keywordRepo,
+		channelGetter: channelGetter,
+		muteResolver:  muteResolver,
+		hub:           hub,
+	}
+}
+
+func (s *keywordAlertService) List(ctx context.Context, userID string) ([]string, error) {
+	return s.keywordRepo.ListByUser(ctx, userID)
+}
+
+func (s *keywordAlertService) Add(ctx context.Context, userID string, req *models.AddKeywordAlertRequest) error {
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+	}
+
+	existing, err := s.keywordRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(existing) >= models.MaxKeywordAlertsPerUser {
+		return fmt.Errorf("%w: at most %d keyword alerts allowed", pkg.ErrBadRequest, models.MaxKeywordAlertsPerUser)
+	}
+
+	return s.keywordRepo.Add(ctx, userID, req.Keyword)
+}
+
+func (s *keywordAlertService) Remove(ctx context.Context, userID, keyword string) error {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return fmt.Errorf("%w: keyword is required", pkg.ErrBadRequest)
+	}
+	return s.keywordRepo.Delete(ctx, userID, keyword)
+}
+
+// messageWordSet lowercases content and splits it into a set of words, so each
+// viewer's keywords are checked with O(1) lookups instead of substring scans.
+func messageWordSet(content string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(content), func(ch rune) bool {
+		return !unicode.IsLetter(ch) && !unicode.IsNumber(ch)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+func (s *keywordAlertService) NotifyKeywordHighlights(message *models.Message, viewerIDs []string) {
+	// E2EE messages have no readable content — nothing to match.
+	if message.Content == nil || *message.Content == "" || len(viewerIDs) == 0 {
+		return
+	}
+
+	candidates := make([]string, 0, len(viewerIDs))
+	for _, id := range viewerIDs {
+		if id != message.UserID { // never self-trigger
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+
+	keywordsByUser, err := s.keywordRepo.GetByUserIDs(ctx, candidates)
+	if err != nil {
+		log.Printf("[keyword] batch keyword lookup failed: %v", err)
+		return
+	}
+	if len(keywordsByUser) == 0 {
+		return
+	}
+
+	words := messageWordSet(*message.Content)
+	if len(words) == 0 {
+		return
+	}
+
+	// ServerID on the message is transient (handler-populated) — resolve it from
+	// the channel when absent so mute checks and the payload stay correct.
+	serverID := message.ServerID
+	if serverID == "" {
+		channel, err := s.channelGetter.GetByID(ctx, message.ChannelID)
+		if err != nil {
+			log.Printf("[keyword] channel lookup failed for %s: %v", message.ChannelID, err)
+			return
+		}
+		serverID = channel.ServerID
+	}
+
+	for userID, keywords := range keywordsByUser {
+		matched := ""
+		for _, kw := range keywords {
+			if words[kw] {
+				matched = kw
+				break
+			}
+		}
+		if matched == "" {
+			continue
+		}
+
+		// Mutes silence highlights the same way they silence mention badges.
+		// Non-fatal: a failed lookup means no suppression.
+		if s.muteResolver != nil {
+			if muted, err := s.muteResolver.EffectiveMutedChannelIDs(ctx, userID, serverID); err == nil && muted[message.ChannelID] {
+				continue
+			}
+		}
+
+		s.hub.BroadcastToUser(userID, ws.Event{
+			Op: ws.OpKeywordHighlight,
+			Data: ws.KeywordHighlightData{
+				MessageID: message.ID,
+				ChannelID: message.ChannelID,
+				ServerID:  serverID,
+				AuthorID:  message.UserID,
+				Keyword:   matched,
+			},
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+type fakeKeywordAlertRepo struct {
+	byUser map[string][]string
+}
+
+func (f *fakeKeywordAlertRepo) Add(_ context.Context, _, _ string) error    { return nil }
+func (f *fakeKeywordAlertRepo) Delete(_ context.Context, _, _ string) error { return nil }
+func (f *fakeKeywordAlertRepo) ListByUser(_ context.Context, userID string) ([]string, error) {
+	return f.byUser[userID], nil
+}
+func (f *fakeKeywordAlertRepo) GetByUserIDs(_ context.Context, userIDs []string) (map[string][]string, error) {
+	out := make(map[string][]string)
+	for _, id := range userIDs {
+		if kws, ok := f.byUser[id]; ok {
+			out[id] = kws
+		}
+	}
+	return out, nil
+}
+
+type fakeMuteResolver struct {
+	muted map[string]map[string]bool // userID -> channelID -> muted
+}
+
+func (f *fakeMuteResolver) EffectiveMutedChannelIDs(_ context.Context, userID, _ string) (map[string]bool, error) {
+	return f.muted[userID], nil
+}
+
+func newKeywordTestService(repo *fakeKeywordAlertRepo, mutes *fakeMuteResolver) (KeywordAlertService, *[]ws.Event) {
+	hub := &testutil.MockBroadcaster{}
+	sent := &[]ws.Event{}
+	hub.BroadcastToUserFn = func(_ string, event ws.Event) {
+		*sent = append(*sent, event)
+	}
+	channelRepo := &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+			return &models.Channel{ID: id, ServerID: "srv1"}, nil
+		},
+	}
+	return NewKeywordAlertService(repo, channelRepo, mutes, hub), sent
+}
+
+// Whole-word matching, case-insensitivity, and self-exclusion in one pass.
+func TestNotifyKeywordHighlights_MatchesWholeWords(t *testing.T) {
+	repo := &fakeKeywordAlertRepo{byUser: map[string][]string{
+		"watcher": {"deploy"},
+		"partial": {"epl"},    // substring of "deploy" — must NOT fire
+		"author":  {"deploy"}, // own message — must NOT fire
+		"silent":  {"release"},
+	}}
+	svc, sent := newKeywordTestService(repo, &fakeMuteResolver{})
+
+	content := "Starting the DEPLOY now."
+	svc.NotifyKeywordHighlights(
+		&models.Message{ID: "m1", ChannelID: "ch1", UserID: "author", Content: &content},
+		[]string{"watcher", "partial", "author", "silent"},
+	)
+
+	if len(*sent) != 1 {
+		t.Fatalf("expected exactly 1 highlight, got %d: %+v", len(*sent), *sent)
+	}
+	data, ok := (*sent)[0].Data.(ws.KeywordHighlightData)
+	if !ok {
+		t.Fatalf("unexpected payload type %T", (*sent)[0].Data)
+	}
+	if data.Keyword != "deploy" || data.MessageID != "m1" || data.ServerID != "srv1" {
+		t.Fatalf("wrong highlight payload: %+v", data)
+	}
+}
+
+// A muted channel suppresses the highlight the same way it suppresses mention badges.
+func TestNotifyKeywordHighlights_RespectsMutes(t *testing.T) {
+	repo := &fakeKeywordAlertRepo{byUser: map[string][]string{"watcher": {"deploy"}}}
+	mutes := &fakeMuteResolver{muted: map[string]map[string]bool{
+		"watcher": {"ch1": true},
+	}}
+	svc, sent := newKeywordTestService(repo, mutes)
+
+	content := "deploy done"
+	svc.NotifyKeywordHighlights(
+		&models.Message{ID: "m1", ChannelID: "ch1", UserID: "author", Content: &content},
+		[]string{"watcher"},
+	)
+
+	if len(*sent) != 0 {
+		t.Fatalf("muted channel must not produce highlights, got %+v", *sent)
+	}
+}
+
+// E2EE messages carry no readable content — the matcher must not fire (or panic).
+func TestNotifyKeywordHighlights_SkipsEncryptedMessages(t *testing.T) {
+	repo := &fakeKeywordAlertRepo{byUser: map[string][]string{"watcher": {"deploy"}}}
+	svc, sent := newKeywordTestService(repo, &fakeMuteResolver{})
+
+	svc.NotifyKeywordHighlights(
+		&models.Message{ID: "m1", ChannelID: "ch1", UserID: "author", Content: nil},
+		[]string{"watcher"},
+	)
+
+	if len(*sent) != 0 {
+		t.Fatalf("encrypted message must not produce highlights, got %+v", *sent)
+	}
+}
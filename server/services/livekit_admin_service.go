@@ -21,6 +21,7 @@ import (
 // ActiveVoiceProvider gives access to in-memory voice state (ISP for admin service).
 type ActiveVoiceProvider interface {
 	GetAllVoiceStates() []models.VoiceState
+	NotifyServerReconnect(serverID string)
 }
 
 // LiveKitAdminService manages platform-managed LiveKit instances (CRUD).
@@ -52,7 +53,7 @@ type livekitAdminService struct {
 	userRepo          repository.UserRepository
 	channelRepo       repository.ChannelRepository
 	voiceProvider     ActiveVoiceProvider
-	encryptionKey     []byte
+	keychain          *crypto.Keychain
 	httpClient        *http.Client
 	urlSigner         FileURLSigner
 	defaultQuotaBytes int64
@@ -67,7 +68,7 @@ func NewLiveKitAdminService(
 	userRepo repository.UserRepository,
 	channelRepo repository.ChannelRepository,
 	voiceProvider ActiveVoiceProvider,
-	encryptionKey []byte,
+	keychain *crypto.Keychain,
 	hetznerToken string,
 	urlSigner FileURLSigner,
 	defaultQuotaBytes int64,
@@ -78,7 +79,7 @@ func NewLiveKitAdminService(
 		userRepo:          userRepo,
 		channelRepo:       channelRepo,
 		voiceProvider:     voiceProvider,
-		encryptionKey:     encryptionKey,
+		keychain:          keychain,
 		urlSigner:         urlSigner,
 		defaultQuotaBytes: defaultQuotaBytes,
 		vcpuCache:         make(map[int64]int),
@@ -127,11 +128,11 @@ func (s *livekitAdminService) CreateInstance(ctx context.Context, req *models.Cr
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
 
-	encKey, err := crypto.Encrypt(req.APIKey, s.encryptionKey)
+	encKey, err := s.keychain.Encrypt(req.APIKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt api key: %w", err)
 	}
-	encSecret, err := crypto.Encrypt(req.APISecret, s.encryptionKey)
+	encSecret, err := s.keychain.Encrypt(req.APISecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt api secret: %w", err)
 	}
@@ -172,14 +173,14 @@ func (s *livekitAdminService) UpdateInstance(ctx context.Context, instanceID str
 		inst.URL = *req.URL
 	}
 	if req.APIKey != nil {
-		encKey, encErr := crypto.Encrypt(*req.APIKey, s.encryptionKey)
+		encKey, encErr := s.keychain.Encrypt(*req.APIKey)
 		if encErr != nil {
 			return nil, fmt.Errorf("failed to encrypt api key: %w", encErr)
 		}
 		inst.APIKey = encKey
 	}
 	if req.APISecret != nil {
-		encSecret, encErr := crypto.Encrypt(*req.APISecret, s.encryptionKey)
+		encSecret, encErr := s.keychain.Encrypt(*req.APISecret)
 		if encErr != nil {
 			return nil, fmt.Errorf("failed to encrypt api secret: %w", encErr)
 		}
@@ -188,6 +189,14 @@ func (s *livekitAdminService) UpdateInstance(ctx context.Context, instanceID str
 	if req.MaxServers != nil {
 		inst.MaxServers = *req.MaxServers
 	}
+	if req.IsDraining != nil {
+		if *req.IsDraining && !inst.IsDraining {
+			if err := s.ensureDrainLeavesCapacity(ctx, instanceID); err != nil {
+				return nil, err
+			}
+		}
+		inst.IsDraining = *req.IsDraining
+	}
 	if req.HetznerServerID != nil {
 		inst.HetznerServerID = *req.HetznerServerID
 	}
@@ -200,6 +209,25 @@ func (s *livekitAdminService) UpdateInstance(ctx context.Context, instanceID str
 	return &view, nil
 }
 
+// ensureDrainLeavesCapacity refuses to drain the last platform instance that can
+// still take new servers — mqvi-hosted server creation would start failing with
+// nothing an admin could tell from the error.
+func (s *livekitAdminService) ensureDrainLeavesCapacity(ctx context.Context, instanceID string) error {
+	instances, err := s.livekitRepo.ListPlatformInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list platform instances: %w", err)
+	}
+	for _, other := range instances {
+		if other.ID == instanceID || other.IsDraining {
+			continue
+		}
+		if other.MaxServers == 0 || other.ServerCount < other.MaxServers {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: draining this instance would leave no capacity for new servers", pkg.ErrBadRequest)
+}
+
 func (s *livekitAdminService) DeleteInstance(ctx context.Context, instanceID, targetInstanceID string) error {
 	inst, err := s.livekitRepo.GetByID(ctx, instanceID)
 	if err != nil {
@@ -229,6 +257,12 @@ func (s *livekitAdminService) DeleteInstance(ctx context.Context, instanceID, ta
 			return fmt.Errorf("%w: migration target must be a platform-managed instance", pkg.ErrBadRequest)
 		}
 
+		// Migrating onto an instance that is itself being emptied defeats the
+		// point of draining — drain first, migrate to a healthy instance, delete.
+		if target.IsDraining {
+			return fmt.Errorf("%w: migration target is draining", pkg.ErrBadRequest)
+		}
+
 		_, migrateErr := s.livekitRepo.MigrateServers(ctx, instanceID, targetInstanceID)
 		if migrateErr != nil {
 			return fmt.Errorf("failed to migrate servers: %w", migrateErr)
@@ -297,6 +331,13 @@ func (s *livekitAdminService) MigrateServerInstance(ctx context.Context, serverI
 		return fmt.Errorf("failed to migrate server instance: %w", err)
 	}
 
+	// Anyone connected to voice holds a token for the old instance and would be
+	// silently stranded there. Tell them to re-request — the fresh token derives
+	// its URL from the row just updated. Other servers' users are untouched.
+	if s.voiceProvider != nil {
+		s.voiceProvider.NotifyServerReconnect(serverID)
+	}
+
 	return nil
 }
 
@@ -497,6 +538,7 @@ func toAdminView(inst *models.LiveKitInstance) models.LiveKitInstanceAdminView {
 		IsPlatformManaged: inst.IsPlatformManaged,
 		ServerCount:       inst.ServerCount,
 		MaxServers:        inst.MaxServers,
+		IsDraining:        inst.IsDraining,
 		HetznerServerID:   inst.HetznerServerID,
 		CreatedAt:         inst.CreatedAt,
 	}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+)
+
+// drainLiveKitRepo serves a fixed instance list and captures updates.
+type drainLiveKitRepo struct {
+	repository.LiveKitRepository
+	instances []models.LiveKitInstance
+	updated   *models.LiveKitInstance
+}
+
+func (r *drainLiveKitRepo) GetByID(_ context.Context, id string) (*models.LiveKitInstance, error) {
+	for i := range r.instances {
+		if r.instances[i].ID == id {
+			inst := r.instances[i]
+			return &inst, nil
+		}
+	}
+	return nil, pkg.ErrNotFound
+}
+
+func (r *drainLiveKitRepo) ListPlatformInstances(_ context.Context) ([]models.LiveKitInstance, error) {
+	return r.instances, nil
+}
+
+func (r *drainLiveKitRepo) Update(_ context.Context, instance *models.LiveKitInstance) error {
+	r.updated = instance
+	return nil
+}
+
+func newDrainTestService(repo *drainLiveKitRepo) LiveKitAdminService {
+	return NewLiveKitAdminService(repo, nil, nil, nil, nil, nil, "", nil, 0)
+}
+
+func TestInstanceDrain_GuardedByRemainingCapacity(t *testing.T) {
+	ctx := context.Background()
+	drain := true
+
+	// The only other instance is full — draining i1 would leave nowhere for new servers.
+	repo := &drainLiveKitRepo{instances: []models.LiveKitInstance{
+		{ID: "i1", IsPlatformManaged: true},
+		{ID: "i2", IsPlatformManaged: true, ServerCount: 1, MaxServers: 1},
+	}}
+	svc := newDrainTestService(repo)
+
+	_, err := svc.UpdateInstance(ctx, "i1", &models.UpdateLiveKitInstanceRequest{IsDraining: &drain})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("draining the last instance with capacity: got %v, want ErrBadRequest", err)
+	}
+	if repo.updated != nil {
+		t.Fatal("refused drain still reached the repository")
+	}
+
+	// With a third instance that has headroom, the same drain goes through.
+	repo.instances = append(repo.instances, models.LiveKitInstance{ID: "i3", IsPlatformManaged: true, ServerCount: 2, MaxServers: 5})
+	view, err := svc.UpdateInstance(ctx, "i1", &models.UpdateLiveKitInstanceRequest{IsDraining: &drain})
+	if err != nil {
+		t.Fatalf("drain with capacity elsewhere: %v", err)
+	}
+	if !view.IsDraining || repo.updated == nil || !repo.updated.IsDraining {
+		t.Fatal("drain flag was not persisted and reflected in the admin view")
+	}
+
+	// Un-draining is never guarded — it only adds capacity back.
+	repo.instances[0].IsDraining = true
+	undrain := false
+	if _, err := svc.UpdateInstance(ctx, "i1", &models.UpdateLiveKitInstanceRequest{IsDraining: &undrain}); err != nil {
+		t.Fatalf("un-drain: %v", err)
+	}
+}
+
+func TestDeleteInstance_RefusesDrainingMigrationTarget(t *testing.T) {
+	repo := &drainLiveKitRepo{instances: []models.LiveKitInstance{
+		{ID: "i1", IsPlatformManaged: true, ServerCount: 3},
+		{ID: "i2", IsPlatformManaged: true, IsDraining: true},
+	}}
+	svc := newDrainTestService(repo)
+
+	err := svc.DeleteInstance(context.Background(), "i1", "i2")
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("migrating onto a draining instance: got %v, want ErrBadRequest", err)
+	}
+}
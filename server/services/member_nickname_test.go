@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// nicknameServerRepo embeds the interface so only the membership-scoped
+// nickname/timeout reads the flow touches need to exist.
+type nicknameServerRepo struct {
+	repository.ServerRepository
+	nickname  *string
+	setCalled bool
+}
+
+func (r *nicknameServerRepo) SetMemberNickname(_ context.Context, _, _ string, nickname *string) error {
+	r.setCalled = true
+	r.nickname = nickname
+	return nil
+}
+
+func (r *nicknameServerRepo) GetMemberNickname(context.Context, string, string) (*string, error) {
+	return r.nickname, nil
+}
+
+func (r *nicknameServerRepo) GetMemberTimeout(context.Context, string, string) (*time.Time, error) {
+	return nil, nil
+}
+
+func nicknameTestService(repo *nicknameServerRepo, rolesByUser map[string][]models.Role) (*memberService, *testutil.MockEventPublisher) {
+	hub := &testutil.MockEventPublisher{}
+	return &memberService{
+		userRepo: &testutil.MockUserRepo{
+			GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+				return &models.User{ID: id, Username: id}, nil
+			},
+		},
+		roleRepo: &testutil.MockRoleRepo{
+			GetByUserIDAndServerFn: func(_ context.Context, userID, _ string) ([]models.Role, error) {
+				return rolesByUser[userID], nil
+			},
+		},
+		serverRepo: repo,
+		hub:        hub,
+		urlSigner:  &testutil.MockFileURLSigner{},
+	}, hub
+}
+
+func TestUpdateNickname_SelfSetAndBroadcast(t *testing.T) {
+	repo := &nicknameServerRepo{}
+	svc, hub := nicknameTestService(repo, nil)
+
+	var broadcast *models.MemberWithRoles
+	hub.BroadcastToServerFn = func(_ string, e ws.Event) {
+		if e.Op == ws.OpMemberUpdate {
+			broadcast = e.Data.(*models.MemberWithRoles)
+		}
+	}
+
+	nick := "  Captain Alice  "
+	member, err := svc.UpdateNickname(context.Background(), "s1", "alice", "alice", &models.UpdateNicknameRequest{Nickname: &nick})
+	if err != nil {
+		t.Fatalf("UpdateNickname: %v", err)
+	}
+	if !repo.setCalled || repo.nickname == nil || *repo.nickname != "Captain Alice" {
+		t.Fatalf("persisted nickname = %v, want trimmed %q", repo.nickname, "Captain Alice")
+	}
+	if member.Nickname == nil || *member.Nickname != "Captain Alice" {
+		t.Fatalf("returned member nickname = %v, want Captain Alice", member.Nickname)
+	}
+	if broadcast == nil || broadcast.Nickname == nil || *broadcast.Nickname != "Captain Alice" {
+		t.Fatalf("OpMemberUpdate broadcast = %+v, want the new nickname", broadcast)
+	}
+}
+
+func TestUpdateNickname_NilRemovesBlankRejected(t *testing.T) {
+	repo := &nicknameServerRepo{nickname: testutil.Ptr("Old")}
+	svc, _ := nicknameTestService(repo, nil)
+	ctx := context.Background()
+
+	if _, err := svc.UpdateNickname(ctx, "s1", "alice", "alice", &models.UpdateNicknameRequest{}); err != nil {
+		t.Fatalf("removal: %v", err)
+	}
+	if !repo.setCalled || repo.nickname != nil {
+		t.Fatalf("removal persisted %v, want nil", repo.nickname)
+	}
+
+	blank := "   "
+	if _, err := svc.UpdateNickname(ctx, "s1", "alice", "alice", &models.UpdateNicknameRequest{Nickname: &blank}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("blank nickname = %v, want ErrBadRequest", err)
+	}
+	long := strings.Repeat("x", models.MaxNicknameLength+1)
+	if _, err := svc.UpdateNickname(ctx, "s1", "alice", "alice", &models.UpdateNicknameRequest{Nickname: &long}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("overlong nickname = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestUpdateNickname_HierarchyGuardsRenamingOthers(t *testing.T) {
+	roles := map[string][]models.Role{
+		"owner": {{IsOwner: true}},
+		"mod":   {{Position: 5}},
+		"peer":  {{Position: 5}},
+		"low":   {{Position: 1}},
+	}
+	nick := "Renamed"
+	ctx := context.Background()
+
+	t.Run("owner cannot be renamed by others", func(t *testing.T) {
+		svc, _ := nicknameTestService(&nicknameServerRepo{}, roles)
+		if _, err := svc.UpdateNickname(ctx, "s1", "mod", "owner", &models.UpdateNicknameRequest{Nickname: &nick}); !errors.Is(err, pkg.ErrForbidden) {
+			t.Fatalf("rename owner = %v, want ErrForbidden", err)
+		}
+	})
+
+	t.Run("equal position is rejected", func(t *testing.T) {
+		svc, _ := nicknameTestService(&nicknameServerRepo{}, roles)
+		if _, err := svc.UpdateNickname(ctx, "s1", "mod", "peer", &models.UpdateNicknameRequest{Nickname: &nick}); !errors.Is(err, pkg.ErrForbidden) {
+			t.Fatalf("rename peer = %v, want ErrForbidden", err)
+		}
+	})
+
+	t.Run("higher position may rename lower", func(t *testing.T) {
+		repo := &nicknameServerRepo{}
+		svc, _ := nicknameTestService(repo, roles)
+		if _, err := svc.UpdateNickname(ctx, "s1", "mod", "low", &models.UpdateNicknameRequest{Nickname: &nick}); err != nil {
+			t.Fatalf("rename low = %v", err)
+		}
+		if !repo.setCalled {
+			t.Fatal("nickname was not persisted")
+		}
+	})
+}
+
+func TestEffectiveMemberName_Precedence(t *testing.T) {
+	nick, display := "Nick", "Display"
+	if got := models.EffectiveMemberName(&nick, &display, "user"); got != "Nick" {
+		t.Errorf("nickname should win, got %q", got)
+	}
+	if got := models.EffectiveMemberName(nil, &display, "user"); got != "Display" {
+		t.Errorf("display_name should be second, got %q", got)
+	}
+	if got := models.EffectiveMemberName(nil, nil, "user"); got != "user" {
+		t.Errorf("username is the fallback, got %q", got)
+	}
+}
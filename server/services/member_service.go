@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
@@ -17,20 +18,44 @@ import (
 
 // MemberService handles member management. All operations are server-scoped.
 type MemberService interface {
-	GetAll(ctx context.Context, serverID string) ([]models.MemberWithRoles, error)
+	// List returns one keyset page of members with batch-loaded roles, plus the
+	// total under the same filters. Real-time joins/leaves stay on the WS events;
+	// this is for the initial load and admin browsing.
+	List(ctx context.Context, serverID string, params models.MemberListParams) (*models.MemberListPage, error)
 	GetByID(ctx context.Context, serverID, userID string) (*models.MemberWithRoles, error)
 	UpdateProfile(ctx context.Context, userID string, req *models.UpdateProfileRequest) (*models.MemberWithRoles, error)
+	// UpdateNickname sets (nil removes) the target's per-server nickname and
+	// broadcasts the change. Self-updates are always allowed; renaming someone
+	// else is gated by PermManageNicknames at the route plus role hierarchy here.
+	UpdateNickname(ctx context.Context, serverID, actorID, targetID string, req *models.UpdateNicknameRequest) (*models.MemberWithRoles, error)
 	UpdatePresence(ctx context.Context, userID string, status models.UserStatus) error
+	// UpdateCustomStatus sets (empty text and emoji clear) the user's custom
+	// status message and broadcasts it to friends and shared-server members.
+	UpdateCustomStatus(ctx context.Context, userID string, req *models.UpdateCustomStatusRequest) (*models.User, error)
 	ModifyRoles(ctx context.Context, serverID, actorID, targetID string, roleIDs []string) (*models.MemberWithRoles, error)
 	Kick(ctx context.Context, serverID, actorID, targetID string) error
 	Ban(ctx context.Context, serverID, actorID, targetID, reason string) error
-	Unban(ctx context.Context, serverID, userID string) error
+	// Timeout sets (duration 0 clears) a temporary moderation timeout on the member.
+	Timeout(ctx context.Context, serverID, actorID, targetID string, req *models.TimeoutRequest) (*models.MemberWithRoles, error)
+	// IsTimedOut reports whether the member currently has an unexpired timeout.
+	IsTimedOut(ctx context.Context, serverID, userID string) (bool, error)
+	// StartTimeoutSweeper clears expired timeouts in the background and tells clients.
+	StartTimeoutSweeper()
+	// StartCustomStatusSweeper clears expired custom statuses in the background and tells clients.
+	StartCustomStatusSweeper()
+	Unban(ctx context.Context, serverID, actorID, targetID string) error
 	GetBans(ctx context.Context, serverID string) ([]models.Ban, error)
 	IsBanned(ctx context.Context, serverID, userID string) (bool, error)
 	// SetVoiceEnforcer wires the voice enforcer post-construction.
 	SetVoiceEnforcer(enforcer VoiceUserPermissionEnforcer)
 	// SetPermCacheInvalidator wires the permission-cache invalidator post-construction.
 	SetPermCacheInvalidator(inv PermissionCacheInvalidator)
+	// SetNameFilter wires the instance name blocklist post-construction.
+	SetNameFilter(filter NameFilter)
+	// SetAuditRecorder wires the audit log post-construction.
+	SetAuditRecorder(rec AuditRecorder)
+	// SetPresenceAudience wires the presence audience resolver post-construction.
+	SetPresenceAudience(aud PresenceAudienceService)
 }
 
 // VoiceDisconnecter disconnects a user from voice on kick/ban (ISP).
@@ -50,16 +75,27 @@ type VoiceUserPermissionEnforcer interface {
 	EnforceUserVoicePermissions(userID string)
 }
 
+// MemberTimeoutChecker gates actions for members under a moderation timeout.
+// Implemented by memberService; consumed by message/reaction/voice services
+// (wired post-construction).
+type MemberTimeoutChecker interface {
+	IsTimedOut(ctx context.Context, serverID, userID string) (bool, error)
+}
+
 type memberService struct {
 	userRepo      repository.UserRepository
 	roleRepo      repository.RoleRepository
 	banRepo       repository.BanRepository
 	serverRepo    repository.ServerRepository
+	friendshipRepo  repository.FriendshipRepository // may be nil in tests; only used for broadcast fan-out
 	hub           ws.BroadcastAndManage
 	voiceKick     VoiceDisconnecter
 	voiceProfile  VoiceProfileSyncer
 	voiceEnforcer   VoiceUserPermissionEnforcer // set post-construction, may be nil
 	permInvalidator PermissionCacheInvalidator  // set post-construction, may be nil
+	nameFilter      NameFilter                  // set post-construction, may be nil
+	audit           AuditRecorder               // set post-construction, may be nil
+	presenceAudience PresenceAudienceService    // set post-construction, may be nil
 	urlSigner       FileURLSigner
 }
 
@@ -67,65 +103,73 @@ func (s *memberService) SetVoiceEnforcer(enforcer VoiceUserPermissionEnforcer) {
 	s.voiceEnforcer = enforcer
 }
 
+func (s *memberService) SetNameFilter(filter NameFilter) {
+	s.nameFilter = filter
+}
+
 func (s *memberService) SetPermCacheInvalidator(inv PermissionCacheInvalidator) {
 	s.permInvalidator = inv
 }
 
+func (s *memberService) SetAuditRecorder(rec AuditRecorder) {
+	s.audit = rec
+}
+
+func (s *memberService) SetPresenceAudience(aud PresenceAudienceService) {
+	s.presenceAudience = aud
+}
+
 func NewMemberService(
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
 	banRepo repository.BanRepository,
 	serverRepo repository.ServerRepository,
+	friendshipRepo repository.FriendshipRepository,
 	hub ws.BroadcastAndManage,
 	voiceKick VoiceDisconnecter,
 	voiceProfile VoiceProfileSyncer,
 	urlSigner FileURLSigner,
 ) MemberService {
 	return &memberService{
-		userRepo:     userRepo,
-		roleRepo:     roleRepo,
-		banRepo:      banRepo,
-		serverRepo:   serverRepo,
-		hub:          hub,
-		voiceKick:    voiceKick,
-		voiceProfile: voiceProfile,
-		urlSigner:    urlSigner,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		banRepo:        banRepo,
+		serverRepo:     serverRepo,
+		friendshipRepo: friendshipRepo,
+		hub:            hub,
+		voiceKick:      voiceKick,
+		voiceProfile:   voiceProfile,
+		urlSigner:      urlSigner,
 	}
 }
 
-func (s *memberService) GetAll(ctx context.Context, serverID string) ([]models.MemberWithRoles, error) {
-	users, err := s.userRepo.GetAll(ctx)
+func (s *memberService) List(ctx context.Context, serverID string, params models.MemberListParams) (*models.MemberListPage, error) {
+	rows, total, err := s.serverRepo.ListMemberPage(ctx, serverID, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all users: %w", err)
+		return nil, fmt.Errorf("failed to list members: %w", err)
 	}
 
-	members := make([]models.MemberWithRoles, 0)
-	for i := range users {
-		// Skip soft-deleted/tombstone users — they shouldn't appear in active member lists.
-		// Their messages stay (tombstone preserves messages.user_id), but they themselves
-		// are no longer "members" of the server in the UI sense.
-		if users[i].DeletedAt != nil {
-			continue
-		}
-
-		isMember, err := s.serverRepo.IsMember(ctx, serverID, users[i].ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to check membership: %w", err)
-		}
-		if !isMember {
-			continue
-		}
+	userIDs := make([]string, len(rows))
+	for i := range rows {
+		userIDs[i] = rows[i].User.ID
+	}
+	rolesByUser, err := s.roleRepo.GetByServerForUsers(ctx, serverID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load roles for member page: %w", err)
+	}
 
-		roles, err := s.roleRepo.GetByUserIDAndServer(ctx, users[i].ID, serverID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get roles for user %s: %w", users[i].ID, err)
-		}
-		m := models.ToMemberWithRoles(&users[i], roles)
+	members := make([]models.MemberWithRoles, 0, len(rows))
+	for i := range rows {
+		m := models.ToMemberWithRoles(&rows[i].User, rolesByUser[rows[i].User.ID])
 		m.AvatarURL = s.urlSigner.SignURLPtr(m.AvatarURL)
+		if until := rows[i].TimeoutUntil; until != nil && until.After(time.Now()) {
+			m.TimeoutUntil = until
+		}
+		m.Nickname = rows[i].Nickname
 		members = append(members, m)
 	}
 
-	return members, nil
+	return &models.MemberListPage{Members: members, Total: total}, nil
 }
 
 func (s *memberService) GetByID(ctx context.Context, serverID, userID string) (*models.MemberWithRoles, error) {
@@ -142,6 +186,22 @@ func (s *memberService) GetByID(ctx context.Context, serverID, userID string) (*
 
 	member := models.ToMemberWithRoles(user, roles)
 	member.AvatarURL = s.urlSigner.SignURLPtr(member.AvatarURL)
+
+	// Expired timeouts may linger in the column until the sweeper runs — never
+	// surface one to clients.
+	until, err := s.serverRepo.GetMemberTimeout(ctx, serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if until != nil && until.After(time.Now()) {
+		member.TimeoutUntil = until
+	}
+
+	nickname, err := s.serverRepo.GetMemberNickname(ctx, serverID, userID)
+	if err != nil {
+		return nil, err
+	}
+	member.Nickname = nickname
 	return &member, nil
 }
 
@@ -150,6 +210,15 @@ func (s *memberService) UpdateProfile(ctx context.Context, userID string, req *m
 		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
 	}
 
+	// Same generic rejection as registration — the matched term must not leak.
+	if s.nameFilter != nil {
+		for _, n := range []*string{req.Username, req.DisplayName} {
+			if n != nil && s.nameFilter.Blocked(*n) {
+				return nil, pkg.WithCode(fmt.Errorf("%w: that name isn't allowed", pkg.ErrBadRequest), pkg.CodeNameNotAllowed)
+			}
+		}
+	}
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -244,22 +313,156 @@ func (s *memberService) UpdateProfile(ctx context.Context, userID string, req *m
 	return &member, nil
 }
 
+func (s *memberService) UpdateNickname(ctx context.Context, serverID, actorID, targetID string, req *models.UpdateNicknameRequest) (*models.MemberWithRoles, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	// Same generic rejection as registration — the matched term must not leak.
+	if req.Nickname != nil && s.nameFilter != nil && s.nameFilter.Blocked(*req.Nickname) {
+		return nil, pkg.WithCode(fmt.Errorf("%w: that name isn't allowed", pkg.ErrBadRequest), pkg.CodeNameNotAllowed)
+	}
+
+	if actorID != targetID {
+		targetRoles, err := s.roleRepo.GetByUserIDAndServer(ctx, targetID, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get target roles: %w", err)
+		}
+		if models.HasOwnerRole(targetRoles) {
+			return nil, fmt.Errorf("%w: the server owner cannot be renamed by others", pkg.ErrForbidden)
+		}
+		actorRoles, err := s.roleRepo.GetByUserIDAndServer(ctx, actorID, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get actor roles: %w", err)
+		}
+		if models.HighestPosition(actorRoles) <= models.HighestPosition(targetRoles) {
+			return nil, fmt.Errorf("%w: cannot rename a member with equal or higher role", pkg.ErrForbidden)
+		}
+	}
+
+	if err := s.serverRepo.SetMemberNickname(ctx, serverID, targetID, req.Nickname); err != nil {
+		return nil, err
+	}
+
+	if s.audit != nil && actorID != targetID {
+		detail := map[string]any{"removed": true}
+		if req.Nickname != nil {
+			detail = map[string]any{"nickname": *req.Nickname}
+		}
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionMemberNickname, targetID, detail)
+	}
+
+	member, err := s.GetByID(ctx, serverID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients re-render the member's name in rosters and messages off this broadcast.
+	s.hub.BroadcastToServer(serverID, ws.Event{
+		Op:   ws.OpMemberUpdate,
+		Data: member,
+	})
+
+	return member, nil
+}
+
 func (s *memberService) UpdatePresence(ctx context.Context, userID string, status models.UserStatus) error {
 	if err := s.userRepo.UpdateStatus(ctx, userID, status); err != nil {
 		return fmt.Errorf("failed to update presence: %w", err)
 	}
 
-	s.hub.BroadcastToAll(ws.Event{
+	event := ws.Event{
 		Op: ws.OpPresence,
 		Data: map[string]string{
 			"user_id": userID,
 			"status":  string(status),
 		},
-	})
+	}
+	// Presence is scoped to shared-server members + friends; without a
+	// resolver (tests) fall back to the old instance-wide broadcast.
+	if s.presenceAudience != nil {
+		recipients, err := s.presenceAudience.Recipients(ctx, userID)
+		if err != nil {
+			log.Printf("[member] failed to resolve presence audience for user %s: %v", userID, err)
+			return nil
+		}
+		s.hub.BroadcastToUsers(recipients, event)
+	} else {
+		s.hub.BroadcastToAll(event)
+	}
 
 	return nil
 }
 
+func (s *memberService) UpdateCustomStatus(ctx context.Context, userID string, req *models.UpdateCustomStatusRequest) (*models.User, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	var text, emoji *string
+	var expiresAt *time.Time
+	if req.Text != "" || req.Emoji != "" {
+		if req.Text != "" {
+			text = &req.Text
+		}
+		if req.Emoji != "" {
+			emoji = &req.Emoji
+		}
+		if req.ClearAfterSeconds > 0 {
+			t := time.Now().Add(time.Duration(req.ClearAfterSeconds) * time.Second)
+			expiresAt = &t
+		}
+	}
+
+	if err := s.userRepo.UpdateCustomStatus(ctx, userID, text, emoji, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to update custom status: %w", err)
+	}
+
+	user, err := s.userRepo.GetActiveByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.broadcastCustomStatus(ctx, user)
+	return user, nil
+}
+
+// broadcastCustomStatus fans the change out to everyone who can already see the
+// user: shared-server members via their server subscriptions, friends directly
+// (they may share no server), plus the user's own other devices. There is no
+// BroadcastToAll here — strangers don't get status messages.
+func (s *memberService) broadcastCustomStatus(ctx context.Context, user *models.User) {
+	event := ws.Event{
+		Op: ws.OpCustomStatusUpdate,
+		Data: ws.CustomStatusUpdateData{
+			UserID:            user.ID,
+			CustomStatus:      user.CustomStatus,
+			CustomStatusEmoji: user.CustomStatusEmoji,
+			ExpiresAt:         user.CustomStatusExpiresAt,
+		},
+	}
+
+	servers, err := s.serverRepo.GetUserServers(ctx, user.ID)
+	if err != nil {
+		log.Printf("[member] custom status broadcast: server list for %s failed: %v", user.ID, err)
+	}
+	for _, srv := range servers {
+		s.hub.BroadcastToServer(srv.ID, event)
+	}
+
+	if s.friendshipRepo != nil {
+		friends, err := s.friendshipRepo.ListFriends(ctx, user.ID)
+		if err != nil {
+			log.Printf("[member] custom status broadcast: friend list for %s failed: %v", user.ID, err)
+		}
+		for _, f := range friends {
+			s.hub.BroadcastToUser(f.UserID, event)
+		}
+	}
+
+	s.hub.BroadcastToUser(user.ID, event)
+}
+
 func (s *memberService) ModifyRoles(ctx context.Context, serverID, actorID, targetID string, roleIDs []string) (*models.MemberWithRoles, error) {
 	actorRoles, err := s.roleRepo.GetByUserIDAndServer(ctx, actorID, serverID)
 	if err != nil {
@@ -343,6 +546,11 @@ func (s *memberService) ModifyRoles(ctx context.Context, serverID, actorID, targ
 		go s.voiceEnforcer.EnforceUserVoicePermissions(targetID)
 	}
 
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionMemberRoles, targetID,
+			map[string]any{"role_ids": roleIDs})
+	}
+
 	// Role changes are server-scoped — only broadcast to that server
 	s.hub.BroadcastToServer(serverID, ws.Event{
 		Op:   ws.OpMemberUpdate,
@@ -366,6 +574,10 @@ func (s *memberService) Kick(ctx context.Context, serverID, actorID, targetID st
 	}
 
 	s.removeFromServer(serverID, targetID)
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionMemberKick, targetID, nil)
+	}
 	return nil
 }
 
@@ -401,9 +613,136 @@ func (s *memberService) Ban(ctx context.Context, serverID, actorID, targetID, re
 	}
 
 	s.removeFromServer(serverID, targetID)
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionMemberBan, targetID,
+			map[string]string{"reason": reason})
+	}
 	return nil
 }
 
+func (s *memberService) Timeout(ctx context.Context, serverID, actorID, targetID string, req *models.TimeoutRequest) (*models.MemberWithRoles, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+	if actorID == targetID {
+		return nil, fmt.Errorf("%w: cannot time out yourself", pkg.ErrBadRequest)
+	}
+
+	targetRoles, err := s.roleRepo.GetByUserIDAndServer(ctx, targetID, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target roles: %w", err)
+	}
+	if models.HasOwnerRole(targetRoles) {
+		return nil, fmt.Errorf("%w: the server owner cannot be timed out", pkg.ErrForbidden)
+	}
+
+	actorRoles, err := s.roleRepo.GetByUserIDAndServer(ctx, actorID, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actor roles: %w", err)
+	}
+	if models.HighestPosition(actorRoles) <= models.HighestPosition(targetRoles) {
+		return nil, fmt.Errorf("%w: cannot time out a member with equal or higher role", pkg.ErrForbidden)
+	}
+
+	var until *time.Time
+	if req.DurationSeconds > 0 {
+		t := time.Now().Add(time.Duration(req.DurationSeconds) * time.Second)
+		until = &t
+	}
+	if err := s.serverRepo.SetMemberTimeout(ctx, serverID, targetID, until); err != nil {
+		return nil, err
+	}
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionMemberTimeout, targetID,
+			map[string]any{"duration_seconds": req.DurationSeconds})
+	}
+
+	member, err := s.GetByID(ctx, serverID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients grey out (or restore) the member off this broadcast.
+	s.hub.BroadcastToServer(serverID, ws.Event{
+		Op:   ws.OpMemberUpdate,
+		Data: member,
+	})
+
+	return member, nil
+}
+
+func (s *memberService) IsTimedOut(ctx context.Context, serverID, userID string) (bool, error) {
+	until, err := s.serverRepo.GetMemberTimeout(ctx, serverID, userID)
+	if err != nil {
+		return false, err
+	}
+	return until != nil && until.After(time.Now()), nil
+}
+
+// StartTimeoutSweeper clears expired timeouts once a minute. Enforcement reads
+// the timestamp directly, so the sweep only exists to reset the column and
+// broadcast the restored member to clients.
+func (s *memberService) StartTimeoutSweeper() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.sweepExpiredTimeouts()
+		}
+	}()
+}
+
+func (s *memberService) sweepExpiredTimeouts() {
+	ctx := context.Background()
+	expired, err := s.serverRepo.ClearExpiredTimeouts(ctx, time.Now())
+	if err != nil {
+		log.Printf("[member] timeout sweep failed: %v", err)
+		return
+	}
+	for _, e := range expired {
+		member, err := s.GetByID(ctx, e.ServerID, e.UserID)
+		if err != nil {
+			log.Printf("[member] timeout sweep: reload member %s failed: %v", e.UserID, err)
+			continue
+		}
+		s.hub.BroadcastToServer(e.ServerID, ws.Event{
+			Op:   ws.OpMemberUpdate,
+			Data: member,
+		})
+	}
+}
+
+// StartCustomStatusSweeper clears expired custom statuses once a minute. Reads
+// already present a past expiry as cleared, so the sweep only exists to null
+// the columns and broadcast the cleared status.
+func (s *memberService) StartCustomStatusSweeper() {
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.sweepExpiredCustomStatuses()
+		}
+	}()
+}
+
+func (s *memberService) sweepExpiredCustomStatuses() {
+	ctx := context.Background()
+	userIDs, err := s.userRepo.ClearExpiredCustomStatuses(ctx, time.Now())
+	if err != nil {
+		log.Printf("[member] custom status sweep failed: %v", err)
+		return
+	}
+	for _, id := range userIDs {
+		// The columns are already nulled — broadcast the cleared state directly
+		// instead of reloading each user.
+		s.broadcastCustomStatus(ctx, &models.User{ID: id})
+	}
+}
+
 // removeFromServer handles post-kick/ban cleanup: voice disconnect, WS broadcasts, subscription removal.
 // Order matters: broadcast before removing subscription so the kicked user receives the events.
 func (s *memberService) removeFromServer(serverID, targetID string) {
@@ -434,8 +773,15 @@ func (s *memberService) removeFromServer(serverID, targetID string) {
 	s.hub.RemoveClientServerID(targetID, serverID)
 }
 
-func (s *memberService) Unban(ctx context.Context, serverID, userID string) error {
-	return s.banRepo.Delete(ctx, serverID, userID)
+func (s *memberService) Unban(ctx context.Context, serverID, actorID, targetID string) error {
+	if err := s.banRepo.Delete(ctx, serverID, targetID); err != nil {
+		return err
+	}
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionMemberUnban, targetID, nil)
+	}
+	return nil
 }
 
 func (s *memberService) GetBans(ctx context.Context, serverID string) ([]models.Ban, error) {
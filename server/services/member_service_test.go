@@ -1,8 +1,17 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
 )
 
 // normalizeOwnAvatarURL is the gate that prevents a profile-update caller from
@@ -115,3 +124,158 @@ func TestNormalizeOwnAvatarURL_PreservesEscapedFilenameCharacters(t *testing.T)
 		t.Fatalf("expected escaped space preserved, got %q", got)
 	}
 }
+
+// ─── Custom status ───
+
+// customStatusServerRepo embeds the interface so only GetUserServers needs to exist.
+type customStatusServerRepo struct {
+	repository.ServerRepository
+	servers []models.ServerListItem
+}
+
+func (r customStatusServerRepo) GetUserServers(context.Context, string) ([]models.ServerListItem, error) {
+	return r.servers, nil
+}
+
+type customStatusFriendRepo struct {
+	repository.FriendshipRepository
+	friends []models.FriendshipWithUser
+}
+
+func (r customStatusFriendRepo) ListFriends(context.Context, string) ([]models.FriendshipWithUser, error) {
+	return r.friends, nil
+}
+
+// A custom status goes to people who can already see the user — shared servers,
+// friends, and the user's own other devices — never out via BroadcastToAll.
+func TestUpdateCustomStatus_ReachesServersAndFriendsNotStrangers(t *testing.T) {
+	status := "raiding the fridge"
+	userRepo := &testutil.MockUserRepo{
+		GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Username: id, CustomStatus: &status}, nil
+		},
+	}
+
+	var serverIDs, userIDs []string
+	broadcastAll := 0
+	hub := &testutil.MockEventPublisher{MockBroadcaster: testutil.MockBroadcaster{
+		BroadcastToServerFn: func(serverID string, e ws.Event) {
+			if e.Op == ws.OpCustomStatusUpdate {
+				serverIDs = append(serverIDs, serverID)
+			}
+		},
+		BroadcastToUserFn: func(userID string, e ws.Event) {
+			if e.Op == ws.OpCustomStatusUpdate {
+				userIDs = append(userIDs, userID)
+			}
+		},
+		BroadcastToAllFn: func(ws.Event) { broadcastAll++ },
+	}}
+
+	svc := &memberService{
+		userRepo:       userRepo,
+		serverRepo:     customStatusServerRepo{servers: []models.ServerListItem{{ID: "s1"}, {ID: "s2"}}},
+		friendshipRepo: customStatusFriendRepo{friends: []models.FriendshipWithUser{{UserID: "friend"}}},
+		hub:            hub,
+	}
+
+	if _, err := svc.UpdateCustomStatus(context.Background(), "u1", &models.UpdateCustomStatusRequest{Text: status}); err != nil {
+		t.Fatalf("UpdateCustomStatus: %v", err)
+	}
+
+	if len(serverIDs) != 2 || serverIDs[0] != "s1" || serverIDs[1] != "s2" {
+		t.Errorf("server broadcasts = %v, want [s1 s2]", serverIDs)
+	}
+	if len(userIDs) != 2 || userIDs[0] != "friend" || userIDs[1] != "u1" {
+		t.Errorf("user broadcasts = %v, want [friend u1]", userIDs)
+	}
+	if broadcastAll != 0 {
+		t.Errorf("BroadcastToAll called %d times — strangers must not receive status messages", broadcastAll)
+	}
+}
+
+func TestUpdateCustomStatus_ExpiryAndClear(t *testing.T) {
+	var gotText, gotEmoji *string
+	var gotExpires *time.Time
+	userRepo := &testutil.MockUserRepo{
+		UpdateCustomStatusFn: func(_ context.Context, _ string, text, emoji *string, expiresAt *time.Time) error {
+			gotText, gotEmoji, gotExpires = text, emoji, expiresAt
+			return nil
+		},
+		GetActiveByIDFn: func(_ context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id}, nil
+		},
+	}
+	svc := &memberService{
+		userRepo:   userRepo,
+		serverRepo: customStatusServerRepo{},
+		hub:        &testutil.MockEventPublisher{},
+	}
+	ctx := context.Background()
+
+	req := &models.UpdateCustomStatusRequest{Text: "brb", Emoji: "🍕", ClearAfterSeconds: 600}
+	if _, err := svc.UpdateCustomStatus(ctx, "u1", req); err != nil {
+		t.Fatalf("UpdateCustomStatus: %v", err)
+	}
+	if gotText == nil || *gotText != "brb" || gotEmoji == nil || *gotEmoji != "🍕" {
+		t.Fatalf("persisted text/emoji = %v/%v, want brb/🍕", gotText, gotEmoji)
+	}
+	if gotExpires == nil || time.Until(*gotExpires) > 10*time.Minute || time.Until(*gotExpires) < 9*time.Minute {
+		t.Errorf("expiry = %v, want ~10m out", gotExpires)
+	}
+
+	// Empty text and emoji clear everything — including a requested expiry.
+	if _, err := svc.UpdateCustomStatus(ctx, "u1", &models.UpdateCustomStatusRequest{ClearAfterSeconds: 600}); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if gotText != nil || gotEmoji != nil || gotExpires != nil {
+		t.Errorf("clear persisted %v/%v/%v, want all nil", gotText, gotEmoji, gotExpires)
+	}
+
+	// Over the 128-rune cap: rejected before the repo is touched.
+	wrote := false
+	userRepo.UpdateCustomStatusFn = func(context.Context, string, *string, *string, *time.Time) error {
+		wrote = true
+		return nil
+	}
+	long := strings.Repeat("x", 129)
+	if _, err := svc.UpdateCustomStatus(ctx, "u1", &models.UpdateCustomStatusRequest{Text: long}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("overlong status error = %v, want ErrBadRequest", err)
+	}
+	if wrote {
+		t.Error("overlong status reached the repository")
+	}
+}
+
+// The sweep broadcasts the cleared state for each expired user — the columns
+// are already nulled, so the payload carries nil fields.
+func TestSweepExpiredCustomStatuses_BroadcastsCleared(t *testing.T) {
+	userRepo := &testutil.MockUserRepo{
+		ClearExpiredCustomStatusesFn: func(context.Context, time.Time) ([]string, error) {
+			return []string{"u1"}, nil
+		},
+	}
+
+	var cleared []ws.CustomStatusUpdateData
+	hub := &testutil.MockEventPublisher{MockBroadcaster: testutil.MockBroadcaster{
+		BroadcastToUserFn: func(_ string, e ws.Event) {
+			if e.Op == ws.OpCustomStatusUpdate {
+				cleared = append(cleared, e.Data.(ws.CustomStatusUpdateData))
+			}
+		},
+	}}
+
+	svc := &memberService{
+		userRepo:   userRepo,
+		serverRepo: customStatusServerRepo{},
+		hub:        hub,
+	}
+	svc.sweepExpiredCustomStatuses()
+
+	if len(cleared) != 1 {
+		t.Fatalf("got %d cleared broadcasts, want 1", len(cleared))
+	}
+	if cleared[0].UserID != "u1" || cleared[0].CustomStatus != nil || cleared[0].CustomStatusEmoji != nil {
+		t.Errorf("cleared payload = %+v, want u1 with nil status fields", cleared[0])
+	}
+}
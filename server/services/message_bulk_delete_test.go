@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+func bulkDeleteService(msgRepo *testutil.MockMessageRepo, hub *testutil.MockBroadcastAndOnline) MessageService {
+	return newTestMessageService(
+		msgRepo,
+		&testutil.MockAttachmentRepo{},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeText}, nil
+			},
+		},
+		&testutil.MockUserRepo{},
+		&testutil.MockMentionRepo{},
+		&testutil.MockRoleMentionRepo{},
+		&testutil.MockRoleRepo{},
+		&testutil.MockReactionRepo{},
+		hub,
+		&testutil.MockChannelPermResolver{},
+	)
+}
+
+// A list assembled against the wrong channel is a caller bug, not something to
+// half-apply: one foreign ID rejects the whole batch before anything is deleted.
+func TestBulkDelete_RejectsTheBatchIfAnyIDIsFromAnotherChannel(t *testing.T) {
+	deleteCalls := 0
+	msgRepo := &testutil.MockMessageRepo{
+		GetByIDsFn: func(_ context.Context, ids []string) ([]models.Message, error) {
+			return []models.Message{
+				{ID: "m1", ChannelID: "ch1", UserID: "spammer"},
+				{ID: "m2", ChannelID: "ch2", UserID: "spammer"},
+			}, nil
+		},
+		DeleteBulkFn: func(_ context.Context, _ string, ids []string) (int, error) {
+			deleteCalls++
+			return len(ids), nil
+		},
+	}
+	svc := bulkDeleteService(msgRepo, &testutil.MockBroadcastAndOnline{})
+
+	_, err := svc.BulkDelete(context.Background(), "srv1", "ch1",
+		&models.BulkDeleteMessagesRequest{MessageIDs: []string{"m1", "m2"}})
+
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("a cross-channel ID was accepted, got err=%v", err)
+	}
+	if deleteCalls != 0 {
+		t.Error("messages were deleted despite the rejected batch")
+	}
+}
+
+func TestBulkDelete_DeletesTheBatchAndBroadcastsOneEvent(t *testing.T) {
+	var deletedBatches [][]string
+	msgRepo := &testutil.MockMessageRepo{
+		GetByIDsFn: func(_ context.Context, ids []string) ([]models.Message, error) {
+			msgs := make([]models.Message, len(ids))
+			for i, id := range ids {
+				msgs[i] = models.Message{ID: id, ChannelID: "ch1", UserID: "spammer"}
+			}
+			return msgs, nil
+		},
+		DeleteBulkFn: func(_ context.Context, _ string, ids []string) (int, error) {
+			deletedBatches = append(deletedBatches, ids)
+			return len(ids), nil
+		},
+	}
+	var events []ws.Event
+	hub := &testutil.MockBroadcastAndOnline{}
+	hub.BroadcastToUsersFn = func(_ []string, event ws.Event) {
+		events = append(events, event)
+	}
+	svc := bulkDeleteService(msgRepo, hub)
+
+	ids, err := svc.BulkDelete(context.Background(), "srv1", "ch1",
+		&models.BulkDeleteMessagesRequest{MessageIDs: []string{"m1", "m2", "m3"}})
+	if err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("reported %d deleted ids, want 3", len(ids))
+	}
+	if len(deletedBatches) != 1 || len(deletedBatches[0]) != 3 {
+		t.Errorf("delete ran as %v, want one batch of 3", deletedBatches)
+	}
+
+	// One event for the whole batch — that is the point of the endpoint.
+	if len(events) != 1 || events[0].Op != ws.OpMessageBulkDelete {
+		t.Fatalf("broadcasts = %v, want exactly one %s", events, ws.OpMessageBulkDelete)
+	}
+	data, _ := events[0].Data.(map[string]any)
+	if got, _ := data["ids"].([]string); len(got) != 3 {
+		t.Errorf("the event carried %v, want all 3 ids", data["ids"])
+	}
+}
+
+// The raid-cleanup form targets one user's recent messages instead of naming IDs,
+// capped at the same batch limit.
+func TestBulkDelete_UserWindowVariantResolvesIDsFromTheRepo(t *testing.T) {
+	var listedUser string
+	var listedLimit int
+	msgRepo := &testutil.MockMessageRepo{
+		ListRecentIDsByUserFn: func(_ context.Context, _, userID string, since time.Time, limit int) ([]string, error) {
+			listedUser = userID
+			listedLimit = limit
+			if time.Since(since) > time.Hour {
+				t.Errorf("since = %v, want within the requested 30-minute window", since)
+			}
+			return []string{"m7", "m8"}, nil
+		},
+		GetByIDsFn: func(_ context.Context, ids []string) ([]models.Message, error) {
+			msgs := make([]models.Message, len(ids))
+			for i, id := range ids {
+				msgs[i] = models.Message{ID: id, ChannelID: "ch1", UserID: "raider"}
+			}
+			return msgs, nil
+		},
+	}
+	svc := bulkDeleteService(msgRepo, &testutil.MockBroadcastAndOnline{})
+
+	ids, err := svc.BulkDelete(context.Background(), "srv1", "ch1",
+		&models.BulkDeleteMessagesRequest{UserID: "raider", WithinMinutes: 30})
+	if err != nil {
+		t.Fatalf("BulkDelete: %v", err)
+	}
+	if listedUser != "raider" || listedLimit != models.MaxBulkDeleteMessages {
+		t.Errorf("listed user %q with limit %d, want raider capped at %d", listedUser, listedLimit, models.MaxBulkDeleteMessages)
+	}
+	if len(ids) != 2 {
+		t.Errorf("deleted %v, want the 2 resolved ids", ids)
+	}
+}
+
+func TestBulkDelete_RequestValidation(t *testing.T) {
+	svc := bulkDeleteService(&testutil.MockMessageRepo{}, &testutil.MockBroadcastAndOnline{})
+
+	over := make([]string, models.MaxBulkDeleteMessages+1)
+	for i := range over {
+		over[i] = fmt.Sprintf("m%d", i)
+	}
+	bad := []*models.BulkDeleteMessagesRequest{
+		{},                                       // neither form
+		{MessageIDs: []string{"m"}, UserID: "u"}, // both forms
+		{MessageIDs: over},                       // over the cap
+		{UserID: "u"},                            // window form without a window
+	}
+	for i, req := range bad {
+		if _, err := svc.BulkDelete(context.Background(), "srv1", "ch1", req); !errors.Is(err, pkg.ErrBadRequest) {
+			t.Errorf("case %d: invalid request accepted, err=%v", i, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package services — MessageExpiryService: deletes ephemeral messages past their TTL.
+//
+// Messages get an absolute expires_at at create time (from the channel's
+// delete_after_seconds or a per-message override), so the sweep here is a
+// plain index walk: collect expired rows, run the same bookkeeping a
+// moderation delete does (attachment files, storage quota, unread counts),
+// delete per channel, and tell the channel's viewers. Pinned messages are
+// exempt at the query level.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// messageExpiryPollInterval is how often the sweep runs; an expired message
+// lingers at most this long past its deadline.
+const messageExpiryPollInterval = time.Minute
+
+// messageExpiryBatchSize caps how many expired messages one sweep removes.
+// A backlog larger than this drains across consecutive polls.
+const messageExpiryBatchSize = 200
+
+// MessageExpiryService sweeps messages whose expires_at has passed.
+type MessageExpiryService interface {
+	// Start launches the sweep poller goroutine. Idempotent.
+	Start()
+	// Stop signals the poller to exit and waits for an in-flight sweep to
+	// finish. Safe to call before Start (no-op).
+	Stop()
+	// RunOnce performs one sweep synchronously. Used by the poller and
+	// exposed for tests.
+	RunOnce(ctx context.Context) error
+}
+
+type messageExpiryService struct {
+	messageRepo    repository.MessageRepository
+	attachmentRepo repository.AttachmentRepository
+	channelRepo    repository.ChannelRepository
+	readStateRepo  repository.ReadStateRepository
+	permResolver   ChannelPermResolver
+	fileDeleter    FileDeleter
+	storageService StorageService
+	hub            ws.BroadcastAndOnline
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewMessageExpiryService(
+	messageRepo repository.MessageRepository,
+	attachmentRepo repository.AttachmentRepository,
+	channelRepo repository.ChannelRepository,
+	readStateRepo repository.ReadStateRepository,
+	permResolver ChannelPermResolver,
+	fileDeleter FileDeleter,
+	storageService StorageService,
+	hub ws.BroadcastAndOnline,
+) MessageExpiryService {
+	return &messageExpiryService{
+		messageRepo:    messageRepo,
+		attachmentRepo: attachmentRepo,
+		channelRepo:    channelRepo,
+		readStateRepo:  readStateRepo,
+		permResolver:   permResolver,
+		fileDeleter:    fileDeleter,
+		storageService: storageService,
+		hub:            hub,
+	}
+}
+
+func (s *messageExpiryService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.started = true
+
+	go s.pollLoop(ctx)
+}
+
+func (s *messageExpiryService) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	cancel := s.cancel
+	done := s.done
+	s.started = false
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+	log.Println("[expiry] stopped")
+}
+
+func (s *messageExpiryService) pollLoop(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(messageExpiryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(context.Background(), messageExpiryPollInterval)
+			if err := s.RunOnce(runCtx); err != nil {
+				log.Printf("[expiry] sweep failed: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+func (s *messageExpiryService) RunOnce(ctx context.Context) error {
+	expired, err := s.messageRepo.ListExpired(ctx, time.Now(), messageExpiryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list expired messages: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	byChannel := make(map[string][]models.Message)
+	for _, m := range expired {
+		byChannel[m.ChannelID] = append(byChannel[m.ChannelID], m)
+	}
+	for channelID, messages := range byChannel {
+		if err := s.sweepChannel(ctx, channelID, messages); err != nil {
+			// Keep going — one channel's failure shouldn't stall the rest,
+			// and the rows stay expired for the next poll to retry.
+			log.Printf("[expiry] sweep of channel %s failed: %v", channelID, err)
+		}
+	}
+	return nil
+}
+
+// sweepChannel deletes one channel's expired batch: same mechanics as a
+// moderation bulk delete (attachment files first — the CASCADE removes the
+// rows — then quota and unread bookkeeping), then one OpMessageDelete per
+// message so clients reuse the single-delete path they already handle.
+func (s *messageExpiryService) sweepChannel(ctx context.Context, channelID string, messages []models.Message) error {
+	ids := make([]string, len(messages))
+	authorByMessage := make(map[string]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+		authorByMessage[m.ID] = m.UserID
+	}
+
+	atts, attErr := s.attachmentRepo.GetByMessageIDs(ctx, ids)
+	if attErr != nil {
+		log.Printf("[expiry] failed to fetch attachments for channel %s (orphan files may remain): %v", channelID, attErr)
+	}
+	releaseByAuthor := make(map[string]int64)
+	for _, a := range atts {
+		s.fileDeleter.DeleteFromURL(a.FileURL)
+		if a.ThumbURL != nil {
+			s.fileDeleter.DeleteFromURL(*a.ThumbURL)
+		}
+		if a.FileSize != nil {
+			releaseByAuthor[authorByMessage[a.MessageID]] += *a.FileSize
+		}
+		if a.ThumbSize != nil {
+			releaseByAuthor[authorByMessage[a.MessageID]] += *a.ThumbSize
+		}
+	}
+
+	if _, err := s.messageRepo.DeleteBulk(ctx, channelID, ids); err != nil {
+		return err
+	}
+
+	for author, bytes := range releaseByAuthor {
+		if err := s.storageService.Release(ctx, author, bytes); err != nil {
+			log.Printf("[expiry] failed to release storage quota for user %s: %v", author, err)
+		}
+	}
+	for _, m := range messages {
+		if err := s.readStateRepo.DecrementUnreadForDeleted(ctx, channelID, m.UserID, m.CreatedAt); err != nil {
+			log.Printf("[expiry] failed to decrement unread counts for channel %s: %v", channelID, err)
+		}
+	}
+
+	viewers := s.allowedViewers(ctx, channelID)
+	for _, id := range ids {
+		s.hub.BroadcastToUsers(viewers, ws.Event{
+			Op: ws.OpMessageDelete,
+			Data: map[string]string{
+				"id":         id,
+				"channel_id": channelID,
+			},
+		})
+	}
+	return nil
+}
+
+// allowedViewers mirrors messageService.allowedViewers: online members with
+// both ViewChannel and ReadMessages on the channel. A vanished channel (the
+// DELETE raced a channel delete) just means nobody to notify.
+func (s *messageExpiryService) allowedViewers(ctx context.Context, channelID string) []string {
+	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	if err != nil || channel == nil {
+		return nil
+	}
+	onlineUsers := s.hub.GetOnlineUserIDsForServer(channel.ServerID)
+	return s.permResolver.AllowedViewers(ctx, channelID, onlineUsers)
+}
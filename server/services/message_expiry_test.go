@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+func newTestExpiryService(
+	msgRepo *testutil.MockMessageRepo,
+	attachRepo *testutil.MockAttachmentRepo,
+	storage *testutil.MockStorageService,
+	hub *testutil.MockBroadcastAndOnline,
+) MessageExpiryService {
+	return NewMessageExpiryService(
+		msgRepo,
+		attachRepo,
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeText}, nil
+			},
+		},
+		&testutil.MockReadStateRepo{},
+		&testutil.MockChannelPermResolver{},
+		&testutil.MockFileDeleter{},
+		storage,
+		hub,
+	)
+}
+
+func TestExpirySweep_DeletesPerChannelAndBroadcastsEachID(t *testing.T) {
+	msgRepo := &testutil.MockMessageRepo{
+		ListExpiredFn: func(_ context.Context, _ time.Time, _ int) ([]models.Message, error) {
+			return []models.Message{
+				{ID: "m1", ChannelID: "ch1", UserID: "alice"},
+				{ID: "m2", ChannelID: "ch2", UserID: "bob"},
+				{ID: "m3", ChannelID: "ch1", UserID: "alice"},
+			}, nil
+		},
+	}
+	deletedByChannel := make(map[string][]string)
+	msgRepo.DeleteBulkFn = func(_ context.Context, channelID string, ids []string) (int, error) {
+		deletedByChannel[channelID] = append(deletedByChannel[channelID], ids...)
+		return len(ids), nil
+	}
+	var events []ws.Event
+	hub := &testutil.MockBroadcastAndOnline{}
+	hub.BroadcastToUsersFn = func(_ []string, event ws.Event) {
+		events = append(events, event)
+	}
+	svc := newTestExpiryService(msgRepo, &testutil.MockAttachmentRepo{}, &testutil.MockStorageService{}, hub)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	if len(deletedByChannel["ch1"]) != 2 || len(deletedByChannel["ch2"]) != 1 {
+		t.Errorf("deletes grouped as %v, want ch1=[m1 m3] ch2=[m2]", deletedByChannel)
+	}
+	// One OpMessageDelete per message — clients reuse the single-delete path.
+	if len(events) != 3 {
+		t.Fatalf("broadcast %d events, want 3", len(events))
+	}
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if e.Op != ws.OpMessageDelete {
+			t.Fatalf("event op = %s, want %s", e.Op, ws.OpMessageDelete)
+		}
+		data := e.Data.(map[string]string)
+		seen[data["id"]] = true
+	}
+	for _, id := range []string{"m1", "m2", "m3"} {
+		if !seen[id] {
+			t.Errorf("no delete event for %s", id)
+		}
+	}
+}
+
+func TestExpirySweep_NothingExpiredIsANoOp(t *testing.T) {
+	deleteCalls := 0
+	msgRepo := &testutil.MockMessageRepo{
+		DeleteBulkFn: func(_ context.Context, _ string, ids []string) (int, error) {
+			deleteCalls++
+			return len(ids), nil
+		},
+	}
+	hub := &testutil.MockBroadcastAndOnline{}
+	hub.BroadcastToUsersFn = func(_ []string, _ ws.Event) {
+		t.Error("an empty sweep must not broadcast")
+	}
+	svc := newTestExpiryService(msgRepo, &testutil.MockAttachmentRepo{}, &testutil.MockStorageService{}, hub)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if deleteCalls != 0 {
+		t.Errorf("DeleteBulk ran %d times on an empty sweep", deleteCalls)
+	}
+}
+
+// Expired attachments give their bytes back to whoever was charged at upload,
+// thumbnail included.
+func TestExpirySweep_ReleasesAttachmentBytesToAuthors(t *testing.T) {
+	msgRepo := &testutil.MockMessageRepo{
+		ListExpiredFn: func(_ context.Context, _ time.Time, _ int) ([]models.Message, error) {
+			return []models.Message{{ID: "m1", ChannelID: "ch1", UserID: "alice"}}, nil
+		},
+	}
+	attachRepo := &testutil.MockAttachmentRepo{
+		GetByMessageIDsFn: func(_ context.Context, _ []string) ([]models.Attachment, error) {
+			return []models.Attachment{{
+				MessageID: "m1",
+				FileURL:   "/files/a.png",
+				FileSize:  testutil.Ptr(int64(1000)),
+				ThumbURL:  testutil.Ptr("/files/a_thumb.png"),
+				ThumbSize: testutil.Ptr(int64(100)),
+			}}, nil
+		},
+	}
+	released := make(map[string]int64)
+	storage := &testutil.MockStorageService{
+		ReleaseFn: func(_ context.Context, userID string, bytes int64) error {
+			released[userID] += bytes
+			return nil
+		},
+	}
+	svc := newTestExpiryService(msgRepo, attachRepo, storage, &testutil.MockBroadcastAndOnline{})
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if released["alice"] != 1100 {
+		t.Errorf("released %d bytes for alice, want 1100 (file + thumbnail)", released["alice"])
+	}
+}
+
+// The deadline is fixed when the message lands: channel setting by default,
+// per-message override either way (including 0 = keep).
+func TestMessageCreate_ExpiresAtFromChannelTTLAndOverride(t *testing.T) {
+	channelTTL := 0
+	var created *models.Message
+	svc := newTestMessageService(
+		&testutil.MockMessageRepo{
+			CreateFn: func(_ context.Context, message *models.Message) error {
+				created = message
+				return nil
+			},
+		},
+		&testutil.MockAttachmentRepo{},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeText, DeleteAfterSeconds: channelTTL}, nil
+			},
+		},
+		&testutil.MockUserRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+				return &models.User{ID: id, Username: id}, nil
+			},
+		},
+		&testutil.MockMentionRepo{},
+		&testutil.MockRoleMentionRepo{},
+		&testutil.MockRoleRepo{},
+		&testutil.MockReactionRepo{},
+		&testutil.MockBroadcastAndOnline{},
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+				return models.PermSendMessages, nil
+			},
+		},
+	)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "ch1", "alice", &models.CreateMessageRequest{Content: "hi"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ExpiresAt != nil {
+		t.Errorf("no TTL anywhere, yet ExpiresAt = %v", created.ExpiresAt)
+	}
+
+	channelTTL = 60
+	if _, err := svc.Create(ctx, "ch1", "alice", &models.CreateMessageRequest{Content: "hi"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ExpiresAt == nil {
+		t.Fatal("channel TTL set, yet ExpiresAt is nil")
+	}
+	if d := time.Until(*created.ExpiresAt); d < 50*time.Second || d > 70*time.Second {
+		t.Errorf("ExpiresAt %v from now, want ~60s", d)
+	}
+
+	if _, err := svc.Create(ctx, "ch1", "alice", &models.CreateMessageRequest{Content: "hi", DeleteAfterSeconds: testutil.Ptr(0)}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ExpiresAt != nil {
+		t.Errorf("override 0 should cancel the channel TTL, got ExpiresAt = %v", created.ExpiresAt)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// blockTable answers HasBlocked from a fixed "blocker->target" set.
+type blockTable map[string]string
+
+func (b blockTable) HasBlocked(_ context.Context, blockerID, targetID string) (bool, error) {
+	return b[blockerID] == targetID, nil
+}
+
+// A mention is a ping the author forces on someone else. Once that someone has
+// blocked the author, the message may still render — but it must not leave a
+// mention row behind.
+func TestMessageCreate_MentionsFromBlockedAuthorAreDropped(t *testing.T) {
+	var saved []string
+	svc := newTestMessageService(
+		&testutil.MockMessageRepo{},
+		&testutil.MockAttachmentRepo{},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeText}, nil
+			},
+		},
+		&testutil.MockUserRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+				return &models.User{ID: id, Username: id}, nil
+			},
+			GetByUsernameFn: func(_ context.Context, _ string) (*models.User, error) {
+				return nil, pkg.ErrNotFound
+			},
+		},
+		&testutil.MockMentionRepo{
+			SaveMentionsFn: func(_ context.Context, _ string, userIDs []string) error {
+				saved = userIDs
+				return nil
+			},
+		},
+		&testutil.MockRoleMentionRepo{},
+		&testutil.MockRoleRepo{},
+		&testutil.MockReactionRepo{},
+		&testutil.MockBroadcastAndOnline{},
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+				return models.PermSendMessages, nil
+			},
+		},
+	)
+	svc.SetBlockChecker(blockTable{"bob": "author"}) // bob blocked the author
+
+	msg, err := svc.Create(context.Background(), "ch1", "author",
+		&models.CreateMessageRequest{Content: "hey <@bob> and <@carol>"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if len(saved) != 1 || saved[0] != "carol" {
+		t.Errorf("saved mentions = %v, want just carol", saved)
+	}
+	if len(msg.Mentions) != 1 || msg.Mentions[0] != "carol" {
+		t.Errorf("message mentions = %v, want just carol", msg.Mentions)
+	}
+}
@@ -0,0 +1,258 @@
+// Package services — MessageRetentionService: enforces per-server message
+// retention windows.
+//
+// Servers with message_retention_days > 0 get a rolling cutoff over
+// created_at; the sweep deletes everything older, channel by channel, with the
+// same bookkeeping the expiry sweep does (attachment files, storage quota,
+// unread counts). Unlike expiry there is no stored deadline — the cutoff is
+// computed per server at query time, so flipping the setting needs no row
+// rewrites. Pinned messages are exempt at the query level. Deletions announce
+// as one OpMessageBulkDelete per channel chunk, not per message, because a
+// newly-enabled window can remove years of history in one pass.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// messageRetentionSweepInterval is how often the sweep runs. Retention is
+// measured in days, so a daily pass is plenty.
+const messageRetentionSweepInterval = 24 * time.Hour
+
+// messageRetentionStartupDelay is how long after boot the first sweep runs —
+// soon enough that a restart never postpones retention by a full day, late
+// enough to stay out of startup's way.
+const messageRetentionStartupDelay = 5 * time.Minute
+
+// messageRetentionChunkSize caps one delete chunk. A sweep loops chunks until
+// the backlog drains, so the cap bounds transaction size, not total work.
+const messageRetentionChunkSize = 500
+
+// MessageRetentionService sweeps messages past their server's retention window.
+type MessageRetentionService interface {
+	// Start launches the sweep poller goroutine. Idempotent.
+	Start()
+	// Stop signals the poller to exit and waits for an in-flight sweep to
+	// finish. Safe to call before Start (no-op).
+	Stop()
+	// RunOnce drains the current backlog in bounded chunks, synchronously.
+	// Used by the poller and exposed for tests.
+	RunOnce(ctx context.Context) error
+}
+
+type messageRetentionService struct {
+	messageRepo    repository.MessageRepository
+	attachmentRepo repository.AttachmentRepository
+	channelRepo    repository.ChannelRepository
+	readStateRepo  repository.ReadStateRepository
+	permResolver   ChannelPermResolver
+	fileDeleter    FileDeleter
+	storageService StorageService
+	hub            ws.BroadcastAndOnline
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewMessageRetentionService(
+	messageRepo repository.MessageRepository,
+	attachmentRepo repository.AttachmentRepository,
+	channelRepo repository.ChannelRepository,
+	readStateRepo repository.ReadStateRepository,
+	permResolver ChannelPermResolver,
+	fileDeleter FileDeleter,
+	storageService StorageService,
+	hub ws.BroadcastAndOnline,
+) MessageRetentionService {
+	return &messageRetentionService{
+		messageRepo:    messageRepo,
+		attachmentRepo: attachmentRepo,
+		channelRepo:    channelRepo,
+		readStateRepo:  readStateRepo,
+		permResolver:   permResolver,
+		fileDeleter:    fileDeleter,
+		storageService: storageService,
+		hub:            hub,
+	}
+}
+
+func (s *messageRetentionService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.started = true
+
+	go s.pollLoop(ctx)
+}
+
+func (s *messageRetentionService) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	cancel := s.cancel
+	done := s.done
+	s.started = false
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+	log.Println("[retention] stopped")
+}
+
+func (s *messageRetentionService) pollLoop(ctx context.Context) {
+	defer close(s.done)
+
+	startup := time.NewTimer(messageRetentionStartupDelay)
+	defer startup.Stop()
+	ticker := time.NewTicker(messageRetentionSweepInterval)
+	defer ticker.Stop()
+
+	sweep := func() {
+		runCtx, cancel := context.WithTimeout(context.Background(), messageRetentionSweepInterval)
+		if err := s.RunOnce(runCtx); err != nil {
+			log.Printf("[retention] sweep failed: %v", err)
+		}
+		cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-startup.C:
+			sweep()
+		case <-ticker.C:
+			sweep()
+		}
+	}
+}
+
+// RunOnce drains the backlog chunk by chunk until a short chunk signals the
+// end. A failure mid-drain leaves the rest for the next pass — the rows stay
+// past their window, so the sweep is resumable by construction.
+func (s *messageRetentionService) RunOnce(ctx context.Context) error {
+	total := 0
+	for {
+		expired, err := s.messageRepo.ListRetentionExpired(ctx, messageRetentionChunkSize)
+		if err != nil {
+			return fmt.Errorf("failed to list retention-expired messages: %w", err)
+		}
+		if len(expired) == 0 {
+			break
+		}
+
+		byChannel := make(map[string][]models.Message)
+		for _, m := range expired {
+			byChannel[m.ChannelID] = append(byChannel[m.ChannelID], m)
+		}
+		purged := 0
+		for channelID, messages := range byChannel {
+			if err := s.sweepChannel(ctx, channelID, messages); err != nil {
+				// Keep going — one channel's failure shouldn't stall the rest.
+				log.Printf("[retention] sweep of channel %s failed: %v", channelID, err)
+				continue
+			}
+			purged += len(messages)
+		}
+		total += purged
+
+		if len(expired) < messageRetentionChunkSize {
+			break
+		}
+		// A full chunk that deleted nothing would come straight back — stop
+		// instead of spinning on it; the next scheduled sweep retries.
+		if purged == 0 || ctx.Err() != nil {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("[retention] purged %d messages past their retention window", total)
+	}
+	return nil
+}
+
+// sweepChannel deletes one channel's chunk with the expiry sweep's mechanics
+// (attachment files before the CASCADE, then quota and unread bookkeeping),
+// then one OpMessageBulkDelete for the whole chunk.
+func (s *messageRetentionService) sweepChannel(ctx context.Context, channelID string, messages []models.Message) error {
+	ids := make([]string, len(messages))
+	authorByMessage := make(map[string]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+		authorByMessage[m.ID] = m.UserID
+	}
+
+	atts, attErr := s.attachmentRepo.GetByMessageIDs(ctx, ids)
+	if attErr != nil {
+		log.Printf("[retention] failed to fetch attachments for channel %s (orphan files may remain): %v", channelID, attErr)
+	}
+	releaseByAuthor := make(map[string]int64)
+	for _, a := range atts {
+		s.fileDeleter.DeleteFromURL(a.FileURL)
+		if a.ThumbURL != nil {
+			s.fileDeleter.DeleteFromURL(*a.ThumbURL)
+		}
+		if a.FileSize != nil {
+			releaseByAuthor[authorByMessage[a.MessageID]] += *a.FileSize
+		}
+		if a.ThumbSize != nil {
+			releaseByAuthor[authorByMessage[a.MessageID]] += *a.ThumbSize
+		}
+	}
+
+	if _, err := s.messageRepo.DeleteBulk(ctx, channelID, ids); err != nil {
+		return err
+	}
+
+	for author, bytes := range releaseByAuthor {
+		if err := s.storageService.Release(ctx, author, bytes); err != nil {
+			log.Printf("[retention] failed to release storage quota for user %s: %v", author, err)
+		}
+	}
+	for _, m := range messages {
+		if err := s.readStateRepo.DecrementUnreadForDeleted(ctx, channelID, m.UserID, m.CreatedAt); err != nil {
+			log.Printf("[retention] failed to decrement unread counts for channel %s: %v", channelID, err)
+		}
+	}
+
+	s.hub.BroadcastToUsers(s.allowedViewers(ctx, channelID), ws.Event{
+		Op: ws.OpMessageBulkDelete,
+		Data: map[string]any{
+			"channel_id": channelID,
+			"ids":        ids,
+		},
+	})
+	return nil
+}
+
+// allowedViewers mirrors messageExpiryService.allowedViewers: online members
+// with both ViewChannel and ReadMessages on the channel.
+func (s *messageRetentionService) allowedViewers(ctx context.Context, channelID string) []string {
+	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	if err != nil || channel == nil {
+		return nil
+	}
+	onlineUsers := s.hub.GetOnlineUserIDsForServer(channel.ServerID)
+	return s.permResolver.AllowedViewers(ctx, channelID, onlineUsers)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+func newTestRetentionService(
+	msgRepo *testutil.MockMessageRepo,
+	hub *testutil.MockBroadcastAndOnline,
+) MessageRetentionService {
+	return NewMessageRetentionService(
+		msgRepo,
+		&testutil.MockAttachmentRepo{},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeText}, nil
+			},
+		},
+		&testutil.MockReadStateRepo{},
+		&testutil.MockChannelPermResolver{},
+		&testutil.MockFileDeleter{},
+		&testutil.MockStorageService{},
+		hub,
+	)
+}
+
+// A backlog larger than one chunk must drain within a single RunOnce — the
+// sweep runs daily, so leftovers would otherwise linger another 24 hours.
+func TestRetentionSweep_DrainsBacklogInChunks(t *testing.T) {
+	// Two full chunks then a short one: 3 list calls, chunk-size rows each
+	// except the last.
+	calls := 0
+	msgRepo := &testutil.MockMessageRepo{
+		ListRetentionExpiredFn: func(_ context.Context, limit int) ([]models.Message, error) {
+			calls++
+			n := limit
+			if calls == 3 {
+				n = 1
+			}
+			if calls > 3 {
+				t.Fatal("sweep kept listing after a short chunk")
+			}
+			batch := make([]models.Message, n)
+			for i := range batch {
+				batch[i] = models.Message{ID: "m", ChannelID: "ch1", UserID: "alice"}
+			}
+			return batch, nil
+		},
+	}
+	deleted := 0
+	msgRepo.DeleteBulkFn = func(_ context.Context, _ string, ids []string) (int, error) {
+		deleted += len(ids)
+		return len(ids), nil
+	}
+	svc := newTestRetentionService(msgRepo, &testutil.MockBroadcastAndOnline{})
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if want := 2*messageRetentionChunkSize + 1; deleted != want {
+		t.Errorf("deleted %d messages, want %d", deleted, want)
+	}
+}
+
+// Deletions announce as one bulk event per channel chunk, not one event per
+// message — a fresh retention window can remove years of history at once.
+func TestRetentionSweep_BroadcastsOneBulkEventPerChannel(t *testing.T) {
+	msgRepo := &testutil.MockMessageRepo{
+		ListRetentionExpiredFn: func(_ context.Context, _ int) ([]models.Message, error) {
+			return []models.Message{
+				{ID: "m1", ChannelID: "ch1", UserID: "alice"},
+				{ID: "m2", ChannelID: "ch2", UserID: "bob"},
+				{ID: "m3", ChannelID: "ch1", UserID: "alice"},
+			}, nil
+		},
+	}
+	var events []ws.Event
+	hub := &testutil.MockBroadcastAndOnline{}
+	hub.BroadcastToUsersFn = func(_ []string, event ws.Event) {
+		events = append(events, event)
+	}
+	svc := newTestRetentionService(msgRepo, hub)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("broadcast %d events, want one per channel (2)", len(events))
+	}
+	idsByChannel := make(map[string][]string)
+	for _, e := range events {
+		if e.Op != ws.OpMessageBulkDelete {
+			t.Fatalf("event op = %s, want %s", e.Op, ws.OpMessageBulkDelete)
+		}
+		data := e.Data.(map[string]any)
+		idsByChannel[data["channel_id"].(string)] = data["ids"].([]string)
+	}
+	if len(idsByChannel["ch1"]) != 2 || len(idsByChannel["ch2"]) != 1 {
+		t.Errorf("bulk events grouped as %v, want ch1=[m1 m3] ch2=[m2]", idsByChannel)
+	}
+}
+
+func TestRetentionSweep_NothingPastWindowIsANoOp(t *testing.T) {
+	deleteCalls := 0
+	msgRepo := &testutil.MockMessageRepo{
+		DeleteBulkFn: func(_ context.Context, _ string, ids []string) (int, error) {
+			deleteCalls++
+			return len(ids), nil
+		},
+	}
+	hub := &testutil.MockBroadcastAndOnline{}
+	hub.BroadcastToUsersFn = func(_ []string, _ ws.Event) {
+		t.Error("an empty sweep must not broadcast")
+	}
+	svc := newTestRetentionService(msgRepo, hub)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if deleteCalls != 0 {
+		t.Errorf("DeleteBulk ran %d times on an empty sweep", deleteCalls)
+	}
+}
@@ -6,23 +6,143 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/pkg/cache"
 	"github.com/akinalp/mqvi/repository"
 	"github.com/akinalp/mqvi/ws"
 )
 
+// channelStatsTTL bounds how stale the channel stats panel may read. The
+// aggregate is one indexed query, but the endpoint sits behind a click any
+// member can spam — the cache absorbs that without a per-channel counter.
+const (
+	channelStatsTTL     = 30 * time.Second
+	channelStatsCleanup = 5 * time.Minute
+)
+
+// maxStoredEditsPerMessage bounds a message's edit history (channel and DM alike) —
+// enough to follow real churn without letting edit-spam grow the table unbounded.
+const maxStoredEditsPerMessage = 25
+
+// slowmodeCleanup is how often expired slowmode entries are swept.
+const slowmodeCleanup = 10 * time.Minute
+
 // Discord-style: <@userId> user, <@&roleId> role. [a-z0-9] covers hex IDs and legacy seeded role IDs.
 var userMentionRegex = regexp.MustCompile(`<@([a-z0-9]+)>`)
 var roleMentionRegex = regexp.MustCompile(`<@&([a-z0-9]+)>`)
 
+// Mass pings are literal @everyone / @here at a word start — "user@everyone.com"
+// must not ping the server.
+var massMentionRegex = regexp.MustCompile(`(?:^|\s)@(everyone|here)\b`)
+
+// extractMentionScope returns models.MentionScopeEveryone/Here when the
+// content carries the token, preferring the wider scope when both appear.
+// The PermMentionEveryone gate is the caller's job.
+func extractMentionScope(content string) string {
+	scope := ""
+	for _, match := range massMentionRegex.FindAllStringSubmatch(content, -1) {
+		if match[1] == models.MentionScopeEveryone {
+			return models.MentionScopeEveryone
+		}
+		scope = models.MentionScopeHere
+	}
+	return scope
+}
+
 type MessageService interface {
 	GetByChannelID(ctx context.Context, channelID string, userID string, beforeID string, limit int) (*models.MessagePage, error)
+	// GetMessagesAround serves "jump to message": the target with up to limit
+	// messages on each side, ascending, plus has-more flags per direction.
+	GetMessagesAround(ctx context.Context, channelID string, userID string, messageID string, limit int) (*models.MessageContextPage, error)
+	// GetChannelStats returns count and first/last timestamps for a channel.
+	// Gated on per-channel ReadMessages like GetByChannelID; results are cached.
+	GetChannelStats(ctx context.Context, channelID string, userID string) (*models.ChannelStats, error)
 	Create(ctx context.Context, channelID string, userID string, req *models.CreateMessageRequest) (*models.Message, error)
+	CreateSystemMessage(ctx context.Context, channelID, userID string, msgType models.MessageType, content string, replyToID *string) (*models.Message, error)
+	// CreateWebhookMessage posts as a webhook: attributed to the webhook's
+	// creator but rendered under the given name/avatar. See WebhookSender.
+	CreateWebhookMessage(ctx context.Context, webhook *models.Webhook, name string, avatarURL *string, content string) (*models.Message, error)
 	BroadcastCreate(message *models.Message)
 	Update(ctx context.Context, id string, userID string, req *models.UpdateMessageRequest) (*models.Message, error)
 	Delete(ctx context.Context, serverID string, id string, userID string, userPermissions models.Permission) error
+	// BulkDelete removes up to models.MaxBulkDeleteMessages messages from one channel
+	// in a single statement and broadcasts one batched delete event. Moderation-only —
+	// the route gates on PermManageMessages, so there is no per-message author check.
+	BulkDelete(ctx context.Context, serverID, channelID string, req *models.BulkDeleteMessagesRequest) ([]string, error)
+	// GetEditHistory returns a message's stored prior revisions, newest first.
+	// Authors can always view their own; anyone else needs PermManageMessages.
+	GetEditHistory(ctx context.Context, serverID, id, userID string, userPermissions models.Permission) ([]models.MessageEdit, error)
+	// SetKeywordHighlighter wires the optional keyword-alert hook consulted on
+	// every message broadcast. Nil-safe: unwired means no highlights.
+	SetKeywordHighlighter(kh KeywordHighlighter)
+	// SetCrossposter wires the optional channel-follow hook that fans new user
+	// messages out to follower channels. Nil-safe: unwired means no cross-posts.
+	SetCrossposter(cp Crossposter)
+	// SetFormatLimits installs the configured message-shape caps applied to
+	// plaintext content on create and edit. Zero limits leave content untouched.
+	SetFormatLimits(limits models.MessageFormatLimits)
+	// SetBlockChecker wires the directional block lookup consulted when parsing
+	// mentions. Nil-safe: unwired means no mention suppression.
+	SetBlockChecker(bc DirectionalBlockChecker)
+	// SetTypingStopper wires the optional hook that clears the author's typing
+	// indicator when their message is broadcast. Nil-safe: unwired means
+	// indicators just time out.
+	SetTypingStopper(ts TypingStopper)
+	// SetTimeoutChecker wires the member-timeout gate consulted on every send.
+	// Nil-safe: unwired means timeouts are not enforced here.
+	SetTimeoutChecker(tc MemberTimeoutChecker)
+	// SetMentionGate wires the notification-settings lookup consulted before a
+	// parsed mention is persisted. Nil-safe: unwired means every valid mention lands.
+	SetMentionGate(mg MentionNotificationGate)
+	// SetEmbedder wires the optional link-unfurl hook that runs after every
+	// message broadcast and attaches stored embeds to history loads. Nil-safe:
+	// unwired means links stay plain text.
+	SetEmbedder(me MessageEmbedder)
+}
+
+// KeywordHighlighter matches message content against viewers' highlight words
+// and notifies them (ISP — KeywordAlertService implements it).
+type KeywordHighlighter interface {
+	NotifyKeywordHighlights(message *models.Message, viewerIDs []string)
+}
+
+// Crossposter copies a new message into channels following its channel
+// (ISP — ChannelFollowService implements it). It filters out system and
+// encrypted messages itself.
+type Crossposter interface {
+	CrosspostMessage(message *models.Message)
+}
+
+// MessageEmbedder unfurls a new message's first link into a stored embed and
+// serves stored embeds for history enrichment (ISP — EmbedService implements
+// it). It gates on PermEmbedLinks and skips E2EE/system messages itself.
+type MessageEmbedder interface {
+	UnfurlMessage(message *models.Message)
+	EmbedsForMessages(ctx context.Context, messageIDs []string) (map[string]*models.MessageEmbed, error)
+}
+
+// DirectionalBlockChecker answers "has blockerID blocked targetID" — one
+// direction, unlike BlockChecker (ISP — BlockService implements it). Mentions
+// from someone a user blocked must not create mention rows or pings for them.
+type DirectionalBlockChecker interface {
+	HasBlocked(ctx context.Context, blockerID, targetID string) (bool, error)
+}
+
+// MentionNotificationGate asks whether a mention may reach its target at all
+// (ISP — NotificationService implements it). A user whose settings say "nothing"
+// for the channel or server opted out of mention rows, not just of pings.
+type MentionNotificationGate interface {
+	ShouldNotifyMention(ctx context.Context, userID, serverID, channelID string) (bool, error)
+}
+
+// TypingStopper retracts a user's typing indicator in a channel (ISP —
+// ws.Hub implements it). The author's own message is the definitive "stopped
+// typing" signal, so clients shouldn't have to wait out the idle timeout.
+type TypingStopper interface {
+	StopTyping(channelID, userID string)
 }
 
 type messageService struct {
@@ -41,6 +161,56 @@ type messageService struct {
 	fileDeleter     FileDeleter
 	storageService  StorageService
 	serverReader    ServerEncryptionReader
+
+	keywordHighlighter KeywordHighlighter         // optional — set post-construction via SetKeywordHighlighter
+	crossposter        Crossposter                // optional — set post-construction via SetCrossposter
+	blockChecker       DirectionalBlockChecker    // optional — set post-construction via SetBlockChecker
+	mentionGate        MentionNotificationGate    // optional — set post-construction via SetMentionGate
+	typingStopper      TypingStopper              // optional — set post-construction via SetTypingStopper
+	timeoutChecker     MemberTimeoutChecker       // optional — set post-construction via SetTimeoutChecker
+	embedder           MessageEmbedder            // optional — set post-construction via SetEmbedder
+	formatLimits       models.MessageFormatLimits // zero value = no shape limits
+
+	// Keyed by channel ID. Shared across users — the stats themselves carry
+	// nothing user-specific; the permission check runs before the cache read.
+	statsCache *cache.TTLCache[string, models.ChannelStats]
+
+	// Last accepted post per (channel, user), for slowmode. The TTL is the
+	// maximum slowmode setting, so entries for quiet channels age out on
+	// their own instead of accumulating.
+	slowmodeSeen *cache.TTLCache[string, time.Time]
+}
+
+func (s *messageService) SetKeywordHighlighter(kh KeywordHighlighter) {
+	s.keywordHighlighter = kh
+}
+
+func (s *messageService) SetEmbedder(me MessageEmbedder) {
+	s.embedder = me
+}
+
+func (s *messageService) SetTypingStopper(ts TypingStopper) {
+	s.typingStopper = ts
+}
+
+func (s *messageService) SetTimeoutChecker(tc MemberTimeoutChecker) {
+	s.timeoutChecker = tc
+}
+
+func (s *messageService) SetCrossposter(cp Crossposter) {
+	s.crossposter = cp
+}
+
+func (s *messageService) SetFormatLimits(limits models.MessageFormatLimits) {
+	s.formatLimits = limits
+}
+
+func (s *messageService) SetBlockChecker(bc DirectionalBlockChecker) {
+	s.blockChecker = bc
+}
+
+func (s *messageService) SetMentionGate(mg MentionNotificationGate) {
+	s.mentionGate = mg
 }
 
 // ServerEncryptionReader reports a server's encryption policy. Narrow on purpose: the message path
@@ -82,7 +252,30 @@ func NewMessageService(
 		fileDeleter:     fileDeleter,
 		storageService:  storageService,
 		serverReader:    serverReader,
+		statsCache:      cache.New[string, models.ChannelStats](channelStatsTTL, channelStatsCleanup),
+		slowmodeSeen:    cache.New[string, time.Time](models.MaxSlowmodeSeconds*time.Second, slowmodeCleanup),
+	}
+}
+
+// slowmodeKey builds the per-(channel, user) tracking key. IDs are hex, so the
+// separator can't collide.
+func slowmodeKey(channelID, userID string) string {
+	return channelID + ":" + userID
+}
+
+// slowmodeRemaining returns how many whole seconds the user must still wait
+// before the channel's slowmode lets them post again (0 = go ahead). Rounds
+// up so the client's countdown never reads 0 while the server still rejects.
+func (s *messageService) slowmodeRemaining(channelID, userID string, slowmodeSeconds int) int {
+	last, ok := s.slowmodeSeen.Get(slowmodeKey(channelID, userID))
+	if !ok {
+		return 0
 	}
+	remaining := time.Duration(slowmodeSeconds)*time.Second - time.Since(last)
+	if remaining <= 0 {
+		return 0
+	}
+	return int((remaining + time.Second - 1) / time.Second)
 }
 
 // enforceServerEncryptionPolicy makes the message's encryption match the server's setting, in both
@@ -152,69 +345,148 @@ func (s *messageService) GetByChannelID(ctx context.Context, channelID string, u
 		messages[i], messages[j] = messages[j], messages[i]
 	}
 
-	// Batch load attachments, mentions, reactions (avoid N+1)
-	if len(messages) > 0 {
-		messageIDs := make([]string, len(messages))
-		for i, m := range messages {
-			messageIDs[i] = m.ID
-		}
+	if err := s.enrichMessages(ctx, messages); err != nil {
+		return nil, err
+	}
 
-		attachments, err := s.attachmentRepo.GetByMessageIDs(ctx, messageIDs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get attachments: %w", err)
-		}
+	// nil slice serializes as JSON null — ensure empty array
+	if messages == nil {
+		messages = []models.Message{}
+	}
 
-		attachmentMap := make(map[string][]models.Attachment)
-		for _, a := range attachments {
-			SignAttachmentURLs(s.urlSigner, &a)
-			attachmentMap[a.MessageID] = append(attachmentMap[a.MessageID], a)
-		}
+	return &models.MessagePage{
+		Messages: messages,
+		HasMore:  hasMore,
+	}, nil
+}
 
-		mentionMap, err := s.mentionRepo.GetByMessageIDs(ctx, messageIDs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get mentions: %w", err)
-		}
+// GetMessagesAround returns the target message with up to limit messages on
+// each side, ascending — the backend of "jump to message" from search results
+// and pins. Same per-channel ReadMessages gate as GetByChannelID.
+func (s *messageService) GetMessagesAround(ctx context.Context, channelID string, userID string, messageID string, limit int) (*models.MessageContextPage, error) {
+	channelPerms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve channel permissions: %w", err)
+	}
+	if !channelPerms.Has(models.PermReadMessages) {
+		return nil, fmt.Errorf("%w: missing read messages permission for this channel", pkg.ErrForbidden)
+	}
 
-		reactionMap, err := s.reactionRepo.GetByMessageIDs(ctx, messageIDs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get reactions: %w", err)
-		}
+	// Per direction, so the full window is up to 2*limit+1 messages.
+	if limit <= 0 || limit > 50 {
+		limit = 25
+	}
+
+	messages, hasBefore, hasAfter, err := s.messageRepo.GetAroundID(ctx, channelID, messageID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.enrichMessages(ctx, messages); err != nil {
+		return nil, err
+	}
+
+	return &models.MessageContextPage{
+		Messages:  messages,
+		HasBefore: hasBefore,
+		HasAfter:  hasAfter,
+	}, nil
+}
+
+// enrichMessages batch-loads attachments, mentions, reactions and embeds for a
+// page of messages and normalizes nil slices to empty ones (avoid N+1, and nil
+// serializes as JSON null). Shared by the cursor page and the context fetch.
+func (s *messageService) enrichMessages(ctx context.Context, messages []models.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	messageIDs := make([]string, len(messages))
+	for i, m := range messages {
+		messageIDs[i] = m.ID
+	}
+
+	attachments, err := s.attachmentRepo.GetByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	attachmentMap := make(map[string][]models.Attachment)
+	for _, a := range attachments {
+		SignAttachmentURLs(s.urlSigner, &a)
+		attachmentMap[a.MessageID] = append(attachmentMap[a.MessageID], a)
+	}
 
-		roleMentionMap, err := s.roleMentionRepo.GetByMessageIDs(ctx, messageIDs)
+	mentionMap, err := s.mentionRepo.GetByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	reactionMap, err := s.reactionRepo.GetByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get reactions: %w", err)
+	}
+
+	roleMentionMap, err := s.roleMentionRepo.GetByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get role mentions: %w", err)
+	}
+
+	// Nil map when unwired — lookups below just yield nil embeds.
+	var embedMap map[string]*models.MessageEmbed
+	if s.embedder != nil {
+		embedMap, err = s.embedder.EmbedsForMessages(ctx, messageIDs)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get role mentions: %w", err)
+			return fmt.Errorf("failed to get embeds: %w", err)
 		}
+	}
 
-		for i := range messages {
-			s.signMessageAvatars(&messages[i])
-			messages[i].Attachments = attachmentMap[messages[i].ID]
-			if messages[i].Attachments == nil {
-				messages[i].Attachments = []models.Attachment{}
-			}
-			messages[i].Mentions = mentionMap[messages[i].ID]
-			if messages[i].Mentions == nil {
-				messages[i].Mentions = []string{}
-			}
-			messages[i].RoleMentions = roleMentionMap[messages[i].ID]
-			if messages[i].RoleMentions == nil {
-				messages[i].RoleMentions = []string{}
-			}
-			messages[i].Reactions = reactionMap[messages[i].ID]
-			if messages[i].Reactions == nil {
-				messages[i].Reactions = []models.ReactionGroup{}
-			}
+	for i := range messages {
+		s.signMessageAvatars(&messages[i])
+		messages[i].Attachments = attachmentMap[messages[i].ID]
+		if messages[i].Attachments == nil {
+			messages[i].Attachments = []models.Attachment{}
+		}
+		messages[i].Mentions = mentionMap[messages[i].ID]
+		if messages[i].Mentions == nil {
+			messages[i].Mentions = []string{}
 		}
+		messages[i].RoleMentions = roleMentionMap[messages[i].ID]
+		if messages[i].RoleMentions == nil {
+			messages[i].RoleMentions = []string{}
+		}
+		messages[i].Reactions = models.PreviewReactions(reactionMap[messages[i].ID])
+		if messages[i].Reactions == nil {
+			messages[i].Reactions = []models.ReactionGroup{}
+		}
+		messages[i].Embed = embedMap[messages[i].ID]
+		messages[i].IsEmojiOnly = models.EmojiOnly(messages[i].Content)
 	}
+	return nil
+}
 
-	// nil slice serializes as JSON null — ensure empty array
-	if messages == nil {
-		messages = []models.Message{}
+// GetChannelStats returns message totals for a channel, behind the same
+// ReadMessages gate as the message list. The aggregate is cached per channel.
+func (s *messageService) GetChannelStats(ctx context.Context, channelID string, userID string) (*models.ChannelStats, error) {
+	channelPerms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve channel permissions: %w", err)
+	}
+	if !channelPerms.Has(models.PermReadMessages) {
+		return nil, fmt.Errorf("%w: missing read messages permission for this channel", pkg.ErrForbidden)
 	}
 
-	return &models.MessagePage{
-		Messages: messages,
-		HasMore:  hasMore,
-	}, nil
+	if stats, ok := s.statsCache.Get(channelID); ok {
+		return &stats, nil
+	}
+
+	stats, err := s.messageRepo.GetChannelStats(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel stats: %w", err)
+	}
+
+	s.statsCache.Set(channelID, *stats)
+	return stats, nil
 }
 
 // Create creates a new message. Checks per-channel SendMessages permission.
@@ -223,6 +495,14 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
+	// Shape limits apply to plaintext only — ciphertext can't be reshaped.
+	if req.EncryptionVersion == 0 {
+		content, err := s.formatLimits.NormalizeFormatting(req.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+		}
+		req.Content = content
+	}
 
 	channel, err := s.channelRepo.GetByID(ctx, channelID)
 	if err != nil {
@@ -242,6 +522,29 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 		return nil, fmt.Errorf("%w: missing send messages permission for this channel", pkg.ErrForbidden)
 	}
 
+	// A moderation timeout silences the member server-wide regardless of channel
+	// permissions — unlike slowmode, managers get no exemption.
+	if s.timeoutChecker != nil {
+		timedOut, err := s.timeoutChecker.IsTimedOut(ctx, channel.ServerID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check member timeout: %w", err)
+		}
+		if timedOut {
+			return nil, fmt.Errorf("%w: you are timed out in this server", pkg.ErrForbidden)
+		}
+	}
+
+	// Slowmode gates the room, not the people running it: channel or message
+	// managers post freely. The remaining seconds ride in the error so the
+	// client can show a countdown.
+	slowmodeApplies := channel.SlowmodeSeconds > 0 &&
+		!channelPerms.Has(models.PermManageMessages) && !channelPerms.Has(models.PermManageChannels)
+	if slowmodeApplies {
+		if remaining := s.slowmodeRemaining(channelID, userID, channel.SlowmodeSeconds); remaining > 0 {
+			return nil, fmt.Errorf("%w: slowmode active, wait %d seconds", pkg.ErrBadRequest, remaining)
+		}
+	}
+
 	if err := s.enforceServerEncryptionPolicy(ctx, channel.ServerID, req.EncryptionVersion); err != nil {
 		return nil, err
 	}
@@ -260,6 +563,15 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 		message.Content = &req.Content
 	}
 
+	// @everyone/@here only take effect for authors holding PermMentionEveryone;
+	// for anyone else the token stays plain text and no scope is stored.
+	canMassMention := channelPerms.Has(models.PermMentionEveryone)
+	if req.EncryptionVersion == 0 && canMassMention {
+		if scope := extractMentionScope(req.Content); scope != "" {
+			message.MentionScope = &scope
+		}
+	}
+
 	// Reply validation — referenced message must be in the same channel
 	if req.ReplyToID != nil && *req.ReplyToID != "" {
 		refMsg, err := s.messageRepo.GetByID(ctx, *req.ReplyToID)
@@ -269,13 +581,39 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 		if refMsg.ChannelID != channelID {
 			return nil, fmt.Errorf("%w: cannot reply to a message in a different channel", pkg.ErrBadRequest)
 		}
+		// Cap the chain this reply would extend. The capped walk also rejects
+		// replies into a cyclic chain (corrupt data), which reads as max depth.
+		depth, err := s.messageRepo.ReplyChainDepth(ctx, refMsg.ID, models.MaxReplyChainDepth)
+		if err != nil {
+			return nil, err
+		}
+		if depth >= models.MaxReplyChainDepth {
+			return nil, fmt.Errorf("%w: reply chain is too deep", pkg.ErrBadRequest)
+		}
 		message.ReplyToID = req.ReplyToID
 	}
 
+	// TTL: a per-message override beats the channel's delete_after_seconds
+	// (including override 0 = keep). The absolute deadline is stored on the
+	// row, so later channel-setting changes leave this message alone.
+	ttl := channel.DeleteAfterSeconds
+	if req.DeleteAfterSeconds != nil {
+		ttl = *req.DeleteAfterSeconds
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(time.Duration(ttl) * time.Second).UTC()
+		message.ExpiresAt = &expiresAt
+	}
+
 	if err := s.messageRepo.Create(ctx, message); err != nil {
 		return nil, fmt.Errorf("failed to create message: %w", err)
 	}
 
+	// The cooldown starts only for messages that actually landed.
+	if slowmodeApplies {
+		s.slowmodeSeen.Set(slowmodeKey(channelID, userID), time.Now())
+	}
+
 	// Bump denormalized unread_count for every user with a read-state row in
 	// this channel (author excluded). Non-fatal: unread badges may briefly
 	// diverge but the message itself is already persisted and delivered.
@@ -317,7 +655,7 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 			serverID = channel.ServerID
 		}
 
-		mentionedIDs := s.extractMentions(ctx, req.Content)
+		mentionedIDs := s.applyMentionGate(ctx, serverID, channelID, s.extractMentions(ctx, userID, req.Content))
 		if len(mentionedIDs) > 0 {
 			if err := s.mentionRepo.SaveMentions(ctx, message.ID, mentionedIDs); err != nil {
 				fmt.Printf("[mention] failed to save mentions for message %s: %v\n", message.ID, err)
@@ -325,7 +663,11 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 		}
 		message.Mentions = mentionedIDs
 
-		roleMentionIDs := s.extractRoleMentions(ctx, req.Content, serverID)
+		if message.MentionScope != nil && *message.MentionScope == models.MentionScopeHere {
+			message.Mentions = append(message.Mentions, s.expandHereMentions(ctx, serverID, channelID, userID, message.ID, mentionedIDs)...)
+		}
+
+		roleMentionIDs := s.extractRoleMentions(ctx, req.Content, serverID, canMassMention)
 		if len(roleMentionIDs) > 0 {
 			if err := s.roleMentionRepo.SaveRoleMentions(ctx, message.ID, roleMentionIDs); err != nil {
 				fmt.Printf("[mention] failed to save role mentions for message %s: %v\n", message.ID, err)
@@ -337,6 +679,97 @@ func (s *messageService) Create(ctx context.Context, channelID string, userID st
 		message.RoleMentions = []string{}
 	}
 
+	message.IsEmojiOnly = models.EmojiOnly(message.Content)
+
+	return message, nil
+}
+
+// CreateSystemMessage persists a server-generated message (no request validation, no
+// mention parsing, never E2EE) and broadcasts it like a user message. userID attributes
+// the action ("X pinned a message"); replyToID points clients at the subject message.
+func (s *messageService) CreateSystemMessage(ctx context.Context, channelID, userID string, msgType models.MessageType, content string, replyToID *string) (*models.Message, error) {
+	message := &models.Message{
+		ChannelID: channelID,
+		UserID:    userID,
+		Type:      msgType,
+		Content:   &content,
+		ReplyToID: replyToID,
+	}
+
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to create system message: %w", err)
+	}
+
+	// Same non-fatal unread bump as Create: system messages appear in the timeline,
+	// so they count as unread like any other message.
+	if err := s.readStateRepo.IncrementUnreadCounts(ctx, channelID, userID); err != nil {
+		log.Printf("[message] failed to increment unread counts for channel %s: %v", channelID, err)
+	}
+
+	author, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system message author: %w", err)
+	}
+	author.PasswordHash = ""
+	author.AvatarURL = s.urlSigner.SignURLPtr(author.AvatarURL)
+	message.Author = author
+	message.Attachments = []models.Attachment{}
+	message.Reactions = []models.ReactionGroup{}
+	message.Mentions = []string{}
+	message.RoleMentions = []string{}
+
+	if message.ReplyToID != nil {
+		if refMsg, err := s.messageRepo.GetByID(ctx, *message.ReplyToID); err == nil && refMsg != nil {
+			if refMsg.Author != nil {
+				refMsg.Author.AvatarURL = s.urlSigner.SignURLPtr(refMsg.Author.AvatarURL)
+			}
+			message.ReferencedMessage = &models.MessageReference{
+				ID:      refMsg.ID,
+				Author:  refMsg.Author,
+				Content: refMsg.Content,
+			}
+		}
+	}
+
+	s.BroadcastCreate(message)
+	return message, nil
+}
+
+// CreateWebhookMessage persists a message posted through a webhook and
+// broadcasts it like a user message. The row is attributed to the webhook's
+// creator (user_id is NOT NULL and quotas and joins run through it), but
+// clients render the denormalized name/avatar whenever webhook_id is set.
+// No mention parsing — integration payloads don't get to ping anyone.
+func (s *messageService) CreateWebhookMessage(ctx context.Context, webhook *models.Webhook, name string, avatarURL *string, content string) (*models.Message, error) {
+	normalized, err := s.formatLimits.NormalizeFormatting(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+	}
+
+	message := &models.Message{
+		ChannelID:        webhook.ChannelID,
+		UserID:           webhook.CreatedBy,
+		Content:          &normalized,
+		WebhookID:        &webhook.ID,
+		WebhookName:      &name,
+		WebhookAvatarURL: avatarURL,
+	}
+
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to create webhook message: %w", err)
+	}
+
+	if err := s.readStateRepo.IncrementUnreadCounts(ctx, webhook.ChannelID, webhook.CreatedBy); err != nil {
+		log.Printf("[message] failed to increment unread counts for channel %s: %v", webhook.ChannelID, err)
+	}
+
+	message.Attachments = []models.Attachment{}
+	message.Reactions = []models.ReactionGroup{}
+	message.Mentions = []string{}
+	message.RoleMentions = []string{}
+	message.IsEmojiOnly = models.EmojiOnly(message.Content)
+
+	s.BroadcastCreate(message)
 	return message, nil
 }
 
@@ -353,28 +786,40 @@ func (s *messageService) allowedViewers(channelID string) []string {
 	}
 
 	onlineUsers := s.hub.GetOnlineUserIDsForServer(channel.ServerID)
-	var allowed []string
-
-	for _, userID := range onlineUsers {
-		perms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, channelID)
-		if err != nil {
-			continue
-		}
-		if perms.Has(models.PermViewChannel) && perms.Has(models.PermReadMessages) {
-			allowed = append(allowed, userID)
-		}
-	}
-
-	return allowed
+	return s.permResolver.AllowedViewers(ctx, channelID, onlineUsers)
 }
 
 // BroadcastCreate sends the message via WS after file uploads complete.
 // Only sends to users with ViewChannel + ReadMessages permission on the channel.
 func (s *messageService) BroadcastCreate(message *models.Message) {
-	s.hub.BroadcastToUsers(s.allowedViewers(message.ChannelID), ws.Event{
+	// Retract the author's typing indicator before the message goes out.
+	if s.typingStopper != nil {
+		s.typingStopper.StopTyping(message.ChannelID, message.UserID)
+	}
+
+	viewers := s.allowedViewers(message.ChannelID)
+	s.hub.BroadcastToUsers(viewers, ws.Event{
 		Op:   ws.OpMessageCreate,
 		Data: message,
 	})
+
+	// Keyword highlights ride the same audience the broadcast used — already
+	// online-filtered and permission-checked. Async: matching does DB lookups.
+	if s.keywordHighlighter != nil {
+		go s.keywordHighlighter.NotifyKeywordHighlights(message, viewers)
+	}
+
+	// Channel-follow fan-out. Async for the same reason: each follower channel
+	// means a system-message insert plus its own broadcast.
+	if s.crossposter != nil {
+		go s.crossposter.CrosspostMessage(message)
+	}
+
+	// Link unfurling hits the network, so the embed always arrives after the
+	// message — as its own OpMessageEmbedUpdate to the same audience.
+	if s.embedder != nil {
+		go s.embedder.UnfurlMessage(message)
+	}
 }
 
 // Update edits a message. Only the message owner can edit.
@@ -382,12 +827,31 @@ func (s *messageService) Update(ctx context.Context, id string, userID string, r
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
 	}
+	if req.EncryptionVersion == 0 {
+		content, err := s.formatLimits.NormalizeFormatting(req.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+		}
+		req.Content = content
+	}
 
 	message, err := s.messageRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	// System messages render from their type; the content is the server's
+	// record, not the attributed actor's to rewrite.
+	if message.Type.IsSystem() {
+		return nil, fmt.Errorf("%w: system messages cannot be edited", pkg.ErrForbidden)
+	}
+
+	// Webhook messages are attributed to the webhook's creator for storage,
+	// but the content is the integration's — nobody gets to rewrite it.
+	if message.WebhookID != nil {
+		return nil, fmt.Errorf("%w: webhook messages cannot be edited", pkg.ErrForbidden)
+	}
+
 	if message.UserID != userID {
 		return nil, fmt.Errorf("%w: you can only edit your own messages", pkg.ErrForbidden)
 	}
@@ -405,6 +869,16 @@ func (s *messageService) Update(ctx context.Context, id string, userID string, r
 		return nil, err
 	}
 
+	// Keep the pre-edit text before it's overwritten. Only plaintext revisions are stored —
+	// old ciphertext can't be rendered and would outlive key rotation. A no-op edit (same
+	// text) records nothing.
+	if message.EncryptionVersion == 0 && message.Content != nil &&
+		!(req.EncryptionVersion == 0 && req.Content == *message.Content) {
+		if err := s.messageRepo.RecordEdit(ctx, id, *message.Content, maxStoredEditsPerMessage); err != nil {
+			return nil, err
+		}
+	}
+
 	// The edit decides the row's version, not the version it was written at — the server's E2EE
 	// setting can have flipped in between. Without this the repo branched on the stored version and
 	// wrote the wrong side: a plaintext message edited after E2EE was switched on had its content
@@ -422,6 +896,24 @@ func (s *messageService) Update(ctx context.Context, id string, userID string, r
 		message.E2EEMetadata = nil
 	}
 
+	// Re-derive the mass-ping scope from the edited text: an edit can add or
+	// drop @everyone/@here, and the permission is re-checked against the
+	// author's current roles, not the ones they had at send time.
+	canMassMention := false
+	message.MentionScope = nil
+	if req.EncryptionVersion == 0 {
+		channelPerms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, message.ChannelID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve channel permissions: %w", err)
+		}
+		canMassMention = channelPerms.Has(models.PermMentionEveryone)
+		if canMassMention {
+			if scope := extractMentionScope(req.Content); scope != "" {
+				message.MentionScope = &scope
+			}
+		}
+	}
+
 	if err := s.messageRepo.Update(ctx, message); err != nil {
 		return nil, err
 	}
@@ -447,7 +939,7 @@ func (s *messageService) Update(ctx context.Context, id string, userID string, r
 		if err := s.mentionRepo.DeleteByMessageID(ctx, id); err != nil {
 			fmt.Printf("[mention] failed to delete old mentions for message %s: %v\n", id, err)
 		}
-		mentionedIDs := s.extractMentions(ctx, req.Content)
+		mentionedIDs := s.applyMentionGate(ctx, serverID, channel.ID, s.extractMentions(ctx, userID, req.Content))
 		if len(mentionedIDs) > 0 {
 			if err := s.mentionRepo.SaveMentions(ctx, id, mentionedIDs); err != nil {
 				fmt.Printf("[mention] failed to save mentions for message %s: %v\n", id, err)
@@ -455,10 +947,14 @@ func (s *messageService) Update(ctx context.Context, id string, userID string, r
 		}
 		message.Mentions = mentionedIDs
 
+		if message.MentionScope != nil && *message.MentionScope == models.MentionScopeHere {
+			message.Mentions = append(message.Mentions, s.expandHereMentions(ctx, serverID, channel.ID, userID, id, mentionedIDs)...)
+		}
+
 		if err := s.roleMentionRepo.DeleteByMessageID(ctx, id); err != nil {
 			fmt.Printf("[mention] failed to delete old role mentions for message %s: %v\n", id, err)
 		}
-		roleMentionIDs := s.extractRoleMentions(ctx, req.Content, serverID)
+		roleMentionIDs := s.extractRoleMentions(ctx, req.Content, serverID, canMassMention)
 		if len(roleMentionIDs) > 0 {
 			if err := s.roleMentionRepo.SaveRoleMentions(ctx, id, roleMentionIDs); err != nil {
 				fmt.Printf("[mention] failed to save role mentions for message %s: %v\n", id, err)
@@ -470,6 +966,8 @@ func (s *messageService) Update(ctx context.Context, id string, userID string, r
 		message.RoleMentions = []string{}
 	}
 
+	message.IsEmojiOnly = models.EmojiOnly(message.Content)
+
 	s.hub.BroadcastToUsers(s.allowedViewers(message.ChannelID), ws.Event{
 		Op:   ws.OpMessageUpdate,
 		Data: message,
@@ -498,6 +996,12 @@ func (s *messageService) Delete(ctx context.Context, serverID string, id string,
 		return fmt.Errorf("%w: you can only delete your own messages", pkg.ErrForbidden)
 	}
 
+	// A system message carries an attributed actor, but it is the server's
+	// record, not theirs — removing one takes moderator rights.
+	if message.Type.IsSystem() && !userPermissions.Has(models.PermManageMessages) {
+		return fmt.Errorf("%w: system messages can only be removed by moderators", pkg.ErrForbidden)
+	}
+
 	// Collect attachment info before delete (CASCADE removes attachment rows)
 	var attachmentBytes int64
 	atts, attErr := s.attachmentRepo.GetByMessageID(ctx, id)
@@ -548,6 +1052,121 @@ func (s *messageService) Delete(ctx context.Context, serverID string, id string,
 	return nil
 }
 
+// BulkDelete is the moderation sweep: explicit IDs, or everything one user posted
+// in a recent window (raid cleanup). Explicit IDs must all belong to the channel —
+// a list assembled against the wrong channel is a caller bug, not something to
+// half-apply. Messages already gone by the time the batch runs are skipped.
+func (s *messageService) BulkDelete(ctx context.Context, serverID, channelID string, req *models.BulkDeleteMessagesRequest) ([]string, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+	}
+
+	// IDOR guard: the channel must belong to the route's server.
+	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil || channel.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+
+	ids := req.MessageIDs
+	if req.UserID != "" {
+		since := time.Now().Add(-time.Duration(req.WithinMinutes) * time.Minute)
+		ids, err = s.messageRepo.ListRecentIDsByUser(ctx, channelID, req.UserID, since, models.MaxBulkDeleteMessages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list user messages: %w", err)
+		}
+	}
+
+	messages, err := s.messageRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	deletedIDs := make([]string, 0, len(messages))
+	for _, m := range messages {
+		if m.ChannelID != channelID {
+			return nil, fmt.Errorf("%w: message %s is not in this channel", pkg.ErrBadRequest, m.ID)
+		}
+		deletedIDs = append(deletedIDs, m.ID)
+	}
+	if len(deletedIDs) == 0 {
+		return []string{}, nil
+	}
+
+	// Attachment files and quota, handled like single Delete but batched: the
+	// CASCADE removes the rows, so the file URLs must be collected first.
+	atts, attErr := s.attachmentRepo.GetByMessageIDs(ctx, deletedIDs)
+	if attErr != nil {
+		log.Printf("[message] failed to fetch attachments for bulk delete (orphan files may remain): %v", attErr)
+	}
+	authorByMessage := make(map[string]string, len(messages))
+	for _, m := range messages {
+		authorByMessage[m.ID] = m.UserID
+	}
+	releaseByAuthor := make(map[string]int64)
+	for _, a := range atts {
+		s.fileDeleter.DeleteFromURL(a.FileURL)
+		if a.ThumbURL != nil {
+			s.fileDeleter.DeleteFromURL(*a.ThumbURL)
+		}
+		if a.FileSize != nil {
+			releaseByAuthor[authorByMessage[a.MessageID]] += *a.FileSize
+		}
+		if a.ThumbSize != nil {
+			releaseByAuthor[authorByMessage[a.MessageID]] += *a.ThumbSize
+		}
+	}
+
+	if _, err := s.messageRepo.DeleteBulk(ctx, channelID, deletedIDs); err != nil {
+		return nil, err
+	}
+
+	for author, bytes := range releaseByAuthor {
+		if err := s.storageService.Release(ctx, author, bytes); err != nil {
+			log.Printf("[message] failed to release storage quota for user %s: %v", author, err)
+		}
+	}
+	for _, m := range messages {
+		if err := s.readStateRepo.DecrementUnreadForDeleted(ctx, channelID, m.UserID, m.CreatedAt); err != nil {
+			log.Printf("[message] failed to decrement unread counts on bulk delete for channel %s: %v", channelID, err)
+		}
+	}
+
+	// One event for the whole batch — clients drop the set in a single update.
+	s.hub.BroadcastToUsers(s.allowedViewers(channelID), ws.Event{
+		Op: ws.OpMessageBulkDelete,
+		Data: map[string]any{
+			"channel_id": channelID,
+			"ids":        deletedIDs,
+		},
+	})
+
+	return deletedIDs, nil
+}
+
+func (s *messageService) GetEditHistory(ctx context.Context, serverID, id, userID string, userPermissions models.Permission) ([]models.MessageEdit, error) {
+	message, err := s.messageRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// IDOR guard: the message's channel must belong to the route's server.
+	channel, err := s.channelRepo.GetByID(ctx, message.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil || channel.ServerID != serverID {
+		return nil, fmt.Errorf("%w: message does not belong to this server", pkg.ErrForbidden)
+	}
+
+	if message.UserID != userID && !userPermissions.Has(models.PermManageMessages) {
+		return nil, fmt.Errorf("%w: you can only view the history of your own messages", pkg.ErrForbidden)
+	}
+
+	return s.messageRepo.GetEditHistory(ctx, id)
+}
+
 // signMessageAvatars signs all avatar URLs embedded in a message (author + referenced message author).
 func (s *messageService) signMessageAvatars(msg *models.Message) {
 	if msg.Author != nil {
@@ -559,8 +1178,9 @@ func (s *messageService) signMessageAvatars(msg *models.Message) {
 }
 
 // extractRoleMentions parses <@&roleId> tokens from content and returns role IDs.
-// Only includes roles that exist in the server and are mentionable.
-func (s *messageService) extractRoleMentions(ctx context.Context, content string, serverID string) []string {
+// Only includes roles that exist in the server and are mentionable; authors with
+// PermMentionEveryone (canMentionAll) may ping non-mentionable roles too.
+func (s *messageService) extractRoleMentions(ctx context.Context, content string, serverID string, canMentionAll bool) []string {
 	if serverID == "" {
 		return []string{}
 	}
@@ -592,7 +1212,7 @@ func (s *messageService) extractRoleMentions(ctx context.Context, content string
 		seen[roleID] = true
 
 		role, ok := roleByID[roleID]
-		if !ok || !role.Mentionable {
+		if !ok || (!role.Mentionable && !canMentionAll) {
 			continue
 		}
 		roleIDs = append(roleIDs, roleID)
@@ -604,9 +1224,11 @@ func (s *messageService) extractRoleMentions(ctx context.Context, content string
 	return roleIDs
 }
 
-// extractMentions parses <@userId> tokens from content and returns valid user IDs.
+// extractMentions parses <@userId> tokens from content and returns valid user
+// IDs. Users who blocked the author are dropped: the message still renders for
+// them, but it must not produce a mention row (and through it a badge or ping).
 // Validates that each user ID exists. Deduplicates results.
-func (s *messageService) extractMentions(ctx context.Context, content string) []string {
+func (s *messageService) extractMentions(ctx context.Context, authorID, content string) []string {
 	matches := userMentionRegex.FindAllStringSubmatch(content, -1)
 	if len(matches) == 0 {
 		return []string{}
@@ -627,6 +1249,14 @@ func (s *messageService) extractMentions(ctx context.Context, content string) []
 		if err != nil {
 			continue
 		}
+
+		// Lookup failure fails open (mention stands) — a transient DB error
+		// should not silently swallow notifications.
+		if s.blockChecker != nil {
+			if blocked, err := s.blockChecker.HasBlocked(ctx, userID, authorID); err == nil && blocked {
+				continue
+			}
+		}
 		userIDs = append(userIDs, userID)
 	}
 
@@ -635,3 +1265,55 @@ func (s *messageService) extractMentions(ctx context.Context, content string) []
 	}
 	return userIDs
 }
+
+// expandHereMentions resolves an @here into the server members online right now,
+// minus the author and anyone already mentioned by name (OR IGNORE in the repo
+// would keep their direct row anyway, but the response shouldn't list them twice).
+// Survivors of the mention gate are stored with kind "here" and returned so the
+// caller can surface them alongside the direct mentions.
+func (s *messageService) expandHereMentions(ctx context.Context, serverID, channelID, authorID, messageID string, directIDs []string) []string {
+	if serverID == "" {
+		return nil
+	}
+
+	already := make(map[string]bool, len(directIDs)+1)
+	already[authorID] = true
+	for _, id := range directIDs {
+		already[id] = true
+	}
+
+	var hereIDs []string
+	for _, id := range s.hub.GetOnlineUserIDsForServer(serverID) {
+		if !already[id] {
+			hereIDs = append(hereIDs, id)
+		}
+	}
+
+	hereIDs = s.applyMentionGate(ctx, serverID, channelID, hereIDs)
+	if len(hereIDs) == 0 {
+		return nil
+	}
+
+	if err := s.mentionRepo.SaveMentionsWithKind(ctx, messageID, hereIDs, models.MentionKindHere); err != nil {
+		fmt.Printf("[mention] failed to save here mentions for message %s: %v\n", messageID, err)
+		return nil
+	}
+	return hereIDs
+}
+
+// applyMentionGate drops mentioned users whose notification settings silence mentions
+// in this channel. Like the block lookup above, a failed read fails open — a transient
+// settings error must not swallow someone's ping.
+func (s *messageService) applyMentionGate(ctx context.Context, serverID, channelID string, userIDs []string) []string {
+	if s.mentionGate == nil || len(userIDs) == 0 {
+		return userIDs
+	}
+	kept := userIDs[:0]
+	for _, id := range userIDs {
+		if ok, err := s.mentionGate.ShouldNotifyMention(ctx, id, serverID, channelID); err == nil && !ok {
+			continue
+		}
+		kept = append(kept, id)
+	}
+	return kept
+}
@@ -652,3 +652,299 @@ func TestUpdate_RejectsPlaintextOnEncryptedServer(t *testing.T) {
 		}
 	})
 }
+
+// System messages are the server's record: the attributed actor can neither
+// rewrite nor remove them, and moderators can still clean them up.
+func TestSystemMessage_ActorCannotEditOrDelete(t *testing.T) {
+	newSvc := func(deleted *bool) MessageService {
+		return newTestMessageService(
+			&testutil.MockMessageRepo{
+				GetByIDFn: func(_ context.Context, _ string) (*models.Message, error) {
+					return &models.Message{ID: "m1", UserID: "u1", ChannelID: "ch1", Type: models.MessageTypeJoin}, nil
+				},
+				DeleteFn: func(_ context.Context, _ string) error {
+					*deleted = true
+					return nil
+				},
+			},
+			&testutil.MockAttachmentRepo{},
+			&testutil.MockChannelRepo{
+				GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+					return &models.Channel{ID: "ch1", ServerID: "srv1"}, nil
+				},
+			},
+			&testutil.MockUserRepo{},
+			&testutil.MockMentionRepo{},
+			&testutil.MockRoleMentionRepo{},
+			&testutil.MockRoleRepo{},
+			&testutil.MockReactionRepo{},
+			&testutil.MockBroadcastAndOnline{},
+			&testutil.MockChannelPermResolver{},
+		)
+	}
+
+	deleted := false
+	svc := newSvc(&deleted)
+
+	// Even the attributed author can't edit the canned content.
+	if _, err := svc.Update(context.Background(), "m1", "u1", &models.UpdateMessageRequest{Content: "rewritten"}); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("edit of system message: got %v, want ErrForbidden", err)
+	}
+
+	// Nor delete it without moderator rights.
+	if err := svc.Delete(context.Background(), "srv1", "m1", "u1", 0); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("author delete of system message: got %v, want ErrForbidden", err)
+	}
+	if deleted {
+		t.Fatal("system message reached the repository delete")
+	}
+
+	// ManageMessages clears it like any other message.
+	if err := svc.Delete(context.Background(), "srv1", "m1", "mod", models.PermManageMessages); err != nil {
+		t.Errorf("moderator delete of system message: %v", err)
+	}
+	if !deleted {
+		t.Error("moderator delete never reached the repository")
+	}
+}
+
+func TestMassMentionScope_GatedOnPermission(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		perms     models.Permission
+		wantScope string // "" means no scope stored
+	}{
+		{
+			name:      "everyone with permission",
+			content:   "@everyone meeting in five",
+			perms:     models.PermSendMessages | models.PermMentionEveryone,
+			wantScope: models.MentionScopeEveryone,
+		},
+		{
+			name:      "here with permission",
+			content:   "@here anyone around?",
+			perms:     models.PermSendMessages | models.PermMentionEveryone,
+			wantScope: models.MentionScopeHere,
+		},
+		{
+			name:      "everyone outranks here when both appear",
+			content:   "@here no wait, @everyone",
+			perms:     models.PermSendMessages | models.PermMentionEveryone,
+			wantScope: models.MentionScopeEveryone,
+		},
+		{
+			name:    "stripped without permission",
+			content: "@everyone meeting in five",
+			perms:   models.PermSendMessages,
+		},
+		{
+			name:    "email address is not a mass ping",
+			content: "reach me at user@everyone.com or ops@here.io",
+			perms:   models.PermSendMessages | models.PermMentionEveryone,
+		},
+		{
+			name:    "mid-word token is not a mass ping",
+			content: "see docs/@everyone-syntax",
+			perms:   models.PermSendMessages | models.PermMentionEveryone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var savedScope *string
+			svc := newTestMessageService(
+				&testutil.MockMessageRepo{
+					CreateFn: func(_ context.Context, m *models.Message) error {
+						m.ID = "m1"
+						savedScope = m.MentionScope
+						return nil
+					},
+				},
+				&testutil.MockAttachmentRepo{},
+				&testutil.MockChannelRepo{
+					GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+						return &models.Channel{ID: "ch1", ServerID: "srv1"}, nil
+					},
+				},
+				&testutil.MockUserRepo{
+					GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+						return &models.User{ID: id, Username: "u-" + id}, nil
+					},
+				},
+				&testutil.MockMentionRepo{},
+				&testutil.MockRoleMentionRepo{},
+				&testutil.MockRoleRepo{},
+				&testutil.MockReactionRepo{},
+				&testutil.MockBroadcastAndOnline{},
+				&testutil.MockChannelPermResolver{
+					ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+						return tt.perms, nil
+					},
+				},
+			)
+
+			msg, err := svc.Create(context.Background(), "ch1", "u1", &models.CreateMessageRequest{Content: tt.content})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			if tt.wantScope == "" {
+				if savedScope != nil {
+					t.Errorf("scope stored as %q, want none", *savedScope)
+				}
+				return
+			}
+			if savedScope == nil || *savedScope != tt.wantScope {
+				t.Fatalf("stored scope = %v, want %q", savedScope, tt.wantScope)
+			}
+			if msg.MentionScope == nil || *msg.MentionScope != tt.wantScope {
+				t.Errorf("response scope = %v, want %q", msg.MentionScope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestHereMention_ExpandsToOnlineMembers(t *testing.T) {
+	var directSaved []string
+	var hereSaved []string
+	var hereKind string
+
+	svc := newTestMessageService(
+		&testutil.MockMessageRepo{
+			CreateFn: func(_ context.Context, m *models.Message) error {
+				m.ID = "m1"
+				return nil
+			},
+		},
+		&testutil.MockAttachmentRepo{},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+				return &models.Channel{ID: "ch1", ServerID: "srv1"}, nil
+			},
+		},
+		&testutil.MockUserRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+				return &models.User{ID: id, Username: "u-" + id}, nil
+			},
+		},
+		&testutil.MockMentionRepo{
+			SaveMentionsFn: func(_ context.Context, _ string, userIDs []string) error {
+				directSaved = userIDs
+				return nil
+			},
+			SaveMentionsWithKindFn: func(_ context.Context, _ string, userIDs []string, kind string) error {
+				hereSaved = userIDs
+				hereKind = kind
+				return nil
+			},
+		},
+		&testutil.MockRoleMentionRepo{},
+		&testutil.MockRoleRepo{},
+		&testutil.MockReactionRepo{},
+		&testutil.MockBroadcastAndOnline{
+			GetOnlineUserIDsForServerFn: func(serverID string) []string {
+				if serverID != "srv1" {
+					t.Errorf("online lookup for server %q, want srv1", serverID)
+				}
+				return []string{"u1", "u2", "u3"}
+			},
+		},
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+				return models.PermSendMessages | models.PermMentionEveryone, nil
+			},
+		},
+	)
+
+	// u2 is named directly and also online: the direct row must win, and the
+	// @here expansion must skip both u2 and the author.
+	msg, err := svc.Create(context.Background(), "ch1", "u1", &models.CreateMessageRequest{Content: "@here ping <@u2>"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if len(directSaved) != 1 || directSaved[0] != "u2" {
+		t.Errorf("direct mentions saved = %v, want [u2]", directSaved)
+	}
+	if hereKind != models.MentionKindHere {
+		t.Errorf("expansion kind = %q, want %q", hereKind, models.MentionKindHere)
+	}
+	if len(hereSaved) != 1 || hereSaved[0] != "u3" {
+		t.Errorf("here mentions saved = %v, want [u3]", hereSaved)
+	}
+	if len(msg.Mentions) != 2 {
+		t.Errorf("response mentions = %v, want u2 and u3", msg.Mentions)
+	}
+}
+
+func TestRoleMention_PermissionBypassesMentionableFlag(t *testing.T) {
+	roles := []models.Role{
+		{ID: "r1", ServerID: "srv1", Mentionable: true},
+		{ID: "r2", ServerID: "srv1", Mentionable: false},
+	}
+
+	tests := []struct {
+		name      string
+		perms     models.Permission
+		wantRoles []string
+	}{
+		{
+			name:      "only mentionable roles without the permission",
+			perms:     models.PermSendMessages,
+			wantRoles: []string{"r1"},
+		},
+		{
+			name:      "permission holder pings locked roles too",
+			perms:     models.PermSendMessages | models.PermMentionEveryone,
+			wantRoles: []string{"r1", "r2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestMessageService(
+				&testutil.MockMessageRepo{},
+				&testutil.MockAttachmentRepo{},
+				&testutil.MockChannelRepo{
+					GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+						return &models.Channel{ID: "ch1", ServerID: "srv1"}, nil
+					},
+				},
+				&testutil.MockUserRepo{
+					GetByIDFn: func(_ context.Context, id string) (*models.User, error) {
+						return &models.User{ID: id, Username: "u-" + id}, nil
+					},
+				},
+				&testutil.MockMentionRepo{},
+				&testutil.MockRoleMentionRepo{},
+				&testutil.MockRoleRepo{
+					GetAllByServerFn: func(_ context.Context, _ string) ([]models.Role, error) {
+						return roles, nil
+					},
+				},
+				&testutil.MockReactionRepo{},
+				&testutil.MockBroadcastAndOnline{},
+				&testutil.MockChannelPermResolver{
+					ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+						return tt.perms, nil
+					},
+				},
+			)
+
+			msg, err := svc.Create(context.Background(), "ch1", "u1", &models.CreateMessageRequest{Content: "<@&r1> <@&r2> deploy"})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+
+			if len(msg.RoleMentions) != len(tt.wantRoles) {
+				t.Fatalf("role mentions = %v, want %v", msg.RoleMentions, tt.wantRoles)
+			}
+			for i, want := range tt.wantRoles {
+				if msg.RoleMentions[i] != want {
+					t.Errorf("role mention %d = %q, want %q", i, msg.RoleMentions[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+func newSlowmodeTestService(slowmodeSeconds int, perms models.Permission) MessageService {
+	return newTestMessageService(
+		&testutil.MockMessageRepo{},
+		&testutil.MockAttachmentRepo{},
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeText, SlowmodeSeconds: slowmodeSeconds}, nil
+			},
+		},
+		&testutil.MockUserRepo{
+			GetByIDFn: func(_ context.Context, _ string) (*models.User, error) {
+				return &models.User{ID: "u1", Username: "alice"}, nil
+			},
+			GetByUsernameFn: func(_ context.Context, _ string) (*models.User, error) {
+				return nil, pkg.ErrNotFound
+			},
+		},
+		&testutil.MockMentionRepo{},
+		&testutil.MockRoleMentionRepo{},
+		&testutil.MockRoleRepo{},
+		&testutil.MockReactionRepo{},
+		&testutil.MockBroadcastAndOnline{},
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+				return perms, nil
+			},
+		},
+	)
+}
+
+func TestMessageCreate_SlowmodeRejectsRapidSecondPost(t *testing.T) {
+	svc := newSlowmodeTestService(30, models.PermSendMessages)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "ch1", "u1", &models.CreateMessageRequest{Content: "first"}); err != nil {
+		t.Fatalf("first message: %v", err)
+	}
+
+	_, err := svc.Create(ctx, "ch1", "u1", &models.CreateMessageRequest{Content: "second"})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("second message error = %v, want ErrBadRequest", err)
+	}
+	// The client renders a countdown from the seconds in the message.
+	if !strings.Contains(err.Error(), "wait 30 seconds") {
+		t.Errorf("error = %q, want the remaining cooldown in it", err.Error())
+	}
+
+	// Another channel has its own clock.
+	if _, err := svc.Create(ctx, "ch2", "u1", &models.CreateMessageRequest{Content: "elsewhere"}); err != nil {
+		t.Errorf("message in a different channel: %v", err)
+	}
+}
+
+func TestMessageCreate_SlowmodeExemptsManagers(t *testing.T) {
+	for _, perm := range []models.Permission{models.PermManageMessages, models.PermManageChannels} {
+		svc := newSlowmodeTestService(30, models.PermSendMessages|perm)
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			if _, err := svc.Create(ctx, "ch1", "u1", &models.CreateMessageRequest{Content: "mod ping"}); err != nil {
+				t.Fatalf("manager message %d (perm %d): %v", i, perm, err)
+			}
+		}
+	}
+}
+
+func TestMessageCreate_SlowmodeOffByDefault(t *testing.T) {
+	svc := newSlowmodeTestService(0, models.PermSendMessages)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Create(ctx, "ch1", "u1", &models.CreateMessageRequest{Content: "burst"}); err != nil {
+			t.Fatalf("message %d without slowmode: %v", i, err)
+		}
+	}
+}
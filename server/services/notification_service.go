@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+)
+
+// NotificationService owns category mutes and resolves a user's effective notification
+// setting per channel: channel override → category setting → server setting. A channel is
+// notification-muted if any level in that chain is muted. Mutes silence notifications and
+// mention badges only — the unread dot is untouched.
+type NotificationService interface {
+	MuteCategory(ctx context.Context, userID, categoryID, serverID string, req *models.MuteCategoryRequest) error
+	UnmuteCategory(ctx context.Context, userID, categoryID string) error
+	GetMutedCategoryIDs(ctx context.Context, userID string) ([]string, error)
+	// EffectiveMutedChannelIDs returns the server's channels whose notifications are
+	// suppressed for this user, with category and server mutes cascaded down. One
+	// channel listing plus three ID-list reads — no per-channel queries.
+	EffectiveMutedChannelIDs(ctx context.Context, userID, serverID string) (map[string]bool, error)
+	// UpdateSetting stores the user's granular setting for one scope, or clears the
+	// row when it is back at the defaults, and returns the effective setting.
+	UpdateSetting(ctx context.Context, userID, scopeType, scopeID string, req *models.UpdateNotificationSettingRequest) (*models.NotificationSetting, error)
+	ListSettings(ctx context.Context, userID string) ([]models.NotificationSetting, error)
+	ShouldNotifyMention(ctx context.Context, userID, serverID, channelID string) (bool, error)
+}
+
+type notificationService struct {
+	categoryMuteRepo repository.CategoryMuteRepository
+	channelMuteRepo  repository.ChannelMuteRepository
+	serverMuteRepo   repository.ServerMuteRepository
+	channelRepo      repository.ChannelRepository
+	settingRepo      repository.NotificationSettingRepository
+}
+
+func NewNotificationService(
+	categoryMuteRepo repository.CategoryMuteRepository,
+	channelMuteRepo repository.ChannelMuteRepository,
+	serverMuteRepo repository.ServerMuteRepository,
+	channelRepo repository.ChannelRepository,
+	settingRepo repository.NotificationSettingRepository,
+) NotificationService {
+	return &notificationService{
+		categoryMuteRepo: categoryMuteRepo,
+		channelMuteRepo:  channelMuteRepo,
+		serverMuteRepo:   serverMuteRepo,
+		channelRepo:      channelRepo,
+		settingRepo:      settingRepo,
+	}
+}
+
+func (s *notificationService) MuteCategory(ctx context.Context, userID, categoryID, serverID string, req *models.MuteCategoryRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// Convert duration to SQLite-compatible timestamp string
+	mutedUntil := req.ParseMutedUntil()
+	var mutedUntilStr *string
+	if mutedUntil != nil {
+		s := mutedUntil.Format("2006-01-02 15:04:05")
+		mutedUntilStr = &s
+	}
+
+	return s.categoryMuteRepo.Upsert(ctx, userID, categoryID, serverID, mutedUntilStr)
+}
+
+func (s *notificationService) UnmuteCategory(ctx context.Context, userID, categoryID string) error {
+	return s.categoryMuteRepo.Delete(ctx, userID, categoryID)
+}
+
+func (s *notificationService) GetMutedCategoryIDs(ctx context.Context, userID string) ([]string, error) {
+	return s.categoryMuteRepo.GetMutedCategoryIDs(ctx, userID)
+}
+
+// UpdateSetting validates the scope, persists the row, and deletes it when it is
+// back at the defaults — no row and the default row must mean the same thing, or
+// ListSettings would accumulate noise forever.
+func (s *notificationService) UpdateSetting(ctx context.Context, userID, scopeType, scopeID string, req *models.UpdateNotificationSettingRequest) (*models.NotificationSetting, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+	}
+	if scopeType != models.NotificationScopeServer && scopeType != models.NotificationScopeChannel {
+		return nil, fmt.Errorf("%w: scope type must be server or channel", pkg.ErrBadRequest)
+	}
+	if req.SuppressEveryone && scopeType != models.NotificationScopeServer {
+		return nil, fmt.Errorf("%w: suppress_everyone only applies to server scope", pkg.ErrBadRequest)
+	}
+
+	setting := &models.NotificationSetting{
+		ScopeType:        scopeType,
+		ScopeID:          scopeID,
+		Level:            req.Level,
+		SuppressEveryone: req.SuppressEveryone,
+	}
+	if setting.Level == models.NotificationLevelAll && !setting.SuppressEveryone {
+		if err := s.settingRepo.Delete(ctx, userID, scopeType, scopeID); err != nil {
+			return nil, err
+		}
+		return setting, nil
+	}
+	if err := s.settingRepo.Upsert(ctx, userID, setting); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+func (s *notificationService) ListSettings(ctx context.Context, userID string) ([]models.NotificationSetting, error) {
+	return s.settingRepo.ListByUser(ctx, userID)
+}
+
+// ShouldNotifyMention reports whether a direct mention of userID in this channel
+// should reach them at all. The channel setting wins over the server setting, and
+// only "nothing" silences a mention — "mentions" exists precisely to let them
+// through. The binary mute tables are deliberately not consulted: a mute hides
+// notifications but keeps the mention badge, while "nothing" erases the mention.
+func (s *notificationService) ShouldNotifyMention(ctx context.Context, userID, serverID, channelID string) (bool, error) {
+	setting, err := s.settingRepo.Get(ctx, userID, models.NotificationScopeChannel, channelID)
+	if err != nil {
+		return false, err
+	}
+	if setting == nil && serverID != "" {
+		setting, err = s.settingRepo.Get(ctx, userID, models.NotificationScopeServer, serverID)
+		if err != nil {
+			return false, err
+		}
+	}
+	if setting != nil {
+		return setting.Level != models.NotificationLevelNothing, nil
+	}
+	return true, nil
+}
+
+func (s *notificationService) EffectiveMutedChannelIDs(ctx context.Context, userID, serverID string) (map[string]bool, error) {
+	muted := make(map[string]bool)
+
+	// Server mute blankets everything — no need to look further down the chain.
+	serverIDs, err := s.serverMuteRepo.GetMutedServerIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	serverMuted := false
+	for _, id := range serverIDs {
+		if id == serverID {
+			serverMuted = true
+			break
+		}
+	}
+
+	channels, err := s.channelRepo.GetAllByServer(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if serverMuted {
+		for _, c := range channels {
+			muted[c.ID] = true
+		}
+		return muted, nil
+	}
+
+	categoryIDs, err := s.categoryMuteRepo.GetMutedCategoryIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	mutedCategories := make(map[string]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		mutedCategories[id] = true
+	}
+
+	channelIDs, err := s.channelMuteRepo.GetMutedChannelIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range channelIDs {
+		muted[id] = true
+	}
+
+	for _, c := range channels {
+		if c.CategoryID != nil && mutedCategories[*c.CategoryID] {
+			muted[c.ID] = true
+		}
+	}
+
+	return muted, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+// fakeSettingRepo keeps rows in a map keyed user|scopeType|scopeID — enough to
+// exercise the precedence and garbage-collection logic without a database.
+type fakeSettingRepo struct {
+	rows    map[string]models.NotificationSetting
+	getErr  error
+	deletes int
+}
+
+func newFakeSettingRepo() *fakeSettingRepo {
+	return &fakeSettingRepo{rows: map[string]models.NotificationSetting{}}
+}
+
+func (r *fakeSettingRepo) Upsert(_ context.Context, userID string, s *models.NotificationSetting) error {
+	r.rows[userID+"|"+s.ScopeType+"|"+s.ScopeID] = *s
+	return nil
+}
+
+func (r *fakeSettingRepo) Get(_ context.Context, userID, scopeType, scopeID string) (*models.NotificationSetting, error) {
+	if r.getErr != nil {
+		return nil, r.getErr
+	}
+	if s, ok := r.rows[userID+"|"+scopeType+"|"+scopeID]; ok {
+		return &s, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeSettingRepo) ListByUser(_ context.Context, userID string) ([]models.NotificationSetting, error) {
+	var out []models.NotificationSetting
+	for k, s := range r.rows {
+		if len(k) > len(userID) && k[:len(userID)+1] == userID+"|" {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeSettingRepo) Delete(_ context.Context, userID, scopeType, scopeID string) error {
+	delete(r.rows, userID+"|"+scopeType+"|"+scopeID)
+	r.deletes++
+	return nil
+}
+
+func TestUpdateSetting_RejectsBadScopesAndChannelSuppress(t *testing.T) {
+	svc := &notificationService{settingRepo: newFakeSettingRepo()}
+	ctx := context.Background()
+
+	_, err := svc.UpdateSetting(ctx, "u1", "dm", "x", &models.UpdateNotificationSettingRequest{Level: "nothing"})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("unknown scope type accepted, got err=%v", err)
+	}
+
+	_, err = svc.UpdateSetting(ctx, "u1", models.NotificationScopeChannel, "c1", &models.UpdateNotificationSettingRequest{SuppressEveryone: true})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("suppress_everyone on a channel accepted, got err=%v", err)
+	}
+
+	_, err = svc.UpdateSetting(ctx, "u1", models.NotificationScopeServer, "s1", &models.UpdateNotificationSettingRequest{Level: "loud"})
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("unknown level accepted, got err=%v", err)
+	}
+}
+
+// A row that matches the defaults must be deleted, not stored — no row and the
+// default row have to mean the same thing, or ListSettings accumulates noise.
+func TestUpdateSetting_DefaultRowIsDeletedNotStored(t *testing.T) {
+	repo := newFakeSettingRepo()
+	svc := &notificationService{settingRepo: repo}
+	ctx := context.Background()
+
+	if _, err := svc.UpdateSetting(ctx, "u1", models.NotificationScopeServer, "s1", &models.UpdateNotificationSettingRequest{Level: models.NotificationLevelNothing}); err != nil {
+		t.Fatalf("UpdateSetting: %v", err)
+	}
+	if len(repo.rows) != 1 {
+		t.Fatalf("%d rows stored, want 1", len(repo.rows))
+	}
+
+	// An empty level means "all" — together with no suppression that is the default.
+	if _, err := svc.UpdateSetting(ctx, "u1", models.NotificationScopeServer, "s1", &models.UpdateNotificationSettingRequest{}); err != nil {
+		t.Fatalf("UpdateSetting (reset): %v", err)
+	}
+	if len(repo.rows) != 0 || repo.deletes != 1 {
+		t.Errorf("reset left %d rows (%d deletes) — the default must clear the row", len(repo.rows), repo.deletes)
+	}
+}
+
+func TestShouldNotifyMention_ChannelSettingWinsOverServer(t *testing.T) {
+	repo := newFakeSettingRepo()
+	svc := &notificationService{settingRepo: repo}
+	ctx := context.Background()
+
+	// No rows at all: mentions go through.
+	if ok, err := svc.ShouldNotifyMention(ctx, "u1", "s1", "c1"); err != nil || !ok {
+		t.Errorf("default blocked a mention (ok=%v err=%v)", ok, err)
+	}
+
+	// Server says nothing — silenced everywhere in the server...
+	repo.Upsert(ctx, "u1", &models.NotificationSetting{ScopeType: models.NotificationScopeServer, ScopeID: "s1", Level: models.NotificationLevelNothing})
+	if ok, _ := svc.ShouldNotifyMention(ctx, "u1", "s1", "c1"); ok {
+		t.Error("server-level \"nothing\" did not silence the mention")
+	}
+
+	// ...except where a channel row overrides it.
+	repo.Upsert(ctx, "u1", &models.NotificationSetting{ScopeType: models.NotificationScopeChannel, ScopeID: "c1", Level: models.NotificationLevelMentions})
+	if ok, _ := svc.ShouldNotifyMention(ctx, "u1", "s1", "c1"); !ok {
+		t.Error("a channel-level \"mentions\" row did not override the server's \"nothing\"")
+	}
+
+	// "mentions" exists to let mentions through — only "nothing" blocks them.
+	repo.Upsert(ctx, "u1", &models.NotificationSetting{ScopeType: models.NotificationScopeChannel, ScopeID: "c1", Level: models.NotificationLevelNothing})
+	if ok, _ := svc.ShouldNotifyMention(ctx, "u1", "s1", "c1"); ok {
+		t.Error("channel-level \"nothing\" did not silence the mention")
+	}
+}
+
+// The gate filters mention rows at persistence time, and a failed settings read
+// fails open — a transient error must not swallow someone's ping.
+func TestApplyMentionGate_FiltersOptedOutUsersAndFailsOpen(t *testing.T) {
+	repo := newFakeSettingRepo()
+	gate := &notificationService{settingRepo: repo}
+	repo.Upsert(context.Background(), "bob", &models.NotificationSetting{ScopeType: models.NotificationScopeServer, ScopeID: "s1", Level: models.NotificationLevelNothing})
+
+	msgSvc := &messageService{mentionGate: gate}
+	got := msgSvc.applyMentionGate(context.Background(), "s1", "c1", []string{"alice", "bob"})
+	if len(got) != 1 || got[0] != "alice" {
+		t.Errorf("gate kept %v, want [alice]", got)
+	}
+
+	repo.getErr = errors.New("settings table on fire")
+	got = msgSvc.applyMentionGate(context.Background(), "s1", "c1", []string{"alice", "bob"})
+	if len(got) != 2 {
+		t.Errorf("a failed settings read dropped mentions: %v", got)
+	}
+
+	msgSvc = &messageService{} // unwired gate
+	got = msgSvc.applyMentionGate(context.Background(), "s1", "c1", []string{"alice", "bob"})
+	if len(got) != 2 {
+		t.Errorf("an unwired gate dropped mentions: %v", got)
+	}
+}
@@ -68,6 +68,18 @@ type P2PCallService interface {
 	// HasActiveCall reports whether the user is in an ACCEPTED (active) call.
 	// Status is read under the lock — no mutable pointer escapes.
 	HasActiveCall(userID string) bool
+	// Group calls — see group_call_service.go. More than two participants means
+	// an ad-hoc LiveKit room instead of a direct peer link, so these manage
+	// membership and hand out room tokens rather than relaying SDP.
+	InitiateGroupCall(callerID, sessionID string, receiverIDs []string, callType models.P2PCallType) error
+	AcceptGroupCall(userID, callID string) error
+	DeclineGroupCall(userID, callID string) error
+	LeaveGroupCall(userID, callID string) error
+	// LeaveAllGroupCalls removes a fully-disconnected user from any group call.
+	LeaveAllGroupCalls(userID string)
+	// SetGroupCallInfra wires the LiveKit room infrastructure post-construction.
+	// Never set (tests) means group calls are unavailable, not broken.
+	SetGroupCallInfra(instances PlatformInstanceGetter, secrets LiveKitSecretDecrypter)
 	SetAppLogger(logger P2PAppLogger)
 	SetCallLogger(logger CallLogger)
 	SetPushNotifier(n PushNotifier)
@@ -95,7 +107,18 @@ type p2pCallService struct {
 	// back — and the call would stay Active forever, which is the exact bug FIX-03 fixed.
 	graceTimers map[string]*time.Timer // callID|userID -> teardown timer
 	graceWindow time.Duration
-	mu          sync.RWMutex
+
+	// Group call state, same lifetime rules as activeCalls. groupRooms keeps the
+	// pinned LiveKit instance (decrypted creds) out of the broadcastable model.
+	groupCalls      map[string]*models.GroupCall // callID -> call
+	userGroupCalls  map[string]string            // userID -> callID (exclusive with userCalls)
+	groupRingTimers map[string]*time.Timer       // callID -> pending-invite timeout
+	groupRooms      map[string]groupCallRoom     // callID -> LiveKit room credentials
+
+	platformInstances PlatformInstanceGetter // set post-construction, may be nil
+	secretDecrypter   LiveKitSecretDecrypter // set post-construction, may be nil
+
+	mu sync.RWMutex
 }
 
 // ringingTimeout auto-cleans a call that is never answered. Slightly longer than
@@ -165,6 +188,11 @@ func NewP2PCallService(
 		ringTimers:    make(map[string]*time.Timer),
 		graceTimers:   make(map[string]*time.Timer),
 		graceWindow:   graceWindow,
+
+		groupCalls:      make(map[string]*models.GroupCall),
+		userGroupCalls:  make(map[string]string),
+		groupRingTimers: make(map[string]*time.Timer),
+		groupRooms:      make(map[string]groupCallRoom),
 	}
 }
 
@@ -298,11 +326,11 @@ func (s *p2pCallService) InitiateCall(callerID, sessionID, receiverID string, ca
 	// concurrent initiates from the same caller both pass the check and overwrite
 	// userCalls, orphaning a call in activeCalls.
 	s.mu.Lock()
-	if _, callerBusy := s.userCalls[callerID]; callerBusy {
+	if s.userBusyLocked(callerID) {
 		s.mu.Unlock()
 		return fmt.Errorf("%w: already in a call", pkg.ErrBadRequest)
 	}
-	if _, receiverBusy := s.userCalls[receiverID]; receiverBusy {
+	if s.userBusyLocked(receiverID) {
 		s.mu.Unlock()
 		// Broadcast outside the lock — no I/O under the mutex.
 		s.hub.BroadcastToUser(callerID, ws.Event{
@@ -27,6 +27,7 @@ func (fakeHub) GetOnlineUserIDs() []string                        { return nil }
 func (fakeHub) GetVisibleOnlineUserIDs() []string                 { return nil }
 func (fakeHub) GetOnlineUserIDsForServer(string) []string         { return nil }
 func (fakeHub) GetOnlineCountsForServers([]string) map[string]int { return nil }
+func (fakeHub) GetStatusesForUsers([]string) map[string]string    { return nil }
 
 // Minimal fakes — only the methods InitiateCall reaches before the busy-check.
 
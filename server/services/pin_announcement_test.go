@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// recordingMessenger captures CreateSystemMessage calls so the test can assert
+// what the pin announcement carried without standing up a full message service.
+type recordingMessenger struct {
+	calls []struct {
+		channelID string
+		userID    string
+		msgType   models.MessageType
+		replyToID *string
+	}
+}
+
+func (r *recordingMessenger) CreateSystemMessage(ctx context.Context, channelID, userID string, msgType models.MessageType, content string, replyToID *string) (*models.Message, error) {
+	r.calls = append(r.calls, struct {
+		channelID string
+		userID    string
+		msgType   models.MessageType
+		replyToID *string
+	}{channelID, userID, msgType, replyToID})
+	return &models.Message{ChannelID: channelID, UserID: userID, Type: msgType}, nil
+}
+
+// Pinning posts a best-effort system message in the channel, gated by the
+// per-channel pin_announcements toggle (on by default via migration 093).
+func TestPin_PostsAnnouncement(t *testing.T) {
+	f := dbtest.New(t)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	channelID := f.Channel("", serverID)
+	userID := f.User("")
+	msgID := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: userID, Content: dbtest.Ptr("pin me")})
+
+	messenger := &recordingMessenger{}
+	svc := NewPinService(
+		repository.NewSQLitePinRepo(f.DB),
+		repository.NewSQLiteMessageRepo(f.DB),
+		repository.NewSQLiteChannelRepo(f.DB),
+		&testutil.MockBroadcastAndOnline{},
+		&testutil.MockChannelPermResolver{},
+		&testutil.MockFileURLSigner{},
+		50,
+	)
+	svc.SetSystemMessenger(messenger)
+
+	if _, err := svc.Pin(ctx, serverID, msgID, channelID, userID); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if len(messenger.calls) != 1 {
+		t.Fatalf("expected 1 announcement, got %d", len(messenger.calls))
+	}
+	call := messenger.calls[0]
+	if call.channelID != channelID || call.userID != userID || call.msgType != models.MessageTypePin {
+		t.Errorf("announcement carried wrong fields: %+v", call)
+	}
+	if call.replyToID == nil || *call.replyToID != msgID {
+		t.Error("announcement must reference the pinned message so clients can link to it")
+	}
+
+	// Toggle the channel setting off: pinning stays silent.
+	if _, err := f.DB.ExecContext(ctx,
+		`UPDATE channels SET pin_announcements = 0 WHERE id = ?`, channelID); err != nil {
+		t.Fatalf("disable announcements: %v", err)
+	}
+	msg2 := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: userID, Content: dbtest.Ptr("quiet pin")})
+	if _, err := svc.Pin(ctx, serverID, msg2, channelID, userID); err != nil {
+		t.Fatalf("pin with announcements off: %v", err)
+	}
+	if len(messenger.calls) != 1 {
+		t.Fatalf("announcement must not fire when the channel toggle is off, got %d calls", len(messenger.calls))
+	}
+}
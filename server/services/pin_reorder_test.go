@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// newPinFixture stands up a pin service over the test DB with a recording
+// broadcaster and a resolver granting view+read (so allowedViewers and
+// ListPins pass). Returned slice pointer collects broadcast events.
+func newPinFixture(f *dbtest.Fixture, maxPins int) (PinService, *[]ws.Event) {
+	var events []ws.Event
+	hub := &testutil.MockBroadcastAndOnline{
+		GetOnlineUserIDsForServerFn: func(string) []string { return []string{"viewer"} },
+	}
+	hub.BroadcastToUsersFn = func(_ []string, event ws.Event) {
+		events = append(events, event)
+	}
+	svc := NewPinService(
+		repository.NewSQLitePinRepo(f.DB),
+		repository.NewSQLiteMessageRepo(f.DB),
+		repository.NewSQLiteChannelRepo(f.DB),
+		hub,
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(context.Context, string, string) (models.Permission, error) {
+				return models.PermViewChannel | models.PermReadMessages, nil
+			},
+		},
+		&testutil.MockFileURLSigner{},
+		maxPins,
+	)
+	return svc, &events
+}
+
+func TestPinLimit_IsConfigurable(t *testing.T) {
+	f := dbtest.New(t)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	channelID := f.Channel("", serverID)
+	userID := f.User("")
+	first := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: userID, Content: dbtest.Ptr("first")})
+	second := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: userID, Content: dbtest.Ptr("second")})
+
+	svc, _ := newPinFixture(f, 1)
+	if _, err := svc.Pin(ctx, serverID, first, channelID, userID); err != nil {
+		t.Fatalf("first pin: %v", err)
+	}
+	if _, err := svc.Pin(ctx, serverID, second, channelID, userID); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("pin beyond the configured limit: got %v, want bad request", err)
+	}
+}
+
+func TestReorderPins_UpdatesOrderAndBroadcasts(t *testing.T) {
+	f := dbtest.New(t)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	channelID := f.Channel("", serverID)
+	userID := f.User("")
+
+	var msgs []string
+	for _, content := range []string{"a", "b", "c"} {
+		msgs = append(msgs, f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: userID, Content: dbtest.Ptr(content)}))
+	}
+
+	svc, events := newPinFixture(f, 50)
+	for _, id := range msgs {
+		if _, err := svc.Pin(ctx, serverID, id, channelID, userID); err != nil {
+			t.Fatalf("pin %s: %v", id, err)
+		}
+	}
+	*events = nil // ignore the pin broadcasts
+
+	want := []string{msgs[1], msgs[2], msgs[0]}
+	pins, err := svc.Reorder(ctx, serverID, channelID, &models.ReorderPinsRequest{MessageIDs: want})
+	if err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	for i, pin := range pins {
+		if pin.MessageID != want[i] {
+			t.Fatalf("pin order after reorder mismatch at %d: got %s, want %s", i, pin.MessageID, want[i])
+		}
+		if pin.PinPosition != i {
+			t.Errorf("pin %s position = %d, want %d", pin.MessageID, pin.PinPosition, i)
+		}
+	}
+
+	if len(*events) != 1 || (*events)[0].Op != ws.OpMessagePinReorder {
+		t.Fatalf("broadcasts = %+v, want one %s event", *events, ws.OpMessagePinReorder)
+	}
+
+	// ListPins reflects the stored order too, not just the reorder response.
+	listed, err := svc.GetPinnedMessages(ctx, userID, channelID)
+	if err != nil {
+		t.Fatalf("list pins: %v", err)
+	}
+	for i, pin := range listed {
+		if pin.MessageID != want[i] {
+			t.Fatalf("listed order mismatch at %d: got %s, want %s", i, pin.MessageID, want[i])
+		}
+	}
+}
+
+func TestReorderPins_RejectsBadInput(t *testing.T) {
+	f := dbtest.New(t)
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	channelID := f.Channel("", serverID)
+	userID := f.User("")
+	msgID := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: userID, Content: dbtest.Ptr("pinned")})
+	otherServer := f.Server(dbtest.ServerSeed{})
+
+	svc, _ := newPinFixture(f, 50)
+	if _, err := svc.Pin(ctx, serverID, msgID, channelID, userID); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+
+	if _, err := svc.Reorder(ctx, serverID, channelID,
+		&models.ReorderPinsRequest{}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("empty list: got %v, want bad request", err)
+	}
+	if _, err := svc.Reorder(ctx, serverID, channelID,
+		&models.ReorderPinsRequest{MessageIDs: []string{"not-pinned"}}); !errors.Is(err, pkg.ErrNotFound) {
+		t.Errorf("unknown message: got %v, want not found", err)
+	}
+	if _, err := svc.Reorder(ctx, otherServer, channelID,
+		&models.ReorderPinsRequest{MessageIDs: []string{msgID}}); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("cross-server channel: got %v, want forbidden", err)
+	}
+}
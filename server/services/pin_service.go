@@ -11,22 +11,35 @@ import (
 	"github.com/akinalp/mqvi/ws"
 )
 
-// MaxPinsPerChannel is the max number of pins per channel (same as Discord: 50).
-const MaxPinsPerChannel = 50
-
 type PinService interface {
 	Pin(ctx context.Context, serverID string, messageID string, channelID string, pinnedBy string) (*models.PinnedMessageWithDetails, error)
 	Unpin(ctx context.Context, serverID string, messageID string, channelID string) error
 	GetPinnedMessages(ctx context.Context, userID string, channelID string) ([]models.PinnedMessageWithDetails, error)
+	Reorder(ctx context.Context, serverID string, channelID string, req *models.ReorderPinsRequest) ([]models.PinnedMessageWithDetails, error)
+	SetSystemMessenger(m SystemMessenger)
+}
+
+// SystemMessenger is the slice of MessageService the pin service needs to drop a
+// pin announcement into the channel timeline. Injected via setter because the
+// message service is constructed before the pin service.
+type SystemMessenger interface {
+	CreateSystemMessage(ctx context.Context, channelID, userID string, msgType models.MessageType, content string, replyToID *string) (*models.Message, error)
 }
 
 type pinService struct {
-	pinRepo      repository.PinRepository
-	messageRepo  repository.MessageRepository
-	channelRepo  repository.ChannelRepository
-	hub          ws.BroadcastAndOnline
-	permResolver ChannelPermResolver
-	urlSigner    FileURLSigner
+	pinRepo         repository.PinRepository
+	messageRepo     repository.MessageRepository
+	channelRepo     repository.ChannelRepository
+	hub             ws.BroadcastAndOnline
+	permResolver    ChannelPermResolver
+	urlSigner       FileURLSigner
+	systemMessenger SystemMessenger
+	maxPins         int
+}
+
+// SetSystemMessenger wires the message service in after construction.
+func (s *pinService) SetSystemMessenger(m SystemMessenger) {
+	s.systemMessenger = m
 }
 
 func NewPinService(
@@ -36,6 +49,7 @@ func NewPinService(
 	hub ws.BroadcastAndOnline,
 	permResolver ChannelPermResolver,
 	urlSigner FileURLSigner,
+	maxPins int,
 ) PinService {
 	return &pinService{
 		pinRepo:      pinRepo,
@@ -44,6 +58,7 @@ func NewPinService(
 		hub:          hub,
 		permResolver: permResolver,
 		urlSigner:    urlSigner,
+		maxPins:      maxPins,
 	}
 }
 
@@ -93,8 +108,8 @@ func (s *pinService) Pin(ctx context.Context, serverID string, messageID string,
 	if err != nil {
 		return nil, fmt.Errorf("failed to check pin count: %w", err)
 	}
-	if count >= MaxPinsPerChannel {
-		return nil, fmt.Errorf("%w: channel has reached the maximum number of pins (%d)", pkg.ErrBadRequest, MaxPinsPerChannel)
+	if count >= s.maxPins {
+		return nil, fmt.Errorf("%w: channel has reached the maximum number of pins (%d)", pkg.ErrBadRequest, s.maxPins)
 	}
 
 	pin := &models.PinnedMessage{
@@ -119,6 +134,15 @@ func (s *pinService) Pin(ctx context.Context, serverID string, messageID string,
 	})
 	log.Printf("[pin] message %s pinned in channel %s by user %s", messageID, channelID, pinnedBy)
 
+	// Pin announcement: best-effort system message ("X pinned a message") so the pin
+	// is discoverable in the timeline. Per-channel toggle; the reply reference lets
+	// clients jump to the pinned message. A failure never unwinds the pin itself.
+	if channel.PinAnnouncements && s.systemMessenger != nil {
+		if _, err := s.systemMessenger.CreateSystemMessage(ctx, channelID, pinnedBy, models.MessageTypePin, "pinned a message", &messageID); err != nil {
+			log.Printf("[pin] failed to post pin announcement in channel %s: %v", channelID, err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -180,6 +204,49 @@ func (s *pinService) GetPinnedMessages(ctx context.Context, userID string, chann
 	return pins, nil
 }
 
+// Reorder rewrites the channel's pin order from an ordered message ID list
+// (first = top). Pins not listed keep their positions, so a partial list only
+// moves the pins it names.
+func (s *pinService) Reorder(ctx context.Context, serverID string, channelID string, req *models.ReorderPinsRequest) ([]models.PinnedMessageWithDetails, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
+	}
+
+	// IDOR guard: the channel must belong to the route's server. The per-pin
+	// channel check lives in the repository UPDATE's WHERE clause.
+	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil || channel.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+
+	if err := s.pinRepo.UpdatePositions(ctx, channelID, req.MessageIDs); err != nil {
+		return nil, err
+	}
+
+	s.hub.BroadcastToUsers(s.allowedViewers(channelID), ws.Event{
+		Op: ws.OpMessagePinReorder,
+		Data: map[string]any{
+			"channel_id":  channelID,
+			"message_ids": req.MessageIDs,
+		},
+	})
+
+	pins, err := s.pinRepo.GetByChannelID(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload pins after reorder: %w", err)
+	}
+	for i := range pins {
+		s.signPinnedMessage(pins[i].Message)
+		if pins[i].PinnedByUser != nil {
+			pins[i].PinnedByUser.AvatarURL = s.urlSigner.SignURLPtr(pins[i].PinnedByUser.AvatarURL)
+		}
+	}
+	return pins, nil
+}
+
 // signPinnedMessage signs avatar URLs in a message's Author and ReferencedMessage.Author.
 func (s *pinService) signPinnedMessage(msg *models.Message) {
 	if msg == nil {
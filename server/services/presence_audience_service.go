@@ -0,0 +1,138 @@
+// Package services — presence audience resolution.
+// Presence updates used to go to every connected user; that leaks online
+// status to strangers and scales with the whole instance instead of the
+// user's social graph. The audience for a user's presence is everyone who
+// shares a server with them, their accepted friends, and the user themself
+// (their other devices).
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akinalp/mqvi/repository"
+)
+
+// presenceAudienceTTL bounds how stale a cached audience may get. Presence
+// changes fire on every connect/disconnect/idle flip, so the cache absorbs
+// almost all of the repo traffic; a new friendship or membership shows up
+// within this window.
+const presenceAudienceTTL = 30 * time.Second
+
+// PresenceAudienceService resolves who may see a user's presence.
+type PresenceAudienceService interface {
+	// Recipients returns the user IDs that should receive presence updates
+	// about userID. Always includes userID itself.
+	Recipients(ctx context.Context, userID string) ([]string, error)
+	// Statuses returns the current status of each requested user the
+	// requester is allowed to see — the batch catch-up counterpart to the
+	// presence broadcasts. IDs outside the requester's audience are omitted
+	// from the result, not reported as offline.
+	Statuses(ctx context.Context, requesterID string, userIDs []string) (map[string]string, error)
+}
+
+// PresenceStatusProvider is the slice of the Hub the batch query needs.
+type PresenceStatusProvider interface {
+	GetStatusesForUsers(userIDs []string) map[string]string
+}
+
+type presenceAudienceService struct {
+	serverRepo repository.ServerRepository
+	friendRepo repository.FriendshipRepository
+	statuses   PresenceStatusProvider
+
+	mu    sync.Mutex
+	cache map[string]presenceAudienceEntry
+}
+
+type presenceAudienceEntry struct {
+	ids     []string
+	expires time.Time
+}
+
+// NewPresenceAudienceService creates the presence audience resolver.
+func NewPresenceAudienceService(serverRepo repository.ServerRepository, friendRepo repository.FriendshipRepository, statuses PresenceStatusProvider) PresenceAudienceService {
+	return &presenceAudienceService{
+		serverRepo: serverRepo,
+		friendRepo: friendRepo,
+		statuses:   statuses,
+		cache:      make(map[string]presenceAudienceEntry),
+	}
+}
+
+func (s *presenceAudienceService) Recipients(ctx context.Context, userID string) ([]string, error) {
+	s.mu.Lock()
+	if entry, ok := s.cache[userID]; ok && time.Now().Before(entry.expires) {
+		ids := entry.ids
+		s.mu.Unlock()
+		return ids, nil
+	}
+	// Expired entries are dropped on overwrite below — the map is bounded by
+	// the users who have been active within a process lifetime, not pruned.
+	s.mu.Unlock()
+
+	set := map[string]struct{}{userID: {}}
+
+	serverIDs, err := s.serverRepo.GetMemberServerIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member servers: %w", err)
+	}
+	for _, serverID := range serverIDs {
+		memberIDs, err := s.serverRepo.GetMemberUserIDs(ctx, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get server members: %w", err)
+		}
+		for _, id := range memberIDs {
+			set[id] = struct{}{}
+		}
+	}
+
+	friends, err := s.friendRepo.ListFriends(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friends: %w", err)
+	}
+	for _, f := range friends {
+		set[f.UserID] = struct{}{}
+	}
+
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	s.mu.Lock()
+	s.cache[userID] = presenceAudienceEntry{ids: ids, expires: time.Now().Add(presenceAudienceTTL)}
+	s.mu.Unlock()
+
+	return ids, nil
+}
+
+func (s *presenceAudienceService) Statuses(ctx context.Context, requesterID string, userIDs []string) (map[string]string, error) {
+	// The audience relation is symmetric — shared membership and accepted
+	// friendship both are — so "who may see the requester" is exactly the set
+	// whose presence the requester may see, and Recipients' cache is reused.
+	audience, err := s.Recipients(ctx, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]struct{}, len(audience))
+	for _, id := range audience {
+		allowed[id] = struct{}{}
+	}
+
+	permitted := make([]string, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		if _, ok := allowed[id]; ok {
+			permitted = append(permitted, id)
+		}
+	}
+
+	return s.statuses.GetStatusesForUsers(permitted), nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/repository"
+)
+
+type audienceServerRepo struct {
+	repository.ServerRepository
+	lookups int
+}
+
+func (r *audienceServerRepo) GetMemberServerIDs(_ context.Context, _ string) ([]string, error) {
+	r.lookups++
+	return []string{"s1", "s2"}, nil
+}
+
+func (r *audienceServerRepo) GetMemberUserIDs(_ context.Context, serverID string) ([]string, error) {
+	if serverID == "s1" {
+		return []string{"alice", "bob"}, nil
+	}
+	return []string{"alice", "carol"}, nil
+}
+
+type audienceFriendRepo struct {
+	repository.FriendshipRepository
+}
+
+func (audienceFriendRepo) ListFriends(_ context.Context, _ string) ([]models.FriendshipWithUser, error) {
+	return []models.FriendshipWithUser{{UserID: "frank"}}, nil
+}
+
+func TestPresenceAudience_UnionsServersAndFriends(t *testing.T) {
+	sr := &audienceServerRepo{}
+	svc := NewPresenceAudienceService(sr, audienceFriendRepo{}, stubStatusProvider{})
+
+	got, err := svc.Recipients(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("recipients: %v", err)
+	}
+	sort.Strings(got)
+	want := []string{"alice", "bob", "carol", "frank"}
+	if len(got) != len(want) {
+		t.Fatalf("recipients = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("recipients = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPresenceAudience_CachesWithinTTL(t *testing.T) {
+	sr := &audienceServerRepo{}
+	svc := NewPresenceAudienceService(sr, audienceFriendRepo{}, stubStatusProvider{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Recipients(context.Background(), "alice"); err != nil {
+			t.Fatalf("recipients: %v", err)
+		}
+	}
+	if sr.lookups != 1 {
+		t.Errorf("repo lookups = %d, want 1 (presence flaps must hit the cache)", sr.lookups)
+	}
+}
+
+// stubStatusProvider marks every asked-about user online — which IDs reach it
+// at all is the thing under test.
+type stubStatusProvider struct{}
+
+func (stubStatusProvider) GetStatusesForUsers(ids []string) map[string]string {
+	out := make(map[string]string, len(ids))
+	for _, id := range ids {
+		out[id] = "online"
+	}
+	return out
+}
+
+func TestPresenceStatuses_OmitsUsersOutsideAudience(t *testing.T) {
+	svc := NewPresenceAudienceService(&audienceServerRepo{}, audienceFriendRepo{}, stubStatusProvider{})
+
+	// alice's audience: herself, bob+carol (shared servers), frank (friend).
+	// "stranger" shares nothing and must be absent, not reported offline.
+	got, err := svc.Statuses(context.Background(), "alice", []string{"bob", "frank", "stranger", "alice", "bob"})
+	if err != nil {
+		t.Fatalf("statuses: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("statuses = %v, want exactly bob, frank and alice", got)
+	}
+	for _, id := range []string{"bob", "frank", "alice"} {
+		if got[id] != "online" {
+			t.Errorf("status[%s] = %q, want online", id, got[id])
+		}
+	}
+	if _, leaked := got["stranger"]; leaked {
+		t.Error("stranger's presence must not be returned")
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// newLimitedReactionService builds a ReactionService where the actor has full
+// channel permissions, backed by the given message state.
+func newLimitedReactionService(groups []models.ReactionGroup, userCount int, maxPerUser, maxDistinct int, toggled *bool) ReactionService {
+	return NewReactionService(
+		&testutil.MockReactionRepo{
+			ToggleFn: func(_ context.Context, _, _, _ string) (bool, error) {
+				*toggled = true
+				return true, nil
+			},
+			GetByMessageIDFn: func(_ context.Context, _ string) ([]models.ReactionGroup, error) {
+				return groups, nil
+			},
+			CountUserReactionsFn: func(_ context.Context, _, _ string) (int, error) {
+				return userCount, nil
+			},
+		},
+		&testutil.MockMessageRepo{
+			GetByIDFn: func(_ context.Context, _ string) (*models.Message, error) {
+				return &models.Message{ID: "m1", ChannelID: "ch1"}, nil
+			},
+		},
+		&testutil.MockChannelRepo{},
+		nil, // serverRepo — only consulted for self-reactions, which these tests never make
+		&testutil.MockBroadcastAndOnline{},
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+				return models.PermAll, nil
+			},
+		},
+		&testutil.MockFileURLSigner{},
+		maxPerUser, maxDistinct,
+	)
+}
+
+func TestToggleReaction_PerUserLimit(t *testing.T) {
+	existing := []models.ReactionGroup{
+		{Emoji: "👍", Count: 1, Users: []string{"u1"}},
+		{Emoji: "🎉", Count: 1, Users: []string{"u1"}},
+	}
+
+	t.Run("adding beyond the per-user cap is rejected before persisting", func(t *testing.T) {
+		toggled := false
+		svc := newLimitedReactionService(existing, 2, 2, 20, &toggled)
+		err := svc.ToggleReaction(context.Background(), "m1", "u1", "🔥")
+		if !errors.Is(err, pkg.ErrBadRequest) {
+			t.Errorf("expected ErrBadRequest, got %v", err)
+		}
+		if toggled {
+			t.Error("reaction was persisted despite exceeding the per-user cap")
+		}
+	})
+
+	t.Run("removing at the cap is allowed", func(t *testing.T) {
+		toggled := false
+		svc := newLimitedReactionService(existing, 2, 2, 20, &toggled)
+		if err := svc.ToggleReaction(context.Background(), "m1", "u1", "👍"); err != nil {
+			t.Errorf("removal at the cap should succeed, got %v", err)
+		}
+		if !toggled {
+			t.Error("removal toggle never reached the repository")
+		}
+	})
+}
+
+func TestToggleReaction_DistinctEmojiLimit(t *testing.T) {
+	existing := []models.ReactionGroup{
+		{Emoji: "👍", Count: 1, Users: []string{"other"}},
+		{Emoji: "🎉", Count: 1, Users: []string{"other"}},
+	}
+
+	t.Run("a new emoji beyond the message cap is rejected", func(t *testing.T) {
+		toggled := false
+		svc := newLimitedReactionService(existing, 0, 5, 2, &toggled)
+		err := svc.ToggleReaction(context.Background(), "m1", "u1", "🔥")
+		if !errors.Is(err, pkg.ErrBadRequest) {
+			t.Errorf("expected ErrBadRequest, got %v", err)
+		}
+		if toggled {
+			t.Error("reaction was persisted despite exceeding the distinct-emoji cap")
+		}
+	})
+
+	t.Run("joining an existing emoji group at the cap is allowed", func(t *testing.T) {
+		toggled := false
+		svc := newLimitedReactionService(existing, 0, 5, 2, &toggled)
+		if err := svc.ToggleReaction(context.Background(), "m1", "u1", "👍"); err != nil {
+			t.Errorf("reacting with an emoji already on the message should succeed, got %v", err)
+		}
+		if !toggled {
+			t.Error("toggle never reached the repository")
+		}
+	})
+}
+
+// selfReactionServerRepo serves a server whose only interesting property is the
+// self-reaction policy.
+type selfReactionServerRepo struct {
+	repository.ServerRepository
+	disallow bool
+}
+
+func (r selfReactionServerRepo) GetByID(_ context.Context, id string) (*models.Server, error) {
+	return &models.Server{ID: id, DisallowSelfReactions: r.disallow}, nil
+}
+
+func TestToggleReaction_SelfReactionPolicy(t *testing.T) {
+	build := func(disallow bool, existing []models.ReactionGroup, toggled *bool) ReactionService {
+		return NewReactionService(
+			&testutil.MockReactionRepo{
+				ToggleFn: func(_ context.Context, _, _, _ string) (bool, error) {
+					*toggled = true
+					return true, nil
+				},
+				GetByMessageIDFn: func(_ context.Context, _ string) ([]models.ReactionGroup, error) {
+					return existing, nil
+				},
+				CountUserReactionsFn: func(_ context.Context, _, _ string) (int, error) {
+					return len(existing), nil
+				},
+			},
+			&testutil.MockMessageRepo{
+				GetByIDFn: func(_ context.Context, _ string) (*models.Message, error) {
+					return &models.Message{ID: "m1", ChannelID: "ch1", UserID: "u1"}, nil
+				},
+			},
+			&testutil.MockChannelRepo{
+				GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+					return &models.Channel{ID: "ch1", ServerID: "s1"}, nil
+				},
+			},
+			selfReactionServerRepo{disallow: disallow},
+			&testutil.MockBroadcastAndOnline{},
+			&testutil.MockChannelPermResolver{
+				ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+					return models.PermAll, nil
+				},
+			},
+			&testutil.MockFileURLSigner{},
+			5, 20,
+		)
+	}
+
+	t.Run("author adding to their own message is rejected when disallowed", func(t *testing.T) {
+		toggled := false
+		svc := build(true, nil, &toggled)
+		err := svc.ToggleReaction(context.Background(), "m1", "u1", "👍")
+		if !errors.Is(err, pkg.ErrForbidden) {
+			t.Errorf("expected ErrForbidden, got %v", err)
+		}
+		if toggled {
+			t.Error("self-reaction was persisted despite the server policy")
+		}
+	})
+
+	t.Run("allowed by default", func(t *testing.T) {
+		toggled := false
+		svc := build(false, nil, &toggled)
+		if err := svc.ToggleReaction(context.Background(), "m1", "u1", "👍"); err != nil {
+			t.Errorf("self-reaction with the policy off should succeed, got %v", err)
+		}
+		if !toggled {
+			t.Error("toggle never reached the repository")
+		}
+	})
+
+	t.Run("removing an existing self-reaction always works", func(t *testing.T) {
+		toggled := false
+		existing := []models.ReactionGroup{{Emoji: "👍", Count: 1, Users: []string{"u1"}}}
+		svc := build(true, existing, &toggled)
+		if err := svc.ToggleReaction(context.Background(), "m1", "u1", "👍"); err != nil {
+			t.Errorf("removal must bypass the policy, got %v", err)
+		}
+		if !toggled {
+			t.Error("removal toggle never reached the repository")
+		}
+	})
+}
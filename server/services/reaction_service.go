@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 	"unicode/utf8"
 
 	"github.com/akinalp/mqvi/models"
@@ -17,30 +18,67 @@ const MaxEmojiLength = 32
 
 type ReactionService interface {
 	ToggleReaction(ctx context.Context, messageID, userID, emoji string) error
+	// ListReactors returns the detailed user list for one emoji on a message,
+	// paginated. The caller must be able to view and read the message's channel.
+	ListReactors(ctx context.Context, messageID, userID, emoji string, limit, offset int) (*models.ReactionUsersPage, error)
+	// SetTimeoutChecker wires the member-timeout gate consulted before a toggle
+	// adds a reaction. Nil-safe: unwired means timeouts are not enforced here.
+	SetTimeoutChecker(tc MemberTimeoutChecker)
 }
 
 type reactionService struct {
 	reactionRepo repository.ReactionRepository
 	messageRepo  repository.MessageRepository
 	channelRepo  repository.ChannelRepository
+	serverRepo   repository.ServerRepository
 	hub          ws.BroadcastAndOnline
 	permResolver ChannelPermResolver
+	urlSigner    FileURLSigner
+	// maxPerUser / maxDistinct mirror config.ReactionsConfig (see there for semantics).
+	maxPerUser  int
+	maxDistinct int
+
+	timeoutChecker MemberTimeoutChecker // optional — set post-construction via SetTimeoutChecker
 }
 
 func NewReactionService(
 	reactionRepo repository.ReactionRepository,
 	messageRepo repository.MessageRepository,
 	channelRepo repository.ChannelRepository,
+	serverRepo repository.ServerRepository,
 	hub ws.BroadcastAndOnline,
 	permResolver ChannelPermResolver,
+	urlSigner FileURLSigner,
+	maxPerUser int,
+	maxDistinct int,
 ) ReactionService {
 	return &reactionService{
 		reactionRepo: reactionRepo,
 		messageRepo:  messageRepo,
 		channelRepo:  channelRepo,
+		serverRepo:   serverRepo,
 		hub:          hub,
 		permResolver: permResolver,
+		urlSigner:    urlSigner,
+		maxPerUser:   maxPerUser,
+		maxDistinct:  maxDistinct,
+	}
+}
+
+func (s *reactionService) SetTimeoutChecker(tc MemberTimeoutChecker) {
+	s.timeoutChecker = tc
+}
+
+// reactionToggleAdds reports whether toggling emoji for userID would ADD a
+// reaction (vs remove an existing one), and whether that emoji would be new to
+// the message entirely. Removals bypass the limit checks.
+func reactionToggleAdds(groups []models.ReactionGroup, userID, emoji string) (adds, newEmoji bool) {
+	for _, g := range groups {
+		if g.Emoji == emoji {
+			return !slices.Contains(g.Users, userID), false
+		}
 	}
+	return true, true
 }
 
 // ToggleReaction adds or removes an emoji reaction on a message.
@@ -70,6 +108,60 @@ func (s *reactionService) ToggleReaction(ctx context.Context, messageID, userID,
 		return fmt.Errorf("%w: cannot react in this channel", pkg.ErrForbidden)
 	}
 
+	// Limit checks apply only when the toggle would add — removing is always allowed.
+	existing, err := s.reactionRepo.GetByMessageID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get reactions before toggle: %w", err)
+	}
+	if adds, newEmoji := reactionToggleAdds(existing, userID, emoji); adds {
+		// A moderation timeout blocks new reactions; removing an existing one
+		// stays allowed, same as the limit checks below.
+		if s.timeoutChecker != nil {
+			channel, chErr := s.channelRepo.GetByID(ctx, message.ChannelID)
+			if chErr != nil {
+				return chErr
+			}
+			if channel == nil {
+				return fmt.Errorf("%w: channel not found", pkg.ErrNotFound)
+			}
+			timedOut, toErr := s.timeoutChecker.IsTimedOut(ctx, channel.ServerID, userID)
+			if toErr != nil {
+				return fmt.Errorf("failed to check member timeout: %w", toErr)
+			}
+			if timedOut {
+				return fmt.Errorf("%w: you are timed out in this server", pkg.ErrForbidden)
+			}
+		}
+		// The author reacting to their own message is a per-server policy, not an
+		// instance rule — DMs have no server and are never subject to it.
+		if message.UserID == userID {
+			channel, chErr := s.channelRepo.GetByID(ctx, message.ChannelID)
+			if chErr != nil {
+				return chErr
+			}
+			if channel == nil {
+				return fmt.Errorf("%w: channel not found", pkg.ErrNotFound)
+			}
+			server, srvErr := s.serverRepo.GetByID(ctx, channel.ServerID)
+			if srvErr != nil {
+				return fmt.Errorf("failed to check server reaction policy: %w", srvErr)
+			}
+			if server.DisallowSelfReactions {
+				return fmt.Errorf("%w: this server does not allow reacting to your own messages", pkg.ErrForbidden)
+			}
+		}
+		if newEmoji && len(existing) >= s.maxDistinct {
+			return fmt.Errorf("%w: this message has reached its limit of %d different reactions", pkg.ErrBadRequest, s.maxDistinct)
+		}
+		userCount, countErr := s.reactionRepo.CountUserReactions(ctx, messageID, userID)
+		if countErr != nil {
+			return fmt.Errorf("failed to count user reactions: %w", countErr)
+		}
+		if userCount >= s.maxPerUser {
+			return fmt.Errorf("%w: you can add at most %d reactions to one message", pkg.ErrBadRequest, s.maxPerUser)
+		}
+	}
+
 	added, err := s.reactionRepo.Toggle(ctx, messageID, userID, emoji)
 	if err != nil {
 		return fmt.Errorf("failed to toggle reaction: %w", err)
@@ -86,7 +178,7 @@ func (s *reactionService) ToggleReaction(ctx context.Context, messageID, userID,
 		Data: map[string]any{
 			"message_id":        messageID,
 			"channel_id":        message.ChannelID,
-			"reactions":         reactions,
+			"reactions":         models.PreviewReactions(reactions),
 			"actor_id":          userID,
 			"message_author_id": message.UserID,
 			"added":             added,
@@ -100,17 +192,39 @@ func (s *reactionService) ToggleReaction(ctx context.Context, messageID, userID,
 	}
 
 	onlineUsers := s.hub.GetOnlineUserIDsForServer(channel.ServerID)
-	var allowed []string
-	for _, uid := range onlineUsers {
-		perms, permErr := s.permResolver.ResolveChannelPermissions(ctx, uid, message.ChannelID)
-		if permErr != nil {
-			continue
-		}
-		if perms.Has(models.PermViewChannel) && perms.Has(models.PermReadMessages) {
-			allowed = append(allowed, uid)
-		}
-	}
-	s.hub.BroadcastToUsers(allowed, event)
+	s.hub.BroadcastToUsers(s.permResolver.AllowedViewers(ctx, message.ChannelID, onlineUsers), event)
 
 	return nil
 }
+
+// ListReactors serves the detail view behind the trimmed previews.
+func (s *reactionService) ListReactors(ctx context.Context, messageID, userID, emoji string, limit, offset int) (*models.ReactionUsersPage, error) {
+	if emoji == "" {
+		return nil, fmt.Errorf("%w: emoji is required", pkg.ErrBadRequest)
+	}
+
+	message, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same gate as ToggleReaction: resolving against the message's own channel
+	// keeps cross-server message IDs unreadable.
+	actorPerms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, message.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if !actorPerms.Has(models.PermViewChannel) || !actorPerms.Has(models.PermReadMessages) {
+		return nil, fmt.Errorf("%w: cannot view this channel", pkg.ErrForbidden)
+	}
+
+	users, total, err := s.reactionRepo.ListReactors(ctx, messageID, emoji, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reactors: %w", err)
+	}
+	for i := range users {
+		users[i].AvatarURL = s.urlSigner.SignURLPtr(users[i].AvatarURL)
+	}
+
+	return &models.ReactionUsersPage{Users: users, Total: total}, nil
+}
@@ -11,26 +11,38 @@ import (
 )
 
 // ReadStateService handles unread message tracking.
-// GetUnreadCounts is server-scoped — returns unread counts for channels in a given server.
+// GetUnreadCounts is server-scoped — returns unread and mention counts for
+// channels in a given server, plus the server-wide mention total.
 type ReadStateService interface {
 	MarkRead(ctx context.Context, userID, channelID, messageID string) error
-	GetUnreadCounts(ctx context.Context, userID, serverID string) ([]models.UnreadInfo, error)
+	GetUnreadCounts(ctx context.Context, userID, serverID string) (*models.UnreadSummary, error)
+	GetAllUnreadCounts(ctx context.Context, userID string) ([]models.ServerUnreadInfo, error)
 	MarkAllRead(ctx context.Context, userID, serverID string) error
 	MarkMentionSeen(ctx context.Context, userID, channelID, mentionMessageID string) error
 }
 
+// NotificationMuteResolver flags channels whose notifications the user muted — directly,
+// via category, or via server — so mention badges can be suppressed without touching the
+// unread dot (ISP — NotificationService implements it).
+type NotificationMuteResolver interface {
+	EffectiveMutedChannelIDs(ctx context.Context, userID, serverID string) (map[string]bool, error)
+}
+
 type readStateService struct {
 	readStateRepo repository.ReadStateRepository
 	permResolver  ChannelPermResolver
+	muteResolver  NotificationMuteResolver
 }
 
 func NewReadStateService(
 	readStateRepo repository.ReadStateRepository,
 	permResolver ChannelPermResolver,
+	muteResolver NotificationMuteResolver,
 ) ReadStateService {
 	return &readStateService{
 		readStateRepo: readStateRepo,
 		permResolver:  permResolver,
+		muteResolver:  muteResolver,
 	}
 }
 
@@ -52,20 +64,74 @@ func (s *readStateService) MarkMentionSeen(ctx context.Context, userID, channelI
 	return s.readStateRepo.SetMentionSeen(ctx, userID, channelID, mentionMessageID)
 }
 
-func (s *readStateService) GetUnreadCounts(ctx context.Context, userID, serverID string) ([]models.UnreadInfo, error) {
+func (s *readStateService) GetUnreadCounts(ctx context.Context, userID, serverID string) (*models.UnreadSummary, error) {
 	all, err := s.readStateRepo.GetUnreadCounts(ctx, userID, serverID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter to channels where user has ViewChannel AND ReadMessages
-	filtered := make([]models.UnreadInfo, 0, len(all))
+	// Effective mute state rides along so the client can keep the unread dot but drop
+	// the mention badge. Non-fatal: a failed lookup means no suppression, not no unreads.
+	var mutedChannels map[string]bool
+	if s.muteResolver != nil {
+		if m, err := s.muteResolver.EffectiveMutedChannelIDs(ctx, userID, serverID); err == nil {
+			mutedChannels = m
+		}
+	}
+
+	// Filter to channels where user has ViewChannel AND ReadMessages. The total
+	// only sums unmuted channels — a muted channel's mentions stay visible in
+	// its own row but must not light the server badge.
+	summary := &models.UnreadSummary{Channels: make([]models.UnreadInfo, 0, len(all))}
+	for _, info := range all {
+		perms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, info.ChannelID)
+		if err != nil {
+			continue
+		}
+		if perms.Has(models.PermViewChannel) && perms.Has(models.PermReadMessages) {
+			info.NotificationsMuted = mutedChannels[info.ChannelID]
+			if !info.NotificationsMuted {
+				summary.TotalMentions += info.MentionCount
+			}
+			summary.Channels = append(summary.Channels, info)
+		}
+	}
+
+	return summary, nil
+}
+
+// GetAllUnreadCounts is the cross-server snapshot behind GET /api/unreads. Same
+// visibility and mute treatment as GetUnreadCounts, applied over one aggregate query
+// instead of a per-server round trip.
+func (s *readStateService) GetAllUnreadCounts(ctx context.Context, userID string) ([]models.ServerUnreadInfo, error) {
+	all, err := s.readStateRepo.GetAllUnreadCounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// One mute lookup per distinct server in the result set (not per channel).
+	mutedByServer := make(map[string]map[string]bool)
+	if s.muteResolver != nil {
+		for _, info := range all {
+			if _, done := mutedByServer[info.ServerID]; done {
+				continue
+			}
+			m, err := s.muteResolver.EffectiveMutedChannelIDs(ctx, userID, info.ServerID)
+			if err != nil {
+				m = nil // non-fatal: no suppression, not no unreads
+			}
+			mutedByServer[info.ServerID] = m
+		}
+	}
+
+	filtered := make([]models.ServerUnreadInfo, 0, len(all))
 	for _, info := range all {
 		perms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, info.ChannelID)
 		if err != nil {
 			continue
 		}
 		if perms.Has(models.PermViewChannel) && perms.Has(models.PermReadMessages) {
+			info.NotificationsMuted = mutedByServer[info.ServerID][info.ChannelID]
 			filtered = append(filtered, info)
 		}
 	}
@@ -0,0 +1,265 @@
+// Package services — voice channel recording via the LiveKit Egress API.
+//
+// A recording is a server-side room composite egress: the LiveKit egress
+// worker joins the room, mixes every participant, and writes one file to its
+// own output storage. Active recordings live only in memory (one per channel);
+// a row is persisted when the egress is stopped. Recording requires a
+// self-hosted LiveKit instance — the platform-managed boxes run bare
+// livekit-server with no egress worker deployed.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/ws"
+
+	"github.com/google/uuid"
+	livekit "github.com/livekit/protocol/livekit"
+	lksdk "github.com/livekit/server-sdk-go/v2"
+)
+
+type RecordingService interface {
+	// Start begins a room composite recording of the channel. One recording per
+	// channel at a time.
+	Start(ctx context.Context, channelID, userID string) error
+	// Stop ends the channel's active recording and returns the persisted metadata.
+	Stop(ctx context.Context, channelID, userID string) (*models.VoiceRecording, error)
+	// GetActiveRecording returns the in-progress recording state for a channel,
+	// or nil when nothing is being recorded.
+	GetActiveRecording(channelID string) *models.VoiceRecording
+}
+
+// egressAPI is the slice of lksdk.EgressClient the service calls; tests stub it.
+type egressAPI interface {
+	StartRoomCompositeEgress(ctx context.Context, req *livekit.RoomCompositeEgressRequest) (*livekit.EgressInfo, error)
+	StopEgress(ctx context.Context, req *livekit.StopEgressRequest) (*livekit.EgressInfo, error)
+}
+
+// activeRecording is the in-memory state of a running egress.
+type activeRecording struct {
+	egressID  string
+	serverID  string
+	startedBy string
+	startedAt time.Time
+}
+
+type recordingService struct {
+	channelGetter ChannelGetter
+	livekitGetter LiveKitInstanceGetter
+	keychain      CredentialDecrypter
+	recordingRepo repository.VoiceRecordingRepository
+	hub           ws.Broadcaster
+
+	// newEgressClient builds the client from decrypted instance credentials.
+	// A field rather than a call so tests can substitute a stub egress.
+	newEgressClient func(url, apiKey, apiSecret string) egressAPI
+
+	mu     sync.Mutex
+	active map[string]*activeRecording // channelID -> running egress
+}
+
+// CredentialDecrypter decrypts stored LiveKit credentials (ISP — satisfied by
+// *crypto.Keychain, stubbed in tests).
+type CredentialDecrypter interface {
+	Decrypt(ciphertext string) (string, error)
+}
+
+func NewRecordingService(
+	channelGetter ChannelGetter,
+	livekitGetter LiveKitInstanceGetter,
+	keychain CredentialDecrypter,
+	recordingRepo repository.VoiceRecordingRepository,
+	hub ws.Broadcaster,
+) RecordingService {
+	return &recordingService{
+		channelGetter: channelGetter,
+		livekitGetter: livekitGetter,
+		keychain:      keychain,
+		recordingRepo: recordingRepo,
+		hub:           hub,
+		newEgressClient: func(url, apiKey, apiSecret string) egressAPI {
+			return lksdk.NewEgressClient(url, apiKey, apiSecret)
+		},
+		active: make(map[string]*activeRecording),
+	}
+}
+
+// egressClientFor resolves the channel's LiveKit instance, rejects instances
+// that cannot run an egress, and returns a client built from the decrypted
+// credentials (same decrypt path as voiceService.GenerateToken).
+func (s *recordingService) egressClientFor(ctx context.Context, serverID string) (egressAPI, error) {
+	lkInstance, err := s.livekitGetter.GetByServerID(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("livekit instance lookup for server %s: %w", serverID, err)
+	}
+	if lkInstance.IsPlatformManaged {
+		return nil, fmt.Errorf("%w: recording requires a self-hosted LiveKit instance with an egress service; platform-managed instances do not run one", pkg.ErrBadRequest)
+	}
+	apiKey, err := s.keychain.Decrypt(lkInstance.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("api key decrypt: %w", err)
+	}
+	apiSecret, err := s.keychain.Decrypt(lkInstance.APISecret)
+	if err != nil {
+		return nil, fmt.Errorf("api secret decrypt: %w", err)
+	}
+	return s.newEgressClient(lkInstance.URL, apiKey, apiSecret), nil
+}
+
+func (s *recordingService) Start(ctx context.Context, channelID, userID string) error {
+	channel, err := s.channelGetter.GetByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if channel.Type != models.ChannelTypeVoice {
+		return fmt.Errorf("%w: not a voice channel", pkg.ErrBadRequest)
+	}
+
+	s.mu.Lock()
+	if _, running := s.active[channelID]; running {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: this channel is already being recorded", pkg.ErrBadRequest)
+	}
+	s.mu.Unlock()
+
+	client, err := s.egressClientFor(ctx, channel.ServerID)
+	if err != nil {
+		return err
+	}
+
+	roomName := channel.ServerID + ":" + channelID
+	info, err := client.StartRoomCompositeEgress(ctx, &livekit.RoomCompositeEgressRequest{
+		RoomName:  roomName,
+		AudioOnly: true,
+		FileOutputs: []*livekit.EncodedFileOutput{{
+			// Filepath is relative to the egress service's configured output
+			// (local dir or bucket) — deliberately not a path on this server.
+			Filepath: "recordings/" + roomName + "-{time}",
+		}},
+	})
+	if err != nil {
+		return egressError(err)
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	// Re-check under lock: a concurrent Start may have won the race while the
+	// egress call was in flight. Keep the first, stop the loser.
+	if _, running := s.active[channelID]; running {
+		s.mu.Unlock()
+		stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, stopErr := client.StopEgress(stopCtx, &livekit.StopEgressRequest{EgressId: info.EgressId}); stopErr != nil {
+			log.Printf("[recording] failed to stop duplicate egress %s: %v", info.EgressId, stopErr)
+		}
+		return fmt.Errorf("%w: this channel is already being recorded", pkg.ErrBadRequest)
+	}
+	s.active[channelID] = &activeRecording{
+		egressID:  info.EgressId,
+		serverID:  channel.ServerID,
+		startedBy: userID,
+		startedAt: now,
+	}
+	s.mu.Unlock()
+
+	s.broadcastState(channel.ServerID, channelID, userID, now, true)
+	log.Printf("[recording] started egress %s for channel %s by user %s", info.EgressId, channelID, userID)
+	return nil
+}
+
+func (s *recordingService) Stop(ctx context.Context, channelID, userID string) (*models.VoiceRecording, error) {
+	s.mu.Lock()
+	rec, running := s.active[channelID]
+	if !running {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: no active recording for this channel", pkg.ErrNotFound)
+	}
+	delete(s.active, channelID)
+	s.mu.Unlock()
+
+	ended := time.Now()
+	recording := &models.VoiceRecording{
+		ID:              uuid.New().String(),
+		ServerID:        rec.serverID,
+		ChannelID:       channelID,
+		EgressID:        rec.egressID,
+		DurationSeconds: int(ended.Sub(rec.startedAt).Seconds()),
+		StartedBy:       rec.startedBy,
+		StartedAt:       rec.startedAt,
+		EndedAt:         &ended,
+	}
+
+	client, err := s.egressClientFor(ctx, rec.serverID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := client.StopEgress(ctx, &livekit.StopEgressRequest{EgressId: rec.egressID})
+	if err != nil {
+		// The egress may already be gone (worker restart, room closed). The
+		// indicator must still clear and the attempt still leaves a record.
+		log.Printf("[recording] stop egress %s failed: %v", rec.egressID, err)
+	} else if len(info.FileResults) > 0 && info.FileResults[0].Location != "" {
+		recording.FileURL = &info.FileResults[0].Location
+	} else if len(info.FileResults) > 0 && info.FileResults[0].Filename != "" {
+		recording.FileURL = &info.FileResults[0].Filename
+	}
+
+	if err := s.recordingRepo.Create(ctx, recording); err != nil {
+		return nil, fmt.Errorf("failed to persist recording metadata: %w", err)
+	}
+
+	s.broadcastState(rec.serverID, channelID, rec.startedBy, rec.startedAt, false)
+	log.Printf("[recording] stopped egress %s for channel %s by user %s (%ds)", rec.egressID, channelID, userID, recording.DurationSeconds)
+	return recording, nil
+}
+
+func (s *recordingService) GetActiveRecording(channelID string) *models.VoiceRecording {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.active[channelID]
+	if !ok {
+		return nil
+	}
+	return &models.VoiceRecording{
+		ServerID:  rec.serverID,
+		ChannelID: channelID,
+		EgressID:  rec.egressID,
+		StartedBy: rec.startedBy,
+		StartedAt: rec.startedAt,
+	}
+}
+
+func (s *recordingService) broadcastState(serverID, channelID, startedBy string, startedAt time.Time, active bool) {
+	data := ws.VoiceRecordingStateBroadcast{
+		ChannelID: channelID,
+		ServerID:  serverID,
+		Active:    active,
+		StartedBy: startedBy,
+	}
+	if active {
+		data.StartedAt = startedAt.UnixMilli()
+	}
+	s.hub.BroadcastToServer(serverID, ws.Event{
+		Op:   ws.OpVoiceRecordingState,
+		Data: data,
+	})
+}
+
+// egressError turns the opaque twirp transport errors a missing egress service
+// produces into something a user can act on.
+func egressError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "unavailable") || strings.Contains(msg, "not_found") ||
+		strings.Contains(msg, "connection refused") || strings.Contains(msg, "unimplemented") {
+		return fmt.Errorf("%w: the LiveKit instance has no egress service configured", pkg.ErrBadRequest)
+	}
+	return fmt.Errorf("failed to start recording: %w", err)
+}
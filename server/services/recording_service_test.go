@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+
+	livekit "github.com/livekit/protocol/livekit"
+)
+
+// stubEgress fakes the LiveKit egress API; startErr simulates an instance
+// with no egress service behind it.
+type stubEgress struct {
+	startErr error
+	started  []*livekit.RoomCompositeEgressRequest
+	stopped  []string
+	stopInfo *livekit.EgressInfo
+}
+
+func (e *stubEgress) StartRoomCompositeEgress(_ context.Context, req *livekit.RoomCompositeEgressRequest) (*livekit.EgressInfo, error) {
+	if e.startErr != nil {
+		return nil, e.startErr
+	}
+	e.started = append(e.started, req)
+	return &livekit.EgressInfo{EgressId: "eg1"}, nil
+}
+
+func (e *stubEgress) StopEgress(_ context.Context, req *livekit.StopEgressRequest) (*livekit.EgressInfo, error) {
+	e.stopped = append(e.stopped, req.EgressId)
+	if e.stopInfo != nil {
+		return e.stopInfo, nil
+	}
+	return &livekit.EgressInfo{EgressId: req.EgressId}, nil
+}
+
+// plainDecrypter passes credentials through untouched.
+type plainDecrypter struct{}
+
+func (plainDecrypter) Decrypt(s string) (string, error) { return s, nil }
+
+type stubInstanceGetter struct{ platformManaged bool }
+
+func (s stubInstanceGetter) GetByServerID(_ context.Context, _ string) (*models.LiveKitInstance, error) {
+	return &models.LiveKitInstance{ID: "lk1", URL: "wss://lk", IsPlatformManaged: s.platformManaged}, nil
+}
+
+// memRecordingRepo captures persisted recordings.
+type memRecordingRepo struct {
+	repository.VoiceRecordingRepository
+	created []*models.VoiceRecording
+}
+
+func (m *memRecordingRepo) Create(_ context.Context, rec *models.VoiceRecording) error {
+	m.created = append(m.created, rec)
+	return nil
+}
+
+func newTestRecordingService(t *testing.T, egress *stubEgress, platformManaged bool) (*recordingService, *memRecordingRepo, *[]ws.Event) {
+	t.Helper()
+	repo := &memRecordingRepo{}
+	var events []ws.Event
+	hub := &testutil.MockBroadcaster{
+		BroadcastToServerFn: func(_ string, event ws.Event) { events = append(events, event) },
+	}
+	svc := NewRecordingService(
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "s1", Type: models.ChannelTypeVoice}, nil
+			},
+		},
+		stubInstanceGetter{platformManaged: platformManaged},
+		plainDecrypter{},
+		repo,
+		hub,
+	).(*recordingService)
+	svc.newEgressClient = func(_, _, _ string) egressAPI { return egress }
+	return svc, repo, &events
+}
+
+func TestRecording_StartStopRoundTrip(t *testing.T) {
+	egress := &stubEgress{stopInfo: &livekit.EgressInfo{
+		EgressId:    "eg1",
+		FileResults: []*livekit.FileInfo{{Location: "s3://bucket/rec.ogg"}},
+	}}
+	svc, repo, events := newTestRecordingService(t, egress, false)
+	ctx := context.Background()
+
+	if err := svc.Start(ctx, "vc1", "u1"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if len(egress.started) != 1 || egress.started[0].RoomName != "s1:vc1" {
+		t.Fatalf("egress started with %+v, want room s1:vc1", egress.started)
+	}
+	if active := svc.GetActiveRecording("vc1"); active == nil || active.StartedBy != "u1" {
+		t.Fatalf("expected an active recording by u1, got %+v", active)
+	}
+
+	// A second start while one is running must not spawn a second egress.
+	if err := svc.Start(ctx, "vc1", "u2"); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("double start: got %v, want ErrBadRequest", err)
+	}
+	if len(egress.started) != 1 {
+		t.Errorf("double start reached the egress API: %d starts", len(egress.started))
+	}
+
+	rec, err := svc.Stop(ctx, "vc1", "u1")
+	if err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if len(egress.stopped) != 1 || egress.stopped[0] != "eg1" {
+		t.Fatalf("expected egress eg1 stopped, got %v", egress.stopped)
+	}
+	if rec.FileURL == nil || *rec.FileURL != "s3://bucket/rec.ogg" {
+		t.Errorf("file URL = %v, want the egress-reported location", rec.FileURL)
+	}
+	if rec.StartedBy != "u1" || rec.EgressID != "eg1" {
+		t.Errorf("persisted metadata wrong: %+v", rec)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("expected 1 persisted recording, got %d", len(repo.created))
+	}
+	if svc.GetActiveRecording("vc1") != nil {
+		t.Error("recording still active after stop")
+	}
+
+	// One start broadcast, one stop broadcast, both on the recording op.
+	if len(*events) != 2 {
+		t.Fatalf("expected 2 broadcasts, got %d", len(*events))
+	}
+	for i, want := range []bool{true, false} {
+		ev := (*events)[i]
+		if ev.Op != ws.OpVoiceRecordingState {
+			t.Errorf("broadcast %d op = %q", i, ev.Op)
+		}
+		if data := ev.Data.(ws.VoiceRecordingStateBroadcast); data.Active != want {
+			t.Errorf("broadcast %d active = %v, want %v", i, data.Active, want)
+		}
+	}
+}
+
+func TestRecording_PlatformManagedInstanceRefused(t *testing.T) {
+	egress := &stubEgress{}
+	svc, _, _ := newTestRecordingService(t, egress, true)
+
+	err := svc.Start(context.Background(), "vc1", "u1")
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("got %v, want ErrBadRequest", err)
+	}
+	if len(egress.started) != 0 {
+		t.Error("egress API was called for a platform-managed instance")
+	}
+}
+
+func TestRecording_EgressNotConfigured(t *testing.T) {
+	egress := &stubEgress{startErr: fmt.Errorf("twirp error unavailable: connection refused")}
+	svc, _, _ := newTestRecordingService(t, egress, false)
+
+	err := svc.Start(context.Background(), "vc1", "u1")
+	if !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("got %v, want ErrBadRequest with a clear egress message", err)
+	}
+	if svc.GetActiveRecording("vc1") != nil {
+		t.Error("failed start left an active recording behind")
+	}
+}
+
+func TestRecording_StopWithoutActive(t *testing.T) {
+	svc, _, _ := newTestRecordingService(t, &stubEgress{}, false)
+
+	if _, err := svc.Stop(context.Background(), "vc1", "u1"); !errors.Is(err, pkg.ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+}
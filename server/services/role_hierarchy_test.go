@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// Hierarchy gate (canManageRole) regression tests: equal position is the edge
+// that must deny — ">= actor's highest" — and the owner bypasses entirely.
+
+func hierarchyRoleSvc(actorRoles []models.Role, target *models.Role, mutated *bool) RoleService {
+	return NewRoleService(
+		&testutil.MockRoleRepo{
+			GetByIDFn: func(_ context.Context, _ string) (*models.Role, error) {
+				return target, nil
+			},
+			GetByUserIDAndServerFn: func(_ context.Context, _, _ string) ([]models.Role, error) {
+				return actorRoles, nil
+			},
+			UpdateFn: func(_ context.Context, _ *models.Role) error {
+				*mutated = true
+				return nil
+			},
+			DeleteFn: func(_ context.Context, _ string) error {
+				*mutated = true
+				return nil
+			},
+		},
+		&testutil.MockUserRepo{},
+		&testutil.MockBroadcaster{},
+	)
+}
+
+func TestRoleUpdate_EqualPositionDenied(t *testing.T) {
+	mutated := false
+	svc := hierarchyRoleSvc(
+		[]models.Role{{ID: "mod", Position: 5}},
+		&models.Role{ID: "role-1", ServerID: "srv-1", Position: 5},
+		&mutated,
+	)
+
+	name := "renamed"
+	_, err := svc.Update(context.Background(), "srv-1", "actor", "role-1",
+		&models.UpdateRoleRequest{Name: &name})
+	if !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("equal-position role update should be forbidden, got %v", err)
+	}
+	if mutated {
+		t.Error("role must NOT be updated when the hierarchy check fails")
+	}
+}
+
+func TestRoleDelete_EqualPositionDenied(t *testing.T) {
+	mutated := false
+	svc := hierarchyRoleSvc(
+		[]models.Role{{ID: "mod", Position: 3}},
+		&models.Role{ID: "role-1", ServerID: "srv-1", Position: 3},
+		&mutated,
+	)
+
+	err := svc.Delete(context.Background(), "srv-1", "actor", "role-1")
+	if !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("equal-position role delete should be forbidden, got %v", err)
+	}
+	if mutated {
+		t.Error("role must NOT be deleted when the hierarchy check fails")
+	}
+}
+
+func TestRoleManage_StrictlyHigherAllowed(t *testing.T) {
+	mutated := false
+	svc := hierarchyRoleSvc(
+		[]models.Role{{ID: "admin", Position: 6}},
+		&models.Role{ID: "role-1", ServerID: "srv-1", Position: 5},
+		&mutated,
+	)
+
+	name := "renamed"
+	if _, err := svc.Update(context.Background(), "srv-1", "actor", "role-1",
+		&models.UpdateRoleRequest{Name: &name}); err != nil {
+		t.Fatalf("strictly-higher actor should pass the hierarchy gate, got %v", err)
+	}
+	if !mutated {
+		t.Error("update should have reached the repo")
+	}
+}
+
+func TestRoleManage_OwnerBypassesHierarchy(t *testing.T) {
+	mutated := false
+	svc := hierarchyRoleSvc(
+		// Owner's literal position is irrelevant — HighestPosition treats IsOwner as top.
+		[]models.Role{{ID: "owner", IsOwner: true, Position: 0}},
+		&models.Role{ID: "role-1", ServerID: "srv-1", Position: 100},
+		&mutated,
+	)
+
+	if err := svc.Delete(context.Background(), "srv-1", "actor", "role-1"); err != nil {
+		t.Fatalf("owner should bypass the hierarchy gate, got %v", err)
+	}
+	if !mutated {
+		t.Error("delete should have reached the repo")
+	}
+}
@@ -27,6 +27,8 @@ type RoleService interface {
 	SetVoiceEnforcer(enforcer VoiceServerPermissionEnforcer)
 	// SetPermCacheInvalidator wires the permission-cache invalidator post-construction.
 	SetPermCacheInvalidator(inv PermissionCacheInvalidator)
+	// SetAuditRecorder wires the audit log post-construction.
+	SetAuditRecorder(rec AuditRecorder)
 }
 
 type roleService struct {
@@ -35,6 +37,7 @@ type roleService struct {
 	hub            ws.Broadcaster
 	voiceEnforcer  VoiceServerPermissionEnforcer // set post-construction, may be nil
 	permInvalidator PermissionCacheInvalidator   // set post-construction, may be nil
+	audit           AuditRecorder                // set post-construction, may be nil
 }
 
 func NewRoleService(
@@ -57,6 +60,10 @@ func (s *roleService) SetPermCacheInvalidator(inv PermissionCacheInvalidator) {
 	s.permInvalidator = inv
 }
 
+func (s *roleService) SetAuditRecorder(rec AuditRecorder) {
+	s.audit = rec
+}
+
 // invalidatePermCache flushes cached permissions after a role's bits change or it is
 // deleted (affects every holder). No-op if unwired.
 func (s *roleService) invalidatePermCache() {
@@ -138,6 +145,11 @@ func (s *roleService) Create(ctx context.Context, serverID, actorID string, req
 		Data: role,
 	})
 
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionRoleCreate, role.ID,
+			map[string]string{"name": role.Name})
+	}
+
 	return role, nil
 }
 
@@ -186,15 +198,10 @@ func (s *roleService) Update(ctx context.Context, serverID, actorID, roleID stri
 		return role, nil
 	}
 
-	actorMaxPos, err := s.getActorMaxPosition(ctx, actorID, serverID)
-	if err != nil {
+	if err := s.canManageRole(ctx, actorID, serverID, role.Position); err != nil {
 		return nil, err
 	}
 
-	if role.Position >= actorMaxPos {
-		return nil, fmt.Errorf("%w: cannot modify a role with equal or higher position", pkg.ErrForbidden)
-	}
-
 	if req.Permissions != nil {
 		actorPerms, permErr := s.getActorEffectivePermissions(ctx, actorID, serverID)
 		if permErr != nil {
@@ -240,6 +247,11 @@ func (s *roleService) Update(ctx context.Context, serverID, actorID, roleID stri
 		Data: role,
 	})
 
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionRoleUpdate, role.ID,
+			map[string]string{"name": role.Name})
+	}
+
 	return role, nil
 }
 
@@ -261,15 +273,10 @@ func (s *roleService) Delete(ctx context.Context, serverID, actorID, roleID stri
 		return fmt.Errorf("%w: cannot delete the default role", pkg.ErrBadRequest)
 	}
 
-	actorMaxPos, err := s.getActorMaxPosition(ctx, actorID, serverID)
-	if err != nil {
+	if err := s.canManageRole(ctx, actorID, serverID, role.Position); err != nil {
 		return err
 	}
 
-	if role.Position >= actorMaxPos {
-		return fmt.Errorf("%w: cannot delete a role with equal or higher position", pkg.ErrForbidden)
-	}
-
 	if err := s.roleRepo.Delete(ctx, roleID); err != nil {
 		return fmt.Errorf("failed to delete role: %w", err)
 	}
@@ -284,6 +291,11 @@ func (s *roleService) Delete(ctx context.Context, serverID, actorID, roleID stri
 		Data: map[string]string{"id": roleID},
 	})
 
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionRoleDelete, roleID,
+			map[string]string{"name": role.Name})
+	}
+
 	return nil
 }
 
@@ -346,6 +358,20 @@ func (s *roleService) ReorderRoles(ctx context.Context, serverID, actorID string
 	return roles, nil
 }
 
+// canManageRole is the shared hierarchy gate for role management: the actor's
+// highest role must strictly outrank the role position being touched. The server
+// owner bypasses it — HighestPosition reports MaxInt32 for the owner role.
+func (s *roleService) canManageRole(ctx context.Context, actorID, serverID string, targetRolePosition int) error {
+	actorMaxPos, err := s.getActorMaxPosition(ctx, actorID, serverID)
+	if err != nil {
+		return err
+	}
+	if targetRolePosition >= actorMaxPos {
+		return fmt.Errorf("%w: cannot manage a role with equal or higher position than your own", pkg.ErrForbidden)
+	}
+	return nil
+}
+
 func (s *roleService) getActorMaxPosition(ctx context.Context, actorID, serverID string) (int, error) {
 	actorRoles, err := s.roleRepo.GetByUserIDAndServer(ctx, actorID, serverID)
 	if err != nil {
@@ -0,0 +1,238 @@
+// Package services — SchedulerService: messages queued for future delivery.
+//
+// A 30-second poller picks up due rows and posts them through the normal
+// message-create path, so scheduled sends get the same validation, permission
+// resolution, mentions, and broadcasts as a live send. Permissions are checked
+// twice: at schedule time (so the queue can't hold posts the author may not
+// make) and again implicitly at send time by the create path — a permission
+// revoked in between drops the post instead of leaking it.
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+
+	"github.com/google/uuid"
+)
+
+// schedulerPollInterval is how often the poller looks for due messages; a
+// scheduled message fires at most this long after its send_at.
+const schedulerPollInterval = 30 * time.Second
+
+// schedulerBatchSize caps how many due messages one poll delivers.
+const schedulerBatchSize = 50
+
+// SchedulerService queues channel messages and delivers them when due.
+type SchedulerService interface {
+	Schedule(ctx context.Context, serverID, channelID, userID string, req *models.ScheduleMessageRequest) (*models.ScheduledMessage, error)
+	// ListPending returns the caller's own queue for a channel — scheduled
+	// messages are private to their author until they fire.
+	ListPending(ctx context.Context, serverID, channelID, userID string) ([]models.ScheduledMessage, error)
+	Cancel(ctx context.Context, serverID, channelID, userID, id string) error
+	// Start launches the delivery poller goroutine. Idempotent.
+	Start()
+	// Stop signals the poller to exit and waits for an in-flight batch to
+	// finish. Safe to call before Start (no-op).
+	Stop()
+	// RunOnce delivers every currently due message synchronously. Used by the
+	// poller and exposed for tests.
+	RunOnce(ctx context.Context) error
+}
+
+// ScheduledSender is the slice of MessageService the scheduler posts through
+// (ISP) — the full create path, permission re-check included.
+type ScheduledSender interface {
+	Create(ctx context.Context, channelID string, userID string, req *models.CreateMessageRequest) (*models.Message, error)
+}
+
+type schedulerService struct {
+	scheduleRepo repository.ScheduledMessageRepository
+	channelRepo  repository.ChannelRepository
+	permResolver ChannelPermResolver
+	sender       ScheduledSender
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewSchedulerService(
+	scheduleRepo repository.ScheduledMessageRepository,
+	channelRepo repository.ChannelRepository,
+	permResolver ChannelPermResolver,
+	sender ScheduledSender,
+) SchedulerService {
+	return &schedulerService{
+		scheduleRepo: scheduleRepo,
+		channelRepo:  channelRepo,
+		permResolver: permResolver,
+		sender:       sender,
+	}
+}
+
+func (s *schedulerService) Schedule(ctx context.Context, serverID, channelID, userID string, req *models.ScheduleMessageRequest) (*models.ScheduledMessage, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	channel, err := s.verifyChannel(ctx, serverID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Type != models.ChannelTypeText {
+		return nil, fmt.Errorf("%w: messages can only be scheduled in text channels", pkg.ErrBadRequest)
+	}
+
+	// Channel-level check on top of the route's server-level gate — an
+	// override can deny SendMessages in this one channel.
+	perms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve channel permissions: %w", err)
+	}
+	if !perms.Has(models.PermSendMessages) {
+		return nil, fmt.Errorf("%w: no permission to send messages in this channel", pkg.ErrForbidden)
+	}
+
+	msg := &models.ScheduledMessage{
+		ID:        uuid.New().String(),
+		ChannelID: channelID,
+		UserID:    userID,
+		Content:   req.Content,
+		SendAt:    req.SendAt.UTC(),
+	}
+
+	if err := s.scheduleRepo.Create(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func (s *schedulerService) ListPending(ctx context.Context, serverID, channelID, userID string) ([]models.ScheduledMessage, error) {
+	if _, err := s.verifyChannel(ctx, serverID, channelID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.scheduleRepo.GetPendingByChannelAndUser(ctx, channelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		messages = []models.ScheduledMessage{}
+	}
+	return messages, nil
+}
+
+func (s *schedulerService) Cancel(ctx context.Context, serverID, channelID, userID, id string) error {
+	if _, err := s.verifyChannel(ctx, serverID, channelID); err != nil {
+		return err
+	}
+
+	msg, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if msg.ChannelID != channelID {
+		return fmt.Errorf("%w: scheduled message not found", pkg.ErrNotFound)
+	}
+	if msg.UserID != userID {
+		return fmt.Errorf("%w: only the author can cancel a scheduled message", pkg.ErrForbidden)
+	}
+
+	return s.scheduleRepo.Delete(ctx, id)
+}
+
+// verifyChannel is the IDOR guard: the channel must belong to the route's server.
+func (s *schedulerService) verifyChannel(ctx context.Context, serverID, channelID string) (*models.Channel, error) {
+	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil || channel.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+	return channel, nil
+}
+
+func (s *schedulerService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.started = true
+
+	go s.pollLoop(ctx)
+}
+
+func (s *schedulerService) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	cancel := s.cancel
+	done := s.done
+	s.started = false
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+	log.Println("[scheduler] stopped")
+}
+
+func (s *schedulerService) pollLoop(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(schedulerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx, cancel := context.WithTimeout(context.Background(), schedulerPollInterval)
+			if err := s.RunOnce(runCtx); err != nil {
+				log.Printf("[scheduler] poll failed: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+func (s *schedulerService) RunOnce(ctx context.Context) error {
+	due, err := s.scheduleRepo.GetDue(ctx, time.Now(), schedulerBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due messages: %w", err)
+	}
+
+	for _, msg := range due {
+		// The create path re-resolves permissions, so a SendMessages revoked
+		// after scheduling refuses the post here.
+		if _, err := s.sender.Create(ctx, msg.ChannelID, msg.UserID, &models.CreateMessageRequest{Content: msg.Content}); err != nil {
+			log.Printf("[scheduler] dropped scheduled message %s channel=%s user=%s: %v", msg.ID, msg.ChannelID, msg.UserID, err)
+		}
+		// Delete either way — a refused send must not retry forever.
+		if err := s.scheduleRepo.Delete(ctx, msg.ID); err != nil {
+			log.Printf("[scheduler] failed to delete scheduled message %s: %v", msg.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// memScheduleRepo is an in-memory queue — enough to exercise due/pending
+// selection without a database.
+type memScheduleRepo struct {
+	repository.ScheduledMessageRepository
+
+	rows []models.ScheduledMessage
+}
+
+func (r *memScheduleRepo) Create(_ context.Context, msg *models.ScheduledMessage) error {
+	r.rows = append(r.rows, *msg)
+	return nil
+}
+
+func (r *memScheduleRepo) GetByID(_ context.Context, id string) (*models.ScheduledMessage, error) {
+	for _, row := range r.rows {
+		if row.ID == id {
+			return &row, nil
+		}
+	}
+	return nil, pkg.ErrNotFound
+}
+
+func (r *memScheduleRepo) GetPendingByChannelAndUser(_ context.Context, channelID, userID string) ([]models.ScheduledMessage, error) {
+	var out []models.ScheduledMessage
+	for _, row := range r.rows {
+		if row.ChannelID == channelID && row.UserID == userID {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *memScheduleRepo) GetDue(_ context.Context, now time.Time, limit int) ([]models.ScheduledMessage, error) {
+	var out []models.ScheduledMessage
+	for _, row := range r.rows {
+		if !row.SendAt.After(now) && len(out) < limit {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *memScheduleRepo) Delete(_ context.Context, id string) error {
+	for i, row := range r.rows {
+		if row.ID == id {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+type sentScheduled struct {
+	channelID string
+	userID    string
+	content   string
+}
+
+// stubSender records sends; err simulates the create path refusing the post.
+type stubSender struct {
+	sent []sentScheduled
+	err  error
+}
+
+func (s *stubSender) Create(_ context.Context, channelID string, userID string, req *models.CreateMessageRequest) (*models.Message, error) {
+	s.sent = append(s.sent, sentScheduled{channelID, userID, req.Content})
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &models.Message{ID: "m1", ChannelID: channelID}, nil
+}
+
+func newTestScheduler(repo *memScheduleRepo, sender *stubSender, perms models.Permission) SchedulerService {
+	return NewSchedulerService(
+		repo,
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				return &models.Channel{ID: id, ServerID: "s1", Type: models.ChannelTypeText}, nil
+			},
+		},
+		&testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, _, _ string) (models.Permission, error) {
+				return perms, nil
+			},
+		},
+		sender,
+	)
+}
+
+func TestSchedule_ValidatesWindowAndPermission(t *testing.T) {
+	repo := &memScheduleRepo{}
+	svc := newTestScheduler(repo, &stubSender{}, models.PermSendMessages)
+	ctx := context.Background()
+
+	if _, err := svc.Schedule(ctx, "s1", "ch1", "u1",
+		&models.ScheduleMessageRequest{Content: "late", SendAt: time.Now().Add(-time.Minute)}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("past send_at: got %v, want bad request", err)
+	}
+	if _, err := svc.Schedule(ctx, "s1", "ch1", "u1",
+		&models.ScheduleMessageRequest{Content: "far", SendAt: time.Now().Add(31 * 24 * time.Hour)}); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Errorf("send_at beyond 30 days: got %v, want bad request", err)
+	}
+
+	// A channel override can deny SendMessages even when the server-level
+	// route gate passed.
+	denied := newTestScheduler(repo, &stubSender{}, models.PermViewChannel)
+	if _, err := denied.Schedule(ctx, "s1", "ch1", "u1",
+		&models.ScheduleMessageRequest{Content: "hi", SendAt: time.Now().Add(time.Hour)}); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("denied channel: got %v, want forbidden", err)
+	}
+
+	msg, err := svc.Schedule(ctx, "s1", "ch1", "u1",
+		&models.ScheduleMessageRequest{Content: "hi", SendAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if msg.ID == "" || len(repo.rows) != 1 {
+		t.Fatalf("scheduled message not persisted: %+v rows=%d", msg, len(repo.rows))
+	}
+}
+
+func TestRunOnce_DeliversOnlyDueAndDeletesRows(t *testing.T) {
+	repo := &memScheduleRepo{rows: []models.ScheduledMessage{
+		{ID: "due", ChannelID: "ch1", UserID: "u1", Content: "now", SendAt: time.Now().Add(-time.Second)},
+		{ID: "later", ChannelID: "ch1", UserID: "u1", Content: "tomorrow", SendAt: time.Now().Add(24 * time.Hour)},
+	}}
+	sender := &stubSender{}
+	svc := newTestScheduler(repo, sender, models.PermSendMessages)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	if len(sender.sent) != 1 || sender.sent[0].content != "now" {
+		t.Fatalf("sent = %+v, want just the due message", sender.sent)
+	}
+	if len(repo.rows) != 1 || repo.rows[0].ID != "later" {
+		t.Fatalf("rows = %+v, want only the future message left", repo.rows)
+	}
+}
+
+// A send the create path refuses (e.g. SendMessages revoked after scheduling)
+// is dropped, not retried — the row must still go away.
+func TestRunOnce_RefusedSendStillDeletesRow(t *testing.T) {
+	repo := &memScheduleRepo{rows: []models.ScheduledMessage{
+		{ID: "due", ChannelID: "ch1", UserID: "u1", Content: "now", SendAt: time.Now().Add(-time.Second)},
+	}}
+	sender := &stubSender{err: fmt.Errorf("%w: no permission", pkg.ErrForbidden)}
+	svc := newTestScheduler(repo, sender, models.PermSendMessages)
+
+	if err := svc.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if len(repo.rows) != 0 {
+		t.Fatalf("refused send left %d rows queued, want 0", len(repo.rows))
+	}
+}
+
+func TestCancel_OnlyTheAuthor(t *testing.T) {
+	repo := &memScheduleRepo{rows: []models.ScheduledMessage{
+		{ID: "sm1", ChannelID: "ch1", UserID: "u1", Content: "hi", SendAt: time.Now().Add(time.Hour)},
+	}}
+	svc := newTestScheduler(repo, &stubSender{}, models.PermSendMessages)
+	ctx := context.Background()
+
+	if err := svc.Cancel(ctx, "s1", "ch1", "intruder", "sm1"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("foreign cancel: got %v, want forbidden", err)
+	}
+	if err := svc.Cancel(ctx, "s1", "ch1", "u1", "sm1"); err != nil {
+		t.Fatalf("author cancel: %v", err)
+	}
+	if len(repo.rows) != 0 {
+		t.Fatalf("cancelled message still queued: %+v", repo.rows)
+	}
+}
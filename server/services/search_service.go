@@ -9,21 +9,35 @@ import (
 	"github.com/akinalp/mqvi/repository"
 )
 
-// SearchService handles server-scoped message search (FTS5).
+// SearchService handles server-scoped message search (FTS5). Queries support
+// two inline operators, parsed out before the FTS match: `from:username`
+// restricts by author and `has:attachment` keeps only messages with files.
 type SearchService interface {
-	Search(ctx context.Context, serverID, query string, channelID *string, limit, offset int) (*repository.SearchResult, error)
+	Search(ctx context.Context, serverID, userID, query string, channelID *string, limit, offset int) (*repository.SearchResult, error)
 }
 
 type searchService struct {
-	searchRepo repository.SearchRepository
-	urlSigner  FileURLSigner
+	searchRepo  repository.SearchRepository
+	channelRepo repository.ChannelRepository
+	visChecker  ChannelVisibilityChecker
+	urlSigner   FileURLSigner
 }
 
-func NewSearchService(searchRepo repository.SearchRepository, urlSigner FileURLSigner) SearchService {
-	return &searchService{searchRepo: searchRepo, urlSigner: urlSigner}
+func NewSearchService(
+	searchRepo repository.SearchRepository,
+	channelRepo repository.ChannelRepository,
+	visChecker ChannelVisibilityChecker,
+	urlSigner FileURLSigner,
+) SearchService {
+	return &searchService{
+		searchRepo:  searchRepo,
+		channelRepo: channelRepo,
+		visChecker:  visChecker,
+		urlSigner:   urlSigner,
+	}
 }
 
-func (s *searchService) Search(ctx context.Context, serverID, query string, channelID *string, limit, offset int) (*repository.SearchResult, error) {
+func (s *searchService) Search(ctx context.Context, serverID, userID, query string, channelID *string, limit, offset int) (*repository.SearchResult, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return nil, fmt.Errorf("%w: search query is required", pkg.ErrBadRequest)
@@ -39,7 +53,38 @@ func (s *searchService) Search(ctx context.Context, serverID, query string, chan
 		offset = 0
 	}
 
-	result, err := s.searchRepo.Search(ctx, query, serverID, channelID, limit, offset)
+	query, filter := parseSearchOperators(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: search needs at least one text term alongside from:/has: filters", pkg.ErrBadRequest)
+	}
+
+	// Search only ever surfaces messages the user could read in place: a single
+	// requested channel is vetted directly, a server-wide search is narrowed to
+	// the user's visible channel set.
+	vis, err := s.visChecker.BuildVisibilityFilter(ctx, userID, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build visibility filter: %w", err)
+	}
+	if channelID != nil {
+		if !vis.CanSee(*channelID) {
+			return nil, fmt.Errorf("%w: no access to this channel", pkg.ErrForbidden)
+		}
+		filter.ChannelID = channelID
+	} else if !vis.IsAdmin {
+		channels, err := s.channelRepo.GetAllByServer(ctx, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channels: %w", err)
+		}
+		visible := make([]string, 0, len(channels))
+		for _, ch := range channels {
+			if vis.CanSee(ch.ID) {
+				visible = append(visible, ch.ID)
+			}
+		}
+		filter.VisibleChannelIDs = visible
+	}
+
+	result, err := s.searchRepo.Search(ctx, query, serverID, filter, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -50,3 +95,24 @@ func (s *searchService) Search(ctx context.Context, serverID, query string, chan
 	}
 	return result, nil
 }
+
+// parseSearchOperators splits `from:username` and `has:attachment` tokens out
+// of the raw query, returning the remaining text terms and the corresponding
+// filter. Unrecognised `has:` values stay in the text query rather than
+// silently filtering nothing.
+func parseSearchOperators(query string) (string, repository.SearchFilter) {
+	var filter repository.SearchFilter
+	var terms []string
+	for _, token := range strings.Fields(query) {
+		switch {
+		case strings.HasPrefix(strings.ToLower(token), "from:") && len(token) > len("from:"):
+			username := token[len("from:"):]
+			filter.FromUsername = &username
+		case strings.EqualFold(token, "has:attachment"):
+			filter.HasAttachment = true
+		default:
+			terms = append(terms, token)
+		}
+	}
+	return strings.Join(terms, " "), filter
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// stubVisibility hands every caller the same prebuilt filter — how the filter
+// is derived from roles is the permission service's test territory.
+type stubVisibility struct{ filter *ChannelVisibilityFilter }
+
+func (s stubVisibility) BuildVisibilityFilter(context.Context, string, string) (*ChannelVisibilityFilter, error) {
+	return s.filter, nil
+}
+
+func newSearchFixture(t *testing.T, vis *ChannelVisibilityFilter) (*dbtest.Fixture, SearchService) {
+	t.Helper()
+	f := dbtest.New(t)
+	svc := NewSearchService(
+		repository.NewSQLiteSearchRepo(f.DB),
+		repository.NewSQLiteChannelRepo(f.DB),
+		stubVisibility{filter: vis},
+		&testutil.MockFileURLSigner{},
+	)
+	return f, svc
+}
+
+// A server-wide search must be silently narrowed to the channels the user can
+// see, and an explicit channel_id for a hidden channel must be refused.
+func TestSearch_NeverLeaksInvisibleChannels(t *testing.T) {
+	hidden := map[string]bool{}
+	f, svc := newSearchFixture(t, &ChannelVisibilityFilter{
+		HasBaseView:     true,
+		HiddenChannels:  hidden,
+		GrantedChannels: map[string]bool{},
+	})
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	visibleCh := f.Channel("", serverID)
+	hiddenCh := f.Channel("", serverID)
+	hidden[hiddenCh] = true
+
+	f.Message(dbtest.MessageSeed{ChannelID: visibleCh, Content: dbtest.Ptr("meeting notes for everyone")})
+	f.Message(dbtest.MessageSeed{ChannelID: hiddenCh, Content: dbtest.Ptr("meeting notes for leadership")})
+
+	res, err := svc.Search(ctx, serverID, "u1", "meeting", nil, 25, 0)
+	if err != nil {
+		t.Fatalf("server-wide search: %v", err)
+	}
+	if res.TotalCount != 1 || len(res.Messages) != 1 || res.Messages[0].ChannelID != visibleCh {
+		t.Fatalf("hidden channel leaked into server-wide results: count=%d messages=%v", res.TotalCount, res.Messages)
+	}
+
+	if _, err := svc.Search(ctx, serverID, "u1", "meeting", &hiddenCh, 25, 0); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("explicit hidden channel_id: got %v, want ErrForbidden", err)
+	}
+	if _, err := svc.Search(ctx, serverID, "u1", "meeting", &visibleCh, 25, 0); err != nil {
+		t.Fatalf("explicit visible channel_id: %v", err)
+	}
+}
+
+// Admins skip the channel enumeration entirely and see everything.
+func TestSearch_AdminSeesAllChannels(t *testing.T) {
+	f, svc := newSearchFixture(t, &ChannelVisibilityFilter{IsAdmin: true})
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	f.Message(dbtest.MessageSeed{ChannelID: f.Channel("", serverID), Content: dbtest.Ptr("meeting one")})
+	f.Message(dbtest.MessageSeed{ChannelID: f.Channel("", serverID), Content: dbtest.Ptr("meeting two")})
+
+	res, err := svc.Search(context.Background(), serverID, "admin", "meeting", nil, 25, 0)
+	if err != nil {
+		t.Fatalf("admin search: %v", err)
+	}
+	if res.TotalCount != 2 {
+		t.Fatalf("admin should see both channels, got count=%d", res.TotalCount)
+	}
+}
+
+// from:username and has:attachment are stripped from the text query and applied
+// as filters; an operator-only query has nothing left to match and is rejected.
+func TestSearch_FromAndHasAttachmentOperators(t *testing.T) {
+	f, svc := newSearchFixture(t, &ChannelVisibilityFilter{
+		HasBaseView:     true,
+		HiddenChannels:  map[string]bool{},
+		GrantedChannels: map[string]bool{},
+	})
+	ctx := context.Background()
+
+	serverID := f.Server(dbtest.ServerSeed{})
+	channelID := f.Channel("", serverID)
+	alice := f.User("alice") // username user_alice
+	bob := f.User("bob")
+
+	aliceMsg := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: alice, Content: dbtest.Ptr("release draft")})
+	bobMsg := f.Message(dbtest.MessageSeed{ChannelID: channelID, UserID: bob, Content: dbtest.Ptr("release feedback")})
+	attachmentRepo := repository.NewSQLiteAttachmentRepo(f.DB)
+	if err := attachmentRepo.Create(ctx, &models.Attachment{
+		MessageID: bobMsg, Filename: "build.log", FileURL: "/api/files/messages/x/build.log",
+	}); err != nil {
+		t.Fatalf("create attachment: %v", err)
+	}
+
+	res, err := svc.Search(ctx, serverID, "u1", "release from:USER_alice", nil, 25, 0)
+	if err != nil {
+		t.Fatalf("from: search: %v", err)
+	}
+	if res.TotalCount != 1 || res.Messages[0].ID != aliceMsg {
+		t.Fatalf("from: should match alice's message case-insensitively, got %+v", res.Messages)
+	}
+
+	res, err = svc.Search(ctx, serverID, "u1", "release has:attachment", nil, 25, 0)
+	if err != nil {
+		t.Fatalf("has:attachment search: %v", err)
+	}
+	if res.TotalCount != 1 || res.Messages[0].ID != bobMsg {
+		t.Fatalf("has:attachment should match only the message with a file, got %+v", res.Messages)
+	}
+
+	if _, err := svc.Search(ctx, serverID, "u1", "from:user_alice has:attachment", nil, 25, 0); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("operator-only query: got %v, want ErrBadRequest", err)
+	}
+}
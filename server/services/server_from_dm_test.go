@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// newDMUpgradeService wires a ServerService over the fixture database — the
+// stub-based newTestServerService can't exercise CreateFromDM's transaction.
+func newDMUpgradeService(f *dbtest.Fixture) ServerService {
+	return NewServerService(
+		f.DB,
+		repository.NewSQLiteServerRepo(f.DB),
+		repository.NewSQLiteLiveKitRepo(f.DB),
+		repository.NewSQLiteRoleRepo(f.DB),
+		repository.NewSQLiteChannelRepo(f.DB),
+		repository.NewSQLiteCategoryRepo(f.DB),
+		repository.NewSQLiteUserRepo(f.DB),
+		repository.NewSQLiteBanRepo(f.DB),
+		repository.NewSQLiteJoinRequestRepo(f.DB),
+		nil, nil,
+		repository.NewSQLiteDMRepo(f.DB),
+		nil,
+		stubHub{}, stubVoiceSync{}, nil, nil, stubSigner{}, nil,
+		models.PermDefaultMember, 100*1024*1024,
+	)
+}
+
+func TestCreateFromDM_AddsBothMembersAndCopiesMessages(t *testing.T) {
+	f := dbtest.New(t)
+	creator := f.User("")
+	other := f.User("")
+	dm := f.DMChannel("", creator, other)
+
+	f.DMMessage(dbtest.DMMessageSeed{DMChannelID: dm, UserID: creator, Content: dbtest.Ptr("first")})
+	f.DMMessage(dbtest.DMMessageSeed{DMChannelID: dm, UserID: other, Content: dbtest.Ptr("second")})
+	// E2EE stragglers must not carry over even in a plaintext conversation.
+	f.DMMessage(dbtest.DMMessageSeed{DMChannelID: dm, UserID: creator, EncryptionVersion: 1, Ciphertext: dbtest.Ptr("opaque")})
+
+	svc := newDMUpgradeService(f)
+	server, err := svc.CreateFromDM(context.Background(), creator, dm, "our server")
+	if err != nil {
+		t.Fatalf("create from dm: %v", err)
+	}
+	if server.OwnerID != creator {
+		t.Errorf("owner = %s, want %s", server.OwnerID, creator)
+	}
+
+	var members int
+	if err := f.DB.QueryRow(
+		`SELECT COUNT(*) FROM server_members WHERE server_id = ?`, server.ID,
+	).Scan(&members); err != nil {
+		t.Fatalf("count members: %v", err)
+	}
+	if members != 2 {
+		t.Errorf("members = %d, want both dm participants", members)
+	}
+
+	// The non-creator gets exactly the default role — leaving must work normally.
+	var otherRoles int
+	if err := f.DB.QueryRow(
+		`SELECT COUNT(*) FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+		 WHERE ur.user_id = ? AND r.server_id = ? AND r.is_default = 1`, other, server.ID,
+	).Scan(&otherRoles); err != nil {
+		t.Fatalf("count roles: %v", err)
+	}
+	if otherRoles != 1 {
+		t.Errorf("default roles for added member = %d, want 1", otherRoles)
+	}
+
+	var generalID string
+	if err := f.DB.QueryRow(
+		`SELECT id FROM channels WHERE server_id = ? AND type = 'text'`, server.ID,
+	).Scan(&generalID); err != nil {
+		t.Fatalf("find general channel: %v", err)
+	}
+
+	rows, err := f.DB.Query(
+		`SELECT user_id, content FROM messages WHERE channel_id = ? ORDER BY rowid`, generalID,
+	)
+	if err != nil {
+		t.Fatalf("read copied messages: %v", err)
+	}
+	defer rows.Close()
+	var copied []string
+	for rows.Next() {
+		var userID, content string
+		if err := rows.Scan(&userID, &content); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		copied = append(copied, userID+":"+content)
+	}
+	want := []string{creator + ":first", other + ":second"}
+	if len(copied) != len(want) {
+		t.Fatalf("copied %d messages (%v), want %d", len(copied), copied, len(want))
+	}
+	for i := range want {
+		if copied[i] != want[i] {
+			t.Errorf("message %d = %q, want %q (original author and order must survive)", i, copied[i], want[i])
+		}
+	}
+}
+
+func TestCreateFromDM_RequiresMembership(t *testing.T) {
+	f := dbtest.New(t)
+	dm := f.DMChannel("", "", "")
+	outsider := f.User("")
+
+	svc := newDMUpgradeService(f)
+	if _, err := svc.CreateFromDM(context.Background(), outsider, dm, "sneaky"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for non-participant, got %v", err)
+	}
+}
+
+func TestCreateFromDM_SkipsHistoryForE2EEConversations(t *testing.T) {
+	f := dbtest.New(t)
+	creator := f.User("")
+	other := f.User("")
+	dm := f.DMChannel("", creator, other)
+	// Even plaintext rows from before encryption was enabled stay put — the
+	// conversation was promised private once the toggle flipped.
+	f.DMMessage(dbtest.DMMessageSeed{DMChannelID: dm, UserID: creator, Content: dbtest.Ptr("pre-upgrade plaintext")})
+	if _, err := f.DB.Exec(`UPDATE dm_channels SET e2ee_enabled = 1 WHERE id = ?`, dm); err != nil {
+		t.Fatalf("enable e2ee: %v", err)
+	}
+
+	svc := newDMUpgradeService(f)
+	server, err := svc.CreateFromDM(context.Background(), creator, dm, "quiet server")
+	if err != nil {
+		t.Fatalf("create from dm: %v", err)
+	}
+
+	var copied int
+	if err := f.DB.QueryRow(
+		`SELECT COUNT(*) FROM messages m JOIN channels c ON c.id = m.channel_id
+		 WHERE c.server_id = ?`, server.ID,
+	).Scan(&copied); err != nil {
+		t.Fatalf("count messages: %v", err)
+	}
+	if copied != 0 {
+		t.Errorf("copied %d messages from an e2ee conversation, want 0", copied)
+	}
+}
@@ -39,6 +39,9 @@ func (s *stubServerRepo) GetActiveByID(_ context.Context, _ string) (*models.Ser
 	return s.server, nil
 }
 func (s *stubServerRepo) AddMember(_ context.Context, _, _ string) error { s.addCalls++; return nil }
+func (s *stubServerRepo) GetServerIDByVanityCode(_ context.Context, _ string) (string, error) {
+	return "", pkg.ErrNotFound
+}
 
 type stubBanRepo struct {
 	repository.BanRepository
@@ -91,6 +94,7 @@ func (stubUserRepo) GetByID(_ context.Context, id string) (*models.User, error)
 type stubHub struct{ ws.BroadcastAndManage }
 
 func (stubHub) AddClientServerID(_, _ string)          {}
+func (stubHub) RemoveClientServerID(_, _ string)       {}
 func (stubHub) BroadcastToUser(_ string, _ ws.Event)   {}
 func (stubHub) BroadcastToServer(_ string, _ ws.Event) {}
 
@@ -106,7 +110,8 @@ func (stubSigner) SignURLPtr(u *string) *string { return u }
 func newTestServerService(sr repository.ServerRepository, ban repository.BanRepository, jr repository.JoinRequestRepository, inv InviteService) ServerService {
 	return NewServerService(
 		nil, sr, nil, stubRoleRepo{}, nil, nil, stubUserRepo{},
-		ban, jr, inv, stubHub{}, stubVoiceSync{}, nil, nil, stubSigner{}, nil,
+		ban, jr, nil, nil, nil, inv, stubHub{}, stubVoiceSync{}, nil, nil, stubSigner{}, nil,
+		models.PermDefaultMember, 100*1024*1024,
 	)
 }
 
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/akinalp/mqvi/pkg/crypto"
 	"github.com/akinalp/mqvi/repository"
 	"github.com/akinalp/mqvi/ws"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // LiveKitSettings exposes non-secret LiveKit info for the settings UI.
@@ -23,12 +25,26 @@ type LiveKitSettings struct {
 
 type ServerService interface {
 	CreateServer(ctx context.Context, ownerID string, req *models.CreateServerRequest) (*models.Server, error)
+	// CreateFromDM "upgrades" a DM conversation into a new mqvi-hosted server:
+	// creates it like CreateServer, adds both participants as members (default
+	// role — they can leave normally), and copies recent plaintext DM messages
+	// into #general. The creator must be a participant of an accepted DM and
+	// becomes the owner.
+	CreateFromDM(ctx context.Context, creatorID, dmChannelID, name string) (*models.Server, error)
+	// Server structure templates — snapshot/instantiate/list. Implemented in
+	// server_template.go.
+	SaveTemplate(ctx context.Context, serverID, userID string, req *models.SaveTemplateRequest) (*models.ServerTemplate, error)
+	CreateFromTemplate(ctx context.Context, ownerID, templateID, name string) (*models.Server, error)
+	ListTemplates(ctx context.Context, userID string) ([]models.ServerTemplate, error)
 	GetServer(ctx context.Context, serverID string) (*models.Server, error)
 	// GetServerRaw returns the server without signing file URLs. Used for internal
 	// operations like file deletion where the raw DB path is needed.
 	GetServerRaw(ctx context.Context, serverID string) (*models.Server, error)
 	GetUserServers(ctx context.Context, userID string) ([]models.ServerListItem, error)
-	UpdateServer(ctx context.Context, serverID string, req *models.UpdateServerRequest) (*models.Server, error)
+	UpdateServer(ctx context.Context, serverID, actorID string, req *models.UpdateServerRequest) (*models.Server, error)
+	// UploadPolicy resolves the server's effective upload limits (size ceiling
+	// applied for mqvi-hosted servers).
+	UploadPolicy(ctx context.Context, serverID string) (models.UploadPolicy, error)
 	UpdateIcon(ctx context.Context, serverID, iconURL string) (*models.Server, error)
 	UpdateBanner(ctx context.Context, serverID, bannerURL string) (*models.Server, error)
 	// DeleteServer soft-deletes the server. Files and LiveKit instance are preserved.
@@ -43,6 +59,9 @@ type ServerService interface {
 	// GetDeletedServers returns soft-deleted servers owned by this user (for restore UI).
 	GetDeletedServers(ctx context.Context, userID string) ([]models.DeletedServerInfo, error)
 	JoinServer(ctx context.Context, userID, inviteCode string) (*JoinResult, error)
+	// SetVanityCode sets (empty clears) the server's human-readable join alias.
+	// Overwriting frees the previous slug; ErrConflict when another server holds it.
+	SetVanityCode(ctx context.Context, serverID, code string) (*string, error)
 	JoinPublicServer(ctx context.Context, userID, serverID string) (*JoinResult, error)
 	// Join approval — pending requesters live in a separate table, never in server_members.
 	ApproveRequest(ctx context.Context, serverID, targetUserID string) error
@@ -50,9 +69,40 @@ type ServerService interface {
 	ListRequests(ctx context.Context, serverID string) ([]models.ServerJoinRequestWithUser, error)
 	CountRequests(ctx context.Context, serverID string) (int, error)
 	LeaveServer(ctx context.Context, serverID, userID string) error
+	// TransferOwnership hands the server to another member (owner-only, password
+	// re-entry). The owner role moves with it; the old owner stays an ordinary
+	// member and is then free to leave.
+	TransferOwnership(ctx context.Context, serverID, actorID string, req *models.TransferOwnershipRequest) (*models.Server, error)
+	// RemoveTemporaryMemberships ends every membership this user holds through a
+	// temporary invite without having earned a non-default role since joining.
+	// Called from the hub's last-disconnect hook.
+	RemoveTemporaryMemberships(ctx context.Context, userID string) error
+	// SoftDeleteOwnedServers soft-deletes every active server the user owns, through
+	// the same flow as an explicit owner delete (broadcasts, voice teardown, restore
+	// window). Called when the owner's account is self-deleted.
+	SoftDeleteOwnedServers(ctx context.Context, userID string) error
 	GetLiveKitSettings(ctx context.Context, serverID string) (*LiveKitSettings, error)
 	// ReorderServers updates the user's personal server list order. No WS broadcast.
 	ReorderServers(ctx context.Context, userID string, req *models.ReorderServersRequest) ([]models.ServerListItem, error)
+	// Welcome screen / rules gate. GetWelcomeScreen is member-readable; the update is
+	// PermAdmin (route middleware). AcceptRules lifts the read-only gate for this member.
+	GetWelcomeScreen(ctx context.Context, serverID string) (*models.WelcomeConfig, error)
+	UpdateWelcomeScreen(ctx context.Context, serverID string, cfg *models.WelcomeConfig) (*models.WelcomeConfig, error)
+	AcceptRules(ctx context.Context, serverID, userID string) error
+	// HydrateJoinResult fills a successful JoinResult with the member's visible channel
+	// tree and roles so the client can render the server without follow-up fetches
+	// (POST /api/servers/join?hydrate=true). No-op for pending results.
+	HydrateJoinResult(ctx context.Context, result *JoinResult, userID string) error
+	// SetPermCacheInvalidator wires the permission-cache invalidator post-construction.
+	SetPermCacheInvalidator(inv PermissionCacheInvalidator)
+	// SetChannelLister wires the channel-tree provider used by HydrateJoinResult.
+	SetChannelLister(lister ChannelLister)
+	// SetAuditRecorder wires the audit log post-construction.
+	SetAuditRecorder(rec AuditRecorder)
+	// SetSystemMessenger wires the join-notice writer post-construction.
+	SetSystemMessenger(m SystemMessenger)
+	// SetMemberGetter wires the member loader used for post-transfer broadcasts.
+	SetMemberGetter(g MemberGetter)
 }
 
 // MaxMqviHostedServersPerUser caps how many mqvi-hosted servers a single
@@ -61,6 +111,27 @@ type ServerService interface {
 // hard-codes the same number in the i18n string — keep both in sync.
 const MaxMqviHostedServersPerUser = 3
 
+// ChannelLister returns the permission-filtered channel tree for one member — the same
+// payload GET /api/servers/{serverId}/channels serves. Implemented by channelService;
+// consumed by HydrateJoinResult so the join response honours channel visibility.
+type ChannelLister interface {
+	GetAllGrouped(ctx context.Context, serverID, userID string) ([]models.CategoryWithChannels, error)
+}
+
+// DMChannelReader reads a DM conversation and its recent messages — the
+// source side of CreateFromDM. Satisfied by repository.DMRepository.
+type DMChannelReader interface {
+	GetChannelByID(ctx context.Context, id string) (*models.DMChannel, error)
+	GetMessages(ctx context.Context, channelID string, beforeID string, limit int) ([]models.DMMessage, error)
+}
+
+// MemberGetter loads one member with their role set (ISP — MemberService implements
+// it). Consumed by TransferOwnership so both sides' clients get the same member
+// payload every other role change broadcasts.
+type MemberGetter interface {
+	GetByID(ctx context.Context, serverID, userID string) (*models.MemberWithRoles, error)
+}
+
 // VoiceStateSyncer pushes a server's in-progress voice participants to a single
 // user — used on server join so a newcomer sees active calls without reconnecting.
 type VoiceStateSyncer interface {
@@ -77,22 +148,56 @@ type VoiceServerDisconnector interface {
 }
 
 type serverService struct {
-	db            *sql.DB // for WithTx in CreateServer
-	serverRepo    repository.ServerRepository
-	livekitRepo   repository.LiveKitRepository
-	roleRepo      repository.RoleRepository
-	channelRepo   repository.ChannelRepository
-	categoryRepo  repository.CategoryRepository
+	db              *sql.DB // for WithTx in CreateServer
+	serverRepo      repository.ServerRepository
+	livekitRepo     repository.LiveKitRepository
+	roleRepo        repository.RoleRepository
+	channelRepo     repository.ChannelRepository
+	categoryRepo    repository.CategoryRepository
 	userRepo        repository.UserRepository
 	banRepo         repository.BanRepository
 	joinRequestRepo repository.JoinRequestRepository
+	channelPermRepo repository.ChannelPermissionRepository
+	templateRepo    repository.ServerTemplateRepository
+	dmRepo          DMChannelReader
 	inviteService   InviteService
 	hub             ws.BroadcastAndManage
 	voiceSync       VoiceStateSyncer
 	voiceDisc       VoiceServerDisconnector
-	encryptionKey   []byte // AES-256-GCM for LiveKit credentials
+	keychain        *crypto.Keychain // AES-256-GCM keys for LiveKit credentials
 	urlSigner       FileURLSigner
 	fileCleanup     FileCleanupService
+	// defaultMemberPerms is what the auto-created "everyone" role grants —
+	// instance-configurable (cfg.DefaultMemberPermissions).
+	defaultMemberPerms models.Permission
+	// maxUploadSize (cfg.Upload.MaxSize) doubles as the default upload limit
+	// and the ceiling a mqvi-hosted server setting cannot exceed.
+	maxUploadSize   int64
+	permInvalidator PermissionCacheInvalidator // set post-construction, may be nil
+	channelLister   ChannelLister              // set post-construction, may be nil
+	audit           AuditRecorder              // set post-construction, may be nil
+	systemMessenger SystemMessenger            // set post-construction, may be nil
+	memberGetter    MemberGetter               // set post-construction, may be nil
+}
+
+func (s *serverService) SetPermCacheInvalidator(inv PermissionCacheInvalidator) {
+	s.permInvalidator = inv
+}
+
+func (s *serverService) SetChannelLister(lister ChannelLister) {
+	s.channelLister = lister
+}
+
+func (s *serverService) SetAuditRecorder(rec AuditRecorder) {
+	s.audit = rec
+}
+
+func (s *serverService) SetSystemMessenger(m SystemMessenger) {
+	s.systemMessenger = m
+}
+
+func (s *serverService) SetMemberGetter(g MemberGetter) {
+	s.memberGetter = g
 }
 
 func NewServerService(
@@ -105,13 +210,18 @@ func NewServerService(
 	userRepo repository.UserRepository,
 	banRepo repository.BanRepository,
 	joinRequestRepo repository.JoinRequestRepository,
+	channelPermRepo repository.ChannelPermissionRepository,
+	templateRepo repository.ServerTemplateRepository,
+	dmRepo DMChannelReader,
 	inviteService InviteService,
 	hub ws.BroadcastAndManage,
 	voiceSync VoiceStateSyncer,
 	voiceDisc VoiceServerDisconnector,
-	encryptionKey []byte,
+	keychain *crypto.Keychain,
 	urlSigner FileURLSigner,
 	fileCleanup FileCleanupService,
+	defaultMemberPerms models.Permission,
+	maxUploadSize int64,
 ) ServerService {
 	return &serverService{
 		db:              db,
@@ -123,13 +233,19 @@ func NewServerService(
 		userRepo:        userRepo,
 		banRepo:         banRepo,
 		joinRequestRepo: joinRequestRepo,
+		channelPermRepo: channelPermRepo,
+		templateRepo:    templateRepo,
+		dmRepo:          dmRepo,
 		inviteService:   inviteService,
 		hub:             hub,
 		voiceSync:       voiceSync,
 		voiceDisc:       voiceDisc,
-		encryptionKey:   encryptionKey,
+		keychain:        keychain,
 		urlSigner:       urlSigner,
 		fileCleanup:     fileCleanup,
+
+		defaultMemberPerms: defaultMemberPerms,
+		maxUploadSize:      maxUploadSize,
 	}
 }
 
@@ -147,6 +263,45 @@ func (s *serverService) CreateServer(ctx context.Context, ownerID string, req *m
 		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
 	}
 
+	livekitInstanceID, err := s.reserveLiveKitInstance(ctx, ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// ─── Atomic transaction: server + membership + roles + channels ───
+	server := &models.Server{
+		Name:              req.Name,
+		OwnerID:           ownerID,
+		IsPublic:          false,
+		LiveKitInstanceID: livekitInstanceID,
+	}
+
+	err = database.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, _, txErr := s.provisionServerTx(ctx, tx, server, ownerID)
+		return txErr
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server (transaction): %w", err)
+	}
+
+	// WS broadcast (after commit)
+	s.hub.AddClientServerID(ownerID, server.ID)
+	s.hub.BroadcastToUser(ownerID, ws.Event{
+		Op:   ws.OpServerCreate,
+		Data: serverListItemSigned(s.urlSigner, server),
+	})
+
+	log.Printf("[server] created server %s (name=%s, owner=%s, host=%s)",
+		server.ID, server.Name, ownerID, req.HostType)
+
+	return server, nil
+}
+
+// reserveLiveKitInstance enforces the mqvi-hosted ownership cap and resolves
+// the LiveKit instance for a new server (nil = no voice support). Runs before
+// the creation transaction — instance allocation talks to external state.
+func (s *serverService) reserveLiveKitInstance(ctx context.Context, ownerID string, req *models.CreateServerRequest) (*string, error) {
 	// Non-admin users can own at most MaxMqviHostedServersPerUser mqvi-hosted servers.
 	// Self-hosted servers are unlimited (the user provides their own LiveKit instance).
 	// Frontend matches on the stable error code "max_servers_reached" to show a
@@ -167,7 +322,6 @@ func (s *serverService) CreateServer(ctx context.Context, ownerID string, req *m
 		}
 	}
 
-	// ─── LiveKit instance setup (outside transaction) ───
 	var livekitInstanceID *string
 
 	switch req.HostType {
@@ -176,11 +330,11 @@ func (s *serverService) CreateServer(ctx context.Context, ownerID string, req *m
 			return nil, fmt.Errorf("%w: livekit_url, livekit_key, and livekit_secret are required for self-hosted", pkg.ErrBadRequest)
 		}
 
-		encKey, err := crypto.Encrypt(req.LiveKitKey, s.encryptionKey)
+		encKey, err := s.keychain.Encrypt(req.LiveKitKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to encrypt livekit key: %w", err)
 		}
-		encSecret, err := crypto.Encrypt(req.LiveKitSecret, s.encryptionKey)
+		encSecret, err := s.keychain.Encrypt(req.LiveKitSecret)
 		if err != nil {
 			return nil, fmt.Errorf("failed to encrypt livekit secret: %w", err)
 		}
@@ -214,124 +368,210 @@ func (s *serverService) CreateServer(ctx context.Context, ownerID string, req *m
 		// No voice support
 	}
 
-	// ─── Atomic transaction: server + membership + roles + channels ───
-	server := &models.Server{
-		Name:              req.Name,
-		OwnerID:           ownerID,
-		IsPublic:          false,
-		LiveKitInstanceID: livekitInstanceID,
+	return livekitInstanceID, nil
+}
+
+// provisionServerTx creates the server row, owner membership, default/owner
+// roles, and the default category/channel tree inside tx. Returns the default
+// role and the "general" text channel so callers can attach extra members or
+// seed messages in the same transaction.
+func (s *serverService) provisionServerTx(ctx context.Context, tx *sql.Tx, server *models.Server, ownerID string) (*models.Role, *models.Channel, error) {
+	txServerRepo := repository.NewSQLiteServerRepo(tx)
+	txRoleRepo := repository.NewSQLiteRoleRepo(tx)
+	txChannelRepo := repository.NewSQLiteChannelRepo(tx)
+	txCategoryRepo := repository.NewSQLiteCategoryRepo(tx)
+
+	if err := txServerRepo.Create(ctx, server); err != nil {
+		return nil, nil, fmt.Errorf("failed to create server: %w", err)
 	}
 
-	err := database.WithTx(ctx, s.db, func(tx *sql.Tx) error {
-		txServerRepo := repository.NewSQLiteServerRepo(tx)
-		txRoleRepo := repository.NewSQLiteRoleRepo(tx)
-		txChannelRepo := repository.NewSQLiteChannelRepo(tx)
-		txCategoryRepo := repository.NewSQLiteCategoryRepo(tx)
+	if err := txServerRepo.AddMember(ctx, server.ID, ownerID); err != nil {
+		return nil, nil, fmt.Errorf("failed to add owner as member: %w", err)
+	}
 
-		if err := txServerRepo.Create(ctx, server); err != nil {
-			return fmt.Errorf("failed to create server: %w", err)
-		}
+	// Default "everyone" role — permission set comes from instance config
+	// (falls back to models.PermDefaultMember).
+	defaultRole := &models.Role{
+		ServerID:    server.ID,
+		Name:        "everyone",
+		Color:       "#99AAB5",
+		Position:    1,
+		Permissions: s.defaultMemberPerms,
+		IsDefault:   true,
+		Mentionable: true,
+	}
+	if err := txRoleRepo.Create(ctx, defaultRole); err != nil {
+		return nil, nil, fmt.Errorf("failed to create default role: %w", err)
+	}
 
-		if err := txServerRepo.AddMember(ctx, server.ID, ownerID); err != nil {
-			return fmt.Errorf("failed to add owner as member: %w", err)
-		}
+	// Owner role — highest position, full permissions
+	ownerRole := &models.Role{
+		ServerID:    server.ID,
+		Name:        "Owner",
+		Color:       "#E74C3C",
+		Position:    100,
+		Permissions: models.PermAll,
+		IsOwner:     true,
+		Mentionable: true,
+	}
+	if err := txRoleRepo.Create(ctx, ownerRole); err != nil {
+		return nil, nil, fmt.Errorf("failed to create owner role: %w", err)
+	}
 
-		// Default "everyone" role
-		defaultPerms := models.PermViewChannel | models.PermReadMessages | models.PermSendMessages |
-			models.PermConnectVoice | models.PermSpeak | models.PermUseSoundboard
+	if err := txRoleRepo.AssignToUser(ctx, ownerID, defaultRole.ID, server.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to assign default role to owner: %w", err)
+	}
+	if err := txRoleRepo.AssignToUser(ctx, ownerID, ownerRole.ID, server.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to assign owner role: %w", err)
+	}
 
-		defaultRole := &models.Role{
-			ServerID:    server.ID,
-			Name:        "everyone",
-			Color:       "#99AAB5",
-			Position:    1,
-			Permissions: defaultPerms,
-			IsDefault:   true,
-			Mentionable: true,
-		}
-		if err := txRoleRepo.Create(ctx, defaultRole); err != nil {
-			return fmt.Errorf("failed to create default role: %w", err)
-		}
+	// Default categories + channels
+	textCategory := &models.Category{
+		ServerID: server.ID,
+		Name:     "Text Channels",
+		Position: 0,
+	}
+	if err := txCategoryRepo.Create(ctx, textCategory); err != nil {
+		return nil, nil, fmt.Errorf("failed to create text category: %w", err)
+	}
 
-		// Owner role — highest position, full permissions
-		ownerRole := &models.Role{
-			ServerID:    server.ID,
-			Name:        "Owner",
-			Color:       "#E74C3C",
-			Position:    100,
-			Permissions: models.PermAll,
-			IsOwner:     true,
-			Mentionable: true,
-		}
-		if err := txRoleRepo.Create(ctx, ownerRole); err != nil {
-			return fmt.Errorf("failed to create owner role: %w", err)
-		}
+	voiceCategory := &models.Category{
+		ServerID: server.ID,
+		Name:     "Voice Channels",
+		Position: 1,
+	}
+	if err := txCategoryRepo.Create(ctx, voiceCategory); err != nil {
+		return nil, nil, fmt.Errorf("failed to create voice category: %w", err)
+	}
 
-		if err := txRoleRepo.AssignToUser(ctx, ownerID, defaultRole.ID, server.ID); err != nil {
-			return fmt.Errorf("failed to assign default role to owner: %w", err)
-		}
-		if err := txRoleRepo.AssignToUser(ctx, ownerID, ownerRole.ID, server.ID); err != nil {
-			return fmt.Errorf("failed to assign owner role: %w", err)
-		}
+	textChannel := &models.Channel{
+		ServerID:   server.ID,
+		Name:       "general",
+		Type:       models.ChannelTypeText,
+		CategoryID: &textCategory.ID,
+		Position:   0,
+	}
+	if err := txChannelRepo.Create(ctx, textChannel); err != nil {
+		return nil, nil, fmt.Errorf("failed to create default text channel: %w", err)
+	}
 
-		// Default categories + channels
-		textCategory := &models.Category{
-			ServerID: server.ID,
-			Name:     "Text Channels",
-			Position: 0,
-		}
-		if err := txCategoryRepo.Create(ctx, textCategory); err != nil {
-			return fmt.Errorf("failed to create text category: %w", err)
-		}
+	voiceChannel := &models.Channel{
+		ServerID:   server.ID,
+		Name:       "General",
+		Type:       models.ChannelTypeVoice,
+		CategoryID: &voiceCategory.ID,
+		Position:   0,
+		Bitrate:    models.DefaultVoiceBitrate,
+	}
+	if err := txChannelRepo.Create(ctx, voiceChannel); err != nil {
+		return nil, nil, fmt.Errorf("failed to create default voice channel: %w", err)
+	}
 
-		voiceCategory := &models.Category{
-			ServerID: server.ID,
-			Name:     "Voice Channels",
-			Position: 1,
+	return defaultRole, textChannel, nil
+}
+
+// dmUpgradeCopyLimit bounds how many recent DM messages are copied into the
+// new server's #general. A full history import would make upgrade latency
+// proportional to conversation age; the most recent page is enough context.
+const dmUpgradeCopyLimit = 50
+
+func (s *serverService) CreateFromDM(ctx context.Context, creatorID, dmChannelID, name string) (*models.Server, error) {
+	req := &models.CreateServerRequest{Name: name, HostType: "mqvi_hosted"}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	dmChannel, err := s.dmRepo.GetChannelByID(ctx, dmChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: dm channel not found", pkg.ErrNotFound)
+	}
+	if creatorID != dmChannel.User1ID && creatorID != dmChannel.User2ID {
+		return nil, fmt.Errorf("%w: not a member of this conversation", pkg.ErrForbidden)
+	}
+	if dmChannel.Status != models.DMStatusAccepted {
+		return nil, fmt.Errorf("%w: conversation is still pending", pkg.ErrBadRequest)
+	}
+	otherID := dmChannel.User1ID
+	if otherID == creatorID {
+		otherID = dmChannel.User2ID
+	}
+
+	// Recent history read before the transaction — E2EE conversations are
+	// skipped entirely (ciphertext is keyed to the DM, useless in a channel).
+	var recent []models.DMMessage
+	if !dmChannel.E2EEEnabled {
+		recent, err = s.dmRepo.GetMessages(ctx, dmChannelID, "", dmUpgradeCopyLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dm messages: %w", err)
 		}
-		if err := txCategoryRepo.Create(ctx, voiceCategory); err != nil {
-			return fmt.Errorf("failed to create voice category: %w", err)
+	}
+
+	livekitInstanceID, err := s.reserveLiveKitInstance(ctx, creatorID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &models.Server{
+		Name:              req.Name,
+		OwnerID:           creatorID,
+		IsPublic:          false,
+		LiveKitInstanceID: livekitInstanceID,
+	}
+
+	// One transaction for the whole upgrade: a server without the second
+	// participant (or vice versa) must never be observable.
+	err = database.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		defaultRole, general, txErr := s.provisionServerTx(ctx, tx, server, creatorID)
+		if txErr != nil {
+			return txErr
 		}
 
-		textChannel := &models.Channel{
-			ServerID:   server.ID,
-			Name:       "general",
-			Type:       models.ChannelTypeText,
-			CategoryID: &textCategory.ID,
-			Position:   0,
+		txServerRepo := repository.NewSQLiteServerRepo(tx)
+		txRoleRepo := repository.NewSQLiteRoleRepo(tx)
+		if err := txServerRepo.AddMember(ctx, server.ID, otherID); err != nil {
+			return fmt.Errorf("failed to add dm member: %w", err)
 		}
-		if err := txChannelRepo.Create(ctx, textChannel); err != nil {
-			return fmt.Errorf("failed to create default text channel: %w", err)
+		if err := txRoleRepo.AssignToUser(ctx, otherID, defaultRole.ID, server.ID); err != nil {
+			return fmt.Errorf("failed to assign default role to dm member: %w", err)
 		}
 
-		voiceChannel := &models.Channel{
-			ServerID:   server.ID,
-			Name:       "General",
-			Type:       models.ChannelTypeVoice,
-			CategoryID: &voiceCategory.ID,
-			Position:   0,
-			Bitrate:    64000,
-		}
-		if err := txChannelRepo.Create(ctx, voiceChannel); err != nil {
-			return fmt.Errorf("failed to create default voice channel: %w", err)
+		// Copy plaintext chat oldest-first (GetMessages returns newest-first)
+		// so #general reads in original order. Call logs and system entries
+		// don't translate — only regular text messages carry over.
+		txMessageRepo := repository.NewSQLiteMessageRepo(tx)
+		for i := len(recent) - 1; i >= 0; i-- {
+			dm := recent[i]
+			if dm.MessageType != models.MessageTypeText || dm.EncryptionVersion != 0 || dm.Content == nil {
+				continue
+			}
+			msg := &models.Message{
+				ChannelID: general.ID,
+				UserID:    dm.UserID,
+				Content:   dm.Content,
+			}
+			if err := txMessageRepo.Create(ctx, msg); err != nil {
+				return fmt.Errorf("failed to copy dm message: %w", err)
+			}
 		}
 
 		return nil
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create server (transaction): %w", err)
+		return nil, fmt.Errorf("failed to create server from dm (transaction): %w", err)
 	}
 
-	// WS broadcast (after commit)
-	s.hub.AddClientServerID(ownerID, server.ID)
-	s.hub.BroadcastToUser(ownerID, ws.Event{
-		Op: ws.OpServerCreate,
-		Data: serverListItemSigned(s.urlSigner, server),
-	})
+	// WS broadcast (after commit) — both participants get the new server.
+	for _, uid := range []string{creatorID, otherID} {
+		s.hub.AddClientServerID(uid, server.ID)
+		s.hub.BroadcastToUser(uid, ws.Event{
+			Op:   ws.OpServerCreate,
+			Data: serverListItemSigned(s.urlSigner, server),
+		})
+	}
 
-	log.Printf("[server] created server %s (name=%s, owner=%s, host=%s)",
-		server.ID, server.Name, ownerID, req.HostType)
+	log.Printf("[server] created server %s from dm %s (owner=%s, member=%s)",
+		server.ID, dmChannelID, creatorID, otherID)
 
 	return server, nil
 }
@@ -369,7 +609,7 @@ func nilIfEmpty(s string) *string {
 	return &s
 }
 
-func (s *serverService) UpdateServer(ctx context.Context, serverID string, req *models.UpdateServerRequest) (*models.Server, error) {
+func (s *serverService) UpdateServer(ctx context.Context, serverID, actorID string, req *models.UpdateServerRequest) (*models.Server, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
 	}
@@ -391,6 +631,9 @@ func (s *serverService) UpdateServer(ctx context.Context, serverID string, req *
 	if req.ApprovalRequired != nil {
 		server.ApprovalRequired = *req.ApprovalRequired
 	}
+	if req.DisallowSelfReactions != nil {
+		server.DisallowSelfReactions = *req.DisallowSelfReactions
+	}
 	if req.Description != nil {
 		server.Description = nilIfEmpty(*req.Description)
 	}
@@ -400,6 +643,33 @@ func (s *serverService) UpdateServer(ctx context.Context, serverID string, req *
 	if req.AFKTimeoutMinutes != nil {
 		server.AFKTimeoutMinutes = *req.AFKTimeoutMinutes
 	}
+	if req.MessageRetentionDays != nil {
+		server.MessageRetentionDays = *req.MessageRetentionDays
+	}
+	if req.UploadMaxSize != nil {
+		if *req.UploadMaxSize == 0 {
+			server.UploadMaxSize = nil // back to the platform default
+		} else {
+			// The ceiling only binds mqvi-hosted servers — self-hosted deployments
+			// own their bandwidth and may raise the limit past the platform default.
+			if *req.UploadMaxSize > s.maxUploadSize {
+				hosted, err := s.isMqviHosted(ctx, server)
+				if err != nil {
+					return nil, err
+				}
+				if hosted {
+					return nil, fmt.Errorf("%w: upload_max_size cannot exceed the platform limit of %dMB on mqvi-hosted servers", pkg.ErrBadRequest, s.maxUploadSize/(1024*1024))
+				}
+			}
+			server.UploadMaxSize = req.UploadMaxSize
+		}
+	}
+	if req.UploadAllowedTypes != nil {
+		server.UploadAllowedTypes = models.JoinTypeRules(*req.UploadAllowedTypes)
+	}
+	if req.UploadBlockedTypes != nil {
+		server.UploadBlockedTypes = models.JoinTypeRules(*req.UploadBlockedTypes)
+	}
 
 	if err := s.serverRepo.Update(ctx, server); err != nil {
 		return nil, fmt.Errorf("failed to update server: %w", err)
@@ -419,11 +689,11 @@ func (s *serverService) UpdateServer(ctx context.Context, serverID string, req *
 			return nil, fmt.Errorf("%w: cannot modify platform-managed LiveKit instance", pkg.ErrForbidden)
 		}
 
-		encKey, err := crypto.Encrypt(*req.LiveKitKey, s.encryptionKey)
+		encKey, err := s.keychain.Encrypt(*req.LiveKitKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to encrypt livekit key: %w", err)
 		}
-		encSecret, err := crypto.Encrypt(*req.LiveKitSecret, s.encryptionKey)
+		encSecret, err := s.keychain.Encrypt(*req.LiveKitSecret)
 		if err != nil {
 			return nil, fmt.Errorf("failed to encrypt livekit secret: %w", err)
 		}
@@ -446,9 +716,113 @@ func (s *serverService) UpdateServer(ctx context.Context, serverID string, req *
 		Data: server,
 	})
 
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionServerUpdate, "", nil)
+	}
+
 	return server, nil
 }
 
+// isMqviHosted reports whether the server's LiveKit instance is platform
+// managed — the same test CountOwnedMqviHostedServers applies in SQL.
+func (s *serverService) isMqviHosted(ctx context.Context, server *models.Server) (bool, error) {
+	if server.LiveKitInstanceID == nil {
+		return false, nil
+	}
+	instance, err := s.livekitRepo.GetByID(ctx, *server.LiveKitInstanceID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get livekit instance: %w", err)
+	}
+	return instance.IsPlatformManaged, nil
+}
+
+// UploadPolicy resolves the effective upload rules for a server. A stored max
+// size above the platform ceiling is clamped for mqvi-hosted servers, so a
+// setting written before a server migrated onto platform hosting cannot
+// overshoot it.
+func (s *serverService) UploadPolicy(ctx context.Context, serverID string) (models.UploadPolicy, error) {
+	server, err := s.serverRepo.GetByID(ctx, serverID)
+	if err != nil {
+		return models.UploadPolicy{}, err
+	}
+
+	policy := models.UploadPolicy{
+		MaxSize:      s.maxUploadSize,
+		AllowedTypes: models.SplitTypeRules(server.UploadAllowedTypes),
+		BlockedTypes: models.SplitTypeRules(server.UploadBlockedTypes),
+	}
+	if server.UploadMaxSize == nil {
+		return policy, nil
+	}
+
+	size := *server.UploadMaxSize
+	if size > s.maxUploadSize {
+		hosted, err := s.isMqviHosted(ctx, server)
+		if err != nil {
+			return models.UploadPolicy{}, err
+		}
+		if hosted {
+			size = s.maxUploadSize
+		}
+	}
+	policy.MaxSize = size
+	return policy, nil
+}
+
+func (s *serverService) GetWelcomeScreen(ctx context.Context, serverID string) (*models.WelcomeConfig, error) {
+	return s.serverRepo.GetWelcomeConfig(ctx, serverID)
+}
+
+func (s *serverService) UpdateWelcomeScreen(ctx context.Context, serverID string, cfg *models.WelcomeConfig) (*models.WelcomeConfig, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	// Suggested channels must belong to this server — a foreign ID would render as a
+	// dead tile on the welcome screen.
+	for _, channelID := range cfg.SuggestedChannelIDs {
+		channel, err := s.channelRepo.GetByID(ctx, channelID)
+		if err != nil || channel == nil || channel.ServerID != serverID {
+			return nil, fmt.Errorf("%w: suggested channel %s not in this server", pkg.ErrBadRequest, channelID)
+		}
+	}
+
+	if err := s.serverRepo.UpdateWelcomeConfig(ctx, serverID, cfg); err != nil {
+		return nil, err
+	}
+
+	s.hub.BroadcastToServer(serverID, ws.Event{
+		Op:   ws.OpWelcomeUpdate,
+		Data: cfg,
+	})
+
+	return cfg, nil
+}
+
+func (s *serverService) AcceptRules(ctx context.Context, serverID, userID string) error {
+	if err := s.serverRepo.SetRulesAccepted(ctx, serverID, userID); err != nil {
+		return err
+	}
+
+	// The resolver baked the read-only mask into this user's cached entries — drop them
+	// or the gate outlives its acceptance by up to the cache TTL.
+	if s.permInvalidator != nil {
+		s.permInvalidator.InvalidateUserPermissions(userID)
+	}
+
+	// Tell the member's other devices the gate lifted (the accepting one got a 200).
+	s.hub.BroadcastToUser(userID, ws.Event{
+		Op:       ws.OpRulesAccept,
+		ServerID: serverID,
+		Data:     map[string]string{"server_id": serverID},
+	})
+
+	return nil
+}
+
 func (s *serverService) UpdateIcon(ctx context.Context, serverID, iconURL string) (*models.Server, error) {
 	server, err := s.serverRepo.GetByID(ctx, serverID)
 	if err != nil {
@@ -664,9 +1038,41 @@ func (s *serverService) GetDeletedServers(ctx context.Context, userID string) ([
 
 // JoinResult is what JoinServer returns: the joined server, or a Pending flag when the
 // server requires approval (a request was created rather than a membership).
+// Channels and Roles stay nil unless the caller asked for a hydrated join.
 type JoinResult struct {
-	Server  *models.Server `json:"server,omitempty"`
-	Pending bool           `json:"pending"`
+	Server   *models.Server                `json:"server,omitempty"`
+	Pending  bool                          `json:"pending"`
+	Channels []models.CategoryWithChannels `json:"channels,omitempty"`
+	Roles    []models.Role                 `json:"roles,omitempty"`
+}
+
+// HydrateJoinResult adds the new member's visible channels and roles to a successful
+// join, saving the client the two follow-up fetches that kept the post-join spinner
+// alive. Visibility filtering is GetAllGrouped's — the same as the channels endpoint.
+func (s *serverService) HydrateJoinResult(ctx context.Context, result *JoinResult, userID string) error {
+	if result == nil || result.Pending || result.Server == nil {
+		return nil
+	}
+	if s.channelLister == nil {
+		return fmt.Errorf("channel lister not wired")
+	}
+
+	channels, err := s.channelLister.GetAllGrouped(ctx, result.Server.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load channels for hydrated join: %w", err)
+	}
+
+	roles, err := s.roleRepo.GetByUserIDAndServer(ctx, userID, result.Server.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load roles for hydrated join: %w", err)
+	}
+	if roles == nil {
+		roles = []models.Role{}
+	}
+
+	result.Channels = channels
+	result.Roles = roles
+	return nil
 }
 
 // maxPendingRequestsPerServer bounds a server's join-request queue so it can't grow
@@ -679,11 +1085,28 @@ const maxPendingRequestsPerServer = 1000
 // consumed — the admin is the gate); otherwise the user is promoted immediately, consuming
 // the invite use exactly as before.
 func (s *serverService) JoinServer(ctx context.Context, userID, inviteCode string) (*JoinResult, error) {
-	invite, err := s.inviteService.Validate(ctx, inviteCode)
-	if err != nil {
-		return nil, err
+	var (
+		serverID      string
+		consumeInvite bool
+		temporary     bool
+	)
+	// Vanity codes resolve first. A vanity slug is a server-owned alias, not an invite
+	// row: unlimited uses, no expiry, and nothing to consume on join.
+	vanityServerID, vanityErr := s.serverRepo.GetServerIDByVanityCode(ctx, inviteCode)
+	switch {
+	case vanityErr == nil:
+		serverID = vanityServerID
+	case errors.Is(vanityErr, pkg.ErrNotFound):
+		invite, err := s.inviteService.Validate(ctx, inviteCode)
+		if err != nil {
+			return nil, err
+		}
+		serverID = invite.ServerID
+		consumeInvite = true
+		temporary = invite.Temporary
+	default:
+		return nil, fmt.Errorf("failed to resolve vanity code: %w", vanityErr)
 	}
-	serverID := invite.ServerID
 
 	banned, err := s.banRepo.Exists(ctx, serverID, userID)
 	if err != nil {
@@ -728,7 +1151,7 @@ func (s *serverService) JoinServer(ctx context.Context, userID, inviteCode strin
 		return &JoinResult{Pending: true}, nil
 	}
 
-	joined, err := s.promoteToMember(ctx, server, userID, inviteCode, true)
+	joined, err := s.promoteToMember(ctx, server, userID, inviteCode, consumeInvite, temporary)
 	if err != nil {
 		return nil, err
 	}
@@ -736,8 +1159,11 @@ func (s *serverService) JoinServer(ctx context.Context, userID, inviteCode strin
 }
 
 // JoinPublicServer joins (or requests to join) a public server straight from the discovery
-// directory — no invite involved. Rejects non-public servers and bans, and honors
-// approval_required exactly like the invite path.
+// directory — no invite involved. is_public is the gate that makes a codeless join legal at
+// all (flipping it off via UpdateServer closes this path on the next attempt; it is checked
+// per join, never cached). Rejects non-public servers and bans, and honors approval_required
+// exactly like the invite path: the two flags are independent, so a public server can still
+// put every discovery join through the admin queue.
 func (s *serverService) JoinPublicServer(ctx context.Context, userID, serverID string) (*JoinResult, error) {
 	server, err := s.serverRepo.GetActiveByID(ctx, serverID)
 	if err != nil {
@@ -782,7 +1208,7 @@ func (s *serverService) JoinPublicServer(ctx context.Context, userID, serverID s
 		return &JoinResult{Pending: true}, nil
 	}
 
-	joined, err := s.promoteToMember(ctx, server, userID, "", false)
+	joined, err := s.promoteToMember(ctx, server, userID, "", false, false)
 	if err != nil {
 		return nil, err
 	}
@@ -793,8 +1219,10 @@ func (s *serverService) JoinPublicServer(ctx context.Context, userID, serverID s
 // add membership, assign the default role, subscribe the socket, and broadcast join events.
 // consumeInvite is true for direct joins and false for admin approvals (admin is the gate).
 // The invite use is consumed only AFTER the atomic max_uses guard, and released back if the
-// membership add then fails (compensation). Returns the server with a signed icon URL.
-func (s *serverService) promoteToMember(ctx context.Context, server *models.Server, userID, inviteCode string, consumeInvite bool) (*models.Server, error) {
+// membership add then fails (compensation). temporary flags the membership for removal on
+// the user's last disconnect (set for joins via a temporary invite; approvals and public
+// joins are always permanent). Returns the server with a signed icon URL.
+func (s *serverService) promoteToMember(ctx context.Context, server *models.Server, userID, inviteCode string, consumeInvite, temporary bool) (*models.Server, error) {
 	serverID := server.ID
 
 	if consumeInvite {
@@ -812,6 +1240,13 @@ func (s *serverService) promoteToMember(ctx context.Context, server *models.Serv
 		return nil, fmt.Errorf("failed to add member: %w", err)
 	}
 
+	if temporary {
+		// Best-effort: a missed flag leaks a permanent membership, never removes one.
+		if err := s.serverRepo.MarkMemberTemporary(ctx, serverID, userID); err != nil {
+			log.Printf("[server] failed to mark temporary membership (server=%s user=%s): %v", serverID, userID, err)
+		}
+	}
+
 	// A member never keeps a pending join request. Clears any lingering request whether the user
 	// joined directly, approval was toggled off mid-request, or a request raced an approval. No-op
 	// in the approval path (ApproveRequest already claimed the row via its own delete).
@@ -834,7 +1269,7 @@ func (s *serverService) promoteToMember(ctx context.Context, server *models.Serv
 
 	// Notify user: server added to their list
 	s.hub.BroadcastToUser(userID, ws.Event{
-		Op: ws.OpServerCreate,
+		Op:   ws.OpServerCreate,
 		Data: serverListItemSigned(s.urlSigner, server),
 	})
 
@@ -855,12 +1290,39 @@ func (s *serverService) promoteToMember(ctx context.Context, server *models.Serv
 		})
 	}
 
+	// Drop a join notice into the server's default channel — the first text
+	// channel by position, the same one clients land on. Best-effort: a server
+	// with no text channel just doesn't get one.
+	if s.systemMessenger != nil {
+		if chID := s.defaultTextChannelID(ctx, serverID); chID != "" {
+			if _, err := s.systemMessenger.CreateSystemMessage(ctx, chID, userID, models.MessageTypeJoin, "joined the server", nil); err != nil {
+				log.Printf("[server] failed to post join message (server=%s user=%s): %v", serverID, userID, err)
+			}
+		}
+	}
+
 	log.Printf("[server] user %s became a member of server %s", userID, serverID)
 	server.IconURL = s.urlSigner.SignURLPtr(server.IconURL)
 	server.BannerURL = s.urlSigner.SignURLPtr(server.BannerURL)
 	return server, nil
 }
 
+// defaultTextChannelID returns the server's landing channel — the first text
+// channel by position — or "" when the server has none.
+func (s *serverService) defaultTextChannelID(ctx context.Context, serverID string) string {
+	channels, err := s.channelRepo.GetAllByServer(ctx, serverID)
+	if err != nil {
+		log.Printf("[server] failed to list channels for server %s: %v", serverID, err)
+		return ""
+	}
+	for _, ch := range channels {
+		if ch.Type == models.ChannelTypeText {
+			return ch.ID
+		}
+	}
+	return ""
+}
+
 // ApproveRequest promotes a pending requester to a member (perm-gated at the route).
 // Concurrency-safe: the request delete is the atomic claim — only the caller that actually
 // removes the row promotes the user, so two racing approvers can't double-add or double-broadcast.
@@ -878,7 +1340,7 @@ func (s *serverService) ApproveRequest(ctx context.Context, serverID, targetUser
 		return fmt.Errorf("%w: no pending join request", pkg.ErrNotFound)
 	}
 
-	if _, err := s.promoteToMember(ctx, server, targetUserID, "", false); err != nil {
+	if _, err := s.promoteToMember(ctx, server, targetUserID, "", false, false); err != nil {
 		// Compensation: we claimed (deleted) the request but couldn't add the member —
 		// restore it so the request isn't silently lost.
 		if reErr := s.joinRequestRepo.Create(ctx, serverID, targetUserID, ""); reErr != nil {
@@ -977,6 +1439,139 @@ func (s *serverService) LeaveServer(ctx context.Context, serverID, userID string
 	return nil
 }
 
+// TransferOwnership resolves the "owner cannot leave" dead end: verify the actor is the
+// owner and re-entered their password, verify the target is an active member, then swap
+// owner_id and the owner role atomically. Webhooks post without a user account, so they
+// can never resolve as a target.
+func (s *serverService) TransferOwnership(ctx context.Context, serverID, actorID string, req *models.TransferOwnershipRequest) (*models.Server, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	server, err := s.serverRepo.GetActiveByID(ctx, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if server.OwnerID != actorID {
+		return nil, fmt.Errorf("%w: only the server owner can transfer ownership", pkg.ErrForbidden)
+	}
+	if req.NewOwnerID == actorID {
+		return nil, fmt.Errorf("%w: you already own this server", pkg.ErrBadRequest)
+	}
+
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(actor.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, fmt.Errorf("%w: password is incorrect", pkg.ErrUnauthorized)
+	}
+
+	target, err := s.userRepo.GetActiveByID(ctx, req.NewOwnerID)
+	if err != nil {
+		if errors.Is(err, pkg.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user not found", pkg.ErrBadRequest)
+		}
+		return nil, err
+	}
+	isMember, err := s.serverRepo.IsMember(ctx, serverID, target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+	if !isMember {
+		return nil, fmt.Errorf("%w: the new owner must be a member of this server", pkg.ErrBadRequest)
+	}
+
+	if err := s.serverRepo.TransferOwnership(ctx, serverID, actorID, target.ID); err != nil {
+		return nil, err
+	}
+	server.OwnerID = target.ID
+
+	// Both role sets changed; stale cached perms must not survive the swap.
+	if s.permInvalidator != nil {
+		s.permInvalidator.InvalidateUserPermissions(actorID)
+		s.permInvalidator.InvalidateUserPermissions(target.ID)
+	}
+
+	server.IconURL = s.urlSigner.SignURLPtr(server.IconURL)
+	server.BannerURL = s.urlSigner.SignURLPtr(server.BannerURL)
+	s.hub.BroadcastToServer(serverID, ws.Event{
+		Op:   ws.OpServerUpdate,
+		Data: server,
+	})
+	if s.memberGetter != nil {
+		for _, uid := range []string{actorID, target.ID} {
+			member, mErr := s.memberGetter.GetByID(ctx, serverID, uid)
+			if mErr != nil {
+				log.Printf("[server] failed to load member %s after ownership transfer: %v", uid, mErr)
+				continue
+			}
+			s.hub.BroadcastToServer(serverID, ws.Event{
+				Op:   ws.OpMemberUpdate,
+				Data: member,
+			})
+		}
+	}
+
+	if s.audit != nil {
+		s.audit.Record(ctx, serverID, actorID, models.AuditActionOwnershipTransfer, target.ID, nil)
+	}
+
+	log.Printf("[server] ownership of server %s transferred from %s to %s", serverID, actorID, target.ID)
+	return server, nil
+}
+
+// SetVanityCode stores (or clears, for an empty code) the server's vanity join alias.
+// Uniqueness is enforced by the partial index on servers.vanity_code — the repo maps a
+// collision to ErrConflict. Returns the stored value for the handler to echo.
+func (s *serverService) SetVanityCode(ctx context.Context, serverID, code string) (*string, error) {
+	var value *string
+	if code != "" {
+		value = &code
+	}
+	if err := s.serverRepo.SetVanityCode(ctx, serverID, value); err != nil {
+		return nil, err
+	}
+	log.Printf("[server] vanity code for server %s set to %q", serverID, code)
+	return value, nil
+}
+
+// RemoveTemporaryMemberships drops the user's removable temporary memberships (see
+// ListRemovableTemporaryMemberships — a non-default role granted after joining makes the
+// membership permanent). Reuses LeaveServer so other members see the same OpMemberLeave
+// as a normal leave; per-server failures are logged and the rest still processed.
+func (s *serverService) RemoveTemporaryMemberships(ctx context.Context, userID string) error {
+	serverIDs, err := s.serverRepo.ListRemovableTemporaryMemberships(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list temporary memberships: %w", err)
+	}
+	for _, serverID := range serverIDs {
+		if err := s.LeaveServer(ctx, serverID, userID); err != nil {
+			log.Printf("[server] failed to remove temporary member (server=%s user=%s): %v", serverID, userID, err)
+			continue
+		}
+		log.Printf("[server] removed temporary member %s from server %s on disconnect", userID, serverID)
+	}
+	return nil
+}
+
+// SoftDeleteOwnedServers tears down the user's servers when their account goes away,
+// instead of leaving them ownerless for the recovery window. Going through DeleteServer
+// keeps the member broadcasts and voice teardown, and the servers land in the same
+// restorable soft-deleted state as an explicit delete.
+func (s *serverService) SoftDeleteOwnedServers(ctx context.Context, userID string) error {
+	serverIDs, err := s.serverRepo.ListActiveServerIDsByOwner(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list owned servers: %w", err)
+	}
+	for _, serverID := range serverIDs {
+		if err := s.DeleteServer(ctx, serverID, userID); err != nil {
+			log.Printf("[server] failed to soft-delete owned server %s for deleted account %s: %v", serverID, userID, err)
+		}
+	}
+	return nil
+}
+
 // GetLiveKitSettings returns non-secret LiveKit info for the settings page.
 func (s *serverService) GetLiveKitSettings(ctx context.Context, serverID string) (*LiveKitSettings, error) {
 	server, err := s.serverRepo.GetByID(ctx, serverID)
@@ -0,0 +1,308 @@
+// Package services — server structure templates.
+// Snapshot a server's categories, channels, roles and permission overrides
+// into a reusable document, and rebuild it as a new server inside the same
+// atomic transaction CreateServer uses.
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/akinalp/mqvi/database"
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/ws"
+)
+
+func (s *serverService) SaveTemplate(ctx context.Context, serverID, userID string, req *models.SaveTemplateRequest) (*models.ServerTemplate, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	categories, err := s.categoryRepo.GetAllByServer(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	channels, err := s.channelRepo.GetAllByServer(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channels: %w", err)
+	}
+	roles, err := s.roleRepo.GetAllByServer(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	structure := models.TemplateStructure{}
+
+	categoryIndex := make(map[string]int, len(categories))
+	for _, cat := range categories {
+		categoryIndex[cat.ID] = len(structure.Categories)
+		structure.Categories = append(structure.Categories, models.TemplateCategory{
+			Name:     cat.Name,
+			Position: cat.Position,
+		})
+	}
+
+	// The owner role is never captured — instantiation mints a fresh one for
+	// the new owner, so a template can't smuggle owner permissions around.
+	roleIndex := make(map[string]int, len(roles))
+	for _, role := range roles {
+		if role.IsOwner {
+			continue
+		}
+		roleIndex[role.ID] = len(structure.Roles)
+		structure.Roles = append(structure.Roles, models.TemplateRole{
+			Name:        role.Name,
+			Color:       role.Color,
+			Position:    role.Position,
+			Permissions: role.Permissions,
+			IsDefault:   role.IsDefault,
+			Mentionable: role.Mentionable,
+		})
+	}
+
+	for _, ch := range channels {
+		tc := models.TemplateChannel{
+			Name:               ch.Name,
+			Type:               ch.Type,
+			Topic:              ch.Topic,
+			Position:           ch.Position,
+			UserLimit:          ch.UserLimit,
+			Bitrate:            ch.Bitrate,
+			PinAnnouncements:   ch.PinAnnouncements,
+			SlowmodeSeconds:    ch.SlowmodeSeconds,
+			DeleteAfterSeconds: ch.DeleteAfterSeconds,
+		}
+		if ch.CategoryID != nil {
+			if idx, ok := categoryIndex[*ch.CategoryID]; ok {
+				tc.CategoryIndex = &idx
+			}
+		}
+		overrides, err := s.channelPermRepo.GetByChannel(ctx, ch.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channel overrides: %w", err)
+		}
+		for _, o := range overrides {
+			// Member overrides are tied to specific people, not structure — skipped.
+			if o.TargetType != models.OverrideTargetRole {
+				continue
+			}
+			// Overrides for the owner role (not snapshotted) are dropped with it.
+			if idx, ok := roleIndex[o.TargetID]; ok {
+				tc.Overrides = append(tc.Overrides, models.TemplateOverride{
+					RoleIndex: idx,
+					Allow:     o.Allow,
+					Deny:      o.Deny,
+				})
+			}
+		}
+		structure.Channels = append(structure.Channels, tc)
+	}
+
+	template := &models.ServerTemplate{
+		CreatorID: userID,
+		Name:      req.Name,
+		IsPublic:  req.IsPublic,
+		Structure: structure,
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[server] saved template %s (name=%s, server=%s, public=%t)",
+		template.ID, template.Name, serverID, template.IsPublic)
+
+	return template, nil
+}
+
+func (s *serverService) ListTemplates(ctx context.Context, userID string) ([]models.ServerTemplate, error) {
+	templates, err := s.templateRepo.ListVisible(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []models.ServerTemplate{}
+	}
+	return templates, nil
+}
+
+func (s *serverService) CreateFromTemplate(ctx context.Context, ownerID, templateID, name string) (*models.Server, error) {
+	template, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !template.IsPublic && template.CreatorID != ownerID {
+		return nil, fmt.Errorf("%w: template is private", pkg.ErrForbidden)
+	}
+
+	// Template servers are always mqvi-hosted; the request carries no LiveKit
+	// credentials. Validation and the hosted-ownership cap come from the same
+	// path a plain create uses.
+	req := &models.CreateServerRequest{Name: name, HostType: "mqvi_hosted"}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+	livekitInstanceID, err := s.reserveLiveKitInstance(ctx, ownerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &models.Server{
+		Name:              req.Name,
+		OwnerID:           ownerID,
+		IsPublic:          false,
+		LiveKitInstanceID: livekitInstanceID,
+	}
+
+	err = database.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		return s.provisionTemplateTx(ctx, tx, server, ownerID, &template.Structure)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server from template: %w", err)
+	}
+
+	s.hub.AddClientServerID(ownerID, server.ID)
+	s.hub.BroadcastToUser(ownerID, ws.Event{
+		Op:   ws.OpServerCreate,
+		Data: serverListItemSigned(s.urlSigner, server),
+	})
+
+	log.Printf("[server] created server %s (name=%s, owner=%s, template=%s)",
+		server.ID, server.Name, ownerID, templateID)
+
+	return server, nil
+}
+
+// provisionTemplateTx is provisionServerTx driven by template data: server row,
+// owner membership and a fresh owner role first, then the template's roles,
+// categories and channels with their overrides, all inside tx. The template's
+// default-role entry supplies the "everyone" permissions; a template without
+// one falls back to the instance default.
+func (s *serverService) provisionTemplateTx(ctx context.Context, tx *sql.Tx, server *models.Server, ownerID string, structure *models.TemplateStructure) error {
+	txServerRepo := repository.NewSQLiteServerRepo(tx)
+	txRoleRepo := repository.NewSQLiteRoleRepo(tx)
+	txChannelRepo := repository.NewSQLiteChannelRepo(tx)
+	txCategoryRepo := repository.NewSQLiteCategoryRepo(tx)
+	txPermRepo := repository.NewSQLiteChannelPermRepo(tx)
+
+	if err := txServerRepo.Create(ctx, server); err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	if err := txServerRepo.AddMember(ctx, server.ID, ownerID); err != nil {
+		return fmt.Errorf("failed to add owner as member: %w", err)
+	}
+
+	ownerRole := &models.Role{
+		ServerID:    server.ID,
+		Name:        "Owner",
+		Color:       "#E74C3C",
+		Position:    100,
+		Permissions: models.PermAll,
+		IsOwner:     true,
+		Mentionable: true,
+	}
+	if err := txRoleRepo.Create(ctx, ownerRole); err != nil {
+		return fmt.Errorf("failed to create owner role: %w", err)
+	}
+
+	var defaultRole *models.Role
+	roleIDs := make([]string, len(structure.Roles))
+	for i, tr := range structure.Roles {
+		role := &models.Role{
+			ServerID:    server.ID,
+			Name:        tr.Name,
+			Color:       tr.Color,
+			Position:    tr.Position,
+			Permissions: tr.Permissions,
+			IsDefault:   tr.IsDefault,
+			Mentionable: tr.Mentionable,
+		}
+		if err := txRoleRepo.Create(ctx, role); err != nil {
+			return fmt.Errorf("failed to create template role %q: %w", tr.Name, err)
+		}
+		roleIDs[i] = role.ID
+		if tr.IsDefault && defaultRole == nil {
+			defaultRole = role
+		}
+	}
+	if defaultRole == nil {
+		defaultRole = &models.Role{
+			ServerID:    server.ID,
+			Name:        "everyone",
+			Color:       "#99AAB5",
+			Position:    1,
+			Permissions: s.defaultMemberPerms,
+			IsDefault:   true,
+			Mentionable: true,
+		}
+		if err := txRoleRepo.Create(ctx, defaultRole); err != nil {
+			return fmt.Errorf("failed to create default role: %w", err)
+		}
+	}
+
+	if err := txRoleRepo.AssignToUser(ctx, ownerID, defaultRole.ID, server.ID); err != nil {
+		return fmt.Errorf("failed to assign default role to owner: %w", err)
+	}
+	if err := txRoleRepo.AssignToUser(ctx, ownerID, ownerRole.ID, server.ID); err != nil {
+		return fmt.Errorf("failed to assign owner role: %w", err)
+	}
+
+	categoryIDs := make([]string, len(structure.Categories))
+	for i, tc := range structure.Categories {
+		category := &models.Category{
+			ServerID: server.ID,
+			Name:     tc.Name,
+			Position: tc.Position,
+		}
+		if err := txCategoryRepo.Create(ctx, category); err != nil {
+			return fmt.Errorf("failed to create template category %q: %w", tc.Name, err)
+		}
+		categoryIDs[i] = category.ID
+	}
+
+	for _, tc := range structure.Channels {
+		channel := &models.Channel{
+			ServerID:           server.ID,
+			Name:               tc.Name,
+			Type:               tc.Type,
+			Topic:              tc.Topic,
+			Position:           tc.Position,
+			UserLimit:          tc.UserLimit,
+			Bitrate:            tc.Bitrate,
+			PinAnnouncements:   tc.PinAnnouncements,
+			SlowmodeSeconds:    tc.SlowmodeSeconds,
+			DeleteAfterSeconds: tc.DeleteAfterSeconds,
+		}
+		if tc.CategoryIndex != nil {
+			if *tc.CategoryIndex < 0 || *tc.CategoryIndex >= len(categoryIDs) {
+				return fmt.Errorf("%w: template structure references a missing category", pkg.ErrBadRequest)
+			}
+			channel.CategoryID = &categoryIDs[*tc.CategoryIndex]
+		}
+		if channel.Type == models.ChannelTypeVoice && channel.Bitrate == 0 {
+			channel.Bitrate = models.DefaultVoiceBitrate
+		}
+		if err := txChannelRepo.Create(ctx, channel); err != nil {
+			return fmt.Errorf("failed to create template channel %q: %w", tc.Name, err)
+		}
+		for _, o := range tc.Overrides {
+			if o.RoleIndex < 0 || o.RoleIndex >= len(roleIDs) {
+				return fmt.Errorf("%w: template structure references a missing role", pkg.ErrBadRequest)
+			}
+			if err := txPermRepo.Set(ctx, &models.ChannelPermissionOverride{
+				ChannelID:  channel.ID,
+				TargetType: models.OverrideTargetRole,
+				TargetID:   roleIDs[o.RoleIndex],
+				Allow:      o.Allow,
+				Deny:       o.Deny,
+			}); err != nil {
+				return fmt.Errorf("failed to copy template override: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
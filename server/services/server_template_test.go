@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+)
+
+// newTemplateService wires a ServerService over the fixture database —
+// snapshot and instantiation both cross the repository layer for real.
+func newTemplateService(f *dbtest.Fixture) ServerService {
+	return NewServerService(
+		f.DB,
+		repository.NewSQLiteServerRepo(f.DB),
+		repository.NewSQLiteLiveKitRepo(f.DB),
+		repository.NewSQLiteRoleRepo(f.DB),
+		repository.NewSQLiteChannelRepo(f.DB),
+		repository.NewSQLiteCategoryRepo(f.DB),
+		repository.NewSQLiteUserRepo(f.DB),
+		repository.NewSQLiteBanRepo(f.DB),
+		repository.NewSQLiteJoinRequestRepo(f.DB),
+		repository.NewSQLiteChannelPermRepo(f.DB),
+		repository.NewSQLiteServerTemplateRepo(f.DB),
+		repository.NewSQLiteDMRepo(f.DB),
+		nil,
+		stubHub{}, stubVoiceSync{}, nil, nil, stubSigner{}, nil,
+		models.PermDefaultMember, 100*1024*1024,
+	)
+}
+
+func TestServerTemplate_RoundTrip(t *testing.T) {
+	f := dbtest.New(t)
+	creator := f.User("")
+	svc := newTemplateService(f)
+
+	source, err := svc.CreateServer(context.Background(), creator, &models.CreateServerRequest{
+		Name: "source", HostType: "mqvi_hosted",
+	})
+	if err != nil {
+		t.Fatalf("create source server: %v", err)
+	}
+
+	// Give the source some non-default structure: a mod role with a channel
+	// override on #general.
+	roleRepo := repository.NewSQLiteRoleRepo(f.DB)
+	modRole := &models.Role{
+		ServerID:    source.ID,
+		Name:        "mods",
+		Color:       "#3498DB",
+		Position:    2,
+		Permissions: models.PermManageMessages,
+		Mentionable: true,
+	}
+	if err := roleRepo.Create(context.Background(), modRole); err != nil {
+		t.Fatalf("create mod role: %v", err)
+	}
+	var generalID string
+	if err := f.DB.QueryRow(
+		`SELECT id FROM channels WHERE server_id = ? AND name = 'general'`, source.ID,
+	).Scan(&generalID); err != nil {
+		t.Fatalf("find general channel: %v", err)
+	}
+	permRepo := repository.NewSQLiteChannelPermRepo(f.DB)
+	if err := permRepo.Set(context.Background(), &models.ChannelPermissionOverride{
+		ChannelID:  generalID,
+		TargetType: models.OverrideTargetRole,
+		TargetID:   modRole.ID,
+		Allow:      models.PermManageMessages,
+	}); err != nil {
+		t.Fatalf("set override: %v", err)
+	}
+
+	template, err := svc.SaveTemplate(context.Background(), source.ID, creator,
+		&models.SaveTemplateRequest{Name: "starter kit"})
+	if err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+	if got := len(template.Structure.Roles); got != 2 {
+		t.Errorf("snapshot roles = %d, want everyone + mods (owner must be skipped)", got)
+	}
+	if got := len(template.Structure.Channels); got != 2 {
+		t.Errorf("snapshot channels = %d, want 2", got)
+	}
+
+	server, err := svc.CreateFromTemplate(context.Background(), creator, template.ID, "copy")
+	if err != nil {
+		t.Fatalf("create from template: %v", err)
+	}
+
+	// Exactly one owner role, minted fresh — never inherited from the template.
+	var ownerRoles int
+	if err := f.DB.QueryRow(
+		`SELECT COUNT(*) FROM roles WHERE server_id = ? AND is_owner = 1`, server.ID,
+	).Scan(&ownerRoles); err != nil {
+		t.Fatalf("count owner roles: %v", err)
+	}
+	if ownerRoles != 1 {
+		t.Errorf("owner roles = %d, want exactly 1", ownerRoles)
+	}
+
+	// The mod role and its override survive with re-mapped IDs.
+	var newModID string
+	var modPerms int64
+	if err := f.DB.QueryRow(
+		`SELECT id, permissions FROM roles WHERE server_id = ? AND name = 'mods'`, server.ID,
+	).Scan(&newModID, &modPerms); err != nil {
+		t.Fatalf("find rebuilt mod role: %v", err)
+	}
+	if models.Permission(modPerms) != models.PermManageMessages {
+		t.Errorf("mod permissions = %d, want %d", modPerms, models.PermManageMessages)
+	}
+	var overrides int
+	if err := f.DB.QueryRow(
+		`SELECT COUNT(*) FROM channel_permissions cp JOIN channels c ON c.id = cp.channel_id
+		 WHERE c.server_id = ? AND c.name = 'general' AND cp.target_type = 'role' AND cp.target_id = ?`, server.ID, newModID,
+	).Scan(&overrides); err != nil {
+		t.Fatalf("count rebuilt overrides: %v", err)
+	}
+	if overrides != 1 {
+		t.Errorf("rebuilt overrides = %d, want 1", overrides)
+	}
+}
+
+func TestCreateFromTemplate_PrivateTemplateIsCreatorOnly(t *testing.T) {
+	f := dbtest.New(t)
+	creator := f.User("")
+	outsider := f.User("")
+	svc := newTemplateService(f)
+
+	source, err := svc.CreateServer(context.Background(), creator, &models.CreateServerRequest{
+		Name: "source", HostType: "mqvi_hosted",
+	})
+	if err != nil {
+		t.Fatalf("create source server: %v", err)
+	}
+	template, err := svc.SaveTemplate(context.Background(), source.ID, creator,
+		&models.SaveTemplateRequest{Name: "private"})
+	if err != nil {
+		t.Fatalf("save template: %v", err)
+	}
+
+	if _, err := svc.CreateFromTemplate(context.Background(), outsider, template.ID, "nope"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for someone else's private template, got %v", err)
+	}
+}
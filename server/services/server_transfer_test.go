@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil/dbtest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const transferTestPassword = "mavi kedi kahve iciyor"
+
+// seedOwnerPassword replaces the fixture's placeholder hash so password re-entry
+// can actually be verified.
+func seedOwnerPassword(t *testing.T, f *dbtest.Fixture, userID string) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(transferTestPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	if _, err := f.DB.ExecContext(context.Background(),
+		`UPDATE users SET password_hash = ? WHERE id = ?`, string(hash), userID); err != nil {
+		t.Fatalf("seed password: %v", err)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	f := dbtest.New(t)
+	svc := newTemplateService(f)
+	ctx := context.Background()
+
+	owner := f.User("")
+	seedOwnerPassword(t, f, owner)
+	server, err := svc.CreateServer(ctx, owner, &models.CreateServerRequest{
+		Name: "handover", HostType: "mqvi_hosted",
+	})
+	if err != nil {
+		t.Fatalf("create server: %v", err)
+	}
+
+	serverRepo := repository.NewSQLiteServerRepo(f.DB)
+	member := f.User("")
+	if err := serverRepo.AddMember(ctx, server.ID, member); err != nil {
+		t.Fatalf("add member: %v", err)
+	}
+	outsider := f.User("")
+
+	t.Run("wrong password is rejected", func(t *testing.T) {
+		_, err := svc.TransferOwnership(ctx, server.ID, owner, &models.TransferOwnershipRequest{
+			NewOwnerID: member, Password: "not the password",
+		})
+		if !errors.Is(err, pkg.ErrUnauthorized) {
+			t.Fatalf("TransferOwnership = %v, want ErrUnauthorized", err)
+		}
+	})
+
+	t.Run("non-member target is rejected", func(t *testing.T) {
+		_, err := svc.TransferOwnership(ctx, server.ID, owner, &models.TransferOwnershipRequest{
+			NewOwnerID: outsider, Password: transferTestPassword,
+		})
+		if !errors.Is(err, pkg.ErrBadRequest) {
+			t.Fatalf("TransferOwnership = %v, want ErrBadRequest", err)
+		}
+	})
+
+	t.Run("only the owner may transfer", func(t *testing.T) {
+		_, err := svc.TransferOwnership(ctx, server.ID, member, &models.TransferOwnershipRequest{
+			NewOwnerID: member, Password: transferTestPassword,
+		})
+		if !errors.Is(err, pkg.ErrForbidden) {
+			t.Fatalf("TransferOwnership = %v, want ErrForbidden", err)
+		}
+	})
+
+	t.Run("transfer moves owner_id and the owner role", func(t *testing.T) {
+		updated, err := svc.TransferOwnership(ctx, server.ID, owner, &models.TransferOwnershipRequest{
+			NewOwnerID: member, Password: transferTestPassword,
+		})
+		if err != nil {
+			t.Fatalf("TransferOwnership: %v", err)
+		}
+		if updated.OwnerID != member {
+			t.Errorf("returned OwnerID = %s, want %s", updated.OwnerID, member)
+		}
+
+		var ownerID string
+		if err := f.DB.QueryRowContext(ctx,
+			`SELECT owner_id FROM servers WHERE id = ?`, server.ID).Scan(&ownerID); err != nil {
+			t.Fatalf("read owner_id: %v", err)
+		}
+		if ownerID != member {
+			t.Errorf("stored owner_id = %s, want %s", ownerID, member)
+		}
+
+		rows, err := f.DB.QueryContext(ctx, `
+			SELECT ur.user_id FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE r.server_id = ? AND r.is_owner = 1`, server.ID)
+		if err != nil {
+			t.Fatalf("query owner role holders: %v", err)
+		}
+		defer rows.Close()
+		var holders []string
+		for rows.Next() {
+			var uid string
+			if err := rows.Scan(&uid); err != nil {
+				t.Fatalf("scan: %v", err)
+			}
+			holders = append(holders, uid)
+		}
+		if len(holders) != 1 || holders[0] != member {
+			t.Errorf("owner role holders = %v, want exactly [%s]", holders, member)
+		}
+
+		// The dead end this feature removes: the former owner can now leave.
+		if err := svc.LeaveServer(ctx, server.ID, owner); err != nil {
+			t.Errorf("former owner could not leave: %v", err)
+		}
+	})
+}
@@ -211,3 +211,23 @@ func TestPlay_RejectsASoundThatIsNotInTheNamedServer(t *testing.T) {
 		t.Fatal("a sound was played through a server it does not belong to")
 	}
 }
+
+// Sounds spam at channel granularity, so the limiter does too: once the channel's
+// burst is spent, the next play is refused no matter who asks.
+func TestPlay_PerChannelRateLimit(t *testing.T) {
+	svc, hub := sbService(map[string]models.Permission{"chan-B": models.PermUseSoundboard}, true)
+
+	for i := 0; i < soundboardPlayBurst; i++ {
+		if err := svc.Play(context.Background(), "server-A", "snd", "u1", "u1"); err != nil {
+			t.Fatalf("play %d within burst: %v", i+1, err)
+		}
+	}
+
+	err := svc.Play(context.Background(), "server-A", "snd", "u1", "u1")
+	if !errors.Is(err, pkg.ErrRateLimited) {
+		t.Fatalf("play past the burst got %v, want ErrRateLimited", err)
+	}
+	if hub.plays() != soundboardPlayBurst {
+		t.Fatalf("the channel heard %d sounds, want exactly the burst of %d", hub.plays(), soundboardPlayBurst)
+	}
+}
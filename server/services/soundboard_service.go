@@ -8,10 +8,12 @@ import (
 	"log"
 	"mime/multipart"
 	"strings"
+	"sync"
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/pkg/files"
+	"github.com/akinalp/mqvi/pkg/ratelimit"
 	"github.com/akinalp/mqvi/repository"
 	"github.com/akinalp/mqvi/ws"
 	"github.com/google/uuid"
@@ -20,6 +22,11 @@ import (
 const (
 	maxSoundDurationMs = 7000 // 7 seconds
 	maxSoundsPerServer = 50
+
+	// Play rate limit, per CHANNEL rather than per user: five people taking turns
+	// with the airhorn are exactly as disruptive as one person mashing it.
+	soundboardPlayBurst  = 3
+	soundboardPlayPerSec = 0.5 // one sound every 2s sustained
 )
 
 // Soundboard uploads are WAV only: the client trims + encodes the selected
@@ -166,6 +173,12 @@ type soundboardService struct {
 	maxSize        int64
 	urlSigner      FileURLSigner
 	storageService StorageService
+
+	// playMu guards playLimiters: one small bucket per voice channel sounds have
+	// been played into, created lazily on first play. Entries are a few dozen
+	// bytes, bounded by the number of channels, so no cleanup pass is needed.
+	playMu       sync.Mutex
+	playLimiters map[string]*ratelimit.TokenBucket
 }
 
 func NewSoundboardService(
@@ -373,6 +386,22 @@ func (s *soundboardService) Delete(ctx context.Context, serverID string, id stri
 	return nil
 }
 
+// allowPlay consumes one token from the channel's play bucket, creating it on
+// first use.
+func (s *soundboardService) allowPlay(channelID string) bool {
+	s.playMu.Lock()
+	defer s.playMu.Unlock()
+	if s.playLimiters == nil {
+		s.playLimiters = make(map[string]*ratelimit.TokenBucket)
+	}
+	tb, ok := s.playLimiters[channelID]
+	if !ok {
+		tb = ratelimit.NewTokenBucket(soundboardPlayBurst, soundboardPlayPerSec)
+		s.playLimiters[channelID] = tb
+	}
+	return tb.Allow()
+}
+
 func (s *soundboardService) Play(ctx context.Context, serverID, soundID, userID, username string) error {
 	// User must be in a voice channel
 	voiceState := s.voice.GetUserVoiceState(userID)
@@ -405,6 +434,10 @@ func (s *soundboardService) Play(ctx context.Context, serverID, soundID, userID,
 		return fmt.Errorf("%w: you cannot use the soundboard in this voice channel", pkg.ErrForbidden)
 	}
 
+	if !s.allowPlay(voiceState.ChannelID) {
+		return fmt.Errorf("%w: the soundboard in this channel needs a moment to cool down", pkg.ErrRateLimited)
+	}
+
 	// Broadcast only to users in the same voice channel
 	participants := s.voice.GetChannelParticipants(voiceState.ChannelID)
 	userIDs := make([]string, 0, len(participants))
@@ -88,7 +88,7 @@ func newPipelineForTest(t *testing.T, scanner *fakeScanner, cache *fakeScanCache
 	if cfg.CircuitOpenSeconds == 0 {
 		cfg.CircuitOpenSeconds = 10
 	}
-	return NewUploadPipeline(files.NewLocator(t.TempDir(), ""), scanner, cache, nil, cfg)
+	return NewUploadPipeline(files.NewLocator(t.TempDir(), "", 0), scanner, cache, nil, cfg)
 }
 
 func storeTestFile(t *testing.T, pipeline UploadPipeline, name string, body []byte, size int64) (*StoredUpload, error) {
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+// UploadPolicyResolver is the message handler's view of per-server upload
+// settings. Implemented by ServerService.
+type UploadPolicyResolver interface {
+	UploadPolicy(ctx context.Context, serverID string) (models.UploadPolicy, error)
+}
+
+// checkUploadType rejects a file the policy's type rules forbid. The declared
+// Content-Type must pass the full policy; the content-sniffed type (first 512
+// bytes) is additionally held against the blocklist, so a blocked type cannot
+// slip through by being renamed and re-labelled. Sniffing is not used for the
+// allowlist — formats the sniffer cannot identify (office documents, archives)
+// would fail an allowlist they legitimately appear on.
+//
+// E2EE uploads are exempt: ciphertext sniffs as octet-stream regardless of
+// what is inside, and the declared type is already opaque.
+func checkUploadType(file multipart.File, header *multipart.FileHeader, mimeBase string, policy models.UploadPolicy, isEncrypted bool) error {
+	if isEncrypted || (len(policy.AllowedTypes) == 0 && len(policy.BlockedTypes) == 0) {
+		return nil
+	}
+
+	if !policy.TypeAllowed(mimeBase, header.Filename) {
+		return fmt.Errorf("%w: file type %q is not allowed on this server", pkg.ErrBadRequest, mimeBase)
+	}
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("failed to read file header: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind file after sniffing: %w", err)
+	}
+
+	sniffed := strings.TrimSpace(strings.Split(http.DetectContentType(buf[:n]), ";")[0])
+	blockOnly := models.UploadPolicy{BlockedTypes: policy.BlockedTypes}
+	if !blockOnly.TypeAllowed(sniffed, header.Filename) {
+		return fmt.Errorf("%w: file content type %q is not allowed on this server", pkg.ErrBadRequest, sniffed)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+)
+
+func sniffTestFile(content string) (multipart.File, *multipart.FileHeader) {
+	return memoryFile{bytes.NewReader([]byte(content))}, &multipart.FileHeader{
+		Filename: "upload.bin",
+		Size:     int64(len(content)),
+	}
+}
+
+func TestCheckUploadType(t *testing.T) {
+	htmlBody := "<!DOCTYPE html><html><body>hi</body></html>"
+
+	t.Run("no rules is a no-op", func(t *testing.T) {
+		file, header := sniffTestFile(htmlBody)
+		if err := checkUploadType(file, header, "text/html", models.UploadPolicy{}, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("declared type on the blocklist is rejected", func(t *testing.T) {
+		file, header := sniffTestFile("plain text")
+		policy := models.UploadPolicy{BlockedTypes: []string{"text/html"}}
+		err := checkUploadType(file, header, "text/html", policy, false)
+		if !errors.Is(err, pkg.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest, got %v", err)
+		}
+	})
+
+	t.Run("renamed blocked content is caught by sniffing", func(t *testing.T) {
+		file, header := sniffTestFile(htmlBody)
+		policy := models.UploadPolicy{BlockedTypes: []string{"text/html"}}
+		// Declared as plain text — the content still sniffs as HTML.
+		err := checkUploadType(file, header, "text/plain", policy, false)
+		if !errors.Is(err, pkg.ErrBadRequest) {
+			t.Fatalf("expected ErrBadRequest from sniffed type, got %v", err)
+		}
+	})
+
+	t.Run("sniffing does not enforce the allowlist", func(t *testing.T) {
+		// Office-style formats sniff as zip/octet-stream; only the declared
+		// type is held against the allowlist.
+		file, header := sniffTestFile("PK\x03\x04 not really a zip")
+		policy := models.UploadPolicy{AllowedTypes: []string{"application/vnd.ms-excel"}}
+		if err := checkUploadType(file, header, "application/vnd.ms-excel", policy, false); err != nil {
+			t.Fatalf("allowlisted declared type rejected: %v", err)
+		}
+	})
+
+	t.Run("encrypted uploads are exempt", func(t *testing.T) {
+		file, header := sniffTestFile(htmlBody)
+		policy := models.UploadPolicy{BlockedTypes: []string{"text/html", "application/octet-stream"}}
+		if err := checkUploadType(file, header, "application/octet-stream", policy, true); err != nil {
+			t.Fatalf("E2EE upload must bypass type rules, got %v", err)
+		}
+	})
+
+	t.Run("file is rewound after sniffing", func(t *testing.T) {
+		file, header := sniffTestFile(htmlBody)
+		policy := models.UploadPolicy{AllowedTypes: []string{"text/*"}}
+		if err := checkUploadType(file, header, "text/html", policy, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rest := new(strings.Builder)
+		if _, err := file.(memoryFile).WriteTo(rest); err != nil {
+			t.Fatalf("read back: %v", err)
+		}
+		if rest.String() != htmlBody {
+			t.Fatal("file position not reset — the stored file would be truncated")
+		}
+	})
+}
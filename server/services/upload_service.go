@@ -1,9 +1,13 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"mime/multipart"
+	"net/textproto"
 	"strings"
 
 	"github.com/akinalp/mqvi/models"
@@ -16,33 +20,43 @@ import (
 // isEncrypted: E2EE files are client-side AES-256-GCM encrypted, sent as
 // application/octet-stream — MIME whitelist is skipped for these.
 type UploadService interface {
-	Upload(ctx context.Context, messageID string, file multipart.File, header *multipart.FileHeader, isEncrypted bool, thumb *ThumbnailUpload) (*models.Attachment, error)
+	// Upload stores one attachment. policy carries the server's resolved upload
+	// limits (see ServerService.UploadPolicy); a zero-value policy falls back
+	// to the platform defaults.
+	Upload(ctx context.Context, messageID string, file multipart.File, header *multipart.FileHeader, isEncrypted bool, thumb *ThumbnailUpload, policy models.UploadPolicy) (*models.Attachment, error)
 }
 
 type uploadService struct {
 	attachmentRepo repository.AttachmentRepository
 	pipeline       UploadPipeline
 	maxSize        int64
+	imageLimits    files.ImageLimits
 }
 
 func NewUploadService(
 	attachmentRepo repository.AttachmentRepository,
 	pipeline UploadPipeline,
 	maxSize int64,
+	imageLimits files.ImageLimits,
 ) UploadService {
 	return &uploadService{
 		attachmentRepo: attachmentRepo,
 		pipeline:       pipeline,
 		maxSize:        maxSize,
+		imageLimits:    imageLimits,
 	}
 }
 
 // All file types are accepted on upload. XSS protection is handled at serve-time
 // via Content-Disposition: attachment for non-media types (see pkg/files/safemime.go).
 
-func (s *uploadService) Upload(ctx context.Context, messageID string, file multipart.File, header *multipart.FileHeader, isEncrypted bool, thumb *ThumbnailUpload) (*models.Attachment, error) {
-	if header.Size > s.maxSize {
-		return nil, fmt.Errorf("%w: file too large (max %dMB)", pkg.ErrBadRequest, s.maxSize/(1024*1024))
+func (s *uploadService) Upload(ctx context.Context, messageID string, file multipart.File, header *multipart.FileHeader, isEncrypted bool, thumb *ThumbnailUpload, policy models.UploadPolicy) (*models.Attachment, error) {
+	maxSize := policy.MaxSize
+	if maxSize == 0 {
+		maxSize = s.maxSize
+	}
+	if header.Size > maxSize {
+		return nil, fmt.Errorf("%w: file too large (this server allows at most %dMB)", pkg.ErrBadRequest, maxSize/(1024*1024))
 	}
 
 	contentType := header.Header.Get("Content-Type")
@@ -52,10 +66,24 @@ func (s *uploadService) Upload(ctx context.Context, messageID string, file multi
 	mimeBase := strings.Split(contentType, ";")[0]
 	mimeBase = strings.TrimSpace(mimeBase)
 
-	// No upload-time MIME restriction — serve-time handles XSS prevention.
-	_ = isEncrypted
+	// Beyond the server's own type rules there is no upload-time MIME
+	// restriction — serve-time handles XSS prevention.
+	if err := checkUploadType(file, header, mimeBase, policy, isEncrypted); err != nil {
+		return nil, err
+	}
 
-	stored, err := s.pipeline.Store(ctx, files.KindMessage, messageID, file, header, s.maxSize)
+	// Dimension check on plaintext images only — E2EE uploads are ciphertext,
+	// their headers can't be read here (the client enforces its own limits).
+	if !isEncrypted && strings.HasPrefix(mimeBase, "image/") {
+		if err := s.imageLimits.Check(file); err != nil {
+			if errors.Is(err, files.ErrImageTooLarge) {
+				return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+			}
+			return nil, err
+		}
+	}
+
+	stored, err := s.pipeline.Store(ctx, files.KindMessage, messageID, file, header, maxSize)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +96,12 @@ func (s *uploadService) Upload(ctx context.Context, messageID string, file multi
 		FileSize:  &fileSize,
 		MimeType:  &mimeBase,
 	}
+	// Server-side fallback for clients that sent no preview: downscale
+	// plaintext JPEG/PNG here. E2EE uploads are ciphertext and can't be
+	// decoded; other types simply store no thumbnail.
+	if thumb == nil && !isEncrypted {
+		thumb = generateThumbnail(file, header.Filename, mimeBase)
+	}
 	if t := storeThumbnail(ctx, s.pipeline, files.KindMessage, messageID, thumb); t != nil {
 		attachment.ThumbURL, attachment.ThumbWidth, attachment.ThumbHeight = &t.URL, t.Width, t.Height
 		attachment.ThumbSize = &t.Size
@@ -85,3 +119,35 @@ func (s *uploadService) Upload(ctx context.Context, messageID string, file multi
 
 	return attachment, nil
 }
+
+// generateThumbnail downscales the stored original into a companion preview,
+// packaged as a ThumbnailUpload so it rides the same store/quota/delete path
+// as a client-supplied one. Any failure is logged and swallowed — the preview
+// is an optimisation, the file is the message.
+func generateThumbnail(file multipart.File, filename, mimeType string) *ThumbnailUpload {
+	data, width, height, err := files.MakeThumbnail(file, mimeType)
+	if err != nil {
+		log.Printf("[upload] thumbnail generation failed for %s: %v — attachment kept without one", filename, err)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+
+	header := &multipart.FileHeader{
+		Filename: "thumb_" + filename,
+		Size:     int64(len(data)),
+		Header:   textproto.MIMEHeader{"Content-Type": []string{mimeType}},
+	}
+	return &ThumbnailUpload{
+		File:   memoryFile{bytes.NewReader(data)},
+		Header: header,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// memoryFile adapts an in-memory buffer to multipart.File for the pipeline.
+type memoryFile struct{ *bytes.Reader }
+
+func (memoryFile) Close() error { return nil }
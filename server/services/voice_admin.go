@@ -68,18 +68,19 @@ func (s *voiceService) AdminUpdateState(ctx context.Context, adminUserID, target
 	newServerMuted := state.IsServerMuted
 	muteChanged := isServerMuted != nil
 	broadcast := ws.VoiceStateUpdateBroadcast{
-		UserID:           state.UserID,
-		ChannelID:        state.ChannelID,
-		Username:         state.Username,
-		DisplayName:      state.DisplayName,
-		AvatarURL:        s.urlSigner.SignURL(state.AvatarURL),
-		IsMuted:          state.IsMuted,
-		IsDeafened:       state.IsDeafened,
-		IsStreaming:      state.IsStreaming,
-		ShareQuality:     state.ShareQuality,
-		IsServerMuted:    state.IsServerMuted,
-		IsServerDeafened: state.IsServerDeafened,
-		Action:           "update",
+		UserID:            state.UserID,
+		ChannelID:         state.ChannelID,
+		Username:          state.Username,
+		DisplayName:       state.DisplayName,
+		AvatarURL:         s.urlSigner.SignURL(state.AvatarURL),
+		IsMuted:           state.IsMuted,
+		IsDeafened:        state.IsDeafened,
+		IsStreaming:       state.IsStreaming,
+		ShareQuality:      state.ShareQuality,
+		IsServerMuted:     state.IsServerMuted,
+		IsServerDeafened:  state.IsServerDeafened,
+		IsPrioritySpeaker: state.IsPrioritySpeaker,
+		Action:            "update",
 	}
 
 	s.mu.Unlock()
@@ -101,7 +102,8 @@ func (s *voiceService) AdminUpdateState(ctx context.Context, adminUserID, target
 				"channel_id": channelID, "error": permErr.Error(),
 			})
 		} else {
-			s.enforceServerMicMuteAtSFU(serverID, channelID, targetUserID, newServerMuted, targetPerms.Has(models.PermSpeak))
+			s.enforceServerMicMuteAtSFU(serverID, channelID, targetUserID, newServerMuted,
+				targetPerms.Has(models.PermSpeak), targetPerms.Has(models.PermVideo))
 		}
 	}
 
@@ -112,32 +114,100 @@ func (s *voiceService) AdminUpdateState(ctx context.Context, adminUserID, target
 	return nil
 }
 
-// buildServerMutePermission returns the LiveKit participant permission for a server-mute
-// state change. Server-mute revokes only the MICROPHONE publish source — camera and screen
-// share stay publishable (Discord-style: mute is audio-only). Gated on canSpeak so muting
-// never grants publish to a user who couldn't publish. Subscribe stays enabled; server-
-// deafen is client-side, not SFU-enforced.
-// serverMuteAllowedSources is every publishable source EXCEPT microphone — the allow-list
-// applied to a server-muted user (audio-only mute; camera + screen share stay publishable).
+// SetPrioritySpeaker toggles the caller's own priority-speaker flag.
+// Same lock discipline as AdminUpdateState: snapshot the channel briefly, resolve
+// the permission outside s.mu, then re-lock and re-verify before mutating. No SFU
+// call — priority speaking is a broadcast-only hint that clients use for ducking.
+func (s *voiceService) SetPrioritySpeaker(ctx context.Context, userID string, isPriority bool) error {
+	s.mu.Lock()
+	state, ok := s.states[userID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: not in a voice channel", pkg.ErrBadRequest)
+	}
+	channelID := state.ChannelID
+	s.mu.Unlock()
+
+	effectivePerms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, channelID)
+	if err != nil {
+		s.logError(models.LogCategoryVoice, &userID, "SetPrioritySpeaker: permission resolve failed", map[string]string{
+			"channel_id": channelID, "error": err.Error(),
+		})
+		return fmt.Errorf("failed to resolve permissions: %w", err)
+	}
+	if !effectivePerms.Has(models.PermPrioritySpeaker) {
+		return fmt.Errorf("%w: priority speaker permission required", pkg.ErrForbidden)
+	}
+
+	s.mu.Lock()
+	state, ok = s.states[userID]
+	if !ok || state.ChannelID != channelID {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: not in a voice channel", pkg.ErrBadRequest)
+	}
+
+	state.IsPrioritySpeaker = isPriority
+
+	serverID := state.ServerID
+	broadcast := ws.VoiceStateUpdateBroadcast{
+		UserID:            state.UserID,
+		ChannelID:         state.ChannelID,
+		Username:          state.Username,
+		DisplayName:       state.DisplayName,
+		AvatarURL:         s.urlSigner.SignURL(state.AvatarURL),
+		IsMuted:           state.IsMuted,
+		IsDeafened:        state.IsDeafened,
+		IsStreaming:       state.IsStreaming,
+		ShareQuality:      state.ShareQuality,
+		IsServerMuted:     state.IsServerMuted,
+		IsServerDeafened:  state.IsServerDeafened,
+		IsPrioritySpeaker: state.IsPrioritySpeaker,
+		Action:            "update",
+	}
+	s.mu.Unlock()
+
+	s.broadcastToServer(serverID, ws.Event{Op: ws.OpVoiceStateUpdate, Data: broadcast})
+
+	log.Printf("[voice] user %s set priority speaker=%v in channel %s", userID, isPriority, channelID)
+	return nil
+}
+
+// allowedPublishSources is the publish source allow-list for a user who holds Speak, or
+// nil when no restriction applies. Server-mute removes the MICROPHONE source (audio-only
+// mute; Discord-style); a missing PermVideo removes the CAMERA source. Screen share is
+// gated separately by PermStream and always stays in the list so the allow-list is never
+// empty (an empty list reads as "unrestricted" to LiveKit).
 // Shared by the live SFU enforcement (buildServerMutePermission) and the token baking in
 // GenerateToken so both stay in lockstep.
-func serverMuteAllowedSources() []livekit.TrackSource {
-	return []livekit.TrackSource{
-		livekit.TrackSource_CAMERA,
+func allowedPublishSources(serverMuted, canVideo bool) []livekit.TrackSource {
+	if !serverMuted && canVideo {
+		return nil
+	}
+	sources := []livekit.TrackSource{
 		livekit.TrackSource_SCREEN_SHARE,
 		livekit.TrackSource_SCREEN_SHARE_AUDIO,
 	}
+	if !serverMuted {
+		sources = append(sources, livekit.TrackSource_MICROPHONE)
+	}
+	if canVideo {
+		sources = append(sources, livekit.TrackSource_CAMERA)
+	}
+	return sources
 }
 
-func buildServerMutePermission(muted, canSpeak bool) *livekit.ParticipantPermission {
+// buildServerMutePermission returns the LiveKit participant permission for a server-mute
+// state change. Gated on canSpeak so muting never grants publish to a user who couldn't
+// publish. Subscribe stays enabled; server-deafen is client-side, not SFU-enforced.
+func buildServerMutePermission(muted, canSpeak, canVideo bool) *livekit.ParticipantPermission {
 	perm := &livekit.ParticipantPermission{
 		CanSubscribe:   true,
 		CanPublish:     canSpeak,
 		CanPublishData: true,
 	}
-	// CanPublishSources supersedes CanPublish: allow every source EXCEPT microphone.
-	if muted && canSpeak {
-		perm.CanPublishSources = serverMuteAllowedSources()
+	// CanPublishSources supersedes CanPublish: only set it for users who can publish at all.
+	if canSpeak {
+		perm.CanPublishSources = allowedPublishSources(muted, canVideo)
 	}
 	return perm
 }
@@ -155,7 +225,7 @@ const (
 // participant's publish permission. A non-cooperating client cannot bypass it: revoking
 // the microphone source unpublishes any live mic track and blocks republishing.
 // Best-effort with bounded retry: errors are logged, not propagated. MUST NOT be called under mu.Lock.
-func (s *voiceService) enforceServerMicMuteAtSFU(serverID, channelID, userID string, muted, canSpeak bool) {
+func (s *voiceService) enforceServerMicMuteAtSFU(serverID, channelID, userID string, muted, canSpeak, canVideo bool) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -172,7 +242,7 @@ func (s *voiceService) enforceServerMicMuteAtSFU(serverID, channelID, userID str
 	req := &livekit.UpdateParticipantRequest{
 		Room:       roomName,
 		Identity:   userID,
-		Permission: buildServerMutePermission(muted, canSpeak),
+		Permission: buildServerMutePermission(muted, canSpeak, canVideo),
 	}
 
 	for attempt := 1; attempt <= sfuMuteMaxAttempts; attempt++ {
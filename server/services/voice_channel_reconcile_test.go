@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+	"github.com/akinalp/mqvi/ws"
+)
+
+// The deleted-channel sweep must reap only states whose channel row is confirmed
+// gone, emit the standard leave broadcast, and stop the channel timer it empties.
+func TestSweepDeletedChannels_ReapsStatesForMissingChannels(t *testing.T) {
+	svc, broadcasts := newTimerTestVoiceService(nil, models.PermConnectVoice)
+
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch-deleted", false, false)
+	_ = svc.JoinChannel("u2", "bob", "Bob", "", "", "ch-alive", false, false)
+
+	// Simulate the channel row vanishing after the joins.
+	svc.channelGetter = &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+			if id == "ch-deleted" {
+				return nil, pkg.ErrNotFound
+			}
+			return &models.Channel{ID: id, ServerID: "srv1", Type: models.ChannelTypeVoice}, nil
+		},
+	}
+
+	svc.sweepDeletedChannels()
+
+	if svc.GetUserVoiceState("u1") != nil {
+		t.Fatal("expected u1 reaped — their channel no longer exists")
+	}
+	if svc.GetUserVoiceState("u2") == nil {
+		t.Fatal("u2's channel still exists — their state must survive the sweep")
+	}
+	if got := countChannelTimerEvents(*broadcasts, ws.OpVoiceChannelTimerStop, "ch-deleted"); got != 1 {
+		t.Fatalf("expected 1 timer-stop for the deleted channel, got %d", got)
+	}
+
+	leaves := 0
+	for _, e := range *broadcasts {
+		if e.Op != ws.OpVoiceStateUpdate {
+			continue
+		}
+		if d, ok := e.Data.(ws.VoiceStateUpdateBroadcast); ok && d.Action == "leave" && d.ChannelID == "ch-deleted" {
+			leaves++
+		}
+	}
+	if leaves != 1 {
+		t.Fatalf("expected exactly 1 leave broadcast for the deleted channel, got %d", leaves)
+	}
+}
+
+// A transient lookup failure is not evidence the channel is gone — the sweep must
+// leave the state alone and retry on the next tick.
+func TestSweepDeletedChannels_TransientErrorDoesNotReap(t *testing.T) {
+	svc, _ := newTimerTestVoiceService(nil, models.PermConnectVoice)
+
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
+
+	svc.channelGetter = &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, _ string) (*models.Channel, error) {
+			return nil, fmt.Errorf("db locked")
+		},
+	}
+
+	svc.sweepDeletedChannels()
+
+	if svc.GetUserVoiceState("u1") == nil {
+		t.Fatal("transient lookup failure must never reap voice state")
+	}
+}
@@ -5,13 +5,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	"github.com/akinalp/mqvi/models"
-	"github.com/akinalp/mqvi/pkg/crypto"
+	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/ws"
 
 	livekit "github.com/livekit/protocol/livekit"
@@ -181,11 +182,11 @@ func (s *voiceService) newLiveKitRoomClient(ctx context.Context, serverID string
 	if err != nil {
 		return nil, fmt.Errorf("livekit instance lookup for server %s: %w", serverID, err)
 	}
-	apiKey, err := crypto.Decrypt(lkInstance.APIKey, s.encryptionKey)
+	apiKey, err := s.keychain.Decrypt(lkInstance.APIKey)
 	if err != nil {
 		return nil, fmt.Errorf("api key decrypt: %w", err)
 	}
-	apiSecret, err := crypto.Decrypt(lkInstance.APISecret, s.encryptionKey)
+	apiSecret, err := s.keychain.Decrypt(lkInstance.APISecret)
 	if err != nil {
 		return nil, fmt.Errorf("api secret decrypt: %w", err)
 	}
@@ -522,3 +523,111 @@ func (s *voiceService) sweepLiveKitReconciliation() {
 
 	s.mu.Unlock()
 }
+
+// StartChannelReconciliation periodically verifies that every in-memory voice state
+// still points at an existing channel, and reaps states left behind when a channel
+// was deleted without its participants being cleared. Defense-in-depth alongside the
+// LiveKit reconciliation: that sweep needs the SFU to agree the user is gone, while
+// this one catches states whose channel row simply no longer exists (e.g. a delete
+// path that skipped the participant teardown). Interval comes from config.
+func (s *voiceService) StartChannelReconciliation(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.sweepDeletedChannels()
+		}
+	}()
+}
+
+// sweepDeletedChannels reaps voice states pointing at channels that no longer exist.
+// Same three-phase shape as the other sweeps: snapshot channels under RLock, check
+// existence against the repository without the lock, reap under Lock. Only a definite
+// not-found removes state — any other lookup error skips the channel, so a transient
+// DB failure can never trigger a false reap. No grace period: a deleted channel row
+// cannot come back, so there is no transient state to forgive.
+func (s *voiceService) sweepDeletedChannels() {
+	// Phase 1: snapshot distinct channels currently holding voice state.
+	channelServers := make(map[string]string) // channelID -> serverID
+	s.mu.RLock()
+	for _, st := range s.states {
+		channelServers[st.ChannelID] = st.ServerID
+	}
+	s.mu.RUnlock()
+
+	if len(channelServers) == 0 {
+		return
+	}
+
+	// Phase 2: check existence without the lock (DB lookups).
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deleted := make(map[string]bool)
+	for channelID := range channelServers {
+		if _, err := s.channelGetter.GetByID(ctx, channelID); err != nil {
+			if errors.Is(err, pkg.ErrNotFound) {
+				deleted[channelID] = true
+			} else {
+				log.Printf("[voice] channel reconcile: lookup failed channel=%s: %v", channelID, err)
+			}
+		}
+	}
+
+	if len(deleted) == 0 {
+		return
+	}
+
+	// Phase 3: reap under Lock. Same leave broadcast the orphan sweep emits, so
+	// clients correct their sidebars without any new event shape.
+	var reaped []orphanEntry
+
+	s.mu.Lock()
+	for userID, state := range s.states {
+		if !deleted[state.ChannelID] {
+			continue
+		}
+
+		channelID := state.ChannelID
+		serverID := state.ServerID
+		username := state.Username
+		displayName := state.DisplayName
+		avatarURL := s.urlSigner.SignURL(state.AvatarURL)
+		delete(s.states, userID)
+		delete(s.offlineSince, userID)
+		delete(s.livekitAbsentSince, userID)
+
+		s.broadcastToServer(serverID, ws.Event{
+			Op: ws.OpVoiceStateUpdate,
+			Data: ws.VoiceStateUpdateBroadcast{
+				UserID:      userID,
+				ChannelID:   channelID,
+				Username:    username,
+				DisplayName: displayName,
+				AvatarURL:   avatarURL,
+				Action:      "leave",
+			},
+		})
+
+		if s.countInChannelLocked(channelID) == 0 {
+			s.stopChannelTimerLocked(channelID, serverID)
+		}
+		s.cleanupRoomPassphraseIfEmpty(channelID)
+
+		reaped = append(reaped, orphanEntry{userID: userID, channelID: channelID, serverID: serverID})
+		log.Printf("[voice] channel reconcile: removed user %s from deleted channel %s", userID, channelID)
+		s.logWarn(models.LogCategoryVoice, &userID, "channel reconcile: voice state pointed at deleted channel", map[string]string{
+			"channel_id": channelID,
+			"server_id":  serverID,
+		})
+	}
+	s.mu.Unlock()
+
+	// LiveKit cleanup outside lock — the room may still exist on the SFU even
+	// though the channel row is gone (serverID was captured from the state, so
+	// removal works after the delete; see removeParticipantFromLiveKit).
+	for _, r := range reaped {
+		s.removeParticipantFromLiveKit(r.serverID, r.channelID, r.userID)
+	}
+}
@@ -1,7 +1,9 @@
-// Package services — ephemeral voice channel chat.
-// Access is gated to users currently in the target voice channel. The entire
-// chat (DB rows + on-disk attachment files) is wiped via CleanupChannel when
-// the last participant leaves (called from voiceService.stopChannelTimerLocked).
+// Package services — voice channel text chat.
+// Writing is gated to users currently in the target voice channel; reading is
+// open to anyone who is either connected or holds PermViewChannel on the
+// channel, so history survives after everyone disconnects. CleanupChannel
+// wipes the chat (DB rows + on-disk attachment files) when the channel itself
+// is deleted.
 package services
 
 import (
@@ -34,13 +36,15 @@ type VoiceMessageService interface {
 	AttachFile(ctx context.Context, messageID, filename, fileURL string, fileSize int64, mimeType *string) (*models.VoiceMessageAttachment, error)
 	// BroadcastCreate publishes a fully-enriched create event after attachments are attached.
 	BroadcastCreate(ctx context.Context, message *models.VoiceMessage)
-	// CleanupChannel wipes every message + attachment file for a channel. Called on N→0.
+	// CleanupChannel wipes every message + attachment file for a channel.
+	// Called when the voice channel is deleted — not on N→0, history persists.
 	CleanupChannel(ctx context.Context, channelID string)
 }
 
 type voiceMessageService struct {
 	repo            repository.VoiceMessageRepository
 	voiceMembership VoiceChannelMembershipChecker
+	permResolver    ChannelPermResolver
 	hub             ws.Broadcaster
 	urlSigner       FileURLSigner
 	fileDeleter     FileDeleter
@@ -49,6 +53,7 @@ type voiceMessageService struct {
 func NewVoiceMessageService(
 	repo repository.VoiceMessageRepository,
 	voiceMembership VoiceChannelMembershipChecker,
+	permResolver ChannelPermResolver,
 	hub ws.Broadcaster,
 	urlSigner FileURLSigner,
 	fileDeleter FileDeleter,
@@ -56,6 +61,7 @@ func NewVoiceMessageService(
 	return &voiceMessageService{
 		repo:            repo,
 		voiceMembership: voiceMembership,
+		permResolver:    permResolver,
 		hub:             hub,
 		urlSigner:       urlSigner,
 		fileDeleter:     fileDeleter,
@@ -63,6 +69,7 @@ func NewVoiceMessageService(
 }
 
 // requireMember returns the user's voice state if they're in channelID, else 403.
+// Writes stay gated on presence — you talk in the room you're standing in.
 func (s *voiceMessageService) requireMember(userID, channelID string) (*models.VoiceState, error) {
 	state := s.voiceMembership.GetUserVoiceState(userID)
 	if state == nil || state.ChannelID != channelID {
@@ -71,6 +78,22 @@ func (s *voiceMessageService) requireMember(userID, channelID string) (*models.V
 	return state, nil
 }
 
+// requireViewer gates reads: being in the voice channel is enough, and so is
+// holding PermViewChannel — members who can see the channel can catch up on
+// its chat without connecting to voice.
+func (s *voiceMessageService) requireViewer(ctx context.Context, userID, channelID string) error {
+	if state := s.voiceMembership.GetUserVoiceState(userID); state != nil && state.ChannelID == channelID {
+		return nil
+	}
+	if s.permResolver != nil {
+		perms, err := s.permResolver.ResolveChannelPermissions(ctx, userID, channelID)
+		if err == nil && perms.Has(models.PermViewChannel) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no access to this voice channel's chat", pkg.ErrForbidden)
+}
+
 func (s *voiceMessageService) Create(ctx context.Context, userID, channelID string, req *models.CreateVoiceMessageRequest) (*models.VoiceMessage, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", pkg.ErrBadRequest, err.Error())
@@ -101,7 +124,7 @@ func (s *voiceMessageService) Create(ctx context.Context, userID, channelID stri
 }
 
 func (s *voiceMessageService) List(ctx context.Context, userID, channelID string, limit int) ([]models.VoiceMessage, error) {
-	if _, err := s.requireMember(userID, channelID); err != nil {
+	if err := s.requireViewer(ctx, userID, channelID); err != nil {
 		return nil, err
 	}
 	msgs, err := s.repo.GetByChannelID(ctx, channelID, limit)
@@ -260,8 +283,8 @@ func (s *voiceMessageService) signAttachments(atts []models.VoiceMessageAttachme
 	return out
 }
 
-// broadcastToParticipants sends an event only to users currently in the voice channel,
-// matching the ephemeral-by-presence semantic — people not in voice don't get noise.
+// broadcastToParticipants sends an event only to users currently in the voice channel —
+// people not in voice don't get event noise; they pick up history on their next List.
 func (s *voiceMessageService) broadcastToParticipants(channelID string, event ws.Event) {
 	participants := s.voiceMembership.GetChannelParticipants(channelID)
 	if len(participants) == 0 {
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// fakeVoiceMembership reports a single user as connected to a single channel.
+type fakeVoiceMembership struct {
+	userID    string
+	channelID string
+}
+
+func (f *fakeVoiceMembership) GetUserVoiceState(userID string) *models.VoiceState {
+	if userID == f.userID {
+		return &models.VoiceState{UserID: f.userID, ChannelID: f.channelID}
+	}
+	return nil
+}
+
+func (f *fakeVoiceMembership) GetChannelParticipants(channelID string) []models.VoiceState {
+	if channelID == f.channelID {
+		return []models.VoiceState{{UserID: f.userID, ChannelID: f.channelID}}
+	}
+	return nil
+}
+
+// fakeVoiceMsgRepo serves a fixed message list; everything else is unused by List.
+type fakeVoiceMsgRepo struct {
+	messages []models.VoiceMessage
+}
+
+func (f *fakeVoiceMsgRepo) Create(context.Context, *models.VoiceMessage) error { return nil }
+func (f *fakeVoiceMsgRepo) GetByID(context.Context, string) (*models.VoiceMessage, error) {
+	return nil, nil
+}
+func (f *fakeVoiceMsgRepo) GetByChannelID(_ context.Context, channelID string, _ int) ([]models.VoiceMessage, error) {
+	var out []models.VoiceMessage
+	for _, m := range f.messages {
+		if m.ChannelID == channelID {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+func (f *fakeVoiceMsgRepo) UpdateContent(context.Context, string, string) error { return nil }
+func (f *fakeVoiceMsgRepo) Delete(context.Context, string) error                { return nil }
+func (f *fakeVoiceMsgRepo) DeleteByChannel(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+func (f *fakeVoiceMsgRepo) CreateAttachment(context.Context, *models.VoiceMessageAttachment) error {
+	return nil
+}
+func (f *fakeVoiceMsgRepo) GetAttachmentsByMessageIDs(context.Context, []string) (map[string][]models.VoiceMessageAttachment, error) {
+	return map[string][]models.VoiceMessageAttachment{}, nil
+}
+
+// A member who can see the voice channel reads its chat without connecting to
+// voice — history is no longer presence-gated.
+func TestVoiceMessageList_ViewPermissionReadsWithoutConnecting(t *testing.T) {
+	svc := &voiceMessageService{
+		repo:            &fakeVoiceMsgRepo{messages: []models.VoiceMessage{{ID: "vm1", ChannelID: "vc1"}}},
+		voiceMembership: &fakeVoiceMembership{userID: "in-voice", channelID: "vc1"},
+		permResolver: &testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(_ context.Context, userID, _ string) (models.Permission, error) {
+				if userID == "viewer" {
+					return models.PermViewChannel, nil
+				}
+				return 0, nil
+			},
+		},
+	}
+
+	msgs, err := svc.List(context.Background(), "viewer", "vc1", 50)
+	if err != nil {
+		t.Fatalf("List with PermViewChannel: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].ID != "vm1" {
+		t.Errorf("got %v, want the channel's single message", msgs)
+	}
+
+	if _, err := svc.List(context.Background(), "stranger", "vc1", 50); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("user with neither presence nor view perm got err=%v, want forbidden", err)
+	}
+}
+
+// Being connected still grants reads on its own, independent of resolved perms.
+func TestVoiceMessageList_ConnectedUserReadsWithoutViewPerm(t *testing.T) {
+	svc := &voiceMessageService{
+		repo:            &fakeVoiceMsgRepo{messages: []models.VoiceMessage{{ID: "vm1", ChannelID: "vc1"}}},
+		voiceMembership: &fakeVoiceMembership{userID: "in-voice", channelID: "vc1"},
+		permResolver:    &testutil.MockChannelPermResolver{},
+	}
+
+	if _, err := svc.List(context.Background(), "in-voice", "vc1", 50); err != nil {
+		t.Fatalf("List while connected: %v", err)
+	}
+}
+
+// Writes keep the presence gate: view permission alone does not let you post.
+func TestVoiceMessageCreate_StillRequiresVoicePresence(t *testing.T) {
+	svc := &voiceMessageService{
+		repo:            &fakeVoiceMsgRepo{},
+		voiceMembership: &fakeVoiceMembership{userID: "in-voice", channelID: "vc1"},
+		permResolver: &testutil.MockChannelPermResolver{
+			ResolveChannelPermissionsFn: func(context.Context, string, string) (models.Permission, error) {
+				return models.PermViewChannel, nil
+			},
+		},
+	}
+
+	req := &models.CreateVoiceMessageRequest{Content: "hi"}
+	if _, err := svc.Create(context.Background(), "viewer", "vc1", req); !errors.Is(err, pkg.ErrForbidden) {
+		t.Errorf("Create without voice presence got err=%v, want forbidden", err)
+	}
+}
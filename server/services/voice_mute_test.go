@@ -19,7 +19,7 @@ func containsSource(sources []livekit.TrackSource, target livekit.TrackSource) b
 // publish source. buildServerMutePermission is the pure core of that enforcement.
 func TestBuildServerMutePermission(t *testing.T) {
 	// Muted speaker: mic excluded, other sources still publishable, subscribe stays on.
-	p := buildServerMutePermission(true, true)
+	p := buildServerMutePermission(true, true, true)
 	if !p.CanSubscribe {
 		t.Error("subscribe must stay enabled (server-deafen is client-side, not SFU-enforced)")
 	}
@@ -37,7 +37,7 @@ func TestBuildServerMutePermission(t *testing.T) {
 	}
 
 	// Unmuted speaker: source restriction cleared → all sources allowed again.
-	p = buildServerMutePermission(false, true)
+	p = buildServerMutePermission(false, true, true)
 	if len(p.CanPublishSources) != 0 {
 		t.Error("unmute must clear the source allow-list")
 	}
@@ -47,7 +47,7 @@ func TestBuildServerMutePermission(t *testing.T) {
 
 	// Muted non-speaker: no publish at all, and NO source allow-list (which would
 	// otherwise supersede CanPublish and grant camera/screen share — an escalation).
-	p = buildServerMutePermission(true, false)
+	p = buildServerMutePermission(true, false, true)
 	if p.CanPublish {
 		t.Error("a non-speaker must not be granted publish")
 	}
@@ -56,8 +56,38 @@ func TestBuildServerMutePermission(t *testing.T) {
 	}
 
 	// Unmuted non-speaker: still cannot publish.
-	p = buildServerMutePermission(false, false)
+	p = buildServerMutePermission(false, false, true)
 	if p.CanPublish {
 		t.Error("a non-speaker must not be granted publish on unmute")
 	}
 }
+
+// PermVideo splits camera publishing out of PermSpeak's blanket CanPublish grant:
+// a speaker without it keeps mic and screen share but loses the camera source.
+func TestAllowedPublishSources_VideoPermission(t *testing.T) {
+	// Speaker without video: camera excluded, mic and screen share stay.
+	sources := allowedPublishSources(false, false)
+	if containsSource(sources, livekit.TrackSource_CAMERA) {
+		t.Error("camera must NOT be publishable without PermVideo")
+	}
+	if !containsSource(sources, livekit.TrackSource_MICROPHONE) {
+		t.Error("microphone must stay publishable without PermVideo (video-only restriction)")
+	}
+	if !containsSource(sources, livekit.TrackSource_SCREEN_SHARE) {
+		t.Error("screen share must stay publishable without PermVideo (gated by PermStream)")
+	}
+
+	// Server-muted speaker without video: only screen share sources remain.
+	sources = allowedPublishSources(true, false)
+	if containsSource(sources, livekit.TrackSource_MICROPHONE) || containsSource(sources, livekit.TrackSource_CAMERA) {
+		t.Error("a muted no-video speaker must lose both microphone and camera")
+	}
+	if !containsSource(sources, livekit.TrackSource_SCREEN_SHARE) {
+		t.Error("the allow-list must never be empty (LiveKit reads empty as unrestricted)")
+	}
+
+	// Unmuted speaker with video: nil — no restriction baked in.
+	if sources = allowedPublishSources(false, true); sources != nil {
+		t.Errorf("unrestricted speaker must get a nil allow-list, got %v", sources)
+	}
+}
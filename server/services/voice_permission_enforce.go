@@ -92,5 +92,6 @@ func (s *voiceService) enforceVoicePermissionForParticipant(p models.VoiceState)
 	}
 	// Speak gates the mic; an active server-mute still removes the mic on top
 	// (buildServerMutePermission handles both).
-	s.enforceServerMicMuteAtSFU(p.ServerID, p.ChannelID, p.UserID, muted, perms.Has(models.PermSpeak))
+	s.enforceServerMicMuteAtSFU(p.ServerID, p.ChannelID, p.UserID, muted,
+		perms.Has(models.PermSpeak), perms.Has(models.PermVideo))
 }
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg/crypto"
 	"github.com/akinalp/mqvi/ws"
 )
 
@@ -43,12 +44,18 @@ type AFKTimeoutGetter interface {
 	GetByID(ctx context.Context, serverID string) (*models.Server, error)
 }
 
+// MemberNicknameGetter resolves a member's per-server nickname for the LiveKit
+// participant name. Satisfied by repository.ServerRepository.
+type MemberNicknameGetter interface {
+	GetMemberNickname(ctx context.Context, serverID, userID string) (*string, error)
+}
+
 // ─── VoiceService Interface ───
 
 type VoiceService interface {
 	GenerateToken(ctx context.Context, userID, username, displayName, channelID string) (*models.VoiceTokenResponse, error)
 	GenerateScreenShareToken(ctx context.Context, userID, username, displayName, channelID string) (*models.VoiceTokenResponse, error)
-	JoinChannel(userID, username, displayName, avatarURL, channelID string, isMuted, isDeafened bool) error
+	JoinChannel(userID, username, displayName, avatarURL, clientPlatform, channelID string, isMuted, isDeafened bool) error
 	LeaveChannel(userID string) error
 	UpdateState(userID string, isMuted, isDeafened, isStreaming *bool, shareQuality *string) error
 	UpdateUserProfile(userID, username, displayName, avatarURL string)
@@ -56,6 +63,12 @@ type VoiceService interface {
 	GetServerParticipants(serverID string) []models.VoiceState
 	GetUserVoiceState(userID string) *models.VoiceState
 	GetAllVoiceStates() []models.VoiceState
+	// NotifyServerReconnect tells every voice user in the server to re-request
+	// a LiveKit token (sent after an admin instance migration).
+	NotifyServerReconnect(serverID string)
+	// InstanceMaxParticipants returns the instance-wide per-channel cap (0 = no cap)
+	// so handlers can expose it alongside the voice states.
+	InstanceMaxParticipants() int
 	GetActiveChannelTimers() map[string]int64 // channelID → start time (Unix ms)
 	// SyncServerStatesToUser pushes a server's in-progress voice participants +
 	// channel timers to one user (used on server join so a newcomer sees active
@@ -68,6 +81,10 @@ type VoiceService interface {
 	DisconnectUser(userID string)
 	GetStreamCount(channelID string) int
 	AdminUpdateState(ctx context.Context, adminUserID, targetUserID string, isServerMuted, isServerDeafened *bool) error
+	// SetPrioritySpeaker toggles the caller's own priority-speaker flag, gated by
+	// PermPrioritySpeaker on their current channel. Client-side ducking hint only —
+	// the LiveKit token grant is untouched.
+	SetPrioritySpeaker(ctx context.Context, userID string, isPriority bool) error
 	MoveUser(ctx context.Context, moverUserID, targetUserID, targetChannelID string) error
 	AdminDisconnectUser(ctx context.Context, disconnecterUserID, targetUserID string) error
 	// Live permission enforcement (S3): re-apply effective voice permissions at the SFU for
@@ -86,7 +103,16 @@ type VoiceService interface {
 	StartOrphanCleanup()
 	StartAFKChecker()
 	StartLiveKitReconciliation()
+	// StartChannelReconciliation sweeps voice states whose channel row no longer
+	// exists (channel deleted without participant teardown) on the given interval.
+	StartChannelReconciliation(interval time.Duration)
 	SetAppLogger(logger VoiceAppLogger)
+	// SetTimeoutChecker wires the member-timeout gate; a timed-out member gets a
+	// listen-only token. Nil-safe: unwired means timeouts are not enforced here.
+	SetTimeoutChecker(tc MemberTimeoutChecker)
+	// SetNicknameGetter wires per-server nickname lookup for participant names.
+	// Nil-safe: unwired falls back to display_name/username.
+	SetNicknameGetter(ng MemberNicknameGetter)
 }
 
 // VoiceAppLogger writes structured logs. ISP interface to avoid importing services.AppLogService.
@@ -122,9 +148,12 @@ type voiceService struct {
 	hub              ws.Broadcaster
 	onlineChecker    OnlineUserChecker
 	afkTimeoutGetter AFKTimeoutGetter
-	encryptionKey    []byte // AES-256-GCM for LiveKit credential decryption
+	keychain         *crypto.Keychain // AES-256-GCM keys for LiveKit credential decryption
 	appLogger        VoiceAppLogger
 	urlSigner        FileURLSigner
+	timeoutChecker   MemberTimeoutChecker // optional — set post-construction via SetTimeoutChecker
+	nicknameGetter   MemberNicknameGetter // optional — set post-construction via SetNicknameGetter
+	maxParticipants  int                  // instance-wide cap per voice channel (0 = no cap) — bounds channel UserLimit
 }
 
 func NewVoiceService(
@@ -134,8 +163,9 @@ func NewVoiceService(
 	hub ws.Broadcaster,
 	onlineChecker OnlineUserChecker,
 	afkTimeoutGetter AFKTimeoutGetter,
-	encryptionKey []byte,
+	keychain *crypto.Keychain,
 	urlSigner FileURLSigner,
+	maxParticipants int,
 ) VoiceService {
 	return &voiceService{
 		states:             make(map[string]*models.VoiceState),
@@ -151,8 +181,9 @@ func NewVoiceService(
 		hub:                hub,
 		onlineChecker:      onlineChecker,
 		afkTimeoutGetter:   afkTimeoutGetter,
-		encryptionKey:      encryptionKey,
+		keychain:           keychain,
 		urlSigner:          urlSigner,
+		maxParticipants:    maxParticipants,
 	}
 }
 
@@ -160,6 +191,14 @@ func (s *voiceService) SetAppLogger(logger VoiceAppLogger) {
 	s.appLogger = logger
 }
 
+func (s *voiceService) SetTimeoutChecker(tc MemberTimeoutChecker) {
+	s.timeoutChecker = tc
+}
+
+func (s *voiceService) SetNicknameGetter(ng MemberNicknameGetter) {
+	s.nicknameGetter = ng
+}
+
 // SetOnChannelEmpty installs a callback fired (in a goroutine) on N→0 transitions.
 // Set once at wiring time; not safe to call concurrently with voice operations.
 func (s *voiceService) SetOnChannelEmpty(fn func(channelID string)) {
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
 	"github.com/akinalp/mqvi/testutil"
 	"github.com/akinalp/mqvi/ws"
 )
@@ -50,8 +52,9 @@ func TestRemoveParticipantFromLiveKit_ReachesSFUAfterChannelDeleted(t *testing.T
 		&testutil.MockBroadcaster{},
 		nil, // onlineChecker
 		nil, // afkTimeoutGetter
-		nil, // encryptionKey
+		nil, // keychain
 		&testutil.MockFileURLSigner{},
+		0, // maxParticipants
 	).(*voiceService)
 
 	svc.removeParticipantFromLiveKit("srv1", "deleted-chan", "u1")
@@ -87,8 +90,9 @@ func newTestVoiceService() (VoiceService, *testutil.MockBroadcaster) {
 		hub,
 		nil, // onlineChecker
 		nil, // afkTimeoutGetter
-		nil, // encryptionKey
+		nil, // keychain
 		&testutil.MockFileURLSigner{},
+		0, // maxParticipants
 	)
 	return svc, hub
 }
@@ -101,7 +105,7 @@ func TestVoiceJoinChannel(t *testing.T) {
 		broadcasts = append(broadcasts, event)
 	}
 
-	err := svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	err := svc.JoinChannel("u1", "alice", "Alice", "", "mobile", "ch1", false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -117,6 +121,9 @@ func TestVoiceJoinChannel(t *testing.T) {
 	if state.Username != "alice" {
 		t.Errorf("username = %q, want %q", state.Username, "alice")
 	}
+	if state.ClientPlatform != "mobile" {
+		t.Errorf("clientPlatform = %q, want %q", state.ClientPlatform, "mobile")
+	}
 
 	// Verify voice state broadcast (channel timer events are separate concern)
 	stateUpdates := filterBroadcasts(broadcasts, ws.OpVoiceStateUpdate)
@@ -134,9 +141,9 @@ func TestVoiceJoinChannel_SwitchChannels(t *testing.T) {
 	}
 
 	// Join ch1
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 	// Switch to ch2
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch2", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch2", false, false)
 
 	state := svc.GetUserVoiceState("u1")
 	if state == nil {
@@ -163,11 +170,11 @@ func TestVoiceJoinChannel_SameChannelRejoin(t *testing.T) {
 	}
 
 	// Join ch1
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 	broadcasts = nil // reset
 
 	// Rejoin same channel (WS reconnect scenario)
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	// Should produce zero broadcasts — silent rejoin
 	if len(broadcasts) != 0 {
@@ -192,7 +199,7 @@ func TestVoiceLeaveChannel(t *testing.T) {
 		broadcasts = append(broadcasts, event)
 	}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 	broadcasts = nil // reset
 
 	err := svc.LeaveChannel("u1")
@@ -224,7 +231,7 @@ func TestVoiceUpdateState(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	truev := true
 	falsev := false
@@ -275,9 +282,9 @@ func TestVoiceGetChannelParticipants(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
-	_ = svc.JoinChannel("u2", "bob", "Bob", "", "ch1", false, false)
-	_ = svc.JoinChannel("u3", "charlie", "Charlie", "", "ch2", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
+	_ = svc.JoinChannel("u2", "bob", "Bob", "", "", "ch1", false, false)
+	_ = svc.JoinChannel("u3", "charlie", "Charlie", "", "", "ch2", false, false)
 
 	ch1Participants := svc.GetChannelParticipants("ch1")
 	if len(ch1Participants) != 2 {
@@ -299,8 +306,8 @@ func TestVoiceGetAllVoiceStates(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
-	_ = svc.JoinChannel("u2", "bob", "Bob", "", "ch2", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
+	_ = svc.JoinChannel("u2", "bob", "Bob", "", "", "ch2", false, false)
 
 	all := svc.GetAllVoiceStates()
 	if len(all) != 2 {
@@ -312,7 +319,7 @@ func TestVoiceDisconnectUser(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	svc.DisconnectUser("u1")
 
@@ -326,8 +333,8 @@ func TestVoiceGetStreamCount(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
-	_ = svc.JoinChannel("u2", "bob", "Bob", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
+	_ = svc.JoinChannel("u2", "bob", "Bob", "", "", "ch1", false, false)
 
 	if svc.GetStreamCount("ch1") != 0 {
 		t.Error("expected 0 streams initially")
@@ -356,7 +363,7 @@ func TestVoiceGetUserVoiceChannelID(t *testing.T) {
 		t.Error("expected empty channel ID for non-voice user")
 	}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	if svc.GetUserVoiceChannelID("u1") != "ch1" {
 		t.Errorf("channel ID = %q, want %q", svc.GetUserVoiceChannelID("u1"), "ch1")
@@ -367,8 +374,8 @@ func TestVoiceScreenShareViewerTracking(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("streamer", "alice", "Alice", "", "ch1", false, false)
-	_ = svc.JoinChannel("viewer1", "bob", "Bob", "", "ch1", false, false)
+	_ = svc.JoinChannel("streamer", "alice", "Alice", "", "", "ch1", false, false)
+	_ = svc.JoinChannel("viewer1", "bob", "Bob", "", "", "ch1", false, false)
 
 	// Start streaming
 	truev := true
@@ -391,7 +398,7 @@ func TestVoiceCleanupViewersForStreamer(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("streamer", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("streamer", "alice", "Alice", "", "", "ch1", false, false)
 	truev := true
 	_ = svc.UpdateState("streamer", nil, nil, &truev, nil)
 
@@ -413,7 +420,7 @@ func TestVoiceState_ServerMuteDeafen(t *testing.T) {
 	svc, hub := newTestVoiceService()
 	hub.BroadcastToServerFn = func(_ string, _ ws.Event) {}
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	state := svc.GetUserVoiceState("u1")
 	if state.IsServerMuted || state.IsServerDeafened {
@@ -436,3 +443,111 @@ func TestVoiceState_ServerMuteDeafen(t *testing.T) {
 		t.Error("u1 not found in voice states")
 	}
 }
+
+func TestSetPrioritySpeaker_PermGatedAndBroadcast(t *testing.T) {
+	svc, broadcasts := newTimerTestVoiceService(nil, models.PermPrioritySpeaker)
+
+	// Not in voice yet — toggling is meaningless.
+	if err := svc.SetPrioritySpeaker(context.Background(), "u1", true); !errors.Is(err, pkg.ErrBadRequest) {
+		t.Fatalf("toggle outside voice got err=%v, want bad request", err)
+	}
+
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
+
+	if err := svc.SetPrioritySpeaker(context.Background(), "u1", true); err != nil {
+		t.Fatalf("SetPrioritySpeaker: %v", err)
+	}
+	state := svc.GetUserVoiceState("u1")
+	if state == nil || !state.IsPrioritySpeaker {
+		t.Error("priority flag not set on voice state")
+	}
+
+	// The flag travels with the normal state-update broadcast so clients duck.
+	flagged := false
+	for _, e := range filterBroadcasts(*broadcasts, ws.OpVoiceStateUpdate) {
+		if d, ok := e.Data.(ws.VoiceStateUpdateBroadcast); ok && d.Action == "update" && d.IsPrioritySpeaker {
+			flagged = true
+		}
+	}
+	if !flagged {
+		t.Error("no state-update broadcast carried the priority flag")
+	}
+}
+
+func TestSetPrioritySpeaker_RequiresPermission(t *testing.T) {
+	svc, _ := newTimerTestVoiceService(nil, models.PermConnectVoice|models.PermSpeak)
+
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
+
+	if err := svc.SetPrioritySpeaker(context.Background(), "u1", true); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("toggle without PermPrioritySpeaker got err=%v, want forbidden", err)
+	}
+	if state := svc.GetUserVoiceState("u1"); state.IsPrioritySpeaker {
+		t.Error("priority flag set despite missing permission")
+	}
+}
+
+func TestNotifyServerReconnect_OnlyAffectedServer(t *testing.T) {
+	hub := &testutil.MockBroadcaster{}
+	lk := &recordingLiveKitGetter{}
+	svc := NewVoiceService(
+		&testutil.MockChannelRepo{
+			GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+				serverID := "srv1"
+				if id == "ch2" {
+					serverID = "srv2"
+				}
+				return &models.Channel{ID: id, ServerID: serverID, Type: models.ChannelTypeVoice}, nil
+			},
+		},
+		lk,
+		&testutil.MockChannelPermResolver{},
+		hub,
+		nil, // onlineChecker
+		nil, // afkTimeoutGetter
+		nil, // keychain
+		&testutil.MockFileURLSigner{},
+		0, // maxParticipants
+	)
+
+	if err := svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false); err != nil {
+		t.Fatalf("join ch1: %v", err)
+	}
+	if err := svc.JoinChannel("u2", "bob", "Bob", "", "", "ch2", false, false); err != nil {
+		t.Fatalf("join ch2: %v", err)
+	}
+
+	var notified []string
+	var payloads []ws.VoiceReconnectData
+	hub.BroadcastToUserFn = func(userID string, event ws.Event) {
+		if event.Op != ws.OpVoiceReconnect {
+			return
+		}
+		notified = append(notified, userID)
+		payloads = append(payloads, event.Data.(ws.VoiceReconnectData))
+	}
+
+	svc.NotifyServerReconnect("srv1")
+
+	if len(notified) != 1 || notified[0] != "u1" {
+		t.Fatalf("notified = %v, want exactly [u1] — srv2's user must not be disturbed", notified)
+	}
+	if payloads[0].ChannelID != "ch1" {
+		t.Errorf("reconnect payload channel = %q, want ch1", payloads[0].ChannelID)
+	}
+
+	// A server with nobody in voice produces no traffic at all.
+	svc.NotifyServerReconnect("srv-empty")
+	if len(notified) != 1 {
+		t.Errorf("empty-server reconnect produced %d extra notification(s)", len(notified)-1)
+	}
+
+	// The reconnecting client's fresh token request must re-resolve the instance
+	// from the server row — that lookup is what picks up the migration target.
+	lk.callCount = 0
+	_, _ = svc.GenerateToken(context.Background(), "u1", "alice", "Alice", "ch1")
+	if lk.callCount != 1 || lk.lastServerID != "srv1" {
+		t.Errorf("token path queried instance %d time(s) for server %q, want 1 lookup for srv1",
+			lk.callCount, lk.lastServerID)
+	}
+}
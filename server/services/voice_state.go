@@ -24,7 +24,7 @@ func (s *voiceService) broadcastToServer(serverID string, event ws.Event) {
 	s.hub.BroadcastToServer(serverID, event)
 }
 
-func (s *voiceService) JoinChannel(userID, username, displayName, avatarURL, channelID string, isMuted, isDeafened bool) error {
+func (s *voiceService) JoinChannel(userID, username, displayName, avatarURL, clientPlatform, channelID string, isMuted, isDeafened bool) error {
 	// avatarURL is the raw, unsigned URL from the DB. We store it unsigned in
 	// VoiceState (which lives as long as the user is in voice — could be hours)
 	// and re-sign at every broadcast egress. Storing an already-signed URL would
@@ -54,6 +54,7 @@ func (s *voiceService) JoinChannel(userID, username, displayName, avatarURL, cha
 			existing.Username = username
 			existing.DisplayName = displayName
 			existing.AvatarURL = avatarURL
+			existing.ClientPlatform = clientPlatform
 			existing.LastActivity = time.Now()
 			// Rejoin is a sign of life (F5 / WS reconnect re-assert) — reset the
 			// LiveKit absence grace so a stale timestamp can't cause a short-grace
@@ -91,32 +92,34 @@ func (s *voiceService) JoinChannel(userID, username, displayName, avatarURL, cha
 	newChannelWasEmpty := s.countInChannelLocked(channelID) == 0
 
 	s.states[userID] = &models.VoiceState{
-		UserID:       userID,
-		ChannelID:    channelID,
-		ChannelName:  channel.Name,
-		ServerID:     serverID,
-		Username:     username,
-		DisplayName:  displayName,
-		AvatarURL:    avatarURL, // unsigned — see comment above
-		IsMuted:      isMuted,
-		IsDeafened:   isDeafened,
-		LastActivity: time.Now(),
+		UserID:         userID,
+		ChannelID:      channelID,
+		ChannelName:    channel.Name,
+		ServerID:       serverID,
+		Username:       username,
+		DisplayName:    displayName,
+		AvatarURL:      avatarURL, // unsigned — see comment above
+		IsMuted:        isMuted,
+		IsDeafened:     isDeafened,
+		ClientPlatform: clientPlatform,
+		LastActivity:   time.Now(),
 	}
 	delete(s.livekitAbsentSince, userID) // fresh session — reset LiveKit absence grace
 
 	s.broadcastToServer(serverID, ws.Event{
 		Op: ws.OpVoiceStateUpdate,
 		Data: ws.VoiceStateUpdateBroadcast{
-			UserID:      userID,
-			ChannelID:   channelID,
-			ChannelName: channel.Name,
-			ServerID:    serverID,
-			Username:    username,
-			DisplayName: displayName,
-			AvatarURL:   signedAvatar,
-			IsMuted:     isMuted,
-			IsDeafened:  isDeafened,
-			Action:      "join",
+			UserID:         userID,
+			ChannelID:      channelID,
+			ChannelName:    channel.Name,
+			ServerID:       serverID,
+			Username:       username,
+			DisplayName:    displayName,
+			AvatarURL:      signedAvatar,
+			IsMuted:        isMuted,
+			IsDeafened:     isDeafened,
+			ClientPlatform: clientPlatform,
+			Action:         "join",
 		},
 	})
 
@@ -266,18 +269,19 @@ func (s *voiceService) UpdateState(userID string, isMuted, isDeafened, isStreami
 	s.broadcastToServer(state.ServerID, ws.Event{
 		Op: ws.OpVoiceStateUpdate,
 		Data: ws.VoiceStateUpdateBroadcast{
-			UserID:           state.UserID,
-			ChannelID:        state.ChannelID,
-			Username:         state.Username,
-			DisplayName:      state.DisplayName,
-			AvatarURL:        s.urlSigner.SignURL(state.AvatarURL),
-			IsMuted:          state.IsMuted,
-			IsDeafened:       state.IsDeafened,
-			IsStreaming:      state.IsStreaming,
-			ShareQuality:     state.ShareQuality,
-			IsServerMuted:    state.IsServerMuted,
-			IsServerDeafened: state.IsServerDeafened,
-			Action:           "update",
+			UserID:            state.UserID,
+			ChannelID:         state.ChannelID,
+			Username:          state.Username,
+			DisplayName:       state.DisplayName,
+			AvatarURL:         s.urlSigner.SignURL(state.AvatarURL),
+			IsMuted:           state.IsMuted,
+			IsDeafened:        state.IsDeafened,
+			IsStreaming:       state.IsStreaming,
+			ShareQuality:      state.ShareQuality,
+			IsServerMuted:     state.IsServerMuted,
+			IsServerDeafened:  state.IsServerDeafened,
+			IsPrioritySpeaker: state.IsPrioritySpeaker,
+			Action:            "update",
 		},
 	})
 
@@ -392,20 +396,22 @@ func (s *voiceService) SyncServerStatesToUser(userID, serverID string) {
 		events = append(events, ws.Event{
 			Op: ws.OpVoiceStateUpdate,
 			Data: ws.VoiceStateUpdateBroadcast{
-				UserID:           st.UserID,
-				ChannelID:        st.ChannelID,
-				ChannelName:      st.ChannelName,
-				ServerID:         st.ServerID,
-				Username:         st.Username,
-				DisplayName:      st.DisplayName,
-				AvatarURL:        s.urlSigner.SignURL(st.AvatarURL),
-				IsMuted:          st.IsMuted,
-				IsDeafened:       st.IsDeafened,
-				IsStreaming:      st.IsStreaming,
-				ShareQuality:     st.ShareQuality,
-				IsServerMuted:    st.IsServerMuted,
-				IsServerDeafened: st.IsServerDeafened,
-				Action:           "join",
+				UserID:            st.UserID,
+				ChannelID:         st.ChannelID,
+				ChannelName:       st.ChannelName,
+				ServerID:          st.ServerID,
+				Username:          st.Username,
+				DisplayName:       st.DisplayName,
+				AvatarURL:         s.urlSigner.SignURL(st.AvatarURL),
+				IsMuted:           st.IsMuted,
+				IsDeafened:        st.IsDeafened,
+				IsStreaming:       st.IsStreaming,
+				ShareQuality:      st.ShareQuality,
+				IsServerMuted:     st.IsServerMuted,
+				IsServerDeafened:  st.IsServerDeafened,
+				IsPrioritySpeaker: st.IsPrioritySpeaker,
+				ClientPlatform:    st.ClientPlatform,
+				Action:            "join",
 			},
 		})
 	}
@@ -475,6 +481,39 @@ func (s *voiceService) GetAllVoiceStates() []models.VoiceState {
 	return states
 }
 
+// NotifyServerReconnect tells everyone in the server's voice channels to
+// re-request a LiveKit token and rejoin. Fired after an instance migration:
+// connected clients hold tokens for the old instance and would otherwise be
+// stranded there until they left and rejoined by hand. The fresh token request
+// re-derives the URL from the server's (now updated) instance row. In-memory
+// voice state is left alone — clients rejoin the same channel.
+func (s *voiceService) NotifyServerReconnect(serverID string) {
+	s.mu.RLock()
+	affected := make([]models.VoiceState, 0)
+	for _, state := range s.states {
+		if state.ServerID == serverID {
+			affected = append(affected, *state)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, state := range affected {
+		s.hub.BroadcastToUser(state.UserID, ws.Event{
+			Op:   ws.OpVoiceReconnect,
+			Data: ws.VoiceReconnectData{ChannelID: state.ChannelID},
+		})
+	}
+	if len(affected) > 0 {
+		log.Printf("[voice] server %s migrated instances; told %d connected user(s) to reconnect", serverID, len(affected))
+	}
+}
+
+// InstanceMaxParticipants returns the configured instance-wide cap (0 = no cap).
+// Immutable after construction, so no lock needed.
+func (s *voiceService) InstanceMaxParticipants() int {
+	return s.maxParticipants
+}
+
 func (s *voiceService) DisconnectUser(userID string) {
 	if err := s.LeaveChannel(userID); err != nil {
 		log.Printf("[voice] disconnect cleanup failed for user=%s: %v", userID, err)
@@ -38,8 +38,9 @@ func newTimerTestVoiceService(online []string, perms models.Permission) (*voiceS
 		hub,
 		&mockOnlineChecker{online: online},
 		nil, // afkTimeoutGetter
-		nil, // encryptionKey
+		nil, // keychain
 		&testutil.MockFileURLSigner{},
+		0, // maxParticipants
 	)
 	return svc.(*voiceService), broadcasts
 }
@@ -106,7 +107,7 @@ func firstStateUpdateIndex(events []ws.Event, channelID, action string) int {
 func TestOrphanSweep_StopsTimerWhenChannelEmpties(t *testing.T) {
 	svc, broadcasts := newTimerTestVoiceService(nil, 0) // u1 offline (empty online set)
 
-	if err := svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false); err != nil {
+	if err := svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false); err != nil {
 		t.Fatalf("join: %v", err)
 	}
 	if got := countChannelTimerEvents(*broadcasts, ws.OpVoiceChannelTimerStart, "ch1"); got != 1 {
@@ -129,7 +130,7 @@ func TestOrphanSweep_StopsTimerWhenChannelEmpties(t *testing.T) {
 func TestAdminDisconnectUser_StopsTimerWhenChannelEmpties(t *testing.T) {
 	svc, broadcasts := newTimerTestVoiceService(nil, models.PermMoveMembers)
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	if err := svc.AdminDisconnectUser(context.Background(), "admin", "u1"); err != nil {
 		t.Fatalf("admin disconnect: %v", err)
@@ -147,7 +148,7 @@ func TestAdminDisconnectUser_StopsTimerWhenChannelEmpties(t *testing.T) {
 func TestMoveUser_TimerTransitions(t *testing.T) {
 	svc, broadcasts := newTimerTestVoiceService(nil, models.PermConnectVoice)
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	if err := svc.MoveUser(context.Background(), "u1", "u1", "ch2"); err != nil {
 		t.Fatalf("move: %v", err)
@@ -175,11 +176,11 @@ func TestMoveUser_TimerTransitions(t *testing.T) {
 func TestSameChannelRejoin_ResetsLiveKitAbsenceTracker(t *testing.T) {
 	svc, _ := newTimerTestVoiceService(nil, 0)
 
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 	svc.livekitAbsentSince["u1"] = time.Now().Add(-time.Hour) // reconcile marked absent
 
 	// Silent same-channel rejoin.
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	if _, tracked := svc.livekitAbsentSince["u1"]; tracked {
 		t.Fatal("expected livekitAbsentSince cleared on same-channel rejoin")
@@ -192,7 +193,7 @@ func TestJoinChannel_ResetsLiveKitAbsenceTracker(t *testing.T) {
 	svc, _ := newTimerTestVoiceService(nil, 0)
 
 	svc.livekitAbsentSince["u1"] = time.Now().Add(-time.Hour) // stale from a prior session
-	_ = svc.JoinChannel("u1", "alice", "Alice", "", "ch1", false, false)
+	_ = svc.JoinChannel("u1", "alice", "Alice", "", "", "ch1", false, false)
 
 	if _, tracked := svc.livekitAbsentSince["u1"]; tracked {
 		t.Fatal("expected livekitAbsentSince cleared on fresh join")
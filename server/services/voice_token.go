@@ -10,7 +10,6 @@ import (
 
 	"github.com/akinalp/mqvi/models"
 	"github.com/akinalp/mqvi/pkg"
-	"github.com/akinalp/mqvi/pkg/crypto"
 
 	"github.com/livekit/protocol/auth"
 )
@@ -33,14 +32,14 @@ func (s *voiceService) GenerateToken(ctx context.Context, userID, username, disp
 		return nil, fmt.Errorf("failed to get livekit instance for server %s: %w", channel.ServerID, err)
 	}
 
-	apiKey, err := crypto.Decrypt(lkInstance.APIKey, s.encryptionKey)
+	apiKey, err := s.keychain.Decrypt(lkInstance.APIKey)
 	if err != nil {
 		s.logError(models.LogCategoryVoice, &userID, "LiveKit API key decryption failed", map[string]string{
 			"instance_id": lkInstance.ID, "error": err.Error(),
 		})
 		return nil, fmt.Errorf("failed to decrypt livekit api key: %w", err)
 	}
-	apiSecret, err := crypto.Decrypt(lkInstance.APISecret, s.encryptionKey)
+	apiSecret, err := s.keychain.Decrypt(lkInstance.APISecret)
 	if err != nil {
 		s.logError(models.LogCategoryVoice, &userID, "LiveKit API secret decryption failed", map[string]string{
 			"instance_id": lkInstance.ID, "error": err.Error(),
@@ -71,8 +70,14 @@ func (s *voiceService) GenerateToken(ctx context.Context, userID, username, disp
 		}
 	}
 
-	// User limit check (0 = unlimited)
-	if channel.UserLimit > 0 {
+	// User limit check (0 = unlimited). The channel's own limit is bounded by the
+	// instance-wide cap, which holds even for unlimited channels — it protects the
+	// LiveKit room, not the channel's policy.
+	limit := channel.UserLimit
+	if s.maxParticipants > 0 && (limit == 0 || s.maxParticipants < limit) {
+		limit = s.maxParticipants
+	}
+	if limit > 0 {
 		participants := s.GetChannelParticipants(channelID)
 		alreadyIn := false
 		for _, p := range participants {
@@ -81,12 +86,24 @@ func (s *voiceService) GenerateToken(ctx context.Context, userID, username, disp
 				break
 			}
 		}
-		if !alreadyIn && len(participants) >= channel.UserLimit {
+		if !alreadyIn && len(participants) >= limit {
 			return nil, fmt.Errorf("%w: voice channel is full", pkg.ErrBadRequest)
 		}
 	}
 
-	canPublish := effectivePerms.Has(models.PermSpeak)
+	// A timed-out member may still join and listen, but must not publish —
+	// the timeout silences voice the same way it silences text.
+	timedOut := false
+	if s.timeoutChecker != nil {
+		var toErr error
+		timedOut, toErr = s.timeoutChecker.IsTimedOut(ctx, channel.ServerID, userID)
+		if toErr != nil {
+			return nil, fmt.Errorf("failed to check member timeout: %w", toErr)
+		}
+	}
+
+	canPublish := effectivePerms.Has(models.PermSpeak) && !timedOut
+	canVideo := effectivePerms.Has(models.PermVideo) && !timedOut
 	canSubscribe := true
 	canPublishData := true
 
@@ -103,10 +120,11 @@ func (s *voiceService) GenerateToken(ctx context.Context, userID, username, disp
 		CanPublishData: &canPublishData,
 	}
 
-	// Server-mute durability: if this user is currently server-muted, bake the mic-source
-	// exclusion into the token so the mute survives reconnect and admin-move — a fresh token
-	// would otherwise re-grant mic publish, letting a client shed the mute by reconnecting.
-	// Mirrors the live SFU enforcement in AdminUpdateState (same allow-list).
+	// Source restrictions baked into the token. Server-mute durability: if this user is
+	// currently server-muted, the mic-source exclusion must survive reconnect and
+	// admin-move — a fresh token would otherwise re-grant mic publish, letting a client
+	// shed the mute by reconnecting. Likewise a denied PermVideo excludes the camera
+	// source. Mirrors the live SFU enforcement in AdminUpdateState (same allow-list).
 	if canPublish {
 		s.mu.RLock()
 		serverMuted := false
@@ -114,15 +132,12 @@ func (s *voiceService) GenerateToken(ctx context.Context, userID, username, disp
 			serverMuted = st.IsServerMuted
 		}
 		s.mu.RUnlock()
-		if serverMuted {
-			grant.SetCanPublishSources(serverMuteAllowedSources())
+		if sources := allowedPublishSources(serverMuted, canVideo); sources != nil {
+			grant.SetCanPublishSources(sources)
 		}
 	}
 
-	participantName := username
-	if displayName != "" {
-		participantName = displayName
-	}
+	participantName := s.resolveParticipantName(ctx, channel.ServerID, userID, username, displayName)
 
 	at.AddGrant(grant).
 		SetIdentity(userID).
@@ -143,12 +158,17 @@ func (s *voiceService) GenerateToken(ctx context.Context, userID, username, disp
 		return nil, fmt.Errorf("failed to create E2EE passphrase: %w", err)
 	}
 
-	return &models.VoiceTokenResponse{
+	resp := &models.VoiceTokenResponse{
 		Token:          token,
 		URL:            lkInstance.URL,
 		ChannelID:      channelID,
 		E2EEPassphrase: passphrase,
-	}, nil
+		Bitrate:        channel.Bitrate,
+	}
+	if channel.RegionHint != nil {
+		resp.Region = *channel.RegionHint
+	}
+	return resp, nil
 }
 
 // GenerateScreenShareToken generates a LiveKit token for the iOS native screen share connection.
@@ -176,11 +196,11 @@ func (s *voiceService) GenerateScreenShareToken(ctx context.Context, userID, use
 		return nil, fmt.Errorf("failed to get livekit instance for server %s: %w", channel.ServerID, err)
 	}
 
-	apiKey, err := crypto.Decrypt(lkInstance.APIKey, s.encryptionKey)
+	apiKey, err := s.keychain.Decrypt(lkInstance.APIKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt livekit api key: %w", err)
 	}
-	apiSecret, err := crypto.Decrypt(lkInstance.APISecret, s.encryptionKey)
+	apiSecret, err := s.keychain.Decrypt(lkInstance.APISecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt livekit api secret: %w", err)
 	}
@@ -203,10 +223,7 @@ func (s *voiceService) GenerateScreenShareToken(ctx context.Context, userID, use
 
 	// Identity suffix "_ss" marks this as a screen share sub-participant
 	ssIdentity := userID + "_ss"
-	participantName := username + " (Screen)"
-	if displayName != "" {
-		participantName = displayName + " (Screen)"
-	}
+	participantName := s.resolveParticipantName(ctx, channel.ServerID, userID, username, displayName) + " (Screen)"
 
 	at.AddGrant(grant).
 		SetIdentity(ssIdentity).
@@ -230,3 +247,20 @@ func (s *voiceService) GenerateScreenShareToken(ctx context.Context, userID, use
 		E2EEPassphrase: passphrase,
 	}, nil
 }
+
+// resolveParticipantName picks the LiveKit participant name with the
+// server-scoped precedence: nickname > global display_name > username.
+// Nickname lookup is best-effort — a failed read falls back to the globals.
+func (s *voiceService) resolveParticipantName(ctx context.Context, serverID, userID, username, displayName string) string {
+	var nickname *string
+	if s.nicknameGetter != nil {
+		if nick, err := s.nicknameGetter.GetMemberNickname(ctx, serverID, userID); err == nil {
+			nickname = nick
+		}
+	}
+	var display *string
+	if displayName != "" {
+		display = &displayName
+	}
+	return models.EffectiveMemberName(nickname, display, username)
+}
@@ -0,0 +1,185 @@
+// Package services — WebhookService: tokened posting endpoints for integrations.
+//
+// Management (create/list/delete/regenerate) rides the normal authenticated
+// routes behind PermManageWebhooks. Execute is the public path where the token
+// IS the credential: it compares in constant time and answers "not found" for
+// a bad id and a bad token alike, so probing one leaks nothing about the other.
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+
+	"github.com/google/uuid"
+)
+
+// WebhookService manages channel webhooks and executes incoming posts.
+type WebhookService interface {
+	Create(ctx context.Context, serverID, channelID, userID string, req *models.CreateWebhookRequest) (*models.Webhook, error)
+	// ListByServer returns every webhook on the server, token included — the
+	// route gates on PermManageWebhooks, so the caller may hand tokens out.
+	ListByServer(ctx context.Context, serverID string) ([]models.Webhook, error)
+	Delete(ctx context.Context, serverID, id string) error
+	// RegenerateToken mints a fresh token; the leaked one stops working the
+	// moment this returns.
+	RegenerateToken(ctx context.Context, serverID, id string) (*models.Webhook, error)
+	// Execute posts a message as the webhook. Unauthenticated: id+token from
+	// the URL are the only credential.
+	Execute(ctx context.Context, id, token string, req *models.ExecuteWebhookRequest) (*models.Message, error)
+}
+
+// WebhookSender is the slice of MessageService webhook execution posts
+// through (ISP).
+type WebhookSender interface {
+	CreateWebhookMessage(ctx context.Context, webhook *models.Webhook, name string, avatarURL *string, content string) (*models.Message, error)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	channelRepo repository.ChannelRepository
+	sender      WebhookSender
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	channelRepo repository.ChannelRepository,
+	sender WebhookSender,
+) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		channelRepo: channelRepo,
+		sender:      sender,
+	}
+}
+
+func (s *webhookService) Create(ctx context.Context, serverID, channelID, userID string, req *models.CreateWebhookRequest) (*models.Webhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	channel, err := s.channelRepo.GetByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil || channel.ServerID != serverID {
+		return nil, fmt.Errorf("%w: channel does not belong to this server", pkg.ErrForbidden)
+	}
+	if channel.Type != models.ChannelTypeText {
+		return nil, fmt.Errorf("%w: webhooks can only post to text channels", pkg.ErrBadRequest)
+	}
+
+	token, err := generateWebhookToken()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		ID:        uuid.New().String(),
+		ServerID:  serverID,
+		ChannelID: channelID,
+		Name:      req.Name,
+		AvatarURL: req.AvatarURL,
+		Token:     token,
+		CreatedBy: userID,
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) ListByServer(ctx context.Context, serverID string) ([]models.Webhook, error) {
+	webhooks, err := s.webhookRepo.ListByServer(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+	return webhooks, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, serverID, id string) error {
+	if _, err := s.getForServer(ctx, serverID, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+func (s *webhookService) RegenerateToken(ctx context.Context, serverID, id string) (*models.Webhook, error) {
+	webhook, err := s.getForServer(ctx, serverID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generateWebhookToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.UpdateToken(ctx, id, token); err != nil {
+		return nil, fmt.Errorf("failed to regenerate webhook token: %w", err)
+	}
+
+	webhook.Token = token
+	return webhook, nil
+}
+
+func (s *webhookService) Execute(ctx context.Context, id, token string, req *models.ExecuteWebhookRequest) (*models.Message, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", pkg.ErrBadRequest, err)
+	}
+
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: webhook not found", pkg.ErrNotFound)
+	}
+	if subtle.ConstantTimeCompare([]byte(webhook.Token), []byte(token)) != 1 {
+		// Same answer as an unknown id — a wrong token must not confirm the
+		// id exists.
+		return nil, fmt.Errorf("%w: webhook not found", pkg.ErrNotFound)
+	}
+
+	// Per-message identity overrides fall back to the webhook's defaults.
+	name := webhook.Name
+	if req.Username != "" {
+		name = req.Username
+	}
+	avatarURL := webhook.AvatarURL
+	if req.AvatarURL != nil {
+		avatarURL = req.AvatarURL
+	}
+
+	return s.sender.CreateWebhookMessage(ctx, webhook, name, avatarURL, req.Content)
+}
+
+// getForServer loads a webhook and verifies it belongs to the server from the
+// route — management is scoped by the caller's PermManageWebhooks there.
+func (s *webhookService) getForServer(ctx context.Context, serverID, id string) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.ServerID != serverID {
+		return nil, fmt.Errorf("%w: webhook does not belong to this server", pkg.ErrForbidden)
+	}
+	return webhook, nil
+}
+
+// generateWebhookToken returns 32 random bytes as 64 hex chars — long enough
+// that the URL is the secret.
+func generateWebhookToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook token: %w", err)
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
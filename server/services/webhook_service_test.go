@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akinalp/mqvi/models"
+	"github.com/akinalp/mqvi/pkg"
+	"github.com/akinalp/mqvi/repository"
+	"github.com/akinalp/mqvi/testutil"
+)
+
+// memWebhookRepo holds webhooks in a map — enough to exercise token checks
+// and server scoping without a database.
+type memWebhookRepo struct {
+	repository.WebhookRepository
+
+	rows map[string]*models.Webhook
+}
+
+func (r *memWebhookRepo) Create(_ context.Context, webhook *models.Webhook) error {
+	if r.rows == nil {
+		r.rows = map[string]*models.Webhook{}
+	}
+	r.rows[webhook.ID] = webhook
+	return nil
+}
+
+func (r *memWebhookRepo) GetByID(_ context.Context, id string) (*models.Webhook, error) {
+	webhook, ok := r.rows[id]
+	if !ok {
+		return nil, pkg.ErrNotFound
+	}
+	copied := *webhook
+	return &copied, nil
+}
+
+func (r *memWebhookRepo) UpdateToken(_ context.Context, id, token string) error {
+	webhook, ok := r.rows[id]
+	if !ok {
+		return pkg.ErrNotFound
+	}
+	webhook.Token = token
+	return nil
+}
+
+func (r *memWebhookRepo) Delete(_ context.Context, id string) error {
+	if _, ok := r.rows[id]; !ok {
+		return pkg.ErrNotFound
+	}
+	delete(r.rows, id)
+	return nil
+}
+
+type webhookSent struct {
+	webhookID string
+	name      string
+	avatarURL *string
+	content   string
+}
+
+// recordingWebhookSender captures what Execute would post.
+type recordingWebhookSender struct {
+	sent []webhookSent
+}
+
+func (r *recordingWebhookSender) CreateWebhookMessage(_ context.Context, webhook *models.Webhook, name string, avatarURL *string, content string) (*models.Message, error) {
+	r.sent = append(r.sent, webhookSent{webhookID: webhook.ID, name: name, avatarURL: avatarURL, content: content})
+	return &models.Message{ID: "m1", ChannelID: webhook.ChannelID}, nil
+}
+
+func newTestWebhookService(repo *memWebhookRepo, sender *recordingWebhookSender) WebhookService {
+	return NewWebhookService(repo, &testutil.MockChannelRepo{
+		GetByIDFn: func(_ context.Context, id string) (*models.Channel, error) {
+			return &models.Channel{ID: id, ServerID: "s1", Type: models.ChannelTypeText}, nil
+		},
+	}, sender)
+}
+
+func TestWebhookExecute_TokenIsTheCredential(t *testing.T) {
+	avatar := "https://example.com/a.png"
+	repo := &memWebhookRepo{rows: map[string]*models.Webhook{
+		"wh1": {ID: "wh1", ServerID: "s1", ChannelID: "ch1", Name: "CI", AvatarURL: &avatar, Token: "secret"},
+	}}
+	sender := &recordingWebhookSender{}
+	svc := newTestWebhookService(repo, sender)
+
+	// Wrong token and unknown id must fail identically — a wrong token must
+	// not confirm the id exists.
+	_, errBadToken := svc.Execute(context.Background(), "wh1", "guess", &models.ExecuteWebhookRequest{Content: "hi"})
+	_, errBadID := svc.Execute(context.Background(), "nope", "secret", &models.ExecuteWebhookRequest{Content: "hi"})
+	if !errors.Is(errBadToken, pkg.ErrNotFound) || !errors.Is(errBadID, pkg.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for both, got %v / %v", errBadToken, errBadID)
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("nothing should have been posted, got %d sends", len(sender.sent))
+	}
+
+	// The right token posts under the webhook's default identity.
+	if _, err := svc.Execute(context.Background(), "wh1", "secret", &models.ExecuteWebhookRequest{Content: "build passed"}); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected 1 send, got %d", len(sender.sent))
+	}
+	got := sender.sent[0]
+	if got.name != "CI" || got.avatarURL == nil || *got.avatarURL != avatar || got.content != "build passed" {
+		t.Errorf("unexpected send: %+v", got)
+	}
+}
+
+func TestWebhookExecute_PayloadOverridesIdentity(t *testing.T) {
+	repo := &memWebhookRepo{rows: map[string]*models.Webhook{
+		"wh1": {ID: "wh1", ServerID: "s1", ChannelID: "ch1", Name: "CI", Token: "secret"},
+	}}
+	sender := &recordingWebhookSender{}
+	svc := newTestWebhookService(repo, sender)
+
+	override := "https://example.com/alert.png"
+	_, err := svc.Execute(context.Background(), "wh1", "secret", &models.ExecuteWebhookRequest{
+		Content:   "disk almost full",
+		Username:  "Alerts",
+		AvatarURL: &override,
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	got := sender.sent[0]
+	if got.name != "Alerts" || got.avatarURL == nil || *got.avatarURL != override {
+		t.Errorf("overrides should win: %+v", got)
+	}
+}
+
+func TestWebhookManagement_ScopedToServer(t *testing.T) {
+	repo := &memWebhookRepo{rows: map[string]*models.Webhook{
+		"wh1": {ID: "wh1", ServerID: "s1", ChannelID: "ch1", Name: "CI", Token: "old"},
+	}}
+	svc := newTestWebhookService(repo, &recordingWebhookSender{})
+
+	// Another server's route context can't touch s1's webhook.
+	if err := svc.Delete(context.Background(), "s2", "wh1"); !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+
+	regenerated, err := svc.RegenerateToken(context.Background(), "s1", "wh1")
+	if err != nil {
+		t.Fatalf("RegenerateToken: %v", err)
+	}
+	if regenerated.Token == "old" || regenerated.Token == "" {
+		t.Error("token should have been replaced")
+	}
+	if repo.rows["wh1"].Token != regenerated.Token {
+		t.Error("new token should be persisted")
+	}
+
+	// Create rejects channels that belong to a different server than the route.
+	_, err = svc.Create(context.Background(), "s2", "ch1", "u1", &models.CreateWebhookRequest{Name: "CI"})
+	if !errors.Is(err, pkg.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for foreign channel, got %v", err)
+	}
+}
@@ -233,6 +233,12 @@ func (f *Fixture) DMChannel(id, user1ID, user2ID string) string {
 		`INSERT INTO dm_channels (id, user1_id, user2_id) VALUES (?, ?, ?)`,
 		id, user1ID, user2ID,
 	)
+	// Membership reads go through dm_channel_members (migration 098); mirror
+	// the pair there like the production insert does.
+	f.exec(
+		`INSERT INTO dm_channel_members (dm_channel_id, user_id) VALUES (?, ?), (?, ?)`,
+		id, user1ID, id, user2ID,
+	)
 	return id
 }
 
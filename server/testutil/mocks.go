@@ -14,35 +14,37 @@ import (
 // ─── UserRepository mock ───
 
 type MockUserRepo struct {
-	CreateFn                   func(ctx context.Context, user *models.User) error
-	GetByIDFn                  func(ctx context.Context, id string) (*models.User, error)
-	GetByUsernameFn            func(ctx context.Context, username string) (*models.User, error)
-	GetAllFn                   func(ctx context.Context) ([]models.User, error)
-	UpdateFn                   func(ctx context.Context, user *models.User) error
-	UpdateStatusFn             func(ctx context.Context, userID string, status models.UserStatus) error
-	UpdatePasswordFn           func(ctx context.Context, userID, oldPasswordHash, newPasswordHash string) (int, error)
-	ResetPasswordWithTokenFn   func(ctx context.Context, userID, resetTokenID, newPasswordHash string) (int, error)
-	UpdateEmailFn              func(ctx context.Context, userID string, email *string) error
-	GetByEmailFn               func(ctx context.Context, email string) (*models.User, error)
-	CountFn                    func(ctx context.Context) (int, error)
-	DeleteFn                   func(ctx context.Context, id string) error
-	ListAdminUsersPagedFn      func(ctx context.Context, params models.AdminListPageParams, defaultQuotaBytes int64, activeVoiceUserIDs []string) (models.AdminUserListPage, error)
-	UpdateLastVoiceActivityFn  func(ctx context.Context, userID string) error
-	PlatformBanFn              func(ctx context.Context, userID, reason, bannedBy string) error
-	PlatformUnbanFn            func(ctx context.Context, userID string) error
-	IsEmailPlatformBannedFn    func(ctx context.Context, email string) (bool, error)
-	DeleteAllMessagesByUserFn  func(ctx context.Context, userID string) error
-	HardDeleteUserFn           func(ctx context.Context, userID string, byAdmin bool) error
-	SoftDeleteFn               func(ctx context.Context, userID string, byAdmin bool) error
-	RestoreFn                  func(ctx context.Context, userID string) error
-	ListSoftDeletedExpiredFn   func(ctx context.Context, ttlDays int) ([]models.User, error)
-	SetPlatformAdminFn         func(ctx context.Context, userID string, isAdmin bool) error
-	InsertPlatformBanFn        func(ctx context.Context, email, username, userID, reason, bannedBy string) error
-	DeletePlatformBanFn        func(ctx context.Context, userID string) error
-	IsUsernamePlatformBannedFn func(ctx context.Context, username string) (bool, error)
-	IsPlatformBannedByUserIDFn func(ctx context.Context, userID string) (bool, error)
-	GetActiveByIDFn            func(ctx context.Context, id string) (*models.User, error)
-	GetActiveByUsernameFn      func(ctx context.Context, username string) (*models.User, error)
+	CreateFn                     func(ctx context.Context, user *models.User) error
+	GetByIDFn                    func(ctx context.Context, id string) (*models.User, error)
+	GetByUsernameFn              func(ctx context.Context, username string) (*models.User, error)
+	GetAllFn                     func(ctx context.Context) ([]models.User, error)
+	UpdateFn                     func(ctx context.Context, user *models.User) error
+	UpdateStatusFn               func(ctx context.Context, userID string, status models.UserStatus) error
+	UpdateCustomStatusFn         func(ctx context.Context, userID string, text, emoji *string, expiresAt *time.Time) error
+	ClearExpiredCustomStatusesFn func(ctx context.Context, now time.Time) ([]string, error)
+	UpdatePasswordFn             func(ctx context.Context, userID, oldPasswordHash, newPasswordHash string) (int, error)
+	ResetPasswordWithTokenFn     func(ctx context.Context, userID, resetTokenID, newPasswordHash string) (int, error)
+	UpdateEmailFn                func(ctx context.Context, userID string, email *string) error
+	GetByEmailFn                 func(ctx context.Context, email string) (*models.User, error)
+	CountFn                      func(ctx context.Context) (int, error)
+	DeleteFn                     func(ctx context.Context, id string) error
+	ListAdminUsersPagedFn        func(ctx context.Context, params models.AdminListPageParams, defaultQuotaBytes int64, activeVoiceUserIDs []string) (models.AdminUserListPage, error)
+	UpdateLastVoiceActivityFn    func(ctx context.Context, userID string) error
+	PlatformBanFn                func(ctx context.Context, userID, reason, bannedBy string, bannedUntil *time.Time) error
+	PlatformUnbanFn              func(ctx context.Context, userID string) error
+	IsEmailPlatformBannedFn      func(ctx context.Context, email string) (bool, error)
+	DeleteAllMessagesByUserFn    func(ctx context.Context, userID string) error
+	HardDeleteUserFn             func(ctx context.Context, userID string, byAdmin bool) error
+	SoftDeleteFn                 func(ctx context.Context, userID string, byAdmin bool) error
+	RestoreFn                    func(ctx context.Context, userID string) error
+	ListSoftDeletedExpiredFn     func(ctx context.Context, ttlDays int) ([]models.User, error)
+	SetPlatformAdminFn           func(ctx context.Context, userID string, isAdmin bool) error
+	InsertPlatformBanFn          func(ctx context.Context, email, username, userID, reason, bannedBy string, bannedUntil *time.Time) error
+	DeletePlatformBanFn          func(ctx context.Context, userID string) error
+	IsUsernamePlatformBannedFn   func(ctx context.Context, username string) (bool, error)
+	IsPlatformBannedByUserIDFn   func(ctx context.Context, userID string) (bool, error)
+	GetActiveByIDFn              func(ctx context.Context, id string) (*models.User, error)
+	GetActiveByUsernameFn        func(ctx context.Context, username string) (*models.User, error)
 }
 
 func (m *MockUserRepo) Create(ctx context.Context, user *models.User) error {
@@ -126,9 +128,9 @@ func (m *MockUserRepo) UpdateLastVoiceActivity(ctx context.Context, userID strin
 	}
 	return nil
 }
-func (m *MockUserRepo) PlatformBan(ctx context.Context, userID, reason, bannedBy string) error {
+func (m *MockUserRepo) PlatformBan(ctx context.Context, userID, reason, bannedBy string, bannedUntil *time.Time) error {
 	if m.PlatformBanFn != nil {
-		return m.PlatformBanFn(ctx, userID, reason, bannedBy)
+		return m.PlatformBanFn(ctx, userID, reason, bannedBy, bannedUntil)
 	}
 	return nil
 }
@@ -188,15 +190,27 @@ func (m *MockUserRepo) ListPlatformAdminEmails(_ context.Context) ([]string, err
 func (m *MockUserRepo) UpdatePrefStatus(_ context.Context, _ string, _ models.UserStatus) error {
 	return nil
 }
+func (m *MockUserRepo) UpdateCustomStatus(ctx context.Context, userID string, text, emoji *string, expiresAt *time.Time) error {
+	if m.UpdateCustomStatusFn != nil {
+		return m.UpdateCustomStatusFn(ctx, userID, text, emoji, expiresAt)
+	}
+	return nil
+}
+func (m *MockUserRepo) ClearExpiredCustomStatuses(ctx context.Context, now time.Time) ([]string, error) {
+	if m.ClearExpiredCustomStatusesFn != nil {
+		return m.ClearExpiredCustomStatusesFn(ctx, now)
+	}
+	return nil, nil
+}
 func (m *MockUserRepo) SetDownloadPromptSeen(_ context.Context, _ string) error {
 	return nil
 }
 func (m *MockUserRepo) SetWelcomeSeen(_ context.Context, _ string) error {
 	return nil
 }
-func (m *MockUserRepo) InsertPlatformBan(ctx context.Context, email, username, userID, reason, bannedBy string) error {
+func (m *MockUserRepo) InsertPlatformBan(ctx context.Context, email, username, userID, reason, bannedBy string, bannedUntil *time.Time) error {
 	if m.InsertPlatformBanFn != nil {
-		return m.InsertPlatformBanFn(ctx, email, username, userID, reason, bannedBy)
+		return m.InsertPlatformBanFn(ctx, email, username, userID, reason, bannedBy, bannedUntil)
 	}
 	return nil
 }
@@ -330,6 +344,7 @@ type MockRoleRepo struct {
 	GetAllByServerFn       func(ctx context.Context, serverID string) ([]models.Role, error)
 	GetDefaultByServerFn   func(ctx context.Context, serverID string) (*models.Role, error)
 	GetByUserIDAndServerFn func(ctx context.Context, userID, serverID string) ([]models.Role, error)
+	GetByServerForUsersFn  func(ctx context.Context, serverID string, userIDs []string) (map[string][]models.Role, error)
 	GetMaxPositionFn       func(ctx context.Context, serverID string) (int, error)
 	CreateFn               func(ctx context.Context, role *models.Role) error
 	UpdateFn               func(ctx context.Context, role *models.Role) error
@@ -363,6 +378,12 @@ func (m *MockRoleRepo) GetByUserIDAndServer(ctx context.Context, userID, serverI
 	}
 	return nil, nil
 }
+func (m *MockRoleRepo) GetByServerForUsers(ctx context.Context, serverID string, userIDs []string) (map[string][]models.Role, error) {
+	if m.GetByServerForUsersFn != nil {
+		return m.GetByServerForUsersFn(ctx, serverID, userIDs)
+	}
+	return nil, nil
+}
 func (m *MockRoleRepo) GetMaxPosition(ctx context.Context, serverID string) (int, error) {
 	if m.GetMaxPositionFn != nil {
 		return m.GetMaxPositionFn(ctx, serverID)
@@ -411,9 +432,11 @@ func (m *MockRoleRepo) RemoveFromUser(ctx context.Context, userID, roleID string
 type MockChannelPermRepo struct {
 	GetByChannelFn         func(ctx context.Context, channelID string) ([]models.ChannelPermissionOverride, error)
 	GetByChannelAndRolesFn func(ctx context.Context, channelID string, roleIDs []string) ([]models.ChannelPermissionOverride, error)
+	GetMemberOverrideFn    func(ctx context.Context, channelID, userID string) (*models.ChannelPermissionOverride, error)
 	GetByRolesFn           func(ctx context.Context, roleIDs []string) ([]models.ChannelPermissionOverride, error)
+	GetByMemberFn          func(ctx context.Context, userID string) ([]models.ChannelPermissionOverride, error)
 	SetFn                  func(ctx context.Context, override *models.ChannelPermissionOverride) error
-	DeleteFn               func(ctx context.Context, channelID, roleID string) error
+	DeleteFn               func(ctx context.Context, channelID, targetType, targetID string) error
 	DeleteAllByChannelFn   func(ctx context.Context, channelID string) error
 }
 
@@ -429,21 +452,33 @@ func (m *MockChannelPermRepo) GetByChannelAndRoles(ctx context.Context, channelI
 	}
 	return nil, nil
 }
+func (m *MockChannelPermRepo) GetMemberOverride(ctx context.Context, channelID, userID string) (*models.ChannelPermissionOverride, error) {
+	if m.GetMemberOverrideFn != nil {
+		return m.GetMemberOverrideFn(ctx, channelID, userID)
+	}
+	return nil, nil
+}
 func (m *MockChannelPermRepo) GetByRoles(ctx context.Context, roleIDs []string) ([]models.ChannelPermissionOverride, error) {
 	if m.GetByRolesFn != nil {
 		return m.GetByRolesFn(ctx, roleIDs)
 	}
 	return nil, nil
 }
+func (m *MockChannelPermRepo) GetByMember(ctx context.Context, userID string) ([]models.ChannelPermissionOverride, error) {
+	if m.GetByMemberFn != nil {
+		return m.GetByMemberFn(ctx, userID)
+	}
+	return nil, nil
+}
 func (m *MockChannelPermRepo) Set(ctx context.Context, override *models.ChannelPermissionOverride) error {
 	if m.SetFn != nil {
 		return m.SetFn(ctx, override)
 	}
 	return nil
 }
-func (m *MockChannelPermRepo) Delete(ctx context.Context, channelID, roleID string) error {
+func (m *MockChannelPermRepo) Delete(ctx context.Context, channelID, targetType, targetID string) error {
 	if m.DeleteFn != nil {
-		return m.DeleteFn(ctx, channelID, roleID)
+		return m.DeleteFn(ctx, channelID, targetType, targetID)
 	}
 	return nil
 }
@@ -522,8 +557,21 @@ type MockMessageRepo struct {
 	CreateFn         func(ctx context.Context, message *models.Message) error
 	GetByIDFn        func(ctx context.Context, id string) (*models.Message, error)
 	GetByChannelIDFn func(ctx context.Context, channelID string, beforeID string, limit int) ([]models.Message, error)
+	GetAroundIDFn    func(ctx context.Context, channelID, messageID string, limit int) ([]models.Message, bool, bool, error)
 	UpdateFn         func(ctx context.Context, message *models.Message) error
 	DeleteFn         func(ctx context.Context, id string) error
+
+	ReplyChainDepthFn func(ctx context.Context, messageID string, maxDepth int) (int, error)
+	CountFn           func(ctx context.Context) (int, error)
+	GetChannelStatsFn func(ctx context.Context, channelID string) (*models.ChannelStats, error)
+	RecordEditFn      func(ctx context.Context, messageID, oldContent string, keep int) error
+	GetEditHistoryFn  func(ctx context.Context, messageID string) ([]models.MessageEdit, error)
+
+	GetByIDsFn             func(ctx context.Context, ids []string) ([]models.Message, error)
+	ListRecentIDsByUserFn  func(ctx context.Context, channelID, userID string, since time.Time, limit int) ([]string, error)
+	ListExpiredFn          func(ctx context.Context, now time.Time, limit int) ([]models.Message, error)
+	ListRetentionExpiredFn func(ctx context.Context, limit int) ([]models.Message, error)
+	DeleteBulkFn           func(ctx context.Context, channelID string, ids []string) (int, error)
 }
 
 func (m *MockMessageRepo) Create(ctx context.Context, message *models.Message) error {
@@ -544,6 +592,12 @@ func (m *MockMessageRepo) GetByChannelID(ctx context.Context, channelID string,
 	}
 	return nil, nil
 }
+func (m *MockMessageRepo) GetAroundID(ctx context.Context, channelID, messageID string, limit int) ([]models.Message, bool, bool, error) {
+	if m.GetAroundIDFn != nil {
+		return m.GetAroundIDFn(ctx, channelID, messageID, limit)
+	}
+	return nil, false, false, nil
+}
 func (m *MockMessageRepo) Update(ctx context.Context, message *models.Message) error {
 	if m.UpdateFn != nil {
 		return m.UpdateFn(ctx, message)
@@ -556,6 +610,67 @@ func (m *MockMessageRepo) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+func (m *MockMessageRepo) ReplyChainDepth(ctx context.Context, messageID string, maxDepth int) (int, error) {
+	if m.ReplyChainDepthFn != nil {
+		return m.ReplyChainDepthFn(ctx, messageID, maxDepth)
+	}
+	return 1, nil
+}
+func (m *MockMessageRepo) RecordEdit(ctx context.Context, messageID, oldContent string, keep int) error {
+	if m.RecordEditFn != nil {
+		return m.RecordEditFn(ctx, messageID, oldContent, keep)
+	}
+	return nil
+}
+func (m *MockMessageRepo) GetEditHistory(ctx context.Context, messageID string) ([]models.MessageEdit, error) {
+	if m.GetEditHistoryFn != nil {
+		return m.GetEditHistoryFn(ctx, messageID)
+	}
+	return nil, nil
+}
+func (m *MockMessageRepo) Count(ctx context.Context) (int, error) {
+	if m.CountFn != nil {
+		return m.CountFn(ctx)
+	}
+	return 0, nil
+}
+func (m *MockMessageRepo) GetChannelStats(ctx context.Context, channelID string) (*models.ChannelStats, error) {
+	if m.GetChannelStatsFn != nil {
+		return m.GetChannelStatsFn(ctx, channelID)
+	}
+	return &models.ChannelStats{ChannelID: channelID}, nil
+}
+func (m *MockMessageRepo) GetByIDs(ctx context.Context, ids []string) ([]models.Message, error) {
+	if m.GetByIDsFn != nil {
+		return m.GetByIDsFn(ctx, ids)
+	}
+	return nil, nil
+}
+func (m *MockMessageRepo) ListRecentIDsByUser(ctx context.Context, channelID, userID string, since time.Time, limit int) ([]string, error) {
+	if m.ListRecentIDsByUserFn != nil {
+		return m.ListRecentIDsByUserFn(ctx, channelID, userID, since, limit)
+	}
+	return nil, nil
+}
+func (m *MockMessageRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error) {
+	if m.ListExpiredFn != nil {
+		return m.ListExpiredFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+func (m *MockMessageRepo) ListRetentionExpired(ctx context.Context, limit int) ([]models.Message, error) {
+	if m.ListRetentionExpiredFn != nil {
+		return m.ListRetentionExpiredFn(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageRepo) DeleteBulk(ctx context.Context, channelID string, ids []string) (int, error) {
+	if m.DeleteBulkFn != nil {
+		return m.DeleteBulkFn(ctx, channelID, ids)
+	}
+	return len(ids), nil
+}
 
 // ─── WS mock (Broadcaster, EventPublisher) ───
 
@@ -606,8 +721,10 @@ type MockEventPublisher struct {
 	GetVisibleOnlineUserIDsFn   func() []string
 	IsOnlineFn                  func(userID string) bool
 	GetOnlineUserIDsForServerFn func(serverID string) []string
+	GetStatusesForUsersFn       func(userIDs []string) map[string]string
 	SetInvisibleFn              func(userID string, invisible bool)
 	DisconnectUserFn            func(userID string)
+	DisconnectUserWithReasonFn  func(userID, reason string)
 	AddClientServerIDFn         func(userID, serverID string)
 	RemoveClientServerIDFn      func(userID, serverID string)
 }
@@ -640,6 +757,16 @@ func (m *MockEventPublisher) GetOnlineUserIDsForServer(serverID string) []string
 func (m *MockEventPublisher) GetOnlineCountsForServers(serverIDs []string) map[string]int {
 	return map[string]int{}
 }
+func (m *MockEventPublisher) GetStatusesForUsers(userIDs []string) map[string]string {
+	if m.GetStatusesForUsersFn != nil {
+		return m.GetStatusesForUsersFn(userIDs)
+	}
+	out := make(map[string]string, len(userIDs))
+	for _, id := range userIDs {
+		out[id] = "offline"
+	}
+	return out
+}
 func (m *MockEventPublisher) SetInvisible(userID string, invisible bool) {
 	if m.SetInvisibleFn != nil {
 		m.SetInvisibleFn(userID, invisible)
@@ -650,6 +777,11 @@ func (m *MockEventPublisher) DisconnectUser(userID string) {
 		m.DisconnectUserFn(userID)
 	}
 }
+func (m *MockEventPublisher) DisconnectUserWithReason(userID, reason string) {
+	if m.DisconnectUserWithReasonFn != nil {
+		m.DisconnectUserWithReasonFn(userID, reason)
+	}
+}
 func (m *MockEventPublisher) AddClientServerID(userID, serverID string) {
 	if m.AddClientServerIDFn != nil {
 		m.AddClientServerIDFn(userID, serverID)
@@ -709,6 +841,7 @@ type MockBroadcastAndOnline struct {
 	GetVisibleOnlineUserIDsFn   func() []string
 	IsOnlineFn                  func(userID string) bool
 	GetOnlineUserIDsForServerFn func(serverID string) []string
+	GetStatusesForUsersFn       func(userIDs []string) map[string]string
 }
 
 func (m *MockBroadcastAndOnline) IsOnline(string) bool { return false }
@@ -734,6 +867,16 @@ func (m *MockBroadcastAndOnline) GetOnlineUserIDsForServer(serverID string) []st
 func (m *MockBroadcastAndOnline) GetOnlineCountsForServers(serverIDs []string) map[string]int {
 	return map[string]int{}
 }
+func (m *MockBroadcastAndOnline) GetStatusesForUsers(userIDs []string) map[string]string {
+	if m.GetStatusesForUsersFn != nil {
+		return m.GetStatusesForUsersFn(userIDs)
+	}
+	out := make(map[string]string, len(userIDs))
+	for _, id := range userIDs {
+		out[id] = "offline"
+	}
+	return out
+}
 
 // ─── AttachmentRepository mock ───
 
@@ -772,10 +915,11 @@ func (m *MockAttachmentRepo) Delete(ctx context.Context, id string) error {
 // ─── MentionRepository mock ───
 
 type MockMentionRepo struct {
-	SaveMentionsFn        func(ctx context.Context, messageID string, userIDs []string) error
-	DeleteByMessageIDFn   func(ctx context.Context, messageID string) error
-	GetMentionedUserIDsFn func(ctx context.Context, messageID string) ([]string, error)
-	GetByMessageIDsFn     func(ctx context.Context, messageIDs []string) (map[string][]string, error)
+	SaveMentionsFn         func(ctx context.Context, messageID string, userIDs []string) error
+	SaveMentionsWithKindFn func(ctx context.Context, messageID string, userIDs []string, kind string) error
+	DeleteByMessageIDFn    func(ctx context.Context, messageID string) error
+	GetMentionedUserIDsFn  func(ctx context.Context, messageID string) ([]string, error)
+	GetByMessageIDsFn      func(ctx context.Context, messageIDs []string) (map[string][]string, error)
 }
 
 func (m *MockMentionRepo) SaveMentions(ctx context.Context, messageID string, userIDs []string) error {
@@ -784,6 +928,12 @@ func (m *MockMentionRepo) SaveMentions(ctx context.Context, messageID string, us
 	}
 	return nil
 }
+func (m *MockMentionRepo) SaveMentionsWithKind(ctx context.Context, messageID string, userIDs []string, kind string) error {
+	if m.SaveMentionsWithKindFn != nil {
+		return m.SaveMentionsWithKindFn(ctx, messageID, userIDs, kind)
+	}
+	return nil
+}
 func (m *MockMentionRepo) DeleteByMessageID(ctx context.Context, messageID string) error {
 	if m.DeleteByMessageIDFn != nil {
 		return m.DeleteByMessageIDFn(ctx, messageID)
@@ -833,9 +983,11 @@ func (m *MockRoleMentionRepo) GetByMessageIDs(ctx context.Context, messageIDs []
 // ─── ReactionRepository mock ───
 
 type MockReactionRepo struct {
-	ToggleFn          func(ctx context.Context, messageID, userID, emoji string) (bool, error)
-	GetByMessageIDFn  func(ctx context.Context, messageID string) ([]models.ReactionGroup, error)
-	GetByMessageIDsFn func(ctx context.Context, messageIDs []string) (map[string][]models.ReactionGroup, error)
+	ToggleFn             func(ctx context.Context, messageID, userID, emoji string) (bool, error)
+	GetByMessageIDFn     func(ctx context.Context, messageID string) ([]models.ReactionGroup, error)
+	GetByMessageIDsFn    func(ctx context.Context, messageIDs []string) (map[string][]models.ReactionGroup, error)
+	CountUserReactionsFn func(ctx context.Context, messageID, userID string) (int, error)
+	ListReactorsFn       func(ctx context.Context, messageID, emoji string, limit, offset int) ([]models.ReactionUser, int, error)
 }
 
 func (m *MockReactionRepo) Toggle(ctx context.Context, messageID, userID, emoji string) (bool, error) {
@@ -856,6 +1008,18 @@ func (m *MockReactionRepo) GetByMessageIDs(ctx context.Context, messageIDs []str
 	}
 	return nil, nil
 }
+func (m *MockReactionRepo) CountUserReactions(ctx context.Context, messageID, userID string) (int, error) {
+	if m.CountUserReactionsFn != nil {
+		return m.CountUserReactionsFn(ctx, messageID, userID)
+	}
+	return 0, nil
+}
+func (m *MockReactionRepo) ListReactors(ctx context.Context, messageID, emoji string, limit, offset int) ([]models.ReactionUser, int, error) {
+	if m.ListReactorsFn != nil {
+		return m.ListReactorsFn(ctx, messageID, emoji, limit, offset)
+	}
+	return nil, 0, nil
+}
 
 // ─── ChannelPermResolver mock ───
 
@@ -874,11 +1038,28 @@ func (m *MockChannelPermResolver) ResolveChannelPermissionsFresh(ctx context.Con
 	return m.ResolveChannelPermissions(ctx, userID, channelID)
 }
 
+// AllowedViewers mirrors the real implementation's filter (without the cache) so
+// broadcast-scoping tests exercise the same view/read decision.
+func (m *MockChannelPermResolver) AllowedViewers(ctx context.Context, channelID string, candidates []string) []string {
+	var allowed []string
+	for _, userID := range candidates {
+		perms, err := m.ResolveChannelPermissions(ctx, userID, channelID)
+		if err != nil {
+			continue
+		}
+		if perms.Has(models.PermViewChannel) && perms.Has(models.PermReadMessages) {
+			allowed = append(allowed, userID)
+		}
+	}
+	return allowed
+}
+
 // ─── ReadStateRepository mock ───
 
 type MockReadStateRepo struct {
 	UpsertFn                    func(ctx context.Context, userID, channelID, messageID string) error
 	GetUnreadCountsFn           func(ctx context.Context, userID, serverID string) ([]models.UnreadInfo, error)
+	GetAllUnreadCountsFn        func(ctx context.Context, userID string) ([]models.ServerUnreadInfo, error)
 	MarkAllReadFn               func(ctx context.Context, userID, serverID string) error
 	IncrementUnreadCountsFn     func(ctx context.Context, channelID, excludeUserID string) error
 	DecrementUnreadForDeletedFn func(ctx context.Context, channelID, authorID string, deletedAt time.Time) error
@@ -897,6 +1078,12 @@ func (m *MockReadStateRepo) GetUnreadCounts(ctx context.Context, userID, serverI
 	}
 	return nil, nil
 }
+func (m *MockReadStateRepo) GetAllUnreadCounts(ctx context.Context, userID string) ([]models.ServerUnreadInfo, error) {
+	if m.GetAllUnreadCountsFn != nil {
+		return m.GetAllUnreadCountsFn(ctx, userID)
+	}
+	return nil, nil
+}
 func (m *MockReadStateRepo) MarkAllRead(ctx context.Context, userID, serverID string) error {
 	if m.MarkAllReadFn != nil {
 		return m.MarkAllReadFn(ctx, userID, serverID)
@@ -58,6 +58,12 @@ type Client struct {
 	// meant for them. Empty for clients that predate it.
 	deviceID string
 
+	// platform is the client platform behind this connection ("desktop", "web"
+	// or "mobile"), from the connect-time client_type hint. Purely cosmetic —
+	// it feeds the presence platform indicator and is never used for authz.
+	// Empty for clients that don't send the hint; immutable after construction.
+	platform string
+
 	// events is the per-connection inbound queue drained by a single eventPump
 	// goroutine. ReadPump enqueues here (except heartbeat, handled inline) so a
 	// connection's events are processed strictly in arrival order — a voice_join
@@ -81,6 +87,15 @@ type Client struct {
 	// updated on join/leave. Used by BroadcastToServer for filtering.
 	serverIDs []string
 
+	// View subscription (OpSubscribe): the channel this connection is actively
+	// viewing. View-scoped events (typing) are only delivered to connections
+	// focused on their channel. hasSubscribed distinguishes a client that
+	// navigated away (filter applies) from one that never sent the op at all —
+	// older clients keep receiving everything. Accessed under Hub.mu.
+	subscribedServerID  string
+	subscribedChannelID string
+	hasSubscribed       bool
+
 	// prefStatus: user's preferred presence loaded from DB at connect time.
 	// Used by addClient to set initial per-connection status.
 	prefStatus string
@@ -90,6 +105,15 @@ type Client struct {
 	// Accessed under Hub.mu.
 	status string
 
+	// lastHeartbeat: when this connection last sent a heartbeat. Stamped at
+	// register and on every heartbeat; read by the hub's auto-idle sweep
+	// (see idle.go). Accessed under Hub.mu.
+	lastHeartbeat time.Time
+
+	// autoIdled: the sweep (not the user) pushed this connection to "idle".
+	// Kept separate from status so the next heartbeat restores the preferred
+	// status without clobbering a manually chosen idle. Accessed under Hub.mu.
+	autoIdled bool
 }
 
 // ReadPump reads messages from the WebSocket and dispatches events.
@@ -141,11 +165,13 @@ var eventHandlers map[string]func(c *Client, event Event)
 func init() {
 	eventHandlers = map[string]func(c *Client, event Event){
 		OpTyping:                (*Client).handleTyping,
+		OpSubscribe:             (*Client).handleSubscribe,
 		OpPresenceUpdate:        (*Client).handlePresenceUpdate,
 		OpVoiceJoin:             (*Client).handleVoiceJoin,
 		OpVoiceLeave:            func(c *Client, _ Event) { c.handleVoiceLeave() },
 		OpVoiceStateUpdateReq:   (*Client).handleVoiceStateUpdate,
 		OpVoiceAdminStateUpdate: (*Client).handleVoiceAdminStateUpdate,
+		OpVoicePrioritySpeaker:  (*Client).handleVoicePrioritySpeaker,
 		OpVoiceMoveUser:         (*Client).handleVoiceMoveUser,
 		OpVoiceDisconnectUser:   (*Client).handleVoiceDisconnectUser,
 		OpScreenShareWatch:      (*Client).handleScreenShareWatch,
@@ -157,6 +183,10 @@ func init() {
 		OpP2PCallEnd:            (*Client).handleP2PCallEnd,
 		OpP2PCallResume:         (*Client).handleP2PCallResume,
 		OpP2PSignal:             (*Client).handleP2PSignal,
+		OpGroupCallInitiate:     (*Client).handleGroupCallInitiate,
+		OpGroupCallAccept:       (*Client).handleGroupCallAccept,
+		OpGroupCallDecline:      (*Client).handleGroupCallDecline,
+		OpGroupCallLeave:        (*Client).handleGroupCallLeave,
 	}
 }
 
@@ -187,6 +217,7 @@ func (c *Client) handleEvent(event Event) {
 		// Drop the connection (mirrors sendEvent's buffer-full behavior) rather than
 		// block ReadPump, which would stall heartbeat handling and force disconnects.
 		log.Printf("[ws] event queue full for user %s, dropping connection", c.userID)
+		c.sendDisconnect(DisconnectReasonSlowConsumer)
 		c.hub.unregister <- c
 	}
 }
@@ -242,13 +273,15 @@ func (c *Client) allowEvent(op string) bool {
 	}
 }
 
-// handleHeartbeat resets the read deadline and acks the client's heartbeat.
+// handleHeartbeat resets the read deadline, acks the client's heartbeat, and
+// stamps liveness for the auto-idle sweep (lifting auto-idle if it was set).
 func (c *Client) handleHeartbeat(_ Event) {
 	if err := c.conn.SetReadDeadline(time.Now().Add(pongWait)); err != nil {
 		log.Printf("[ws] failed to set read deadline for user %s: %v", c.userID, err)
 		return
 	}
 	c.sendEvent(Event{Op: OpHeartbeatAck})
+	c.hub.touchHeartbeat(c)
 }
 
 // handlePresenceUpdate processes a client presence change.
@@ -275,6 +308,9 @@ func (c *Client) handlePresenceUpdate(event Event) {
 
 	c.hub.mu.Lock()
 	c.status = data.Status
+	// An explicit choice always clears the sweep's flag — whatever the user
+	// picked is now the status the next heartbeat must not "restore" away.
+	c.autoIdled = false
 	aggregate := c.hub.computeAggregateStatusLocked(c.userID)
 	c.hub.mu.Unlock()
 
@@ -307,6 +343,24 @@ func (c *Client) handleTyping(event Event) {
 	}
 }
 
+// handleSubscribe records which channel this connection is viewing. No
+// validation needed: the subscription only ever narrows what the client
+// receives — claiming a channel it can't read gains nothing, because every
+// view-scoped broadcast is still permission-filtered before it gets here.
+func (c *Client) handleSubscribe(event Event) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return
+	}
+
+	var sub SubscribeData
+	if err := json.Unmarshal(dataBytes, &sub); err != nil {
+		return
+	}
+
+	c.hub.SetClientSubscription(c, sub.ServerID, sub.ChannelID)
+}
+
 // handleDMTyping broadcasts a DM typing indicator to the other participant only.
 // Uses a callback to avoid Hub depending on DM repo directly.
 func (c *Client) handleDMTyping(event Event) {
@@ -359,7 +413,7 @@ func (c *Client) handleVoiceJoin(event Event) {
 
 	if c.hub.onVoiceJoin != nil {
 		info := c.hub.getUserInfo(c.userID)
-		c.hub.onVoiceJoin(c.userID, info.Username, info.DisplayName, info.AvatarURL, data.ChannelID, data.IsMuted, data.IsDeafened)
+		c.hub.onVoiceJoin(c.userID, info.Username, info.DisplayName, info.AvatarURL, c.platform, data.ChannelID, data.IsMuted, data.IsDeafened)
 	}
 }
 
@@ -412,6 +466,22 @@ func (c *Client) handleVoiceAdminStateUpdate(event Event) {
 	}
 }
 
+func (c *Client) handleVoicePrioritySpeaker(event Event) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return
+	}
+
+	var data VoicePrioritySpeakerData
+	if err := json.Unmarshal(dataBytes, &data); err != nil {
+		return
+	}
+
+	if c.hub.onVoicePrioritySpeaker != nil {
+		c.hub.onVoicePrioritySpeaker(c.userID, data.IsPrioritySpeaker)
+	}
+}
+
 func (c *Client) handleVoiceMoveUser(event Event) {
 	dataBytes, err := json.Marshal(event.Data)
 	if err != nil {
@@ -573,6 +643,61 @@ func (c *Client) handleP2PCallResume(event Event) {
 	}
 }
 
+func (c *Client) handleGroupCallInitiate(event Event) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return
+	}
+
+	var data GroupCallInitiateData
+	if err := json.Unmarshal(dataBytes, &data); err != nil {
+		return
+	}
+
+	if len(data.ReceiverIDs) == 0 || data.CallType == "" {
+		log.Printf("[ws] group_call_initiate missing fields from user %s", c.userID)
+		return
+	}
+
+	if c.hub.onGroupCallInitiate != nil {
+		c.hub.onGroupCallInitiate(c.userID, c.sessionID, data)
+	}
+}
+
+// handleGroupCallAction covers accept/decline/leave — identical envelopes, one decoder.
+func (c *Client) handleGroupCallAction(event Event, op string, cb GroupCallActionCallback) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return
+	}
+
+	var data GroupCallActionData
+	if err := json.Unmarshal(dataBytes, &data); err != nil {
+		return
+	}
+
+	if data.CallID == "" {
+		log.Printf("[ws] %s missing call_id from user %s", op, c.userID)
+		return
+	}
+
+	if cb != nil {
+		cb(c.userID, data.CallID)
+	}
+}
+
+func (c *Client) handleGroupCallAccept(event Event) {
+	c.handleGroupCallAction(event, OpGroupCallAccept, c.hub.onGroupCallAccept)
+}
+
+func (c *Client) handleGroupCallDecline(event Event) {
+	c.handleGroupCallAction(event, OpGroupCallDecline, c.hub.onGroupCallDecline)
+}
+
+func (c *Client) handleGroupCallLeave(event Event) {
+	c.handleGroupCallAction(event, OpGroupCallLeave, c.hub.onGroupCallLeave)
+}
+
 // handleP2PSignal relays WebRTC SDP/ICE data to the other peer.
 func (c *Client) handleP2PSignal(event Event) {
 	dataBytes, err := json.Marshal(event.Data)
@@ -601,6 +726,21 @@ func (c *Client) markClosed() {
 	c.closeOnce.Do(func() { close(c.done) })
 }
 
+// sendDisconnect pushes an OpDisconnect control frame straight onto the socket,
+// bypassing the send buffer — the client that needs it most (a slow consumer)
+// is exactly the one whose buffer is full. Best-effort under the usual write
+// deadline; a genuinely dead socket just errors and the close proceeds.
+func (c *Client) sendDisconnect(reason string) {
+	if c.conn == nil {
+		return
+	}
+	data, err := json.Marshal(disconnectEvent(reason))
+	if err != nil {
+		return
+	}
+	_ = c.writeMessage(websocket.TextMessage, data)
+}
+
 // trySend enqueues data without ever sending on a closed channel (send is never closed).
 // Returns false only when the buffer is full and the client is still open — the caller
 // should then drop the connection. A client already being torn down returns true (no-op).
@@ -629,6 +769,7 @@ func (c *Client) sendEvent(event Event) {
 
 	if !c.trySend(data) {
 		log.Printf("[ws] send buffer full for user %s, dropping connection", c.userID)
+		c.sendDisconnect(DisconnectReasonSlowConsumer)
 		c.hub.unregister <- c
 	}
 }
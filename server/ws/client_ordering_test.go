@@ -19,7 +19,7 @@ func TestClient_EventPump_JoinBeforeUpdate(t *testing.T) {
 	releaseJoin := make(chan struct{})
 
 	h := &Hub{unregister: make(chan *Client, 8)}
-	h.onVoiceJoin = func(_, _, _, _, _ string, _, _ bool) {
+	h.onVoiceJoin = func(_, _, _, _, _, _ string, _, _ bool) {
 		close(joinStarted)
 		<-releaseJoin // hold the worker inside the join handler
 		mu.Lock()
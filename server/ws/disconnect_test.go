@@ -0,0 +1,84 @@
+package ws
+
+import (
+	"testing"
+)
+
+// Each reason maps to its own backoff hint; codes where reconnecting is
+// pointless (banned) carry zero so the client does not retry at all.
+func TestDisconnectEvent_RetryAfterMapping(t *testing.T) {
+	cases := []struct {
+		reason     string
+		retryAfter int
+	}{
+		{DisconnectReasonSlowConsumer, 5},
+		{DisconnectReasonServerShutdown, 30},
+		{DisconnectReasonBanned, 0},
+		{DisconnectReasonSessionReplaced, 0},
+	}
+	for _, tc := range cases {
+		ev := disconnectEvent(tc.reason)
+		if ev.Op != OpDisconnect {
+			t.Fatalf("%s: op = %s, want %s", tc.reason, ev.Op, OpDisconnect)
+		}
+		data := ev.Data.(DisconnectData)
+		if data.Reason != tc.reason {
+			t.Errorf("reason = %s, want %s", data.Reason, tc.reason)
+		}
+		if data.RetryAfterSeconds != tc.retryAfter {
+			t.Errorf("%s: retry_after = %d, want %d", tc.reason, data.RetryAfterSeconds, tc.retryAfter)
+		}
+	}
+}
+
+// DisconnectUserWithReason must unregister every connection the user has,
+// and only that user's.
+func TestDisconnectUserWithReason_UnregistersAllSessions(t *testing.T) {
+	h := &Hub{
+		clients:    make(map[string]map[*Client]bool),
+		unregister: make(chan *Client, 8),
+	}
+	target1 := &Client{hub: h, userID: "u-target", send: make(chan []byte, 4), done: make(chan struct{})}
+	target2 := &Client{hub: h, userID: "u-target", send: make(chan []byte, 4), done: make(chan struct{})}
+	other := &Client{hub: h, userID: "u-other", send: make(chan []byte, 4), done: make(chan struct{})}
+	h.clients["u-target"] = map[*Client]bool{target1: true, target2: true}
+	h.clients["u-other"] = map[*Client]bool{other: true}
+
+	h.DisconnectUserWithReason("u-target", DisconnectReasonBanned)
+
+	if len(h.unregister) != 2 {
+		t.Fatalf("%d clients unregistered, want 2", len(h.unregister))
+	}
+	for i := 0; i < 2; i++ {
+		if c := <-h.unregister; c.userID != "u-target" {
+			t.Errorf("unregistered %s, want u-target", c.userID)
+		}
+	}
+}
+
+// Shutdown marks every client closed and empties the connection maps, so a
+// late broadcast finds nobody to send to.
+func TestShutdown_ClosesAllClients(t *testing.T) {
+	h := &Hub{
+		clients:       make(map[string]map[*Client]bool),
+		serverClients: make(map[string]map[*Client]bool),
+	}
+	a := &Client{hub: h, userID: "u-a", send: make(chan []byte, 4), done: make(chan struct{})}
+	b := &Client{hub: h, userID: "u-b", send: make(chan []byte, 4), done: make(chan struct{})}
+	h.clients["u-a"] = map[*Client]bool{a: true}
+	h.clients["u-b"] = map[*Client]bool{b: true}
+	h.serverClients["srv"] = map[*Client]bool{a: true, b: true}
+
+	h.Shutdown()
+
+	for _, c := range []*Client{a, b} {
+		select {
+		case <-c.done:
+		default:
+			t.Fatalf("client %s not marked closed after Shutdown", c.userID)
+		}
+	}
+	if len(h.clients) != 0 || len(h.serverClients) != 0 {
+		t.Errorf("connection maps not reset: %d users, %d servers", len(h.clients), len(h.serverClients))
+	}
+}
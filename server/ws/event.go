@@ -1,6 +1,10 @@
 package ws
 
-import "github.com/akinalp/mqvi/models"
+import (
+	"time"
+
+	"github.com/akinalp/mqvi/models"
+)
 
 // Event is the WebSocket message format.
 // Seq is a monotonic counter for gap detection on the client side.
@@ -18,6 +22,9 @@ const (
 	OpHeartbeat      = "heartbeat"
 	OpTyping         = "typing"
 	OpPresenceUpdate = "presence_update"
+	// OpSubscribe declares what the client is actively viewing. The hub uses it
+	// to route view-scoped events (typing) only to connections that render them.
+	OpSubscribe = "subscribe"
 )
 
 // Server -> Client
@@ -27,6 +34,9 @@ const (
 	OpMessageCreate = "message_create"
 	OpMessageUpdate = "message_update"
 	OpMessageDelete = "message_delete"
+	// OpMessageBulkDelete carries every ID of a moderation batch in one event,
+	// so clearing a raid is one client update instead of a hundred.
+	OpMessageBulkDelete = "message_bulk_delete"
 	OpChannelCreate  = "channel_create"
 	OpChannelUpdate  = "channel_update"
 	OpChannelDelete  = "channel_delete"
@@ -34,7 +44,11 @@ const (
 	OpCategoryUpdate = "category_update"
 	OpCategoryDelete = "category_delete"
 	OpTypingStart    = "typing_start"
+	OpTypingStop     = "typing_stop"
 	OpPresence      = "presence_update"
+	// OpCustomStatusUpdate — a user's custom status message changed (or expired).
+	// Scoped to friends and shared-server members, never to strangers.
+	OpCustomStatusUpdate = "custom_status_update"
 	OpMemberJoin    = "member_join"
 	OpMemberLeave   = "member_leave"
 	OpMemberUpdate  = "member_update"
@@ -51,11 +65,25 @@ const (
 	// Broadcast to all server members; only PermApproveMembers holders render it.
 	OpJoinRequestUpdate = "join_request_update"
 
-	OpMessagePin   = "message_pin"
-	OpMessageUnpin = "message_unpin"
+	// OpWelcomeUpdate carries the server's new welcome screen / rules config.
+	// OpRulesAccept tells a member's other devices that the rules gate lifted.
+	OpWelcomeUpdate = "welcome_update"
+	OpRulesAccept   = "rules_accept"
+
+	OpMessagePin        = "message_pin"
+	OpMessageUnpin      = "message_unpin"
+	OpMessagePinReorder = "message_pin_reorder"
 
 	OpReactionUpdate = "reaction_update"
 
+	// OpMessageEmbedUpdate delivers a message's link embed once the async
+	// unfurl finishes — the message itself has already been broadcast without it.
+	OpMessageEmbedUpdate = "message_embed_update"
+
+	// OpKeywordHighlight — a registered highlight word appeared in a channel
+	// message the recipient can read. Sent only to that user.
+	OpKeywordHighlight = "keyword_highlight"
+
 	OpChannelPermissionUpdate = "channel_permission_update"
 	OpChannelPermissionDelete = "channel_permission_delete"
 
@@ -72,10 +100,14 @@ const (
 	OpDMMessagePin     = "dm_message_pin"
 	OpDMMessageUnpin   = "dm_message_unpin"
 	OpDMRead            = "dm_read"
+	OpDMReadReceipt     = "dm_read_receipt"
 	OpDMSettingsUpdate  = "dm_settings_update"
 	OpDMRequestAccept      = "dm_request_accept"
 	OpDMRequestDecline     = "dm_request_decline"
 	OpDMChannelStatusChange = "dm_channel_status_change"
+	// OpDMGroupMemberUpdate announces a member added to / removed from a group
+	// DM; data carries the channel id, the action and the affected user id.
+	OpDMGroupMemberUpdate = "dm_group_member_update"
 
 	// Block operations
 	OpUserBlock   = "user_block"
@@ -90,12 +122,32 @@ const (
 	OpVoiceMessageUpdate          = "voice_message_update"      // ephemeral voice chat message edited
 	OpVoiceMessageDelete          = "voice_message_delete"      // ephemeral voice chat message deleted
 	OpScreenShareViewerUpdate     = "screen_share_viewer_update"
+	OpVoiceRecordingState         = "voice_recording_state"     // recording started/stopped in a voice channel
 
 	// Friend operations
 	OpFriendRequestCreate  = "friend_request_create"
 	OpFriendRequestAccept  = "friend_request_accept"
 	OpFriendRequestDecline = "friend_request_decline"
 	OpFriendRemove         = "friend_remove"
+
+	// OpServerShutdown — the instance is draining for a restart/deploy. Sent to
+	// every connection before new ones are refused; clients should finish what
+	// they're doing and expect the socket to drop, then reconnect with backoff.
+	OpServerShutdown = "server_shutdown"
+
+	// OpDisconnect — the server is about to close THIS connection on purpose.
+	// Carries a reason code and a suggested retry delay (DisconnectData) so the
+	// client can back off and show the right message instead of blindly
+	// hammering reconnects. Best-effort: a connection that dies on its own
+	// never gets one.
+	OpDisconnect = "disconnect"
+
+	// OpResume — sent instead of OpReady when a reconnect with
+	// ?session_id=&last_seq= could be resumed. The frames that follow carry
+	// their original seq and replay what the connection missed. Receiving a
+	// plain OpReady after a resume attempt means the gap was not coverable
+	// (see resume.go) and the client must do a full resync.
+	OpResume = "resume"
 )
 
 // Client -> Server voice operations
@@ -104,6 +156,7 @@ const (
 	OpVoiceLeave            = "voice_leave"
 	OpVoiceStateUpdateReq   = "voice_state_update_request"
 	OpVoiceAdminStateUpdate = "voice_admin_state_update"
+	OpVoicePrioritySpeaker  = "voice_priority_speaker" // toggle own priority-speaker flag (PermPrioritySpeaker)
 	OpVoiceMoveUser        = "voice_move_user"
 	OpVoiceDisconnectUser  = "voice_disconnect_user"
 	OpScreenShareWatch     = "screen_share_watch"
@@ -114,7 +167,8 @@ const (
 const (
 	OpVoiceForceMove       = "voice_force_move"
 	OpVoiceForceDisconnect = "voice_force_disconnect"
-	OpVoiceAFKKick         = "voice_afk_kick" // user kicked for inactivity
+	OpVoiceAFKKick         = "voice_afk_kick"  // user kicked for inactivity
+	OpVoiceReconnect       = "voice_reconnect" // instance migrated — re-request a token and rejoin
 )
 
 // P2P Call signaling flow:
@@ -134,6 +188,22 @@ const (
 	OpP2PCallResume = "p2p_call_resume"
 )
 
+// Group call operations. Media rides an ad-hoc LiveKit room on a platform
+// instance, so there is no signal relay — the server only coordinates
+// membership and hands out room tokens.
+const (
+	OpGroupCallInitiate = "group_call_initiate"
+	OpGroupCallAccept   = "group_call_accept"
+	OpGroupCallDecline  = "group_call_decline"
+	OpGroupCallLeave    = "group_call_leave"
+	// OpGroupCallUpdate — a participant's state changed (joined/declined/left).
+	OpGroupCallUpdate = "group_call_update"
+	// OpGroupCallToken — sent only to the joining user's connections: their
+	// LiveKit room credentials.
+	OpGroupCallToken = "group_call_token"
+	OpGroupCallEnd   = "group_call_end"
+)
+
 // E2EE operations
 const (
 	OpDeviceKeyChange  = "device_key_change"
@@ -168,25 +238,127 @@ type ReadyData struct {
 	Servers         []models.ServerListItem `json:"servers"`
 	MutedServerIDs  []string          `json:"muted_server_ids"`
 	MutedChannelIDs []string          `json:"muted_channel_ids"`
-	PrefStatus      string            `json:"pref_status"`
+	// MutedCategoryIDs cascade to the category's channels for notification/badge
+	// purposes only — the unread dot ignores them.
+	MutedCategoryIDs []string `json:"muted_category_ids"`
+	PrefStatus       string   `json:"pref_status"`
+	// CustomStatus/CustomStatusEmoji echo the connecting user's own status
+	// message; other users' statuses arrive in friend/member DTOs and via
+	// OpCustomStatusUpdate.
+	CustomStatus      *string `json:"custom_status"`
+	CustomStatusEmoji *string `json:"custom_status_emoji"`
+	// PendingFriendRequests is the incoming-request count, so an offline stretch
+	// can't hide a friend request behind an extra fetch.
+	PendingFriendRequests int `json:"pending_friend_requests"`
+	// UnreadDMs is one compact row per DM conversation with unread messages —
+	// counts and latest activity, never message history.
+	UnreadDMs []models.DMUnreadSummary `json:"unread_dms"`
+	// NotificationSettings is the granular per-scope view (levels, @everyone
+	// suppression); the muted-ID lists above stay as-is for older clients.
+	NotificationSettings []models.NotificationSetting `json:"notification_settings"`
 }
 
 type PresenceData struct {
 	UserID string `json:"user_id"`
 	Status string `json:"status"`
 	IsAuto bool   `json:"is_auto,omitempty"`
+	// ClientPlatform is the best platform across the user's connections
+	// ("desktop" > "web" > "mobile"). Omitted when unknown and always omitted
+	// on offline broadcasts, so invisible users don't leak a device type.
+	ClientPlatform string `json:"client_platform,omitempty"`
+}
+
+// CustomStatusUpdateData carries a user's new custom status. All fields nil =
+// the status was cleared (explicitly or by the expiry sweep).
+type CustomStatusUpdateData struct {
+	UserID            string     `json:"user_id"`
+	CustomStatus      *string    `json:"custom_status"`
+	CustomStatusEmoji *string    `json:"custom_status_emoji"`
+	ExpiresAt         *time.Time `json:"custom_status_expires_at,omitempty"`
+}
+
+// ResumeData acknowledges a session resume. SessionID is the NEW connection's
+// session (the old one is consumed); Replayed is how many missed frames follow.
+// Replayed frames may interleave with newer live events — clients order and
+// deduplicate by seq.
+type ResumeData struct {
+	SessionID string `json:"session_id"`
+	Replayed  int    `json:"replayed"`
+}
+
+// ServerShutdownData tells clients how long the drain window is, so a UI can
+// time its "reconnecting shortly" hint instead of guessing.
+type ServerShutdownData struct {
+	DrainSeconds int `json:"drain_seconds"`
+}
+
+// Disconnect reason codes (OpDisconnect). The code picks the client's message
+// and whether reconnecting is worth trying at all.
+const (
+	// DisconnectReasonSlowConsumer — this connection fell too far behind the
+	// event stream (full send buffer or wedged inbound worker). Reconnecting
+	// is fine; the backlog is gone.
+	DisconnectReasonSlowConsumer = "slow_consumer"
+	// DisconnectReasonServerShutdown — the instance is going away. Clients
+	// should back off rather than reconnect against a draining server.
+	DisconnectReasonServerShutdown = "server_shutdown"
+	// DisconnectReasonBanned — the account was banned. Do not reconnect.
+	DisconnectReasonBanned = "banned"
+	// DisconnectReasonSessionReplaced — a newer connection took over this
+	// session. Do not reconnect; the other connection is the live one.
+	DisconnectReasonSessionReplaced = "session_replaced"
+)
+
+// DisconnectData is the OpDisconnect payload. RetryAfterSeconds is the
+// suggested minimum wait before reconnecting; zero means "do not auto-reconnect".
+type DisconnectData struct {
+	Reason            string `json:"reason"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+// disconnectRetryAfter maps each reason to its suggested backoff. Absent (or
+// zero) means auto-reconnect is pointless — the condition won't clear on its own.
+var disconnectRetryAfter = map[string]int{
+	DisconnectReasonSlowConsumer:   5,
+	DisconnectReasonServerShutdown: 30,
+}
+
+// disconnectEvent builds the control event sent right before a deliberate close.
+func disconnectEvent(reason string) Event {
+	return Event{
+		Op: OpDisconnect,
+		Data: DisconnectData{
+			Reason:            reason,
+			RetryAfterSeconds: disconnectRetryAfter[reason],
+		},
+	}
 }
 
 type TypingData struct {
 	ChannelID string `json:"channel_id"`
 }
 
+// SubscribeData is the client's current view. Empty fields mean "nothing of
+// that kind in view" (e.g. the user navigated to DMs or settings). Sent on
+// every channel switch, so it always reflects the latest focus.
+type SubscribeData struct {
+	ServerID  string `json:"server_id"`
+	ChannelID string `json:"channel_id"`
+}
+
 type TypingStartData struct {
 	UserID    string `json:"user_id"`
 	Username  string `json:"username"`
 	ChannelID string `json:"channel_id"`
 }
 
+// TypingStopData — the hub emits this itself: after 8s of silence or as soon
+// as the typer's message is broadcast. Clients no longer need local timers.
+type TypingStopData struct {
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+}
+
 type DMTypingStartData struct {
 	UserID      string `json:"user_id"`
 	Username    string `json:"username"`
@@ -217,6 +389,23 @@ type VoiceAdminStateUpdateData struct {
 	IsServerDeafened *bool  `json:"is_server_deafened,omitempty"`
 }
 
+// VoicePrioritySpeakerData — user toggles their own priority-speaker flag.
+// One flag per user; the permission check happens against their current channel.
+type VoicePrioritySpeakerData struct {
+	IsPrioritySpeaker bool `json:"is_priority_speaker"`
+}
+
+// KeywordHighlightData — payload for OpKeywordHighlight. Carries just enough to
+// render a notification and jump to the message; the message itself arrives via
+// the normal message_create broadcast.
+type KeywordHighlightData struct {
+	MessageID string `json:"message_id"`
+	ChannelID string `json:"channel_id"`
+	ServerID  string `json:"server_id"`
+	AuthorID  string `json:"author_id"`
+	Keyword   string `json:"keyword"`
+}
+
 type VoiceMoveUserData struct {
 	TargetUserID    string `json:"target_user_id"`
 	TargetChannelID string `json:"target_channel_id"`
@@ -231,22 +420,30 @@ type VoiceForceMoveData struct {
 	ChannelID string `json:"channel_id"`
 }
 
+// VoiceReconnectData — server tells client its LiveKit token is stale (the
+// server moved to another instance); re-request a token for the same channel.
+type VoiceReconnectData struct {
+	ChannelID string `json:"channel_id"`
+}
+
 // VoiceStateUpdateBroadcast — broadcast payload when a user's voice state changes.
 type VoiceStateUpdateBroadcast struct {
-	UserID           string `json:"user_id"`
-	ChannelID        string `json:"channel_id"`
-	ChannelName      string `json:"channel_name,omitempty"` // set on "join" — feeds cross-server voice popups
-	ServerID         string `json:"server_id,omitempty"`    // set on "join" — attributes the entry to a server
-	Username         string `json:"username"`
-	DisplayName      string `json:"display_name"`
-	AvatarURL        string `json:"avatar_url"`
-	IsMuted          bool   `json:"is_muted"`
-	IsDeafened       bool   `json:"is_deafened"`
-	IsStreaming      bool   `json:"is_streaming"`
-	ShareQuality     string `json:"share_quality,omitempty"`
-	IsServerMuted    bool   `json:"is_server_muted"`
-	IsServerDeafened bool   `json:"is_server_deafened"`
-	Action           string `json:"action"` // "join", "leave", "update"
+	UserID            string `json:"user_id"`
+	ChannelID         string `json:"channel_id"`
+	ChannelName       string `json:"channel_name,omitempty"` // set on "join" — feeds cross-server voice popups
+	ServerID          string `json:"server_id,omitempty"`    // set on "join" — attributes the entry to a server
+	Username          string `json:"username"`
+	DisplayName       string `json:"display_name"`
+	AvatarURL         string `json:"avatar_url"`
+	IsMuted           bool   `json:"is_muted"`
+	IsDeafened        bool   `json:"is_deafened"`
+	IsStreaming       bool   `json:"is_streaming"`
+	ShareQuality      string `json:"share_quality,omitempty"`
+	IsServerMuted     bool   `json:"is_server_muted"`
+	IsServerDeafened  bool   `json:"is_server_deafened"`
+	IsPrioritySpeaker bool   `json:"is_priority_speaker"` // clients duck other participants while this user talks
+	ClientPlatform    string `json:"client_platform,omitempty"` // set on "join" — platform the user joined voice from
+	Action            string `json:"action"` // "join", "leave", "update"
 }
 
 // VoiceStatesSyncData — bulk voice state sync sent on connection.
@@ -266,6 +463,17 @@ type VoiceChannelTimerStopData struct {
 	ChannelID string `json:"channel_id"`
 }
 
+// VoiceRecordingStateBroadcast — recording indicator for a voice channel.
+// Sent server-wide when a recording starts (active=true) or stops, so every
+// participant (and prospective joiner) can see the channel is being recorded.
+type VoiceRecordingStateBroadcast struct {
+	ChannelID string `json:"channel_id"`
+	ServerID  string `json:"server_id"`
+	Active    bool   `json:"active"`
+	StartedBy string `json:"started_by"`
+	StartedAt int64  `json:"started_at,omitempty"` // Unix ms, set while active
+}
+
 // VoiceMessageDeleteData — id + channel_id so clients can locate the row.
 type VoiceMessageDeleteData struct {
 	ID        string `json:"id"`
@@ -274,19 +482,21 @@ type VoiceMessageDeleteData struct {
 
 // VoiceStateItem mirrors models.VoiceState without creating a ws -> models dependency.
 type VoiceStateItem struct {
-	UserID           string `json:"user_id"`
-	ChannelID        string `json:"channel_id"`
-	ChannelName      string `json:"channel_name"`
-	ServerID         string `json:"server_id"`
-	Username         string `json:"username"`
-	DisplayName      string `json:"display_name"`
-	AvatarURL        string `json:"avatar_url"`
-	IsMuted          bool   `json:"is_muted"`
-	IsDeafened       bool   `json:"is_deafened"`
-	IsStreaming      bool   `json:"is_streaming"`
-	ShareQuality     string `json:"share_quality,omitempty"`
-	IsServerMuted    bool   `json:"is_server_muted"`
-	IsServerDeafened bool   `json:"is_server_deafened"`
+	UserID            string `json:"user_id"`
+	ChannelID         string `json:"channel_id"`
+	ChannelName       string `json:"channel_name"`
+	ServerID          string `json:"server_id"`
+	Username          string `json:"username"`
+	DisplayName       string `json:"display_name"`
+	AvatarURL         string `json:"avatar_url"`
+	IsMuted           bool   `json:"is_muted"`
+	IsDeafened        bool   `json:"is_deafened"`
+	IsStreaming       bool   `json:"is_streaming"`
+	ShareQuality      string `json:"share_quality,omitempty"`
+	IsServerMuted     bool   `json:"is_server_muted"`
+	IsServerDeafened  bool   `json:"is_server_deafened"`
+	IsPrioritySpeaker bool   `json:"is_priority_speaker"`
+	ClientPlatform    string `json:"client_platform,omitempty"`
 }
 
 // ScreenShareWatchData — client tells server they started/stopped watching a screen share.
@@ -330,6 +540,16 @@ type P2PCallResumeData struct {
 	CallID string `json:"call_id"`
 }
 
+type GroupCallInitiateData struct {
+	ReceiverIDs []string `json:"receiver_ids"`
+	CallType    string   `json:"call_type"` // "voice" or "video"
+}
+
+// GroupCallActionData — accept/decline/leave all name only the call.
+type GroupCallActionData struct {
+	CallID string `json:"call_id"`
+}
+
 // P2PSignalData carries WebRTC SDP/ICE data. Server relays without inspecting.
 type P2PSignalData struct {
 	CallID    string `json:"call_id"`
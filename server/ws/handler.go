@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -49,6 +51,17 @@ type ServerListProvider interface {
 	GetUserServers(ctx context.Context, userID string) ([]models.ServerListItem, error)
 }
 
+// FriendRequestCounter supplies the ready event's pending friend request badge.
+// ISP interface to avoid a ws -> services dependency.
+type FriendRequestCounter interface {
+	CountIncomingRequests(ctx context.Context, userID string) (int, error)
+}
+
+// DMUnreadProvider supplies the ready event's compact unread-DM digest.
+type DMUnreadProvider interface {
+	UnreadSummaries(ctx context.Context, userID string) ([]models.DMUnreadSummary, error)
+}
+
 // MuteChecker returns muted server IDs for the ready event.
 type MuteChecker interface {
 	GetMutedServerIDs(ctx context.Context, userID string) ([]string, error)
@@ -59,6 +72,24 @@ type ChannelMuteChecker interface {
 	GetMutedChannelIDs(ctx context.Context, userID string) ([]string, error)
 }
 
+// CategoryMuteChecker returns muted category IDs for the ready event.
+type CategoryMuteChecker interface {
+	GetMutedCategoryIDs(ctx context.Context, userID string) ([]string, error)
+}
+
+// NotificationSettingsProvider returns the user's granular per-scope notification
+// settings for the ready event.
+type NotificationSettingsProvider interface {
+	ListSettings(ctx context.Context, userID string) ([]models.NotificationSetting, error)
+}
+
+// PresencePeerProvider resolves the users whose presence is visible to a given
+// user (shared-server members + friends). Filters the ready event's online list
+// so strangers' online status never reaches the client.
+type PresencePeerProvider interface {
+	Recipients(ctx context.Context, userID string) ([]string, error)
+}
+
 // URLSigner signs file URLs before they reach the client.
 // ISP interface to avoid circular ws -> services dependency.
 type URLSigner interface {
@@ -71,10 +102,10 @@ type AppLogger interface {
 	Log(level models.LogLevel, category models.LogCategory, userID, serverID *string, message string, metadata map[string]string)
 }
 
-// AllowedOrigins is set by main.go at startup to share the same origin
-// whitelist between HTTP CORS and WebSocket upgrade.
+// AllowOrigin is set by main.go at startup to share the same origin policy
+// (exact, wildcard and regex entries) between HTTP CORS and WebSocket upgrade.
 // Electron production uses file:// protocol which sends "null" as Origin.
-var AllowedOrigins []string
+var AllowOrigin func(origin string) bool
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  4096,
@@ -94,10 +125,8 @@ var upgrader = websocket.Upgrader{
 		if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
 			return true
 		}
-		for _, allowed := range AllowedOrigins {
-			if origin == allowed {
-				return true
-			}
+		if AllowOrigin != nil && AllowOrigin(origin) {
+			return true
 		}
 		log.Printf("[ws] rejected connection from origin: %s", origin)
 		return false
@@ -106,16 +135,21 @@ var upgrader = websocket.Upgrader{
 
 // Handler handles WebSocket connection upgrades.
 type Handler struct {
-	hub                  *Hub
-	tokenValidator       TokenValidator
-	banChecker           BanChecker
-	voiceStatesProvider  VoiceStatesProvider
-	userInfoProvider     UserInfoProvider
-	serverListProvider   ServerListProvider
-	muteChecker          MuteChecker
-	channelMuteChecker   ChannelMuteChecker
-	urlSigner            URLSigner
-	incomingCallProvider IncomingCallProvider
+	hub                   *Hub
+	tokenValidator        TokenValidator
+	banChecker            BanChecker
+	voiceStatesProvider   VoiceStatesProvider
+	userInfoProvider      UserInfoProvider
+	serverListProvider    ServerListProvider
+	muteChecker           MuteChecker
+	channelMuteChecker    ChannelMuteChecker
+	categoryMuteChecker   CategoryMuteChecker
+	urlSigner             URLSigner
+	incomingCallProvider  IncomingCallProvider
+	friendReqCounter      FriendRequestCounter
+	dmUnreadProvider      DMUnreadProvider
+	notifSettingsProvider NotificationSettingsProvider
+	presencePeerProvider  PresencePeerProvider
 }
 
 // SetIncomingCallProvider wires the (optional) provider used to re-deliver a ringing
@@ -124,6 +158,18 @@ func (h *Handler) SetIncomingCallProvider(p IncomingCallProvider) {
 	h.incomingCallProvider = p
 }
 
+// SetNotificationSettingsProvider wires the (optional) source of the ready event's
+// granular notification settings. Set post-construction like the call provider.
+func (h *Handler) SetNotificationSettingsProvider(p NotificationSettingsProvider) {
+	h.notifSettingsProvider = p
+}
+
+// SetPresencePeerProvider wires the (optional) ready-event online-list filter.
+// Set post-construction like the providers above.
+func (h *Handler) SetPresencePeerProvider(p PresencePeerProvider) {
+	h.presencePeerProvider = p
+}
+
 func NewHandler(
 	hub *Hub,
 	tokenValidator TokenValidator,
@@ -133,7 +179,10 @@ func NewHandler(
 	serverListProvider ServerListProvider,
 	muteChecker MuteChecker,
 	channelMuteChecker ChannelMuteChecker,
+	categoryMuteChecker CategoryMuteChecker,
 	urlSigner URLSigner,
+	friendReqCounter FriendRequestCounter,
+	dmUnreadProvider DMUnreadProvider,
 ) *Handler {
 	return &Handler{
 		hub:                 hub,
@@ -144,7 +193,10 @@ func NewHandler(
 		serverListProvider:  serverListProvider,
 		muteChecker:         muteChecker,
 		channelMuteChecker:  channelMuteChecker,
+		categoryMuteChecker: categoryMuteChecker,
 		urlSigner:           urlSigner,
+		friendReqCounter:    friendReqCounter,
+		dmUnreadProvider:    dmUnreadProvider,
 	}
 }
 
@@ -152,6 +204,14 @@ func NewHandler(
 // Token is passed as a query param (?token=JWT) since browsers can't set
 // custom headers on WebSocket handshakes.
 func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
+	// During a drain the instance is about to go away — a connection accepted
+	// now would just be force-closed seconds later. 503 tells the client to
+	// retry with backoff (and a load balancer to go elsewhere).
+	if h.hub.IsShuttingDown() {
+		http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
 	token := r.URL.Query().Get("token")
 	if token == "" {
 		http.Error(w, "missing token", http.StatusUnauthorized)
@@ -170,6 +230,7 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 	// Fetch user info before upgrade — reject banned users early
 	var displayName, avatarURL string
 	var dbPrefStatus models.UserStatus
+	var customStatus, customStatusEmoji *string
 	if h.userInfoProvider != nil {
 		user, err := h.userInfoProvider.GetByID(r.Context(), claims.UserID)
 		if err != nil {
@@ -180,7 +241,7 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "user not found", http.StatusUnauthorized)
 			return
 		}
-		if user.IsPlatformBanned {
+		if user.PlatformBanActive() {
 			h.hub.logEvent(models.LogLevelWarn, models.LogCategoryAuth, &claims.UserID, "WS connect blocked: account suspended", nil)
 			http.Error(w, "account suspended", http.StatusForbidden)
 			return
@@ -204,6 +265,12 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 			avatarURL = *user.AvatarURL
 		}
 		dbPrefStatus = user.PrefStatus
+		// An already-expired custom status is presented as cleared — the sweep
+		// persists that within the minute, the ready payload just doesn't wait.
+		if user.CustomStatusExpiresAt == nil || user.CustomStatusExpiresAt.After(time.Now()) {
+			customStatus = user.CustomStatus
+			customStatusEmoji = user.CustomStatusEmoji
+		}
 	}
 
 	// Server-scoped ban check
@@ -239,12 +306,23 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 		deviceID = ""
 	}
 
+	// Optional platform hint for the presence indicator. Unknown values are
+	// dropped, not stored — see normalizePlatform.
+	platform := normalizePlatform(r.URL.Query().Get("client_type"))
+
+	// Reconnecting clients pass their previous session and the last Event.Seq
+	// they processed; if the gap is still buffered we replay it instead of
+	// forcing a full resync (see resume.go for the window).
+	resumeSessionID := r.URL.Query().Get("session_id")
+	lastSeq, _ := strconv.ParseInt(r.URL.Query().Get("last_seq"), 10, 64)
+
 	client := &Client{
 		hub:           h.hub,
 		conn:          conn,
 		userID:        claims.UserID,
 		sessionID:     uuid.New().String(),
 		deviceID:      deviceID,
+		platform:      platform,
 		send:          make(chan []byte, sendBufferSize),
 		events:        make(chan Event, eventQueueSize),
 		done:          make(chan struct{}),
@@ -305,20 +383,120 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 		mutedChannelIDs = []string{}
 	}
 
+	// Muted category IDs for notification suppression (cascade to their channels)
+	var mutedCategoryIDs []string
+	if h.categoryMuteChecker != nil {
+		if ids, err := h.categoryMuteChecker.GetMutedCategoryIDs(r.Context(), claims.UserID); err == nil {
+			mutedCategoryIDs = ids
+		} else {
+			log.Printf("[ws] category mute check failed for user %s: %v", claims.UserID, err)
+		}
+	}
+	if mutedCategoryIDs == nil {
+		mutedCategoryIDs = []string{}
+	}
+
+	// Pending friend requests + unread-DM digest: what accrued while the user was
+	// offline, delivered in the connect flow instead of a round of follow-up
+	// fetches. Both are non-fatal — a failed lookup costs a badge, not the session.
+	var pendingFriendRequests int
+	if h.friendReqCounter != nil {
+		if n, err := h.friendReqCounter.CountIncomingRequests(r.Context(), claims.UserID); err == nil {
+			pendingFriendRequests = n
+		} else {
+			log.Printf("[ws] friend request count failed for user %s: %v", claims.UserID, err)
+		}
+	}
+	var unreadDMs []models.DMUnreadSummary
+	if h.dmUnreadProvider != nil {
+		if sums, err := h.dmUnreadProvider.UnreadSummaries(r.Context(), claims.UserID); err == nil {
+			unreadDMs = sums
+		} else {
+			log.Printf("[ws] DM unread summary failed for user %s: %v", claims.UserID, err)
+		}
+	}
+	if unreadDMs == nil {
+		unreadDMs = []models.DMUnreadSummary{}
+	}
+
+	// Granular notification settings (level per scope, @everyone suppression).
+	// The muted-ID lists above remain the coarse view for older clients.
+	var notifSettings []models.NotificationSetting
+	if h.notifSettingsProvider != nil {
+		if settings, err := h.notifSettingsProvider.ListSettings(r.Context(), claims.UserID); err == nil {
+			notifSettings = settings
+		} else {
+			log.Printf("[ws] notification settings fetch failed for user %s: %v", claims.UserID, err)
+		}
+	}
+	if notifSettings == nil {
+		notifSettings = []models.NotificationSetting{}
+	}
+
 	h.hub.register <- client
 
-	// Send ready event with online users, servers, mute state, and persisted pref_status
-	client.sendEvent(Event{
-		Op: OpReady,
-		Data: ReadyData{
-			SessionID:       client.sessionID,
-			OnlineUserIDs:   h.hub.GetVisibleOnlineUserIDs(),
-			Servers:         readyServers,
-			MutedServerIDs:  mutedServerIDs,
-			MutedChannelIDs: mutedChannelIDs,
-			PrefStatus:      prefStatus,
-		},
-	})
+	// Attempt the resume only after registration, so no event can fall between
+	// the replay snapshot and live delivery (frames the new connection already
+	// received live may repeat in the replay; clients dedupe by seq).
+	resumed := false
+	if resumeSessionID != "" && lastSeq > 0 {
+		if frames, ok := h.hub.ResumeEvents(resumeSessionID, claims.UserID, lastSeq); ok {
+			resumed = true
+			client.sendEvent(Event{
+				Op:   OpResume,
+				Data: ResumeData{SessionID: client.sessionID, Replayed: len(frames)},
+			})
+			for _, frame := range frames {
+				if !client.trySend(frame) {
+					break
+				}
+			}
+		}
+	}
+
+	if !resumed {
+		// Send ready event with online users, servers, mute state, and persisted pref_status.
+		// After a failed resume attempt this full payload IS the resync signal.
+		// The online list is scoped to the user's presence peers — everyone else's
+		// status is none of this client's business. Fails closed: a resolver error
+		// yields an empty list, and live presence events fill it back in.
+		onlineUserIDs := h.hub.GetVisibleOnlineUserIDs()
+		if h.presencePeerProvider != nil {
+			if peers, err := h.presencePeerProvider.Recipients(r.Context(), claims.UserID); err == nil {
+				allowed := make(map[string]struct{}, len(peers))
+				for _, id := range peers {
+					allowed[id] = struct{}{}
+				}
+				visible := make([]string, 0, len(onlineUserIDs))
+				for _, id := range onlineUserIDs {
+					if _, ok := allowed[id]; ok {
+						visible = append(visible, id)
+					}
+				}
+				onlineUserIDs = visible
+			} else {
+				log.Printf("[ws] presence peer resolution failed for user %s: %v", claims.UserID, err)
+				onlineUserIDs = []string{}
+			}
+		}
+		client.sendEvent(Event{
+			Op: OpReady,
+			Data: ReadyData{
+				SessionID:       client.sessionID,
+				OnlineUserIDs:   onlineUserIDs,
+				Servers:         readyServers,
+				MutedServerIDs:   mutedServerIDs,
+				MutedChannelIDs:  mutedChannelIDs,
+				MutedCategoryIDs: mutedCategoryIDs,
+				PrefStatus:       prefStatus,
+				CustomStatus:      customStatus,
+				CustomStatusEmoji: customStatusEmoji,
+				PendingFriendRequests: pendingFriendRequests,
+				UnreadDMs:             unreadDMs,
+				NotificationSettings:  notifSettings,
+			},
+		})
+	}
 
 	// Send voice states sync so frontend can initialize voiceStore.
 	// Filter to servers the user belongs to — voice events are server-scoped,
@@ -337,19 +515,21 @@ func (h *Handler) HandleConnection(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 			items = append(items, VoiceStateItem{
-				UserID:           s.UserID,
-				ChannelID:        s.ChannelID,
-				ChannelName:      s.ChannelName,
-				ServerID:         s.ServerID,
-				Username:         s.Username,
-				DisplayName:      s.DisplayName,
-				AvatarURL:        h.urlSigner.SignURL(s.AvatarURL),
-				IsMuted:          s.IsMuted,
-				IsDeafened:       s.IsDeafened,
-				IsStreaming:      s.IsStreaming,
-				ShareQuality:     s.ShareQuality,
-				IsServerMuted:    s.IsServerMuted,
-				IsServerDeafened: s.IsServerDeafened,
+				UserID:            s.UserID,
+				ChannelID:         s.ChannelID,
+				ChannelName:       s.ChannelName,
+				ServerID:          s.ServerID,
+				Username:          s.Username,
+				DisplayName:       s.DisplayName,
+				AvatarURL:         h.urlSigner.SignURL(s.AvatarURL),
+				IsMuted:           s.IsMuted,
+				IsDeafened:        s.IsDeafened,
+				IsStreaming:       s.IsStreaming,
+				ShareQuality:      s.ShareQuality,
+				IsServerMuted:     s.IsServerMuted,
+				IsServerDeafened:  s.IsServerDeafened,
+				IsPrioritySpeaker: s.IsPrioritySpeaker,
+				ClientPlatform:    s.ClientPlatform,
 			})
 			visibleChannels[s.ChannelID] = struct{}{}
 		}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/akinalp/mqvi/models"
 )
@@ -38,12 +39,14 @@ type UserStateProvider interface {
 	GetVisibleOnlineUserIDs() []string
 	GetOnlineUserIDsForServer(serverID string) []string
 	GetOnlineCountsForServers(serverIDs []string) map[string]int
+	GetStatusesForUsers(userIDs []string) map[string]string
 }
 
 // ClientManager manages WebSocket client connections.
 type ClientManager interface {
 	SetInvisible(userID string, invisible bool)
 	DisconnectUser(userID string)
+	DisconnectUserWithReason(userID, reason string)
 	AddClientServerID(userID, serverID string)
 	RemoveClientServerID(userID, serverID string)
 }
@@ -78,8 +81,9 @@ type SessionDisconnectCallback func(userID, sessionID string)
 // ─── Voice Callback Types ───
 
 // VoiceJoinCallback — user wants to join a voice channel.
-// displayName may be empty if the user hasn't set one.
-type VoiceJoinCallback func(userID, username, displayName, avatarURL, channelID string, isMuted, isDeafened bool)
+// displayName may be empty if the user hasn't set one. clientPlatform is the
+// joining connection's platform hint ("" for clients that don't send one).
+type VoiceJoinCallback func(userID, username, displayName, avatarURL, clientPlatform, channelID string, isMuted, isDeafened bool)
 
 // VoiceLeaveCallback — user wants to leave a voice channel.
 type VoiceLeaveCallback func(userID string)
@@ -96,6 +100,9 @@ type PresenceManualUpdateCallback func(userID string, status string, isAuto bool
 // Nil pointers mean "no change" (partial update).
 type VoiceAdminStateUpdateCallback func(adminUserID, targetUserID string, isServerMuted, isServerDeafened *bool)
 
+// VoicePrioritySpeakerCallback — user toggled their own priority-speaker flag.
+type VoicePrioritySpeakerCallback func(userID string, isPriority bool)
+
 // VoiceMoveUserCallback — authorized user moved someone between voice channels.
 type VoiceMoveUserCallback func(moverUserID, targetUserID, targetChannelID string)
 
@@ -116,6 +123,15 @@ type P2PCallDeclineCallback func(userID, deviceID string, data P2PCallDeclineDat
 type P2PCallEndCallback func(userID, deviceID, callID string)
 type P2PCallResumeCallback func(userID, sessionID, callID string)
 
+// ─── Group Call Callback Types ───
+
+// GroupCallInitiateCallback carries the initiating connection's sessionID so a
+// two-party invite can fall back to the P2P path, which is session-owned.
+type GroupCallInitiateCallback func(callerID, sessionID string, data GroupCallInitiateData)
+
+// GroupCallActionCallback handles accept/decline/leave — all take just the call.
+type GroupCallActionCallback func(userID, callID string)
+
 // P2PSignalCallback — WebRTC signaling data relayed to the other peer.
 type P2PSignalCallback func(senderID, senderSessionID string, data P2PSignalData)
 
@@ -157,6 +173,10 @@ type Hub struct {
 	// seq: monotonic counter for outbound event ordering
 	seq atomic.Int64
 
+	// closing flips once BeginShutdown runs; the HTTP handler refuses new
+	// connections after that. Existing clients stay until Shutdown.
+	closing atomic.Bool
+
 	// userInfos: cached user info for typing/voice broadcasts
 	userInfos map[string]cachedUserInfo
 	userMu    sync.RWMutex
@@ -165,6 +185,26 @@ type Hub struct {
 	// Protected by mu (same lock as clients).
 	invisibleUsers map[string]bool
 
+	// peakOnlineUsers: high-water mark of concurrently connected users since boot.
+	// Updated on connect, never reset. Protected by mu (same lock as clients).
+	peakOnlineUsers int
+
+	// resumeBuffers/resumeSessions back session resume (see resume.go).
+	// Protected by resumeMu; lock order is mu before resumeMu (broadcasts
+	// buffer while holding mu's read lock).
+	resumeBuffers  map[string]*userEventBuffer
+	resumeSessions map[string]string
+	resumeMu       sync.Mutex
+
+	// typers: channelID -> userID -> active typer, swept from Run's ticker
+	// (see typing.go). Protected by typingMu, never held across a broadcast.
+	typers   map[string]map[string]*activeTyper
+	typingMu sync.Mutex
+
+	// idleThreshold: heartbeat silence before a connection is swept to
+	// auto-idle (see idle.go). Zero disables the sweep. Set before Run.
+	idleThreshold time.Duration
+
 	// Presence callbacks — set in main.go.
 	// Called in separate goroutines to avoid deadlock (callback may call Broadcast
 	// which needs RLock, but add/removeClient holds Lock).
@@ -177,6 +217,7 @@ type Hub struct {
 	onVoiceLeave            VoiceLeaveCallback
 	onVoiceStateUpdate      VoiceStateUpdateCallback
 	onVoiceAdminStateUpdate VoiceAdminStateUpdateCallback
+	onVoicePrioritySpeaker  VoicePrioritySpeakerCallback
 	onVoiceMoveUser         VoiceMoveUserCallback
 	onVoiceDisconnectUser   VoiceDisconnectUserCallback
 	onVoiceActivity         VoiceActivityCallback
@@ -189,7 +230,12 @@ type Hub struct {
 	onP2PCallDecline  P2PCallDeclineCallback
 	onP2PCallEnd      P2PCallEndCallback
 	onP2PCallResume   P2PCallResumeCallback
-	onP2PSignal       P2PSignalCallback
+
+	onGroupCallInitiate GroupCallInitiateCallback
+	onGroupCallAccept   GroupCallActionCallback
+	onGroupCallDecline  GroupCallActionCallback
+	onGroupCallLeave    GroupCallActionCallback
+	onP2PSignal         P2PSignalCallback
 
 	// Channel typing callback — set in main.go
 	onChannelTyping ChannelTypingCallback
@@ -212,6 +258,9 @@ func NewHub() *Hub {
 		unregister:     make(chan *Client),
 		userInfos:      make(map[string]cachedUserInfo),
 		invisibleUsers: make(map[string]bool),
+		resumeBuffers:  make(map[string]*userEventBuffer),
+		resumeSessions: make(map[string]string),
+		typers:         make(map[string]map[string]*activeTyper),
 	}
 }
 
@@ -236,7 +285,14 @@ func (h *Hub) removeClientFromServerIndex(client *Client, serverID string) {
 }
 
 // Run is the Hub's main event loop. Started as `go hub.Run()` in main.go.
+// The tickers drive the idle-typer sweep (typing.go) and the presence
+// auto-idle sweep (idle.go).
 func (h *Hub) Run() {
+	ticker := time.NewTicker(typingSweepInterval)
+	defer ticker.Stop()
+	idleTicker := time.NewTicker(idleSweepInterval)
+	defer idleTicker.Stop()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -244,6 +300,12 @@ func (h *Hub) Run() {
 
 		case client := <-h.unregister:
 			h.removeClient(client)
+
+		case <-ticker.C:
+			h.expireTypers()
+
+		case <-idleTicker.C:
+			h.sweepAutoIdle()
 		}
 	}
 }
@@ -255,6 +317,8 @@ func (h *Hub) addClient(client *Client) {
 
 	isFirstConnection := len(h.clients[client.userID]) == 0
 
+	client.lastHeartbeat = time.Now() // registering counts as liveness for the auto-idle sweep
+
 	// Set per-connection status from prefStatus or default to "online"
 	if client.prefStatus != "" && client.prefStatus != "offline" {
 		client.status = client.prefStatus
@@ -269,11 +333,17 @@ func (h *Hub) addClient(client *Client) {
 	}
 	h.clients[client.userID][client] = true
 
+	if n := len(h.clients); n > h.peakOnlineUsers {
+		h.peakOnlineUsers = n
+	}
+
 	// Index this client by its serverIDs (set by handler.go before register).
 	for _, sid := range client.serverIDs {
 		h.addClientToServerIndex(client, sid)
 	}
 
+	h.resumeTrackConnect(client)
+
 	// New connection may change aggregate (e.g. existing idle + new online = online)
 	var aggregateForExisting string
 	if !isFirstConnection {
@@ -332,6 +402,8 @@ func (h *Hub) removeClient(client *Client) {
 				log.Printf("[ws] client disconnected: user=%s (remaining: %d, aggregate=%s)",
 					client.userID, len(clients), newAggregate)
 			}
+
+			h.resumeTrackDisconnect(client, fullyDisconnected)
 		}
 	}
 
@@ -381,6 +453,41 @@ func (h *Hub) computeAggregateStatusLocked(userID string) string {
 	return bestStatus
 }
 
+// platformPriority ranks client platforms for aggregation. Higher = more
+// capable; a user online from several devices is shown under the best one.
+// Unknown/absent hints rank 0 and never win over a known platform.
+var platformPriority = map[string]int{
+	"desktop": 3,
+	"web":     2,
+	"mobile":  1,
+}
+
+// normalizePlatform returns p if it is a known client platform, "" otherwise.
+// Keeps arbitrary client-supplied strings out of presence broadcasts.
+func normalizePlatform(p string) string {
+	if _, ok := platformPriority[p]; ok {
+		return p
+	}
+	return ""
+}
+
+// BestClientPlatform returns the highest-ranked platform across the user's
+// connections, or "" if the user is offline or no connection sent a hint.
+func (h *Hub) BestClientPlatform(userID string) string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	bestPriority := 0
+	best := ""
+	for client := range h.clients[userID] {
+		if p := platformPriority[client.platform]; p > bestPriority {
+			bestPriority = p
+			best = client.platform
+		}
+	}
+	return best
+}
+
 // BroadcastToAll sends an event to all connected clients.
 func (h *Hub) BroadcastToAll(event Event) {
 	event.Seq = h.seq.Add(1)
@@ -394,11 +501,13 @@ func (h *Hub) BroadcastToAll(event Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.bufferForAll(event.Seq, data, "")
+
 	for _, clients := range h.clients {
 		for client := range clients {
 			if !client.trySend(data) {
 				// Buffer full — slow client, disconnect
-				go func(c *Client) { h.unregister <- c }(client)
+				go h.dropSlowClient(client)
 			}
 		}
 	}
@@ -426,18 +535,75 @@ func (h *Hub) BroadcastToUsers(userIDs []string, event Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.bufferForUsers(event.Seq, data, userIDs...)
+
+	for userID, clients := range h.clients {
+		if !allowed[userID] {
+			continue
+		}
+		for client := range clients {
+			if !client.trySend(data) {
+				go h.dropSlowClient(client)
+			}
+		}
+	}
+}
+
+// BroadcastToUsersInChannel sends a view-scoped event to the given users, but
+// only to connections currently viewing channelID (per OpSubscribe). Used for
+// events that are pure UI for the open channel — typing indicators — where
+// delivering to a connection focused elsewhere is wasted bandwidth and client
+// work. Connections that never subscribed receive everything (older clients).
+// View-scoped events are deliberately excluded from the resume buffer —
+// replaying a stale typing indicator after a reconnect is worse than dropping it.
+func (h *Hub) BroadcastToUsersInChannel(userIDs []string, channelID string, event Event) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	event.Seq = h.seq.Add(1)
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[ws] failed to marshal broadcast event: %v", err)
+		return
+	}
+
+	allowed := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		allowed[id] = true
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	for userID, clients := range h.clients {
 		if !allowed[userID] {
 			continue
 		}
 		for client := range clients {
+			if client.hasSubscribed && client.subscribedChannelID != channelID {
+				continue
+			}
 			if !client.trySend(data) {
-				go func(c *Client) { h.unregister <- c }(client)
+				go h.dropSlowClient(client)
 			}
 		}
 	}
 }
 
+// SetClientSubscription records what a connection is actively viewing (OpSubscribe).
+// Per connection, not per user — the same user can have different channels open
+// in different tabs, and each tab only needs its own channel's view events.
+func (h *Hub) SetClientSubscription(client *Client, serverID, channelID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client.subscribedServerID = serverID
+	client.subscribedChannelID = channelID
+	client.hasSubscribed = true
+}
+
 // BroadcastToAllExcept sends an event to everyone except the specified user.
 func (h *Hub) BroadcastToAllExcept(excludeUserID string, event Event) {
 	event.Seq = h.seq.Add(1)
@@ -451,13 +617,15 @@ func (h *Hub) BroadcastToAllExcept(excludeUserID string, event Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.bufferForAll(event.Seq, data, excludeUserID)
+
 	for userID, clients := range h.clients {
 		if userID == excludeUserID {
 			continue
 		}
 		for client := range clients {
 			if !client.trySend(data) {
-				go func(c *Client) { h.unregister <- c }(client)
+				go h.dropSlowClient(client)
 			}
 		}
 	}
@@ -476,10 +644,12 @@ func (h *Hub) BroadcastToUser(userID string, event Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.bufferForUsers(event.Seq, data, userID)
+
 	if clients, ok := h.clients[userID]; ok {
 		for client := range clients {
 			if !client.trySend(data) {
-				go func(c *Client) { h.unregister <- c }(client)
+				go h.dropSlowClient(client)
 			}
 		}
 	}
@@ -511,6 +681,14 @@ func (h *Hub) Counts() (connections, users int) {
 	return connections, len(h.clients)
 }
 
+// PeakOnlineUsers returns the highest number of concurrently connected users seen
+// since boot. Counts distinct users, not sockets — multi-tab doesn't inflate it.
+func (h *Hub) PeakOnlineUsers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.peakOnlineUsers
+}
+
 func (h *Hub) GetOnlineUserIDs() []string {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -582,6 +760,25 @@ func (h *Hub) GetOnlineCountsForServers(serverIDs []string) map[string]int {
 	return out
 }
 
+// GetStatusesForUsers returns the current effective status for each requested
+// user under a single read lock: the aggregate across their connections, with
+// disconnected and invisible users both reported as "offline" — a batch query
+// must not distinguish the two. Every requested ID gets an entry.
+func (h *Hub) GetStatusesForUsers(userIDs []string) map[string]string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	out := make(map[string]string, len(userIDs))
+	for _, userID := range userIDs {
+		if len(h.clients[userID]) == 0 || h.invisibleUsers[userID] {
+			out[userID] = "offline"
+			continue
+		}
+		out[userID] = h.computeAggregateStatusLocked(userID)
+	}
+	return out
+}
+
 // SetInvisible marks a user as invisible (connected but hidden from online lists).
 func (h *Hub) SetInvisible(userID string, invisible bool) {
 	h.mu.Lock()
@@ -661,6 +858,10 @@ func (h *Hub) OnVoiceAdminStateUpdate(cb VoiceAdminStateUpdateCallback) {
 	h.onVoiceAdminStateUpdate = cb
 }
 
+func (h *Hub) OnVoicePrioritySpeaker(cb VoicePrioritySpeakerCallback) {
+	h.onVoicePrioritySpeaker = cb
+}
+
 func (h *Hub) OnVoiceMoveUser(cb VoiceMoveUserCallback) {
 	h.onVoiceMoveUser = cb
 }
@@ -693,6 +894,22 @@ func (h *Hub) OnP2PCallResume(cb P2PCallResumeCallback) {
 	h.onP2PCallResume = cb
 }
 
+func (h *Hub) OnGroupCallInitiate(cb GroupCallInitiateCallback) {
+	h.onGroupCallInitiate = cb
+}
+
+func (h *Hub) OnGroupCallAccept(cb GroupCallActionCallback) {
+	h.onGroupCallAccept = cb
+}
+
+func (h *Hub) OnGroupCallDecline(cb GroupCallActionCallback) {
+	h.onGroupCallDecline = cb
+}
+
+func (h *Hub) OnGroupCallLeave(cb GroupCallActionCallback) {
+	h.onGroupCallLeave = cb
+}
+
 func (h *Hub) OnP2PSignal(cb P2PSignalCallback) {
 	h.onP2PSignal = cb
 }
@@ -709,8 +926,25 @@ func (h *Hub) OnScreenShareWatch(cb ScreenShareWatchCallback) {
 	h.onScreenShareWatch = cb
 }
 
-// DisconnectUser forcefully closes all WS connections for a user (e.g. after ban).
+// dropSlowClient tells a slow consumer why it is being closed, then unregisters
+// it. Runs in its own goroutine (spawned from the broadcast loops) so a dead
+// socket's write deadline never blocks a broadcast holding h.mu.
+func (h *Hub) dropSlowClient(c *Client) {
+	c.sendDisconnect(DisconnectReasonSlowConsumer)
+	h.unregister <- c
+}
+
+// DisconnectUser forcefully closes all WS connections for a user without a
+// reason notice — for account-removal paths where the socket is about to fail
+// auth anyway.
 func (h *Hub) DisconnectUser(userID string) {
+	h.DisconnectUserWithReason(userID, "")
+}
+
+// DisconnectUserWithReason closes all of a user's connections after telling
+// each one why (OpDisconnect), so clients show the right message instead of
+// reconnecting into the same rejection. Empty reason skips the notice.
+func (h *Hub) DisconnectUserWithReason(userID, reason string) {
 	h.mu.RLock()
 	clients := make([]*Client, 0)
 	if userClients, ok := h.clients[userID]; ok {
@@ -721,22 +955,62 @@ func (h *Hub) DisconnectUser(userID string) {
 	h.mu.RUnlock()
 
 	for _, client := range clients {
+		if reason != "" {
+			client.sendDisconnect(reason)
+		}
 		h.unregister <- client
 	}
 }
 
-// Shutdown closes all client connections (graceful shutdown).
+// BeginShutdown starts the drain phase: new WS connections are refused from
+// this point on, and every connected client is told the instance is going
+// away. Existing sockets stay open so in-flight work can finish — Shutdown
+// force-closes whatever is still connected once the drain window expires.
+func (h *Hub) BeginShutdown(drain time.Duration) {
+	h.closing.Store(true)
+	h.BroadcastToAll(Event{
+		Op:   OpServerShutdown,
+		Data: ServerShutdownData{DrainSeconds: int(drain / time.Second)},
+	})
+
+	h.mu.RLock()
+	users := len(h.clients)
+	h.mu.RUnlock()
+	log.Printf("[ws] shutdown notice sent to %d users, refusing new connections", users)
+}
+
+// IsShuttingDown reports whether BeginShutdown has run.
+func (h *Hub) IsShuttingDown() bool {
+	return h.closing.Load()
+}
+
+// Shutdown closes all client connections (graceful shutdown). Each one gets an
+// OpDisconnect notice first — BeginShutdown's broadcast was minutes ago and a
+// client that connected mid-drain (or missed it) would otherwise reconnect
+// straight into the dying instance. Notices go out in parallel, outside the
+// lock, so one dead socket's write deadline can't stall the rest.
 func (h *Hub) Shutdown() {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
+	all := make([]*Client, 0)
 	for _, clients := range h.clients {
 		for client := range clients {
-			client.markClosed()
+			all = append(all, client)
 		}
 	}
 	h.clients = make(map[string]map[*Client]bool)
 	h.serverClients = make(map[string]map[*Client]bool)
+	h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, client := range all {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			c.sendDisconnect(DisconnectReasonServerShutdown)
+			c.markClosed()
+		}(client)
+	}
+	wg.Wait()
 	log.Println("[ws] hub shut down, all connections closed")
 }
 
@@ -758,9 +1032,11 @@ func (h *Hub) BroadcastToServer(serverID string, event Event) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.bufferForServer(event.Seq, data, serverID, "")
+
 	for client := range h.serverClients[serverID] {
 		if !client.trySend(data) {
-			go func(c *Client) { h.unregister <- c }(client)
+			go h.dropSlowClient(client)
 		}
 	}
 }
@@ -779,12 +1055,14 @@ func (h *Hub) BroadcastToServerExcept(serverID, excludeUserID string, event Even
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.bufferForServer(event.Seq, data, serverID, excludeUserID)
+
 	for client := range h.serverClients[serverID] {
 		if client.userID == excludeUserID {
 			continue
 		}
 		if !client.trySend(data) {
-			go func(c *Client) { h.unregister <- c }(client)
+			go h.dropSlowClient(client)
 		}
 	}
 }
@@ -802,6 +1080,7 @@ func (h *Hub) AddClientServerID(userID, serverID string) {
 			}
 		}
 	}
+	h.resumeSyncServerID(userID, serverID, true)
 }
 
 // RemoveClientServerID removes a server ID from all connections of a user (on leave/kick).
@@ -820,6 +1099,7 @@ func (h *Hub) RemoveClientServerID(userID, serverID string) {
 			}
 		}
 	}
+	h.resumeSyncServerID(userID, serverID, false)
 }
 
 // SetClientServerIDs sets all server IDs for a client (at WS connect, from DB).
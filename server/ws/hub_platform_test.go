@@ -0,0 +1,53 @@
+package ws
+
+import "testing"
+
+// Platform aggregation: a user online from several devices is shown under the
+// most capable one (desktop > web > mobile), and unknown hints never win.
+func TestBestClientPlatform_PicksHighestRanked(t *testing.T) {
+	h := &Hub{clients: make(map[string]map[*Client]bool)}
+
+	addConn := func(userID, platform string) {
+		c := &Client{hub: h, userID: userID, platform: platform}
+		if h.clients[userID] == nil {
+			h.clients[userID] = make(map[*Client]bool)
+		}
+		h.clients[userID][c] = true
+	}
+
+	if got := h.BestClientPlatform("u-offline"); got != "" {
+		t.Errorf("offline user: got %q, want empty", got)
+	}
+
+	addConn("u1", "mobile")
+	if got := h.BestClientPlatform("u1"); got != "mobile" {
+		t.Errorf("single mobile connection: got %q, want mobile", got)
+	}
+
+	addConn("u1", "web")
+	addConn("u1", "desktop")
+	if got := h.BestClientPlatform("u1"); got != "desktop" {
+		t.Errorf("mobile+web+desktop: got %q, want desktop", got)
+	}
+
+	// A connection without a hint (old client) must not mask a known platform.
+	addConn("u2", "")
+	addConn("u2", "mobile")
+	if got := h.BestClientPlatform("u2"); got != "mobile" {
+		t.Errorf("hintless+mobile: got %q, want mobile", got)
+	}
+}
+
+// normalizePlatform keeps arbitrary client-supplied strings out of broadcasts.
+func TestNormalizePlatform(t *testing.T) {
+	for _, p := range []string{"desktop", "web", "mobile"} {
+		if got := normalizePlatform(p); got != p {
+			t.Errorf("normalizePlatform(%q) = %q, want unchanged", p, got)
+		}
+	}
+	for _, p := range []string{"", "Desktop", "toaster", "mobile "} {
+		if got := normalizePlatform(p); got != "" {
+			t.Errorf("normalizePlatform(%q) = %q, want empty", p, got)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package ws
+
+import "testing"
+
+// View-scoped routing: BroadcastToUsersInChannel must skip connections subscribed
+// to a different channel, deliver to connections viewing the target channel, and
+// still deliver to connections that never sent OpSubscribe (older clients).
+func TestBroadcastToUsersInChannel_FiltersBySubscription(t *testing.T) {
+	h := &Hub{
+		clients:    make(map[string]map[*Client]bool),
+		unregister: make(chan *Client, 8),
+	}
+
+	newClient := func(userID string) *Client {
+		c := &Client{
+			hub:    h,
+			userID: userID,
+			send:   make(chan []byte, 4),
+			done:   make(chan struct{}),
+		}
+		h.clients[userID] = map[*Client]bool{c: true}
+		return c
+	}
+
+	viewing := newClient("u-viewing")
+	h.SetClientSubscription(viewing, "s1", "chan-a")
+
+	elsewhere := newClient("u-elsewhere")
+	h.SetClientSubscription(elsewhere, "s1", "chan-b")
+
+	legacy := newClient("u-legacy") // never subscribed
+
+	h.BroadcastToUsersInChannel([]string{"u-viewing", "u-elsewhere", "u-legacy"}, "chan-a", Event{
+		Op:   OpTypingStart,
+		Data: TypingStartData{UserID: "u-sender", ChannelID: "chan-a"},
+	})
+
+	if len(viewing.send) != 1 {
+		t.Errorf("client viewing chan-a should receive the event, got %d queued", len(viewing.send))
+	}
+	if len(elsewhere.send) != 0 {
+		t.Errorf("client viewing chan-b should be filtered out, got %d queued", len(elsewhere.send))
+	}
+	if len(legacy.send) != 1 {
+		t.Errorf("never-subscribed client should still receive the event, got %d queued", len(legacy.send))
+	}
+
+	// Switching view moves the filter with the client: after focusing chan-a,
+	// the previously-filtered connection receives the next broadcast.
+	h.SetClientSubscription(elsewhere, "s1", "chan-a")
+	h.BroadcastToUsersInChannel([]string{"u-elsewhere"}, "chan-a", Event{Op: OpTypingStart})
+	if len(elsewhere.send) != 1 {
+		t.Errorf("client should receive events for its new view after resubscribing, got %d queued", len(elsewhere.send))
+	}
+}
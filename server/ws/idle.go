@@ -0,0 +1,96 @@
+package ws
+
+import "time"
+
+// Server-side AFK detection: presence used to be entirely client-driven, so a
+// client that stopped sending anything (laptop lid closed, app backgrounded)
+// stayed "online" until the socket died. The hub now stamps every heartbeat
+// and a sweep from Run's ticker pushes connections that have gone quiet for
+// idleThreshold to an automatic "idle" — but only when the user's preferred
+// status is "online". Manual dnd/idle/invisible are never overridden, and the
+// autoIdled flag keeps the sweep's idle separate from a user-chosen one: the
+// next heartbeat lifts only the automatic kind.
+
+// idleSweepInterval is how often Run's ticker scans for quiet connections.
+// Coarse on purpose — the threshold is minutes, so second-level precision
+// would only buy lock contention.
+const idleSweepInterval = time.Minute
+
+// SetIdleThreshold sets how long a connection may go without a heartbeat
+// before the sweep marks it auto-idle. Zero disables the sweep entirely.
+// Called once before Run; not safe to change while the hub is running.
+func (h *Hub) SetIdleThreshold(d time.Duration) {
+	h.idleThreshold = d
+}
+
+// touchHeartbeat stamps connection liveness and, if the sweep had auto-idled
+// this connection, restores its preferred status. Called inline from the
+// heartbeat path, so the fast case (not auto-idled) is one stamp under the lock.
+func (h *Hub) touchHeartbeat(c *Client) {
+	h.mu.Lock()
+	c.lastHeartbeat = time.Now()
+	if !c.autoIdled {
+		h.mu.Unlock()
+		return
+	}
+	c.autoIdled = false
+	// Eligibility for auto-idle required a preferred status of "online", so
+	// that is what resuming restores.
+	c.status = "online"
+	aggregate := h.computeAggregateStatusLocked(c.userID)
+	cb := h.onPresenceManualUpdate
+	h.mu.Unlock()
+
+	if cb != nil {
+		go cb(c.userID, aggregate, true)
+	}
+}
+
+// sweepAutoIdle marks connections without a heartbeat for idleThreshold as
+// auto-idle and broadcasts the new aggregate per affected user. Runs from
+// Run's ticker.
+func (h *Hub) sweepAutoIdle() {
+	if h.idleThreshold <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-h.idleThreshold)
+
+	type idledUser struct {
+		userID    string
+		aggregate string
+	}
+	var idled []idledUser
+
+	h.mu.Lock()
+	for userID, clients := range h.clients {
+		changed := false
+		for c := range clients {
+			// Only "online" connections whose DB-preferred status is also
+			// online qualify — a manual dnd/idle/invisible stays untouched.
+			if c.autoIdled || c.status != "online" {
+				continue
+			}
+			if c.prefStatus != "" && c.prefStatus != "online" {
+				continue
+			}
+			if c.lastHeartbeat.After(cutoff) {
+				continue
+			}
+			c.autoIdled = true
+			c.status = "idle"
+			changed = true
+		}
+		if changed {
+			idled = append(idled, idledUser{userID, h.computeAggregateStatusLocked(userID)})
+		}
+	}
+	cb := h.onPresenceManualUpdate
+	h.mu.Unlock()
+
+	if cb == nil {
+		return
+	}
+	for _, u := range idled {
+		go cb(u.userID, u.aggregate, true)
+	}
+}
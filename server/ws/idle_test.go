@@ -0,0 +1,123 @@
+package ws
+
+import (
+	"testing"
+	"time"
+)
+
+func idleTestHub(threshold time.Duration) *Hub {
+	return &Hub{
+		clients:       make(map[string]map[*Client]bool),
+		idleThreshold: threshold,
+	}
+}
+
+func addIdleTestClient(h *Hub, userID, status, prefStatus string, lastHeartbeat time.Time) *Client {
+	c := &Client{
+		hub:           h,
+		userID:        userID,
+		status:        status,
+		prefStatus:    prefStatus,
+		lastHeartbeat: lastHeartbeat,
+	}
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*Client]bool)
+	}
+	h.clients[userID][c] = true
+	return c
+}
+
+func TestSweepAutoIdle_QuietOnlineConnectionGoesIdle(t *testing.T) {
+	h := idleTestHub(10 * time.Minute)
+	stale := time.Now().Add(-11 * time.Minute)
+	c := addIdleTestClient(h, "u1", "online", "online", stale)
+
+	updates := make(chan string, 1)
+	h.onPresenceManualUpdate = func(userID, status string, isAuto bool) {
+		if !isAuto {
+			t.Error("sweep must report its change as automatic")
+		}
+		updates <- userID + ":" + status
+	}
+
+	h.sweepAutoIdle()
+
+	if !c.autoIdled || c.status != "idle" {
+		t.Fatalf("connection not auto-idled: autoIdled=%v status=%q", c.autoIdled, c.status)
+	}
+	select {
+	case got := <-updates:
+		if got != "u1:idle" {
+			t.Errorf("broadcast %q, want u1:idle", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no presence broadcast from the sweep")
+	}
+
+	// Second sweep is a no-op — the connection is already flagged.
+	h.sweepAutoIdle()
+	select {
+	case <-updates:
+		t.Error("re-sweeping an idled connection must not re-broadcast")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Manual presence choices outrank the sweep: a user on dnd (or invisible, or
+// whose preferred status isn't "online") is not touched however quiet they are.
+func TestSweepAutoIdle_NeverOverridesManualStatus(t *testing.T) {
+	h := idleTestHub(10 * time.Minute)
+	stale := time.Now().Add(-time.Hour)
+	dnd := addIdleTestClient(h, "u-dnd", "dnd", "dnd", stale)
+	invisible := addIdleTestClient(h, "u-inv", "offline", "offline", stale)
+
+	h.onPresenceManualUpdate = func(string, string, bool) {
+		t.Error("sweep broadcast a change for a manually set status")
+	}
+
+	h.sweepAutoIdle()
+
+	if dnd.autoIdled || dnd.status != "dnd" {
+		t.Errorf("dnd connection changed: autoIdled=%v status=%q", dnd.autoIdled, dnd.status)
+	}
+	if invisible.autoIdled || invisible.status != "offline" {
+		t.Errorf("invisible connection changed: autoIdled=%v status=%q", invisible.autoIdled, invisible.status)
+	}
+}
+
+func TestTouchHeartbeat_RestoresOnlyAutoIdle(t *testing.T) {
+	h := idleTestHub(10 * time.Minute)
+	c := addIdleTestClient(h, "u1", "online", "online", time.Now().Add(-11*time.Minute))
+
+	updates := make(chan string, 2)
+	h.onPresenceManualUpdate = func(_, status string, _ bool) { updates <- status }
+
+	h.sweepAutoIdle()
+	<-updates
+
+	h.touchHeartbeat(c)
+	if c.autoIdled || c.status != "online" {
+		t.Fatalf("heartbeat did not lift auto-idle: autoIdled=%v status=%q", c.autoIdled, c.status)
+	}
+	select {
+	case got := <-updates:
+		if got != "online" {
+			t.Errorf("restore broadcast %q, want online", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no presence broadcast on heartbeat resume")
+	}
+
+	// A later heartbeat on a connection that was never auto-idled only stamps
+	// liveness — no broadcast.
+	before := c.lastHeartbeat
+	h.touchHeartbeat(c)
+	if !c.lastHeartbeat.After(before) {
+		t.Error("heartbeat did not refresh the liveness stamp")
+	}
+	select {
+	case <-updates:
+		t.Error("plain heartbeat must not broadcast presence")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
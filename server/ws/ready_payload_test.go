@@ -39,6 +39,8 @@ func TestReadyPayload_CarriesEveryFieldTheClientReads(t *testing.T) {
 		"muted_server_ids",
 		"muted_channel_ids",
 		"pref_status",
+		"pending_friend_requests",
+		"unread_dms",
 	} {
 		if _, present := envelope.D[key]; !present {
 			t.Errorf(
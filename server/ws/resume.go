@@ -0,0 +1,225 @@
+package ws
+
+import (
+	"time"
+)
+
+// Session resume: every broadcast a user should receive is also appended to a
+// per-user ring buffer so a reconnecting client can replay what it missed
+// instead of refetching everything. The client reconnects with
+// ?session_id=<old>&last_seq=<last seen Event.Seq>; if that seq is still in
+// the buffer the missed frames are replayed (OpResume), otherwise the normal
+// OpReady doubles as the "do a full resync" signal.
+//
+// Resume is impossible — and clients must full-refetch — once either bound is
+// crossed: more than resumeBufferSize events were broadcast to the user since
+// last_seq, or the user has been fully offline for longer than resumeWindow.
+const (
+	// resumeBufferSize bounds the per-user ring buffer. Oldest frames are
+	// evicted first; a last_seq older than the newest evicted frame cannot
+	// resume.
+	resumeBufferSize = 200
+
+	// resumeWindow is how long a fully-offline user's buffer (and their
+	// resumable session IDs) are kept before being dropped.
+	resumeWindow = 2 * time.Minute
+)
+
+// bufferedEvent is one marshalled frame as it went over the wire, keyed by the
+// hub-global Event.Seq it carried.
+type bufferedEvent struct {
+	seq  int64
+	data []byte
+}
+
+// userEventBuffer holds the replayable frames for one user plus the state
+// needed to keep feeding it while the user is offline.
+type userEventBuffer struct {
+	// events is oldest-first; append drops the head at capacity.
+	events []bufferedEvent
+
+	// evictedThrough is the seq of the newest frame dropped from the buffer.
+	// Seq is hub-global, so a resume is valid iff last_seq >= evictedThrough:
+	// every frame this user missed with a higher seq is still present (gaps
+	// belonging to other users' events are natural and fine).
+	evictedThrough int64
+
+	// serverIDs snapshots the user's server membership so server-scoped
+	// broadcasts keep landing in the buffer while the user is offline.
+	serverIDs map[string]bool
+
+	// sessions are the connection session IDs allowed to resume against this
+	// buffer.
+	sessions map[string]bool
+
+	// offlineSince is set when the user's last connection closes and zeroed on
+	// reconnect; buffers offline for longer than resumeWindow are pruned.
+	offlineSince time.Time
+}
+
+func (b *userEventBuffer) append(seq int64, data []byte) {
+	if len(b.events) >= resumeBufferSize {
+		drop := len(b.events) - resumeBufferSize + 1
+		b.evictedThrough = b.events[drop-1].seq
+		b.events = append(b.events[:0], b.events[drop:]...)
+	}
+	b.events = append(b.events, bufferedEvent{seq: seq, data: data})
+}
+
+// expired reports whether the buffer belongs to a user offline past the window.
+func (b *userEventBuffer) expired(now time.Time) bool {
+	return !b.offlineSince.IsZero() && now.Sub(b.offlineSince) > resumeWindow
+}
+
+// resumeTrackConnect registers a new connection's session for later resume and
+// (re)snapshots the user's server membership. MUST be called under h.mu Lock.
+func (h *Hub) resumeTrackConnect(client *Client) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	buf, ok := h.resumeBuffers[client.userID]
+	if !ok {
+		buf = &userEventBuffer{
+			serverIDs: make(map[string]bool),
+			sessions:  make(map[string]bool),
+		}
+		h.resumeBuffers[client.userID] = buf
+	}
+	buf.offlineSince = time.Time{}
+	for _, sid := range client.serverIDs {
+		buf.serverIDs[sid] = true
+	}
+	buf.sessions[client.sessionID] = true
+	h.resumeSessions[client.sessionID] = client.userID
+}
+
+// resumeTrackDisconnect stamps the buffer when the user's last connection
+// closes. The dying session stays registered so it can still resume within the
+// window. MUST be called under h.mu Lock.
+func (h *Hub) resumeTrackDisconnect(client *Client, fullyDisconnected bool) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	if buf, ok := h.resumeBuffers[client.userID]; ok && fullyDisconnected {
+		buf.offlineSince = time.Now()
+	}
+}
+
+// resumeSyncServerID keeps the offline-broadcast scope in step with server
+// join/leave. MUST be called under h.mu Lock.
+func (h *Hub) resumeSyncServerID(userID, serverID string, member bool) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	if buf, ok := h.resumeBuffers[userID]; ok {
+		if member {
+			buf.serverIDs[serverID] = true
+		} else {
+			delete(buf.serverIDs, serverID)
+		}
+	}
+}
+
+// dropBufferLocked removes a user's buffer and its sessions.
+// MUST be called under h.resumeMu.
+func (h *Hub) dropBufferLocked(userID string, buf *userEventBuffer) {
+	for sid := range buf.sessions {
+		delete(h.resumeSessions, sid)
+	}
+	delete(h.resumeBuffers, userID)
+}
+
+// bufferForAll appends a frame to every user's buffer (except excludeUserID,
+// if set), pruning expired buffers as it goes.
+func (h *Hub) bufferForAll(seq int64, data []byte, excludeUserID string) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	now := time.Now()
+	for userID, buf := range h.resumeBuffers {
+		if buf.expired(now) {
+			h.dropBufferLocked(userID, buf)
+			continue
+		}
+		if userID == excludeUserID {
+			continue
+		}
+		buf.append(seq, data)
+	}
+}
+
+// bufferForUsers appends a frame to the buffers of the given users, skipping
+// users who never connected (no buffer) or whose buffer has expired.
+func (h *Hub) bufferForUsers(seq int64, data []byte, userIDs ...string) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	now := time.Now()
+	for _, userID := range userIDs {
+		buf, ok := h.resumeBuffers[userID]
+		if !ok {
+			continue
+		}
+		if buf.expired(now) {
+			h.dropBufferLocked(userID, buf)
+			continue
+		}
+		buf.append(seq, data)
+	}
+}
+
+// bufferForServer appends a frame to the buffers of every user whose snapshot
+// includes serverID — connected or offline-within-window.
+func (h *Hub) bufferForServer(seq int64, data []byte, serverID, excludeUserID string) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	now := time.Now()
+	for userID, buf := range h.resumeBuffers {
+		if buf.expired(now) {
+			h.dropBufferLocked(userID, buf)
+			continue
+		}
+		if userID == excludeUserID || !buf.serverIDs[serverID] {
+			continue
+		}
+		buf.append(seq, data)
+	}
+}
+
+// ResumeEvents attempts a session resume: it returns the frames with seq >
+// lastSeq that sessionID's user missed, or ok=false when the gap is no longer
+// coverable (unknown/foreign session, buffer expired, or lastSeq already
+// evicted) and the caller must fall back to a full OpReady.
+//
+// The session is consumed either way — a session ID resumes at most once.
+// Frames taken after the new connection registered may duplicate events it
+// already received live; clients deduplicate by seq.
+func (h *Hub) ResumeEvents(sessionID, userID string, lastSeq int64) ([][]byte, bool) {
+	h.resumeMu.Lock()
+	defer h.resumeMu.Unlock()
+
+	owner, ok := h.resumeSessions[sessionID]
+	if !ok || owner != userID {
+		return nil, false
+	}
+	delete(h.resumeSessions, sessionID)
+
+	buf, ok := h.resumeBuffers[userID]
+	if !ok {
+		return nil, false
+	}
+	delete(buf.sessions, sessionID)
+
+	if buf.expired(time.Now()) || lastSeq < buf.evictedThrough {
+		return nil, false
+	}
+
+	var frames [][]byte
+	for _, ev := range buf.events {
+		if ev.seq > lastSeq {
+			frames = append(frames, ev.data)
+		}
+	}
+	return frames, true
+}
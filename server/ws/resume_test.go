@@ -0,0 +1,120 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func newResumeTestClient(userID, sessionID string, serverIDs ...string) *Client {
+	return &Client{
+		userID:    userID,
+		sessionID: sessionID,
+		send:      make(chan []byte, sendBufferSize),
+		done:      make(chan struct{}),
+		serverIDs: serverIDs,
+	}
+}
+
+func frameSeqs(t *testing.T, frames [][]byte) []int64 {
+	t.Helper()
+	seqs := make([]int64, 0, len(frames))
+	for _, frame := range frames {
+		var ev Event
+		if err := json.Unmarshal(frame, &ev); err != nil {
+			t.Fatalf("replayed frame is not a marshalled event: %v", err)
+		}
+		seqs = append(seqs, ev.Seq)
+	}
+	return seqs
+}
+
+// A connection that drops mid-stream and reconnects with its old session ID
+// gets exactly the frames it missed, in seq order, and the session is consumed.
+func TestResume_ReplaysMissedEvents(t *testing.T) {
+	h := NewHub()
+
+	old := newResumeTestClient("u1", "sess-old")
+	h.addClient(old)
+
+	h.BroadcastToUser("u1", Event{Op: OpMessageCreate})
+	lastSeen := h.seq.Load()
+
+	h.removeClient(old)
+
+	// Two events land while the user is offline but inside the window.
+	h.BroadcastToUser("u1", Event{Op: OpMessageCreate})
+	h.BroadcastToUser("u1", Event{Op: OpMessageDelete})
+
+	fresh := newResumeTestClient("u1", "sess-new")
+	h.addClient(fresh)
+
+	frames, ok := h.ResumeEvents("sess-old", "u1", lastSeen)
+	if !ok {
+		t.Fatal("resume within the window should succeed")
+	}
+	seqs := frameSeqs(t, frames)
+	if len(seqs) != 2 || seqs[0] != lastSeen+1 || seqs[1] != lastSeen+2 {
+		t.Fatalf("replayed seqs = %v, want the two missed events after %d", seqs, lastSeen)
+	}
+
+	// A session ID resumes at most once.
+	if _, ok := h.ResumeEvents("sess-old", "u1", lastSeen); ok {
+		t.Error("second resume against a consumed session should fail")
+	}
+}
+
+// Resume must refuse a foreign user's session and a last_seq that has already
+// been evicted from the bounded buffer.
+func TestResume_RefusesForeignSessionAndEvictedSeq(t *testing.T) {
+	h := NewHub()
+
+	old := newResumeTestClient("u1", "sess-old")
+	h.addClient(old)
+	h.BroadcastToUser("u1", Event{Op: OpMessageCreate})
+	lastSeen := h.seq.Load()
+	h.removeClient(old)
+
+	if _, ok := h.ResumeEvents("sess-old", "intruder", lastSeen); ok {
+		t.Fatal("resume with someone else's session ID should fail")
+	}
+
+	// Overflow the ring buffer so the frame after lastSeen is evicted.
+	for i := 0; i < resumeBufferSize+1; i++ {
+		h.BroadcastToUser("u1", Event{Op: OpMessageCreate})
+	}
+	if _, ok := h.ResumeEvents("sess-old", "u1", lastSeen); ok {
+		t.Fatal("resume should fail once last_seq predates the oldest buffered frame")
+	}
+}
+
+// Server-scoped events keep landing in an offline member's buffer — but only
+// for their servers — and the whole buffer dies past the resume window.
+func TestResume_ServerScopeAndWindowExpiry(t *testing.T) {
+	h := NewHub()
+
+	old := newResumeTestClient("u1", "sess-old", "s1")
+	h.addClient(old)
+	lastSeen := h.seq.Load()
+	h.removeClient(old)
+
+	h.BroadcastToServer("s1", Event{Op: OpChannelCreate})
+	h.BroadcastToServer("s2", Event{Op: OpChannelCreate}) // not u1's server
+
+	frames, ok := h.ResumeEvents("sess-old", "u1", lastSeen)
+	if !ok || len(frames) != 1 {
+		t.Fatalf("got %d frames ok=%v, want exactly the s1 event", len(frames), ok)
+	}
+
+	// Same shape again, but the user has been offline past the window.
+	stale := newResumeTestClient("u2", "sess-stale", "s1")
+	h.addClient(stale)
+	h.removeClient(stale)
+	h.resumeMu.Lock()
+	h.resumeBuffers["u2"].offlineSince = time.Now().Add(-resumeWindow - time.Minute)
+	h.resumeMu.Unlock()
+
+	if _, ok := h.ResumeEvents("sess-stale", "u2", 0); ok {
+		t.Fatal("resume after the window expired should fail")
+	}
+}
@@ -0,0 +1,30 @@
+package ws
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// A draining instance must refuse new WS connections before even looking at
+// the token — anything accepted now would be force-closed moments later.
+func TestHandleConnection_RefusedDuringShutdown(t *testing.T) {
+	hub := NewHub()
+	h := &Handler{hub: hub}
+
+	if hub.IsShuttingDown() {
+		t.Fatal("fresh hub should not report shutting down")
+	}
+
+	hub.BeginShutdown(10 * time.Second)
+
+	if !hub.IsShuttingDown() {
+		t.Fatal("hub should report shutting down after BeginShutdown")
+	}
+
+	rec := httptest.NewRecorder()
+	h.HandleConnection(rec, httptest.NewRequest("GET", "/ws?token=whatever", nil))
+	if rec.Code != 503 {
+		t.Errorf("status = %d, want 503 during drain", rec.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package ws
+
+import "time"
+
+// Typing stop tracking: OpTypingStart is client-driven, but nothing used to
+// say when typing ended — every client ran its own guess-timer. The hub now
+// remembers who is typing where and emits OpTypingStop itself: after
+// typingTimeout of silence (swept from Run's ticker) or immediately when the
+// typer's message lands (messageService.BroadcastCreate calls StopTyping).
+
+const (
+	// typingTimeout is how long after the last typing_start a typer is
+	// considered stopped if no message arrives.
+	typingTimeout = 8 * time.Second
+
+	// typingSweepInterval is how often Run's ticker scans for idle typers.
+	typingSweepInterval = 2 * time.Second
+)
+
+// activeTyper is one user typing in one channel. The recipient list is
+// captured at start time so the stop reaches exactly the audience that saw
+// the start, without re-resolving permissions from a background sweep.
+type activeTyper struct {
+	recipients []string
+	lastTyped  time.Time
+}
+
+// TrackTyping records (or refreshes) an active typer. Called by the channel
+// typing callback right before it broadcasts the matching OpTypingStart.
+func (h *Hub) TrackTyping(channelID, userID string, recipients []string) {
+	h.typingMu.Lock()
+	defer h.typingMu.Unlock()
+
+	if h.typers[channelID] == nil {
+		h.typers[channelID] = make(map[string]*activeTyper)
+	}
+	h.typers[channelID][userID] = &activeTyper{recipients: recipients, lastTyped: time.Now()}
+}
+
+// StopTyping emits an immediate OpTypingStop if the user is an active typer
+// in the channel; a no-op otherwise. Called when the typer's message is
+// broadcast — the message itself is the definitive "stopped typing" signal.
+func (h *Hub) StopTyping(channelID, userID string) {
+	h.typingMu.Lock()
+	typer, ok := h.typers[channelID][userID]
+	if ok {
+		h.removeTyperLocked(channelID, userID)
+	}
+	h.typingMu.Unlock()
+
+	if ok {
+		h.broadcastTypingStop(channelID, userID, typer)
+	}
+}
+
+// removeTyperLocked deletes a typer and drops the channel's inner map when it
+// empties, so the typers map never accumulates entries for quiet channels.
+// MUST be called under typingMu.
+func (h *Hub) removeTyperLocked(channelID, userID string) {
+	delete(h.typers[channelID], userID)
+	if len(h.typers[channelID]) == 0 {
+		delete(h.typers, channelID)
+	}
+}
+
+// expireTypers sweeps out typers idle beyond typingTimeout and emits their
+// stop events. Broadcasts run after typingMu is released — BroadcastToUsersInChannel
+// takes the hub's main lock.
+func (h *Hub) expireTypers() {
+	type expiredTyper struct {
+		channelID string
+		userID    string
+		typer     *activeTyper
+	}
+	cutoff := time.Now().Add(-typingTimeout)
+
+	h.typingMu.Lock()
+	var expired []expiredTyper
+	for channelID, users := range h.typers {
+		for userID, typer := range users {
+			if typer.lastTyped.Before(cutoff) {
+				expired = append(expired, expiredTyper{channelID, userID, typer})
+				h.removeTyperLocked(channelID, userID)
+			}
+		}
+	}
+	h.typingMu.Unlock()
+
+	for _, e := range expired {
+		h.broadcastTypingStop(e.channelID, e.userID, e.typer)
+	}
+}
+
+// broadcastTypingStop is view-scoped like the start event — a stop for a
+// channel nobody is looking at renders nothing.
+func (h *Hub) broadcastTypingStop(channelID, userID string, typer *activeTyper) {
+	h.BroadcastToUsersInChannel(typer.recipients, channelID, Event{
+		Op: OpTypingStop,
+		Data: TypingStopData{
+			UserID:    userID,
+			ChannelID: channelID,
+		},
+	})
+}
@@ -0,0 +1,89 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func typingTestHub(t *testing.T) (*Hub, *Client) {
+	t.Helper()
+	h := &Hub{
+		clients:    make(map[string]map[*Client]bool),
+		unregister: make(chan *Client, 8),
+		typers:     make(map[string]map[string]*activeTyper),
+	}
+	viewer := &Client{
+		hub:    h,
+		userID: "u-viewer",
+		send:   make(chan []byte, 4),
+		done:   make(chan struct{}),
+	}
+	h.clients["u-viewer"] = map[*Client]bool{viewer: true}
+	return h, viewer
+}
+
+func requireTypingStop(t *testing.T, viewer *Client, userID string) {
+	t.Helper()
+	if len(viewer.send) != 1 {
+		t.Fatalf("viewer has %d queued frames, want 1 typing_stop", len(viewer.send))
+	}
+	var ev Event
+	if err := json.Unmarshal(<-viewer.send, &ev); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if ev.Op != OpTypingStop {
+		t.Fatalf("op = %s, want %s", ev.Op, OpTypingStop)
+	}
+	data := ev.Data.(map[string]any)
+	if data["user_id"] != userID {
+		t.Errorf("stop for user %v, want %s", data["user_id"], userID)
+	}
+}
+
+// A message from the typer retracts the indicator immediately: one stop event
+// to the audience that saw the start, and the tracking entry is gone.
+func TestStopTyping_EmitsImmediateStop(t *testing.T) {
+	h, viewer := typingTestHub(t)
+
+	h.TrackTyping("chan-a", "u-typer", []string{"u-viewer"})
+	h.StopTyping("chan-a", "u-typer")
+	requireTypingStop(t, viewer, "u-typer")
+
+	if len(h.typers) != 0 {
+		t.Errorf("typers map not cleaned up: %+v", h.typers)
+	}
+
+	// Stopping again is a no-op, not a duplicate broadcast.
+	h.StopTyping("chan-a", "u-typer")
+	if len(viewer.send) != 0 {
+		t.Error("second stop must not broadcast again")
+	}
+}
+
+// The sweep only stops typers idle beyond the timeout, and dropping the last
+// typer of a channel removes the channel's map entry entirely.
+func TestExpireTypers_SweepsOnlyIdle(t *testing.T) {
+	h, viewer := typingTestHub(t)
+
+	h.TrackTyping("chan-a", "u-idle", []string{"u-viewer"})
+	h.TrackTyping("chan-b", "u-active", []string{"u-viewer"})
+	h.typers["chan-a"]["u-idle"].lastTyped = time.Now().Add(-typingTimeout - time.Second)
+
+	h.expireTypers()
+	requireTypingStop(t, viewer, "u-idle")
+
+	if _, ok := h.typers["chan-a"]; ok {
+		t.Error("emptied channel must be removed from the typers map")
+	}
+	if _, ok := h.typers["chan-b"]["u-active"]; !ok {
+		t.Error("active typer must survive the sweep")
+	}
+
+	// A fresh typing_start refreshes the timestamp, so the next sweep keeps it.
+	h.TrackTyping("chan-b", "u-active", []string{"u-viewer"})
+	h.expireTypers()
+	if len(viewer.send) != 0 {
+		t.Errorf("refreshed typer must not be stopped, got %d frames", len(viewer.send))
+	}
+}